@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/CedricHerzog/perfowl/internal/analyzer"
+	"github.com/CedricHerzog/perfowl/internal/parser"
+	"github.com/CedricHerzog/perfowl/internal/report"
+	"github.com/spf13/cobra"
+)
+
+var analyzeCmd = &cobra.Command{
+	Use:   "analyze",
+	Short: "Run a comprehensive analysis of the profile",
+	Long: `Runs the comprehensive analysis the MCP analyze_profile tool returns:
+- The profile summary (browser, platform, threads, page-load milestones)
+- Detected bottlenecks with the overall score
+- Extension performance impact
+
+Use -o junit or -o sarif to report the bottlenecks for CI.`,
+	RunE: runAnalyze,
+}
+
+func init() {
+	rootCmd.AddCommand(analyzeCmd)
+}
+
+func runAnalyze(cmd *cobra.Command, args []string) error {
+	if profilePath == "" {
+		return fmt.Errorf("profile path is required (use --profile or -p)")
+	}
+
+	profile, detectedType, err := loadProfile(profilePath)
+	if err != nil {
+		return err
+	}
+
+	analysis := report.Analyze(profile, detectedType)
+
+	switch outputFormat {
+	case "json":
+		return outputAnalyzeJSON(analysis)
+	case "junit", "sarif":
+		return outputFindings("analyze", analyzer.BottleneckFindings(analysis.Bottlenecks, profile))
+	case "markdown":
+		return outputAnalyzeMarkdown(analysis, profile)
+	default:
+		return outputAnalyzeText(analysis, profile)
+	}
+}
+
+func outputAnalyzeJSON(analysis report.Analysis) error {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(analysis)
+}
+
+// outputAnalyzeMarkdown writes the summary, bottleneck and extension reports in turn
+func outputAnalyzeMarkdown(analysis report.Analysis, profile *parser.Profile) error {
+	if err := outputMarkdown(analysis.Summary); err != nil {
+		return err
+	}
+	fmt.Println()
+	if err := outputBottlenecksMarkdown(analysis.Bottlenecks, profile); err != nil {
+		return err
+	}
+	fmt.Println()
+	return outputExtensionsMarkdown(analysis.Extensions)
+}
+
+// outputAnalyzeText writes the summary, bottleneck and extension reports in turn
+func outputAnalyzeText(analysis report.Analysis, profile *parser.Profile) error {
+	if err := outputText(analysis.Summary); err != nil {
+		return err
+	}
+	fmt.Println()
+	if err := outputBottlenecksText(analysis.Bottlenecks, profile); err != nil {
+		return err
+	}
+	fmt.Println()
+	return outputExtensionsText(analysis.Extensions)
+}
@@ -30,8 +30,20 @@ var batchCmd = &cobra.Command{
 	Long: `Analyzes multiple browser profiles across worker counts and generates
 visualization charts showing scaling behavior.
 
+Each label with at least two worker counts gets an Amdahl's law fit (serial
+fraction) and, with three or more, a Universal Scalability Law fit (contention σ
+and coherency κ). The summary reports the fitted parameters with R², predicted
+times beyond the measured counts and a recommended worker count; the time
+charts overlay the fitted curve.
+
 Profiles can be specified via a config file (YAML or JSON) or inline.
 
+List several profiles with the same label and worker count to record repeated
+runs: they are merged into one point with the mean, median, standard deviation,
+min/max and 95% confidence interval of each metric, after dropping outliers
+beyond 1.5 IQR. Text and markdown show the values as mean ± CI, and the charts
+draw error bars.
+
 Example usage with config file:
   perfowl batch --config profiles.yaml -o text
   perfowl batch --config profiles.yaml --chart wall_clock -o svg > chart.svg
@@ -74,6 +86,15 @@ func runBatch(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("no profiles specified. Use --config or --profiles")
 	}
 
+	// --threads applies to every entry without its own selector
+	if threadFilter != "" {
+		for i := range profiles {
+			if profiles[i].Threads == "" {
+				profiles[i].Threads = threadFilter
+			}
+		}
+	}
+
 	// Run batch analysis
 	result, err := analyzer.AnalyzeBatch(profiles)
 	if err != nil {
@@ -100,21 +121,11 @@ func parseProfiles() ([]analyzer.ProfileEntry, error) {
 
 	// Try config file first
 	if batchConfigFile != "" {
-		data, err := os.ReadFile(batchConfigFile)
+		config, err := readBatchConfig(batchConfigFile)
 		if err != nil {
-			return nil, fmt.Errorf("failed to read config file: %w", err)
-		}
-
-		var config BatchConfig
-
-		// Try YAML first, then JSON
-		if err := yaml.Unmarshal(data, &config); err != nil {
-			if err := json.Unmarshal(data, &config); err != nil {
-				return nil, fmt.Errorf("failed to parse config file as YAML or JSON: %w", err)
-			}
+			return nil, err
 		}
-
-		profiles = config.Profiles
+		profiles = config
 	}
 
 	// Parse inline profiles (can be used in addition to config)
@@ -129,6 +140,25 @@ func parseProfiles() ([]analyzer.ProfileEntry, error) {
 	return profiles, nil
 }
 
+// readBatchConfig reads the profile entries of a YAML or JSON batch config file
+func readBatchConfig(path string) ([]analyzer.ProfileEntry, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	var config BatchConfig
+
+	// Try YAML first, then JSON
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		if err := json.Unmarshal(data, &config); err != nil {
+			return nil, fmt.Errorf("failed to parse config file as YAML or JSON: %w", err)
+		}
+	}
+
+	return config.Profiles, nil
+}
+
 func parseInlineProfiles(input string) ([]analyzer.ProfileEntry, error) {
 	var profiles []analyzer.ProfileEntry
 
@@ -203,6 +233,33 @@ func outputBatchText(result *analyzer.BatchAnalysisResult) error {
 	}
 	fmt.Println()
 
+	if len(result.Summary.ScalingFits) > 0 {
+		fmt.Println("Scaling Model (Amdahl / USL fit):")
+		fmt.Println(strings.Repeat("-", 70))
+		fmt.Printf("%-12s %7s %8s %8s %9s %7s %7s %9s\n", "Label", "Model", "Serial", "USL σ", "USL κ", "R²", "Peak", "Recommend")
+		for _, label := range result.Summary.Labels {
+			fit, ok := result.Summary.ScalingFits[label]
+			if !ok {
+				continue
+			}
+			r2 := fit.AmdahlR2
+			if fit.Model == analyzer.ScalingModelUSL {
+				r2 = fit.USLR2
+			}
+			fmt.Printf("%-12s %7s %7.1f%% %8.4f %9.5f %7.3f %7s %9d\n",
+				label,
+				fit.Model,
+				fit.SerialFraction*100,
+				fit.Contention,
+				fit.Coherency,
+				r2,
+				formatPeakWorkers(fit),
+				fit.RecommendedWorkers,
+			)
+		}
+		fmt.Println()
+	}
+
 	// Print detailed data per label
 	for _, label := range result.Summary.Labels {
 		points := result.Series[label]
@@ -212,19 +269,23 @@ func outputBatchText(result *analyzer.BatchAnalysisResult) error {
 
 		fmt.Printf("%s Data Points:\n", label)
 		fmt.Println(strings.Repeat("-", 70))
-		fmt.Printf("%-8s %12s %12s %10s %10s %12s\n",
-			"Workers", "WallClock", "TotalWork", "Speedup", "Efficiency", "CryptoTime")
+		fmt.Printf("%-8s %-10s %18s %18s %14s %14s %18s\n",
+			"Workers", "Runs", "WallClock", "TotalWork", "Speedup", "Efficiency", "CryptoTime")
 
 		for _, p := range points {
-			fmt.Printf("%-8d %10.1fms %10.1fms %9.2fx %9.1f%% %10.1fms\n",
+			fmt.Printf("%-8d %-10s %18s %18s %14s %14s %18s\n",
 				p.WorkerCount,
-				p.WallClockMs,
-				p.TotalWorkMs,
-				p.Speedup,
-				p.Efficiency,
-				p.CryptoTimeMs,
+				formatRuns(p),
+				formatRunValue(p, analyzer.BatchMetricWallClock, "%.1fms", p.WallClockMs),
+				formatRunValue(p, analyzer.BatchMetricTotalWork, "%.1fms", p.TotalWorkMs),
+				formatRunValue(p, analyzer.BatchMetricSpeedup, "%.2fx", p.Speedup),
+				formatRunValue(p, analyzer.BatchMetricEfficiency, "%.1f%%", p.Efficiency),
+				formatRunValue(p, analyzer.BatchMetricCryptoTime, "%.1fms", p.CryptoTimeMs),
 			)
 		}
+		if hasRepeatedRuns(points) {
+			fmt.Println("  (means of repeated runs ± 95% confidence interval, outliers beyond 1.5 IQR excluded)")
+		}
 		fmt.Println()
 	}
 
@@ -253,6 +314,32 @@ func outputBatchMarkdown(result *analyzer.BatchAnalysisResult) error {
 	}
 	fmt.Println()
 
+	if len(result.Summary.ScalingFits) > 0 {
+		fmt.Println("## Scaling Model")
+		fmt.Println()
+		fmt.Println("| Label | Model | Metric | Serial Fraction | USL σ | USL κ | Amdahl R² | USL R² | Peak Workers | Recommended Workers |")
+		fmt.Println("|-------|-------|--------|-----------------|-------|-------|-----------|--------|--------------|---------------------|")
+		for _, label := range result.Summary.Labels {
+			fit, ok := result.Summary.ScalingFits[label]
+			if !ok {
+				continue
+			}
+			fmt.Printf("| %s | %s | %s | %.1f%% | %.4f | %.5f | %.3f | %.3f | %s | %d |\n",
+				label,
+				fit.Model,
+				fit.Metric,
+				fit.SerialFraction*100,
+				fit.Contention,
+				fit.Coherency,
+				fit.AmdahlR2,
+				fit.USLR2,
+				formatPeakWorkers(fit),
+				fit.RecommendedWorkers,
+			)
+		}
+		fmt.Println()
+	}
+
 	// Detailed tables
 	for _, label := range result.Summary.Labels {
 		points := result.Series[label]
@@ -261,21 +348,62 @@ func outputBatchMarkdown(result *analyzer.BatchAnalysisResult) error {
 		}
 
 		fmt.Printf("## %s Data\n\n", label)
-		fmt.Println("| Workers | Wall Clock | Total Work | Speedup | Efficiency | Crypto Time |")
-		fmt.Println("|---------|------------|------------|---------|------------|-------------|")
+		fmt.Println("| Workers | Runs | Wall Clock | Total Work | Speedup | Efficiency | Crypto Time |")
+		fmt.Println("|---------|------|------------|------------|---------|------------|-------------|")
 
 		for _, p := range points {
-			fmt.Printf("| %d | %.1f ms | %.1f ms | %.2fx | %.1f%% | %.1f ms |\n",
+			fmt.Printf("| %d | %s | %s | %s | %s | %s | %s |\n",
 				p.WorkerCount,
-				p.WallClockMs,
-				p.TotalWorkMs,
-				p.Speedup,
-				p.Efficiency,
-				p.CryptoTimeMs,
+				formatRuns(p),
+				formatRunValue(p, analyzer.BatchMetricWallClock, "%.1f ms", p.WallClockMs),
+				formatRunValue(p, analyzer.BatchMetricTotalWork, "%.1f ms", p.TotalWorkMs),
+				formatRunValue(p, analyzer.BatchMetricSpeedup, "%.2fx", p.Speedup),
+				formatRunValue(p, analyzer.BatchMetricEfficiency, "%.1f%%", p.Efficiency),
+				formatRunValue(p, analyzer.BatchMetricCryptoTime, "%.1f ms", p.CryptoTimeMs),
 			)
 		}
+		if hasRepeatedRuns(points) {
+			fmt.Println()
+			fmt.Println("Values are means of repeated runs ± the 95% confidence interval; outliers beyond 1.5 IQR are excluded.")
+		}
 		fmt.Println()
 	}
 
 	return nil
 }
+
+// formatPeakWorkers shows the USL throughput peak, or "-" when throughput keeps rising
+func formatPeakWorkers(fit analyzer.ScalingFit) string {
+	if fit.PeakWorkers <= 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%.1f", fit.PeakWorkers)
+}
+
+// formatRunValue formats a metric, followed by ± its 95% confidence margin when the
+// point aggregates repeated runs
+func formatRunValue(p analyzer.ProfileDataPoint, metric, format string, value float64) string {
+	out := fmt.Sprintf(format, value)
+	if stats, ok := p.Stats[metric]; ok && stats.N > 1 {
+		out += " ±" + fmt.Sprintf(format, stats.Margin())
+	}
+	return out
+}
+
+// formatRuns shows the number of runs behind a point and how many wall clock outliers were dropped
+func formatRuns(p analyzer.ProfileDataPoint) string {
+	if stats, ok := p.Stats[analyzer.BatchMetricWallClock]; ok && stats.Outliers > 0 {
+		return fmt.Sprintf("%d (-%d)", p.Runs, stats.Outliers)
+	}
+	return fmt.Sprintf("%d", p.Runs)
+}
+
+// hasRepeatedRuns reports whether any point aggregates several runs
+func hasRepeatedRuns(points []analyzer.ProfileDataPoint) bool {
+	for _, p := range points {
+		if p.Runs > 1 {
+			return true
+		}
+	}
+	return false
+}
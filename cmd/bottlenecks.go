@@ -4,11 +4,11 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
-	"sort"
 	"strings"
 
 	"github.com/CedricHerzog/perfowl/internal/analyzer"
 	"github.com/CedricHerzog/perfowl/internal/parser"
+	"github.com/CedricHerzog/perfowl/internal/report"
 	"github.com/spf13/cobra"
 )
 
@@ -28,7 +28,11 @@ Detects:
 - Layout thrashing (rapid reflow cycles)
 - Slow JavaScript execution
 - Network blocking
-- Extension overhead`,
+- Extension overhead
+
+Use -o junit or -o sarif to report each bottleneck as a failed test case or a
+code scanning alert (high = error, medium = warning, low = note), located at a
+script named in the bottleneck or at the heaviest function's file and line.`,
 	RunE: runBottlenecks,
 }
 
@@ -42,44 +46,18 @@ func runBottlenecks(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("profile path is required (use --profile or -p)")
 	}
 
-	bt := parser.ParseBrowserType(browserType)
-	profile, _, err := parser.LoadProfileWithType(profilePath, bt)
+	profile, _, err := loadProfile(profilePath)
 	if err != nil {
-		return fmt.Errorf("failed to load profile: %w", err)
+		return err
 	}
 
-	// Run bottleneck detection
-	bottlenecks := analyzer.DetectBottlenecks(profile)
-
-	// Filter by severity if specified
-	if minSeverity != "" {
-		minSev := analyzer.ParseSeverity(minSeverity)
-		var filtered []analyzer.Bottleneck
-		for _, b := range bottlenecks {
-			if b.Severity >= minSev {
-				filtered = append(filtered, b)
-			}
-		}
-		bottlenecks = filtered
-	}
-
-	// Sort by severity (highest first)
-	sort.Slice(bottlenecks, func(i, j int) bool {
-		return bottlenecks[i].Severity > bottlenecks[j].Severity
-	})
-
-	// Calculate overall score
-	score := analyzer.CalculateScore(bottlenecks)
-
-	output := analyzer.BottleneckReport{
-		Score:       score,
-		Bottlenecks: bottlenecks,
-		Summary:     analyzer.GenerateSummary(bottlenecks, profile),
-	}
+	output := report.Bottlenecks(profile, minSeverity)
 
 	switch outputFormat {
 	case "json":
 		return outputBottlenecksJSON(output)
+	case "junit", "sarif":
+		return outputFindings("bottlenecks", analyzer.BottleneckFindings(output, profile))
 	case "markdown":
 		return outputBottlenecksMarkdown(output, profile)
 	default:
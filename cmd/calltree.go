@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/CedricHerzog/perfowl/internal/analyzer"
+	"github.com/CedricHerzog/perfowl/internal/report"
+	"github.com/spf13/cobra"
+)
+
+var (
+	callTreeThread string
+	callTreeLimit  int
+)
+
+var callTreeCmd = &cobra.Command{
+	Use:   "calltree",
+	Short: "Show the hottest functions and call paths",
+	Long: `Analyzes the sampled call stacks, as the MCP get_call_tree tool does:
+- Top functions by self time, with their running time
+- Hot paths: the call paths the most self time was spent in
+
+Use --thread to analyze a single thread by name; by default every thread is
+included.
+
+Example:
+  perfowl calltree -p profile.json.gz --thread GeckoMain -l 10`,
+	RunE: runCallTree,
+}
+
+func init() {
+	rootCmd.AddCommand(callTreeCmd)
+	callTreeCmd.Flags().StringVar(&callTreeThread, "thread", "", "Only analyze the thread with this name")
+	callTreeCmd.Flags().IntVarP(&callTreeLimit, "limit", "l", report.DefaultLimit, "Maximum number of functions and hot paths to show")
+}
+
+func runCallTree(cmd *cobra.Command, args []string) error {
+	if profilePath == "" {
+		return fmt.Errorf("profile path is required (use --profile or -p)")
+	}
+
+	profile, _, err := loadProfile(profilePath)
+	if err != nil {
+		return err
+	}
+
+	analysis := report.CallTree(profile, callTreeThread, callTreeLimit)
+
+	switch outputFormat {
+	case "json":
+		return outputCallTreeJSON(analysis)
+	case "markdown":
+		return outputCallTreeMarkdown(analysis)
+	default:
+		fmt.Print(analyzer.FormatCallTree(analysis))
+		return nil
+	}
+}
+
+func outputCallTreeJSON(analysis analyzer.CallTreeAnalysis) error {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(analysis)
+}
+
+func outputCallTreeMarkdown(analysis analyzer.CallTreeAnalysis) error {
+	md := strings.Builder{}
+
+	md.WriteString("# Call Tree Analysis\n\n")
+
+	md.WriteString("## Summary\n\n")
+	if analysis.ThreadName != "" {
+		md.WriteString(fmt.Sprintf("- **Thread**: %s\n", analysis.ThreadName))
+	}
+	md.WriteString(fmt.Sprintf("- **Total Time**: %.2f ms\n", analysis.TotalTimeMs))
+	md.WriteString(fmt.Sprintf("- **Total Samples**: %d\n", analysis.TotalSamples))
+
+	if len(analysis.TopFunctions) > 0 {
+		md.WriteString("\n## Top Functions by Self Time\n\n")
+		md.WriteString("| Function | Self Time | Self % | Running Time | Total % |\n")
+		md.WriteString("|----------|-----------|--------|--------------|---------|\n")
+		for _, f := range analysis.TopFunctions {
+			md.WriteString(fmt.Sprintf("| `%s` | %.2fms | %.1f%% | %.2fms | %.1f%% |\n",
+				f.Name, f.SelfTimeMs, f.SelfPercent, f.RunningTimeMs, f.TotalPercent))
+		}
+	}
+
+	if len(analysis.HotPaths) > 0 {
+		md.WriteString("\n## Hot Paths\n\n")
+		for _, hp := range analysis.HotPaths {
+			md.WriteString(fmt.Sprintf("- %.1f%% (%.2fms): `%s`\n", hp.Percent, hp.SelfTimeMs, hp.Path))
+		}
+	}
+
+	if len(analysis.TopFunctions) == 0 {
+		md.WriteString("\nNo CPU samples recorded.\n")
+	}
+
+	fmt.Print(md.String())
+	return nil
+}
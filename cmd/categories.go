@@ -0,0 +1,133 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/CedricHerzog/perfowl/internal/analyzer"
+	"github.com/CedricHerzog/perfowl/internal/report"
+	"github.com/spf13/cobra"
+)
+
+var categoriesThread string
+
+var categoriesCmd = &cobra.Command{
+	Use:   "categories",
+	Short: "Break CPU time down by profiler category",
+	Long: `Breaks sampled CPU time down by profiler category (JavaScript, Layout, GC / CC,
+Graphics, ...), as the MCP get_category_breakdown tool does:
+- Time, share and sample count of each category
+- The same breakdown for each thread
+
+Use --thread to analyze a single thread by name; by default every thread is
+included.`,
+	RunE: runCategories,
+}
+
+func init() {
+	rootCmd.AddCommand(categoriesCmd)
+	categoriesCmd.Flags().StringVar(&categoriesThread, "thread", "", "Only analyze the thread with this name")
+}
+
+func runCategories(cmd *cobra.Command, args []string) error {
+	if profilePath == "" {
+		return fmt.Errorf("profile path is required (use --profile or -p)")
+	}
+
+	profile, _, err := loadProfile(profilePath)
+	if err != nil {
+		return err
+	}
+
+	breakdown := report.Categories(profile, categoriesThread)
+
+	switch outputFormat {
+	case "json":
+		return outputCategoriesJSON(breakdown)
+	case "markdown":
+		return outputCategoriesMarkdown(breakdown)
+	default:
+		return outputCategoriesText(breakdown)
+	}
+}
+
+func outputCategoriesJSON(breakdown analyzer.CategoryBreakdown) error {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(breakdown)
+}
+
+func writeCategoriesTable(md *strings.Builder, categories []analyzer.CategoryStats) {
+	md.WriteString("| Category | Time | Percent | Samples |\n")
+	md.WriteString("|----------|------|---------|---------|\n")
+	for _, c := range categories {
+		md.WriteString(fmt.Sprintf("| %s | %.2fms | %.1f%% | %d |\n", c.Name, c.TimeMs, c.Percent, c.SampleCount))
+	}
+}
+
+func outputCategoriesMarkdown(breakdown analyzer.CategoryBreakdown) error {
+	md := strings.Builder{}
+
+	md.WriteString("# Category Breakdown\n\n")
+
+	md.WriteString("## Summary\n\n")
+	md.WriteString(fmt.Sprintf("- **Total Time**: %.2f ms\n", breakdown.TotalTimeMs))
+	md.WriteString(fmt.Sprintf("- **Categories**: %d\n", len(breakdown.Categories)))
+
+	if len(breakdown.Categories) == 0 {
+		md.WriteString("\nNo CPU samples recorded.\n")
+		fmt.Print(md.String())
+		return nil
+	}
+
+	md.WriteString("\n## Categories\n\n")
+	writeCategoriesTable(&md, breakdown.Categories)
+
+	if len(breakdown.ByThread) > 1 {
+		md.WriteString("\n## By Thread\n")
+		for _, name := range sortedThreadNames(breakdown.ByThread) {
+			md.WriteString(fmt.Sprintf("\n### %s\n\n", name))
+			writeCategoriesTable(&md, breakdown.ByThread[name])
+		}
+	}
+
+	fmt.Print(md.String())
+	return nil
+}
+
+func printCategoryRows(categories []analyzer.CategoryStats) {
+	for _, c := range categories {
+		fmt.Printf("%-25s %10.2fms %7.1f%% %8d\n", truncateName(c.Name, 25), c.TimeMs, c.Percent, c.SampleCount)
+	}
+}
+
+func outputCategoriesText(breakdown analyzer.CategoryBreakdown) error {
+	fmt.Println("Category Breakdown")
+	fmt.Println(strings.Repeat("=", 60))
+	fmt.Println()
+
+	fmt.Printf("Total Time: %.2f ms\n", breakdown.TotalTimeMs)
+	fmt.Println()
+
+	if len(breakdown.Categories) == 0 {
+		fmt.Println("No CPU samples recorded.")
+		return nil
+	}
+
+	fmt.Printf("%-25s %12s %8s %8s\n", "Category", "Time", "Percent", "Samples")
+	fmt.Println(strings.Repeat("-", 60))
+	printCategoryRows(breakdown.Categories)
+
+	if len(breakdown.ByThread) > 1 {
+		for _, name := range sortedThreadNames(breakdown.ByThread) {
+			fmt.Println()
+			fmt.Printf("%s:\n", name)
+			fmt.Println(strings.Repeat("-", 60))
+			printCategoryRows(breakdown.ByThread[name])
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,303 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/CedricHerzog/perfowl/internal/analyzer"
+	"github.com/CedricHerzog/perfowl/internal/format/junit"
+	"github.com/CedricHerzog/perfowl/internal/format/sarif"
+	"github.com/CedricHerzog/perfowl/internal/parser"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	checkBudgetFile string
+	checkBaseline   string
+	checkTolerance  float64
+)
+
+var checkCmd = &cobra.Command{
+	Use:   "check",
+	Short: "Check a profile against a performance budget (CI gate)",
+	Long: `Evaluates every rule in a budget file against the profile, prints a pass/fail
+table and exits non-zero when any rule fails.
+
+Rules can limit:
+- score: the bottleneck score (CalculateScore), usually with min
+- duration_ms, long_tasks, gc_time_ms, gc_major_count, sync_ipc_count, layout_count
+- operation_time_ms: time between a start and end marker pattern
+- category_percent: share of CPU time in a category, optionally for one thread
+- function_self_time_ms: self time of a named function, optionally for one thread
+
+A rule naming a category, function or thread the profile doesn't have fails
+rather than passing unchecked.
+
+With --baseline, rules with a tolerance also fail when the metric got worse than
+the baseline profile by more than that many percent, or when it can't be measured
+in the baseline. Without --baseline, a rule with only a tolerance fails.
+
+Use -o junit or -o sarif for CI test reports and code scanning alerts.
+
+Example:
+  perfowl check -p profile.json.gz --budget budget.yaml
+  perfowl check -p profile.json.gz --budget budget.yaml --baseline main.json.gz -o junit > budget.xml
+
+Example budget file (budget.yaml):
+  tolerance: 10          # default % allowed against --baseline
+  rules:
+    - metric: score
+      min: 80
+    - metric: long_tasks
+      max: 3
+    - name: decrypt time
+      metric: operation_time_ms
+      start: "UserTiming:decrypt-start"
+      end: "UserTiming:decrypt-end"
+      max: 500
+      tolerance: 5
+    - metric: category_percent
+      category: GC / CC
+      max: 10
+    - metric: function_self_time_ms
+      function: computeHash
+      thread: GeckoMain
+      max: 200`,
+	RunE: runCheck,
+	// A failed budget is a result, not a usage error; Execute reports it once
+	SilenceUsage:  true,
+	SilenceErrors: true,
+}
+
+func init() {
+	rootCmd.AddCommand(checkCmd)
+	checkCmd.Flags().StringVar(&checkBudgetFile, "budget", "", "Path to the YAML/JSON budget file (required)")
+	checkCmd.Flags().StringVar(&checkBaseline, "baseline", "", "Baseline profile to compare against with the rules' tolerance")
+	checkCmd.Flags().Float64Var(&checkTolerance, "tolerance", 0, "Default tolerance in percent against the baseline (overrides the budget file)")
+}
+
+// loadBudget reads a YAML or JSON budget and the line each rule starts on
+func loadBudget(path string) (analyzer.Budget, []int, error) {
+	var budget analyzer.Budget
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return budget, nil, fmt.Errorf("failed to read budget file: %w", err)
+	}
+
+	// YAML is a superset of JSON, so one parser covers both
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return budget, nil, fmt.Errorf("failed to parse budget file as YAML or JSON: %w", err)
+	}
+	if err := doc.Decode(&budget); err != nil {
+		return budget, nil, fmt.Errorf("failed to parse budget file: %w", err)
+	}
+
+	lines := make([]int, len(budget.Rules))
+	if len(doc.Content) > 0 {
+		root := doc.Content[0]
+		for i := 0; i+1 < len(root.Content); i += 2 {
+			if root.Content[i].Value == "rules" {
+				for j, rule := range root.Content[i+1].Content {
+					if j < len(lines) {
+						lines[j] = rule.Line
+					}
+				}
+			}
+		}
+	}
+	return budget, lines, nil
+}
+
+func runCheck(cmd *cobra.Command, args []string) error {
+	if profilePath == "" {
+		return fmt.Errorf("profile path is required (use --profile or -p)")
+	}
+	if checkBudgetFile == "" {
+		return fmt.Errorf("budget file is required (use --budget)")
+	}
+
+	budget, lines, err := loadBudget(checkBudgetFile)
+	if err != nil {
+		return err
+	}
+	if checkTolerance > 0 {
+		budget.Tolerance = checkTolerance
+	}
+
+	profile, _, err := loadProfile(profilePath)
+	if err != nil {
+		return err
+	}
+	var baseline *parser.Profile
+	if checkBaseline != "" {
+		baseline, _, err = loadProfile(checkBaseline)
+		if err != nil {
+			return fmt.Errorf("baseline: %w", err)
+		}
+	}
+
+	report, err := analyzer.CheckBudget(profile, baseline, budget)
+	if err != nil {
+		return fmt.Errorf("invalid budget: %w", err)
+	}
+
+	switch outputFormat {
+	case "json":
+		err = outputCheckJSON(report)
+	case "markdown":
+		err = outputCheckMarkdown(report)
+	case "junit":
+		err = writeCheckJUnit(os.Stdout, report, lines)
+	case "sarif":
+		err = writeCheckSARIF(os.Stdout, report, lines)
+	default:
+		err = outputCheckText(report)
+	}
+	if err != nil {
+		return err
+	}
+
+	if !report.Passed {
+		return fmt.Errorf("%d of %d budget rules failed", report.Failed, report.Total)
+	}
+	return nil
+}
+
+// formatBudgetLimit shows a result's absolute limits and baseline tolerance
+func formatBudgetLimit(r analyzer.BudgetResult) string {
+	var limits []string
+	if r.Min != nil {
+		limits = append(limits, fmt.Sprintf(">= %.2f", *r.Min))
+	}
+	if r.Max != nil {
+		limits = append(limits, fmt.Sprintf("<= %.2f", *r.Max))
+	}
+	if r.Baseline != nil {
+		limits = append(limits, fmt.Sprintf("%.2f ± %.1f%%", *r.Baseline, *r.Tolerance))
+	}
+	if len(limits) == 0 {
+		return "-"
+	}
+	return strings.Join(limits, ", ")
+}
+
+func outputCheckJSON(report analyzer.BudgetReport) error {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(report)
+}
+
+func outputCheckMarkdown(report analyzer.BudgetReport) error {
+	md := strings.Builder{}
+
+	md.WriteString("# Performance Budget\n\n")
+
+	md.WriteString("## Summary\n\n")
+	status := "✅ Passed"
+	if !report.Passed {
+		status = "🔴 Failed"
+	}
+	md.WriteString(fmt.Sprintf("- **Status**: %s\n", status))
+	md.WriteString(fmt.Sprintf("- **Rules**: %d passed, %d failed\n", report.Total-report.Failed, report.Failed))
+
+	md.WriteString("\n## Rules\n\n")
+	md.WriteString("| Rule | Value | Limit | Result | Details |\n")
+	md.WriteString("|------|-------|-------|--------|---------|\n")
+	for _, r := range report.Results {
+		result := "✅ pass"
+		if !r.Passed {
+			result = "🔴 fail"
+		}
+		md.WriteString(fmt.Sprintf("| %s | %.2f | %s | %s | %s |\n", r.Rule, r.Value, formatBudgetLimit(r), result, r.Message))
+	}
+
+	fmt.Print(md.String())
+	return nil
+}
+
+func outputCheckText(report analyzer.BudgetReport) error {
+	fmt.Println("Performance Budget")
+	fmt.Println(strings.Repeat("=", 60))
+	fmt.Println()
+
+	fmt.Printf("  %-36s %12s  %-24s %s\n", "Rule", "Value", "Limit", "Result")
+	fmt.Println(strings.Repeat("-", 60))
+	for _, r := range report.Results {
+		result := "PASS"
+		if !r.Passed {
+			result = "FAIL"
+		}
+		fmt.Printf("  %-36s %12.2f  %-24s %s\n", truncateName(r.Rule, 36), r.Value, formatBudgetLimit(r), result)
+		if !r.Passed {
+			fmt.Printf("    %s\n", r.Message)
+		}
+	}
+	fmt.Println()
+
+	if report.Passed {
+		fmt.Printf("All %d rules passed\n", report.Total)
+	} else {
+		fmt.Printf("%d of %d rules failed\n", report.Failed, report.Total)
+	}
+	return nil
+}
+
+// budgetRuleID is the rule identifier used in CI reports
+func budgetRuleID(metric string) string {
+	return "budget/" + metric
+}
+
+func writeCheckJUnit(w io.Writer, report analyzer.BudgetReport, lines []int) error {
+	cases := make([]junit.TestCase, 0, len(report.Results))
+	for i, r := range report.Results {
+		tc := junit.TestCase{
+			Name:      r.Rule,
+			ClassName: "perfowl.budget." + r.Metric,
+			File:      checkBudgetFile,
+			SystemOut: r.Message,
+		}
+		if i < len(lines) {
+			tc.Line = lines[i]
+		}
+		if !r.Passed {
+			tc.Failure = &junit.Failure{Message: r.Message, Type: budgetRuleID(r.Metric), Text: fmt.Sprintf("value %.2f, limit %s", r.Value, formatBudgetLimit(r))}
+		}
+		cases = append(cases, tc)
+	}
+	return junit.Write(w, "perfowl", junit.NewSuite("perfowl budget", cases))
+}
+
+func writeCheckSARIF(w io.Writer, report analyzer.BudgetReport, lines []int) error {
+	run := sarif.Run{Tool: sarif.Tool{Driver: sarifDriver()}}
+
+	seen := make(map[string]bool)
+	for i, r := range report.Results {
+		if r.Passed {
+			continue
+		}
+		id := budgetRuleID(r.Metric)
+		if !seen[id] {
+			seen[id] = true
+			run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, sarif.Rule{
+				ID:               id,
+				ShortDescription: &sarif.Message{Text: "Performance budget for " + r.Metric},
+			})
+		}
+		line := 0
+		if i < len(lines) {
+			line = lines[i]
+		}
+		run.Results = append(run.Results, sarif.Result{
+			RuleID:    id,
+			Level:     sarif.LevelError,
+			Message:   sarif.Message{Text: fmt.Sprintf("%s: %s", r.Rule, r.Message)},
+			Locations: []sarif.Location{sarif.NewLocation(checkBudgetFile, line)},
+		})
+	}
+	return sarif.Write(w, run)
+}
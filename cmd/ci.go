@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/CedricHerzog/perfowl/internal/analyzer"
+	"github.com/CedricHerzog/perfowl/internal/format/junit"
+	"github.com/CedricHerzog/perfowl/internal/format/sarif"
+	"github.com/CedricHerzog/perfowl/internal/version"
+)
+
+// outputFindings writes findings as JUnit XML or SARIF, depending on -o
+func outputFindings(suite string, findings []analyzer.Finding) error {
+	if outputFormat == "sarif" {
+		return writeFindingsSARIF(os.Stdout, findings, sourceRoot)
+	}
+	return writeFindingsJUnit(os.Stdout, suite, findings)
+}
+
+// sarifDriver describes perfowl as the tool behind a SARIF run
+func sarifDriver() sarif.Driver {
+	return sarif.Driver{
+		Name:           "perfowl",
+		Version:        version.Version,
+		InformationURI: "https://github.com/CedricHerzog/perfowl",
+	}
+}
+
+// sarifLevel maps a severity to a SARIF result level
+func sarifLevel(s analyzer.Severity) string {
+	switch s {
+	case analyzer.SeverityHigh:
+		return sarif.LevelError
+	case analyzer.SeverityMedium:
+		return sarif.LevelWarning
+	default:
+		return sarif.LevelNote
+	}
+}
+
+// writeFindingsJUnit makes each finding a failed test case. A run without
+// findings gets one passing case so the suite is never empty.
+func writeFindingsJUnit(w io.Writer, suite string, findings []analyzer.Finding) error {
+	cases := make([]junit.TestCase, 0, len(findings))
+	for _, f := range findings {
+		cases = append(cases, junit.TestCase{
+			Name:      f.Title,
+			ClassName: "perfowl." + strings.ReplaceAll(f.Rule, "/", "."),
+			File:      f.File,
+			Line:      f.Line,
+			Failure: &junit.Failure{
+				Message: fmt.Sprintf("[%s] %s", f.Severity, f.Message),
+				Type:    f.Severity.String(),
+				Text:    f.Message,
+			},
+		})
+	}
+	if len(cases) == 0 {
+		cases = append(cases, junit.TestCase{Name: "no issues found", ClassName: "perfowl." + suite})
+	}
+	return junit.Write(w, "perfowl", junit.NewSuite("perfowl "+suite, cases))
+}
+
+// writeFindingsSARIF makes each finding a result at its severity's level. Script
+// URLs are mapped to files under root; a result whose file can't be mapped to a
+// repository path gets no location, since code scanning can't show it anyway.
+func writeFindingsSARIF(w io.Writer, findings []analyzer.Finding, root string) error {
+	run := sarif.Run{Tool: sarif.Tool{Driver: sarifDriver()}}
+	seen := make(map[string]bool)
+	for _, f := range findings {
+		if !seen[f.Rule] {
+			seen[f.Rule] = true
+			run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, sarif.Rule{
+				ID:               f.Rule,
+				ShortDescription: &sarif.Message{Text: f.Title},
+			})
+		}
+		result := sarif.Result{
+			RuleID:  f.Rule,
+			Level:   sarifLevel(f.Severity),
+			Message: sarif.Message{Text: f.Message},
+		}
+		if path, ok := analyzer.RepositoryPath(root, f.File); ok {
+			result.Locations = []sarif.Location{sarif.NewLocation(path, f.Line)}
+		}
+		run.Results = append(run.Results, result)
+	}
+	return sarif.Write(w, run)
+}
@@ -2,11 +2,15 @@ package cmd
 
 import (
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
+	"github.com/CedricHerzog/perfowl/internal/analyzer"
 	"github.com/CedricHerzog/perfowl/internal/parser"
+	"github.com/CedricHerzog/perfowl/internal/report"
 	"github.com/CedricHerzog/perfowl/internal/testutil"
+	"github.com/spf13/cobra"
 )
 
 func TestBuildSummary_BasicFields(t *testing.T) {
@@ -25,10 +29,10 @@ func TestBuildSummary_BasicFields(t *testing.T) {
 		WithThread(testutil.NewThreadBuilder("DOM Worker").Build()).
 		Build()
 
-	summary := buildSummary(profile, parser.BrowserFirefox)
+	summary := report.BuildSummary(profile, parser.BrowserFirefox)
 
-	if summary.Duration != 5 {
-		t.Errorf("Duration = %v, want 5", summary.Duration)
+	if summary.DurationSeconds != 5 {
+		t.Errorf("Duration = %v, want 5", summary.DurationSeconds)
 	}
 	if summary.BrowserType != "firefox" {
 		t.Errorf("BrowserType = %v, want firefox", summary.BrowserType)
@@ -50,7 +54,7 @@ func TestBuildSummary_BasicFields(t *testing.T) {
 func TestBuildSummary_WithExtensions(t *testing.T) {
 	profile := testutil.ProfileWithExtensions()
 
-	summary := buildSummary(profile, parser.BrowserFirefox)
+	summary := report.BuildSummary(profile, parser.BrowserFirefox)
 
 	if summary.ExtensionCount != 2 {
 		t.Errorf("ExtensionCount = %v, want 2", summary.ExtensionCount)
@@ -63,7 +67,7 @@ func TestBuildSummary_WithExtensions(t *testing.T) {
 func TestBuildSummary_WithCategories(t *testing.T) {
 	profile := testutil.ProfileWithCategories()
 
-	summary := buildSummary(profile, parser.BrowserFirefox)
+	summary := report.BuildSummary(profile, parser.BrowserFirefox)
 
 	if len(summary.Categories) == 0 {
 		t.Error("expected categories")
@@ -85,7 +89,7 @@ func TestBuildSummary_Markers(t *testing.T) {
 			Build()).
 		Build()
 
-	summary := buildSummary(profile, parser.BrowserFirefox)
+	summary := report.BuildSummary(profile, parser.BrowserFirefox)
 
 	if summary.TotalMarkers != 2 {
 		t.Errorf("TotalMarkers = %v, want 2", summary.TotalMarkers)
@@ -106,7 +110,7 @@ func TestBuildSummary_Samples(t *testing.T) {
 			Build()).
 		Build()
 
-	summary := buildSummary(profile, parser.BrowserFirefox)
+	summary := report.BuildSummary(profile, parser.BrowserFirefox)
 
 	if summary.TotalSamples != 100 {
 		t.Errorf("TotalSamples = %v, want 100", summary.TotalSamples)
@@ -119,17 +123,34 @@ func TestBuildSummary_Chrome(t *testing.T) {
 		WithThread(testutil.NewThreadBuilder("CrBrowserMain").AsMainThread().Build()).
 		Build()
 
-	summary := buildSummary(profile, parser.BrowserChrome)
+	summary := report.BuildSummary(profile, parser.BrowserChrome)
 
 	if summary.BrowserType != "chrome" {
 		t.Errorf("BrowserType = %v, want chrome", summary.BrowserType)
 	}
 }
 
+func TestBuildSummary_PageLoad(t *testing.T) {
+	summary := report.BuildSummary(testutil.ProfileWithPageLoad(), parser.BrowserChrome)
+
+	if len(summary.PageLoad) != 1 {
+		t.Fatalf("expected 1 navigation in summary, got %d", len(summary.PageLoad))
+	}
+	if len(summary.PageLoad[0].Milestones) != 6 {
+		t.Errorf("expected 6 milestones, got %d", len(summary.PageLoad[0].Milestones))
+	}
+
+	for _, output := range []func(report.Summary) error{outputText, outputMarkdown, outputJSON} {
+		if err := output(summary); err != nil {
+			t.Errorf("summary output error: %v", err)
+		}
+	}
+}
+
 func TestProfileSummary_Struct(t *testing.T) {
-	summary := ProfileSummary{
+	summary := report.Summary{
 		BrowserType:     "firefox",
-		Duration:        10.5,
+		DurationSeconds: 10.5,
 		Platform:        "Linux",
 		OSCPU:           "x86_64",
 		Product:         "Firefox",
@@ -151,7 +172,7 @@ func TestProfileSummary_Struct(t *testing.T) {
 	if summary.BrowserType != "firefox" {
 		t.Error("BrowserType field mismatch")
 	}
-	if summary.Duration != 10.5 {
+	if summary.DurationSeconds != 10.5 {
 		t.Error("Duration field mismatch")
 	}
 	if summary.Platform != "Linux" {
@@ -288,9 +309,9 @@ func TestRunSummary_InvalidProfile(t *testing.T) {
 }
 
 func TestOutputFunctions_NoPanic(t *testing.T) {
-	summary := ProfileSummary{
+	summary := report.Summary{
 		BrowserType:     "firefox",
-		Duration:        5.0,
+		DurationSeconds: 5.0,
 		Platform:        "Linux",
 		OSCPU:           "x86_64",
 		Product:         "Firefox",
@@ -332,11 +353,11 @@ func TestOutputFunctions_NoPanic(t *testing.T) {
 }
 
 func TestOutputFunctions_EmptyBrowserType(t *testing.T) {
-	summary := ProfileSummary{
-		BrowserType: "",
-		Duration:    5.0,
-		Features:    []string{},
-		Categories:  []string{},
+	summary := report.Summary{
+		BrowserType:     "",
+		DurationSeconds: 5.0,
+		Features:        []string{},
+		Categories:      []string{},
 	}
 
 	// Should not panic even with empty browser type
@@ -352,13 +373,13 @@ func TestOutputFunctions_EmptyBrowserType(t *testing.T) {
 }
 
 func TestOutputFunctions_NoExtensions(t *testing.T) {
-	summary := ProfileSummary{
-		BrowserType:    "chrome",
-		Duration:       10.0,
-		ExtensionCount: 0,
-		Extensions:     map[string]string{},
-		Features:       []string{"js"},
-		Categories:     []string{"JavaScript"},
+	summary := report.Summary{
+		BrowserType:     "chrome",
+		DurationSeconds: 10.0,
+		ExtensionCount:  0,
+		Extensions:      map[string]string{},
+		Features:        []string{"js"},
+		Categories:      []string{"JavaScript"},
 	}
 
 	// Should not panic with no extensions
@@ -376,7 +397,7 @@ func TestOutputFunctions_NoExtensions(t *testing.T) {
 func TestBuildSummary_EmptyProfile(t *testing.T) {
 	profile := testutil.MinimalProfile()
 
-	summary := buildSummary(profile, parser.BrowserUnknown)
+	summary := report.BuildSummary(profile, parser.BrowserUnknown)
 
 	if summary.ThreadCount != 0 {
 		t.Errorf("ThreadCount = %v, want 0", summary.ThreadCount)
@@ -934,253 +955,1395 @@ func TestRunScaling_Success(t *testing.T) {
 	}
 }
 
-func TestRunExtensions_Success(t *testing.T) {
-	// Use ProfileWithExtensionActivity which has actual extension samples
-	profile := testutil.ProfileWithExtensionActivity()
-	path := testutil.TempProfileFile(t, profile)
+func TestJITCmd_Definition(t *testing.T) {
+	if jitCmd.Use != "jit" {
+		t.Errorf("jitCmd.Use = %s, want 'jit'", jitCmd.Use)
+	}
+	if jitCmd.Flags().Lookup("compare") == nil {
+		t.Error("expected 'compare' flag to be defined")
+	}
+}
+
+func TestRunJIT_MissingProfile(t *testing.T) {
+	originalPath := profilePath
+	defer func() { profilePath = originalPath }()
+
+	profilePath = ""
+
+	err := runJIT(jitCmd, []string{})
+	if err == nil {
+		t.Error("expected error for missing profile path")
+	}
+}
+
+func TestRunJIT_Success(t *testing.T) {
+	path := testutil.TempProfileFile(t, testutil.ProfileWithJITTiers())
+	comparePath := testutil.TempProfileFile(t, testutil.ProfileWithJITTiers())
 
 	originalPath := profilePath
 	originalBrowser := browserType
 	originalFormat := outputFormat
+	originalCompare := jitCompareProfile
 	defer func() {
 		profilePath = originalPath
 		browserType = originalBrowser
 		outputFormat = originalFormat
+		jitCompareProfile = originalCompare
 	}()
 
 	profilePath = path
 	browserType = "auto"
 
-	// Test text output
-	outputFormat = "text"
-	err := runExtensions(extensionsCmd, []string{})
-	if err != nil {
-		t.Errorf("runExtensions text format error: %v", err)
+	for _, compare := range []string{"", comparePath} {
+		jitCompareProfile = compare
+		for _, format := range []string{"text", "markdown", "json"} {
+			outputFormat = format
+			if err := runJIT(jitCmd, []string{}); err != nil {
+				t.Errorf("runJIT %s format (compare=%q) error: %v", format, compare, err)
+			}
+		}
 	}
+}
 
-	// Test markdown output
-	outputFormat = "markdown"
-	err = runExtensions(extensionsCmd, []string{})
-	if err != nil {
-		t.Errorf("runExtensions markdown format error: %v", err)
+func TestLinesCmd_Definition(t *testing.T) {
+	if linesCmd.Use != "lines" {
+		t.Errorf("linesCmd.Use = %s, want 'lines'", linesCmd.Use)
 	}
-
-	// Test JSON output
-	outputFormat = "json"
-	err = runExtensions(extensionsCmd, []string{})
-	if err != nil {
-		t.Errorf("runExtensions json format error: %v", err)
+	for _, flag := range []string{"function", "file", "source-root", "context", "limit"} {
+		if linesCmd.Flags().Lookup(flag) == nil {
+			t.Errorf("expected '%s' flag to be defined", flag)
+		}
 	}
 }
 
-// Batch command tests
-func TestRunBatch_NoProfiles(t *testing.T) {
-	originalConfig := batchConfigFile
-	originalProfiles := batchProfiles
+func TestRunLines_MissingFilter(t *testing.T) {
+	path := testutil.TempProfileFile(t, testutil.ProfileWithSourceLines())
+
+	originalPath := profilePath
+	originalFunction := linesFunction
+	originalFile := linesFile
 	defer func() {
-		batchConfigFile = originalConfig
-		batchProfiles = originalProfiles
+		profilePath = originalPath
+		linesFunction = originalFunction
+		linesFile = originalFile
 	}()
 
-	batchConfigFile = ""
-	batchProfiles = ""
+	profilePath = path
+	linesFunction = ""
+	linesFile = ""
 
-	err := runBatch(batchCmd, []string{})
-	if err == nil {
-		t.Error("expected error for no profiles")
-	}
-	if !strings.Contains(err.Error(), "no profiles specified") {
-		t.Errorf("unexpected error: %v", err)
+	if err := runLines(linesCmd, []string{}); err == nil {
+		t.Error("expected error without function or file filter")
 	}
 }
 
-func TestRunBatch_InlineProfiles(t *testing.T) {
-	profile := testutil.ProfileWithWorkers(2)
-	path := testutil.TempProfileFile(t, profile)
+func TestRunLines_Success(t *testing.T) {
+	path := testutil.TempProfileFile(t, testutil.ProfileWithSourceLines())
 
-	originalConfig := batchConfigFile
-	originalProfiles := batchProfiles
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, "app.js"), []byte(strings.Repeat("code\n", 20)), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	originalPath := profilePath
+	originalBrowser := browserType
 	originalFormat := outputFormat
-	originalChartType := batchChartType
+	originalFunction := linesFunction
+	originalRoot := linesSourceRoot
 	defer func() {
-		batchConfigFile = originalConfig
-		batchProfiles = originalProfiles
+		profilePath = originalPath
+		browserType = originalBrowser
 		outputFormat = originalFormat
-		batchChartType = originalChartType
+		linesFunction = originalFunction
+		linesSourceRoot = originalRoot
 	}()
 
-	batchConfigFile = ""
-	batchProfiles = path + ":2:Test"
-	batchChartType = "wall_clock"
-
-	// Test text output
-	outputFormat = "text"
-	err := runBatch(batchCmd, []string{})
-	if err != nil {
-		t.Errorf("runBatch text format error: %v", err)
-	}
-
-	// Test markdown output
-	outputFormat = "markdown"
-	err = runBatch(batchCmd, []string{})
-	if err != nil {
-		t.Errorf("runBatch markdown format error: %v", err)
+	profilePath = path
+	browserType = "auto"
+	linesFunction = "decrypt"
+
+	for _, sourceRoot := range []string{"", root} {
+		linesSourceRoot = sourceRoot
+		for _, format := range []string{"text", "markdown", "json"} {
+			outputFormat = format
+			if err := runLines(linesCmd, []string{}); err != nil {
+				t.Errorf("runLines %s format (source root=%q) error: %v", format, sourceRoot, err)
+			}
+		}
 	}
+}
 
-	// Test JSON output
-	outputFormat = "json"
-	err = runBatch(batchCmd, []string{})
-	if err != nil {
-		t.Errorf("runBatch json format error: %v", err)
+func TestResourcesCmd_Definition(t *testing.T) {
+	if resourcesCmd.Use != "resources" {
+		t.Errorf("resourcesCmd.Use = %s, want 'resources'", resourcesCmd.Use)
 	}
-
-	// Test SVG output
-	outputFormat = "svg"
-	err = runBatch(batchCmd, []string{})
-	if err != nil {
-		t.Errorf("runBatch svg format error: %v", err)
+	if resourcesCmd.Flags().Lookup("first-party") == nil {
+		t.Error("expected 'first-party' flag to be defined")
 	}
 }
 
-func TestRunBatch_ConfigFile(t *testing.T) {
-	profile := testutil.ProfileWithWorkers(2)
-	profilePath := testutil.TempProfileFile(t, profile)
+func TestRunResources_MissingProfile(t *testing.T) {
+	originalPath := profilePath
+	defer func() { profilePath = originalPath }()
 
-	// Create a temp config file
-	tmpDir := t.TempDir()
-	configPath := tmpDir + "/batch.yaml"
-	configContent := `profiles:
-  - path: ` + profilePath + `
-    workers: 2
-    label: Test
-`
-	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
-		t.Fatalf("failed to write config file: %v", err)
+	profilePath = ""
+
+	if err := runResources(resourcesCmd, []string{}); err == nil {
+		t.Error("expected error for missing profile path")
 	}
+}
 
-	originalConfig := batchConfigFile
-	originalProfiles := batchProfiles
+func TestRunResources_Success(t *testing.T) {
+	path := testutil.TempProfileFile(t, testutil.ProfileWithResources())
+
+	originalPath := profilePath
+	originalBrowser := browserType
 	originalFormat := outputFormat
+	originalFirstParty := resourcesFirstParty
 	defer func() {
-		batchConfigFile = originalConfig
-		batchProfiles = originalProfiles
+		profilePath = originalPath
+		browserType = originalBrowser
 		outputFormat = originalFormat
+		resourcesFirstParty = originalFirstParty
 	}()
 
-	batchConfigFile = configPath
-	batchProfiles = ""
-	outputFormat = "text"
+	profilePath = path
+	browserType = "auto"
 
-	err := runBatch(batchCmd, []string{})
-	if err != nil {
-		t.Errorf("runBatch with config file error: %v", err)
+	for _, firstParty := range [][]string{nil, {"example.com"}} {
+		resourcesFirstParty = firstParty
+		for _, format := range []string{"text", "markdown", "json"} {
+			outputFormat = format
+			if err := runResources(resourcesCmd, []string{}); err != nil {
+				t.Errorf("runResources %s format (first party=%v) error: %v", format, firstParty, err)
+			}
+		}
 	}
 }
 
-func TestRunBatch_SVGFile(t *testing.T) {
-	profile := testutil.ProfileWithWorkers(2)
-	path := testutil.TempProfileFile(t, profile)
+func TestFramesCmd_Definition(t *testing.T) {
+	if framesCmd.Use != "frames" {
+		t.Errorf("framesCmd.Use = %s, want 'frames'", framesCmd.Use)
+	}
+	if framesCmd.Flags().Lookup("limit") == nil {
+		t.Error("expected 'limit' flag to be defined")
+	}
+}
 
-	tmpDir := t.TempDir()
-	svgPath := tmpDir + "/chart.svg"
+func TestRunFrames_MissingProfile(t *testing.T) {
+	originalPath := profilePath
+	defer func() { profilePath = originalPath }()
 
-	originalConfig := batchConfigFile
-	originalProfiles := batchProfiles
+	profilePath = ""
+
+	if err := runFrames(framesCmd, []string{}); err == nil {
+		t.Error("expected error for missing profile path")
+	}
+}
+
+func TestRunFrames_Success(t *testing.T) {
+	originalPath := profilePath
+	originalBrowser := browserType
 	originalFormat := outputFormat
-	originalSVGPath := batchSVGPath
 	defer func() {
-		batchConfigFile = originalConfig
-		batchProfiles = originalProfiles
+		profilePath = originalPath
+		browserType = originalBrowser
 		outputFormat = originalFormat
-		batchSVGPath = originalSVGPath
 	}()
 
-	batchConfigFile = ""
-	batchProfiles = path + ":2:Test"
-	outputFormat = "svg-file"
-	batchSVGPath = svgPath
+	browserType = "auto"
 
-	err := runBatch(batchCmd, []string{})
-	if err != nil {
-		t.Errorf("runBatch svg-file format error: %v", err)
+	for _, profile := range []*parser.Profile{testutil.ProfileWithFrames(), testutil.MinimalProfile()} {
+		profilePath = testutil.TempProfileFile(t, profile)
+		for _, format := range []string{"text", "markdown", "json"} {
+			outputFormat = format
+			if err := runFrames(framesCmd, []string{}); err != nil {
+				t.Errorf("runFrames %s format error: %v", format, err)
+			}
+		}
 	}
+}
 
-	// Check file was created
-	if _, err := os.Stat(svgPath); os.IsNotExist(err) {
-		t.Error("expected SVG file to be created")
+func TestInteractionsCmd_Definition(t *testing.T) {
+	if interactionsCmd.Use != "interactions" {
+		t.Errorf("interactionsCmd.Use = %s, want 'interactions'", interactionsCmd.Use)
+	}
+	if interactionsCmd.Flags().Lookup("limit") == nil {
+		t.Error("expected 'limit' flag to be defined")
 	}
 }
 
-func TestRunBatch_InvalidInlineFormat(t *testing.T) {
-	originalConfig := batchConfigFile
-	originalProfiles := batchProfiles
-	defer func() {
-		batchConfigFile = originalConfig
-		batchProfiles = originalProfiles
-	}()
+func TestRunInteractions_MissingProfile(t *testing.T) {
+	originalPath := profilePath
+	defer func() { profilePath = originalPath }()
 
-	batchConfigFile = ""
-	batchProfiles = "invalid-format" // missing :workers:label
+	profilePath = ""
 
-	err := runBatch(batchCmd, []string{})
-	if err == nil {
-		t.Error("expected error for invalid inline format")
+	if err := runInteractions(interactionsCmd, []string{}); err == nil {
+		t.Error("expected error for missing profile path")
 	}
 }
 
-func TestRunBatch_InvalidConfigFile(t *testing.T) {
-	originalConfig := batchConfigFile
-	originalProfiles := batchProfiles
+func TestRunInteractions_Success(t *testing.T) {
+	originalPath := profilePath
+	originalBrowser := browserType
+	originalFormat := outputFormat
 	defer func() {
-		batchConfigFile = originalConfig
-		batchProfiles = originalProfiles
+		profilePath = originalPath
+		browserType = originalBrowser
+		outputFormat = originalFormat
 	}()
 
-	batchConfigFile = "/nonexistent/config.yaml"
-	batchProfiles = ""
+	browserType = "auto"
 
-	err := runBatch(batchCmd, []string{})
-	if err == nil {
-		t.Error("expected error for nonexistent config file")
+	for _, profile := range []*parser.Profile{testutil.ProfileWithInteractions(), testutil.MinimalProfile()} {
+		profilePath = testutil.TempProfileFile(t, profile)
+		for _, format := range []string{"text", "markdown", "json"} {
+			outputFormat = format
+			if err := runInteractions(interactionsCmd, []string{}); err != nil {
+				t.Errorf("runInteractions %s format error: %v", format, err)
+			}
+		}
 	}
 }
 
-// Scaling comparison tests
-func TestRunScaling_WithComparison(t *testing.T) {
-	profile1 := testutil.ProfileWithWorkers(2)
-	profile2 := testutil.ProfileWithWorkers(4)
-	path1 := testutil.TempProfileFile(t, profile1)
-	path2 := testutil.TempProfileFile(t, profile2)
+func TestLayoutShiftsCmd_Definition(t *testing.T) {
+	if layoutShiftsCmd.Use != "layout-shifts" {
+		t.Errorf("layoutShiftsCmd.Use = %s, want 'layout-shifts'", layoutShiftsCmd.Use)
+	}
+	if layoutShiftsCmd.Flags().Lookup("limit") == nil {
+		t.Error("expected 'limit' flag to be defined")
+	}
+}
+
+func TestRunLayoutShifts_MissingProfile(t *testing.T) {
+	originalPath := profilePath
+	defer func() { profilePath = originalPath }()
+
+	profilePath = ""
+
+	if err := runLayoutShifts(layoutShiftsCmd, []string{}); err == nil {
+		t.Error("expected error for missing profile path")
+	}
+}
 
+func TestRunLayoutShifts_Success(t *testing.T) {
 	originalPath := profilePath
 	originalBrowser := browserType
 	originalFormat := outputFormat
-	originalCompare := compareProfile
 	defer func() {
 		profilePath = originalPath
 		browserType = originalBrowser
 		outputFormat = originalFormat
-		compareProfile = originalCompare
 	}()
 
-	profilePath = path1
-	compareProfile = path2
 	browserType = "auto"
 
-	// Test text output
-	outputFormat = "text"
-	err := runScaling(scalingCmd, []string{})
-	if err != nil {
-		t.Errorf("runScaling comparison text format error: %v", err)
+	for _, profile := range []*parser.Profile{testutil.ProfileWithLayoutShifts(), testutil.MinimalProfile()} {
+		profilePath = testutil.TempProfileFile(t, profile)
+		for _, format := range []string{"text", "markdown", "json"} {
+			outputFormat = format
+			if err := runLayoutShifts(layoutShiftsCmd, []string{}); err != nil {
+				t.Errorf("runLayoutShifts %s format error: %v", format, err)
+			}
+		}
 	}
+}
 
-	// Test markdown output
-	outputFormat = "markdown"
-	err = runScaling(scalingCmd, []string{})
-	if err != nil {
-		t.Errorf("runScaling comparison markdown format error: %v", err)
+func TestNetworkCmd_Definition(t *testing.T) {
+	if networkCmd.Use != "network" {
+		t.Errorf("networkCmd.Use = %s, want 'network'", networkCmd.Use)
 	}
-
+	if networkCmd.Flags().Lookup("limit") == nil {
+		t.Error("expected 'limit' flag to be defined")
+	}
+}
+
+func TestRunNetwork_MissingProfile(t *testing.T) {
+	originalPath := profilePath
+	defer func() { profilePath = originalPath }()
+
+	profilePath = ""
+
+	if err := runNetwork(networkCmd, []string{}); err == nil {
+		t.Error("expected error for missing profile path")
+	}
+}
+
+func TestRunNetwork_Success(t *testing.T) {
+	originalPath := profilePath
+	originalBrowser := browserType
+	originalFormat := outputFormat
+	defer func() {
+		profilePath = originalPath
+		browserType = originalBrowser
+		outputFormat = originalFormat
+	}()
+
+	browserType = "auto"
+
+	for _, profile := range []*parser.Profile{testutil.ProfileWithNetwork(), testutil.MinimalProfile()} {
+		profilePath = testutil.TempProfileFile(t, profile)
+		for _, format := range []string{"text", "markdown", "json", "svg"} {
+			outputFormat = format
+			if err := runNetwork(networkCmd, []string{}); err != nil {
+				t.Errorf("runNetwork %s format error: %v", format, err)
+			}
+		}
+	}
+}
+
+func TestFormatBytes(t *testing.T) {
+	tests := map[int64]string{0: "0 B", 512: "512 B", 2048: "2.0 KiB", 3 << 20: "3.0 MiB"}
+	for n, want := range tests {
+		if got := formatBytes(n); got != want {
+			t.Errorf("formatBytes(%d) = %s, want %s", n, got, want)
+		}
+	}
+}
+
+func TestScriptsCmd_Definition(t *testing.T) {
+	if scriptsCmd.Use != "scripts" {
+		t.Errorf("scriptsCmd.Use = %s, want 'scripts'", scriptsCmd.Use)
+	}
+	if scriptsCmd.Flags().Lookup("limit") == nil {
+		t.Error("expected 'limit' flag to be defined")
+	}
+}
+
+func TestRunScripts_MissingProfile(t *testing.T) {
+	originalPath := profilePath
+	defer func() { profilePath = originalPath }()
+
+	profilePath = ""
+
+	if err := runScripts(scriptsCmd, []string{}); err == nil {
+		t.Error("expected error for missing profile path")
+	}
+}
+
+func TestRunScripts_Success(t *testing.T) {
+	originalPath := profilePath
+	originalBrowser := browserType
+	originalFormat := outputFormat
+	defer func() {
+		profilePath = originalPath
+		browserType = originalBrowser
+		outputFormat = originalFormat
+	}()
+
+	browserType = "auto"
+
+	for _, profile := range []*parser.Profile{testutil.ProfileWithScriptCosts(), testutil.MinimalProfile()} {
+		profilePath = testutil.TempProfileFile(t, profile)
+		for _, format := range []string{"text", "markdown", "json"} {
+			outputFormat = format
+			if err := runScripts(scriptsCmd, []string{}); err != nil {
+				t.Errorf("runScripts %s format error: %v", format, err)
+			}
+		}
+	}
+}
+
+func TestGCCmd_Definition(t *testing.T) {
+	if gcCmd.Use != "gc" {
+		t.Errorf("gcCmd.Use = %s, want 'gc'", gcCmd.Use)
+	}
+	if gcCmd.Flags().Lookup("limit") == nil {
+		t.Error("expected 'limit' flag to be defined")
+	}
+}
+
+func TestRunGC_MissingProfile(t *testing.T) {
+	originalPath := profilePath
+	defer func() { profilePath = originalPath }()
+
+	profilePath = ""
+
+	if err := runGC(gcCmd, []string{}); err == nil {
+		t.Error("expected error for missing profile path")
+	}
+}
+
+func TestRunGC_Success(t *testing.T) {
+	originalPath := profilePath
+	originalBrowser := browserType
+	originalFormat := outputFormat
+	defer func() {
+		profilePath = originalPath
+		browserType = originalBrowser
+		outputFormat = originalFormat
+	}()
+
+	browserType = "auto"
+
+	for _, profile := range []*parser.Profile{testutil.ProfileWithGCPayloads(), testutil.ProfileWithGC(), testutil.MinimalProfile()} {
+		profilePath = testutil.TempProfileFile(t, profile)
+		for _, format := range []string{"text", "markdown", "json"} {
+			outputFormat = format
+			if err := runGC(gcCmd, []string{}); err != nil {
+				t.Errorf("runGC %s format error: %v", format, err)
+			}
+		}
+	}
+}
+
+func TestStylesCmd_Definition(t *testing.T) {
+	if stylesCmd.Use != "styles" {
+		t.Errorf("stylesCmd.Use = %s, want 'styles'", stylesCmd.Use)
+	}
+	if stylesCmd.Flags().Lookup("limit") == nil {
+		t.Error("expected 'limit' flag to be defined")
+	}
+}
+
+func TestRunStyles_MissingProfile(t *testing.T) {
+	originalPath := profilePath
+	defer func() { profilePath = originalPath }()
+
+	profilePath = ""
+
+	if err := runStyles(stylesCmd, []string{}); err == nil {
+		t.Error("expected error for missing profile path")
+	}
+}
+
+func TestRunStyles_Success(t *testing.T) {
+	originalPath := profilePath
+	originalBrowser := browserType
+	originalFormat := outputFormat
+	defer func() {
+		profilePath = originalPath
+		browserType = originalBrowser
+		outputFormat = originalFormat
+	}()
+
+	browserType = "auto"
+
+	for _, profile := range []*parser.Profile{testutil.ProfileWithStyles(), testutil.MinimalProfile()} {
+		profilePath = testutil.TempProfileFile(t, profile)
+		for _, format := range []string{"text", "markdown", "json"} {
+			outputFormat = format
+			if err := runStyles(stylesCmd, []string{}); err != nil {
+				t.Errorf("runStyles %s format error: %v", format, err)
+			}
+		}
+	}
+}
+
+func TestConcurrencyCmd_Definition(t *testing.T) {
+	if concurrencyCmd.Use != "concurrency" {
+		t.Errorf("concurrencyCmd.Use = %s, want 'concurrency'", concurrencyCmd.Use)
+	}
+	if concurrencyCmd.Flags().Lookup("bucket") == nil {
+		t.Error("expected 'bucket' flag to be defined")
+	}
+}
+
+func TestRunConcurrency_MissingProfile(t *testing.T) {
+	originalPath := profilePath
+	defer func() { profilePath = originalPath }()
+
+	profilePath = ""
+
+	if err := runConcurrency(concurrencyCmd, []string{}); err == nil {
+		t.Error("expected error for missing profile path")
+	}
+}
+
+func TestRunConcurrency_Success(t *testing.T) {
+	originalPath := profilePath
+	originalBrowser := browserType
+	originalFormat := outputFormat
+	defer func() {
+		profilePath = originalPath
+		browserType = originalBrowser
+		outputFormat = originalFormat
+	}()
+
+	browserType = "auto"
+
+	for _, profile := range []*parser.Profile{testutil.ProfileWithConcurrency(), testutil.MinimalProfile()} {
+		profilePath = testutil.TempProfileFile(t, profile)
+		for _, format := range []string{"text", "markdown", "json", "svg"} {
+			outputFormat = format
+			if err := runConcurrency(concurrencyCmd, []string{}); err != nil {
+				t.Errorf("runConcurrency %s format error: %v", format, err)
+			}
+		}
+	}
+}
+
+func TestCriticalPathCmd_Definition(t *testing.T) {
+	if criticalPathCmd.Use != "critical-path" {
+		t.Errorf("criticalPathCmd.Use = %s, want 'critical-path'", criticalPathCmd.Use)
+	}
+	for _, flag := range []string{"start", "end", "find-last"} {
+		if criticalPathCmd.Flags().Lookup(flag) == nil {
+			t.Errorf("expected '%s' flag to be defined", flag)
+		}
+	}
+}
+
+func TestRunCriticalPath_MissingProfile(t *testing.T) {
+	originalPath := profilePath
+	defer func() { profilePath = originalPath }()
+
+	profilePath = ""
+
+	if err := runCriticalPath(criticalPathCmd, []string{}); err == nil {
+		t.Error("expected error for missing profile path")
+	}
+}
+
+func TestRunCriticalPath_Success(t *testing.T) {
+	originalPath := profilePath
+	originalBrowser := browserType
+	originalFormat := outputFormat
+	defer func() {
+		profilePath = originalPath
+		browserType = originalBrowser
+		outputFormat = originalFormat
+		criticalPathStart, criticalPathEnd = "", ""
+	}()
+
+	browserType = "auto"
+	criticalPathStart = "UserTiming:decrypt-start"
+	criticalPathEnd = "UserTiming:decrypt-end"
+
+	profilePath = testutil.TempProfileFile(t, testutil.ProfileWithCriticalPath())
+	for _, format := range []string{"text", "markdown", "json"} {
+		outputFormat = format
+		if err := runCriticalPath(criticalPathCmd, []string{}); err != nil {
+			t.Errorf("runCriticalPath %s format error: %v", format, err)
+		}
+	}
+
+	profilePath = testutil.TempProfileFile(t, testutil.MinimalProfile())
+	if err := runCriticalPath(criticalPathCmd, []string{}); err == nil {
+		t.Error("expected error when the markers are missing")
+	}
+}
+
+func TestMessagesCmd_Definition(t *testing.T) {
+	if messagesCmd.Use != "messages" {
+		t.Errorf("messagesCmd.Use = %s, want 'messages'", messagesCmd.Use)
+	}
+	if messagesCmd.Flags().Lookup("limit") == nil {
+		t.Error("expected 'limit' flag to be defined")
+	}
+}
+
+func TestRunMessages_MissingProfile(t *testing.T) {
+	originalPath := profilePath
+	defer func() { profilePath = originalPath }()
+
+	profilePath = ""
+
+	if err := runMessages(messagesCmd, []string{}); err == nil {
+		t.Error("expected error for missing profile path")
+	}
+}
+
+func TestRunMessages_Success(t *testing.T) {
+	originalPath := profilePath
+	originalBrowser := browserType
+	originalFormat := outputFormat
+	defer func() {
+		profilePath = originalPath
+		browserType = originalBrowser
+		outputFormat = originalFormat
+	}()
+
+	browserType = "auto"
+
+	for _, profile := range []*parser.Profile{testutil.ProfileWithMessageFlow(), testutil.MinimalProfile()} {
+		profilePath = testutil.TempProfileFile(t, profile)
+		for _, format := range []string{"text", "markdown", "json", "dot", "mermaid"} {
+			outputFormat = format
+			if err := runMessages(messagesCmd, []string{}); err != nil {
+				t.Errorf("runMessages %s format error: %v", format, err)
+			}
+		}
+	}
+}
+
+func TestCompareCmd_Definition(t *testing.T) {
+	if compareCmd.Use != "compare" {
+		t.Errorf("Use = %v, want compare", compareCmd.Use)
+	}
+	for _, flag := range []string{"baseline", "candidate", "start", "end", "alpha", "limit"} {
+		if compareCmd.Flags().Lookup(flag) == nil {
+			t.Errorf("expected --%s flag", flag)
+		}
+	}
+}
+
+func TestRunCompare_MissingProfiles(t *testing.T) {
+	originalBaselines, originalCandidates := compareBaselines, compareCandidates
+	defer func() {
+		compareBaselines, compareCandidates = originalBaselines, originalCandidates
+	}()
+
+	compareBaselines = []string{"base.json"}
+	compareCandidates = nil
+	err := runCompare(compareCmd, []string{})
+	testutil.AssertErrorContains(t, err, "baseline and candidate profiles are required")
+}
+
+func TestRunCompare_InvalidAlpha(t *testing.T) {
+	originalBaselines, originalCandidates, originalAlpha := compareBaselines, compareCandidates, compareAlpha
+	defer func() {
+		compareBaselines, compareCandidates, compareAlpha = originalBaselines, originalCandidates, originalAlpha
+	}()
+
+	compareBaselines, compareCandidates = []string{"a.json"}, []string{"b.json"}
+	compareAlpha = 1.5
+	err := runCompare(compareCmd, []string{})
+	testutil.AssertErrorContains(t, err, "--alpha")
+}
+
+func TestRunCompare_Success(t *testing.T) {
+	originalBrowser := browserType
+	originalFormat := outputFormat
+	originalBaselines, originalCandidates := compareBaselines, compareCandidates
+	originalStart, originalEnd, originalAlpha := compareStart, compareEnd, compareAlpha
+	defer func() {
+		browserType = originalBrowser
+		outputFormat = originalFormat
+		compareBaselines, compareCandidates = originalBaselines, originalCandidates
+		compareStart, compareEnd, compareAlpha = originalStart, originalEnd, originalAlpha
+	}()
+
+	browserType = "auto"
+	compareAlpha = 0.05
+	compareStart, compareEnd = "start", "end"
+	compareBaselines, compareCandidates = nil, nil
+	for i := 0; i < 4; i++ {
+		compareBaselines = append(compareBaselines, testutil.TempProfileFile(t, testutil.ProfileRun(1000+float64(i), 30)))
+		compareCandidates = append(compareCandidates, testutil.TempProfileFile(t, testutil.ProfileRun(1200+float64(i), 60)))
+	}
+
+	for _, format := range []string{"text", "markdown", "json"} {
+		outputFormat = format
+		if err := runCompare(compareCmd, []string{}); err != nil {
+			t.Errorf("runCompare %s format error: %v", format, err)
+		}
+	}
+}
+
+func TestRunCompare_LoadError(t *testing.T) {
+	originalBaselines, originalCandidates := compareBaselines, compareCandidates
+	defer func() {
+		compareBaselines, compareCandidates = originalBaselines, originalCandidates
+	}()
+
+	compareBaselines = []string{testutil.TempProfileFile(t, testutil.MinimalProfile())}
+	compareCandidates = []string{"/nonexistent/profile.json"}
+	err := runCompare(compareCmd, []string{})
+	testutil.AssertErrorContains(t, err, "/nonexistent/profile.json")
+}
+
+func TestCheckCmd_Definition(t *testing.T) {
+	if checkCmd.Use != "check" {
+		t.Errorf("Use = %v, want check", checkCmd.Use)
+	}
+	for _, flag := range []string{"budget", "baseline", "tolerance"} {
+		if checkCmd.Flags().Lookup(flag) == nil {
+			t.Errorf("expected --%s flag", flag)
+		}
+	}
+}
+
+func TestRunCheck_MissingArguments(t *testing.T) {
+	originalPath, originalBudget := profilePath, checkBudgetFile
+	defer func() {
+		profilePath, checkBudgetFile = originalPath, originalBudget
+	}()
+
+	profilePath, checkBudgetFile = "", "budget.yaml"
+	testutil.AssertErrorContains(t, runCheck(checkCmd, []string{}), "profile path is required")
+
+	profilePath, checkBudgetFile = "profile.json", ""
+	testutil.AssertErrorContains(t, runCheck(checkCmd, []string{}), "budget file is required")
+}
+
+// withCheckState saves and restores the flags runCheck reads
+func withCheckState(t *testing.T) {
+	originalPath := profilePath
+	originalBrowser := browserType
+	originalFormat := outputFormat
+	originalBudget, originalBaseline, originalTolerance := checkBudgetFile, checkBaseline, checkTolerance
+	t.Cleanup(func() {
+		profilePath = originalPath
+		browserType = originalBrowser
+		outputFormat = originalFormat
+		checkBudgetFile, checkBaseline, checkTolerance = originalBudget, originalBaseline, originalTolerance
+	})
+	browserType = "auto"
+	checkBaseline, checkTolerance = "", 0
+}
+
+func TestRunCheck_Passes(t *testing.T) {
+	withCheckState(t)
+
+	profilePath = testutil.TempProfileFile(t, testutil.ProfileRun(1000, 30))
+	checkBudgetFile = testutil.TempTextFile(t, `rules:
+  - metric: score
+    min: 80
+  - metric: function_self_time_ms
+    function: computeHash
+    max: 50
+`, "budget.yaml")
+
+	for _, format := range []string{"text", "markdown", "json", "junit", "sarif"} {
+		outputFormat = format
+		if err := runCheck(checkCmd, []string{}); err != nil {
+			t.Errorf("runCheck %s format error: %v", format, err)
+		}
+	}
+}
+
+func TestRunCheck_Fails(t *testing.T) {
+	withCheckState(t)
+
+	profilePath = testutil.TempProfileFile(t, testutil.ProfileRun(1100, 30))
+	checkBaseline = testutil.TempProfileFile(t, testutil.ProfileRun(1000, 30))
+	checkTolerance = 5
+	checkBudgetFile = testutil.TempTextFile(t, `{"rules": [{"metric": "duration_ms"}, {"metric": "long_tasks", "max": 0}]}`, "budget.json")
+
+	for _, format := range []string{"text", "markdown", "json", "junit", "sarif"} {
+		outputFormat = format
+		testutil.AssertErrorContains(t, runCheck(checkCmd, []string{}), "1 of 2 budget rules failed")
+	}
+}
+
+func TestRunCheck_InvalidBudget(t *testing.T) {
+	withCheckState(t)
+	profilePath = testutil.TempProfileFile(t, testutil.MinimalProfile())
+
+	checkBudgetFile = testutil.TempTextFile(t, "rules:\n  - metric: fps\n    max: 1\n", "budget.yaml")
+	testutil.AssertErrorContains(t, runCheck(checkCmd, []string{}), "unknown metric")
+
+	checkBudgetFile = testutil.TempTextFile(t, "rules: [", "broken.yaml")
+	testutil.AssertErrorContains(t, runCheck(checkCmd, []string{}), "failed to parse budget file")
+
+	checkBudgetFile = "/nonexistent/budget.yaml"
+	testutil.AssertErrorContains(t, runCheck(checkCmd, []string{}), "failed to read budget file")
+}
+
+func TestLoadBudget_RuleLines(t *testing.T) {
+	path := testutil.TempTextFile(t, `tolerance: 5
+rules:
+  - metric: score
+    min: 80
+  - metric: long_tasks
+    max: 3
+`, "budget.yaml")
+
+	budget, lines, err := loadBudget(path)
+	testutil.AssertNoError(t, err)
+	if budget.Tolerance != 5 || len(budget.Rules) != 2 {
+		t.Errorf("unexpected budget %+v", budget)
+	}
+	if len(lines) != 2 || lines[0] != 3 || lines[1] != 5 {
+		t.Errorf("lines = %v, want [3 5]", lines)
+	}
+}
+
+func TestWriteCheckSARIF_FailuresOnly(t *testing.T) {
+	zero := 0.0
+	report := analyzer.BudgetReport{Results: []analyzer.BudgetResult{
+		{Rule: "score", Metric: "score", Passed: true},
+		{Rule: "long_tasks", Metric: "long_tasks", Value: 2, Max: &zero, Message: "2.00 exceeds the maximum 0.00"},
+	}}
+
+	var sb strings.Builder
+	testutil.AssertNoError(t, writeCheckSARIF(&sb, report, []int{2, 4}))
+	out := sb.String()
+	testutil.AssertStringContains(t, out, `"ruleId": "budget/long_tasks"`)
+	testutil.AssertStringContains(t, out, `"startLine": 4`)
+	testutil.AssertStringNotContains(t, out, `"budget/score"`)
+
+	sb.Reset()
+	testutil.AssertNoError(t, writeCheckJUnit(&sb, report, []int{2, 4}))
+	testutil.AssertStringContains(t, sb.String(), `tests="2" failures="1"`)
+}
+
+func TestRunFindingCommands_CIFormats(t *testing.T) {
+	originalPath := profilePath
+	originalBrowser := browserType
+	originalFormat := outputFormat
+	originalSeverity := minSeverity
+	defer func() {
+		profilePath = originalPath
+		browserType = originalBrowser
+		outputFormat = originalFormat
+		minSeverity = originalSeverity
+	}()
+	browserType = "auto"
+	minSeverity = ""
+
+	tests := []struct {
+		name    string
+		profile *parser.Profile
+		run     func() error
+	}{
+		{"analyze", testutil.ProfileWithBottlenecks(), func() error { return runAnalyze(analyzeCmd, []string{}) }},
+		{"bottlenecks", testutil.ProfileWithBottlenecks(), func() error { return runBottlenecks(bottlenecksCmd, []string{}) }},
+		{"contention", testutil.ProfileWithContentionData(), func() error { return runContention(contentionCmd, []string{}) }},
+		{"crypto", testutil.ProfileWithCrypto(), func() error { return runCrypto(cryptoCmd, []string{}) }},
+		{"workers", testutil.ProfileWithWorkersData(), func() error { return runWorkers(workersCmd, []string{}) }},
+	}
+	for _, tt := range tests {
+		profilePath = testutil.TempProfileFile(t, tt.profile)
+		for _, format := range []string{"junit", "sarif"} {
+			outputFormat = format
+			if err := tt.run(); err != nil {
+				t.Errorf("%s %s format error: %v", tt.name, format, err)
+			}
+		}
+	}
+}
+
+func TestWriteFindingsJUnit(t *testing.T) {
+	findings := []analyzer.Finding{
+		{Rule: "bottleneck/long-tasks", Title: "Long Tasks", Severity: analyzer.SeverityHigh, Message: "3 long tasks",
+			File: "https://example.com/js/app.js", Line: 10},
+	}
+
+	var sb strings.Builder
+	testutil.AssertNoError(t, writeFindingsJUnit(&sb, "bottlenecks", findings))
+	out := sb.String()
+	testutil.AssertStringContains(t, out, `tests="1" failures="1"`)
+	testutil.AssertStringContains(t, out, `classname="perfowl.bottleneck.long-tasks"`)
+	testutil.AssertStringContains(t, out, `line="10"`)
+
+	sb.Reset()
+	testutil.AssertNoError(t, writeFindingsJUnit(&sb, "bottlenecks", nil))
+	testutil.AssertStringContains(t, sb.String(), `tests="1" failures="0"`)
+	testutil.AssertStringContains(t, sb.String(), "no issues found")
+}
+
+func TestWriteFindingsSARIF_Levels(t *testing.T) {
+	findings := []analyzer.Finding{
+		{Rule: "bottleneck/long-tasks", Title: "Long Tasks", Severity: analyzer.SeverityHigh, Message: "a", File: "app.js", Line: 3},
+		{Rule: "bottleneck/gc-pressure", Title: "GC Pressure", Severity: analyzer.SeverityMedium, Message: "b"},
+		{Rule: "bottleneck/layout-thrashing", Title: "Layout Thrashing", Severity: analyzer.SeverityLow, Message: "c"},
+	}
+
+	var sb strings.Builder
+	testutil.AssertNoError(t, writeFindingsSARIF(&sb, findings, ""))
+	out := sb.String()
+	for _, level := range []string{`"level": "error"`, `"level": "warning"`, `"level": "note"`} {
+		testutil.AssertStringContains(t, out, level)
+	}
+	testutil.AssertStringContains(t, out, `"uri": "app.js"`)
+	testutil.AssertStringContains(t, out, `"startLine": 3`)
+}
+
+func TestWriteFindingsSARIF_SourceRoot(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "js"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "js", "app.js"), []byte("run()\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	findings := []analyzer.Finding{
+		{Rule: "bottleneck/long-tasks", Title: "Long Tasks", Severity: analyzer.SeverityHigh, Message: "a", File: "https://cdn.example.com/js/app.js", Line: 3},
+		{Rule: "bottleneck/gc-pressure", Title: "GC Pressure", Severity: analyzer.SeverityMedium, Message: "b", File: "https://cdn.example.com/vendor.js", Line: 9},
+	}
+
+	var sb strings.Builder
+	testutil.AssertNoError(t, writeFindingsSARIF(&sb, findings, root))
+	out := sb.String()
+	testutil.AssertStringContains(t, out, `"uri": "js/app.js"`)
+	if strings.Contains(out, "cdn.example.com") || strings.Count(out, `"physicalLocation"`) != 1 {
+		t.Errorf("expected only the resolvable script to get a location, got %s", out)
+	}
+
+	// Without a root, URLs are left out rather than emitted as-is
+	sb.Reset()
+	testutil.AssertNoError(t, writeFindingsSARIF(&sb, findings, ""))
+	if strings.Contains(sb.String(), `"locations"`) {
+		t.Errorf("expected no locations for URLs without --source-root, got %s", sb.String())
+	}
+}
+
+func TestReportCmd_Definition(t *testing.T) {
+	if reportCmd.Use != "report" {
+		t.Errorf("Use = %v, want report", reportCmd.Use)
+	}
+	for _, flag := range []string{"baseline", "batch", "title", "limit"} {
+		if reportCmd.Flags().Lookup(flag) == nil {
+			t.Errorf("expected --%s flag", flag)
+		}
+	}
+}
+
+func withReportState(t *testing.T) {
+	originalPath := profilePath
+	originalBrowser := browserType
+	originalFormat := outputFormat
+	originalBaseline, originalBatch, originalTitle, originalLimit := reportBaseline, reportBatchConfig, reportTitle, reportLimit
+	t.Cleanup(func() {
+		profilePath = originalPath
+		browserType = originalBrowser
+		outputFormat = originalFormat
+		reportBaseline, reportBatchConfig, reportTitle, reportLimit = originalBaseline, originalBatch, originalTitle, originalLimit
+	})
+	browserType = "auto"
+	reportBaseline, reportBatchConfig, reportTitle, reportLimit = "", "", "", 20
+}
+
+func TestRunReport_WritesFile(t *testing.T) {
+	withReportState(t)
+
+	profilePath = testutil.TempProfileFile(t, testutil.ProfileWithBottlenecks())
+	reportBaseline = testutil.TempProfileFile(t, testutil.MinimalProfile())
+	reportTitle = "Checkout <flow>"
+	outputFormat = filepath.Join(t.TempDir(), "report.html")
+
+	testutil.AssertNoError(t, runReport(reportCmd, []string{}))
+
+	data, err := os.ReadFile(outputFormat)
+	testutil.AssertNoError(t, err)
+	out := string(data)
+	for _, want := range []string{"<!DOCTYPE html>", "Checkout &lt;flow&gt;", `<span class="score`, `<span class="sev medium">medium</span>`, "<td class=\"num regressed\">new</td>",
+		"Baseline Comparison", "Categories", "Top Functions", "Workers", "Contention", "<details open"} {
+		testutil.AssertStringContains(t, out, want)
+	}
+	// Offline: no external stylesheets or scripts, charts inlined without XML declarations
+	testutil.AssertStringNotContains(t, out, "<link")
+	testutil.AssertStringNotContains(t, out, "<script src")
+	testutil.AssertStringNotContains(t, out, "<?xml")
+}
+
+func TestRunReport_Charts(t *testing.T) {
+	withReportState(t)
+
+	path := testutil.TempProfileFile(t, testutil.ProfileWithCallTree())
+	profilePath = path
+	reportBatchConfig = testutil.TempTextFile(t, "profiles:\n  - path: "+path+"\n    workers: 1\n    label: Firefox\n  - path: "+path+"\n    workers: 2\n    label: Firefox\n", "batch.yaml")
+
+	report := buildHTMLReport(testutil.ProfileWithCallTree(), parser.BrowserFirefox, nil, nil)
+	if len(report.Charts) == 0 || report.Charts[0].Title != "Flame Graph" {
+		t.Errorf("expected the flame graph first, got %+v", report.Charts)
+	}
+	if report.Baseline != nil || len(report.Scaling) != 0 {
+		t.Error("expected no baseline or scaling sections without --baseline and --batch")
+	}
+
+	outputFormat = filepath.Join(t.TempDir(), "report.html")
+	testutil.AssertNoError(t, runReport(reportCmd, []string{}))
+	data, err := os.ReadFile(outputFormat)
+	testutil.AssertNoError(t, err)
+	testutil.AssertStringContains(t, string(data), `<details open id="scaling">`)
+	testutil.AssertStringContains(t, string(data), "Flame Graph (100.0ms)")
+}
+
+func TestRunReport_Errors(t *testing.T) {
+	withReportState(t)
+
+	profilePath = ""
+	testutil.AssertErrorContains(t, runReport(reportCmd, []string{}), "profile path is required")
+
+	profilePath = testutil.TempProfileFile(t, testutil.MinimalProfile())
+	outputFormat = "json"
+	testutil.AssertErrorContains(t, runReport(reportCmd, []string{}), "report only writes HTML")
+
+	outputFormat = "text"
+	reportBaseline = "/nonexistent/baseline.json"
+	testutil.AssertErrorContains(t, runReport(reportCmd, []string{}), "baseline")
+}
+
+func TestInlineSVG(t *testing.T) {
+	got := string(inlineSVG("<?xml version=\"1.0\"?>\n<svg></svg>"))
+	if got != "<svg></svg>" {
+		t.Errorf("inlineSVG = %q, want <svg></svg>", got)
+	}
+	if scoreClass(85) != "good" || scoreClass(70) != "fair" || scoreClass(10) != "poor" {
+		t.Error("unexpected score classes")
+	}
+}
+
+func TestProfileToolCommands_Definition(t *testing.T) {
+	for _, c := range []*cobra.Command{analyzeCmd, callTreeCmd, categoriesCmd, threadsCmd} {
+		if c.RunE == nil {
+			t.Errorf("%s has no RunE", c.Use)
+		}
+	}
+	if callTreeCmd.Flags().Lookup("thread") == nil || callTreeCmd.Flags().Lookup("limit") == nil {
+		t.Error("expected --thread and --limit flags on calltree")
+	}
+	if categoriesCmd.Flags().Lookup("thread") == nil {
+		t.Error("expected --thread flag on categories")
+	}
+}
+
+func TestRunProfileToolCommands_MissingProfile(t *testing.T) {
+	originalPath := profilePath
+	defer func() { profilePath = originalPath }()
+	profilePath = ""
+
+	for _, run := range []func(*cobra.Command, []string) error{runAnalyze, runCallTree, runCategories, runThreads} {
+		err := run(rootCmd, []string{})
+		testutil.AssertErrorContains(t, err, "profile path is required")
+	}
+}
+
+func TestRunProfileToolCommands_Success(t *testing.T) {
+	originalPath := profilePath
+	originalBrowser := browserType
+	originalFormat := outputFormat
+	originalCallTreeThread, originalCategoriesThread := callTreeThread, categoriesThread
+	defer func() {
+		profilePath = originalPath
+		browserType = originalBrowser
+		outputFormat = originalFormat
+		callTreeThread, categoriesThread = originalCallTreeThread, originalCategoriesThread
+	}()
+
+	browserType = "auto"
+	profilePath = testutil.TempProfileFile(t, testutil.ProfileWithCallTree())
+
+	tests := []struct {
+		name string
+		run  func(*cobra.Command, []string) error
+		cmd  *cobra.Command
+	}{
+		{"analyze", runAnalyze, analyzeCmd},
+		{"calltree", runCallTree, callTreeCmd},
+		{"categories", runCategories, categoriesCmd},
+		{"threads", runThreads, threadsCmd},
+	}
+	for _, thread := range []string{"", "GeckoMain"} {
+		callTreeThread, categoriesThread = thread, thread
+		for _, tt := range tests {
+			for _, format := range []string{"text", "markdown", "json"} {
+				outputFormat = format
+				if err := tt.run(tt.cmd, []string{}); err != nil {
+					t.Errorf("%s %s format (thread %q) error: %v", tt.name, format, thread, err)
+				}
+			}
+		}
+	}
+}
+
+func TestRunCompare_SinglePair(t *testing.T) {
+	originalBrowser := browserType
+	originalFormat := outputFormat
+	originalBaselines, originalCandidates := compareBaselines, compareCandidates
+	originalStart, originalEnd, originalAlpha := compareStart, compareEnd, compareAlpha
+	defer func() {
+		browserType = originalBrowser
+		outputFormat = originalFormat
+		compareBaselines, compareCandidates = originalBaselines, originalCandidates
+		compareStart, compareEnd, compareAlpha = originalStart, originalEnd, originalAlpha
+	}()
+
+	browserType = "auto"
+	compareAlpha = 0.05
+	compareStart, compareEnd = "", ""
+	compareBaselines = []string{testutil.TempProfileFile(t, testutil.ProfileWithCallTree())}
+	compareCandidates = []string{testutil.TempProfileFile(t, testutil.ProfileWithBottlenecks())}
+
+	for _, format := range []string{"text", "markdown", "json"} {
+		outputFormat = format
+		if err := runCompare(compareCmd, []string{}); err != nil {
+			t.Errorf("runCompare %s format error: %v", format, err)
+		}
+	}
+}
+
+func TestProfileDiffRows(t *testing.T) {
+	diff := analyzer.ProfileDiff{
+		Baseline:   analyzer.ProfileSummary{DurationMs: 1000, GCMajorCount: 1},
+		Comparison: analyzer.ProfileSummary{DurationMs: 1500, GCMajorCount: 4},
+	}
+
+	rows := profileDiffRows(diff)
+	testutil.AssertSliceLen(t, rows, 9)
+	if rows[0].metric != "Duration (ms)" || rows[0].baseline != 1000 || rows[0].candidate != 1500 {
+		t.Errorf("unexpected duration row %+v", rows[0])
+	}
+	if rows[4].metric != "Major GCs" || rows[4].decimals != 0 || rows[4].candidate != 4 {
+		t.Errorf("unexpected major GC row %+v", rows[4])
+	}
+}
+
+func TestRunExtensions_Success(t *testing.T) {
+	// Use ProfileWithExtensionActivity which has actual extension samples
+	profile := testutil.ProfileWithExtensionActivity()
+	path := testutil.TempProfileFile(t, profile)
+
+	originalPath := profilePath
+	originalBrowser := browserType
+	originalFormat := outputFormat
+	defer func() {
+		profilePath = originalPath
+		browserType = originalBrowser
+		outputFormat = originalFormat
+	}()
+
+	profilePath = path
+	browserType = "auto"
+
+	// Test text output
+	outputFormat = "text"
+	err := runExtensions(extensionsCmd, []string{})
+	if err != nil {
+		t.Errorf("runExtensions text format error: %v", err)
+	}
+
+	// Test markdown output
+	outputFormat = "markdown"
+	err = runExtensions(extensionsCmd, []string{})
+	if err != nil {
+		t.Errorf("runExtensions markdown format error: %v", err)
+	}
+
+	// Test JSON output
+	outputFormat = "json"
+	err = runExtensions(extensionsCmd, []string{})
+	if err != nil {
+		t.Errorf("runExtensions json format error: %v", err)
+	}
+}
+
+// Batch command tests
+func TestRunBatch_NoProfiles(t *testing.T) {
+	originalConfig := batchConfigFile
+	originalProfiles := batchProfiles
+	defer func() {
+		batchConfigFile = originalConfig
+		batchProfiles = originalProfiles
+	}()
+
+	batchConfigFile = ""
+	batchProfiles = ""
+
+	err := runBatch(batchCmd, []string{})
+	if err == nil {
+		t.Error("expected error for no profiles")
+	}
+	if !strings.Contains(err.Error(), "no profiles specified") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestRunBatch_InlineProfiles(t *testing.T) {
+	profile := testutil.ProfileWithWorkers(2)
+	path := testutil.TempProfileFile(t, profile)
+
+	originalConfig := batchConfigFile
+	originalProfiles := batchProfiles
+	originalFormat := outputFormat
+	originalChartType := batchChartType
+	defer func() {
+		batchConfigFile = originalConfig
+		batchProfiles = originalProfiles
+		outputFormat = originalFormat
+		batchChartType = originalChartType
+	}()
+
+	batchConfigFile = ""
+	batchProfiles = path + ":2:Test"
+	batchChartType = "wall_clock"
+
+	// Test text output
+	outputFormat = "text"
+	err := runBatch(batchCmd, []string{})
+	if err != nil {
+		t.Errorf("runBatch text format error: %v", err)
+	}
+
+	// Test markdown output
+	outputFormat = "markdown"
+	err = runBatch(batchCmd, []string{})
+	if err != nil {
+		t.Errorf("runBatch markdown format error: %v", err)
+	}
+
+	// Test JSON output
+	outputFormat = "json"
+	err = runBatch(batchCmd, []string{})
+	if err != nil {
+		t.Errorf("runBatch json format error: %v", err)
+	}
+
+	// Test SVG output
+	outputFormat = "svg"
+	err = runBatch(batchCmd, []string{})
+	if err != nil {
+		t.Errorf("runBatch svg format error: %v", err)
+	}
+}
+
+func TestRunBatch_RepeatedRuns(t *testing.T) {
+	fast := testutil.TempProfileFile(t, testutil.ProfileWithWorkers(2))
+	slow := testutil.TempProfileFile(t, testutil.ProfileWithWorkers(4))
+
+	originalConfig := batchConfigFile
+	originalProfiles := batchProfiles
+	originalFormat := outputFormat
+	defer func() {
+		batchConfigFile = originalConfig
+		batchProfiles = originalProfiles
+		outputFormat = originalFormat
+	}()
+
+	batchConfigFile = ""
+	batchProfiles = fast + ":2:Test," + slow + ":2:Test," + fast + ":2:Test," + slow + ":4:Test"
+
+	for _, format := range []string{"text", "markdown", "json", "svg"} {
+		outputFormat = format
+		if err := runBatch(batchCmd, []string{}); err != nil {
+			t.Errorf("runBatch %s format error: %v", format, err)
+		}
+	}
+}
+
+func TestRunBatch_ConfigFile(t *testing.T) {
+	profile := testutil.ProfileWithWorkers(2)
+	profilePath := testutil.TempProfileFile(t, profile)
+
+	// Create a temp config file
+	tmpDir := t.TempDir()
+	configPath := tmpDir + "/batch.yaml"
+	configContent := `profiles:
+  - path: ` + profilePath + `
+    workers: 2
+    label: Test
+`
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+		t.Fatalf("failed to write config file: %v", err)
+	}
+
+	originalConfig := batchConfigFile
+	originalProfiles := batchProfiles
+	originalFormat := outputFormat
+	defer func() {
+		batchConfigFile = originalConfig
+		batchProfiles = originalProfiles
+		outputFormat = originalFormat
+	}()
+
+	batchConfigFile = configPath
+	batchProfiles = ""
+	outputFormat = "text"
+
+	err := runBatch(batchCmd, []string{})
+	if err != nil {
+		t.Errorf("runBatch with config file error: %v", err)
+	}
+}
+
+func TestRunBatch_SVGFile(t *testing.T) {
+	profile := testutil.ProfileWithWorkers(2)
+	path := testutil.TempProfileFile(t, profile)
+
+	tmpDir := t.TempDir()
+	svgPath := tmpDir + "/chart.svg"
+
+	originalConfig := batchConfigFile
+	originalProfiles := batchProfiles
+	originalFormat := outputFormat
+	originalSVGPath := batchSVGPath
+	defer func() {
+		batchConfigFile = originalConfig
+		batchProfiles = originalProfiles
+		outputFormat = originalFormat
+		batchSVGPath = originalSVGPath
+	}()
+
+	batchConfigFile = ""
+	batchProfiles = path + ":2:Test"
+	outputFormat = "svg-file"
+	batchSVGPath = svgPath
+
+	err := runBatch(batchCmd, []string{})
+	if err != nil {
+		t.Errorf("runBatch svg-file format error: %v", err)
+	}
+
+	// Check file was created
+	if _, err := os.Stat(svgPath); os.IsNotExist(err) {
+		t.Error("expected SVG file to be created")
+	}
+}
+
+func TestRunBatch_InvalidInlineFormat(t *testing.T) {
+	originalConfig := batchConfigFile
+	originalProfiles := batchProfiles
+	defer func() {
+		batchConfigFile = originalConfig
+		batchProfiles = originalProfiles
+	}()
+
+	batchConfigFile = ""
+	batchProfiles = "invalid-format" // missing :workers:label
+
+	err := runBatch(batchCmd, []string{})
+	if err == nil {
+		t.Error("expected error for invalid inline format")
+	}
+}
+
+func TestRunBatch_InvalidConfigFile(t *testing.T) {
+	originalConfig := batchConfigFile
+	originalProfiles := batchProfiles
+	defer func() {
+		batchConfigFile = originalConfig
+		batchProfiles = originalProfiles
+	}()
+
+	batchConfigFile = "/nonexistent/config.yaml"
+	batchProfiles = ""
+
+	err := runBatch(batchCmd, []string{})
+	if err == nil {
+		t.Error("expected error for nonexistent config file")
+	}
+}
+
+// Scaling comparison tests
+func TestRunScaling_WithComparison(t *testing.T) {
+	profile1 := testutil.ProfileWithWorkers(2)
+	profile2 := testutil.ProfileWithWorkers(4)
+	path1 := testutil.TempProfileFile(t, profile1)
+	path2 := testutil.TempProfileFile(t, profile2)
+
+	originalPath := profilePath
+	originalBrowser := browserType
+	originalFormat := outputFormat
+	originalCompare := compareProfile
+	defer func() {
+		profilePath = originalPath
+		browserType = originalBrowser
+		outputFormat = originalFormat
+		compareProfile = originalCompare
+	}()
+
+	profilePath = path1
+	compareProfile = path2
+	browserType = "auto"
+
+	// Test text output
+	outputFormat = "text"
+	err := runScaling(scalingCmd, []string{})
+	if err != nil {
+		t.Errorf("runScaling comparison text format error: %v", err)
+	}
+
+	// Test markdown output
+	outputFormat = "markdown"
+	err = runScaling(scalingCmd, []string{})
+	if err != nil {
+		t.Errorf("runScaling comparison markdown format error: %v", err)
+	}
+
 	// Test JSON output
 	outputFormat = "json"
 	err = runScaling(scalingCmd, []string{})
@@ -1188,3 +2351,114 @@ func TestRunScaling_WithComparison(t *testing.T) {
 		t.Errorf("runScaling comparison json format error: %v", err)
 	}
 }
+
+func TestRootCmd_RangeFlags(t *testing.T) {
+	if rootCmd.PersistentFlags().Lookup("range") == nil {
+		t.Error("expected 'range' flag to be defined")
+	}
+	if rootCmd.PersistentFlags().Lookup("range-from-markers") == nil {
+		t.Error("expected 'range-from-markers' flag to be defined")
+	}
+}
+
+func TestApplyTimeRange(t *testing.T) {
+	originalRange := timeRange
+	originalMarkerRange := markerTimeRange
+	defer func() {
+		timeRange = originalRange
+		markerTimeRange = originalMarkerRange
+	}()
+
+	profile := testutil.ProfileWithDelimiters()
+
+	// No range selected returns the profile unchanged
+	timeRange = ""
+	markerTimeRange = ""
+	result, err := applyTimeRange(profile)
+	if err != nil {
+		t.Fatalf("applyTimeRange error: %v", err)
+	}
+	if result != profile {
+		t.Error("expected unchanged profile without a range")
+	}
+
+	// Explicit time range
+	timeRange = "150ms-300ms"
+	result, err = applyTimeRange(profile)
+	if err != nil {
+		t.Fatalf("applyTimeRange error: %v", err)
+	}
+	if result.Duration() != 150 {
+		t.Errorf("Duration() = %v, want 150", result.Duration())
+	}
+
+	// Marker range
+	timeRange = ""
+	markerTimeRange = "DOMEvent:click..Paint"
+	result, err = applyTimeRange(profile)
+	if err != nil {
+		t.Fatalf("applyTimeRange error: %v", err)
+	}
+	if result.Duration() != 80 {
+		t.Errorf("Duration() = %v, want 80", result.Duration())
+	}
+
+	// Both at once is rejected
+	timeRange = "150ms-300ms"
+	if _, err := applyTimeRange(profile); err == nil {
+		t.Error("expected error when both ranges are set")
+	}
+
+	// Invalid range
+	timeRange = "bogus"
+	markerTimeRange = ""
+	if _, err := applyTimeRange(profile); err == nil {
+		t.Error("expected error for invalid range")
+	}
+
+	// A range outside the capture is empty
+	timeRange = "90000ms-95000ms"
+	_, err = applyTimeRange(profile)
+	testutil.AssertErrorContains(t, err, "does not overlap the profile")
+}
+
+func TestRootCmd_ThreadsFlag(t *testing.T) {
+	if rootCmd.PersistentFlags().Lookup("threads") == nil {
+		t.Error("expected 'threads' flag to be defined")
+	}
+}
+
+func TestApplyThreadFilter(t *testing.T) {
+	original := threadFilter
+	defer func() { threadFilter = original }()
+
+	profile := testutil.ProfileWithWorkers(2)
+
+	threadFilter = ""
+	result, err := applyThreadFilter(profile)
+	if err != nil {
+		t.Fatalf("applyThreadFilter error: %v", err)
+	}
+	if result != profile {
+		t.Error("expected unchanged profile without a selector")
+	}
+
+	threadFilter = "!main"
+	result, err = applyThreadFilter(profile)
+	if err != nil {
+		t.Fatalf("applyThreadFilter error: %v", err)
+	}
+	if len(result.Threads) != 2 {
+		t.Errorf("len(Threads) = %d, want 2", len(result.Threads))
+	}
+
+	threadFilter = "Compositor"
+	if _, err := applyThreadFilter(profile); err == nil {
+		t.Error("expected error when no threads match")
+	}
+
+	threadFilter = "bogus:value"
+	if _, err := applyThreadFilter(profile); err == nil {
+		t.Error("expected error for invalid selector")
+	}
+}
@@ -0,0 +1,359 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/CedricHerzog/perfowl/internal/analyzer"
+	"github.com/CedricHerzog/perfowl/internal/parser"
+	"github.com/CedricHerzog/perfowl/internal/report"
+	"github.com/spf13/cobra"
+)
+
+var (
+	compareBaselines  []string
+	compareCandidates []string
+	compareStart      string
+	compareEnd        string
+	compareFindLast   bool
+	compareAlpha      float64
+	compareLimit      int
+)
+
+var compareCmd = &cobra.Command{
+	Use:   "compare",
+	Short: "Compare repeated baseline and candidate runs with significance tests",
+	Long: `Compares N baseline profiles against M candidate profiles. Each metric is tested
+with a two-sided Mann-Whitney U test instead of a fixed percentage threshold:
+- Duration, GC time and long task count
+- Operation time between two marker patterns (--start and --end)
+- Self time of the heaviest functions
+
+For every metric the report shows the medians, the change of the median, the
+p-value, Cliff's delta as the effect size and a verdict: improved, regressed,
+unchanged or inconclusive. The p-values of all metrics and functions are
+adjusted together with Holm-Bonferroni, and the verdict uses the adjusted
+p-value. Inconclusive means there are too few runs for any difference to
+reach significance after that correction; with the default metrics and
+functions at alpha 0.05 that takes at least 6 runs per side.
+
+With exactly one baseline and one candidate and no --start/--end there is
+nothing to test, so the pair is diffed instead, as the MCP compare_profiles
+tool does: duration, samples, GC, sync IPC, long tasks and layouts, each
+judged against a fixed threshold.
+
+Example:
+  perfowl compare --baseline before.json.gz --candidate after.json.gz
+  perfowl compare --baseline base1.json.gz,base2.json.gz,base3.json.gz,base4.json.gz \
+    --candidate cand1.json.gz,cand2.json.gz,cand3.json.gz,cand4.json.gz
+  perfowl compare --baseline base1.json.gz --baseline base2.json.gz \
+    --candidate cand1.json.gz --candidate cand2.json.gz -s "startDecrypt" -e "endDecrypt"`,
+	RunE: runCompare,
+}
+
+func init() {
+	rootCmd.AddCommand(compareCmd)
+	compareCmd.Flags().StringSliceVar(&compareBaselines, "baseline", nil, "Baseline profile paths (comma-separated or repeated)")
+	compareCmd.Flags().StringSliceVar(&compareCandidates, "candidate", nil, "Candidate profile paths (comma-separated or repeated)")
+	compareCmd.Flags().StringVarP(&compareStart, "start", "s", "", "Pattern for the operation's start marker")
+	compareCmd.Flags().StringVarP(&compareEnd, "end", "e", "", "Pattern for the operation's end marker")
+	compareCmd.Flags().BoolVarP(&compareFindLast, "find-last", "L", false, "Find the last matching end marker instead of first")
+	compareCmd.Flags().Float64Var(&compareAlpha, "alpha", analyzer.SignificanceDefaultAlpha, "Significance level")
+	compareCmd.Flags().IntVarP(&compareLimit, "limit", "l", analyzer.CompareSetDefaultFunctionLimit, "Maximum number of functions to compare")
+}
+
+func runCompare(cmd *cobra.Command, args []string) error {
+	if len(compareBaselines) == 0 || len(compareCandidates) == 0 {
+		return fmt.Errorf("baseline and candidate profiles are required (use --baseline and --candidate)")
+	}
+	if compareAlpha <= 0 || compareAlpha >= 1 {
+		return fmt.Errorf("--alpha must be between 0 and 1")
+	}
+
+	baselines, err := loadProfiles(compareBaselines)
+	if err != nil {
+		return err
+	}
+	candidates, err := loadProfiles(compareCandidates)
+	if err != nil {
+		return err
+	}
+
+	if len(baselines) == 1 && len(candidates) == 1 && compareStart == "" && compareEnd == "" {
+		return outputProfileDiff(report.Compare(baselines[0], candidates[0]))
+	}
+
+	comparison := analyzer.CompareProfileSets(baselines, candidates, analyzer.CompareSetOptions{
+		Operation: analyzer.MeasureOptions{
+			StartPattern: compareStart,
+			EndPattern:   compareEnd,
+			FindLast:     compareFindLast,
+		},
+		Alpha:         compareAlpha,
+		FunctionLimit: compareLimit,
+	})
+
+	switch outputFormat {
+	case "json":
+		return outputCompareJSON(comparison)
+	case "markdown":
+		return outputCompareMarkdown(comparison)
+	default:
+		return outputCompareText(comparison)
+	}
+}
+
+// loadProfiles loads and scopes each profile in turn
+func loadProfiles(paths []string) ([]*parser.Profile, error) {
+	profiles := make([]*parser.Profile, 0, len(paths))
+	for _, path := range paths {
+		profile, _, err := loadProfile(path)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		profiles = append(profiles, profile)
+	}
+	return profiles, nil
+}
+
+func outputCompareJSON(comparison analyzer.ProfileSetComparison) error {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(comparison)
+}
+
+// verdictIcon marks a verdict in markdown tables
+func verdictIcon(verdict string) string {
+	switch verdict {
+	case analyzer.VerdictImproved:
+		return "✅ improved"
+	case analyzer.VerdictRegressed:
+		return "🔴 regressed"
+	case analyzer.VerdictInconclusive:
+		return "❔ inconclusive"
+	default:
+		return "➖ unchanged"
+	}
+}
+
+func writeCompareTable(md *strings.Builder, metrics []analyzer.MetricComparison) {
+	md.WriteString("| Metric | Baseline | Candidate | Change | p-value | Adjusted p | Effect | Verdict |\n")
+	md.WriteString("|--------|----------|-----------|--------|---------|------------|--------|---------|\n")
+	for _, m := range metrics {
+		md.WriteString(fmt.Sprintf("| %s | %.2f | %.2f | %+.1f%% | %.3f | %.3f | %.2f (%s) | %s |\n",
+			m.Metric, m.BaselineMedian, m.CandidateMedian, m.ChangePercent, m.PValue, m.AdjustedPValue, m.EffectSize, m.EffectMagnitude, verdictIcon(m.Verdict)))
+	}
+}
+
+func outputCompareMarkdown(comparison analyzer.ProfileSetComparison) error {
+	md := strings.Builder{}
+
+	md.WriteString("# Profile Comparison\n\n")
+
+	md.WriteString("## Summary\n\n")
+	md.WriteString(fmt.Sprintf("- **Baseline Runs**: %d\n", comparison.BaselineRuns))
+	md.WriteString(fmt.Sprintf("- **Candidate Runs**: %d\n", comparison.CandidateRuns))
+	md.WriteString(fmt.Sprintf("- **Test**: Mann-Whitney U, alpha %.2f, Holm-Bonferroni adjusted\n", comparison.Alpha))
+	md.WriteString(fmt.Sprintf("- **Improved**: %d\n", len(comparison.Improved)))
+	md.WriteString(fmt.Sprintf("- **Regressed**: %d\n", len(comparison.Regressed)))
+
+	if len(comparison.Metrics) > 0 {
+		md.WriteString("\n## Metrics\n\n")
+		md.WriteString("Medians across runs; effect is Cliff's delta (negative means the candidate is lower).\n\n")
+		writeCompareTable(&md, comparison.Metrics)
+	}
+
+	if len(comparison.Functions) > 0 {
+		md.WriteString("\n## Function Self Time (ms)\n\n")
+		writeCompareTable(&md, comparison.Functions)
+	}
+
+	if len(comparison.Regressed) > 0 {
+		md.WriteString("\n## Regressions\n\n")
+		for _, r := range comparison.Regressed {
+			md.WriteString(fmt.Sprintf("- 🔴 %s\n", r))
+		}
+	}
+
+	if len(comparison.Improved) > 0 {
+		md.WriteString("\n## Improvements\n\n")
+		for _, r := range comparison.Improved {
+			md.WriteString(fmt.Sprintf("- ✅ %s\n", r))
+		}
+	}
+
+	if len(comparison.Recommendations) > 0 {
+		md.WriteString("\n## Recommendations\n\n")
+		for _, r := range comparison.Recommendations {
+			md.WriteString(fmt.Sprintf("- 💡 %s\n", r))
+		}
+	}
+
+	fmt.Print(md.String())
+	return nil
+}
+
+func printCompareRows(metrics []analyzer.MetricComparison) {
+	fmt.Printf("  %-30s %12s %12s %9s %8s %8s %7s  %s\n", "Metric", "Baseline", "Candidate", "Change", "p", "adj. p", "Effect", "Verdict")
+	for _, m := range metrics {
+		fmt.Printf("  %-30s %12.2f %12.2f %+8.1f%% %8.3f %8.3f %+7.2f  %s\n",
+			truncateName(m.Metric, 30), m.BaselineMedian, m.CandidateMedian, m.ChangePercent, m.PValue, m.AdjustedPValue, m.EffectSize, m.Verdict)
+	}
+}
+
+func outputCompareText(comparison analyzer.ProfileSetComparison) error {
+	fmt.Println("Profile Comparison")
+	fmt.Println(strings.Repeat("=", 60))
+	fmt.Println()
+
+	fmt.Printf("Baseline Runs: %d\n", comparison.BaselineRuns)
+	fmt.Printf("Candidate Runs: %d\n", comparison.CandidateRuns)
+	fmt.Printf("Test: Mann-Whitney U, alpha %.2f, Holm-Bonferroni adjusted\n", comparison.Alpha)
+	fmt.Println()
+
+	if len(comparison.Metrics) > 0 {
+		fmt.Println("Metrics (medians):")
+		fmt.Println(strings.Repeat("-", 60))
+		printCompareRows(comparison.Metrics)
+		fmt.Println()
+	}
+
+	if len(comparison.Functions) > 0 {
+		fmt.Println("Function Self Time (ms, medians):")
+		fmt.Println(strings.Repeat("-", 60))
+		printCompareRows(comparison.Functions)
+		fmt.Println()
+	}
+
+	if len(comparison.Regressed) > 0 {
+		fmt.Println("Regressions:")
+		fmt.Println(strings.Repeat("-", 60))
+		for _, r := range comparison.Regressed {
+			fmt.Printf("  - %s\n", r)
+		}
+		fmt.Println()
+	}
+
+	if len(comparison.Improved) > 0 {
+		fmt.Println("Improvements:")
+		fmt.Println(strings.Repeat("-", 60))
+		for _, r := range comparison.Improved {
+			fmt.Printf("  - %s\n", r)
+		}
+		fmt.Println()
+	}
+
+	if len(comparison.Recommendations) > 0 {
+		fmt.Println("Recommendations:")
+		fmt.Println(strings.Repeat("-", 60))
+		for _, r := range comparison.Recommendations {
+			fmt.Printf("  - %s\n", r)
+		}
+	}
+
+	return nil
+}
+
+// outputProfileDiff writes the diff of a single baseline and candidate pair
+func outputProfileDiff(diff analyzer.ProfileDiff) error {
+	switch outputFormat {
+	case "json":
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(diff)
+	case "markdown":
+		return outputProfileDiffMarkdown(diff)
+	default:
+		return outputProfileDiffText(diff)
+	}
+}
+
+// profileDiffRow is one metric of a single-pair diff
+type profileDiffRow struct {
+	metric    string
+	baseline  float64
+	candidate float64
+	decimals  int
+}
+
+func profileDiffRows(diff analyzer.ProfileDiff) []profileDiffRow {
+	b, c := diff.Baseline, diff.Comparison
+	return []profileDiffRow{
+		{"Duration (ms)", b.DurationMs, c.DurationMs, 2},
+		{"Samples", float64(b.TotalSamples), float64(c.TotalSamples), 0},
+		{"Threads", float64(b.ThreadCount), float64(c.ThreadCount), 0},
+		{"GC time (ms)", b.GCTotalTimeMs, c.GCTotalTimeMs, 2},
+		{"Major GCs", float64(b.GCMajorCount), float64(c.GCMajorCount), 0},
+		{"Minor GCs", float64(b.GCMinorCount), float64(c.GCMinorCount), 0},
+		{"Sync IPC", float64(b.SyncIPCCount), float64(c.SyncIPCCount), 0},
+		{"Long tasks", float64(b.LongTaskCount), float64(c.LongTaskCount), 0},
+		{"Layouts", float64(b.LayoutCount), float64(c.LayoutCount), 0},
+	}
+}
+
+func outputProfileDiffMarkdown(diff analyzer.ProfileDiff) error {
+	md := strings.Builder{}
+
+	md.WriteString("# Profile Comparison\n\n")
+
+	md.WriteString("## Metrics\n\n")
+	md.WriteString("A single run per side; changes are judged against fixed thresholds.\n\n")
+	md.WriteString("| Metric | Baseline | Candidate | Change |\n")
+	md.WriteString("|--------|----------|-----------|--------|\n")
+	for _, r := range profileDiffRows(diff) {
+		md.WriteString(fmt.Sprintf("| %s | %.*f | %.*f | %+.*f |\n",
+			r.metric, r.decimals, r.baseline, r.decimals, r.candidate, r.decimals, r.candidate-r.baseline))
+	}
+
+	if len(diff.Regressed) > 0 {
+		md.WriteString("\n## Regressions\n\n")
+		for _, r := range diff.Regressed {
+			md.WriteString(fmt.Sprintf("- 🔴 %s\n", r))
+		}
+	}
+
+	if len(diff.Improved) > 0 {
+		md.WriteString("\n## Improvements\n\n")
+		for _, r := range diff.Improved {
+			md.WriteString(fmt.Sprintf("- ✅ %s\n", r))
+		}
+	}
+
+	fmt.Print(md.String())
+	return nil
+}
+
+func outputProfileDiffText(diff analyzer.ProfileDiff) error {
+	fmt.Println("Profile Comparison")
+	fmt.Println(strings.Repeat("=", 60))
+	fmt.Println()
+
+	fmt.Printf("  %-20s %12s %12s %12s\n", "Metric", "Baseline", "Candidate", "Change")
+	fmt.Println(strings.Repeat("-", 60))
+	for _, r := range profileDiffRows(diff) {
+		fmt.Printf("  %-20s %12.*f %12.*f %+12.*f\n",
+			r.metric, r.decimals, r.baseline, r.decimals, r.candidate, r.decimals, r.candidate-r.baseline)
+	}
+	fmt.Println()
+
+	if len(diff.Regressed) > 0 {
+		fmt.Println("Regressions:")
+		fmt.Println(strings.Repeat("-", 60))
+		for _, r := range diff.Regressed {
+			fmt.Printf("  - %s\n", r)
+		}
+		fmt.Println()
+	}
+
+	if len(diff.Improved) > 0 {
+		fmt.Println("Improvements:")
+		fmt.Println(strings.Repeat("-", 60))
+		for _, r := range diff.Improved {
+			fmt.Printf("  - %s\n", r)
+		}
+	}
+
+	return nil
+}
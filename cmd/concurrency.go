@@ -0,0 +1,176 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/CedricHerzog/perfowl/internal/analyzer"
+	"github.com/CedricHerzog/perfowl/internal/chart"
+	"github.com/spf13/cobra"
+)
+
+var concurrencyBucketMs float64
+
+var concurrencyCmd = &cobra.Command{
+	Use:   "concurrency",
+	Short: "Analyze how many threads run in parallel over time",
+	Long: `Builds a timeline of running threads from sample timestamps and per-sample
+CPU deltas including:
+- Average parallelism and peak concurrency
+- Time spent with 0, 1, 2, ... threads running
+- Serial phases where only the main thread was running
+- Per-thread busy time and load imbalance across workers
+
+Use -o svg to print a stacked-area chart instead.
+
+Example:
+  perfowl concurrency --profile profile.json.gz
+  perfowl concurrency --profile profile.json.gz --bucket 5 -o svg > concurrency.svg`,
+	RunE: runConcurrency,
+}
+
+func init() {
+	rootCmd.AddCommand(concurrencyCmd)
+	concurrencyCmd.Flags().Float64Var(&concurrencyBucketMs, "bucket", 0, "Timeline resolution in ms (default: the profile sampling interval)")
+}
+
+func runConcurrency(cmd *cobra.Command, args []string) error {
+	if profilePath == "" {
+		return fmt.Errorf("profile path is required (use --profile or -p)")
+	}
+
+	profile, _, err := loadProfile(profilePath)
+	if err != nil {
+		return err
+	}
+
+	analysis := analyzer.AnalyzeConcurrency(profile, concurrencyBucketMs)
+
+	switch outputFormat {
+	case "json":
+		return outputConcurrencyJSON(analysis)
+	case "markdown":
+		return outputConcurrencyMarkdown(analysis)
+	case "svg":
+		fmt.Println(chart.GenerateConcurrencyChart(analysis))
+		return nil
+	default:
+		return outputConcurrencyText(analysis)
+	}
+}
+
+func outputConcurrencyJSON(analysis analyzer.ConcurrencyAnalysis) error {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(analysis)
+}
+
+// formatThreadLabel names a thread with its TID when it has one
+func formatThreadLabel(t analyzer.ConcurrencyThread) string {
+	if t.TID == "" {
+		return t.Name
+	}
+	return fmt.Sprintf("%s (%s)", t.Name, t.TID)
+}
+
+func outputConcurrencyMarkdown(analysis analyzer.ConcurrencyAnalysis) error {
+	md := strings.Builder{}
+
+	md.WriteString("# Concurrency Analysis\n\n")
+
+	md.WriteString("## Summary\n\n")
+	md.WriteString(fmt.Sprintf("- **Span**: %.2fms - %.2fms (%.2fms buckets)\n", analysis.StartTime, analysis.EndTime, analysis.BucketMs))
+	md.WriteString(fmt.Sprintf("- **Threads**: %d (%d workers)\n", analysis.ThreadCount, analysis.WorkerCount))
+	md.WriteString(fmt.Sprintf("- **Average Parallelism**: %.2f\n", analysis.AverageParallelism))
+	md.WriteString(fmt.Sprintf("- **Peak Concurrency**: %d\n", analysis.PeakConcurrency))
+	md.WriteString(fmt.Sprintf("- **Serial Time**: %.2fms (%.1f%%)\n", analysis.SerialTimeMs, analysis.SerialPercent))
+	md.WriteString(fmt.Sprintf("- **Worker Load Imbalance**: %.1f%%\n", analysis.LoadImbalance*100))
+
+	if len(analysis.Levels) > 0 {
+		md.WriteString("\n## Time per Concurrency Level\n\n")
+		md.WriteString("| Running Threads | Time | % |\n")
+		md.WriteString("|-----------------|------|---|\n")
+		for _, l := range analysis.Levels {
+			md.WriteString(fmt.Sprintf("| %d | %.2fms | %.1f%% |\n", l.Level, l.DurationMs, l.Percent))
+		}
+	}
+
+	if len(analysis.Threads) > 0 {
+		md.WriteString("\n## Threads\n\n")
+		md.WriteString("| Thread | Busy | Utilization |\n")
+		md.WriteString("|--------|------|-------------|\n")
+		for _, t := range analysis.Threads {
+			md.WriteString(fmt.Sprintf("| %s | %.2fms | %.1f%% |\n", formatThreadLabel(t), t.BusyMs, t.Utilization))
+		}
+	}
+
+	if len(analysis.SerialPhases) > 0 {
+		md.WriteString("\n## Serial Phases\n\n")
+		for _, p := range analysis.SerialPhases {
+			md.WriteString(fmt.Sprintf("- 🔶 %.2fms - %.2fms (%.2fms)\n", p.StartTime, p.EndTime, p.DurationMs))
+		}
+	}
+
+	if len(analysis.Recommendations) > 0 {
+		md.WriteString("\n## Recommendations\n\n")
+		for _, r := range analysis.Recommendations {
+			md.WriteString(fmt.Sprintf("- 💡 %s\n", r))
+		}
+	}
+
+	fmt.Print(md.String())
+	return nil
+}
+
+func outputConcurrencyText(analysis analyzer.ConcurrencyAnalysis) error {
+	fmt.Println("Concurrency Analysis")
+	fmt.Println(strings.Repeat("=", 60))
+	fmt.Println()
+
+	fmt.Printf("Span: %.2fms - %.2fms (%.2fms buckets)\n", analysis.StartTime, analysis.EndTime, analysis.BucketMs)
+	fmt.Printf("Threads: %d (%d workers)\n", analysis.ThreadCount, analysis.WorkerCount)
+	fmt.Printf("Average Parallelism: %.2f\n", analysis.AverageParallelism)
+	fmt.Printf("Peak Concurrency: %d\n", analysis.PeakConcurrency)
+	fmt.Printf("Serial Time: %.2fms (%.1f%%)\n", analysis.SerialTimeMs, analysis.SerialPercent)
+	fmt.Printf("Worker Load Imbalance: %.1f%%\n", analysis.LoadImbalance*100)
+	fmt.Println()
+
+	if len(analysis.Levels) > 0 {
+		fmt.Println("Time per Concurrency Level:")
+		fmt.Println(strings.Repeat("-", 60))
+		for _, l := range analysis.Levels {
+			fmt.Printf("  %2d running: %10.2fms (%5.1f%%)\n", l.Level, l.DurationMs, l.Percent)
+		}
+		fmt.Println()
+	}
+
+	if len(analysis.Threads) > 0 {
+		fmt.Println("Threads:")
+		fmt.Println(strings.Repeat("-", 60))
+		for _, t := range analysis.Threads {
+			fmt.Printf("  %-35s %10.2fms %6.1f%%\n", truncateName(formatThreadLabel(t), 35), t.BusyMs, t.Utilization)
+		}
+		fmt.Println()
+	}
+
+	if len(analysis.SerialPhases) > 0 {
+		fmt.Println("Serial Phases (main thread only):")
+		fmt.Println(strings.Repeat("-", 60))
+		for _, p := range analysis.SerialPhases {
+			fmt.Printf("  %.2fms - %.2fms (%.2fms)\n", p.StartTime, p.EndTime, p.DurationMs)
+		}
+		fmt.Println()
+	}
+
+	if len(analysis.Recommendations) > 0 {
+		fmt.Println("Recommendations:")
+		fmt.Println(strings.Repeat("-", 60))
+		for _, r := range analysis.Recommendations {
+			fmt.Printf("  - %s\n", r)
+		}
+	}
+
+	return nil
+}
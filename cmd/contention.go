@@ -7,7 +7,6 @@ import (
 	"strings"
 
 	"github.com/CedricHerzog/perfowl/internal/analyzer"
-	"github.com/CedricHerzog/perfowl/internal/parser"
 	"github.com/spf13/cobra"
 )
 
@@ -22,7 +21,9 @@ var contentionCmd = &cobra.Command{
 Provides severity assessment and recommendations for:
 - Reducing GC pressure
 - Converting sync IPC to async
-- Optimizing shared resource access`,
+- Optimizing shared resource access
+
+Use -o junit or -o sarif to report each kind of contention for CI.`,
 	RunE: runContention,
 }
 
@@ -35,10 +36,9 @@ func runContention(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("profile path is required (use --profile or -p)")
 	}
 
-	bt := parser.ParseBrowserType(browserType)
-	profile, _, err := parser.LoadProfileWithType(profilePath, bt)
+	profile, _, err := loadProfile(profilePath)
 	if err != nil {
-		return fmt.Errorf("failed to load profile: %w", err)
+		return err
 	}
 
 	analysis := analyzer.AnalyzeContention(profile)
@@ -46,6 +46,8 @@ func runContention(cmd *cobra.Command, args []string) error {
 	switch outputFormat {
 	case "json":
 		return outputContentionJSON(analysis)
+	case "junit", "sarif":
+		return outputFindings("contention", analyzer.ContentionFindings(analysis, profile))
 	case "markdown":
 		return outputContentionMarkdown(analysis)
 	default:
@@ -0,0 +1,181 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/CedricHerzog/perfowl/internal/analyzer"
+	"github.com/spf13/cobra"
+)
+
+var (
+	criticalPathStart    string
+	criticalPathEnd      string
+	criticalPathFindLast bool
+)
+
+var criticalPathCmd = &cobra.Command{
+	Use:   "critical-path",
+	Short: "Find what determined the time between two markers across threads",
+	Long: `Computes the cross-thread critical path between a start and an end marker.
+Starting from the end marker's thread it walks backwards, following postMessage
+deliveries, Chrome flow events and IPC messages whenever the thread was idle
+waiting on them. Reports:
+- The thread segments and message hops on the critical path with the time each contributes
+- Per-thread contribution and CPU time in the window
+- Slack: how much later off-path workers could have finished without delaying the end
+
+Markers are matched with the same patterns as the measure command.
+
+Example:
+  perfowl critical-path -p profile.json.gz \
+    --start "UserTiming:decrypt-start" \
+    --end "UserTiming:decrypt-end"`,
+	RunE: runCriticalPath,
+}
+
+func init() {
+	rootCmd.AddCommand(criticalPathCmd)
+	criticalPathCmd.Flags().StringVarP(&criticalPathStart, "start", "s", "", "Pattern for start marker (required)")
+	criticalPathCmd.Flags().StringVarP(&criticalPathEnd, "end", "e", "", "Pattern for end marker (required)")
+	criticalPathCmd.Flags().BoolVarP(&criticalPathFindLast, "find-last", "L", false, "Find the last matching end marker instead of first")
+	_ = criticalPathCmd.MarkFlagRequired("start")
+	_ = criticalPathCmd.MarkFlagRequired("end")
+}
+
+func runCriticalPath(cmd *cobra.Command, args []string) error {
+	if profilePath == "" {
+		return fmt.Errorf("profile path is required (use --profile or -p)")
+	}
+
+	profile, _, err := loadProfile(profilePath)
+	if err != nil {
+		return err
+	}
+
+	analysis, err := analyzer.AnalyzeCriticalPath(profile, analyzer.MeasureOptions{
+		StartPattern: criticalPathStart,
+		EndPattern:   criticalPathEnd,
+		FindLast:     criticalPathFindLast,
+	})
+	if err != nil {
+		return fmt.Errorf("critical path failed: %w", err)
+	}
+
+	switch outputFormat {
+	case "json":
+		return outputCriticalPathJSON(analysis)
+	case "markdown":
+		return outputCriticalPathMarkdown(analysis)
+	default:
+		return outputCriticalPathText(analysis)
+	}
+}
+
+func outputCriticalPathJSON(analysis *analyzer.CriticalPathAnalysis) error {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(analysis)
+}
+
+func outputCriticalPathMarkdown(analysis *analyzer.CriticalPathAnalysis) error {
+	md := strings.Builder{}
+
+	md.WriteString("# Critical Path Analysis\n\n")
+
+	md.WriteString("## Summary\n\n")
+	md.WriteString(fmt.Sprintf("- **Start**: %s at %.2fms (%s)\n", analysis.StartMarker.Name, analysis.StartMarker.TimeMs, analysis.StartMarker.Thread))
+	md.WriteString(fmt.Sprintf("- **End**: %s at %.2fms (%s)\n", analysis.EndMarker.Name, analysis.EndMarker.TimeMs, analysis.EndMarker.Thread))
+	md.WriteString(fmt.Sprintf("- **End-to-End Time**: %.2fms\n", analysis.TotalMs))
+	md.WriteString(fmt.Sprintf("- **Cross-Thread Dependencies**: %d\n", analysis.Dependencies))
+
+	if len(analysis.Segments) > 0 {
+		md.WriteString("\n## Critical Path\n\n")
+		md.WriteString("| Start | End | Duration | % | Segment | CPU |\n")
+		md.WriteString("|-------|-----|----------|---|---------|-----|\n")
+		for _, s := range analysis.Segments {
+			segment := s.Thread
+			cpu := fmt.Sprintf("%.2fms", s.BusyMs)
+			if s.Kind == analyzer.CriticalPathMessage {
+				segment = fmt.Sprintf("✉️ %s (%s)", s.Thread, s.Via)
+				cpu = "-"
+			}
+			md.WriteString(fmt.Sprintf("| %.2fms | %.2fms | %.2fms | %.1f%% | %s | %s |\n",
+				s.StartTime, s.EndTime, s.DurationMs, s.Percent, segment, cpu))
+		}
+	}
+
+	if len(analysis.Threads) > 0 {
+		md.WriteString("\n## Threads\n\n")
+		md.WriteString("| Thread | On Path | Contribution | CPU | Finished | Slack |\n")
+		md.WriteString("|--------|---------|--------------|-----|----------|-------|\n")
+		for _, t := range analysis.Threads {
+			onPath := ""
+			if t.OnCriticalPath {
+				onPath = "🔶"
+			}
+			md.WriteString(fmt.Sprintf("| %s | %s | %.2fms (%.1f%%) | %.2fms | %.2fms | %.2fms |\n",
+				t.Thread, onPath, t.ContributionMs, t.Percent, t.BusyMs, t.FinishTime, t.SlackMs))
+		}
+	}
+
+	if len(analysis.Recommendations) > 0 {
+		md.WriteString("\n## Recommendations\n\n")
+		for _, r := range analysis.Recommendations {
+			md.WriteString(fmt.Sprintf("- 💡 %s\n", r))
+		}
+	}
+
+	fmt.Print(md.String())
+	return nil
+}
+
+func outputCriticalPathText(analysis *analyzer.CriticalPathAnalysis) error {
+	fmt.Println("Critical Path Analysis")
+	fmt.Println(strings.Repeat("=", 60))
+	fmt.Println()
+
+	fmt.Printf("Start: %s at %.2fms (%s)\n", analysis.StartMarker.Name, analysis.StartMarker.TimeMs, analysis.StartMarker.Thread)
+	fmt.Printf("End: %s at %.2fms (%s)\n", analysis.EndMarker.Name, analysis.EndMarker.TimeMs, analysis.EndMarker.Thread)
+	fmt.Printf("End-to-End Time: %.2fms\n", analysis.TotalMs)
+	fmt.Printf("Cross-Thread Dependencies: %d\n", analysis.Dependencies)
+	fmt.Println()
+
+	if len(analysis.Segments) > 0 {
+		fmt.Println("Critical Path:")
+		fmt.Println(strings.Repeat("-", 60))
+		for _, s := range analysis.Segments {
+			if s.Kind == analyzer.CriticalPathMessage {
+				fmt.Printf("  %9.2fms  %8.2fms %5.1f%%    -> %s (%s)\n", s.StartTime, s.DurationMs, s.Percent, s.Thread, s.Via)
+				continue
+			}
+			fmt.Printf("  %9.2fms  %8.2fms %5.1f%%  %s (cpu %.2fms)\n", s.StartTime, s.DurationMs, s.Percent, truncateName(s.Thread, 30), s.BusyMs)
+		}
+		fmt.Println()
+	}
+
+	if len(analysis.Threads) > 0 {
+		fmt.Println("Threads:")
+		fmt.Println(strings.Repeat("-", 60))
+		for _, t := range analysis.Threads {
+			marker := " "
+			if t.OnCriticalPath {
+				marker = "*"
+			}
+			fmt.Printf("  %s %-30s path %8.2fms  cpu %8.2fms  slack %8.2fms\n", marker, truncateName(t.Thread, 30), t.ContributionMs, t.BusyMs, t.SlackMs)
+		}
+		fmt.Println()
+	}
+
+	if len(analysis.Recommendations) > 0 {
+		fmt.Println("Recommendations:")
+		fmt.Println(strings.Repeat("-", 60))
+		for _, r := range analysis.Recommendations {
+			fmt.Printf("  - %s\n", r)
+		}
+	}
+
+	return nil
+}
@@ -7,7 +7,6 @@ import (
 	"strings"
 
 	"github.com/CedricHerzog/perfowl/internal/analyzer"
-	"github.com/CedricHerzog/perfowl/internal/parser"
 	"github.com/spf13/cobra"
 )
 
@@ -22,7 +21,9 @@ var cryptoCmd = &cobra.Command{
 Identifies potential issues like:
 - Serialized crypto operations that could be parallelized
 - Usage of weak algorithms (MD5, SHA-1)
-- Excessive crypto overhead`,
+- Excessive crypto overhead
+
+Use -o junit or -o sarif to report each warning for CI.`,
 	RunE: runCrypto,
 }
 
@@ -35,10 +36,9 @@ func runCrypto(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("profile path is required (use --profile or -p)")
 	}
 
-	bt := parser.ParseBrowserType(browserType)
-	profile, _, err := parser.LoadProfileWithType(profilePath, bt)
+	profile, _, err := loadProfile(profilePath)
 	if err != nil {
-		return fmt.Errorf("failed to load profile: %w", err)
+		return err
 	}
 
 	analysis := analyzer.AnalyzeCrypto(profile)
@@ -46,6 +46,8 @@ func runCrypto(cmd *cobra.Command, args []string) error {
 	switch outputFormat {
 	case "json":
 		return outputCryptoJSON(analysis)
+	case "junit", "sarif":
+		return outputFindings("crypto", analyzer.WarningFindings("crypto", analysis.Warnings, profile))
 	case "markdown":
 		return outputCryptoMarkdown(analysis)
 	default:
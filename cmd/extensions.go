@@ -4,11 +4,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
-	"sort"
 	"strings"
 
 	"github.com/CedricHerzog/perfowl/internal/analyzer"
-	"github.com/CedricHerzog/perfowl/internal/parser"
 	"github.com/spf13/cobra"
 )
 
@@ -22,7 +20,9 @@ var extensionsCmd = &cobra.Command{
 	Long: `Analyzes the performance impact of browser extensions in the profile.
 
 Shows metrics for each extension including:
-- Total time spent in extension code
+- Sampled CPU time of stacks running extension code, per thread
+- Top extension functions by self time
+- Total duration of extension-related markers
 - Number of markers/events triggered
 - DOM events caused
 - IPC messages sent
@@ -40,10 +40,9 @@ func runExtensions(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("profile path is required (use --profile or -p)")
 	}
 
-	bt := parser.ParseBrowserType(browserType)
-	profile, _, err := parser.LoadProfileWithType(profilePath, bt)
+	profile, _, err := loadProfile(profilePath)
 	if err != nil {
-		return fmt.Errorf("failed to load profile: %w", err)
+		return err
 	}
 
 	// Analyze extensions
@@ -83,16 +82,14 @@ func outputExtensionsMarkdown(report analyzer.ExtensionsAnalysis) error {
 
 	md.WriteString("## Summary\n\n")
 	md.WriteString(fmt.Sprintf("- **Total Extensions**: %d\n", report.TotalExtensions))
+	md.WriteString(fmt.Sprintf("- **Combined CPU Time**: %.2f ms\n", report.TotalCPUTimeMs))
 	md.WriteString(fmt.Sprintf("- **Combined Duration**: %.2f ms\n", report.TotalDuration))
 	md.WriteString(fmt.Sprintf("- **Total Events**: %d\n", report.TotalEvents))
 
 	if len(report.Extensions) > 0 {
 		md.WriteString("\n## Extension Details\n\n")
 
-		// Sort by duration
-		sort.Slice(report.Extensions, func(i, j int) bool {
-			return report.Extensions[i].TotalDuration > report.Extensions[j].TotalDuration
-		})
+		analyzer.SortExtensionReports(report.Extensions)
 
 		for _, ext := range report.Extensions {
 			impactEmoji := map[string]string{
@@ -104,11 +101,26 @@ func outputExtensionsMarkdown(report analyzer.ExtensionsAnalysis) error {
 			md.WriteString(fmt.Sprintf("### %s %s\n\n", impactEmoji, ext.Name))
 			md.WriteString(fmt.Sprintf("- **ID**: `%s`\n", ext.ID))
 			md.WriteString(fmt.Sprintf("- **Impact**: %s\n", ext.ImpactScore))
+			md.WriteString(fmt.Sprintf("- **CPU Time**: %.2f ms (%d samples)\n", ext.CPUTimeMs, ext.SampleCount))
 			md.WriteString(fmt.Sprintf("- **Total Duration**: %.2f ms\n", ext.TotalDuration))
 			md.WriteString(fmt.Sprintf("- **Markers/Events**: %d\n", ext.MarkersCount))
 			md.WriteString(fmt.Sprintf("- **DOM Events**: %d\n", ext.DOMEvents))
 			md.WriteString(fmt.Sprintf("- **IPC Messages**: %d\n", ext.IPCMessages))
 
+			if len(ext.CPUByThread) > 0 {
+				md.WriteString("\n**CPU by Thread:**\n")
+				for _, tc := range ext.CPUByThread {
+					md.WriteString(fmt.Sprintf("- %s: %.2fms\n", tc.Thread, tc.CPUTimeMs))
+				}
+			}
+
+			if len(ext.TopFunctions) > 0 {
+				md.WriteString("\n**Top Functions:**\n")
+				for _, fn := range ext.TopFunctions {
+					md.WriteString(fmt.Sprintf("- `%s` (self %.2fms, total %.2fms)\n", fn.Name, fn.SelfTimeMs, fn.TotalTimeMs))
+				}
+			}
+
 			if len(ext.TopMarkers) > 0 {
 				md.WriteString("\n**Top Activity:**\n")
 				for _, marker := range ext.TopMarkers {
@@ -133,15 +145,13 @@ func outputExtensionsText(report analyzer.ExtensionsAnalysis) error {
 
 	fmt.Println("Summary:")
 	fmt.Printf("  Total Extensions: %d\n", report.TotalExtensions)
+	fmt.Printf("  Combined CPU Time: %.2f ms\n", report.TotalCPUTimeMs)
 	fmt.Printf("  Combined Duration: %.2f ms\n", report.TotalDuration)
 	fmt.Printf("  Total Events: %d\n", report.TotalEvents)
 	fmt.Println()
 
 	if len(report.Extensions) > 0 {
-		// Sort by duration
-		sort.Slice(report.Extensions, func(i, j int) bool {
-			return report.Extensions[i].TotalDuration > report.Extensions[j].TotalDuration
-		})
+		analyzer.SortExtensionReports(report.Extensions)
 
 		fmt.Println("Extension Details:")
 		fmt.Println(strings.Repeat("-", 50))
@@ -155,11 +165,26 @@ func outputExtensionsText(report analyzer.ExtensionsAnalysis) error {
 
 			fmt.Printf("\n%s %s\n", impactStr, ext.Name)
 			fmt.Printf("  ID:             %s\n", ext.ID)
+			fmt.Printf("  CPU Time:       %.2f ms (%d samples)\n", ext.CPUTimeMs, ext.SampleCount)
 			fmt.Printf("  Total Duration: %.2f ms\n", ext.TotalDuration)
 			fmt.Printf("  Markers/Events: %d\n", ext.MarkersCount)
 			fmt.Printf("  DOM Events:     %d\n", ext.DOMEvents)
 			fmt.Printf("  IPC Messages:   %d\n", ext.IPCMessages)
 
+			if len(ext.CPUByThread) > 0 {
+				fmt.Println("  CPU by Thread:")
+				for _, tc := range ext.CPUByThread {
+					fmt.Printf("    - %s (%.2fms)\n", tc.Thread, tc.CPUTimeMs)
+				}
+			}
+
+			if len(ext.TopFunctions) > 0 {
+				fmt.Println("  Top Functions:")
+				for _, fn := range ext.TopFunctions {
+					fmt.Printf("    - %s (self %.2fms, total %.2fms)\n", fn.Name, fn.SelfTimeMs, fn.TotalTimeMs)
+				}
+			}
+
 			if len(ext.TopMarkers) > 0 {
 				fmt.Println("  Top Activity:")
 				for _, marker := range ext.TopMarkers {
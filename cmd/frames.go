@@ -0,0 +1,166 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/CedricHerzog/perfowl/internal/analyzer"
+	"github.com/spf13/cobra"
+)
+
+var framesLimit int
+
+var framesCmd = &cobra.Command{
+	Use:   "frames",
+	Short: "Analyze frame rate and jank",
+	Long: `Analyzes frame timing from frame markers including:
+- Frame duration distribution and p50/p95/p99 frame time
+- Dropped frames (missed vsync intervals) and janky frames
+- The longest frames with the main-thread markers and functions that overlapped them
+
+Firefox frames come from Composite or RefreshDriverTick markers, Chrome frames
+from DrawFrame or BeginFrame events. The vsync interval is measured from vsync
+markers when present and defaults to 60Hz otherwise. Long gaps where the main
+thread was idle are not counted as frames.`,
+	RunE: runFrames,
+}
+
+func init() {
+	rootCmd.AddCommand(framesCmd)
+	framesCmd.Flags().IntVarP(&framesLimit, "limit", "l", 10, "Maximum number of long frames to report")
+}
+
+func runFrames(cmd *cobra.Command, args []string) error {
+	if profilePath == "" {
+		return fmt.Errorf("profile path is required (use --profile or -p)")
+	}
+
+	profile, _, err := loadProfile(profilePath)
+	if err != nil {
+		return err
+	}
+
+	analysis := analyzer.AnalyzeFrames(profile, framesLimit)
+
+	switch outputFormat {
+	case "json":
+		return outputFramesJSON(analysis)
+	case "markdown":
+		return outputFramesMarkdown(analysis)
+	default:
+		return outputFramesText(analysis)
+	}
+}
+
+func outputFramesJSON(analysis analyzer.FrameAnalysis) error {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(analysis)
+}
+
+func outputFramesMarkdown(analysis analyzer.FrameAnalysis) error {
+	md := strings.Builder{}
+
+	md.WriteString("# Frame Analysis\n\n")
+
+	md.WriteString("## Summary\n\n")
+	if analysis.Source != "" {
+		md.WriteString(fmt.Sprintf("- **Frame Source**: %s on %s\n", analysis.Source, analysis.SourceThread))
+	}
+	md.WriteString(fmt.Sprintf("- **Vsync Interval**: %.2f ms (%s)\n", analysis.VsyncIntervalMs, analysis.VsyncSource))
+	md.WriteString(fmt.Sprintf("- **Frames**: %d (%.1f fps)\n", analysis.TotalFrames, analysis.AverageFPS))
+	md.WriteString(fmt.Sprintf("- **Frame Time**: p50 %.2fms, p95 %.2fms, p99 %.2fms, max %.2fms\n",
+		analysis.P50FrameMs, analysis.P95FrameMs, analysis.P99FrameMs, analysis.MaxFrameMs))
+	md.WriteString(fmt.Sprintf("- **Dropped Frames**: %d\n", analysis.DroppedFrames))
+	md.WriteString(fmt.Sprintf("- **Janky Frames**: %d (%.1f%%), %d severe\n",
+		analysis.JankyFrames, analysis.JankyPercent, analysis.SevereJankFrames))
+	if analysis.IdleGaps > 0 {
+		md.WriteString(fmt.Sprintf("- **Idle Gaps Skipped**: %d\n", analysis.IdleGaps))
+	}
+
+	if len(analysis.Distribution) > 0 {
+		md.WriteString("\n## Frame Time Distribution\n\n")
+		md.WriteString("| Frame Time | Frames | Percent |\n")
+		md.WriteString("|------------|--------|---------|\n")
+		for _, b := range analysis.Distribution {
+			md.WriteString(fmt.Sprintf("| %s | %d | %.1f%% |\n", b.Label, b.Count, b.Percent))
+		}
+	}
+
+	if len(analysis.LongestFrames) > 0 {
+		md.WriteString("\n## Longest Frames\n\n")
+		for _, f := range analysis.LongestFrames {
+			md.WriteString(fmt.Sprintf("### %.2fms at %.2fms\n\n", f.DurationMs, f.StartTime))
+			md.WriteString(fmt.Sprintf("- **Dropped Frames**: %d\n", f.DroppedFrames))
+			md.WriteString(fmt.Sprintf("- **Main Thread Busy**: %.2f ms\n", f.MainThreadBusyMs))
+			for _, w := range f.Markers {
+				md.WriteString(fmt.Sprintf("- 🔶 %s (%s): %.2fms overlap\n", w.Name, w.Category, w.OverlapMs))
+			}
+			for _, fn := range f.TopFunctions {
+				md.WriteString(fmt.Sprintf("- 🔹 `%s`: %.2fms\n", fn.Name, fn.TimeMs))
+			}
+			md.WriteString("\n")
+		}
+	}
+
+	if len(analysis.Recommendations) > 0 {
+		md.WriteString("\n## Recommendations\n\n")
+		for _, r := range analysis.Recommendations {
+			md.WriteString(fmt.Sprintf("- 💡 %s\n", r))
+		}
+	}
+
+	fmt.Print(md.String())
+	return nil
+}
+
+func outputFramesText(analysis analyzer.FrameAnalysis) error {
+	fmt.Println("Frame Analysis")
+	fmt.Println(strings.Repeat("=", 60))
+	fmt.Println()
+
+	if analysis.Source != "" {
+		fmt.Printf("Frame Source:   %s on %s\n", analysis.Source, analysis.SourceThread)
+	}
+	fmt.Printf("Vsync Interval: %.2f ms (%s)\n", analysis.VsyncIntervalMs, analysis.VsyncSource)
+	fmt.Printf("Frames:         %d (%.1f fps)\n", analysis.TotalFrames, analysis.AverageFPS)
+	fmt.Printf("Frame Time:     p50 %.2fms  p95 %.2fms  p99 %.2fms  max %.2fms\n",
+		analysis.P50FrameMs, analysis.P95FrameMs, analysis.P99FrameMs, analysis.MaxFrameMs)
+	fmt.Printf("Dropped Frames: %d\n", analysis.DroppedFrames)
+	fmt.Printf("Janky Frames:   %d (%.1f%%), %d severe\n", analysis.JankyFrames, analysis.JankyPercent, analysis.SevereJankFrames)
+	fmt.Println()
+
+	if len(analysis.Distribution) > 0 {
+		fmt.Println("Distribution:")
+		for _, b := range analysis.Distribution {
+			fmt.Printf("  %-16s %6d  %5.1f%%\n", b.Label, b.Count, b.Percent)
+		}
+		fmt.Println()
+	}
+
+	if len(analysis.LongestFrames) > 0 {
+		fmt.Println("Longest Frames:")
+		for _, f := range analysis.LongestFrames {
+			fmt.Printf("  %8.2f ms at %.2f ms (%d dropped, main thread busy %.2f ms)\n",
+				f.DurationMs, f.StartTime, f.DroppedFrames, f.MainThreadBusyMs)
+			for _, w := range f.Markers {
+				fmt.Printf("    - %s (%s): %.2f ms overlap\n", w.Name, w.Category, w.OverlapMs)
+			}
+			for _, fn := range f.TopFunctions {
+				fmt.Printf("    - %s: %.2f ms\n", truncateName(fn.Name, 50), fn.TimeMs)
+			}
+		}
+		fmt.Println()
+	}
+
+	if len(analysis.Recommendations) > 0 {
+		fmt.Println("Recommendations:")
+		for _, r := range analysis.Recommendations {
+			fmt.Printf("  - %s\n", r)
+		}
+	}
+
+	return nil
+}
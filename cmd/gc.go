@@ -0,0 +1,210 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/CedricHerzog/perfowl/internal/analyzer"
+	"github.com/spf13/cobra"
+)
+
+var gcLimit int
+
+var gcCmd = &cobra.Command{
+	Use:   "gc",
+	Short: "Analyze garbage collection reasons, pauses and allocation pressure",
+	Long: `Analyzes garbage collection using Firefox GCMajor/GCMinor/GCSlice payloads and
+Chrome MajorGC/MinorGC events including:
+- A histogram of GC trigger reasons
+- Minimum mutator utilisation (MMU) over 20ms and 50ms windows
+- Nursery promotion rate and heap reclaimed
+- Incremental slices that exceeded their time budget
+- Functions sampled just before each GC (likely allocators)
+
+Example:
+  perfowl gc --profile profile.json.gz`,
+	RunE: runGC,
+}
+
+func init() {
+	rootCmd.AddCommand(gcCmd)
+	gcCmd.Flags().IntVarP(&gcLimit, "limit", "l", 10, "Maximum number of collections, slices and functions to report")
+}
+
+func runGC(cmd *cobra.Command, args []string) error {
+	if profilePath == "" {
+		return fmt.Errorf("profile path is required (use --profile or -p)")
+	}
+
+	profile, _, err := loadProfile(profilePath)
+	if err != nil {
+		return err
+	}
+
+	analysis := analyzer.AnalyzeGC(profile, gcLimit)
+
+	switch outputFormat {
+	case "json":
+		return outputGCJSON(analysis)
+	case "markdown":
+		return outputGCMarkdown(analysis)
+	default:
+		return outputGCText(analysis)
+	}
+}
+
+func outputGCJSON(analysis analyzer.GCAnalysis) error {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(analysis)
+}
+
+// formatReportedMMU renders an MMU reported by the browser, which is -1 when absent
+func formatReportedMMU(mmu float64) string {
+	if mmu < 0 {
+		return "n/a"
+	}
+	return fmt.Sprintf("%.0f%%", mmu*100)
+}
+
+func outputGCMarkdown(analysis analyzer.GCAnalysis) error {
+	md := strings.Builder{}
+
+	md.WriteString("# GC Analysis\n\n")
+
+	md.WriteString("## Summary\n\n")
+	md.WriteString(fmt.Sprintf("- **Collections**: %d major, %d minor (%d slices)\n", analysis.MajorCount, analysis.MinorCount, analysis.SliceCount))
+	md.WriteString(fmt.Sprintf("- **Pause Time**: %.2fms (%.1f%% of profile), longest %.2fms\n", analysis.TotalPauseMs, analysis.PauseTimePercent, analysis.MaxPauseMs))
+	md.WriteString(fmt.Sprintf("- **MMU**: %.0f%% (20ms), %.0f%% (50ms)", analysis.MMU20ms*100, analysis.MMU50ms*100))
+	if analysis.MMUThread != "" {
+		md.WriteString(fmt.Sprintf(" on %s", analysis.MMUThread))
+	}
+	md.WriteString("\n")
+	md.WriteString(fmt.Sprintf("- **Browser-Reported MMU**: %s (20ms), %s (50ms)\n", formatReportedMMU(analysis.ReportedMMU20ms), formatReportedMMU(analysis.ReportedMMU50ms)))
+	if analysis.NurseryBytes > 0 {
+		md.WriteString(fmt.Sprintf("- **Nursery Promotion**: %.1f%% (%s of %s)\n", analysis.PromotionRate*100, formatBytes(analysis.TenuredBytes), formatBytes(analysis.NurseryBytes)))
+	}
+	if analysis.HeapReclaimedBytes > 0 {
+		md.WriteString(fmt.Sprintf("- **Heap Reclaimed**: %s\n", formatBytes(analysis.HeapReclaimedBytes)))
+	}
+	md.WriteString(fmt.Sprintf("- **Slices Over Budget**: %d\n", analysis.SlicesOverBudget))
+
+	if len(analysis.Reasons) > 0 {
+		md.WriteString("\n## Reasons\n\n")
+		md.WriteString("| Reason | Kind | Count | Total | Max |\n")
+		md.WriteString("|--------|------|-------|-------|-----|\n")
+		for _, r := range analysis.Reasons {
+			md.WriteString(fmt.Sprintf("| %s | %s | %d | %.2fms | %.2fms |\n", r.Reason, r.Kind, r.Count, r.TotalMs, r.MaxMs))
+		}
+	}
+
+	if len(analysis.LongestCollections) > 0 {
+		md.WriteString("\n## Longest Collections\n\n")
+		for _, c := range analysis.LongestCollections {
+			md.WriteString(fmt.Sprintf("- 🔶 **%s** %.2fms at %.2fms on %s (%s)", c.Kind, c.DurationMs, c.StartTime, c.Thread, c.Reason))
+			if c.NonincrementalReason != "" {
+				md.WriteString(fmt.Sprintf(" ⚠️ non-incremental: %s", c.NonincrementalReason))
+			}
+			md.WriteString("\n")
+		}
+	}
+
+	if len(analysis.OverBudgetSlices) > 0 {
+		md.WriteString("\n## Slices Over Budget\n\n")
+		md.WriteString("| Time | Pause | Budget | Over | Reason | Thread |\n")
+		md.WriteString("|------|-------|--------|------|--------|--------|\n")
+		for _, s := range analysis.OverBudgetSlices {
+			md.WriteString(fmt.Sprintf("| %.2fms | %.2fms | %.2fms | %.2fms | %s | %s |\n", s.StartTime, s.PauseMs, s.BudgetMs, s.OverMs, s.Reason, s.Thread))
+		}
+	}
+
+	if len(analysis.AllocationHotspots) > 0 {
+		md.WriteString("\n## Running Before GC\n\n")
+		for _, h := range analysis.AllocationHotspots {
+			md.WriteString(fmt.Sprintf("- 🔹 `%s`: %.2fms before %d GCs\n", h.Name, h.TimeMs, h.GCCount))
+		}
+	}
+
+	if len(analysis.Recommendations) > 0 {
+		md.WriteString("\n## Recommendations\n\n")
+		for _, r := range analysis.Recommendations {
+			md.WriteString(fmt.Sprintf("- 💡 %s\n", r))
+		}
+	}
+
+	fmt.Print(md.String())
+	return nil
+}
+
+func outputGCText(analysis analyzer.GCAnalysis) error {
+	fmt.Println("GC Analysis")
+	fmt.Println(strings.Repeat("=", 60))
+	fmt.Println()
+
+	fmt.Printf("Collections: %d major, %d minor (%d slices)\n", analysis.MajorCount, analysis.MinorCount, analysis.SliceCount)
+	fmt.Printf("Pause Time: %.2fms (%.1f%% of profile), longest %.2fms\n", analysis.TotalPauseMs, analysis.PauseTimePercent, analysis.MaxPauseMs)
+	fmt.Printf("MMU: %.0f%% (20ms), %.0f%% (50ms)", analysis.MMU20ms*100, analysis.MMU50ms*100)
+	if analysis.MMUThread != "" {
+		fmt.Printf(" on %s", analysis.MMUThread)
+	}
+	fmt.Println()
+	fmt.Printf("Browser-Reported MMU: %s (20ms), %s (50ms)\n", formatReportedMMU(analysis.ReportedMMU20ms), formatReportedMMU(analysis.ReportedMMU50ms))
+	if analysis.NurseryBytes > 0 {
+		fmt.Printf("Nursery Promotion: %.1f%% (%s of %s)\n", analysis.PromotionRate*100, formatBytes(analysis.TenuredBytes), formatBytes(analysis.NurseryBytes))
+	}
+	if analysis.HeapReclaimedBytes > 0 {
+		fmt.Printf("Heap Reclaimed: %s\n", formatBytes(analysis.HeapReclaimedBytes))
+	}
+	fmt.Printf("Slices Over Budget: %d\n", analysis.SlicesOverBudget)
+	fmt.Println()
+
+	if len(analysis.Reasons) > 0 {
+		fmt.Println("Reasons:")
+		fmt.Println(strings.Repeat("-", 60))
+		for _, r := range analysis.Reasons {
+			fmt.Printf("  %-30s %-6s %4d  %9.2fms total  %8.2fms max\n", truncateName(r.Reason, 30), r.Kind, r.Count, r.TotalMs, r.MaxMs)
+		}
+		fmt.Println()
+	}
+
+	if len(analysis.LongestCollections) > 0 {
+		fmt.Println("Longest Collections:")
+		fmt.Println(strings.Repeat("-", 60))
+		for _, c := range analysis.LongestCollections {
+			fmt.Printf("  %-6s %8.2fms at %9.2fms  %s (%s)\n", c.Kind, c.DurationMs, c.StartTime, c.Reason, c.Thread)
+			if c.NonincrementalReason != "" {
+				fmt.Printf("         non-incremental: %s\n", c.NonincrementalReason)
+			}
+		}
+		fmt.Println()
+	}
+
+	if len(analysis.OverBudgetSlices) > 0 {
+		fmt.Println("Slices Over Budget:")
+		fmt.Println(strings.Repeat("-", 60))
+		for _, s := range analysis.OverBudgetSlices {
+			fmt.Printf("  %9.2fms: paused %.2fms of a %.2fms budget (+%.2fms) %s\n", s.StartTime, s.PauseMs, s.BudgetMs, s.OverMs, s.Reason)
+		}
+		fmt.Println()
+	}
+
+	if len(analysis.AllocationHotspots) > 0 {
+		fmt.Println("Running Before GC:")
+		fmt.Println(strings.Repeat("-", 60))
+		for _, h := range analysis.AllocationHotspots {
+			fmt.Printf("  %-40s %8.2fms  before %d GCs\n", truncateName(h.Name, 40), h.TimeMs, h.GCCount)
+		}
+		fmt.Println()
+	}
+
+	if len(analysis.Recommendations) > 0 {
+		fmt.Println("Recommendations:")
+		for _, r := range analysis.Recommendations {
+			fmt.Printf("  - %s\n", r)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,188 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/CedricHerzog/perfowl/internal/analyzer"
+	"github.com/spf13/cobra"
+)
+
+var interactionsLimit int
+
+var interactionsCmd = &cobra.Command{
+	Use:   "interactions",
+	Short: "Analyze input responsiveness and interaction latency (INP)",
+	Long: `Measures how long clicks, taps and key presses took to produce a paint:
+- Input delay from the event timestamp until the handlers started
+- Processing time spent in the event handlers
+- Presentation delay until the next paint or composite
+- An INP-style score (the worst interaction, ignoring one outlier per 50)
+
+Related events such as pointerdown, pointerup and click are grouped into one
+interaction. Firefox DOMEvent markers without a recorded timestamp take their
+input delay from the sampled event delay when the profile has one, which is
+also summarized per thread.`,
+	RunE: runInteractions,
+}
+
+func init() {
+	rootCmd.AddCommand(interactionsCmd)
+	interactionsCmd.Flags().IntVarP(&interactionsLimit, "limit", "l", 10, "Maximum number of slow interactions to report")
+}
+
+func runInteractions(cmd *cobra.Command, args []string) error {
+	if profilePath == "" {
+		return fmt.Errorf("profile path is required (use --profile or -p)")
+	}
+
+	profile, _, err := loadProfile(profilePath)
+	if err != nil {
+		return err
+	}
+
+	analysis := analyzer.AnalyzeInteractions(profile, interactionsLimit)
+
+	switch outputFormat {
+	case "json":
+		return outputInteractionsJSON(analysis)
+	case "markdown":
+		return outputInteractionsMarkdown(analysis)
+	default:
+		return outputInteractionsText(analysis)
+	}
+}
+
+func outputInteractionsJSON(analysis analyzer.InteractionAnalysis) error {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(analysis)
+}
+
+func outputInteractionsMarkdown(analysis analyzer.InteractionAnalysis) error {
+	md := strings.Builder{}
+
+	md.WriteString("# Interaction Analysis\n\n")
+
+	md.WriteString("## Summary\n\n")
+	md.WriteString(fmt.Sprintf("- **Interactions**: %d (%d slower than %.0fms)\n",
+		analysis.TotalInteractions, analysis.SlowInteractions, analyzer.InteractionGoodMs))
+	if analysis.TotalInteractions > 0 {
+		md.WriteString(fmt.Sprintf("- **INP**: %.2f ms (%s)\n", analysis.INPMs, analysis.INPRating))
+		md.WriteString(fmt.Sprintf("- **Latency**: p50 %.2fms, p75 %.2fms, max %.2fms\n",
+			analysis.P50LatencyMs, analysis.P75LatencyMs, analysis.MaxLatencyMs))
+		md.WriteString(fmt.Sprintf("- **Average Phases**: input delay %.2fms, processing %.2fms, presentation %.2fms\n",
+			analysis.AvgInputDelayMs, analysis.AvgProcessingMs, analysis.AvgPresentationMs))
+	}
+
+	if len(analysis.ByType) > 0 {
+		md.WriteString("\n## By Event Type\n\n")
+		md.WriteString("| Type | Count | p50 | Max |\n")
+		md.WriteString("|------|-------|-----|-----|\n")
+		for _, ts := range analysis.ByType {
+			md.WriteString(fmt.Sprintf("| %s | %d | %.2fms | %.2fms |\n", ts.Type, ts.Count, ts.P50LatencyMs, ts.MaxLatencyMs))
+		}
+	}
+
+	if len(analysis.EventDelay) > 0 {
+		md.WriteString("\n## Event Delay\n\n")
+		md.WriteString("| Thread | Mean | p95 | Max | Samples Over Threshold |\n")
+		md.WriteString("|--------|------|-----|-----|------------------------|\n")
+		for _, ed := range analysis.EventDelay {
+			md.WriteString(fmt.Sprintf("| %s | %.2fms | %.2fms | %.2fms | %d |\n",
+				ed.Thread, ed.MeanMs, ed.P95Ms, ed.MaxMs, ed.SamplesOver))
+		}
+	}
+
+	if len(analysis.WorstInteractions) > 0 {
+		md.WriteString("\n## Slowest Interactions\n\n")
+		for _, in := range analysis.WorstInteractions {
+			md.WriteString(fmt.Sprintf("### %s at %.2fms: %.2fms\n\n", in.Type, in.StartTime, in.LatencyMs))
+			md.WriteString(fmt.Sprintf("- **Input Delay**: %.2f ms (%s)\n", in.InputDelayMs, in.InputDelaySource))
+			md.WriteString(fmt.Sprintf("- **Processing**: %.2f ms\n", in.ProcessingMs))
+			if in.PresentedBy != "" {
+				md.WriteString(fmt.Sprintf("- **Presentation Delay**: %.2f ms (%s)\n", in.PresentationDelayMs, in.PresentedBy))
+			} else {
+				md.WriteString("- ⚠️ No paint followed this interaction\n")
+			}
+			for _, ev := range in.Events {
+				md.WriteString(fmt.Sprintf("- 🔶 %s handler: %.2fms\n", ev.Type, ev.DurationMs))
+			}
+			for _, fn := range in.TopFunctions {
+				md.WriteString(fmt.Sprintf("- 🔹 `%s`: %.2fms\n", fn.Name, fn.TimeMs))
+			}
+			md.WriteString("\n")
+		}
+	}
+
+	if len(analysis.Recommendations) > 0 {
+		md.WriteString("\n## Recommendations\n\n")
+		for _, r := range analysis.Recommendations {
+			md.WriteString(fmt.Sprintf("- 💡 %s\n", r))
+		}
+	}
+
+	fmt.Print(md.String())
+	return nil
+}
+
+func outputInteractionsText(analysis analyzer.InteractionAnalysis) error {
+	fmt.Println("Interaction Analysis")
+	fmt.Println(strings.Repeat("=", 60))
+	fmt.Println()
+
+	fmt.Printf("Interactions:   %d (%d slower than %.0fms)\n",
+		analysis.TotalInteractions, analysis.SlowInteractions, analyzer.InteractionGoodMs)
+	if analysis.TotalInteractions > 0 {
+		fmt.Printf("INP:            %.2f ms (%s)\n", analysis.INPMs, analysis.INPRating)
+		fmt.Printf("Latency:        p50 %.2fms  p75 %.2fms  max %.2fms\n",
+			analysis.P50LatencyMs, analysis.P75LatencyMs, analysis.MaxLatencyMs)
+		fmt.Printf("Average Phases: input delay %.2fms  processing %.2fms  presentation %.2fms\n",
+			analysis.AvgInputDelayMs, analysis.AvgProcessingMs, analysis.AvgPresentationMs)
+	}
+	fmt.Println()
+
+	if len(analysis.ByType) > 0 {
+		fmt.Println("By Event Type:")
+		for _, ts := range analysis.ByType {
+			fmt.Printf("  %-14s %5d  p50 %8.2f ms  max %8.2f ms\n", ts.Type, ts.Count, ts.P50LatencyMs, ts.MaxLatencyMs)
+		}
+		fmt.Println()
+	}
+
+	if len(analysis.EventDelay) > 0 {
+		fmt.Println("Event Delay:")
+		for _, ed := range analysis.EventDelay {
+			fmt.Printf("  %-20s mean %6.2f ms  p95 %6.2f ms  max %6.2f ms  (%d samples over %.0fms)\n",
+				truncateName(ed.Thread, 20), ed.MeanMs, ed.P95Ms, ed.MaxMs, ed.SamplesOver, ed.ThresholdMs)
+		}
+		fmt.Println()
+	}
+
+	if len(analysis.WorstInteractions) > 0 {
+		fmt.Println("Slowest Interactions:")
+		for _, in := range analysis.WorstInteractions {
+			presented := in.PresentedBy
+			if presented == "" {
+				presented = "no paint"
+			}
+			fmt.Printf("  %8.2f ms  %-12s at %.2f ms (delay %.2f, processing %.2f, presentation %.2f via %s)\n",
+				in.LatencyMs, in.Type, in.StartTime, in.InputDelayMs, in.ProcessingMs, in.PresentationDelayMs, presented)
+			for _, fn := range in.TopFunctions {
+				fmt.Printf("    - %s: %.2f ms\n", truncateName(fn.Name, 50), fn.TimeMs)
+			}
+		}
+		fmt.Println()
+	}
+
+	if len(analysis.Recommendations) > 0 {
+		fmt.Println("Recommendations:")
+		for _, r := range analysis.Recommendations {
+			fmt.Printf("  - %s\n", r)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,254 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/CedricHerzog/perfowl/internal/analyzer"
+	"github.com/CedricHerzog/perfowl/internal/parser"
+	"github.com/spf13/cobra"
+)
+
+var (
+	jitCompareProfile string
+	jitLimit          int
+)
+
+var jitCmd = &cobra.Command{
+	Use:   "jit",
+	Short: "Analyze JavaScript time by JIT execution tier",
+	Long: `Analyzes JavaScript time by execution tier including:
+- Time in the interpreter, baseline interpreter, baseline and optimizing tiers
+- Tier mix per thread and per function
+- Hot functions that never reach the optimizing tier (deoptimization or warm-up issues)
+
+Firefox reports the tier of every JIT frame (interpreter, blinterp, baseline, ion).
+Chrome traces only report V8's code type, so the tier breakdown is limited.
+
+Use --compare to compare the tier mix between two profiles:
+  perfowl jit -p baseline.json.gz --compare regressed.json.gz`,
+	RunE: runJIT,
+}
+
+func init() {
+	rootCmd.AddCommand(jitCmd)
+	jitCmd.Flags().StringVar(&jitCompareProfile, "compare", "", "Compare with another profile")
+	jitCmd.Flags().IntVarP(&jitLimit, "limit", "l", 20, "Maximum number of functions to report")
+}
+
+func runJIT(cmd *cobra.Command, args []string) error {
+	if profilePath == "" {
+		return fmt.Errorf("profile path is required (use --profile or -p)")
+	}
+
+	profile, _, err := loadProfile(profilePath)
+	if err != nil {
+		return err
+	}
+
+	if jitCompareProfile != "" {
+		compProfile, _, err := parser.LoadProfileWithType(jitCompareProfile, parser.ParseBrowserType(browserType))
+		if err != nil {
+			return fmt.Errorf("failed to load comparison profile: %w", err)
+		}
+		compProfile, err = applyScope(compProfile)
+		if err != nil {
+			return err
+		}
+
+		comparison := analyzer.CompareJITTiers(profile, compProfile, jitLimit)
+
+		switch outputFormat {
+		case "json":
+			return outputJITComparisonJSON(comparison)
+		case "markdown":
+			return outputJITComparisonMarkdown(comparison)
+		default:
+			return outputJITComparisonText(comparison)
+		}
+	}
+
+	analysis := analyzer.AnalyzeJITTiers(profile, jitLimit)
+
+	switch outputFormat {
+	case "json":
+		return outputJITJSON(analysis)
+	case "markdown":
+		return outputJITMarkdown(analysis)
+	default:
+		return outputJITText(analysis)
+	}
+}
+
+func outputJITJSON(analysis analyzer.JITTierAnalysis) error {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(analysis)
+}
+
+func outputJITComparisonJSON(comparison analyzer.JITTierComparison) error {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(comparison)
+}
+
+func outputJITMarkdown(analysis analyzer.JITTierAnalysis) error {
+	md := strings.Builder{}
+
+	md.WriteString("# JIT Tier Analysis\n\n")
+
+	md.WriteString("## Summary\n\n")
+	md.WriteString(fmt.Sprintf("- **Total JS Time**: %.2f ms\n", analysis.TotalJSTimeMs))
+	md.WriteString(fmt.Sprintf("- **JS Samples**: %d\n", analysis.TotalSamples))
+
+	if len(analysis.Tiers) > 0 {
+		md.WriteString("\n## Time by Tier\n\n")
+		md.WriteString("| Tier | Time | Percent |\n")
+		md.WriteString("|------|------|---------|\n")
+		for _, ts := range analysis.Tiers {
+			md.WriteString(fmt.Sprintf("| %s | %.2fms | %.1f%% |\n", ts.Tier, ts.TimeMs, ts.Percent))
+		}
+	}
+
+	if len(analysis.Unoptimized) > 0 {
+		md.WriteString("\n## Hot Functions Below the Optimizing Tier\n\n")
+		writeJITFunctionTable(&md, analysis.Unoptimized)
+	}
+
+	if len(analysis.TopFunctions) > 0 {
+		md.WriteString("\n## Top Functions\n\n")
+		writeJITFunctionTable(&md, analysis.TopFunctions)
+	}
+
+	if len(analysis.Recommendations) > 0 {
+		md.WriteString("\n## Recommendations\n\n")
+		for _, r := range analysis.Recommendations {
+			md.WriteString(fmt.Sprintf("- 💡 %s\n", r))
+		}
+	}
+
+	fmt.Print(md.String())
+	return nil
+}
+
+func writeJITFunctionTable(md *strings.Builder, funcs []analyzer.FunctionTierStats) {
+	md.WriteString("| Function | Total | Interpreter | Baseline Interp | Baseline | Optimized | Optimized % |\n")
+	md.WriteString("|----------|-------|-------------|-----------------|----------|-----------|-------------|\n")
+	for _, fs := range funcs {
+		name := fs.Name
+		if fs.IsWasm {
+			name += " (wasm)"
+		}
+		md.WriteString(fmt.Sprintf("| `%s` | %.2fms | %.2fms | %.2fms | %.2fms | %.2fms | %.1f%% |\n",
+			name, fs.TotalTimeMs, fs.InterpreterMs, fs.BaselineInterpreterMs, fs.BaselineMs, fs.OptimizedMs, fs.OptimizedPercent))
+	}
+}
+
+func outputJITComparisonMarkdown(comparison analyzer.JITTierComparison) error {
+	md := strings.Builder{}
+
+	md.WriteString("# JIT Tier Comparison\n\n")
+
+	md.WriteString("## Tier Mix\n\n")
+	md.WriteString("| Tier | Baseline | Comparison | Change |\n")
+	md.WriteString("|------|----------|------------|--------|\n")
+	for _, tc := range comparison.TierChanges {
+		md.WriteString(fmt.Sprintf("| %s | %.1f%% (%.1fms) | %.1f%% (%.1fms) | %+.1f pts |\n",
+			tc.Tier, tc.BaselinePercent, tc.BaselineMs, tc.ComparisonPercent, tc.ComparisonMs, tc.ChangePoints))
+	}
+
+	if len(comparison.FunctionChanges) > 0 {
+		md.WriteString("\n## Functions with Tier Changes\n\n")
+		md.WriteString("| Function | Baseline Optimized | Comparison Optimized | Change |\n")
+		md.WriteString("|----------|--------------------|----------------------|--------|\n")
+		for _, fc := range comparison.FunctionChanges {
+			md.WriteString(fmt.Sprintf("| `%s` | %.1f%% | %.1f%% | %+.1f pts |\n",
+				fc.Name, fc.BaselineOptimizedPercent, fc.ComparisonOptimizedPercent, fc.ChangePoints))
+		}
+	}
+
+	md.WriteString("\n## Analysis\n\n")
+	md.WriteString(comparison.Analysis + "\n")
+
+	fmt.Print(md.String())
+	return nil
+}
+
+func outputJITText(analysis analyzer.JITTierAnalysis) error {
+	fmt.Println("JIT Tier Analysis")
+	fmt.Println(strings.Repeat("=", 60))
+	fmt.Println()
+
+	fmt.Printf("Total JS Time: %.2f ms (%d samples)\n\n", analysis.TotalJSTimeMs, analysis.TotalSamples)
+
+	if len(analysis.Tiers) > 0 {
+		fmt.Println("Time by Tier:")
+		for _, ts := range analysis.Tiers {
+			fmt.Printf("  %-22s %10.2f ms  %5.1f%%\n", ts.Tier, ts.TimeMs, ts.Percent)
+		}
+		fmt.Println()
+	}
+
+	if len(analysis.Unoptimized) > 0 {
+		fmt.Println("Hot Functions Below the Optimizing Tier:")
+		for _, fs := range analysis.Unoptimized {
+			fmt.Printf("  %-40s %8.2f ms  %5.1f%% optimized\n", truncateName(fs.Name, 40), fs.TotalTimeMs, fs.OptimizedPercent)
+		}
+		fmt.Println()
+	}
+
+	if len(analysis.TopFunctions) > 0 {
+		fmt.Println("Top Functions:")
+		for _, fs := range analysis.TopFunctions {
+			fmt.Printf("  %-40s %8.2f ms  %5.1f%% optimized\n", truncateName(fs.Name, 40), fs.TotalTimeMs, fs.OptimizedPercent)
+		}
+		fmt.Println()
+	}
+
+	if len(analysis.Recommendations) > 0 {
+		fmt.Println("Recommendations:")
+		for _, r := range analysis.Recommendations {
+			fmt.Printf("  - %s\n", r)
+		}
+	}
+
+	return nil
+}
+
+func outputJITComparisonText(comparison analyzer.JITTierComparison) error {
+	fmt.Println("JIT Tier Comparison")
+	fmt.Println(strings.Repeat("=", 60))
+	fmt.Println()
+
+	fmt.Println("Tier                      Baseline    Comparison    Change")
+	fmt.Println(strings.Repeat("-", 60))
+	for _, tc := range comparison.TierChanges {
+		fmt.Printf("%-22s    %7.1f%%    %9.1f%%    %+.1f pts\n",
+			tc.Tier, tc.BaselinePercent, tc.ComparisonPercent, tc.ChangePoints)
+	}
+	fmt.Println()
+
+	if len(comparison.FunctionChanges) > 0 {
+		fmt.Println("Functions with Tier Changes (optimized share):")
+		for _, fc := range comparison.FunctionChanges {
+			fmt.Printf("  %-40s %5.1f%% -> %5.1f%%  (%+.1f pts)\n",
+				truncateName(fc.Name, 40), fc.BaselineOptimizedPercent, fc.ComparisonOptimizedPercent, fc.ChangePoints)
+		}
+		fmt.Println()
+	}
+
+	fmt.Println("Analysis:")
+	fmt.Printf("  %s\n", comparison.Analysis)
+
+	return nil
+}
+
+// truncateName shortens a name to max characters for column output
+func truncateName(name string, max int) string {
+	if len(name) > max {
+		return name[:max-3] + "..."
+	}
+	return name
+}
@@ -7,7 +7,6 @@ import (
 	"strings"
 
 	"github.com/CedricHerzog/perfowl/internal/analyzer"
-	"github.com/CedricHerzog/perfowl/internal/parser"
 	"github.com/spf13/cobra"
 )
 
@@ -34,10 +33,9 @@ func runJSCrypto(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("profile path is required (use --profile or -p)")
 	}
 
-	bt := parser.ParseBrowserType(browserType)
-	profile, _, err := parser.LoadProfileWithType(profilePath, bt)
+	profile, _, err := loadProfile(profilePath)
 	if err != nil {
-		return fmt.Errorf("failed to load profile: %w", err)
+		return err
 	}
 
 	analysis := analyzer.AnalyzeJSCrypto(profile)
@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/CedricHerzog/perfowl/internal/analyzer"
+	"github.com/spf13/cobra"
+)
+
+var layoutShiftsLimit int
+
+var layoutShiftsCmd = &cobra.Command{
+	Use:     "layout-shifts",
+	Aliases: []string{"cls"},
+	Short:   "Analyze layout stability (CLS) from Chrome LayoutShift events",
+	Long: `Computes Cumulative Layout Shift from Chrome LayoutShift events including:
+- Session windows (shifts less than 1s apart, at most 5s long) and their scores
+- CLS, the score of the worst session window
+- The largest shifts with their timestamps and the DOM nodes they moved
+
+Shifts within 500ms of user input are reported but do not count towards CLS.`,
+	RunE: runLayoutShifts,
+}
+
+func init() {
+	rootCmd.AddCommand(layoutShiftsCmd)
+	layoutShiftsCmd.Flags().IntVarP(&layoutShiftsLimit, "limit", "l", 10, "Maximum number of shifts to report")
+}
+
+func runLayoutShifts(cmd *cobra.Command, args []string) error {
+	if profilePath == "" {
+		return fmt.Errorf("profile path is required (use --profile or -p)")
+	}
+
+	profile, _, err := loadProfile(profilePath)
+	if err != nil {
+		return err
+	}
+
+	analysis := analyzer.AnalyzeLayoutShifts(profile, layoutShiftsLimit)
+
+	switch outputFormat {
+	case "json":
+		return outputLayoutShiftsJSON(analysis)
+	case "markdown":
+		return outputLayoutShiftsMarkdown(analysis)
+	default:
+		fmt.Print(analyzer.FormatLayoutShiftAnalysis(analysis))
+		return nil
+	}
+}
+
+func outputLayoutShiftsJSON(analysis analyzer.LayoutShiftAnalysis) error {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(analysis)
+}
+
+func outputLayoutShiftsMarkdown(analysis analyzer.LayoutShiftAnalysis) error {
+	md := strings.Builder{}
+
+	md.WriteString("# Layout Shift Analysis\n\n")
+
+	ratingEmoji := map[string]string{
+		"good":              "✅",
+		"needs-improvement": "🟡",
+		"poor":              "🔴",
+	}
+
+	md.WriteString("## Summary\n\n")
+	md.WriteString(fmt.Sprintf("- **CLS**: %s %.3f (%s)\n", ratingEmoji[analysis.Rating], analysis.CLS, analysis.Rating))
+	md.WriteString(fmt.Sprintf("- **Layout Shifts**: %d (%d excluded after input)\n", analysis.TotalShifts, analysis.ExcludedShifts))
+	md.WriteString(fmt.Sprintf("- **Total Score**: %.4f\n", analysis.TotalScore))
+	md.WriteString(fmt.Sprintf("- **Session Windows**: %d\n", len(analysis.Windows)))
+
+	if len(analysis.Windows) > 0 {
+		md.WriteString("\n## Session Windows\n\n")
+		md.WriteString("| Window | Start | End | Score | Shifts |\n")
+		md.WriteString("|--------|-------|-----|-------|--------|\n")
+		for _, w := range analysis.Windows {
+			label := fmt.Sprintf("%d", w.Index+1)
+			if w.Index == analysis.WorstWindow {
+				label += " (CLS)"
+			}
+			md.WriteString(fmt.Sprintf("| %s | %.2fms | %.2fms | %.4f | %d |\n", label, w.StartTime, w.EndTime, w.Score, w.ShiftCount))
+		}
+	}
+
+	if len(analysis.LargestShifts) > 0 {
+		md.WriteString("\n## Largest Shifts\n\n")
+		for _, s := range analysis.LargestShifts {
+			note := ""
+			if s.HadRecentInput {
+				note = " ℹ️ after input, excluded"
+			}
+			md.WriteString(fmt.Sprintf("- 🔶 **%.4f** at %.2fms%s\n", s.Score, s.TimeMs, note))
+			for _, n := range s.Nodes {
+				md.WriteString(fmt.Sprintf("  - 🔹 node `%d`: %v → %v\n", n.NodeID, n.OldRect, n.NewRect))
+			}
+		}
+	}
+
+	if len(analysis.Recommendations) > 0 {
+		md.WriteString("\n## Recommendations\n\n")
+		for _, r := range analysis.Recommendations {
+			md.WriteString(fmt.Sprintf("- 💡 %s\n", r))
+		}
+	}
+
+	fmt.Print(md.String())
+	return nil
+}
@@ -0,0 +1,197 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/CedricHerzog/perfowl/internal/analyzer"
+	"github.com/spf13/cobra"
+)
+
+var (
+	linesFunction   string
+	linesFile       string
+	linesSourceRoot string
+	linesContext    int
+	linesLimit      int
+)
+
+var linesCmd = &cobra.Command{
+	Use:     "lines",
+	Aliases: []string{"list"},
+	Short:   "Attribute time to source lines of a function or file",
+	Long: `Attributes self and total time to individual source lines including:
+- Self time of the line the sample was executing
+- Total time of lines on the stack (call sites include their callees)
+- The hottest lines across all matching functions
+
+Select code with --function and/or --file (case-insensitive substring matches).
+When --source-root points to a local checkout, the output is an annotated
+source listing similar to pprof -list:
+  perfowl lines -p profile.json.gz --function decrypt --source-root ./src
+
+Profiles without frame line numbers fall back to the function's first line.`,
+	RunE: runLines,
+}
+
+func init() {
+	rootCmd.AddCommand(linesCmd)
+	linesCmd.Flags().StringVar(&linesFunction, "function", "", "Function name filter")
+	linesCmd.Flags().StringVar(&linesFile, "file", "", "File name or URL filter")
+	linesCmd.Flags().StringVar(&linesSourceRoot, "source-root", "", "Local directory containing the source files")
+	linesCmd.Flags().IntVar(&linesContext, "context", 3, "Lines of context around sampled lines in the listing")
+	linesCmd.Flags().IntVarP(&linesLimit, "limit", "l", 20, "Maximum number of hot lines to report")
+}
+
+func runLines(cmd *cobra.Command, args []string) error {
+	if profilePath == "" {
+		return fmt.Errorf("profile path is required (use --profile or -p)")
+	}
+	if linesFunction == "" && linesFile == "" {
+		return fmt.Errorf("a function or file filter is required (use --function or --file)")
+	}
+
+	profile, _, err := loadProfile(profilePath)
+	if err != nil {
+		return err
+	}
+
+	analysis, err := analyzer.AnalyzeSourceLines(profile, analyzer.SourceLineOptions{
+		Function:     linesFunction,
+		File:         linesFile,
+		SourceRoot:   linesSourceRoot,
+		ContextLines: linesContext,
+		Limit:        linesLimit,
+	})
+	if err != nil {
+		return err
+	}
+
+	switch outputFormat {
+	case "json":
+		return outputLinesJSON(analysis)
+	case "markdown":
+		return outputLinesMarkdown(analysis)
+	default:
+		return outputLinesText(analysis)
+	}
+}
+
+func outputLinesJSON(analysis analyzer.SourceLineAnalysis) error {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(analysis)
+}
+
+func outputLinesMarkdown(analysis analyzer.SourceLineAnalysis) error {
+	md := strings.Builder{}
+
+	md.WriteString("# Source Line Attribution\n\n")
+
+	md.WriteString("## Summary\n\n")
+	if analysis.Function != "" {
+		md.WriteString(fmt.Sprintf("- **Function**: `%s`\n", analysis.Function))
+	}
+	if analysis.File != "" {
+		md.WriteString(fmt.Sprintf("- **File**: `%s`\n", analysis.File))
+	}
+	md.WriteString(fmt.Sprintf("- **Self Time**: %.2f ms of %.2f ms\n", analysis.SelfTimeMs, analysis.ProfileTimeMs))
+	if analysis.UnattributedMs > 0 {
+		md.WriteString(fmt.Sprintf("- **Without Line Info**: %.2f ms\n", analysis.UnattributedMs))
+	}
+
+	if len(analysis.HotLines) > 0 {
+		md.WriteString("\n## Hot Lines\n\n")
+		md.WriteString("| Location | Function | Self | Total | Self % |\n")
+		md.WriteString("|----------|----------|------|-------|--------|\n")
+		for _, ls := range analysis.HotLines {
+			md.WriteString(fmt.Sprintf("| `%s:%d` | `%s` | %.2fms | %.2fms | %.1f%% |\n",
+				ls.File, ls.Line, ls.Function, ls.SelfTimeMs, ls.TotalTimeMs, ls.SelfPercent))
+		}
+	}
+
+	for _, fs := range analysis.Files {
+		md.WriteString(fmt.Sprintf("\n## %s\n\n", fs.File))
+		md.WriteString(fmt.Sprintf("- **Functions**: %s\n", strings.Join(fs.Functions, ", ")))
+		if fs.SourceError != "" {
+			md.WriteString(fmt.Sprintf("- ⚠️ %s\n", fs.SourceError))
+		}
+		if len(fs.Listing) > 0 {
+			md.WriteString("\n```\n")
+			writeLinesListing(&md, fs.Listing)
+			md.WriteString("```\n")
+		}
+	}
+
+	fmt.Print(md.String())
+	return nil
+}
+
+func outputLinesText(analysis analyzer.SourceLineAnalysis) error {
+	fmt.Println("Source Line Attribution")
+	fmt.Println(strings.Repeat("=", 60))
+	fmt.Println()
+
+	fmt.Printf("Self Time: %.2f ms of %.2f ms\n", analysis.SelfTimeMs, analysis.ProfileTimeMs)
+	if analysis.UnattributedMs > 0 {
+		fmt.Printf("Without Line Info: %.2f ms\n", analysis.UnattributedMs)
+	}
+	fmt.Println()
+
+	if len(analysis.Files) == 0 {
+		fmt.Println("No matching frames found.")
+		return nil
+	}
+
+	for _, fs := range analysis.Files {
+		fmt.Printf("ROUTINE ======================== %s in %s\n", strings.Join(fs.Functions, ", "), fs.File)
+		if fs.SourceError != "" {
+			fmt.Printf("  (%s)\n", fs.SourceError)
+		}
+
+		if len(fs.Listing) > 0 {
+			md := strings.Builder{}
+			writeLinesListing(&md, fs.Listing)
+			fmt.Print(md.String())
+		} else {
+			fmt.Printf("%12s %12s %7s\n", "Self", "Total", "Line")
+			for _, ls := range fs.Lines {
+				fmt.Printf("%12s %12s %7d\n", formatLineTime(ls.SelfTimeMs), formatLineTime(ls.TotalTimeMs), ls.Line)
+			}
+		}
+		fmt.Println()
+	}
+
+	if len(analysis.HotLines) > 0 {
+		fmt.Println("Hot Lines:")
+		for _, ls := range analysis.HotLines {
+			fmt.Printf("  %-50s %10.2f ms  %5.1f%%\n",
+				truncateName(fmt.Sprintf("%s:%d", ls.File, ls.Line), 50), ls.SelfTimeMs, ls.SelfPercent)
+		}
+	}
+
+	return nil
+}
+
+// writeLinesListing writes an annotated source listing in pprof -list style
+func writeLinesListing(sb *strings.Builder, listing []analyzer.AnnotatedLine) {
+	sb.WriteString(fmt.Sprintf("%12s %12s %7s\n", "Self", "Total", "Line"))
+	for _, al := range listing {
+		if al.Gap {
+			sb.WriteString(fmt.Sprintf("%12s %12s %7s\n", "", "", "..."))
+			continue
+		}
+		sb.WriteString(fmt.Sprintf("%12s %12s %7d  %s\n",
+			formatLineTime(al.SelfTimeMs), formatLineTime(al.TotalTimeMs), al.Line, al.Source))
+	}
+}
+
+// formatLineTime formats a line time, using "." for lines without samples
+func formatLineTime(ms float64) string {
+	if ms == 0 {
+		return "."
+	}
+	return fmt.Sprintf("%.2fms", ms)
+}
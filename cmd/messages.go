@@ -0,0 +1,176 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/CedricHerzog/perfowl/internal/analyzer"
+	"github.com/spf13/cobra"
+)
+
+var messagesLimit int
+
+var messagesCmd = &cobra.Command{
+	Use:   "messages",
+	Short: "Analyze postMessage traffic between the main thread and workers",
+	Long: `Pairs posted messages with the events that handled them (Firefox PostMessage
+markers and message DOM events, Chrome WorkerThread::PostMessage and
+HandlePostMessage events) and reports:
+- Messages sent and received per thread
+- Per sender/receiver channel message counts
+- Queueing latency from send to receive (min, avg, p50, p95, max)
+- Payload sizes where the markers record them
+
+Use -o dot or -o mermaid to print a graph of who talks to whom.
+
+Example:
+  perfowl messages --profile profile.json.gz
+  perfowl messages --profile profile.json.gz -o dot | dot -Tsvg > messages.svg`,
+	RunE: runMessages,
+}
+
+func init() {
+	rootCmd.AddCommand(messagesCmd)
+	messagesCmd.Flags().IntVarP(&messagesLimit, "limit", "l", 20, "Maximum number of channels to report")
+}
+
+func runMessages(cmd *cobra.Command, args []string) error {
+	if profilePath == "" {
+		return fmt.Errorf("profile path is required (use --profile or -p)")
+	}
+
+	profile, _, err := loadProfile(profilePath)
+	if err != nil {
+		return err
+	}
+
+	analysis := analyzer.AnalyzeMessageFlow(profile, messagesLimit)
+
+	switch outputFormat {
+	case "json":
+		return outputMessagesJSON(analysis)
+	case "markdown":
+		return outputMessagesMarkdown(analysis)
+	case "dot":
+		fmt.Print(analyzer.FormatMessageFlowDOT(analysis))
+		return nil
+	case "mermaid":
+		fmt.Print(analyzer.FormatMessageFlowMermaid(analysis))
+		return nil
+	default:
+		return outputMessagesText(analysis)
+	}
+}
+
+func outputMessagesJSON(analysis analyzer.MessageFlowAnalysis) error {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(analysis)
+}
+
+// formatChannelBytes shows a channel's payload sizes, or "-" when none were recorded
+func formatChannelBytes(c analyzer.MessageChannel) string {
+	if c.TotalBytes == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%s (avg %s)", formatBytes(c.TotalBytes), formatBytes(int64(c.AvgBytes)))
+}
+
+func outputMessagesMarkdown(analysis analyzer.MessageFlowAnalysis) error {
+	md := strings.Builder{}
+
+	md.WriteString("# Message Flow Analysis\n\n")
+
+	md.WriteString("## Summary\n\n")
+	md.WriteString(fmt.Sprintf("- **Messages Sent**: %d\n", analysis.TotalSent))
+	md.WriteString(fmt.Sprintf("- **Messages Received**: %d\n", analysis.TotalReceived))
+	md.WriteString(fmt.Sprintf("- **Paired**: %d (%d unmatched sends, %d unmatched receives)\n", analysis.Paired, analysis.UnpairedSends, analysis.UnpairedReceives))
+	if analysis.TotalBytes > 0 {
+		md.WriteString(fmt.Sprintf("- **Payload**: %s\n", formatBytes(analysis.TotalBytes)))
+	}
+
+	if len(analysis.Channels) > 0 {
+		md.WriteString("\n## Channels\n\n")
+		md.WriteString("| From | To | Messages | Latency p50 | Latency p95 | Latency Max | Payload |\n")
+		md.WriteString("|------|----|----------|-------------|-------------|-------------|---------|\n")
+		for _, c := range analysis.Channels {
+			md.WriteString(fmt.Sprintf("| %s | %s | %d | %.2fms | %.2fms | %.2fms | %s |\n",
+				c.From, c.To, c.Messages, c.LatencyP50Ms, c.LatencyP95Ms, c.LatencyMaxMs, formatChannelBytes(c)))
+		}
+	}
+
+	if len(analysis.Threads) > 0 {
+		md.WriteString("\n## Threads\n\n")
+		md.WriteString("| Thread | Sent | Received |\n")
+		md.WriteString("|--------|------|----------|\n")
+		for _, t := range analysis.Threads {
+			md.WriteString(fmt.Sprintf("| %s | %d | %d |\n", t.Thread, t.Sent, t.Received))
+		}
+	}
+
+	if len(analysis.Channels) > 0 {
+		md.WriteString("\n## Graph\n\n")
+		md.WriteString("```mermaid\n")
+		md.WriteString(analyzer.FormatMessageFlowMermaid(analysis))
+		md.WriteString("```\n")
+	}
+
+	if len(analysis.Recommendations) > 0 {
+		md.WriteString("\n## Recommendations\n\n")
+		for _, r := range analysis.Recommendations {
+			md.WriteString(fmt.Sprintf("- 💡 %s\n", r))
+		}
+	}
+
+	fmt.Print(md.String())
+	return nil
+}
+
+func outputMessagesText(analysis analyzer.MessageFlowAnalysis) error {
+	fmt.Println("Message Flow Analysis")
+	fmt.Println(strings.Repeat("=", 60))
+	fmt.Println()
+
+	fmt.Printf("Messages Sent: %d\n", analysis.TotalSent)
+	fmt.Printf("Messages Received: %d\n", analysis.TotalReceived)
+	fmt.Printf("Paired: %d (%d unmatched sends, %d unmatched receives)\n", analysis.Paired, analysis.UnpairedSends, analysis.UnpairedReceives)
+	if analysis.TotalBytes > 0 {
+		fmt.Printf("Payload: %s\n", formatBytes(analysis.TotalBytes))
+	}
+	fmt.Println()
+
+	if len(analysis.Channels) > 0 {
+		fmt.Println("Channels:")
+		fmt.Println(strings.Repeat("-", 60))
+		for _, c := range analysis.Channels {
+			fmt.Printf("  %s -> %s\n", c.From, c.To)
+			fmt.Printf("    %d messages, latency min %.2fms / avg %.2fms / p50 %.2fms / p95 %.2fms / max %.2fms\n",
+				c.Messages, c.LatencyMinMs, c.LatencyAvgMs, c.LatencyP50Ms, c.LatencyP95Ms, c.LatencyMaxMs)
+			if c.TotalBytes > 0 {
+				fmt.Printf("    payload %s (max %s)\n", formatChannelBytes(c), formatBytes(c.MaxBytes))
+			}
+		}
+		fmt.Println()
+	}
+
+	if len(analysis.Threads) > 0 {
+		fmt.Println("Threads:")
+		fmt.Println(strings.Repeat("-", 60))
+		for _, t := range analysis.Threads {
+			fmt.Printf("  %-40s sent %5d  received %5d\n", truncateName(t.Thread, 40), t.Sent, t.Received)
+		}
+		fmt.Println()
+	}
+
+	if len(analysis.Recommendations) > 0 {
+		fmt.Println("Recommendations:")
+		fmt.Println(strings.Repeat("-", 60))
+		for _, r := range analysis.Recommendations {
+			fmt.Printf("  - %s\n", r)
+		}
+	}
+
+	return nil
+}
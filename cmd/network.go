@@ -0,0 +1,219 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/CedricHerzog/perfowl/internal/analyzer"
+	"github.com/CedricHerzog/perfowl/internal/chart"
+	"github.com/spf13/cobra"
+)
+
+var networkLimit int
+
+var networkCmd = &cobra.Command{
+	Use:   "network",
+	Short: "Analyze the network request waterfall",
+	Long: `Reconstructs network requests from Firefox Network markers and Chrome
+ResourceSendRequest/ResourceReceiveResponse/ResourceFinish events including:
+- Per-request DNS, connect, TLS, waiting (TTFB) and download timings
+- Render-blocking stylesheets and scripts requested before first paint
+- Critical request chains (requests that waited on each other)
+- Request count, transfer size and time per domain
+
+Use -o svg to print a waterfall chart instead.
+
+Example:
+  perfowl network --profile profile.json.gz
+  perfowl network --profile trace.json -o svg > waterfall.svg`,
+	RunE: runNetwork,
+}
+
+func init() {
+	rootCmd.AddCommand(networkCmd)
+	networkCmd.Flags().IntVarP(&networkLimit, "limit", "l", 50, "Maximum number of requests to report")
+}
+
+func runNetwork(cmd *cobra.Command, args []string) error {
+	if profilePath == "" {
+		return fmt.Errorf("profile path is required (use --profile or -p)")
+	}
+
+	profile, _, err := loadProfile(profilePath)
+	if err != nil {
+		return err
+	}
+
+	analysis := analyzer.AnalyzeNetwork(profile, networkLimit)
+
+	switch outputFormat {
+	case "json":
+		return outputNetworkJSON(analysis)
+	case "markdown":
+		return outputNetworkMarkdown(analysis)
+	case "svg":
+		fmt.Println(chart.GenerateWaterfallChart(analysis))
+		return nil
+	default:
+		return outputNetworkText(analysis)
+	}
+}
+
+func outputNetworkJSON(analysis analyzer.NetworkAnalysis) error {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(analysis)
+}
+
+func outputNetworkMarkdown(analysis analyzer.NetworkAnalysis) error {
+	md := strings.Builder{}
+
+	md.WriteString("# Network Analysis\n\n")
+
+	md.WriteString("## Summary\n\n")
+	md.WriteString(fmt.Sprintf("- **Requests**: %d (%d failed, %d from cache)\n", analysis.TotalRequests, analysis.FailedRequests, analysis.CachedRequests))
+	md.WriteString(fmt.Sprintf("- **Transferred**: %s\n", formatBytes(analysis.TransferBytes)))
+	md.WriteString(fmt.Sprintf("- **Span**: %.2fms - %.2fms\n", analysis.StartTime, analysis.EndTime))
+	md.WriteString(fmt.Sprintf("- **Render-Blocking Requests**: %d", analysis.RenderBlockingCount))
+	if analysis.RenderBlockingCount > 0 {
+		md.WriteString(fmt.Sprintf(" (last finished at %.2fms)", analysis.RenderBlockingEndMs))
+	}
+	md.WriteString("\n")
+	if analysis.FirstContentfulPaint > 0 {
+		md.WriteString(fmt.Sprintf("- **First Contentful Paint**: %.2fms\n", analysis.FirstContentfulPaint))
+	}
+	md.WriteString(fmt.Sprintf("- **Longest Critical Chain**: %.2fms\n", analysis.LongestChainMs))
+
+	if len(analysis.Requests) > 0 {
+		md.WriteString("\n## Requests\n\n")
+		md.WriteString("| Start | Duration | DNS | Connect | TLS | Waiting | Download | Size | Type | URL |\n")
+		md.WriteString("|-------|----------|-----|---------|-----|---------|----------|------|------|-----|\n")
+		for _, r := range analysis.Requests {
+			url := r.URL
+			if r.RenderBlocking {
+				url = "⚠️ " + url
+			}
+			if r.Failed {
+				url = "❌ " + url
+			}
+			md.WriteString(fmt.Sprintf("| %.2fms | %.2fms | %.1f | %.1f | %.1f | %.1f | %.1f | %s | %s | `%s` |\n",
+				r.StartTime, r.DurationMs, r.DNSMs, r.ConnectMs, r.TLSMs, r.WaitingMs, r.DownloadMs,
+				formatBytes(r.TransferBytes), r.Type, url))
+		}
+	}
+
+	if len(analysis.CriticalChains) > 0 {
+		md.WriteString("\n## Critical Request Chains\n\n")
+		for _, c := range analysis.CriticalChains {
+			md.WriteString(fmt.Sprintf("- 🔶 **%.2fms** (%.2fms - %.2fms)\n", c.DurationMs, c.StartTime, c.EndTime))
+			for _, u := range c.URLs {
+				md.WriteString(fmt.Sprintf("  - 🔹 `%s`\n", u))
+			}
+		}
+	}
+
+	if len(analysis.ByDomain) > 0 {
+		md.WriteString("\n## By Domain\n\n")
+		md.WriteString("| Domain | Requests | Size | Total Time | Avg | Max | Blocking |\n")
+		md.WriteString("|--------|----------|------|------------|-----|-----|----------|\n")
+		for _, d := range analysis.ByDomain {
+			md.WriteString(fmt.Sprintf("| %s | %d | %s | %.2fms | %.2fms | %.2fms | %d |\n",
+				d.Domain, d.Requests, formatBytes(d.TransferBytes), d.TotalTimeMs, d.AvgDurationMs, d.MaxDurationMs, d.RenderBlocking))
+		}
+	}
+
+	if len(analysis.Recommendations) > 0 {
+		md.WriteString("\n## Recommendations\n\n")
+		for _, r := range analysis.Recommendations {
+			md.WriteString(fmt.Sprintf("- 💡 %s\n", r))
+		}
+	}
+
+	fmt.Print(md.String())
+	return nil
+}
+
+func outputNetworkText(analysis analyzer.NetworkAnalysis) error {
+	fmt.Println("Network Analysis")
+	fmt.Println(strings.Repeat("=", 60))
+	fmt.Println()
+
+	fmt.Printf("Requests: %d (%d failed, %d from cache)\n", analysis.TotalRequests, analysis.FailedRequests, analysis.CachedRequests)
+	fmt.Printf("Transferred: %s\n", formatBytes(analysis.TransferBytes))
+	fmt.Printf("Render-Blocking: %d", analysis.RenderBlockingCount)
+	if analysis.RenderBlockingCount > 0 {
+		fmt.Printf(" (last finished at %.2fms)", analysis.RenderBlockingEndMs)
+	}
+	fmt.Println()
+	if analysis.FirstContentfulPaint > 0 {
+		fmt.Printf("First Contentful Paint: %.2fms\n", analysis.FirstContentfulPaint)
+	}
+	fmt.Printf("Longest Critical Chain: %.2fms\n", analysis.LongestChainMs)
+	fmt.Println()
+
+	if len(analysis.Requests) > 0 {
+		fmt.Println("Waterfall:")
+		fmt.Println(strings.Repeat("-", 60))
+		for _, r := range analysis.Requests {
+			flags := ""
+			if r.RenderBlocking {
+				flags += " [blocking]"
+			}
+			if r.Failed {
+				flags += " [failed]"
+			}
+			if r.FromCache {
+				flags += " [cache]"
+			}
+			fmt.Printf("  %9.2fms %8.2fms  %-10s %s%s\n", r.StartTime, r.DurationMs, r.Type, truncateName(r.URL, 70), flags)
+			fmt.Printf("      dns %.1f  connect %.1f  tls %.1f  waiting %.1f  download %.1f  (%s)\n",
+				r.DNSMs, r.ConnectMs, r.TLSMs, r.WaitingMs, r.DownloadMs, formatBytes(r.TransferBytes))
+		}
+		fmt.Println()
+	}
+
+	if len(analysis.CriticalChains) > 0 {
+		fmt.Println("Critical Request Chains:")
+		fmt.Println(strings.Repeat("-", 60))
+		for _, c := range analysis.CriticalChains {
+			fmt.Printf("  %.2fms (%.2fms - %.2fms)\n", c.DurationMs, c.StartTime, c.EndTime)
+			for i, u := range c.URLs {
+				fmt.Printf("    %s%s\n", strings.Repeat("  ", i), truncateName(u, 70))
+			}
+		}
+		fmt.Println()
+	}
+
+	if len(analysis.ByDomain) > 0 {
+		fmt.Println("By Domain:")
+		fmt.Println(strings.Repeat("-", 60))
+		for _, d := range analysis.ByDomain {
+			fmt.Printf("  %-30s %3d requests  %10s  %9.2fms total  %8.2fms max\n",
+				truncateName(d.Domain, 30), d.Requests, formatBytes(d.TransferBytes), d.TotalTimeMs, d.MaxDurationMs)
+		}
+		fmt.Println()
+	}
+
+	if len(analysis.Recommendations) > 0 {
+		fmt.Println("Recommendations:")
+		for _, r := range analysis.Recommendations {
+			fmt.Printf("  - %s\n", r)
+		}
+	}
+
+	return nil
+}
+
+// formatBytes renders a byte count with a binary unit
+func formatBytes(n int64) string {
+	switch {
+	case n >= 1<<20:
+		return fmt.Sprintf("%.1f MiB", float64(n)/(1<<20))
+	case n >= 1<<10:
+		return fmt.Sprintf("%.1f KiB", float64(n)/(1<<10))
+	default:
+		return fmt.Sprintf("%d B", n)
+	}
+}
@@ -0,0 +1,506 @@
+package cmd
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/CedricHerzog/perfowl/internal/analyzer"
+	"github.com/CedricHerzog/perfowl/internal/chart"
+	"github.com/CedricHerzog/perfowl/internal/parser"
+	"github.com/CedricHerzog/perfowl/internal/report"
+	"github.com/spf13/cobra"
+)
+
+var (
+	reportBaseline    string
+	reportBatchConfig string
+	reportTitle       string
+	reportLimit       int
+)
+
+// reportMaxRequests caps the requests drawn in the report's network waterfall
+const reportMaxRequests = 50
+
+var reportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "Generate a self-contained HTML report",
+	Long: `Writes a single HTML file that can be opened offline and shared: styles,
+charts and scripts are inlined and nothing is loaded from a CDN.
+
+The report contains:
+- Profile summary and the performance score
+- Bottlenecks with their recommendations
+- Category breakdown and the top functions with hot paths
+- Flame graph, thread concurrency timeline and network waterfall
+- Worker and contention findings
+- With --baseline, a comparison against the baseline profile
+- With --batch, the scaling chart of a batch config (see perfowl batch)
+
+Every section is collapsible. -o names the HTML file to write; without it the
+report is written to stdout.
+
+Example:
+  perfowl report -p profile.json.gz -o report.html
+  perfowl report -p profile.json.gz --baseline main.json.gz -o report.html
+  perfowl report -p profile.json.gz --batch profiles.yaml --title "Decrypt scaling" -o report.html`,
+	RunE: runReport,
+}
+
+func init() {
+	rootCmd.AddCommand(reportCmd)
+	reportCmd.Flags().StringVar(&reportBaseline, "baseline", "", "Baseline profile to compare against")
+	reportCmd.Flags().StringVar(&reportBatchConfig, "batch", "", "Batch config file (YAML/JSON) for the scaling chart")
+	reportCmd.Flags().StringVar(&reportTitle, "title", "", "Report title (default: the profile file name)")
+	reportCmd.Flags().IntVarP(&reportLimit, "limit", "l", 20, "Maximum number of functions and hot paths to show")
+}
+
+// htmlChart is an SVG chart inlined in the report
+type htmlChart struct {
+	Title string
+	SVG   template.HTML
+}
+
+// htmlDiffRow is one metric of the baseline comparison
+type htmlDiffRow struct {
+	Metric   string
+	Baseline float64
+	Current  float64
+	Decimals int    // Counts are shown without decimals
+	Change   string // Percent change, or "new" when the baseline was zero
+	Class    string // improved, regressed or unchanged
+}
+
+// htmlBaseline is the baseline comparison section
+type htmlBaseline struct {
+	Path      string
+	Rows      []htmlDiffRow
+	Improved  []string
+	Regressed []string
+}
+
+// htmlReport holds everything the report template renders
+type htmlReport struct {
+	Title       string
+	Profile     string
+	Summary     report.Summary
+	Bottlenecks analyzer.BottleneckReport
+	Categories  analyzer.CategoryBreakdown
+	CallTree    analyzer.CallTreeAnalysis
+	Workers     analyzer.WorkerAnalysis
+	Contention  analyzer.ContentionAnalysis
+	Charts      []htmlChart
+	Scaling     []htmlChart
+	Baseline    *htmlBaseline
+}
+
+func runReport(cmd *cobra.Command, args []string) error {
+	if profilePath == "" {
+		return fmt.Errorf("profile path is required (use --profile or -p)")
+	}
+
+	// -o names the output file; the text default means stdout
+	outPath := ""
+	switch outputFormat {
+	case "", "text", "html", "-":
+	case "json", "markdown", "junit", "sarif":
+		return fmt.Errorf("report only writes HTML; use -o report.html")
+	default:
+		outPath = outputFormat
+	}
+
+	profile, detectedType, err := loadProfile(profilePath)
+	if err != nil {
+		return err
+	}
+
+	var baseline *parser.Profile
+	if reportBaseline != "" {
+		baseline, _, err = loadProfile(reportBaseline)
+		if err != nil {
+			return fmt.Errorf("baseline: %w", err)
+		}
+	}
+
+	var batch *analyzer.BatchAnalysisResult
+	if reportBatchConfig != "" {
+		entries, err := readBatchConfig(reportBatchConfig)
+		if err != nil {
+			return err
+		}
+		batch, err = analyzer.AnalyzeBatch(entries)
+		if err != nil {
+			return fmt.Errorf("batch analysis failed: %w", err)
+		}
+	}
+
+	page := buildHTMLReport(profile, detectedType, baseline, batch)
+
+	if outPath == "" {
+		return writeHTMLReport(os.Stdout, page)
+	}
+	f, err := os.Create(outPath)
+	if err != nil {
+		return fmt.Errorf("failed to create report file: %w", err)
+	}
+	if err := writeHTMLReport(f, page); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write report file: %w", err)
+	}
+	fmt.Printf("Report saved to: %s\n", outPath)
+	return nil
+}
+
+// buildHTMLReport runs the analyses shown in the report
+func buildHTMLReport(profile *parser.Profile, bt parser.BrowserType, baseline *parser.Profile, batch *analyzer.BatchAnalysisResult) htmlReport {
+	page := htmlReport{
+		Title:       reportTitle,
+		Profile:     filepath.Base(profilePath),
+		Summary:     report.BuildSummary(profile, bt),
+		Bottlenecks: report.Bottlenecks(profile, ""),
+		Categories:  report.Categories(profile, ""),
+		CallTree:    report.CallTree(profile, "", reportLimit),
+		Workers:     analyzer.AnalyzeWorkers(profile),
+		Contention:  analyzer.AnalyzeContention(profile),
+	}
+	if page.Title == "" {
+		page.Title = page.Profile
+	}
+
+	if flame := analyzer.BuildFlameGraph(profile, "", analyzer.FlameDefaultMinPercent); flame.TotalMs > 0 {
+		page.Charts = append(page.Charts, htmlChart{"Flame Graph", inlineSVG(chart.GenerateFlameGraph(flame))})
+	}
+	if concurrency := analyzer.AnalyzeConcurrency(profile, 0); len(concurrency.Timeline) > 0 {
+		page.Charts = append(page.Charts, htmlChart{"Thread Timeline", inlineSVG(chart.GenerateConcurrencyChart(concurrency))})
+	}
+	if network := analyzer.AnalyzeNetwork(profile, reportMaxRequests); len(network.Requests) > 0 {
+		page.Charts = append(page.Charts, htmlChart{"Network Waterfall", inlineSVG(chart.GenerateWaterfallChart(network))})
+	}
+
+	if batch != nil {
+		for _, c := range []struct {
+			title string
+			kind  chart.ChartType
+		}{
+			{"Wall Clock", chart.ChartWallClock},
+			{"Speedup", chart.ChartSpeedup},
+			{"Efficiency", chart.ChartEfficiency},
+		} {
+			page.Scaling = append(page.Scaling, htmlChart{c.title, inlineSVG(chart.GenerateScalingChart(batch, c.kind))})
+		}
+	}
+
+	if baseline != nil {
+		page.Baseline = buildHTMLBaseline(baseline, profile, page.Bottlenecks.Score)
+	}
+	return page
+}
+
+// buildHTMLBaseline compares the profile against the baseline, lower being
+// better for every metric but the score
+func buildHTMLBaseline(baseline, profile *parser.Profile, score int) *htmlBaseline {
+	diff := report.Compare(baseline, profile)
+	b, c := diff.Baseline, diff.Comparison
+
+	section := &htmlBaseline{
+		Path:      filepath.Base(reportBaseline),
+		Improved:  diff.Improved,
+		Regressed: diff.Regressed,
+	}
+	row := func(metric string, base, current float64, decimals int, higherIsBetter bool) {
+		r := htmlDiffRow{Metric: metric, Baseline: base, Current: current, Decimals: decimals, Class: "unchanged", Change: "0.0%"}
+		switch {
+		case base != 0:
+			r.Change = fmt.Sprintf("%+.1f%%", (current-base)/base*100)
+		case current != 0:
+			r.Change = "new"
+		}
+		switch {
+		case current == base:
+		case (current < base) != higherIsBetter:
+			r.Class = "improved"
+		default:
+			r.Class = "regressed"
+		}
+		section.Rows = append(section.Rows, r)
+	}
+	row("Score", float64(report.Bottlenecks(baseline, "").Score), float64(score), 0, true)
+	row("Duration (ms)", b.DurationMs, c.DurationMs, 1, false)
+	row("GC time (ms)", b.GCTotalTimeMs, c.GCTotalTimeMs, 1, false)
+	row("Major GCs", float64(b.GCMajorCount), float64(c.GCMajorCount), 0, false)
+	row("Minor GCs", float64(b.GCMinorCount), float64(c.GCMinorCount), 0, false)
+	row("Long tasks", float64(b.LongTaskCount), float64(c.LongTaskCount), 0, false)
+	row("Sync IPC", float64(b.SyncIPCCount), float64(c.SyncIPCCount), 0, false)
+	row("Layouts", float64(b.LayoutCount), float64(c.LayoutCount), 0, false)
+	return section
+}
+
+// inlineSVG drops the XML declaration so a chart can be embedded in HTML. The
+// charts escape every label they draw, including batch labels from the config.
+func inlineSVG(svg string) template.HTML {
+	if strings.HasPrefix(svg, "<?xml") {
+		if i := strings.Index(svg, "?>"); i >= 0 {
+			svg = strings.TrimLeft(svg[i+2:], "\n")
+		}
+	}
+	return template.HTML(svg)
+}
+
+// scoreClass rates a score for colouring
+func scoreClass(score int) string {
+	switch {
+	case score >= 80:
+		return "good"
+	case score >= 60:
+		return "fair"
+	default:
+		return "poor"
+	}
+}
+
+var reportFuncs = template.FuncMap{
+	"scoreClass": scoreClass,
+	"percentWidth": func(p float64) string {
+		if p > 100 {
+			p = 100
+		}
+		return fmt.Sprintf("%.1f%%", p)
+	},
+	"location": func(fn analyzer.FunctionStats) string {
+		if fn.File == "" {
+			return ""
+		}
+		if fn.Line > 0 {
+			return fmt.Sprintf("%s:%d", fn.File, fn.Line)
+		}
+		return fn.File
+	},
+}
+
+var reportTemplate = template.Must(template.New("report").Funcs(reportFuncs).Parse(reportHTML))
+
+// writeHTMLReport renders the report as one self-contained HTML document
+func writeHTMLReport(w io.Writer, page htmlReport) error {
+	if err := reportTemplate.Execute(w, page); err != nil {
+		return fmt.Errorf("failed to render report: %w", err)
+	}
+	return nil
+}
+
+const reportHTML = `<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Title}} - PerfOwl Report</title>
+<style>
+  body { font: 14px/1.5 system-ui, -apple-system, sans-serif; color: #222; background: #f5f5f5; margin: 0; }
+  main { max-width: 1080px; margin: 0 auto; padding: 24px; }
+  h1 { font-size: 24px; margin: 0 0 4px; }
+  .subtitle { color: #666; margin: 0 0 16px; }
+  .toolbar button { font: inherit; margin-right: 8px; padding: 4px 10px; border: 1px solid #ccc; border-radius: 4px; background: #fff; cursor: pointer; }
+  details { background: #fff; border: 1px solid #ddd; border-radius: 6px; margin: 16px 0; }
+  summary { font-size: 17px; font-weight: 600; padding: 12px 16px; cursor: pointer; }
+  .section { padding: 0 16px 16px; overflow-x: auto; }
+  table { border-collapse: collapse; width: 100%; }
+  th, td { text-align: left; padding: 6px 8px; border-bottom: 1px solid #eee; vertical-align: top; }
+  th { background: #fafafa; font-weight: 600; }
+  td.num, th.num { text-align: right; font-variant-numeric: tabular-nums; white-space: nowrap; }
+  .mono { font-family: ui-monospace, monospace; font-size: 12px; word-break: break-all; }
+  .score { display: inline-block; font-size: 40px; font-weight: 700; padding: 4px 18px; border-radius: 8px; color: #fff; }
+  .score.good { background: #43A047; } .score.fair { background: #FB8C00; } .score.poor { background: #E53935; }
+  .grid { display: grid; grid-template-columns: repeat(auto-fill, minmax(220px, 1fr)); gap: 8px 24px; }
+  .grid div span { display: block; color: #666; font-size: 12px; }
+  .sev { font-weight: 600; text-transform: uppercase; font-size: 12px; }
+  .sev.high, .regressed { color: #C62828; } .sev.medium { color: #EF6C00; } .sev.low { color: #1565C0; }
+  .improved { color: #2E7D32; } .unchanged { color: #666; }
+  .bar { background: #eee; border-radius: 3px; height: 10px; min-width: 120px; }
+  .bar div { background: #42A5F5; height: 10px; border-radius: 3px; }
+  .rec { margin: 4px 0 0; color: #555; }
+  svg { max-width: 100%; height: auto; }
+  ul { margin: 4px 0; padding-left: 20px; }
+</style>
+</head>
+<body>
+<main>
+<h1>{{.Title}}</h1>
+<p class="subtitle">PerfOwl report for {{.Profile}}</p>
+<div class="toolbar">
+  <button type="button" onclick="document.querySelectorAll('details').forEach(function(d){d.open=true})">Expand all</button>
+  <button type="button" onclick="document.querySelectorAll('details').forEach(function(d){d.open=false})">Collapse all</button>
+</div>
+
+<details open id="summary">
+<summary>Summary</summary>
+<div class="section">
+<p><span class="score {{scoreClass .Bottlenecks.Score}}">{{.Bottlenecks.Score}}</span> / 100</p>
+<p>{{.Bottlenecks.Summary}}</p>
+<div class="grid">
+  <div><span>Browser</span>{{.Summary.BrowserType}}</div>
+  <div><span>Duration</span>{{printf "%.2f" .Summary.DurationSeconds}} s</div>
+  <div><span>Platform</span>{{.Summary.Platform}} {{.Summary.OSCPU}}</div>
+  <div><span>Product</span>{{.Summary.Product}} {{.Summary.BuildID}}</div>
+  <div><span>CPU</span>{{.Summary.CPUName}} ({{.Summary.PhysicalCPUs}} physical, {{.Summary.LogicalCPUs}} logical)</div>
+  <div><span>Threads</span>{{.Summary.ThreadCount}} ({{.Summary.MainThreadCount}} main)</div>
+  <div><span>Samples</span>{{.Summary.TotalSamples}}</div>
+  <div><span>Markers</span>{{.Summary.TotalMarkers}}</div>
+  <div><span>Extensions</span>{{.Summary.ExtensionCount}}</div>
+</div>
+{{- range .Summary.PageLoad}}
+<h3>Page load{{if .URL}}: <span class="mono">{{.URL}}</span>{{end}}</h3>
+<table>
+<tr><th>Milestone</th><th class="num">Since navigation</th><th>Source</th></tr>
+{{- range .Milestones}}
+<tr><td>{{.Name}}</td><td class="num">{{printf "%.1f" .SinceNavigationMs}} ms</td><td>{{.Source}}</td></tr>
+{{- end}}
+</table>
+{{- end}}
+</div>
+</details>
+
+<details open id="bottlenecks">
+<summary>Bottlenecks ({{len .Bottlenecks.Bottlenecks}})</summary>
+<div class="section">
+{{- if .Bottlenecks.Bottlenecks}}
+<table>
+<tr><th>Severity</th><th>Bottleneck</th><th class="num">Count</th><th class="num">Total</th><th>Details</th></tr>
+{{- range .Bottlenecks.Bottlenecks}}
+<tr>
+  <td><span class="sev {{.Severity}}">{{.Severity}}</span></td>
+  <td>{{.Type}}</td>
+  <td class="num">{{.Count}}</td>
+  <td class="num">{{printf "%.1f" .TotalDuration}} ms</td>
+  <td>{{.Description}}{{if .Recommendation}}<p class="rec">💡 {{.Recommendation}}</p>{{end}}
+  {{- if .Locations}}<ul class="mono">{{range .Locations}}<li>{{.}}</li>{{end}}</ul>{{end}}</td>
+</tr>
+{{- end}}
+</table>
+{{- else}}
+<p>No bottlenecks detected.</p>
+{{- end}}
+</div>
+</details>
+
+{{- if .Baseline}}
+<details open id="baseline">
+<summary>Baseline Comparison</summary>
+<div class="section">
+<p>Compared against <span class="mono">{{.Baseline.Path}}</span>.</p>
+<table>
+<tr><th>Metric</th><th class="num">Baseline</th><th class="num">Current</th><th class="num">Change</th></tr>
+{{- range .Baseline.Rows}}
+<tr><td>{{.Metric}}</td><td class="num">{{printf "%.*f" .Decimals .Baseline}}</td><td class="num">{{printf "%.*f" .Decimals .Current}}</td><td class="num {{.Class}}">{{.Change}}</td></tr>
+{{- end}}
+</table>
+{{- if .Baseline.Regressed}}
+<h3>Regressions</h3>
+<ul>{{range .Baseline.Regressed}}<li class="regressed">{{.}}</li>{{end}}</ul>
+{{- end}}
+{{- if .Baseline.Improved}}
+<h3>Improvements</h3>
+<ul>{{range .Baseline.Improved}}<li class="improved">{{.}}</li>{{end}}</ul>
+{{- end}}
+</div>
+</details>
+{{- end}}
+
+<details open id="categories">
+<summary>Categories</summary>
+<div class="section">
+{{- if .Categories.Categories}}
+<table>
+<tr><th>Category</th><th class="num">Time</th><th class="num">Share</th><th></th></tr>
+{{- range .Categories.Categories}}
+<tr><td>{{.Name}}</td><td class="num">{{printf "%.1f" .TimeMs}} ms</td><td class="num">{{printf "%.1f%%" .Percent}}</td><td><div class="bar"><div style="width: {{percentWidth .Percent}}"></div></div></td></tr>
+{{- end}}
+</table>
+{{- else}}
+<p>No CPU samples recorded.</p>
+{{- end}}
+</div>
+</details>
+
+<details open id="functions">
+<summary>Top Functions</summary>
+<div class="section">
+{{- if .CallTree.TopFunctions}}
+<table>
+<tr><th>Function</th><th class="num">Self</th><th class="num">Self %</th><th class="num">Running</th><th>Location</th></tr>
+{{- range .CallTree.TopFunctions}}
+<tr><td class="mono">{{.Name}}</td><td class="num">{{printf "%.1f" .SelfTimeMs}} ms</td><td class="num">{{printf "%.1f%%" .SelfPercent}}</td><td class="num">{{printf "%.1f" .RunningTimeMs}} ms</td><td class="mono">{{location .}}</td></tr>
+{{- end}}
+</table>
+{{- else}}
+<p>No CPU samples recorded.</p>
+{{- end}}
+{{- if .CallTree.HotPaths}}
+<h3>Hot Paths</h3>
+<table>
+<tr><th class="num">Share</th><th class="num">Time</th><th>Path</th></tr>
+{{- range .CallTree.HotPaths}}
+<tr><td class="num">{{printf "%.1f%%" .Percent}}</td><td class="num">{{printf "%.1f" .SelfTimeMs}} ms</td><td class="mono">{{.Path}}</td></tr>
+{{- end}}
+</table>
+{{- end}}
+</div>
+</details>
+
+{{- range .Charts}}
+<details open>
+<summary>{{.Title}}</summary>
+<div class="section">{{.SVG}}</div>
+</details>
+{{- end}}
+
+{{- if .Scaling}}
+<details open id="scaling">
+<summary>Scaling</summary>
+<div class="section">
+{{- range .Scaling}}
+<h3>{{.Title}}</h3>
+{{.SVG}}
+{{- end}}
+</div>
+</details>
+{{- end}}
+
+<details open id="workers">
+<summary>Workers ({{.Workers.TotalWorkers}})</summary>
+<div class="section">
+{{- if .Workers.Workers}}
+<p>{{.Workers.ActiveWorkers}} active, {{printf "%.1f" .Workers.TotalCPUTimeMs}} ms CPU, {{printf "%.1f%%" .Workers.OverallEfficiency}} efficiency.</p>
+<table>
+<tr><th>Worker</th><th class="num">CPU</th><th class="num">Idle</th><th class="num">Active</th><th class="num">Sent</th><th class="num">Received</th></tr>
+{{- range .Workers.Workers}}
+<tr><td>{{.ThreadName}}</td><td class="num">{{printf "%.1f" .CPUTimeMs}} ms</td><td class="num">{{printf "%.1f" .IdleTimeMs}} ms</td><td class="num">{{printf "%.1f%%" .ActivePercent}}</td><td class="num">{{.MessagesSent}}</td><td class="num">{{.MessagesReceived}}</td></tr>
+{{- end}}
+</table>
+{{- else}}
+<p>No worker threads found.</p>
+{{- end}}
+{{- if .Workers.Warnings}}
+<ul>{{range .Workers.Warnings}}<li>⚠️ {{.}}</li>{{end}}</ul>
+{{- end}}
+</div>
+</details>
+
+<details open id="contention">
+<summary>Contention</summary>
+<div class="section">
+<p>Severity <span class="sev {{.Contention.Severity}}">{{.Contention.Severity}}</span>: {{.Contention.TotalEvents}} events, {{printf "%.1f" .Contention.TotalImpactMs}} ms impact
+({{.Contention.GCContention}} GC, {{.Contention.IPCContention}} IPC, {{.Contention.LockContention}} lock).</p>
+{{- if .Contention.Recommendations}}
+<ul>{{range .Contention.Recommendations}}<li>💡 {{.}}</li>{{end}}</ul>
+{{- end}}
+</div>
+</details>
+</main>
+</body>
+</html>
+`
@@ -0,0 +1,209 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/CedricHerzog/perfowl/internal/analyzer"
+	"github.com/spf13/cobra"
+)
+
+var (
+	resourcesFirstParty []string
+	resourcesLimit      int
+)
+
+var resourcesCmd = &cobra.Command{
+	Use:   "resources",
+	Short: "Attribute CPU time to scripts, origins, third parties and libraries",
+	Long: `Rolls sample self and total time up by resource including:
+- First-party vs third-party vs extension vs browser time
+- Time per origin (host) and per script
+- Native time per library
+
+Self time goes to the resource of the innermost frame, total time to every
+resource on the stack, so third-party total time includes the layout or GC
+work its scripts trigger.
+
+Pass the page's own domains with --first-party (subdomains match):
+  perfowl resources -p profile.json.gz --first-party example.com,examplecdn.net`,
+	RunE: runResources,
+}
+
+func init() {
+	rootCmd.AddCommand(resourcesCmd)
+	resourcesCmd.Flags().StringSliceVar(&resourcesFirstParty, "first-party", nil, "First-party domains (comma-separated)")
+	resourcesCmd.Flags().IntVarP(&resourcesLimit, "limit", "l", 20, "Maximum number of resources, origins and libraries to report")
+}
+
+func runResources(cmd *cobra.Command, args []string) error {
+	if profilePath == "" {
+		return fmt.Errorf("profile path is required (use --profile or -p)")
+	}
+
+	profile, _, err := loadProfile(profilePath)
+	if err != nil {
+		return err
+	}
+
+	analysis := analyzer.AnalyzeResources(profile, analyzer.ResourceOptions{
+		FirstPartyDomains: resourcesFirstParty,
+		Limit:             resourcesLimit,
+	})
+
+	switch outputFormat {
+	case "json":
+		return outputResourcesJSON(analysis)
+	case "markdown":
+		return outputResourcesMarkdown(analysis)
+	default:
+		return outputResourcesText(analysis)
+	}
+}
+
+func outputResourcesJSON(analysis analyzer.ResourceAnalysis) error {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(analysis)
+}
+
+func outputResourcesMarkdown(analysis analyzer.ResourceAnalysis) error {
+	md := strings.Builder{}
+
+	md.WriteString("# Resource Attribution\n\n")
+
+	md.WriteString("## Summary\n\n")
+	md.WriteString(fmt.Sprintf("- **Total CPU Time**: %.2f ms\n", analysis.TotalTimeMs))
+	if len(analysis.FirstPartyDomains) > 0 {
+		md.WriteString(fmt.Sprintf("- **First-Party Domains**: %s\n", strings.Join(analysis.FirstPartyDomains, ", ")))
+	}
+
+	if len(analysis.Parties) > 0 {
+		md.WriteString("\n## Time by Party\n\n")
+		md.WriteString("| Party | Self | Self % | Total | Total % |\n")
+		md.WriteString("|-------|------|--------|-------|---------|\n")
+		for _, ps := range analysis.Parties {
+			md.WriteString(fmt.Sprintf("| %s | %.2fms | %.1f%% | %.2fms | %.1f%% |\n",
+				ps.Party, ps.SelfTimeMs, ps.SelfPercent, ps.TotalTimeMs, ps.TotalPercent))
+		}
+	}
+
+	if len(analysis.Origins) > 0 {
+		md.WriteString("\n## Origins\n\n")
+		md.WriteString("| Origin | Party | Self | Total | Resources |\n")
+		md.WriteString("|--------|-------|------|-------|-----------|\n")
+		for _, os := range analysis.Origins {
+			md.WriteString(fmt.Sprintf("| `%s` | %s | %.2fms | %.2fms | %d |\n",
+				os.Origin, os.Party, os.SelfTimeMs, os.TotalTimeMs, os.ResourceCount))
+		}
+	}
+
+	if len(analysis.Resources) > 0 {
+		md.WriteString("\n## Resources\n\n")
+		md.WriteString("| Resource | Type | Party | Self | Total | Self % |\n")
+		md.WriteString("|----------|------|-------|------|-------|--------|\n")
+		for _, rs := range analysis.Resources {
+			md.WriteString(fmt.Sprintf("| `%s` | %s | %s | %.2fms | %.2fms | %.1f%% |\n",
+				rs.Name, rs.Type, rs.Party, rs.SelfTimeMs, rs.TotalTimeMs, rs.SelfPercent))
+		}
+	}
+
+	if len(analysis.Libraries) > 0 {
+		md.WriteString("\n## Native Libraries\n\n")
+		md.WriteString("| Library | Self | Total | Self % |\n")
+		md.WriteString("|---------|------|-------|--------|\n")
+		for _, ls := range analysis.Libraries {
+			md.WriteString(fmt.Sprintf("| `%s` | %.2fms | %.2fms | %.1f%% |\n",
+				ls.Name, ls.SelfTimeMs, ls.TotalTimeMs, ls.SelfPercent))
+		}
+	}
+
+	if len(analysis.ByThread) > 0 {
+		md.WriteString("\n## By Thread\n\n")
+		for _, name := range sortedThreadNames(analysis.ByThread) {
+			parts := make([]string, 0, len(analysis.ByThread[name]))
+			for _, ps := range analysis.ByThread[name] {
+				parts = append(parts, fmt.Sprintf("%s %.1f%%", ps.Party, ps.SelfPercent))
+			}
+			md.WriteString(fmt.Sprintf("- **%s**: %s\n", name, strings.Join(parts, ", ")))
+		}
+	}
+
+	if len(analysis.Notes) > 0 {
+		md.WriteString("\n## Notes\n\n")
+		for _, n := range analysis.Notes {
+			md.WriteString(fmt.Sprintf("- ℹ️ %s\n", n))
+		}
+	}
+
+	fmt.Print(md.String())
+	return nil
+}
+
+func outputResourcesText(analysis analyzer.ResourceAnalysis) error {
+	fmt.Println("Resource Attribution")
+	fmt.Println(strings.Repeat("=", 60))
+	fmt.Println()
+
+	fmt.Printf("Total CPU Time: %.2f ms\n", analysis.TotalTimeMs)
+	if len(analysis.FirstPartyDomains) > 0 {
+		fmt.Printf("First-Party Domains: %s\n", strings.Join(analysis.FirstPartyDomains, ", "))
+	}
+	fmt.Println()
+
+	if len(analysis.Parties) > 0 {
+		fmt.Println("Time by Party (self / total):")
+		for _, ps := range analysis.Parties {
+			fmt.Printf("  %-14s %10.2f ms %5.1f%%  %10.2f ms %5.1f%%\n",
+				ps.Party, ps.SelfTimeMs, ps.SelfPercent, ps.TotalTimeMs, ps.TotalPercent)
+		}
+		fmt.Println()
+	}
+
+	if len(analysis.Origins) > 0 {
+		fmt.Println("Origins (self / total):")
+		for _, os := range analysis.Origins {
+			fmt.Printf("  %-40s %-13s %8.2f ms  %8.2f ms\n",
+				truncateName(os.Origin, 40), os.Party, os.SelfTimeMs, os.TotalTimeMs)
+		}
+		fmt.Println()
+	}
+
+	if len(analysis.Resources) > 0 {
+		fmt.Println("Resources (self / total):")
+		for _, rs := range analysis.Resources {
+			fmt.Printf("  %-50s %8.2f ms  %8.2f ms\n", truncateName(rs.Name, 50), rs.SelfTimeMs, rs.TotalTimeMs)
+		}
+		fmt.Println()
+	}
+
+	if len(analysis.Libraries) > 0 {
+		fmt.Println("Native Libraries (self / total):")
+		for _, ls := range analysis.Libraries {
+			fmt.Printf("  %-40s %8.2f ms  %8.2f ms\n", truncateName(ls.Name, 40), ls.SelfTimeMs, ls.TotalTimeMs)
+		}
+		fmt.Println()
+	}
+
+	if len(analysis.Notes) > 0 {
+		fmt.Println("Notes:")
+		for _, n := range analysis.Notes {
+			fmt.Printf("  - %s\n", n)
+		}
+	}
+
+	return nil
+}
+
+// sortedThreadNames returns the keys of a per-thread map in sorted order
+func sortedThreadNames[T any](byThread map[string]T) []string {
+	names := make([]string, 0, len(byThread))
+	for name := range byThread {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -4,14 +4,20 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/CedricHerzog/perfowl/internal/analyzer"
+	"github.com/CedricHerzog/perfowl/internal/parser"
 	"github.com/CedricHerzog/perfowl/internal/version"
 	"github.com/spf13/cobra"
 )
 
 var (
-	profilePath  string
-	outputFormat string
-	browserType  string
+	profilePath     string
+	outputFormat    string
+	browserType     string
+	timeRange       string
+	markerTimeRange string
+	threadFilter    string
+	sourceRoot      string
 )
 
 var rootCmd = &cobra.Command{
@@ -42,6 +48,86 @@ func Execute() {
 
 func init() {
 	rootCmd.PersistentFlags().StringVarP(&profilePath, "profile", "p", "", "Path to browser profile JSON (gzip supported)")
-	rootCmd.PersistentFlags().StringVarP(&outputFormat, "output", "o", "text", "Output format: text, json, markdown")
+	rootCmd.PersistentFlags().StringVarP(&outputFormat, "output", "o", "text", "Output format: text, json, markdown (junit, sarif for analyze, check, bottlenecks, contention, crypto, workers)")
 	rootCmd.PersistentFlags().StringVarP(&browserType, "browser", "b", "auto", "Browser type: auto, firefox, chrome")
+	rootCmd.PersistentFlags().StringVar(&timeRange, "range", "", "Only analyze samples and markers in this time range (e.g. 1200ms-3400ms)")
+	rootCmd.PersistentFlags().StringVar(&markerTimeRange, "range-from-markers", "", `Only analyze the range between two marker patterns (e.g. "DOMEvent:click..Paint")`)
+	rootCmd.PersistentFlags().StringVar(&threadFilter, "threads", "", `Only analyze matching threads (e.g. "GeckoMain&process:tab,!pid:1234")`)
+	rootCmd.PersistentFlags().StringVar(&sourceRoot, "source-root", "", "Source directory that script URLs in -o sarif locations are resolved against")
+}
+
+// loadProfile loads the profile at path and applies the global range and thread selection
+func loadProfile(path string) (*parser.Profile, parser.BrowserType, error) {
+	bt := parser.ParseBrowserType(browserType)
+	profile, detectedType, err := parser.LoadProfileWithType(path, bt)
+	if err != nil {
+		return nil, detectedType, fmt.Errorf("failed to load profile: %w", err)
+	}
+
+	profile, err = applyScope(profile)
+	if err != nil {
+		return nil, detectedType, err
+	}
+
+	return profile, detectedType, nil
+}
+
+// applyScope applies the --range, --range-from-markers and --threads selections
+func applyScope(profile *parser.Profile) (*parser.Profile, error) {
+	profile, err := applyTimeRange(profile)
+	if err != nil {
+		return nil, err
+	}
+	return applyThreadFilter(profile)
+}
+
+// applyTimeRange restricts the profile to the range selected by --range or --range-from-markers
+func applyTimeRange(profile *parser.Profile) (*parser.Profile, error) {
+	if timeRange != "" && markerTimeRange != "" {
+		return nil, fmt.Errorf("--range and --range-from-markers cannot be used together")
+	}
+
+	var r parser.TimeRange
+	switch {
+	case timeRange != "":
+		var err error
+		if r, err = parser.ParseTimeRange(timeRange); err != nil {
+			return nil, err
+		}
+	case markerTimeRange != "":
+		startPattern, endPattern, err := analyzer.ParseMarkerRange(markerTimeRange)
+		if err != nil {
+			return nil, err
+		}
+		if r, err = analyzer.ResolveMarkerRange(profile, startPattern, endPattern); err != nil {
+			return nil, fmt.Errorf("failed to resolve marker range: %w", err)
+		}
+	default:
+		return profile, nil
+	}
+
+	sliced := profile.Slice(r)
+	if sliced.Duration() <= 0 {
+		return nil, fmt.Errorf("range %s does not overlap the profile (%s)", r,
+			parser.TimeRange{StartMs: profile.Meta.ProfilingStartTime, EndMs: profile.Meta.ProfilingEndTime})
+	}
+	return sliced, nil
+}
+
+// applyThreadFilter restricts the profile to the threads selected by --threads
+func applyThreadFilter(profile *parser.Profile) (*parser.Profile, error) {
+	if threadFilter == "" {
+		return profile, nil
+	}
+
+	sel, err := parser.ParseThreadSelector(threadFilter)
+	if err != nil {
+		return nil, err
+	}
+
+	selected := profile.SelectThreads(sel)
+	if len(selected.Threads) == 0 {
+		return nil, fmt.Errorf("no threads match selector %q", threadFilter)
+	}
+	return selected, nil
 }
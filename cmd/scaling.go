@@ -39,18 +39,21 @@ func runScaling(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("profile path is required (use --profile or -p)")
 	}
 
-	bt := parser.ParseBrowserType(browserType)
-	profile, _, err := parser.LoadProfileWithType(profilePath, bt)
+	profile, _, err := loadProfile(profilePath)
 	if err != nil {
-		return fmt.Errorf("failed to load profile: %w", err)
+		return err
 	}
 
 	// Check if we're doing a comparison
 	if compareProfile != "" {
-		compProfile, _, err := parser.LoadProfileWithType(compareProfile, bt)
+		compProfile, _, err := parser.LoadProfileWithType(compareProfile, parser.ParseBrowserType(browserType))
 		if err != nil {
 			return fmt.Errorf("failed to load comparison profile: %w", err)
 		}
+		compProfile, err = applyScope(compProfile)
+		if err != nil {
+			return err
+		}
 
 		comparison := analyzer.CompareScaling(profile, compProfile)
 
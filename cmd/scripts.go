@@ -0,0 +1,135 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/CedricHerzog/perfowl/internal/analyzer"
+	"github.com/spf13/cobra"
+)
+
+var scriptsLimit int
+
+var scriptsCmd = &cobra.Command{
+	Use:   "scripts",
+	Short: "Break JavaScript cost down per script into parse, compile and execute",
+	Long: `Attributes JavaScript load cost to each script URL including:
+- Parse and compile time (v8.parseOnBackground, v8.compile, v8.compileModule, Firefox parse/bytecode markers)
+- Top-level execution time (EvaluateScript, v8.evaluateModule), excluding nested compilation
+- Main thread versus background (streaming or off-thread) time
+- Transfer size, download time and download-to-eval delay from the script's network request
+
+Example:
+  perfowl scripts --profile trace.json --limit 10`,
+	RunE: runScripts,
+}
+
+func init() {
+	rootCmd.AddCommand(scriptsCmd)
+	scriptsCmd.Flags().IntVarP(&scriptsLimit, "limit", "l", 20, "Maximum number of scripts to report")
+}
+
+func runScripts(cmd *cobra.Command, args []string) error {
+	if profilePath == "" {
+		return fmt.Errorf("profile path is required (use --profile or -p)")
+	}
+
+	profile, _, err := loadProfile(profilePath)
+	if err != nil {
+		return err
+	}
+
+	analysis := analyzer.AnalyzeScriptCosts(profile, scriptsLimit)
+
+	switch outputFormat {
+	case "json":
+		return outputScriptsJSON(analysis)
+	case "markdown":
+		return outputScriptsMarkdown(analysis)
+	default:
+		return outputScriptsText(analysis)
+	}
+}
+
+func outputScriptsJSON(analysis analyzer.ScriptCostAnalysis) error {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(analysis)
+}
+
+func outputScriptsMarkdown(analysis analyzer.ScriptCostAnalysis) error {
+	md := strings.Builder{}
+
+	md.WriteString("# Script Cost Analysis\n\n")
+
+	md.WriteString("## Summary\n\n")
+	md.WriteString(fmt.Sprintf("- **Scripts**: %d\n", analysis.TotalScripts))
+	md.WriteString(fmt.Sprintf("- **Parse**: %.2fms\n", analysis.TotalParseMs))
+	md.WriteString(fmt.Sprintf("- **Compile**: %.2fms\n", analysis.TotalCompileMs))
+	md.WriteString(fmt.Sprintf("- **Execute**: %.2fms\n", analysis.TotalExecuteMs))
+	md.WriteString(fmt.Sprintf("- **Main Thread**: %.2fms (%.2fms parse/compile)\n", analysis.MainThreadMs, analysis.MainCompileMs))
+	md.WriteString(fmt.Sprintf("- **Background**: %.2fms\n", analysis.BackgroundMs))
+
+	if len(analysis.Scripts) > 0 {
+		md.WriteString("\n## Scripts\n\n")
+		md.WriteString("| Script | Size | Load→Eval | Parse | Compile | Execute | Main | Background |\n")
+		md.WriteString("|--------|------|-----------|-------|---------|---------|------|------------|\n")
+		for _, s := range analysis.Scripts {
+			notes := ""
+			if s.Streamed {
+				notes += " 🔹 streamed"
+			}
+			if s.CodeCacheBytes > 0 {
+				notes += " 🔹 cached"
+			}
+			md.WriteString(fmt.Sprintf("| `%s`%s | %s | %.2fms | %.2fms | %.2fms | %.2fms | %.2fms | %.2fms |\n",
+				s.URL, notes, formatBytes(s.TransferBytes), s.LoadToEvalMs, s.ParseMs, s.CompileMs, s.ExecuteMs, s.MainThreadMs, s.BackgroundMs))
+		}
+	}
+
+	if len(analysis.Recommendations) > 0 {
+		md.WriteString("\n## Recommendations\n\n")
+		for _, r := range analysis.Recommendations {
+			md.WriteString(fmt.Sprintf("- 💡 %s\n", r))
+		}
+	}
+
+	fmt.Print(md.String())
+	return nil
+}
+
+func outputScriptsText(analysis analyzer.ScriptCostAnalysis) error {
+	fmt.Println("Script Cost Analysis")
+	fmt.Println(strings.Repeat("=", 60))
+	fmt.Println()
+
+	fmt.Printf("Scripts: %d\n", analysis.TotalScripts)
+	fmt.Printf("Parse: %.2fms  Compile: %.2fms  Execute: %.2fms\n", analysis.TotalParseMs, analysis.TotalCompileMs, analysis.TotalExecuteMs)
+	fmt.Printf("Main Thread: %.2fms (%.2fms parse/compile)  Background: %.2fms\n", analysis.MainThreadMs, analysis.MainCompileMs, analysis.BackgroundMs)
+	fmt.Println()
+
+	if len(analysis.Scripts) > 0 {
+		fmt.Println("Scripts:")
+		fmt.Println(strings.Repeat("-", 60))
+		for _, s := range analysis.Scripts {
+			fmt.Printf("  %s\n", truncateName(s.URL, 70))
+			fmt.Printf("    parse %.2fms  compile %.2fms  execute %.2fms  (main %.2fms, background %.2fms)\n",
+				s.ParseMs, s.CompileMs, s.ExecuteMs, s.MainThreadMs, s.BackgroundMs)
+			if s.RequestStart > 0 || s.DownloadMs > 0 {
+				fmt.Printf("    %s downloaded in %.2fms, evaluated %.2fms after request\n", formatBytes(s.TransferBytes), s.DownloadMs, s.LoadToEvalMs)
+			}
+		}
+		fmt.Println()
+	}
+
+	if len(analysis.Recommendations) > 0 {
+		fmt.Println("Recommendations:")
+		for _, r := range analysis.Recommendations {
+			fmt.Printf("  - %s\n", r)
+		}
+	}
+
+	return nil
+}
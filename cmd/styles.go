@@ -0,0 +1,180 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/CedricHerzog/perfowl/internal/analyzer"
+	"github.com/spf13/cobra"
+)
+
+var stylesLimit int
+
+var stylesCmd = &cobra.Command{
+	Use:   "styles",
+	Short: "Analyze style recalculation cost and forced style flushes",
+	Long: `Analyzes style recalculation from Firefox Styles markers and Chrome
+UpdateLayoutTree events including:
+- Elements styled per recalc and the largest recalcs
+- Style cache reuse (Firefox style sharing and reuse)
+- Recalcs forced synchronously by JavaScript, grouped by the forcing function with its stack
+- The slowest CSS selectors when Chrome selector stats are enabled
+
+Example:
+  perfowl styles --profile profile.json.gz`,
+	RunE: runStyles,
+}
+
+func init() {
+	rootCmd.AddCommand(stylesCmd)
+	stylesCmd.Flags().IntVarP(&stylesLimit, "limit", "l", 10, "Maximum number of recalcs, culprits and selectors to report")
+}
+
+func runStyles(cmd *cobra.Command, args []string) error {
+	if profilePath == "" {
+		return fmt.Errorf("profile path is required (use --profile or -p)")
+	}
+
+	profile, _, err := loadProfile(profilePath)
+	if err != nil {
+		return err
+	}
+
+	analysis := analyzer.AnalyzeStyles(profile, stylesLimit)
+
+	switch outputFormat {
+	case "json":
+		return outputStylesJSON(analysis)
+	case "markdown":
+		return outputStylesMarkdown(analysis)
+	default:
+		return outputStylesText(analysis)
+	}
+}
+
+func outputStylesJSON(analysis analyzer.StyleAnalysis) error {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(analysis)
+}
+
+// formatReuseRatio renders the style cache reuse ratio, which is -1 when not reported
+func formatReuseRatio(ratio float64) string {
+	if ratio < 0 {
+		return "n/a"
+	}
+	return fmt.Sprintf("%.1f%%", ratio*100)
+}
+
+func outputStylesMarkdown(analysis analyzer.StyleAnalysis) error {
+	md := strings.Builder{}
+
+	md.WriteString("# Style Recalculation Analysis\n\n")
+
+	md.WriteString("## Summary\n\n")
+	md.WriteString(fmt.Sprintf("- **Recalcs**: %d (%.2fms, longest %.2fms)\n", analysis.TotalRecalcs, analysis.TotalMs, analysis.MaxMs))
+	md.WriteString(fmt.Sprintf("- **Forced by JavaScript**: %d (%.2fms)\n", analysis.ForcedRecalcs, analysis.ForcedMs))
+	md.WriteString(fmt.Sprintf("- **Elements Styled**: %d (%.1f per recalc, max %d)\n", analysis.ElementsStyled, analysis.AvgElementsPerRecalc, analysis.MaxElementsStyled))
+	md.WriteString(fmt.Sprintf("- **Style Cache Reuse**: %s\n", formatReuseRatio(analysis.CacheReuseRatio)))
+
+	if len(analysis.Culprits) > 0 {
+		md.WriteString("\n## Forced Recalcs\n\n")
+		for _, c := range analysis.Culprits {
+			md.WriteString(fmt.Sprintf("- ⚠️ `%s`: %d recalcs, %.2fms, %d elements\n", c.Function, c.Count, c.TotalMs, c.ElementsStyled))
+			for _, frame := range c.Stack {
+				md.WriteString(fmt.Sprintf("  - 🔹 `%s`\n", frame))
+			}
+		}
+	}
+
+	if len(analysis.SlowestRecalcs) > 0 {
+		md.WriteString("\n## Slowest Recalcs\n\n")
+		md.WriteString("| Time | Duration | Styled | Matched | Shared | Reused | Forced | Thread |\n")
+		md.WriteString("|------|----------|--------|---------|--------|--------|--------|--------|\n")
+		for _, r := range analysis.SlowestRecalcs {
+			forced := ""
+			if r.Forced {
+				forced = "yes"
+			}
+			md.WriteString(fmt.Sprintf("| %.2fms | %.2fms | %d | %d | %d | %d | %s | %s |\n",
+				r.StartTime, r.DurationMs, r.ElementsStyled, r.ElementsMatched, r.StylesShared, r.StylesReused, forced, r.Thread))
+		}
+	}
+
+	if len(analysis.Selectors) > 0 {
+		md.WriteString("\n## Slowest Selectors\n\n")
+		md.WriteString("| Selector | Time | Attempts | Matches | Fast Rejects |\n")
+		md.WriteString("|----------|------|----------|---------|--------------|\n")
+		for _, s := range analysis.Selectors {
+			md.WriteString(fmt.Sprintf("| `%s` | %.3fms | %d | %d | %d |\n", s.Selector, s.ElapsedMs, s.MatchAttempts, s.MatchCount, s.FastRejectCount))
+		}
+	}
+
+	if len(analysis.Recommendations) > 0 {
+		md.WriteString("\n## Recommendations\n\n")
+		for _, r := range analysis.Recommendations {
+			md.WriteString(fmt.Sprintf("- 💡 %s\n", r))
+		}
+	}
+
+	fmt.Print(md.String())
+	return nil
+}
+
+func outputStylesText(analysis analyzer.StyleAnalysis) error {
+	fmt.Println("Style Recalculation Analysis")
+	fmt.Println(strings.Repeat("=", 60))
+	fmt.Println()
+
+	fmt.Printf("Recalcs: %d (%.2fms, longest %.2fms)\n", analysis.TotalRecalcs, analysis.TotalMs, analysis.MaxMs)
+	fmt.Printf("Forced by JavaScript: %d (%.2fms)\n", analysis.ForcedRecalcs, analysis.ForcedMs)
+	fmt.Printf("Elements Styled: %d (%.1f per recalc, max %d)\n", analysis.ElementsStyled, analysis.AvgElementsPerRecalc, analysis.MaxElementsStyled)
+	fmt.Printf("Style Cache Reuse: %s\n", formatReuseRatio(analysis.CacheReuseRatio))
+	fmt.Println()
+
+	if len(analysis.Culprits) > 0 {
+		fmt.Println("Forced Recalcs:")
+		fmt.Println(strings.Repeat("-", 60))
+		for _, c := range analysis.Culprits {
+			fmt.Printf("  %s: %d recalcs, %.2fms, %d elements\n", truncateName(c.Function, 60), c.Count, c.TotalMs, c.ElementsStyled)
+			for _, frame := range c.Stack {
+				fmt.Printf("      at %s\n", frame)
+			}
+		}
+		fmt.Println()
+	}
+
+	if len(analysis.SlowestRecalcs) > 0 {
+		fmt.Println("Slowest Recalcs:")
+		fmt.Println(strings.Repeat("-", 60))
+		for _, r := range analysis.SlowestRecalcs {
+			forced := ""
+			if r.Forced {
+				forced = " [forced]"
+			}
+			fmt.Printf("  %9.2fms %8.2fms  %6d styled  %6d shared  %6d reused%s\n",
+				r.StartTime, r.DurationMs, r.ElementsStyled, r.StylesShared, r.StylesReused, forced)
+		}
+		fmt.Println()
+	}
+
+	if len(analysis.Selectors) > 0 {
+		fmt.Println("Slowest Selectors:")
+		fmt.Println(strings.Repeat("-", 60))
+		for _, s := range analysis.Selectors {
+			fmt.Printf("  %-40s %8.3fms  %6d attempts  %6d matches\n", truncateName(s.Selector, 40), s.ElapsedMs, s.MatchAttempts, s.MatchCount)
+		}
+		fmt.Println()
+	}
+
+	if len(analysis.Recommendations) > 0 {
+		fmt.Println("Recommendations:")
+		for _, r := range analysis.Recommendations {
+			fmt.Printf("  - %s\n", r)
+		}
+	}
+
+	return nil
+}
@@ -6,7 +6,8 @@ import (
 	"os"
 	"strings"
 
-	"github.com/CedricHerzog/perfowl/internal/parser"
+	"github.com/CedricHerzog/perfowl/internal/analyzer"
+	"github.com/CedricHerzog/perfowl/internal/report"
 	"github.com/spf13/cobra"
 	"golang.org/x/text/cases"
 	"golang.org/x/text/language"
@@ -15,7 +16,7 @@ import (
 var summaryCmd = &cobra.Command{
 	Use:   "summary",
 	Short: "Display a summary of the browser profile",
-	Long:  `Shows key information about the profile including duration, threads, extensions, and captured features.`,
+	Long:  `Shows key information about the profile including duration, threads, extensions, captured features, and page-load milestones (navigation start, first paint, FCP, DOMContentLoaded, LCP, load) for each navigation.`,
 	RunE:  runSummary,
 }
 
@@ -23,38 +24,17 @@ func init() {
 	rootCmd.AddCommand(summaryCmd)
 }
 
-type ProfileSummary struct {
-	BrowserType     string            `json:"browser_type"`
-	Duration        float64           `json:"duration_seconds"`
-	Platform        string            `json:"platform"`
-	OSCPU           string            `json:"os_cpu"`
-	Product         string            `json:"product"`
-	BuildID         string            `json:"build_id"`
-	CPUName         string            `json:"cpu_name"`
-	PhysicalCPUs    int               `json:"physical_cpus"`
-	LogicalCPUs     int               `json:"logical_cpus"`
-	ThreadCount     int               `json:"thread_count"`
-	MainThreadCount int               `json:"main_thread_count"`
-	ExtensionCount  int               `json:"extension_count"`
-	Extensions      map[string]string `json:"extensions"`
-	Features        []string          `json:"features"`
-	Categories      []string          `json:"categories"`
-	TotalMarkers    int               `json:"total_markers"`
-	TotalSamples    int               `json:"total_samples"`
-}
-
 func runSummary(cmd *cobra.Command, args []string) error {
 	if profilePath == "" {
 		return fmt.Errorf("profile path is required (use --profile or -p)")
 	}
 
-	bt := parser.ParseBrowserType(browserType)
-	profile, detectedType, err := parser.LoadProfileWithType(profilePath, bt)
+	profile, detectedType, err := loadProfile(profilePath)
 	if err != nil {
-		return fmt.Errorf("failed to load profile: %w", err)
+		return err
 	}
 
-	summary := buildSummary(profile, detectedType)
+	summary := report.BuildSummary(profile, detectedType)
 
 	switch outputFormat {
 	case "json":
@@ -66,47 +46,13 @@ func runSummary(cmd *cobra.Command, args []string) error {
 	}
 }
 
-func buildSummary(profile *parser.Profile, bt parser.BrowserType) ProfileSummary {
-	summary := ProfileSummary{
-		BrowserType:    string(bt),
-		Duration:       profile.DurationSeconds(),
-		Platform:       profile.Meta.Platform,
-		OSCPU:          profile.Meta.OSCPU,
-		Product:        profile.Meta.Product,
-		BuildID:        profile.Meta.AppBuildID,
-		CPUName:        profile.Meta.CPUName,
-		PhysicalCPUs:   profile.Meta.PhysicalCPUs,
-		LogicalCPUs:    profile.Meta.LogicalCPUs,
-		ThreadCount:    profile.ThreadCount(),
-		ExtensionCount: profile.ExtensionCount(),
-		Extensions:     profile.GetExtensions(),
-		Features:       profile.Meta.Configuration.Features,
-	}
-
-	// Count main threads
-	for _, t := range profile.Threads {
-		if t.IsMainThread {
-			summary.MainThreadCount++
-		}
-		summary.TotalMarkers += t.Markers.Length
-		summary.TotalSamples += t.Samples.Length
-	}
-
-	// Get category names
-	for _, cat := range profile.Meta.Categories {
-		summary.Categories = append(summary.Categories, cat.Name)
-	}
-
-	return summary
-}
-
-func outputJSON(summary ProfileSummary) error {
+func outputJSON(summary report.Summary) error {
 	encoder := json.NewEncoder(os.Stdout)
 	encoder.SetIndent("", "  ")
 	return encoder.Encode(summary)
 }
 
-func outputMarkdown(summary ProfileSummary) error {
+func outputMarkdown(summary report.Summary) error {
 	md := strings.Builder{}
 
 	browserName := cases.Title(language.English).String(summary.BrowserType)
@@ -117,7 +63,7 @@ func outputMarkdown(summary ProfileSummary) error {
 
 	md.WriteString("## Overview\n\n")
 	md.WriteString(fmt.Sprintf("- **Browser**: %s\n", browserName))
-	md.WriteString(fmt.Sprintf("- **Duration**: %.2f seconds\n", summary.Duration))
+	md.WriteString(fmt.Sprintf("- **Duration**: %.2f seconds\n", summary.DurationSeconds))
 	md.WriteString(fmt.Sprintf("- **Platform**: %s (%s)\n", summary.Platform, summary.OSCPU))
 	md.WriteString(fmt.Sprintf("- **Product**: %s (Build: %s)\n", summary.Product, summary.BuildID))
 	md.WriteString(fmt.Sprintf("- **CPU**: %s (%d physical, %d logical cores)\n", summary.CPUName, summary.PhysicalCPUs, summary.LogicalCPUs))
@@ -132,6 +78,19 @@ func outputMarkdown(summary ProfileSummary) error {
 		md.WriteString(fmt.Sprintf("- %s\n", feature))
 	}
 
+	if len(summary.PageLoad) > 0 {
+		md.WriteString("\n## Page Load\n\n")
+		for _, nav := range summary.PageLoad {
+			md.WriteString(fmt.Sprintf("### Navigation %d%s\n\n", nav.Index+1, navigationLabel(nav)))
+			md.WriteString("| Milestone | Time | Since Navigation |\n")
+			md.WriteString("|-----------|------|------------------|\n")
+			for _, m := range nav.Milestones {
+				md.WriteString(fmt.Sprintf("| %s | %.2fms | %.2fms |\n", m.Name, m.TimeMs, m.SinceNavigationMs))
+			}
+			md.WriteString("\n")
+		}
+	}
+
 	if summary.ExtensionCount > 0 {
 		md.WriteString("\n## Extensions\n\n")
 		md.WriteString(fmt.Sprintf("**%d extensions active during profiling:**\n\n", summary.ExtensionCount))
@@ -144,7 +103,7 @@ func outputMarkdown(summary ProfileSummary) error {
 	return nil
 }
 
-func outputText(summary ProfileSummary) error {
+func outputText(summary report.Summary) error {
 	browserName := cases.Title(language.English).String(summary.BrowserType)
 	if browserName == "" {
 		browserName = "Browser"
@@ -155,7 +114,7 @@ func outputText(summary ProfileSummary) error {
 
 	fmt.Println("Overview:")
 	fmt.Printf("  Browser:      %s\n", browserName)
-	fmt.Printf("  Duration:     %.2f seconds\n", summary.Duration)
+	fmt.Printf("  Duration:     %.2f seconds\n", summary.DurationSeconds)
 	fmt.Printf("  Platform:     %s (%s)\n", summary.Platform, summary.OSCPU)
 	fmt.Printf("  Product:      %s\n", summary.Product)
 	fmt.Printf("  Build ID:     %s\n", summary.BuildID)
@@ -175,6 +134,17 @@ func outputText(summary ProfileSummary) error {
 	}
 	fmt.Println()
 
+	if len(summary.PageLoad) > 0 {
+		fmt.Println("Page Load:")
+		for _, nav := range summary.PageLoad {
+			fmt.Printf("  Navigation %d%s\n", nav.Index+1, navigationLabel(nav))
+			for _, m := range nav.Milestones {
+				fmt.Printf("    %-24s %10.2f ms  (+%.2f ms)\n", m.Name, m.TimeMs, m.SinceNavigationMs)
+			}
+		}
+		fmt.Println()
+	}
+
 	if summary.ExtensionCount > 0 {
 		fmt.Printf("Extensions (%d):\n", summary.ExtensionCount)
 		for id, name := range summary.Extensions {
@@ -185,3 +155,15 @@ func outputText(summary ProfileSummary) error {
 
 	return nil
 }
+
+// navigationLabel describes where a navigation went for summary headings
+func navigationLabel(nav analyzer.Navigation) string {
+	switch {
+	case nav.URL != "":
+		return ": " + nav.URL
+	case nav.Implicit:
+		return " (no navigation start recorded)"
+	default:
+		return ""
+	}
+}
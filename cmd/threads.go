@@ -0,0 +1,130 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/CedricHerzog/perfowl/internal/analyzer"
+	"github.com/CedricHerzog/perfowl/internal/report"
+	"github.com/spf13/cobra"
+)
+
+var threadsCmd = &cobra.Command{
+	Use:   "threads",
+	Short: "Analyze CPU usage and activity per thread",
+	Long: `Analyzes every thread in the profile, as the MCP get_thread_analysis tool does:
+- Process type, name, PID and TID
+- Sampled CPU time, samples and markers
+- Wake-ups and the average interval between them
+- Top profiler categories
+
+Threads are listed by CPU time, busiest first.`,
+	RunE: runThreads,
+}
+
+func init() {
+	rootCmd.AddCommand(threadsCmd)
+}
+
+func runThreads(cmd *cobra.Command, args []string) error {
+	if profilePath == "" {
+		return fmt.Errorf("profile path is required (use --profile or -p)")
+	}
+
+	profile, _, err := loadProfile(profilePath)
+	if err != nil {
+		return err
+	}
+
+	analysis := report.Threads(profile)
+
+	switch outputFormat {
+	case "json":
+		return outputThreadsJSON(analysis)
+	case "markdown":
+		return outputThreadsMarkdown(analysis)
+	default:
+		return outputThreadsText(analysis)
+	}
+}
+
+// topCategoryNames joins the names of a thread's top categories
+func topCategoryNames(t analyzer.ThreadStats) string {
+	names := make([]string, 0, len(t.TopCategories))
+	for _, c := range t.TopCategories {
+		names = append(names, fmt.Sprintf("%s %.0f%%", c.Name, c.Percent))
+	}
+	return strings.Join(names, ", ")
+}
+
+func outputThreadsJSON(analysis analyzer.ThreadAnalysis) error {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(analysis)
+}
+
+func outputThreadsMarkdown(analysis analyzer.ThreadAnalysis) error {
+	md := strings.Builder{}
+
+	md.WriteString("# Thread Analysis\n\n")
+
+	md.WriteString("## Summary\n\n")
+	md.WriteString(fmt.Sprintf("- **Total Threads**: %d\n", analysis.TotalThreads))
+	md.WriteString(fmt.Sprintf("- **Main Threads**: %d\n", analysis.MainThreadCount))
+	md.WriteString(fmt.Sprintf("- **Parent Process Threads**: %d\n", analysis.ParentProcessThreads))
+	md.WriteString(fmt.Sprintf("- **Content Process Threads**: %d\n", analysis.ContentProcessThreads))
+
+	if len(analysis.Threads) > 0 {
+		md.WriteString("\n## Threads\n\n")
+		md.WriteString("| Thread | Process | PID | CPU Time | Samples | Markers | Wakes | Top Categories |\n")
+		md.WriteString("|--------|---------|-----|----------|---------|---------|-------|----------------|\n")
+		for _, t := range analysis.Threads {
+			name := t.Name
+			if t.IsMainThread {
+				name += " (main)"
+			}
+			md.WriteString(fmt.Sprintf("| %s | %s | %s | %.2fms | %d | %d | %d | %s |\n",
+				name, t.ProcessType, t.PID, t.CPUTimeMs, t.SampleCount, t.MarkerCount, t.WakeCount, topCategoryNames(t)))
+		}
+	}
+
+	fmt.Print(md.String())
+	return nil
+}
+
+func outputThreadsText(analysis analyzer.ThreadAnalysis) error {
+	fmt.Println("Thread Analysis")
+	fmt.Println(strings.Repeat("=", 60))
+	fmt.Println()
+
+	fmt.Println("Summary:")
+	fmt.Printf("  Total Threads:            %d\n", analysis.TotalThreads)
+	fmt.Printf("  Main Threads:             %d\n", analysis.MainThreadCount)
+	fmt.Printf("  Parent Process Threads:   %d\n", analysis.ParentProcessThreads)
+	fmt.Printf("  Content Process Threads:  %d\n", analysis.ContentProcessThreads)
+	fmt.Println()
+
+	if len(analysis.Threads) == 0 {
+		return nil
+	}
+
+	fmt.Printf("%-30s %-10s %12s %8s %8s %6s\n", "Thread", "Process", "CPU Time", "Samples", "Markers", "Wakes")
+	fmt.Println(strings.Repeat("-", 80))
+	for _, t := range analysis.Threads {
+		name := t.Name
+		if t.IsMainThread {
+			name += " *"
+		}
+		fmt.Printf("%-30s %-10s %10.2fms %8d %8d %6d\n",
+			truncateName(name, 30), truncateName(t.ProcessType, 10), t.CPUTimeMs, t.SampleCount, t.MarkerCount, t.WakeCount)
+		if cats := topCategoryNames(t); cats != "" {
+			fmt.Printf("  %s\n", cats)
+		}
+	}
+	fmt.Println()
+	fmt.Println("* main thread")
+
+	return nil
+}
@@ -7,7 +7,6 @@ import (
 	"strings"
 
 	"github.com/CedricHerzog/perfowl/internal/analyzer"
-	"github.com/CedricHerzog/perfowl/internal/parser"
 	"github.com/spf13/cobra"
 )
 
@@ -24,7 +23,9 @@ var workersCmd = &cobra.Command{
 Identifies potential issues like:
 - Worker starvation (low utilization)
 - Excessive synchronous waits
-- Contention between workers`,
+- Contention between workers
+
+Use -o junit or -o sarif to report each warning for CI.`,
 	RunE: runWorkers,
 }
 
@@ -37,10 +38,9 @@ func runWorkers(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("profile path is required (use --profile or -p)")
 	}
 
-	bt := parser.ParseBrowserType(browserType)
-	profile, _, err := parser.LoadProfileWithType(profilePath, bt)
+	profile, _, err := loadProfile(profilePath)
 	if err != nil {
-		return fmt.Errorf("failed to load profile: %w", err)
+		return err
 	}
 
 	analysis := analyzer.AnalyzeWorkers(profile)
@@ -48,6 +48,8 @@ func runWorkers(cmd *cobra.Command, args []string) error {
 	switch outputFormat {
 	case "json":
 		return outputWorkersJSON(analysis)
+	case "junit", "sarif":
+		return outputFindings("workers", analyzer.WarningFindings("workers", analysis.Warnings, profile))
 	case "markdown":
 		return outputWorkersMarkdown(analysis)
 	default:
@@ -75,16 +77,16 @@ func outputWorkersMarkdown(analysis analyzer.WorkerAnalysis) error {
 
 	if len(analysis.Workers) > 0 {
 		md.WriteString("\n## Worker Details\n\n")
-		md.WriteString("| Worker | CPU Time | Idle Time | Active % | Messages | Sync Waits |\n")
-		md.WriteString("|--------|----------|-----------|----------|----------|------------|\n")
+		md.WriteString("| Worker | CPU Time | Idle Time | Active % | Sent | Received | Sync Waits |\n")
+		md.WriteString("|--------|----------|-----------|----------|------|----------|------------|\n")
 
 		for _, w := range analysis.Workers {
 			name := w.ThreadName
 			if len(name) > 20 {
 				name = name[:17] + "..."
 			}
-			md.WriteString(fmt.Sprintf("| %s | %.2fms | %.2fms | %.1f%% | %d | %d |\n",
-				name, w.CPUTimeMs, w.IdleTimeMs, w.ActivePercent, w.MessagesSent, w.SyncWaitCount))
+			md.WriteString(fmt.Sprintf("| %s | %.2fms | %.2fms | %.1f%% | %d | %d | %d |\n",
+				name, w.CPUTimeMs, w.IdleTimeMs, w.ActivePercent, w.MessagesSent, w.MessagesReceived, w.SyncWaitCount))
 		}
 	}
 
@@ -135,7 +137,7 @@ func outputWorkersText(analysis analyzer.WorkerAnalysis) error {
 	if len(analysis.Workers) > 0 {
 		fmt.Println("Worker Details:")
 		fmt.Println(strings.Repeat("-", 60))
-		fmt.Printf("%-22s %10s %10s %8s %6s\n", "Name", "CPU Time", "Idle Time", "Active%", "Msgs")
+		fmt.Printf("%-22s %10s %10s %8s %6s %6s\n", "Name", "CPU Time", "Idle Time", "Active%", "Sent", "Recv")
 		fmt.Println(strings.Repeat("-", 60))
 
 		for _, w := range analysis.Workers {
@@ -143,8 +145,8 @@ func outputWorkersText(analysis analyzer.WorkerAnalysis) error {
 			if len(name) > 22 {
 				name = name[:19] + "..."
 			}
-			fmt.Printf("%-22s %8.2fms %8.2fms %7.1f%% %6d\n",
-				name, w.CPUTimeMs, w.IdleTimeMs, w.ActivePercent, w.MessagesSent)
+			fmt.Printf("%-22s %8.2fms %8.2fms %7.1f%% %6d %6d\n",
+				name, w.CPUTimeMs, w.IdleTimeMs, w.ActivePercent, w.MessagesSent, w.MessagesReceived)
 		}
 		fmt.Println()
 	}
@@ -25,30 +25,59 @@ type ProfileEntry struct {
 	EndPattern       string  `json:"end_pattern,omitempty" yaml:"end_pattern,omitempty"`
 	StartMinDuration float64 `json:"start_min_duration,omitempty" yaml:"start_min_duration,omitempty"`
 	EndMinDuration   float64 `json:"end_min_duration,omitempty" yaml:"end_min_duration,omitempty"`
+	Threads          string  `json:"threads,omitempty" yaml:"threads,omitempty"`
 }
 
-// ProfileDataPoint represents metrics for a single profile
+// Batch metrics, keyed like the chart types
+const (
+	BatchMetricWallClock     = "wall_clock"
+	BatchMetricOperationTime = "operation_time"
+	BatchMetricTotalWork     = "total_work"
+	BatchMetricEfficiency    = "efficiency"
+	BatchMetricSpeedup       = "speedup"
+	BatchMetricCryptoTime    = "crypto_time"
+)
+
+// ProfileDataPoint represents metrics for a single profile, or the mean of repeated
+// runs with the same label and worker count
 type ProfileDataPoint struct {
-	WorkerCount     int     `json:"worker_count"`
-	Label           string  `json:"label"`
-	FilePath        string  `json:"file_path"`
-	WallClockMs     float64 `json:"wall_clock_ms"`
-	OperationTimeMs float64 `json:"operation_time_ms,omitempty"`
-	TotalWorkMs     float64 `json:"total_work_ms"`
-	Efficiency      float64 `json:"efficiency_percent"`
-	Speedup         float64 `json:"speedup"`
-	CryptoTimeMs    float64 `json:"crypto_time_ms"`
+	WorkerCount     int                    `json:"worker_count"`
+	Label           string                 `json:"label"`
+	FilePath        string                 `json:"file_path"`
+	FilePaths       []string               `json:"file_paths,omitempty"` // Every run when several were aggregated
+	Runs            int                    `json:"runs"`
+	WallClockMs     float64                `json:"wall_clock_ms"`
+	OperationTimeMs float64                `json:"operation_time_ms,omitempty"`
+	TotalWorkMs     float64                `json:"total_work_ms"`
+	Efficiency      float64                `json:"efficiency_percent"`
+	Speedup         float64                `json:"speedup"`
+	CryptoTimeMs    float64                `json:"crypto_time_ms"`
+	Stats           map[string]MetricStats `json:"stats,omitempty"` // Metric -> repeated-run statistics
+}
+
+// batchMetrics maps each metric name to its field in a data point
+var batchMetrics = []struct {
+	name  string
+	field func(p *ProfileDataPoint) *float64
+}{
+	{BatchMetricWallClock, func(p *ProfileDataPoint) *float64 { return &p.WallClockMs }},
+	{BatchMetricOperationTime, func(p *ProfileDataPoint) *float64 { return &p.OperationTimeMs }},
+	{BatchMetricTotalWork, func(p *ProfileDataPoint) *float64 { return &p.TotalWorkMs }},
+	{BatchMetricEfficiency, func(p *ProfileDataPoint) *float64 { return &p.Efficiency }},
+	{BatchMetricSpeedup, func(p *ProfileDataPoint) *float64 { return &p.Speedup }},
+	{BatchMetricCryptoTime, func(p *ProfileDataPoint) *float64 { return &p.CryptoTimeMs }},
 }
 
 // BatchSummary provides high-level insights across all profiles
 type BatchSummary struct {
-	TotalProfiles    int                `json:"total_profiles"`
-	Labels           []string           `json:"labels"`
-	BestWorkers      map[string]int     `json:"best_workers"`       // Label -> optimal worker count
-	MinWallClock     map[string]float64 `json:"min_wall_clock"`     // Label -> minimum wall clock time
-	MinOperationTime map[string]float64 `json:"min_operation_time"` // Label -> minimum operation time
-	MaxSpeedup       map[string]float64 `json:"max_speedup"`        // Label -> maximum speedup achieved
-	PeakEfficiency   map[string]float64 `json:"peak_efficiency"`    // Label -> peak efficiency
+	TotalProfiles    int                   `json:"total_profiles"`
+	Labels           []string              `json:"labels"`
+	BestWorkers      map[string]int        `json:"best_workers"`       // Label -> optimal worker count
+	MinWallClock     map[string]float64    `json:"min_wall_clock"`     // Label -> minimum wall clock time
+	MinOperationTime map[string]float64    `json:"min_operation_time"` // Label -> minimum operation time
+	MaxSpeedup       map[string]float64    `json:"max_speedup"`        // Label -> maximum speedup achieved
+	PeakEfficiency   map[string]float64    `json:"peak_efficiency"`    // Label -> peak efficiency
+	ScalingFits      map[string]ScalingFit `json:"scaling_fits"`       // Label -> Amdahl/USL model fit
 }
 
 // BatchAnalysisResult contains aggregated results from batch analysis
@@ -69,6 +98,7 @@ func AnalyzeBatch(profiles []ProfileEntry) (*BatchAnalysisResult, error) {
 			MinOperationTime: make(map[string]float64),
 			MaxSpeedup:       make(map[string]float64),
 			PeakEfficiency:   make(map[string]float64),
+			ScalingFits:      make(map[string]ScalingFit),
 		},
 	}
 
@@ -100,6 +130,15 @@ func AnalyzeBatch(profiles []ProfileEntry) (*BatchAnalysisResult, error) {
 				return
 			}
 
+			if e.Threads != "" {
+				sel, err := parser.ParseThreadSelector(e.Threads)
+				if err != nil {
+					results <- profileResult{err: fmt.Errorf("profile %s: %w", e.Path, err)}
+					return
+				}
+				profile = profile.SelectThreads(sel)
+			}
+
 			// Run scaling analysis
 			scaling := AnalyzeScaling(profile)
 
@@ -111,6 +150,7 @@ func AnalyzeBatch(profiles []ProfileEntry) (*BatchAnalysisResult, error) {
 				WorkerCount:  e.WorkerCount,
 				Label:        e.Label,
 				FilePath:     e.Path,
+				Runs:         1,
 				WallClockMs:  scaling.WallClockMs,
 				TotalWorkMs:  scaling.TotalWorkMs,
 				Efficiency:   scaling.Efficiency,
@@ -155,11 +195,15 @@ func AnalyzeBatch(profiles []ProfileEntry) (*BatchAnalysisResult, error) {
 		labelSet[r.label] = true
 	}
 
-	// Sort each series by worker count
-	for label := range result.Series {
-		sort.Slice(result.Series[label], func(i, j int) bool {
-			return result.Series[label][i].WorkerCount < result.Series[label][j].WorkerCount
+	// Sort each series by worker count and merge repeated runs
+	for label, points := range result.Series {
+		sort.Slice(points, func(i, j int) bool {
+			if points[i].WorkerCount != points[j].WorkerCount {
+				return points[i].WorkerCount < points[j].WorkerCount
+			}
+			return points[i].FilePath < points[j].FilePath
 		})
+		result.Series[label] = aggregateRuns(points)
 	}
 
 	// Build summary
@@ -212,7 +256,54 @@ func AnalyzeBatch(profiles []ProfileEntry) (*BatchAnalysisResult, error) {
 		if hasOpTime {
 			result.Summary.MinOperationTime[label] = minOpTime
 		}
+		if fit, ok := FitScaling(points); ok {
+			result.Summary.ScalingFits[label] = fit
+		}
 	}
 
 	return result, nil
 }
+
+// aggregateRuns merges consecutive points with the same worker count into one point
+// holding the mean of each metric after outlier rejection, with the repeated-run
+// statistics. Operation time only counts the runs that measured it.
+func aggregateRuns(points []ProfileDataPoint) []ProfileDataPoint {
+	aggregated := make([]ProfileDataPoint, 0, len(points))
+	for start := 0; start < len(points); {
+		end := start + 1
+		for end < len(points) && points[end].WorkerCount == points[start].WorkerCount {
+			end++
+		}
+		runs := points[start:end]
+		start = end
+		if len(runs) == 1 {
+			aggregated = append(aggregated, runs[0])
+			continue
+		}
+
+		point := runs[0]
+		point.Runs = len(runs)
+		point.Stats = make(map[string]MetricStats)
+		for _, r := range runs {
+			point.FilePaths = append(point.FilePaths, r.FilePath)
+		}
+		for _, m := range batchMetrics {
+			values := make([]float64, 0, len(runs))
+			for i := range runs {
+				v := *m.field(&runs[i])
+				if m.name == BatchMetricOperationTime && v <= 0 {
+					continue
+				}
+				values = append(values, v)
+			}
+			if len(values) == 0 {
+				continue
+			}
+			stats := SummarizeRuns(values)
+			point.Stats[m.name] = stats
+			*m.field(&point) = stats.Mean
+		}
+		aggregated = append(aggregated, point)
+	}
+	return aggregated
+}
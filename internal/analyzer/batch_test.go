@@ -61,6 +61,74 @@ func TestAnalyzeBatch_MultipleProfiles(t *testing.T) {
 	}
 }
 
+func TestAnalyzeBatch_ScalingFit(t *testing.T) {
+	path1 := testutil.TempProfileFile(t, testutil.ProfileWithWorkers(2))
+	path2 := testutil.TempProfileFile(t, testutil.ProfileWithWorkers(4))
+
+	result, err := AnalyzeBatch([]ProfileEntry{
+		{Path: path1, WorkerCount: 2, Label: "Firefox"},
+		{Path: path2, WorkerCount: 4, Label: "Firefox"},
+		{Path: path1, WorkerCount: 2, Label: "Single"},
+	})
+
+	if err != nil {
+		t.Fatalf("AnalyzeBatch error: %v", err)
+	}
+	fit, ok := result.Summary.ScalingFits["Firefox"]
+	if !ok {
+		t.Fatal("expected a scaling fit for Firefox")
+	}
+	if fit.Points != 2 || fit.RecommendedWorkers < 1 || len(fit.Predicted) == 0 {
+		t.Errorf("unexpected fit %+v", fit)
+	}
+	if _, ok := result.Summary.ScalingFits["Single"]; ok {
+		t.Error("expected no fit for a label measured at one worker count")
+	}
+}
+
+func TestAnalyzeBatch_AggregatesRepeatedRuns(t *testing.T) {
+	path := testutil.TempProfileFile(t, testutil.ProfileWithWorkers(2))
+	other := testutil.TempProfileFile(t, testutil.ProfileWithWorkers(4))
+
+	result, err := AnalyzeBatch([]ProfileEntry{
+		{Path: path, WorkerCount: 2, Label: "Firefox"},
+		{Path: path, WorkerCount: 2, Label: "Firefox"},
+		{Path: path, WorkerCount: 2, Label: "Firefox"},
+		{Path: other, WorkerCount: 4, Label: "Firefox"},
+	})
+
+	if err != nil {
+		t.Fatalf("AnalyzeBatch error: %v", err)
+	}
+	points := result.Series["Firefox"]
+	if len(points) != 2 {
+		t.Fatalf("expected 2 aggregated points, got %d", len(points))
+	}
+	if result.Summary.TotalProfiles != 4 {
+		t.Errorf("TotalProfiles = %d, want 4", result.Summary.TotalProfiles)
+	}
+
+	repeated := points[0]
+	if repeated.WorkerCount != 2 || repeated.Runs != 3 || len(repeated.FilePaths) != 3 {
+		t.Errorf("first point = %+v, want 3 runs at 2 workers", repeated)
+	}
+	stats, ok := repeated.Stats[BatchMetricWallClock]
+	if !ok {
+		t.Fatal("expected wall clock statistics")
+	}
+	if stats.N != 3 || stats.StdDev != 0 || stats.Mean != repeated.WallClockMs {
+		t.Errorf("unexpected wall clock stats %+v for identical runs", stats)
+	}
+	if _, ok := repeated.Stats[BatchMetricOperationTime]; ok {
+		t.Error("expected no operation time statistics without markers")
+	}
+
+	single := points[1]
+	if single.Runs != 1 || single.Stats != nil {
+		t.Errorf("single run should have no statistics, got %+v", single)
+	}
+}
+
 func TestAnalyzeBatch_DifferentLabels(t *testing.T) {
 	profile1 := testutil.ProfileWithWorkers(2)
 	profile2 := testutil.ProfileWithWorkers(2)
@@ -265,3 +333,23 @@ func TestBatchSummary_Fields(t *testing.T) {
 		t.Errorf("expected 2 labels, got %d", len(summary.Labels))
 	}
 }
+
+func TestAnalyzeBatch_ThreadSelector(t *testing.T) {
+	profile := testutil.ProfileWithWorkers(4)
+	path := testutil.TempProfileFile(t, profile)
+
+	all, err := AnalyzeBatch([]ProfileEntry{{Path: path, WorkerCount: 4, Label: "Test"}})
+	if err != nil {
+		t.Fatalf("AnalyzeBatch error: %v", err)
+	}
+	mainOnly, err := AnalyzeBatch([]ProfileEntry{{Path: path, WorkerCount: 4, Label: "Test", Threads: "main"}})
+	if err != nil {
+		t.Fatalf("AnalyzeBatch error: %v", err)
+	}
+
+	allWork := all.Series["Test"][0].TotalWorkMs
+	mainWork := mainOnly.Series["Test"][0].TotalWorkMs
+	if mainWork >= allWork {
+		t.Errorf("TotalWorkMs with main thread only = %v, want less than %v", mainWork, allWork)
+	}
+}
@@ -111,6 +111,11 @@ func DetectBottlenecks(profile *parser.Profile) []Bottleneck {
 		bottlenecks = append(bottlenecks, *b)
 	}
 
+	// Detect forced synchronous layouts
+	if b := detectForcedLayout(profile); b != nil {
+		bottlenecks = append(bottlenecks, *b)
+	}
+
 	// Detect network blocking
 	if b := detectNetworkBlocking(allMarkers); b != nil {
 		bottlenecks = append(bottlenecks, *b)
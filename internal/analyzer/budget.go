@@ -0,0 +1,339 @@
+package analyzer
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/CedricHerzog/perfowl/internal/parser"
+)
+
+// Metrics a budget rule can limit
+const (
+	BudgetMetricScore         = "score"
+	BudgetMetricDuration      = "duration_ms"
+	BudgetMetricLongTasks     = "long_tasks"
+	BudgetMetricGCTime        = "gc_time_ms"
+	BudgetMetricGCMajor       = "gc_major_count"
+	BudgetMetricSyncIPC       = "sync_ipc_count"
+	BudgetMetricLayouts       = "layout_count"
+	BudgetMetricOperationTime = "operation_time_ms"
+	BudgetMetricCategory      = "category_percent"
+	BudgetMetricFunction      = "function_self_time_ms"
+
+	budgetFunctionPool = 100000 // Call tree limit when looking up a named function
+)
+
+// budgetMetrics lists every supported metric for validation messages
+var budgetMetrics = []string{
+	BudgetMetricScore, BudgetMetricDuration, BudgetMetricLongTasks, BudgetMetricGCTime,
+	BudgetMetricGCMajor, BudgetMetricSyncIPC, BudgetMetricLayouts, BudgetMetricOperationTime,
+	BudgetMetricCategory, BudgetMetricFunction,
+}
+
+// Budget declares the performance limits a profile must meet
+type Budget struct {
+	Tolerance float64      `json:"tolerance,omitempty" yaml:"tolerance"` // Default allowed change in percent against a baseline
+	Rules     []BudgetRule `json:"rules" yaml:"rules"`
+}
+
+// BudgetRule limits one metric. Min and Max are absolute limits; Tolerance allows
+// a percentage change against the baseline profile in the worse direction.
+type BudgetRule struct {
+	Name      string   `json:"name,omitempty" yaml:"name"`
+	Metric    string   `json:"metric" yaml:"metric"`
+	Min       *float64 `json:"min,omitempty" yaml:"min"`
+	Max       *float64 `json:"max,omitempty" yaml:"max"`
+	Tolerance *float64 `json:"tolerance,omitempty" yaml:"tolerance"` // Overrides Budget.Tolerance
+
+	Start    string `json:"start,omitempty" yaml:"start"`         // operation_time_ms start marker pattern
+	End      string `json:"end,omitempty" yaml:"end"`             // operation_time_ms end marker pattern
+	FindLast bool   `json:"find_last,omitempty" yaml:"find_last"` // operation_time_ms: use the last matching end marker
+	Category string `json:"category,omitempty" yaml:"category"`   // category_percent category name
+	Function string `json:"function,omitempty" yaml:"function"`   // function_self_time_ms function name
+	Thread   string `json:"thread,omitempty" yaml:"thread"`       // Restrict category_percent and function_self_time_ms to one thread
+}
+
+// Label names the rule in reports, falling back to a description of the metric
+func (r BudgetRule) Label() string {
+	if r.Name != "" {
+		return r.Name
+	}
+	switch r.Metric {
+	case BudgetMetricOperationTime:
+		return fmt.Sprintf("%s %s..%s", r.Metric, r.Start, r.End)
+	case BudgetMetricCategory:
+		return fmt.Sprintf("%s %s", r.Metric, r.Category)
+	case BudgetMetricFunction:
+		return fmt.Sprintf("%s %s", r.Metric, r.Function)
+	}
+	return r.Metric
+}
+
+// higherIsBetter reports whether larger values of the rule's metric are better
+func (r BudgetRule) higherIsBetter() bool {
+	return r.Metric == BudgetMetricScore
+}
+
+// BudgetResult is the outcome of one rule
+type BudgetResult struct {
+	Rule      string   `json:"rule"`
+	Metric    string   `json:"metric"`
+	Value     float64  `json:"value"`
+	Min       *float64 `json:"min,omitempty"`
+	Max       *float64 `json:"max,omitempty"`
+	Baseline  *float64 `json:"baseline,omitempty"`
+	Tolerance *float64 `json:"tolerance,omitempty"`
+	Passed    bool     `json:"passed"`
+	Message   string   `json:"message"`
+}
+
+// BudgetReport holds every rule's outcome
+type BudgetReport struct {
+	Passed  bool           `json:"passed"`
+	Total   int            `json:"total"`
+	Failed  int            `json:"failed"`
+	Results []BudgetResult `json:"results"`
+}
+
+// Validate checks that every rule names a known metric, has its parameters and
+// sets at least one limit
+func (b Budget) Validate() error {
+	if len(b.Rules) == 0 {
+		return fmt.Errorf("budget has no rules")
+	}
+	for i, r := range b.Rules {
+		known := false
+		for _, m := range budgetMetrics {
+			if r.Metric == m {
+				known = true
+				break
+			}
+		}
+		switch {
+		case !known:
+			return fmt.Errorf("rule %d: unknown metric %q (use one of: %s)", i+1, r.Metric, strings.Join(budgetMetrics, ", "))
+		case r.Metric == BudgetMetricOperationTime && (r.Start == "" || r.End == ""):
+			return fmt.Errorf("rule %d: %s needs start and end marker patterns", i+1, r.Metric)
+		case r.Metric == BudgetMetricCategory && r.Category == "":
+			return fmt.Errorf("rule %d: %s needs a category", i+1, r.Metric)
+		case r.Metric == BudgetMetricFunction && r.Function == "":
+			return fmt.Errorf("rule %d: %s needs a function", i+1, r.Metric)
+		case r.Min == nil && r.Max == nil && r.Tolerance == nil && b.Tolerance == 0:
+			return fmt.Errorf("rule %d: %s sets no min, max or tolerance", i+1, r.Label())
+		}
+	}
+	return nil
+}
+
+// CheckBudget evaluates each rule against the profile. When baseline is not nil,
+// rules with a tolerance also fail if the metric got worse than the baseline by
+// more than the tolerance, or if the baseline value can't be measured. Without a
+// baseline, a rule whose only limit is a tolerance fails rather than passing
+// unchecked.
+func CheckBudget(profile, baseline *parser.Profile, budget Budget) (BudgetReport, error) {
+	if err := budget.Validate(); err != nil {
+		return BudgetReport{}, err
+	}
+
+	report := BudgetReport{
+		Passed:  true,
+		Total:   len(budget.Rules),
+		Results: make([]BudgetResult, 0, len(budget.Rules)),
+	}
+	metrics := &budgetMetricCache{profile: profile}
+	var baseMetrics *budgetMetricCache
+	if baseline != nil {
+		baseMetrics = &budgetMetricCache{profile: baseline}
+	}
+
+	for _, rule := range budget.Rules {
+		result := BudgetResult{
+			Rule:   rule.Label(),
+			Metric: rule.Metric,
+			Min:    rule.Min,
+			Max:    rule.Max,
+			Passed: true,
+		}
+		value, err := metrics.value(rule)
+		if err != nil {
+			result.Passed = false
+			result.Message = err.Error()
+		} else {
+			result.Value = value
+			var failures []string
+			if rule.Min != nil && value < *rule.Min {
+				failures = append(failures, fmt.Sprintf("%.2f is below the minimum %.2f", value, *rule.Min))
+			}
+			if rule.Max != nil && value > *rule.Max {
+				failures = append(failures, fmt.Sprintf("%.2f exceeds the maximum %.2f", value, *rule.Max))
+			}
+
+			tolerance := rule.Tolerance
+			if tolerance == nil && budget.Tolerance > 0 {
+				t := budget.Tolerance
+				tolerance = &t
+			}
+			switch {
+			case baseMetrics != nil && tolerance != nil:
+				result.Tolerance = tolerance
+				if base, err := baseMetrics.value(rule); err != nil {
+					failures = append(failures, fmt.Sprintf("baseline: %v", err))
+				} else {
+					result.Baseline = &base
+					if msg := budgetBaselineFailure(rule, value, base, *tolerance); msg != "" {
+						failures = append(failures, msg)
+					}
+				}
+			case rule.Min == nil && rule.Max == nil:
+				failures = append(failures, "only a tolerance is set, which needs a baseline profile to check against")
+			}
+
+			if len(failures) > 0 {
+				result.Passed = false
+				result.Message = strings.Join(failures, "; ")
+			} else {
+				result.Message = budgetPassMessage(result)
+			}
+		}
+
+		if !result.Passed {
+			report.Passed = false
+			report.Failed++
+		}
+		report.Results = append(report.Results, result)
+	}
+
+	return report, nil
+}
+
+// budgetBaselineFailure describes a change against the baseline beyond the
+// tolerance, or returns "" when the change is within it
+func budgetBaselineFailure(rule BudgetRule, value, base, tolerance float64) string {
+	change := percentChange(base, value)
+	if rule.higherIsBetter() {
+		if value < base*(1-tolerance/100) {
+			return fmt.Sprintf("dropped %s from baseline %.2f (tolerance %.1f%%)", formatPercent(change), base, tolerance)
+		}
+		return ""
+	}
+	if value > base*(1+tolerance/100) && value-base > 1e-9 {
+		return fmt.Sprintf("rose %s from baseline %.2f (tolerance %.1f%%)", formatPercent(change), base, tolerance)
+	}
+	return ""
+}
+
+// budgetPassMessage summarizes a passing rule
+func budgetPassMessage(result BudgetResult) string {
+	var limits []string
+	if result.Min != nil {
+		limits = append(limits, fmt.Sprintf(">= %.2f", *result.Min))
+	}
+	if result.Max != nil {
+		limits = append(limits, fmt.Sprintf("<= %.2f", *result.Max))
+	}
+	if result.Baseline != nil {
+		limits = append(limits, fmt.Sprintf("baseline %.2f ± %.1f%%", *result.Baseline, *result.Tolerance))
+	}
+	if len(limits) == 0 {
+		return fmt.Sprintf("%.2f", result.Value)
+	}
+	return fmt.Sprintf("%.2f within %s", result.Value, strings.Join(limits, ", "))
+}
+
+// budgetMetricCache computes each profile-wide analysis once across rules
+type budgetMetricCache struct {
+	profile     *parser.Profile
+	summary     *ProfileSummary
+	bottlenecks []Bottleneck
+	detected    bool
+}
+
+// value measures the rule's metric
+func (c *budgetMetricCache) value(rule BudgetRule) (float64, error) {
+	if rule.Thread != "" && (rule.Metric == BudgetMetricCategory || rule.Metric == BudgetMetricFunction) {
+		names := make([]string, 0, len(c.profile.Threads))
+		found := false
+		for _, thread := range c.profile.Threads {
+			names = append(names, thread.Name)
+			found = found || thread.Name == rule.Thread
+		}
+		if !found {
+			return 0, fmt.Errorf("unknown thread %q (profile has: %s)", rule.Thread, strings.Join(names, ", "))
+		}
+	}
+
+	switch rule.Metric {
+	case BudgetMetricScore, BudgetMetricLongTasks:
+		// Use the bottleneck detector so long tasks agree with the score
+		if !c.detected {
+			c.bottlenecks, c.detected = DetectBottlenecks(c.profile), true
+		}
+		if rule.Metric == BudgetMetricScore {
+			return float64(CalculateScore(c.bottlenecks)), nil
+		}
+		for _, b := range c.bottlenecks {
+			if b.Type == "Long Tasks" {
+				return float64(b.Count), nil
+			}
+		}
+		return 0, nil
+	case BudgetMetricOperationTime:
+		m, err := MeasureOperationAdvanced(c.profile, MeasureOptions{
+			StartPattern: rule.Start,
+			EndPattern:   rule.End,
+			FindLast:     rule.FindLast,
+		})
+		if err != nil {
+			return 0, fmt.Errorf("operation not measured: %w", err)
+		}
+		return m.OperationTimeMs, nil
+	case BudgetMetricCategory:
+		known := make([]string, 0, len(c.profile.Meta.Categories))
+		found := false
+		for _, cat := range c.profile.Meta.Categories {
+			known = append(known, cat.Name)
+			found = found || strings.EqualFold(cat.Name, rule.Category)
+		}
+		if !found {
+			return 0, fmt.Errorf("unknown category %q (profile has: %s)", rule.Category, strings.Join(known, ", "))
+		}
+		for _, cat := range AnalyzeCategories(c.profile, rule.Thread).Categories {
+			if strings.EqualFold(cat.Name, rule.Category) {
+				return cat.Percent, nil
+			}
+		}
+		return 0, nil // A known category without samples
+	case BudgetMetricFunction:
+		var total float64
+		found := false
+		for _, fn := range AnalyzeCallTree(c.profile, rule.Thread, budgetFunctionPool).TopFunctions {
+			if fn.Name == rule.Function {
+				total += fn.SelfTimeMs
+				found = true
+			}
+		}
+		if !found {
+			return 0, fmt.Errorf("function %q was not sampled", rule.Function)
+		}
+		return math.Round(total*1000) / 1000, nil
+	}
+
+	if c.summary == nil {
+		summary := extractSummary(c.profile, "")
+		c.summary = &summary
+	}
+	switch rule.Metric {
+	case BudgetMetricDuration:
+		return c.summary.DurationMs, nil
+	case BudgetMetricGCTime:
+		return c.summary.GCTotalTimeMs, nil
+	case BudgetMetricGCMajor:
+		return float64(c.summary.GCMajorCount), nil
+	case BudgetMetricSyncIPC:
+		return float64(c.summary.SyncIPCCount), nil
+	case BudgetMetricLayouts:
+		return float64(c.summary.LayoutCount), nil
+	}
+	return 0, fmt.Errorf("unknown metric %q", rule.Metric)
+}
@@ -0,0 +1,200 @@
+package analyzer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/CedricHerzog/perfowl/internal/testutil"
+)
+
+func limit(v float64) *float64 { return &v }
+
+func TestBudget_Validate(t *testing.T) {
+	tests := []struct {
+		name   string
+		budget Budget
+		want   string
+	}{
+		{"no rules", Budget{}, "no rules"},
+		{"unknown metric", Budget{Rules: []BudgetRule{{Metric: "fps", Max: limit(1)}}}, "unknown metric"},
+		{"operation patterns", Budget{Rules: []BudgetRule{{Metric: BudgetMetricOperationTime, Start: "a", Max: limit(1)}}}, "start and end"},
+		{"category", Budget{Rules: []BudgetRule{{Metric: BudgetMetricCategory, Max: limit(1)}}}, "needs a category"},
+		{"function", Budget{Rules: []BudgetRule{{Metric: BudgetMetricFunction, Max: limit(1)}}}, "needs a function"},
+		{"no limit", Budget{Rules: []BudgetRule{{Metric: BudgetMetricLongTasks}}}, "no min, max or tolerance"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testutil.AssertErrorContains(t, tt.budget.Validate(), tt.want)
+		})
+	}
+
+	// A budget-wide tolerance is a limit on its own
+	testutil.AssertNoError(t, Budget{Tolerance: 10, Rules: []BudgetRule{{Metric: BudgetMetricLongTasks}}}.Validate())
+}
+
+func TestCheckBudget_AbsoluteLimits(t *testing.T) {
+	profile := testutil.ProfileWithLongTasks(5)
+
+	report, err := CheckBudget(profile, nil, Budget{Rules: []BudgetRule{
+		{Metric: BudgetMetricLongTasks, Max: limit(3)},
+		{Metric: BudgetMetricLongTasks, Max: limit(10), Name: "generous"},
+		{Metric: BudgetMetricScore, Min: limit(0)},
+		{Metric: BudgetMetricDuration, Min: limit(5000)},
+	}})
+	testutil.AssertNoError(t, err)
+
+	if report.Passed || report.Total != 4 || report.Failed != 2 {
+		t.Errorf("Passed/Total/Failed = %v/%d/%d, want false/4/2", report.Passed, report.Total, report.Failed)
+	}
+	r := report.Results
+	if r[0].Passed || r[0].Value != 5 || !strings.Contains(r[0].Message, "exceeds the maximum") {
+		t.Errorf("unexpected long task result %+v", r[0])
+	}
+	if !r[1].Passed || r[1].Rule != "generous" {
+		t.Errorf("unexpected named rule result %+v", r[1])
+	}
+	if !r[2].Passed {
+		t.Errorf("expected the score rule to pass, got %+v", r[2])
+	}
+	if r[3].Passed || !strings.Contains(r[3].Message, "below the minimum") {
+		t.Errorf("unexpected duration result %+v", r[3])
+	}
+}
+
+func TestCheckBudget_Metrics(t *testing.T) {
+	report, err := CheckBudget(testutil.ProfileRun(1000, 30), nil, Budget{Rules: []BudgetRule{
+		{Metric: BudgetMetricFunction, Function: "computeHash", Max: limit(25)},
+		{Metric: BudgetMetricFunction, Function: "render", Thread: "GeckoMain", Max: limit(25)},
+		{Metric: BudgetMetricCategory, Category: "javascript", Max: limit(50)},
+		{Metric: BudgetMetricCategory, Category: "GC / CC", Max: limit(1)},
+	}})
+	testutil.AssertNoError(t, err)
+
+	r := report.Results
+	testutil.AssertFloatApproxEqual(t, r[0].Value, 30, 1e-9)
+	testutil.AssertFalse(t, r[0].Passed, "computeHash self time exceeds its budget")
+	testutil.AssertFloatApproxEqual(t, r[1].Value, 20, 1e-9)
+	testutil.AssertTrue(t, r[1].Passed, "render self time is within its budget")
+	testutil.AssertFloatApproxEqual(t, r[2].Value, 100, 1e-9)
+	testutil.AssertFalse(t, r[2].Passed, "all samples are JavaScript")
+	testutil.AssertTrue(t, r[3].Passed, "a category without samples is 0%")
+	if r[0].Rule != "function_self_time_ms computeHash" {
+		t.Errorf("Rule = %q, want the default label", r[0].Rule)
+	}
+}
+
+func TestCheckBudget_OperationTime(t *testing.T) {
+	profile := testutil.ProfileWithDelimiters()
+
+	report, err := CheckBudget(profile, nil, Budget{Rules: []BudgetRule{
+		{Metric: BudgetMetricOperationTime, Start: "DOMEvent", End: "Paint", Max: limit(1000)},
+		{Metric: BudgetMetricOperationTime, Start: "DOMEvent", End: "Missing", Max: limit(1000)},
+	}})
+	testutil.AssertNoError(t, err)
+
+	if !report.Results[0].Passed || report.Results[0].Value <= 0 {
+		t.Errorf("expected a measured operation within budget, got %+v", report.Results[0])
+	}
+	if report.Results[1].Passed || !strings.Contains(report.Results[1].Message, "operation not measured") {
+		t.Errorf("expected a missing operation to fail, got %+v", report.Results[1])
+	}
+}
+
+func TestCheckBudget_Baseline(t *testing.T) {
+	baseline := testutil.ProfileRun(1000, 30)
+	candidate := testutil.ProfileRun(1080, 30)
+
+	report, err := CheckBudget(candidate, baseline, Budget{Tolerance: 5, Rules: []BudgetRule{
+		{Metric: BudgetMetricDuration},
+		{Metric: BudgetMetricDuration, Tolerance: limit(10)},
+		{Metric: BudgetMetricScore},
+	}})
+	testutil.AssertNoError(t, err)
+
+	r := report.Results
+	if r[0].Passed || !strings.Contains(r[0].Message, "rose 8.0% from baseline 1000.00") {
+		t.Errorf("expected an 8%% rise to fail a 5%% tolerance, got %+v", r[0])
+	}
+	if r[0].Baseline == nil || *r[0].Baseline != 1000 || *r[0].Tolerance != 5 {
+		t.Errorf("expected baseline 1000 with tolerance 5, got %+v", r[0])
+	}
+	testutil.AssertTrue(t, r[1].Passed, "the rule's own tolerance overrides the budget's")
+	testutil.AssertTrue(t, r[2].Passed, "an unchanged score passes")
+}
+
+func TestCheckBudget_BaselineScoreDrop(t *testing.T) {
+	report, err := CheckBudget(testutil.ProfileWithLongTasks(5), testutil.MinimalProfile(), Budget{Rules: []BudgetRule{
+		{Metric: BudgetMetricScore, Tolerance: limit(2)},
+	}})
+	testutil.AssertNoError(t, err)
+
+	if report.Results[0].Passed || !strings.Contains(report.Results[0].Message, "dropped") {
+		t.Errorf("expected a lower score to fail, got %+v", report.Results[0])
+	}
+}
+
+func TestCheckBudget_UnknownCategory(t *testing.T) {
+	report, err := CheckBudget(testutil.ProfileRun(1000, 30), nil, Budget{Rules: []BudgetRule{
+		{Metric: BudgetMetricCategory, Category: "GC/CC", Max: limit(1)},
+	}})
+	testutil.AssertNoError(t, err)
+
+	r := report.Results[0]
+	testutil.AssertFalse(t, r.Passed, "a misspelt category must not pass")
+	for _, want := range []string{`unknown category "GC/CC"`, "GC / CC", "JavaScript"} {
+		testutil.AssertStringContains(t, r.Message, want)
+	}
+}
+
+func TestCheckBudget_UnknownFunctionOrThread(t *testing.T) {
+	report, err := CheckBudget(testutil.ProfileRun(1000, 30), nil, Budget{Rules: []BudgetRule{
+		{Metric: BudgetMetricFunction, Function: "computeHsah", Max: limit(1000)},
+		{Metric: BudgetMetricFunction, Function: "computeHash", Thread: "GeckoMian", Max: limit(1000)},
+		{Metric: BudgetMetricCategory, Category: "JavaScript", Thread: "GeckoMian", Max: limit(100)},
+		{Metric: BudgetMetricFunction, Function: "computeHash", Max: limit(1000)},
+	}})
+	testutil.AssertNoError(t, err)
+
+	r := report.Results
+	testutil.AssertFalse(t, r[0].Passed, "a misspelt function must not pass")
+	testutil.AssertStringContains(t, r[0].Message, `function "computeHsah" was not sampled`)
+	for _, i := range []int{1, 2} {
+		testutil.AssertFalse(t, r[i].Passed, "a misspelt thread must not pass")
+		testutil.AssertStringContains(t, r[i].Message, `unknown thread "GeckoMian"`)
+	}
+	testutil.AssertTrue(t, r[3].Passed, "a sampled function is checked")
+}
+
+func TestCheckBudget_ToleranceWithoutBaseline(t *testing.T) {
+	report, err := CheckBudget(testutil.ProfileRun(1000, 30), nil, Budget{Tolerance: 5, Rules: []BudgetRule{
+		{Metric: BudgetMetricDuration},
+		{Metric: BudgetMetricLongTasks, Tolerance: limit(10)},
+		{Metric: BudgetMetricDuration, Max: limit(5000)},
+	}})
+	testutil.AssertNoError(t, err)
+
+	r := report.Results
+	for _, i := range []int{0, 1} {
+		if r[i].Passed || !strings.Contains(r[i].Message, "needs a baseline") {
+			t.Errorf("expected tolerance-only rule %d to fail without a baseline, got %+v", i, r[i])
+		}
+	}
+	testutil.AssertTrue(t, r[2].Passed, "a rule with an absolute limit is still checked")
+}
+
+func TestCheckBudget_BaselineNotMeasured(t *testing.T) {
+	report, err := CheckBudget(testutil.ProfileWithDelimiters(), testutil.MinimalProfile(), Budget{Rules: []BudgetRule{
+		{Metric: BudgetMetricOperationTime, Start: "DOMEvent", End: "Paint", Tolerance: limit(10)},
+	}})
+	testutil.AssertNoError(t, err)
+
+	r := report.Results[0]
+	if r.Passed || !strings.Contains(r.Message, "baseline: operation not measured") {
+		t.Errorf("expected an unmeasurable baseline to fail, got %+v", r)
+	}
+}
+
+func TestCheckBudget_Invalid(t *testing.T) {
+	_, err := CheckBudget(testutil.MinimalProfile(), nil, Budget{})
+	testutil.AssertError(t, err)
+}
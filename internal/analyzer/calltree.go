@@ -12,6 +12,7 @@ import (
 type FunctionStats struct {
 	Name          string  `json:"name"`
 	File          string  `json:"file,omitempty"`
+	Line          int     `json:"line,omitempty"` // Line the function starts on, when the profile records it
 	SelfTimeMs    float64 `json:"self_time_ms"`
 	RunningTimeMs float64 `json:"running_time_ms"`
 	SelfPercent   float64 `json:"self_percent"`
@@ -59,6 +60,7 @@ func AnalyzeCallTree(profile *parser.Profile, threadName string, limit int) Call
 		runningTime float64
 		sampleCount int
 		file        string
+		line        int
 	}
 	globalFuncStats := make(map[string]*funcData)
 
@@ -149,13 +151,13 @@ func AnalyzeCallTree(profile *parser.Profile, threadName string, limit int) Call
 		}
 
 		// Helper to get function name from frame index
-		getFuncName := func(frameIdx int) (string, string) {
+		getFuncName := func(frameIdx int) (string, string, int) {
 			if frameIdx < 0 || frameIdx >= frameTable.Length || frameIdx >= len(frameTable.Func) {
-				return "(unknown)", ""
+				return "(unknown)", "", 0
 			}
 			funcIdx := frameTable.Func[frameIdx]
 			if funcIdx < 0 || funcIdx >= funcTable.Length {
-				return "(unknown)", ""
+				return "(unknown)", "", 0
 			}
 
 			funcName := "(unknown)"
@@ -174,15 +176,20 @@ func AnalyzeCallTree(profile *parser.Profile, threadName string, limit int) Call
 				}
 			}
 
-			return funcName, fileName
+			line := 0
+			if funcIdx < len(funcTable.LineNumber) {
+				line = funcTable.LineNumber[funcIdx]
+			}
+
+			return funcName, fileName, line
 		}
 
 		// Convert leaf frame stats to function stats (aggregate by name)
 		for frameIdx, selfTime := range leafFrameTime {
-			funcName, fileName := getFuncName(frameIdx)
+			funcName, fileName, line := getFuncName(frameIdx)
 
 			if globalFuncStats[funcName] == nil {
-				globalFuncStats[funcName] = &funcData{file: fileName}
+				globalFuncStats[funcName] = &funcData{file: fileName, line: line}
 			}
 			globalFuncStats[funcName].selfTime += selfTime
 			globalFuncStats[funcName].sampleCount += leafFrameCount[frameIdx]
@@ -190,10 +197,10 @@ func AnalyzeCallTree(profile *parser.Profile, threadName string, limit int) Call
 
 		// Add running time
 		for frameIdx, runTime := range frameRunningTime {
-			funcName, fileName := getFuncName(frameIdx)
+			funcName, fileName, line := getFuncName(frameIdx)
 
 			if globalFuncStats[funcName] == nil {
-				globalFuncStats[funcName] = &funcData{file: fileName}
+				globalFuncStats[funcName] = &funcData{file: fileName, line: line}
 			}
 			globalFuncStats[funcName].runningTime += runTime
 		}
@@ -242,6 +249,7 @@ func AnalyzeCallTree(profile *parser.Profile, threadName string, limit int) Call
 		analysis.TopFunctions = append(analysis.TopFunctions, FunctionStats{
 			Name:          name,
 			File:          data.file,
+			Line:          data.line,
 			SelfTimeMs:    data.selfTime,
 			RunningTimeMs: data.runningTime,
 			SelfPercent:   selfPercent,
@@ -149,3 +149,17 @@ func TestFormatCallTree_Output(t *testing.T) {
 		t.Error("expected non-empty output")
 	}
 }
+
+func TestAnalyzeCallTree_FunctionLine(t *testing.T) {
+	analysis := AnalyzeCallTree(testutil.ProfileWithSourceLines(), "", 10)
+
+	for _, fn := range analysis.TopFunctions {
+		if fn.Name == "decrypt" {
+			if fn.File != "https://example.com/js/app.js" || fn.Line != 10 {
+				t.Errorf("decrypt at %s:%d, want app.js:10", fn.File, fn.Line)
+			}
+			return
+		}
+	}
+	t.Error("expected decrypt in the top functions")
+}
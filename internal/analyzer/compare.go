@@ -48,7 +48,9 @@ type DiffChanges struct {
 	LayoutChange          int     `json:"layout_change"`
 }
 
-// CompareProfiles compares two profiles and returns differences
+// CompareProfiles compares two profiles and returns differences. With a single run
+// per side it can only apply fixed thresholds; use CompareProfileSets to test
+// repeated runs for significance.
 func CompareProfiles(baseline, comparison *parser.Profile) ProfileDiff {
 	// Extract summaries in parallel
 	var baseSummary, compSummary ProfileSummary
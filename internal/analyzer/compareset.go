@@ -0,0 +1,334 @@
+package analyzer
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+
+	"github.com/CedricHerzog/perfowl/internal/parser"
+)
+
+// Profile set comparison settings
+const (
+	CompareSetDefaultFunctionLimit = 10  // Functions compared by self time
+	compareSetFunctionPool         = 200 // Functions collected per run before picking the ones to compare
+)
+
+// Metrics compared across profile sets
+const (
+	CompareMetricDuration      = "duration_ms"
+	CompareMetricGCTime        = "gc_time_ms"
+	CompareMetricLongTasks     = "long_tasks"
+	CompareMetricOperationTime = "operation_time_ms"
+)
+
+// CompareSetOptions configures a comparison of repeated runs
+type CompareSetOptions struct {
+	Operation     MeasureOptions // Operation time is compared when both patterns are set
+	Alpha         float64        // Significance level, SignificanceDefaultAlpha when zero
+	FunctionLimit int            // Functions compared by self time, CompareSetDefaultFunctionLimit when zero
+}
+
+// MetricComparison is one metric tested across baseline and candidate runs
+type MetricComparison struct {
+	Metric          string  `json:"metric"`
+	BaselineRuns    int     `json:"baseline_runs"`
+	CandidateRuns   int     `json:"candidate_runs"`
+	BaselineMedian  float64 `json:"baseline_median"`
+	CandidateMedian float64 `json:"candidate_median"`
+	ChangePercent   float64 `json:"change_percent"` // Change of the median
+	U               float64 `json:"u"`
+	PValue          float64 `json:"p_value"`
+	AdjustedPValue  float64 `json:"adjusted_p_value"` // Holm-Bonferroni across every metric and function; the verdict uses this
+	Exact           bool    `json:"exact"`
+	EffectSize      float64 `json:"effect_size"` // Cliff's delta; negative means the candidate is lower
+	EffectMagnitude string  `json:"effect_magnitude"`
+	Verdict         string  `json:"verdict"`
+}
+
+// ProfileSetComparison compares N baseline runs against M candidate runs with a
+// Mann-Whitney U test per metric
+type ProfileSetComparison struct {
+	BaselineRuns    int                `json:"baseline_runs"`
+	CandidateRuns   int                `json:"candidate_runs"`
+	Method          string             `json:"method"`
+	Correction      string             `json:"correction"` // Multiple-comparison correction applied to the p-values
+	Alpha           float64            `json:"alpha"`
+	Metrics         []MetricComparison `json:"metrics"`
+	Functions       []MetricComparison `json:"functions"` // Self time per function; Metric holds the function name
+	Improved        []string           `json:"improved"`
+	Regressed       []string           `json:"regressed"`
+	Recommendations []string           `json:"recommendations"`
+}
+
+// runMetrics holds the values measured in one run
+type runMetrics struct {
+	summary      ProfileSummary
+	operationMs  float64
+	hasOperation bool
+	selfTime     map[string]float64
+}
+
+// compareSetMetric reads one metric from a run; ok is false when the run lacks it
+type compareSetMetric struct {
+	name string
+	get  func(runMetrics) (float64, bool)
+}
+
+// CompareProfileSets compares repeated baseline runs against repeated candidate runs.
+// Each metric gets a two-sided Mann-Whitney U test, Cliff's delta as its effect size
+// and a verdict; lower is better for every metric. The p-values of all metrics and
+// functions are adjusted together with Holm-Bonferroni before the verdicts are
+// decided, so testing many functions does not inflate false regressions.
+// Functions are the union of the
+// heaviest functions by median self time on either side; a run where a function
+// does not appear counts as zero self time.
+func CompareProfileSets(baselines, candidates []*parser.Profile, opts CompareSetOptions) ProfileSetComparison {
+	if opts.Alpha <= 0 {
+		opts.Alpha = SignificanceDefaultAlpha
+	}
+	if opts.FunctionLimit <= 0 {
+		opts.FunctionLimit = CompareSetDefaultFunctionLimit
+	}
+
+	result := ProfileSetComparison{
+		BaselineRuns:    len(baselines),
+		CandidateRuns:   len(candidates),
+		Method:          "mann-whitney-u",
+		Correction:      "holm-bonferroni",
+		Alpha:           opts.Alpha,
+		Metrics:         make([]MetricComparison, 0),
+		Functions:       make([]MetricComparison, 0),
+		Improved:        make([]string, 0),
+		Regressed:       make([]string, 0),
+		Recommendations: make([]string, 0),
+	}
+
+	base := measureRuns(baselines, opts)
+	cand := measureRuns(candidates, opts)
+
+	values := func(runs []runMetrics, get func(runMetrics) (float64, bool)) []float64 {
+		out := make([]float64, 0, len(runs))
+		for _, r := range runs {
+			if v, ok := get(r); ok {
+				out = append(out, v)
+			}
+		}
+		return out
+	}
+	metrics := []compareSetMetric{
+		{CompareMetricDuration, func(r runMetrics) (float64, bool) { return r.summary.DurationMs, true }},
+		{CompareMetricGCTime, func(r runMetrics) (float64, bool) { return r.summary.GCTotalTimeMs, true }},
+		{CompareMetricLongTasks, func(r runMetrics) (float64, bool) { return float64(r.summary.LongTaskCount), true }},
+	}
+	if opts.Operation.StartPattern != "" && opts.Operation.EndPattern != "" {
+		metrics = append(metrics, compareSetMetric{CompareMetricOperationTime, func(r runMetrics) (float64, bool) { return r.operationMs, r.hasOperation }})
+	}
+	tests := make([]MannWhitneyResult, 0)
+	for _, metric := range metrics {
+		b, c := values(base, metric.get), values(cand, metric.get)
+		if len(b) == 0 || len(c) == 0 {
+			continue
+		}
+		m, test := compareMetric(metric.name, b, c)
+		result.Metrics = append(result.Metrics, m)
+		tests = append(tests, test)
+	}
+
+	for _, name := range compareSetFunctions(base, cand, opts.FunctionLimit) {
+		selfTime := func(r runMetrics) (float64, bool) { return r.selfTime[name], true }
+		m, test := compareMetric(name, values(base, selfTime), values(cand, selfTime))
+		result.Functions = append(result.Functions, m)
+		tests = append(tests, test)
+	}
+
+	applyHolmVerdicts(result.Metrics, result.Functions, tests, opts.Alpha)
+
+	for _, list := range [][]MetricComparison{result.Metrics, result.Functions} {
+		for _, m := range list {
+			switch m.Verdict {
+			case VerdictImproved:
+				result.Improved = append(result.Improved, fmt.Sprintf("%s reduced by %s (adjusted p=%.3f)", m.Metric, formatPercent(m.ChangePercent), m.AdjustedPValue))
+			case VerdictRegressed:
+				result.Regressed = append(result.Regressed, fmt.Sprintf("%s increased by %s (adjusted p=%.3f)", m.Metric, formatPercent(m.ChangePercent), m.AdjustedPValue))
+			}
+		}
+	}
+
+	result.Recommendations = compareSetRecommendations(result, opts)
+	return result
+}
+
+// measureRuns extracts the compared metrics from each run in parallel
+func measureRuns(profiles []*parser.Profile, opts CompareSetOptions) []runMetrics {
+	runs := make([]runMetrics, len(profiles))
+	var wg sync.WaitGroup
+	for i, profile := range profiles {
+		wg.Add(1)
+		go func(i int, profile *parser.Profile) {
+			defer wg.Done()
+			run := runMetrics{
+				summary:  extractSummary(profile, fmt.Sprintf("run %d", i+1)),
+				selfTime: make(map[string]float64),
+			}
+			if opts.Operation.StartPattern != "" && opts.Operation.EndPattern != "" {
+				if m, err := MeasureOperationAdvanced(profile, opts.Operation); err == nil {
+					run.operationMs, run.hasOperation = m.OperationTimeMs, true
+				}
+			}
+			for _, fn := range AnalyzeCallTree(profile, "", compareSetFunctionPool).TopFunctions {
+				run.selfTime[fn.Name] += fn.SelfTimeMs
+			}
+			runs[i] = run
+		}(i, profile)
+	}
+	wg.Wait()
+	return runs
+}
+
+// compareSetFunctions picks the functions with the highest median self time on
+// either side
+func compareSetFunctions(base, cand []runMetrics, limit int) []string {
+	names := make(map[string]bool)
+	for _, runs := range [][]runMetrics{base, cand} {
+		for _, r := range runs {
+			for name := range r.selfTime {
+				names[name] = true
+			}
+		}
+	}
+
+	median := func(runs []runMetrics, name string) float64 {
+		vals := make([]float64, 0, len(runs))
+		for _, r := range runs {
+			vals = append(vals, r.selfTime[name])
+		}
+		sort.Float64s(vals)
+		return percentile(vals, 50)
+	}
+	type ranked struct {
+		name   string
+		weight float64
+	}
+	all := make([]ranked, 0, len(names))
+	for name := range names {
+		w := median(base, name)
+		if c := median(cand, name); c > w {
+			w = c
+		}
+		if w > 0 {
+			all = append(all, ranked{name, w})
+		}
+	}
+	sort.Slice(all, func(i, j int) bool {
+		if all[i].weight != all[j].weight {
+			return all[i].weight > all[j].weight
+		}
+		return all[i].name < all[j].name
+	})
+	if len(all) > limit {
+		all = all[:limit]
+	}
+
+	out := make([]string, len(all))
+	for i, r := range all {
+		out[i] = r.name
+	}
+	return out
+}
+
+// compareMetric runs the significance test for one metric; the verdict is left
+// for applyHolmVerdicts
+func compareMetric(name string, baseline, candidate []float64) (MetricComparison, MannWhitneyResult) {
+	b := append([]float64(nil), baseline...)
+	c := append([]float64(nil), candidate...)
+	sort.Float64s(b)
+	sort.Float64s(c)
+
+	test := MannWhitneyU(b, c)
+	delta := CliffsDelta(b, c, test.U)
+	m := MetricComparison{
+		Metric:          name,
+		BaselineRuns:    len(b),
+		CandidateRuns:   len(c),
+		BaselineMedian:  percentile(b, 50),
+		CandidateMedian: percentile(c, 50),
+		U:               test.U,
+		PValue:          test.PValue,
+		Exact:           test.Exact,
+		EffectSize:      delta,
+		EffectMagnitude: effectMagnitude(delta),
+	}
+	m.ChangePercent = percentChange(m.BaselineMedian, m.CandidateMedian)
+	return m, test
+}
+
+// applyHolmVerdicts adjusts the p-values of the metrics and functions, whose tests
+// are given in the same order, as one family and decides each verdict from the
+// adjusted p-value. The smallest p-value the runs can produce is scaled the same
+// way, since the first step of Holm-Bonferroni tests against alpha over the
+// family size.
+func applyHolmVerdicts(metrics, functions []MetricComparison, tests []MannWhitneyResult, alpha float64) {
+	pValues := make([]float64, len(tests))
+	for i, test := range tests {
+		pValues[i] = test.PValue
+	}
+	adjusted := holmAdjust(pValues)
+
+	i := 0
+	for _, list := range [][]MetricComparison{metrics, functions} {
+		for j := range list {
+			test := tests[i]
+			test.PValue = adjusted[i]
+			test.MinP = math.Min(1, test.MinP*float64(len(tests)))
+			list[j].AdjustedPValue = adjusted[i]
+			list[j].Verdict = significanceVerdict(test, list[j].EffectSize, alpha)
+			i++
+		}
+	}
+}
+
+func compareSetRecommendations(result ProfileSetComparison, opts CompareSetOptions) []string {
+	recs := make([]string, 0)
+
+	if result.BaselineRuns == 0 || result.CandidateRuns == 0 {
+		return append(recs, "No runs were given for one side of the comparison; pass at least one baseline and one candidate profile")
+	}
+
+	inconclusive := false
+	for _, m := range result.Metrics {
+		if m.Verdict == VerdictInconclusive {
+			inconclusive = true
+			break
+		}
+	}
+	if inconclusive {
+		recs = append(recs, fmt.Sprintf("%d baseline and %d candidate runs cannot reach significance at alpha %.2f; record more runs (the smallest p-value must stay below alpha after correcting for %d tests)",
+			result.BaselineRuns, result.CandidateRuns, result.Alpha, len(result.Metrics)+len(result.Functions)))
+	}
+
+	if opts.Operation.StartPattern != "" && opts.Operation.EndPattern != "" {
+		measured := false
+		for _, m := range result.Metrics {
+			if m.Metric == CompareMetricOperationTime {
+				measured = true
+				if m.BaselineRuns < result.BaselineRuns || m.CandidateRuns < result.CandidateRuns {
+					recs = append(recs, fmt.Sprintf("The operation markers were missing in %d of %d runs; those runs were left out of the operation time test",
+						result.BaselineRuns+result.CandidateRuns-m.BaselineRuns-m.CandidateRuns, result.BaselineRuns+result.CandidateRuns))
+				}
+			}
+		}
+		if !measured {
+			recs = append(recs, fmt.Sprintf("No operation matching %q to %q was found on at least one side", opts.Operation.StartPattern, opts.Operation.EndPattern))
+		}
+	}
+
+	if len(result.Regressed) > 0 {
+		recs = append(recs, fmt.Sprintf("%d metric(s) regressed significantly; investigate those before merging", len(result.Regressed)))
+	} else if !inconclusive {
+		recs = append(recs, "No significant regressions were found")
+	}
+
+	return recs
+}
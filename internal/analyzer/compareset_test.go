@@ -0,0 +1,168 @@
+package analyzer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/CedricHerzog/perfowl/internal/parser"
+	"github.com/CedricHerzog/perfowl/internal/testutil"
+)
+
+// runs builds one profile per duration with the given computeHash samples
+func runs(durations []float64, hotSamples int) []*parser.Profile {
+	profiles := make([]*parser.Profile, 0, len(durations))
+	for _, d := range durations {
+		profiles = append(profiles, testutil.ProfileRun(d, hotSamples))
+	}
+	return profiles
+}
+
+func findMetric(list []MetricComparison, name string) *MetricComparison {
+	for i := range list {
+		if list[i].Metric == name {
+			return &list[i]
+		}
+	}
+	return nil
+}
+
+func TestCompareProfileSets_Regression(t *testing.T) {
+	baselines := runs([]float64{1000, 1010, 990, 1005, 995}, 30)
+	candidates := runs([]float64{1200, 1190, 1210, 1205, 1195}, 60)
+
+	result := CompareProfileSets(baselines, candidates, CompareSetOptions{})
+
+	if result.BaselineRuns != 5 || result.CandidateRuns != 5 {
+		t.Errorf("runs = %d/%d, want 5/5", result.BaselineRuns, result.CandidateRuns)
+	}
+	if result.Alpha != SignificanceDefaultAlpha || result.Method != "mann-whitney-u" {
+		t.Errorf("unexpected alpha/method %v/%q", result.Alpha, result.Method)
+	}
+
+	duration := findMetric(result.Metrics, CompareMetricDuration)
+	if duration == nil {
+		t.Fatal("expected a duration comparison")
+	}
+	if duration.Verdict != VerdictRegressed {
+		t.Errorf("duration verdict = %q, want regressed", duration.Verdict)
+	}
+	testutil.AssertFloatApproxEqual(t, duration.BaselineMedian, 1000, 1e-9)
+	testutil.AssertFloatApproxEqual(t, duration.CandidateMedian, 1200, 1e-9)
+	testutil.AssertFloatApproxEqual(t, duration.ChangePercent, 20, 1e-9)
+	testutil.AssertFloatApproxEqual(t, duration.EffectSize, 1, 1e-9)
+	if duration.EffectMagnitude != "large" {
+		t.Errorf("effect magnitude = %q, want large", duration.EffectMagnitude)
+	}
+
+	// Identical GC time in every run is not a change
+	if gc := findMetric(result.Metrics, CompareMetricGCTime); gc == nil || gc.Verdict != VerdictUnchanged {
+		t.Errorf("expected unchanged GC time, got %+v", gc)
+	}
+
+	hash := findMetric(result.Functions, "computeHash")
+	if hash == nil {
+		t.Fatal("expected computeHash in the function comparisons")
+	}
+	// Self time is identical within each side, so the tied samples use the normal approximation
+	if hash.Verdict != VerdictRegressed || hash.Exact {
+		t.Errorf("expected an approximate computeHash regression, got %+v", hash)
+	}
+	testutil.AssertFloatApproxEqual(t, hash.ChangePercent, 100, 1e-9)
+	if render := findMetric(result.Functions, "render"); render == nil || render.Verdict == VerdictRegressed {
+		t.Errorf("expected render unchanged, got %+v", render)
+	}
+
+	if len(result.Regressed) == 0 || !strings.Contains(result.Regressed[0], CompareMetricDuration) {
+		t.Errorf("expected duration in the regressions, got %v", result.Regressed)
+	}
+	testutil.AssertSliceEmpty(t, result.Improved)
+}
+
+func TestCompareProfileSets_Improvement(t *testing.T) {
+	baselines := runs([]float64{1200, 1190, 1210, 1205, 1195, 1215}, 30)
+	candidates := runs([]float64{1000, 1010, 990, 1005, 995, 1015}, 30)
+
+	result := CompareProfileSets(baselines, candidates, CompareSetOptions{})
+
+	if d := findMetric(result.Metrics, CompareMetricDuration); d == nil || d.Verdict != VerdictImproved {
+		t.Errorf("expected improved duration, got %+v", d)
+	}
+	testutil.AssertSliceEmpty(t, result.Regressed)
+	testutil.AssertSliceLen(t, result.Improved, 1)
+}
+
+func TestCompareProfileSets_HolmCorrection(t *testing.T) {
+	// Four runs per side reach p = 0.029 on their own, which does not survive
+	// correcting for the other metrics and functions
+	baselines := runs([]float64{1200, 1190, 1210, 1205}, 30)
+	candidates := runs([]float64{1000, 1010, 990, 1005}, 30)
+
+	result := CompareProfileSets(baselines, candidates, CompareSetOptions{})
+
+	if result.Correction != "holm-bonferroni" {
+		t.Errorf("Correction = %q, want holm-bonferroni", result.Correction)
+	}
+	d := findMetric(result.Metrics, CompareMetricDuration)
+	if d == nil {
+		t.Fatal("expected a duration comparison")
+	}
+	if d.PValue >= SignificanceDefaultAlpha {
+		t.Fatalf("expected a raw p-value below alpha, got %v", d.PValue)
+	}
+	tests := len(result.Metrics) + len(result.Functions)
+	testutil.AssertFloatApproxEqual(t, d.AdjustedPValue, d.PValue*float64(tests), 1e-9)
+	if d.Verdict == VerdictImproved {
+		t.Errorf("expected the correction to withhold the improvement, got %+v", d)
+	}
+	testutil.AssertSliceEmpty(t, result.Improved)
+}
+
+func TestCompareProfileSets_Inconclusive(t *testing.T) {
+	result := CompareProfileSets(runs([]float64{1000}, 30), runs([]float64{2000}, 30), CompareSetOptions{})
+
+	d := findMetric(result.Metrics, CompareMetricDuration)
+	if d == nil || d.Verdict != VerdictInconclusive {
+		t.Errorf("expected an inconclusive single-run comparison, got %+v", d)
+	}
+	found := false
+	for _, r := range result.Recommendations {
+		if strings.Contains(r, "record more runs") {
+			found = true
+		}
+	}
+	testutil.AssertTrue(t, found, "expected a recommendation to record more runs")
+}
+
+func TestCompareProfileSets_FunctionLimit(t *testing.T) {
+	result := CompareProfileSets(runs([]float64{1000}, 30), runs([]float64{1000}, 30), CompareSetOptions{FunctionLimit: 1})
+
+	testutil.AssertSliceLen(t, result.Functions, 1)
+	if result.Functions[0].Metric != "computeHash" {
+		t.Errorf("top function = %q, want computeHash", result.Functions[0].Metric)
+	}
+}
+
+func TestCompareProfileSets_OperationNotFound(t *testing.T) {
+	result := CompareProfileSets(runs([]float64{1000}, 30), runs([]float64{1000}, 30), CompareSetOptions{
+		Operation: MeasureOptions{StartPattern: "missing-start", EndPattern: "missing-end"},
+	})
+
+	if findMetric(result.Metrics, CompareMetricOperationTime) != nil {
+		t.Error("expected no operation time comparison when the markers are missing")
+	}
+	found := false
+	for _, r := range result.Recommendations {
+		if strings.Contains(r, "missing-start") {
+			found = true
+		}
+	}
+	testutil.AssertTrue(t, found, "expected a recommendation about the missing operation")
+}
+
+func TestCompareProfileSets_Empty(t *testing.T) {
+	result := CompareProfileSets(nil, nil, CompareSetOptions{})
+
+	testutil.AssertSliceEmpty(t, result.Metrics)
+	testutil.AssertSliceEmpty(t, result.Functions)
+	testutil.AssertSliceLen(t, result.Recommendations, 1)
+}
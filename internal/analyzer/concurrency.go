@@ -0,0 +1,323 @@
+package analyzer
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/CedricHerzog/perfowl/internal/parser"
+)
+
+// Concurrency timeline settings
+const (
+	ConcurrencyMaxBuckets     = 500  // Upper bound on timeline points; the bucket width grows to fit
+	ConcurrencyBusyThreshold  = 0.5  // A thread busy for at least this fraction of a bucket counts as running
+	ConcurrencySerialMinMs    = 10.0 // Shortest serial phase that is reported
+	ConcurrencyMaxSerialPhase = 20   // Serial phases listed, longest first
+)
+
+// busyInterval is a span of CPU time on one thread
+type busyInterval struct {
+	start float64
+	end   float64
+}
+
+// ConcurrencyThread is the activity of one thread over the timeline
+type ConcurrencyThread struct {
+	Name        string  `json:"name"`
+	TID         string  `json:"tid,omitempty"`
+	IsMain      bool    `json:"is_main,omitempty"`
+	IsWorker    bool    `json:"is_worker,omitempty"`
+	BusyMs      float64 `json:"busy_ms"`
+	Utilization float64 `json:"utilization_percent"` // Busy time over the timeline span
+}
+
+// ConcurrencyPoint is the activity in one timeline bucket
+type ConcurrencyPoint struct {
+	Time    float64   `json:"time_ms"`      // Bucket start
+	Active  float64   `json:"active"`       // Sum of thread utilizations, i.e. effective running threads
+	Running int       `json:"running"`      // Threads busy for at least ConcurrencyBusyThreshold of the bucket
+	Threads []float64 `json:"thread_usage"` // Busy fraction per thread, in ConcurrencyAnalysis.Threads order
+}
+
+// ConcurrencyLevel is the time spent with a given number of threads running
+type ConcurrencyLevel struct {
+	Level      int     `json:"level"`
+	DurationMs float64 `json:"duration_ms"`
+	Percent    float64 `json:"percent"`
+}
+
+// SerialPhase is a period where only the main thread was running
+type SerialPhase struct {
+	StartTime  float64 `json:"start_time_ms"`
+	EndTime    float64 `json:"end_time_ms"`
+	DurationMs float64 `json:"duration_ms"`
+}
+
+// ConcurrencyAnalysis describes how many threads ran in parallel over time
+type ConcurrencyAnalysis struct {
+	StartTime          float64             `json:"start_time_ms"`
+	EndTime            float64             `json:"end_time_ms"`
+	BucketMs           float64             `json:"bucket_ms"`
+	ThreadCount        int                 `json:"thread_count"`
+	WorkerCount        int                 `json:"worker_count"`
+	AverageParallelism float64             `json:"average_parallelism"` // Busy thread time over time with any thread busy
+	PeakConcurrency    int                 `json:"peak_concurrency"`
+	SerialTimeMs       float64             `json:"serial_time_ms"`
+	SerialPercent      float64             `json:"serial_percent"`
+	LoadImbalance      float64             `json:"load_imbalance"` // 1 - mean/max worker busy time; 0 is perfectly balanced
+	Threads            []ConcurrencyThread `json:"threads"`
+	Levels             []ConcurrencyLevel  `json:"levels"`
+	SerialPhases       []SerialPhase       `json:"serial_phases"`
+	Timeline           []ConcurrencyPoint  `json:"timeline,omitempty"`
+	Recommendations    []string            `json:"recommendations,omitempty"`
+}
+
+// AnalyzeConcurrency builds a time series of how many threads are running at each
+// moment from the sample timestamps and per-sample CPU deltas. Each sample's CPU
+// time is placed at the end of the gap since the previous sample. bucketMs sets the
+// timeline resolution; when it is not positive the profile interval is used, widened
+// so the timeline has at most ConcurrencyMaxBuckets points.
+func AnalyzeConcurrency(profile *parser.Profile, bucketMs float64) ConcurrencyAnalysis {
+	analysis := ConcurrencyAnalysis{
+		Threads:      make([]ConcurrencyThread, 0),
+		Levels:       make([]ConcurrencyLevel, 0),
+		SerialPhases: make([]SerialPhase, 0),
+	}
+
+	var busy [][]busyInterval
+	start, end := math.Inf(1), math.Inf(-1)
+	for i := range profile.Threads {
+		thread := &profile.Threads[i]
+		intervals := threadBusyIntervals(profile, thread)
+		if len(intervals) == 0 {
+			continue
+		}
+		t := ConcurrencyThread{
+			Name:     thread.Name,
+			TID:      thread.TID.String(),
+			IsMain:   thread.IsMainThread,
+			IsWorker: isWorkerThread(thread),
+		}
+		for _, iv := range intervals {
+			t.BusyMs += iv.end - iv.start
+		}
+		start = math.Min(start, intervals[0].start)
+		end = math.Max(end, intervals[len(intervals)-1].end)
+		analysis.Threads = append(analysis.Threads, t)
+		busy = append(busy, intervals)
+	}
+
+	if len(analysis.Threads) == 0 {
+		analysis.Recommendations = concurrencyRecommendations(analysis)
+		return analysis
+	}
+
+	span := end - start
+	if bucketMs <= 0 {
+		bucketMs = profile.Meta.Interval
+		if bucketMs <= 0 {
+			bucketMs = 1
+		}
+	}
+	if span/bucketMs > ConcurrencyMaxBuckets {
+		bucketMs = span / ConcurrencyMaxBuckets
+	}
+	buckets := int(math.Ceil(span / bucketMs))
+	if buckets == 0 {
+		buckets = 1
+	}
+
+	analysis.StartTime, analysis.EndTime, analysis.BucketMs = start, end, bucketMs
+	analysis.ThreadCount = len(analysis.Threads)
+
+	// Spread each thread's busy intervals over the buckets they overlap
+	usage := make([][]float64, buckets)
+	for b := range usage {
+		usage[b] = make([]float64, len(busy))
+	}
+	for t, intervals := range busy {
+		for _, iv := range intervals {
+			for b := int((iv.start - start) / bucketMs); b < buckets; b++ {
+				bStart := start + float64(b)*bucketMs
+				if bStart >= iv.end {
+					break
+				}
+				if overlap := math.Min(iv.end, bStart+bucketMs) - math.Max(iv.start, bStart); overlap > 0 {
+					usage[b][t] += overlap / bucketMs
+				}
+			}
+		}
+	}
+
+	levels := make(map[int]float64)
+	var busyWall float64
+	serialStart := -1.0
+	closeSerial := func(at float64) {
+		if serialStart >= 0 && at-serialStart >= ConcurrencySerialMinMs {
+			analysis.SerialPhases = append(analysis.SerialPhases, SerialPhase{
+				StartTime:  serialStart,
+				EndTime:    at,
+				DurationMs: at - serialStart,
+			})
+		}
+		serialStart = -1
+	}
+
+	analysis.Timeline = make([]ConcurrencyPoint, buckets)
+	for b, threads := range usage {
+		p := ConcurrencyPoint{Time: start + float64(b)*bucketMs, Threads: threads}
+		width := math.Min(bucketMs, end-p.Time)
+		mainRunning := false
+		for t, u := range threads {
+			threads[t] = math.Min(1, u)
+			p.Active += threads[t]
+			if threads[t] >= ConcurrencyBusyThreshold {
+				p.Running++
+				mainRunning = mainRunning || analysis.Threads[t].IsMain
+			}
+		}
+		mainOnly := mainRunning && p.Running == 1
+		analysis.Timeline[b] = p
+
+		levels[p.Running] += width
+		if p.Running > analysis.PeakConcurrency {
+			analysis.PeakConcurrency = p.Running
+		}
+		if p.Active > 0 {
+			busyWall += width
+		}
+		if mainOnly {
+			analysis.SerialTimeMs += width
+			if serialStart < 0 {
+				serialStart = p.Time
+			}
+		} else {
+			closeSerial(p.Time)
+		}
+	}
+	closeSerial(end)
+
+	if busyWall > 0 {
+		var busyTotal float64
+		for _, t := range analysis.Threads {
+			busyTotal += t.BusyMs
+		}
+		analysis.AverageParallelism = busyTotal / busyWall
+	}
+	if span > 0 {
+		analysis.SerialPercent = analysis.SerialTimeMs / span * 100
+		for i := range analysis.Threads {
+			analysis.Threads[i].Utilization = analysis.Threads[i].BusyMs / span * 100
+		}
+	}
+
+	for level, ms := range levels {
+		l := ConcurrencyLevel{Level: level, DurationMs: ms}
+		if span > 0 {
+			l.Percent = ms / span * 100
+		}
+		analysis.Levels = append(analysis.Levels, l)
+	}
+	sort.Slice(analysis.Levels, func(i, j int) bool { return analysis.Levels[i].Level < analysis.Levels[j].Level })
+
+	sort.Slice(analysis.SerialPhases, func(i, j int) bool {
+		if analysis.SerialPhases[i].DurationMs != analysis.SerialPhases[j].DurationMs {
+			return analysis.SerialPhases[i].DurationMs > analysis.SerialPhases[j].DurationMs
+		}
+		return analysis.SerialPhases[i].StartTime < analysis.SerialPhases[j].StartTime
+	})
+	if len(analysis.SerialPhases) > ConcurrencyMaxSerialPhase {
+		analysis.SerialPhases = analysis.SerialPhases[:ConcurrencyMaxSerialPhase]
+	}
+
+	// Load imbalance across workers: how far the average worker falls short of the busiest
+	var workerTotal, workerMax float64
+	for _, t := range analysis.Threads {
+		if !t.IsWorker {
+			continue
+		}
+		analysis.WorkerCount++
+		workerTotal += t.BusyMs
+		workerMax = math.Max(workerMax, t.BusyMs)
+	}
+	if analysis.WorkerCount > 1 && workerMax > 0 {
+		analysis.LoadImbalance = 1 - workerTotal/float64(analysis.WorkerCount)/workerMax
+	}
+
+	analysis.Recommendations = concurrencyRecommendations(analysis)
+	return analysis
+}
+
+// threadBusyIntervals returns the spans a thread was running, in time order. A
+// sample's CPU time (ThreadCPUDelta, or the whole gap for a non-idle sample without
+// it) is placed at the end of the gap since the previous sample; adjacent spans are
+// merged.
+func threadBusyIntervals(profile *parser.Profile, thread *parser.Thread) []busyInterval {
+	samples := &thread.Samples
+	n := len(samples.Time)
+	if samples.Length < n {
+		n = samples.Length
+	}
+	interval := profile.Meta.Interval
+	if interval <= 0 {
+		interval = 1
+	}
+
+	var intervals []busyInterval
+	for i := 0; i < n; i++ {
+		t := samples.Time[i]
+		gap := interval
+		if i > 0 {
+			gap = t - samples.Time[i-1]
+		}
+		if gap <= 0 {
+			continue
+		}
+
+		var cpu float64
+		if i < len(samples.ThreadCPUDelta) && samples.ThreadCPUDelta[i] > 0 {
+			cpu = float64(samples.ThreadCPUDelta[i]) / 1000.0
+		} else if len(samples.ThreadCPUDelta) == 0 {
+			stackIdx := -1
+			if i < len(samples.Stack) {
+				stackIdx = samples.Stack[i]
+			}
+			if !isIdleSample(profile, thread, stackIdx) {
+				// Without CPU deltas a long gap means the thread was asleep between samples
+				cpu = math.Min(gap, interval)
+			}
+		}
+		cpu = math.Min(cpu, gap)
+		if cpu <= 0 {
+			continue
+		}
+
+		iv := busyInterval{start: t - cpu, end: t}
+		if last := len(intervals) - 1; last >= 0 && iv.start <= intervals[last].end {
+			intervals[last].end = math.Max(intervals[last].end, iv.end)
+			continue
+		}
+		intervals = append(intervals, iv)
+	}
+	return intervals
+}
+
+// concurrencyRecommendations suggests follow-ups based on thread parallelism
+func concurrencyRecommendations(analysis ConcurrencyAnalysis) []string {
+	var recs []string
+	if analysis.ThreadCount == 0 {
+		return append(recs, "No thread activity was found; the profile needs samples with timestamps")
+	}
+
+	if analysis.SerialPercent > 30 {
+		recs = append(recs, fmt.Sprintf("Only the main thread was running for %.0f%% of the profile; move independent work to workers to overlap it", analysis.SerialPercent))
+	}
+	if analysis.WorkerCount > 1 && analysis.LoadImbalance > 0.3 {
+		recs = append(recs, fmt.Sprintf("Worker load is imbalanced (%.0f%%); split work into smaller chunks or use a shared queue so idle workers can pick up more", analysis.LoadImbalance*100))
+	}
+	if analysis.WorkerCount > 1 && analysis.AverageParallelism < float64(analysis.WorkerCount)/2 {
+		recs = append(recs, fmt.Sprintf("Average parallelism is %.2f with %d workers; workers spend most of their time waiting", analysis.AverageParallelism, analysis.WorkerCount))
+	}
+	return recs
+}
@@ -0,0 +1,104 @@
+package analyzer
+
+import (
+	"testing"
+
+	"github.com/CedricHerzog/perfowl/internal/testutil"
+)
+
+func TestAnalyzeConcurrency_Empty(t *testing.T) {
+	analysis := AnalyzeConcurrency(testutil.MinimalProfile(), 0)
+
+	if analysis.ThreadCount != 0 || len(analysis.Timeline) != 0 {
+		t.Errorf("expected no activity, got %d threads and %d points", analysis.ThreadCount, len(analysis.Timeline))
+	}
+	if analysis.Threads == nil || analysis.Levels == nil || analysis.SerialPhases == nil {
+		t.Error("expected non-nil slices")
+	}
+	if len(analysis.Recommendations) != 1 {
+		t.Errorf("expected a single recommendation, got %v", analysis.Recommendations)
+	}
+}
+
+func TestAnalyzeConcurrency_Timeline(t *testing.T) {
+	analysis := AnalyzeConcurrency(testutil.ProfileWithConcurrency(), 0)
+
+	if analysis.ThreadCount != 3 || analysis.WorkerCount != 2 {
+		t.Fatalf("threads = %d, workers = %d, want 3 and 2", analysis.ThreadCount, analysis.WorkerCount)
+	}
+	testutil.AssertFloatApproxEqual(t, analysis.StartTime, 0, 0.001)
+	testutil.AssertFloatApproxEqual(t, analysis.EndTime, 100, 0.001)
+	testutil.AssertFloatApproxEqual(t, analysis.BucketMs, 1, 0.001)
+	if len(analysis.Timeline) != 100 {
+		t.Fatalf("expected 100 timeline points, got %d", len(analysis.Timeline))
+	}
+
+	testutil.AssertFloatApproxEqual(t, analysis.Threads[0].BusyMs, 60, 0.001)
+	testutil.AssertFloatApproxEqual(t, analysis.Threads[1].BusyMs, 40, 0.001)
+	testutil.AssertFloatApproxEqual(t, analysis.Threads[2].BusyMs, 20, 0.001)
+
+	// 120ms of busy thread time over 100ms with something running
+	testutil.AssertFloatApproxEqual(t, analysis.AverageParallelism, 1.2, 0.001)
+	if analysis.PeakConcurrency != 2 {
+		t.Errorf("PeakConcurrency = %d, want 2", analysis.PeakConcurrency)
+	}
+	if p := analysis.Timeline[50]; p.Running != 2 || p.Threads[0] != 0 {
+		t.Errorf("point at 50ms = %+v, want the two workers running", p)
+	}
+}
+
+func TestAnalyzeConcurrency_LevelsAndSerialPhases(t *testing.T) {
+	analysis := AnalyzeConcurrency(testutil.ProfileWithConcurrency(), 0)
+
+	if len(analysis.Levels) != 2 {
+		t.Fatalf("expected 2 concurrency levels, got %+v", analysis.Levels)
+	}
+	if analysis.Levels[0].Level != 1 || analysis.Levels[1].Level != 2 {
+		t.Errorf("levels = %+v, want 1 and 2", analysis.Levels)
+	}
+	testutil.AssertFloatApproxEqual(t, analysis.Levels[0].DurationMs, 80, 0.001)
+	testutil.AssertFloatApproxEqual(t, analysis.Levels[1].Percent, 20, 0.001)
+
+	testutil.AssertFloatApproxEqual(t, analysis.SerialTimeMs, 60, 0.001)
+	testutil.AssertFloatApproxEqual(t, analysis.SerialPercent, 60, 0.001)
+	if len(analysis.SerialPhases) != 2 {
+		t.Fatalf("expected 2 serial phases, got %+v", analysis.SerialPhases)
+	}
+	// Longest first
+	testutil.AssertFloatApproxEqual(t, analysis.SerialPhases[0].StartTime, 0, 0.001)
+	testutil.AssertFloatApproxEqual(t, analysis.SerialPhases[0].DurationMs, 40, 0.001)
+	testutil.AssertFloatApproxEqual(t, analysis.SerialPhases[1].StartTime, 80, 0.001)
+
+	// Workers busy 40ms and 20ms: mean 30 over max 40
+	testutil.AssertFloatApproxEqual(t, analysis.LoadImbalance, 0.25, 0.001)
+}
+
+func TestAnalyzeConcurrency_BucketSize(t *testing.T) {
+	analysis := AnalyzeConcurrency(testutil.ProfileWithConcurrency(), 10)
+
+	if len(analysis.Timeline) != 10 {
+		t.Fatalf("expected 10 timeline points, got %d", len(analysis.Timeline))
+	}
+	// 40-50ms: both workers busy the whole bucket
+	testutil.AssertFloatApproxEqual(t, analysis.Timeline[4].Active, 2, 0.001)
+}
+
+func TestThreadBusyIntervals_UsesSampleTimes(t *testing.T) {
+	profile := testutil.NewProfileBuilder().
+		WithInterval(1).
+		WithThread(testutil.NewThreadBuilder("DOM Worker").
+			WithSamples(testutil.NewSamplesBuilder().
+				AddSampleWithCPUDelta(0, 500, 0).
+				AddSampleWithCPUDelta(0, 510, 4000).
+				AddSampleWithCPUDelta(0, 511, 1000).
+				Build()).
+			Build()).
+		Build()
+
+	intervals := threadBusyIntervals(profile, &profile.Threads[0])
+	if len(intervals) != 1 {
+		t.Fatalf("expected adjacent spans to merge, got %+v", intervals)
+	}
+	testutil.AssertFloatApproxEqual(t, intervals[0].start, 506, 0.001)
+	testutil.AssertFloatApproxEqual(t, intervals[0].end, 511, 0.001)
+}
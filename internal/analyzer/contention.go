@@ -67,25 +67,14 @@ func AnalyzeContention(profile *parser.Profile) ContentionAnalysis {
 	for _, thread := range profile.Threads {
 		isWorker := isWorkerThread(&thread)
 
-		// Estimate thread active periods from samples
-		interval := profile.Meta.Interval
-		sampleTime := 0.0
-
-		for i := 0; i < thread.Samples.Length; i++ {
-			cpuDelta := interval
-			if i < len(thread.Samples.ThreadCPUDelta) && thread.Samples.ThreadCPUDelta[i] > 0 {
-				cpuDelta = float64(thread.Samples.ThreadCPUDelta[i]) / 1000.0
-			}
-
-			if cpuDelta > 0 {
-				activities = append(activities, threadActivity{
-					name:      thread.Name,
-					startTime: sampleTime,
-					endTime:   sampleTime + cpuDelta,
-					isWorker:  isWorker,
-				})
-			}
-			sampleTime += interval
+		// Thread active periods from the sample timestamps and CPU deltas
+		for _, iv := range threadBusyIntervals(profile, &thread) {
+			activities = append(activities, threadActivity{
+				name:      thread.Name,
+				startTime: iv.start,
+				endTime:   iv.end,
+				isWorker:  isWorker,
+			})
 		}
 
 		// Process markers for GC and IPC events
@@ -0,0 +1,413 @@
+package analyzer
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/CedricHerzog/perfowl/internal/parser"
+)
+
+// Critical path segment kinds
+const (
+	CriticalPathRun     = "run"     // A thread running or waiting to run on the path
+	CriticalPathMessage = "message" // A message in flight between two threads
+)
+
+// Dependency edge kinds
+const (
+	DependencyMessage = "postMessage"
+	DependencyFlow    = "flow"
+	DependencyIPC     = "ipc"
+)
+
+// Critical path settings
+const (
+	CriticalPathWaitWindowMs = 5.0  // How far before a receive the thread must have been idle to be waiting on it
+	CriticalPathWaitBusyMax  = 0.5  // Busy fraction of that window below which the thread counts as idle
+	criticalPathMaxHops      = 1000 // Guards the backward walk against cycles in malformed profiles
+)
+
+// threadEdge is a dependency from a point on one thread to a later point on another
+type threadEdge struct {
+	from     int
+	to       int
+	sendTime float64
+	recvTime float64
+	kind     string
+}
+
+// CriticalPathSegment is one step of the critical path
+type CriticalPathSegment struct {
+	Kind       string  `json:"kind"`   // CriticalPathRun or CriticalPathMessage
+	Thread     string  `json:"thread"` // "from → to" for messages
+	StartTime  float64 `json:"start_time_ms"`
+	EndTime    float64 `json:"end_time_ms"`
+	DurationMs float64 `json:"duration_ms"`
+	BusyMs     float64 `json:"busy_ms,omitempty"` // CPU time of the thread during a run segment
+	Percent    float64 `json:"percent"`           // Share of the end-to-end time
+	Via        string  `json:"via,omitempty"`     // Dependency kind of a message segment
+}
+
+// CriticalPathThread is the contribution and slack of one thread
+type CriticalPathThread struct {
+	Thread         string  `json:"thread"`
+	OnCriticalPath bool    `json:"on_critical_path"`
+	ContributionMs float64 `json:"contribution_ms"`
+	Percent        float64 `json:"percent"`
+	BusyMs         float64 `json:"busy_ms"`
+	FinishTime     float64 `json:"finish_time_ms"` // Last message sent or busy time in the window
+	SlackMs        float64 `json:"slack_ms"`       // How much later it could have finished without delaying the end
+}
+
+// CriticalPathAnalysis explains what determined the time between two delimiters
+type CriticalPathAnalysis struct {
+	StartMarker     DelimiterMarker       `json:"start_marker"`
+	EndMarker       DelimiterMarker       `json:"end_marker"`
+	TotalMs         float64               `json:"total_ms"`
+	Dependencies    int                   `json:"dependencies"` // Cross-thread edges found in the window
+	Segments        []CriticalPathSegment `json:"segments"`
+	Threads         []CriticalPathThread  `json:"threads"`
+	Recommendations []string              `json:"recommendations,omitempty"`
+}
+
+// AnalyzeCriticalPath computes the cross-thread critical path between a start and
+// an end delimiter matched like MeasureOperationAdvanced. It walks backwards from
+// the end marker's thread: whenever the thread was idle before receiving a message,
+// flow or IPC reply, the path jumps to the sending thread at the time it was sent.
+// The segments are returned in time order along with each thread's contribution and
+// its slack relative to the thread whose message the end thread waited on last.
+func AnalyzeCriticalPath(profile *parser.Profile, opts MeasureOptions) (*CriticalPathAnalysis, error) {
+	measurement, err := MeasureOperationAdvanced(profile, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	start, end := measurement.StartMarker.TimeMs, measurement.EndMarker.TimeMs
+	analysis := &CriticalPathAnalysis{
+		StartMarker: measurement.StartMarker,
+		EndMarker:   measurement.EndMarker,
+		TotalMs:     measurement.OperationTimeMs,
+		Segments:    make([]CriticalPathSegment, 0),
+		Threads:     make([]CriticalPathThread, 0),
+	}
+
+	endThread := delimiterThread(profile, measurement.EndMarker)
+	if endThread < 0 {
+		return nil, fmt.Errorf("thread of end marker '%s' not found", measurement.EndMarker.Name)
+	}
+
+	busy := make([][]busyInterval, len(profile.Threads))
+	for i := range profile.Threads {
+		busy[i] = threadBusyIntervals(profile, &profile.Threads[i])
+	}
+
+	// Incoming edges per thread, latest receive first
+	var edges []threadEdge
+	incoming := make(map[int][]threadEdge)
+	for _, e := range threadDependencies(profile) {
+		if e.recvTime <= start || e.recvTime > end {
+			continue
+		}
+		edges = append(edges, e)
+		incoming[e.to] = append(incoming[e.to], e)
+	}
+	for _, in := range incoming {
+		sort.Slice(in, func(i, j int) bool { return in[i].recvTime > in[j].recvTime })
+	}
+	analysis.Dependencies = len(edges)
+
+	waitWindow := math.Max(CriticalPathWaitWindowMs, 2*profile.Meta.Interval)
+	waitingOn := func(e threadEdge) bool {
+		from := math.Max(start, e.recvTime-waitWindow)
+		if e.recvTime <= from {
+			return true
+		}
+		return busyBetween(busy[e.to], from, e.recvTime)/(e.recvTime-from) < CriticalPathWaitBusyMax
+	}
+
+	// Walk backwards from the end, jumping to the sender whenever a thread waited on it
+	var segments []CriticalPathSegment
+	thread, t := endThread, end
+	joinTime := end
+	for hop := 0; hop < criticalPathMaxHops; hop++ {
+		var next *threadEdge
+		for _, e := range incoming[thread] {
+			if e.recvTime <= t && e.sendTime < t && waitingOn(e) {
+				e := e
+				next = &e
+				break
+			}
+		}
+		if next == nil || next.sendTime <= start {
+			segments = append(segments, criticalRunSegment(profile, busy, thread, start, t))
+			break
+		}
+
+		segments = append(segments, criticalRunSegment(profile, busy, thread, next.recvTime, t))
+		segments = append(segments, CriticalPathSegment{
+			Kind:       CriticalPathMessage,
+			Thread:     threadLabel(&profile.Threads[next.from]) + " → " + threadLabel(&profile.Threads[next.to]),
+			StartTime:  next.sendTime,
+			EndTime:    next.recvTime,
+			DurationMs: next.recvTime - next.sendTime,
+			Via:        next.kind,
+		})
+		if thread == endThread && joinTime == end {
+			joinTime = next.sendTime
+		}
+		thread, t = next.from, next.sendTime
+	}
+
+	// Chronological order, dropping empty steps
+	for i := len(segments) - 1; i >= 0; i-- {
+		s := segments[i]
+		if s.DurationMs <= 0 && s.Kind == CriticalPathRun {
+			continue
+		}
+		if analysis.TotalMs > 0 {
+			s.Percent = s.DurationMs / analysis.TotalMs * 100
+		}
+		analysis.Segments = append(analysis.Segments, s)
+	}
+
+	analysis.Threads = criticalPathThreads(profile, analysis, busy, edges, start, end, joinTime)
+	analysis.Recommendations = criticalPathRecommendations(analysis)
+	return analysis, nil
+}
+
+// criticalRunSegment builds a run segment for a thread between two times
+func criticalRunSegment(profile *parser.Profile, busy [][]busyInterval, thread int, from, to float64) CriticalPathSegment {
+	return CriticalPathSegment{
+		Kind:       CriticalPathRun,
+		Thread:     threadLabel(&profile.Threads[thread]),
+		StartTime:  from,
+		EndTime:    to,
+		DurationMs: math.Max(0, to-from),
+		BusyMs:     busyBetween(busy[thread], from, to),
+	}
+}
+
+// criticalPathThreads aggregates the path per thread and computes the slack of the others
+func criticalPathThreads(profile *parser.Profile, analysis *CriticalPathAnalysis, busy [][]busyInterval, edges []threadEdge, start, end, joinTime float64) []CriticalPathThread {
+	byLabel := make(map[string]*CriticalPathThread)
+	var order []string
+	get := func(label string) *CriticalPathThread {
+		t := byLabel[label]
+		if t == nil {
+			t = &CriticalPathThread{Thread: label}
+			byLabel[label] = t
+			order = append(order, label)
+		}
+		return t
+	}
+
+	for _, s := range analysis.Segments {
+		if s.Kind != CriticalPathRun {
+			continue
+		}
+		t := get(s.Thread)
+		t.OnCriticalPath = true
+		t.ContributionMs += s.DurationMs
+	}
+
+	lastSend := make(map[int]float64)
+	for _, e := range edges {
+		if e.sendTime >= start && e.sendTime > lastSend[e.from] {
+			lastSend[e.from] = e.sendTime
+		}
+	}
+
+	for i := range profile.Threads {
+		busyMs := busyBetween(busy[i], start, end)
+		finish, sent := lastSend[i]
+		if !sent {
+			if busyMs == 0 {
+				continue
+			}
+			for _, iv := range busy[i] {
+				if iv.start < end && iv.end > start {
+					finish = math.Min(iv.end, end)
+				}
+			}
+		}
+
+		t := get(threadLabel(&profile.Threads[i]))
+		t.BusyMs += busyMs
+		t.FinishTime = math.Max(t.FinishTime, finish)
+		if !t.OnCriticalPath {
+			t.SlackMs = math.Max(0, joinTime-t.FinishTime)
+		}
+	}
+
+	threads := make([]CriticalPathThread, 0, len(order))
+	for _, label := range order {
+		t := byLabel[label]
+		if analysis.TotalMs > 0 {
+			t.Percent = t.ContributionMs / analysis.TotalMs * 100
+		}
+		threads = append(threads, *t)
+	}
+	sort.SliceStable(threads, func(i, j int) bool {
+		if threads[i].ContributionMs != threads[j].ContributionMs {
+			return threads[i].ContributionMs > threads[j].ContributionMs
+		}
+		return threads[i].SlackMs < threads[j].SlackMs
+	})
+	return threads
+}
+
+// busyBetween sums the busy time of sorted intervals within [from, to]
+func busyBetween(intervals []busyInterval, from, to float64) float64 {
+	total := 0.0
+	i := sort.Search(len(intervals), func(i int) bool { return intervals[i].end > from })
+	for ; i < len(intervals) && intervals[i].start < to; i++ {
+		total += math.Min(intervals[i].end, to) - math.Max(intervals[i].start, from)
+	}
+	return total
+}
+
+// threadLabel names a thread with its TID so workers sharing a name stay distinct
+func threadLabel(thread *parser.Thread) string {
+	if tid := thread.TID.String(); tid != "" {
+		return fmt.Sprintf("%s (%s)", thread.Name, tid)
+	}
+	return thread.Name
+}
+
+// delimiterThread finds the index of the thread a delimiter marker was recorded on
+func delimiterThread(profile *parser.Profile, m DelimiterMarker) int {
+	fallback := -1
+	for i := range profile.Threads {
+		thread := &profile.Threads[i]
+		if thread.Name != m.Thread {
+			continue
+		}
+		if fallback < 0 {
+			fallback = i
+		}
+		for _, pm := range parser.ExtractMarkers(thread, profile.Meta.Categories) {
+			if pm.Name == m.Name && pm.StartTime == m.TimeMs {
+				return i
+			}
+		}
+	}
+	return fallback
+}
+
+// threadDependencies collects cross-thread edges from posted messages, Chrome flow
+// events and IPC messages
+func threadDependencies(profile *parser.Profile) []threadEdge {
+	var edges []threadEdge
+	for _, m := range pairThreadMessages(profile).paired {
+		edges = append(edges, threadEdge{from: m.send.thread, to: m.recv.thread, sendTime: m.send.time, recvTime: m.recv.time, kind: DependencyMessage})
+	}
+
+	flows := make(map[string][]messageEndpoint)
+	ipcSends := make(map[string]messageEndpoint)
+	ipcReceives := make(map[string]messageEndpoint)
+	for i := range profile.Threads {
+		for _, m := range parser.ExtractMarkers(&profile.Threads[i], profile.Meta.Categories) {
+			ep := messageEndpoint{thread: i, time: m.StartTime, data: m.Data}
+			switch {
+			case m.Name == parser.FlowMarkerName:
+				if id, _ := m.Data["id"].(string); id != "" {
+					flows[id] = append(flows[id], ep)
+				}
+			case m.Category == "IPC" || m.Name == "IPC" || m.Type == "IPC":
+				key := ipcMessageKey(m.Data)
+				if key == "" {
+					continue
+				}
+				switch direction, _ := m.Data["direction"].(string); direction {
+				case "sending":
+					if _, seen := ipcSends[key]; !seen {
+						ipcSends[key] = ep
+					}
+				case "receiving":
+					if _, seen := ipcReceives[key]; !seen {
+						ipcReceives[key] = ep
+					}
+				}
+			}
+		}
+	}
+
+	// Flow events: the first point of a flow leads to every later point on other threads
+	for _, points := range flows {
+		sort.SliceStable(points, func(i, j int) bool { return points[i].time < points[j].time })
+		for _, p := range points[1:] {
+			if p.thread != points[0].thread {
+				edges = append(edges, threadEdge{from: points[0].thread, to: p.thread, sendTime: points[0].time, recvTime: p.time, kind: DependencyFlow})
+			}
+		}
+	}
+
+	for key, s := range ipcSends {
+		if r, ok := ipcReceives[key]; ok && r.thread != s.thread && r.time >= s.time {
+			edges = append(edges, threadEdge{from: s.thread, to: r.thread, sendTime: s.time, recvTime: r.time, kind: DependencyIPC})
+		}
+	}
+
+	sort.Slice(edges, func(i, j int) bool {
+		if edges[i].recvTime != edges[j].recvTime {
+			return edges[i].recvTime < edges[j].recvTime
+		}
+		return edges[i].from < edges[j].from
+	})
+	return edges
+}
+
+// ipcMessageKey identifies both ends of a Firefox IPC message
+func ipcMessageKey(data map[string]interface{}) string {
+	seqno, ok := data["messageSeqno"].(float64)
+	if !ok {
+		return ""
+	}
+	if phase, _ := data["phase"].(string); phase != "" && phase != "endpoint" {
+		return ""
+	}
+	messageType, _ := data["messageType"].(string)
+	return fmt.Sprintf("%s#%d", messageType, int64(seqno))
+}
+
+// criticalPathRecommendations suggests follow-ups based on the critical path
+func criticalPathRecommendations(analysis *CriticalPathAnalysis) []string {
+	var recs []string
+	if analysis.Dependencies == 0 {
+		recs = append(recs, "No cross-thread messages, flows or IPC were found between the delimiters; the critical path stays on the end marker's thread")
+	}
+
+	var top *CriticalPathThread
+	for i := range analysis.Threads {
+		if analysis.Threads[i].OnCriticalPath {
+			top = &analysis.Threads[i]
+			break
+		}
+	}
+	if top != nil && top.Percent > 50 && len(analysis.Threads) > 1 {
+		recs = append(recs, fmt.Sprintf("%s accounts for %.0f%% of the end-to-end time; speeding it up or splitting its work shortens the whole operation", top.Thread, top.Percent))
+	}
+
+	var messageMs float64
+	for _, s := range analysis.Segments {
+		if s.Kind == CriticalPathMessage {
+			messageMs += s.DurationMs
+		}
+	}
+	if analysis.TotalMs > 0 && messageMs/analysis.TotalMs > 0.1 {
+		recs = append(recs, fmt.Sprintf("%.1fms of the critical path is messages waiting to be delivered; the receiving threads are busy or the payloads are expensive to clone", messageMs))
+	}
+
+	var slack []string
+	for _, t := range analysis.Threads {
+		if !t.OnCriticalPath && t.SlackMs > 0.2*analysis.TotalMs && t.SlackMs > 1 {
+			slack = append(slack, t.Thread)
+		}
+	}
+	if len(slack) > 0 {
+		recs = append(recs, fmt.Sprintf("%s finished well before the critical thread; give them a larger share of the work", strings.Join(slack, ", ")))
+	}
+	return recs
+}
@@ -0,0 +1,131 @@
+package analyzer
+
+import (
+	"testing"
+
+	"github.com/CedricHerzog/perfowl/internal/testutil"
+)
+
+func decryptPathOptions() MeasureOptions {
+	return MeasureOptions{
+		StartPattern: "UserTiming:decrypt-start",
+		EndPattern:   "UserTiming:decrypt-end",
+	}
+}
+
+func TestAnalyzeCriticalPath_MissingDelimiters(t *testing.T) {
+	if _, err := AnalyzeCriticalPath(testutil.MinimalProfile(), decryptPathOptions()); err == nil {
+		t.Error("expected error when the delimiters are missing")
+	}
+}
+
+func TestAnalyzeCriticalPath_FollowsSlowestWorker(t *testing.T) {
+	analysis, err := AnalyzeCriticalPath(testutil.ProfileWithCriticalPath(), decryptPathOptions())
+	if err != nil {
+		t.Fatalf("AnalyzeCriticalPath error: %v", err)
+	}
+
+	testutil.AssertFloatApproxEqual(t, analysis.TotalMs, 80, 0.001)
+	if analysis.Dependencies != 4 {
+		t.Errorf("Dependencies = %d, want 4", analysis.Dependencies)
+	}
+
+	want := []struct {
+		kind   string
+		thread string
+		start  float64
+		end    float64
+	}{
+		{CriticalPathRun, "GeckoMain (1)", 0, 1},
+		{CriticalPathMessage, "GeckoMain (1) → DOM Worker (2)", 1, 3},
+		{CriticalPathRun, "DOM Worker (2)", 3, 68},
+		{CriticalPathMessage, "DOM Worker (2) → GeckoMain (1)", 68, 70},
+		{CriticalPathRun, "GeckoMain (1)", 70, 80},
+	}
+	if len(analysis.Segments) != len(want) {
+		t.Fatalf("expected %d segments, got %+v", len(want), analysis.Segments)
+	}
+	for i, w := range want {
+		s := analysis.Segments[i]
+		if s.Kind != w.kind || s.Thread != w.thread {
+			t.Errorf("segment %d = %s %s, want %s %s", i, s.Kind, s.Thread, w.kind, w.thread)
+		}
+		testutil.AssertFloatApproxEqual(t, s.StartTime, w.start, 0.001)
+		testutil.AssertFloatApproxEqual(t, s.EndTime, w.end, 0.001)
+	}
+	testutil.AssertFloatApproxEqual(t, analysis.Segments[2].Percent, 81.25, 0.001)
+	testutil.AssertFloatApproxEqual(t, analysis.Segments[2].BusyMs, 65, 0.001)
+	if analysis.Segments[1].Via != DependencyMessage {
+		t.Errorf("Via = %q, want %q", analysis.Segments[1].Via, DependencyMessage)
+	}
+}
+
+func TestAnalyzeCriticalPath_ThreadSlack(t *testing.T) {
+	analysis, err := AnalyzeCriticalPath(testutil.ProfileWithCriticalPath(), decryptPathOptions())
+	if err != nil {
+		t.Fatalf("AnalyzeCriticalPath error: %v", err)
+	}
+
+	if len(analysis.Threads) != 3 {
+		t.Fatalf("expected 3 threads, got %+v", analysis.Threads)
+	}
+	slow := analysis.Threads[0]
+	if slow.Thread != "DOM Worker (2)" || !slow.OnCriticalPath || slow.SlackMs != 0 {
+		t.Errorf("top thread = %+v, want DOM Worker (2) on the path without slack", slow)
+	}
+	testutil.AssertFloatApproxEqual(t, slow.ContributionMs, 65, 0.001)
+
+	fast := analysis.Threads[2]
+	if fast.Thread != "DOM Worker (3)" || fast.OnCriticalPath {
+		t.Fatalf("last thread = %+v, want DOM Worker (3) off the path", fast)
+	}
+	// Finished at 38ms while the path waited on worker 2 until 68ms
+	testutil.AssertFloatApproxEqual(t, fast.FinishTime, 38, 0.001)
+	testutil.AssertFloatApproxEqual(t, fast.SlackMs, 30, 0.001)
+}
+
+func TestAnalyzeCriticalPath_SingleThread(t *testing.T) {
+	mb := testutil.NewMarkerBuilder().
+		AddUserTiming("decrypt-start", 10).
+		AddUserTiming("decrypt-end", 50)
+	profile := testutil.NewProfileBuilder().
+		WithThread(mb.BuildForThread(testutil.NewThreadBuilder("GeckoMain").AsMainThread()).Build()).
+		Build()
+
+	analysis, err := AnalyzeCriticalPath(profile, decryptPathOptions())
+	if err != nil {
+		t.Fatalf("AnalyzeCriticalPath error: %v", err)
+	}
+	if len(analysis.Segments) != 1 || analysis.Segments[0].DurationMs != 40 {
+		t.Errorf("expected one 40ms segment, got %+v", analysis.Segments)
+	}
+	if len(analysis.Recommendations) == 0 {
+		t.Error("expected a recommendation about missing dependencies")
+	}
+}
+
+func TestThreadDependencies_FlowAndIPC(t *testing.T) {
+	a, aStrs := testutil.NewMarkerBuilder().
+		AddCustom("Flow", 1, 10, 0, map[string]interface{}{"type": "Flow", "id": "42", "phase": "s"}).
+		AddCustom("IPC", 1, 20, 0, map[string]interface{}{"type": "IPC", "messageType": "PContent::Msg_Sync", "messageSeqno": float64(5), "direction": "sending", "phase": "endpoint"}).
+		Build()
+	b, bStrs := testutil.NewMarkerBuilder().
+		AddCustom("Flow", 1, 12, 0, map[string]interface{}{"type": "Flow", "id": "42", "phase": "f"}).
+		AddCustom("IPC", 1, 25, 0, map[string]interface{}{"type": "IPC", "messageType": "PContent::Msg_Sync", "messageSeqno": float64(5), "direction": "receiving", "phase": "endpoint"}).
+		Build()
+	profile := testutil.NewProfileBuilder().
+		WithThread(testutil.NewThreadBuilder("A").WithStringArray(aStrs).WithMarkers(a).Build()).
+		WithThread(testutil.NewThreadBuilder("B").WithStringArray(bStrs).WithMarkers(b).Build()).
+		Build()
+
+	edges := threadDependencies(profile)
+	if len(edges) != 2 {
+		t.Fatalf("expected 2 edges, got %+v", edges)
+	}
+	if edges[0].kind != DependencyFlow || edges[0].from != 0 || edges[0].to != 1 {
+		t.Errorf("first edge = %+v, want a flow from A to B", edges[0])
+	}
+	if edges[1].kind != DependencyIPC || edges[1].sendTime != 20 || edges[1].recvTime != 25 {
+		t.Errorf("second edge = %+v, want IPC 20ms → 25ms", edges[1])
+	}
+}
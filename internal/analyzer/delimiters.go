@@ -150,10 +150,41 @@ func MeasureOperationWithOptions(profile *parser.Profile, startPattern, endPatte
 	})
 }
 
-// MeasureOperationAdvanced finds start/end markers with full control over matching options
+// MeasureOperationAdvanced finds start/end markers with full control over matching options.
+// Either pattern may name a page-load milestone ("navigationStart", "FCP", "LCP",
+// "DCL", "PageLoad:load"), which matches that milestone of any navigation. A bare
+// alias such as "load" only names the milestone when no marker matches it
+// literally; "PageLoad:load" always does.
 func MeasureOperationAdvanced(profile *parser.Profile, opts MeasureOptions) (*OperationMeasurement, error) {
 	allMarkers := GetDelimiterMarkers(profile, nil)
 
+	resolveAnchor := func(pattern string) (string, bool) {
+		anchor, ok := ResolveMilestoneAnchor(pattern)
+		if !ok {
+			return "", false
+		}
+		for _, m := range allMarkers {
+			if MatchMarkerPattern(m, pattern) {
+				return "", false
+			}
+		}
+		return anchor, true
+	}
+	startAnchor, startIsAnchor := resolveAnchor(opts.StartPattern)
+	endAnchor, endIsAnchor := resolveAnchor(opts.EndPattern)
+	if startIsAnchor || endIsAnchor {
+		allMarkers = append(allMarkers, milestoneDelimiters(ExtractPageLoadMilestones(profile))...)
+		sort.SliceStable(allMarkers, func(i, j int) bool {
+			return allMarkers[i].TimeMs < allMarkers[j].TimeMs
+		})
+	}
+	matches := func(m DelimiterMarker, pattern, anchor string, isAnchor bool) bool {
+		if isAnchor {
+			return m.Type == PageLoadMarkerType && m.Name == anchor
+		}
+		return MatchMarkerPattern(m, pattern)
+	}
+
 	if len(allMarkers) == 0 {
 		return nil, fmt.Errorf("no delimiter markers found in profile")
 	}
@@ -168,7 +199,7 @@ func MeasureOperationAdvanced(profile *parser.Profile, opts MeasureOptions) (*Op
 		if opts.StartMinDurationMs > 0 && m.DurationMs < opts.StartMinDurationMs {
 			continue
 		}
-		if MatchMarkerPattern(*m, opts.StartPattern) {
+		if matches(*m, opts.StartPattern, startAnchor, startIsAnchor) {
 			startMarker = m
 			break
 		}
@@ -191,7 +222,7 @@ func MeasureOperationAdvanced(profile *parser.Profile, opts MeasureOptions) (*Op
 		if opts.EndMinDurationMs > 0 && m.DurationMs < opts.EndMinDurationMs {
 			continue
 		}
-		if MatchMarkerPattern(*m, opts.EndPattern) {
+		if matches(*m, opts.EndPattern, endAnchor, endIsAnchor) {
 			if opts.FindLast {
 				// Keep updating to find the last match
 				endMarker = &allMarkers[i]
@@ -214,6 +245,41 @@ func MeasureOperationAdvanced(profile *parser.Profile, opts MeasureOptions) (*Op
 	}, nil
 }
 
+// ResolveMarkerRange finds the time range spanned by the first marker matching
+// startPattern and the next marker matching endPattern. The range ends when the
+// end marker finishes, so its own work is included in the selection.
+func ResolveMarkerRange(profile *parser.Profile, startPattern, endPattern string) (parser.TimeRange, error) {
+	measurement, err := MeasureOperationAdvanced(profile, MeasureOptions{
+		StartPattern: startPattern,
+		EndPattern:   endPattern,
+	})
+	if err != nil {
+		return parser.TimeRange{}, err
+	}
+
+	return parser.TimeRange{
+		StartMs: measurement.StartMarker.TimeMs,
+		EndMs:   measurement.EndMarker.TimeMs + measurement.EndMarker.DurationMs,
+	}, nil
+}
+
+// ParseMarkerRange parses a marker range expression like "DOMEvent:click..Paint"
+// into its start and end patterns
+func ParseMarkerRange(expr string) (string, string, error) {
+	parts := strings.SplitN(expr, "..", 2)
+	if len(parts) != 2 {
+		return "", "", fmt.Errorf("invalid marker range %q: expected format START..END (e.g. DOMEvent:click..Paint)", expr)
+	}
+
+	startPattern := strings.Trim(strings.TrimSpace(parts[0]), `"'`)
+	endPattern := strings.Trim(strings.TrimSpace(parts[1]), `"'`)
+	if startPattern == "" || endPattern == "" {
+		return "", "", fmt.Errorf("invalid marker range %q: both start and end patterns are required", expr)
+	}
+
+	return startPattern, endPattern, nil
+}
+
 // MeasureOperationByIndex finds start/end markers by index and returns duration
 // This is useful when the LLM has identified specific markers by their index
 func MeasureOperationByIndex(profile *parser.Profile, startIndex, endIndex int) (*OperationMeasurement, error) {
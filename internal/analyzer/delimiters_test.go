@@ -3,6 +3,7 @@ package analyzer
 import (
 	"testing"
 
+	"github.com/CedricHerzog/perfowl/internal/parser"
 	"github.com/CedricHerzog/perfowl/internal/testutil"
 )
 
@@ -164,6 +165,64 @@ func TestMeasureOperationLast_FindsLastMatch(t *testing.T) {
 	}
 }
 
+func TestMeasureOperationAdvanced_MilestoneAnchors(t *testing.T) {
+	profile := testutil.ProfileWithPageLoad()
+
+	result, err := MeasureOperationAdvanced(profile, MeasureOptions{
+		StartPattern: "navigationStart",
+		EndPattern:   "LCP",
+	})
+	if err != nil {
+		t.Fatalf("MeasureOperationAdvanced error: %v", err)
+	}
+	testutil.AssertFloatApproxEqual(t, result.OperationTimeMs, 700, 0.01)
+	if result.EndMarker.Type != PageLoadMarkerType || result.EndMarker.Name != MilestoneLargestContentfulPaint {
+		t.Errorf("end marker = %s/%s, want PageLoad/largestContentfulPaint", result.EndMarker.Type, result.EndMarker.Name)
+	}
+
+	result, err = MeasureOperationAdvanced(profile, MeasureOptions{
+		StartPattern: "FCP",
+		EndPattern:   "PageLoad:load",
+	})
+	if err != nil {
+		t.Fatalf("MeasureOperationAdvanced error: %v", err)
+	}
+	testutil.AssertFloatApproxEqual(t, result.OperationTimeMs, 540, 0.01)
+}
+
+func TestMeasureOperationAdvanced_LiteralMarkersBeforeAliases(t *testing.T) {
+	markers, strs := testutil.NewMarkerBuilder().
+		AddCustom("DocumentLoad", 5, 0, 400, nil).
+		AddCustom("nav", 5, 10, 0, nil).
+		AddCustom("load", 5, 90, 0, nil).
+		Build()
+	profile := testutil.NewProfileBuilder().
+		WithCategories(testutil.DefaultCategories()).
+		WithThread(testutil.NewThreadBuilder("GeckoMain").
+			AsMainThread().
+			WithStringArray(strs).
+			WithMarkers(markers).
+			Build()).
+		Build()
+
+	// Markers named like the aliases match as markers
+	result, err := MeasureOperationAdvanced(profile, MeasureOptions{StartPattern: "nav", EndPattern: "load"})
+	if err != nil {
+		t.Fatalf("MeasureOperationAdvanced error: %v", err)
+	}
+	testutil.AssertFloatApproxEqual(t, result.OperationTimeMs, 80, 0.01)
+	if result.EndMarker.Type == PageLoadMarkerType {
+		t.Errorf("end marker = %+v, want the load marker", result.EndMarker)
+	}
+
+	// The PageLoad prefix still names the milestone
+	result, err = MeasureOperationAdvanced(profile, MeasureOptions{StartPattern: "nav", EndPattern: "PageLoad:load"})
+	if err != nil {
+		t.Fatalf("MeasureOperationAdvanced error: %v", err)
+	}
+	testutil.AssertFloatApproxEqual(t, result.OperationTimeMs, 390, 0.01)
+}
+
 func TestMeasureOperationByIndex_Valid(t *testing.T) {
 	profile := testutil.ProfileWithDelimiters()
 
@@ -253,3 +312,78 @@ func TestIsDelimiterMarker_DOMEvent(t *testing.T) {
 		})
 	}
 }
+
+func TestResolveMarkerRange(t *testing.T) {
+	profile := testutil.ProfileWithDelimiters()
+
+	r, err := ResolveMarkerRange(profile, "DOMEvent:click", "Paint")
+	if err != nil {
+		t.Fatalf("ResolveMarkerRange error: %v", err)
+	}
+
+	// Click starts at 100, paint runs 160-180
+	if r.StartMs != 100 {
+		t.Errorf("StartMs = %v, want 100", r.StartMs)
+	}
+	if r.EndMs != 180 {
+		t.Errorf("EndMs = %v, want 180", r.EndMs)
+	}
+}
+
+func TestResolveMarkerRange_NoMatch(t *testing.T) {
+	profile := testutil.ProfileWithDelimiters()
+
+	_, err := ResolveMarkerRange(profile, "DOMEvent:keydown", "Paint")
+	if err == nil {
+		t.Error("expected error for unmatched start pattern")
+	}
+}
+
+func TestParseMarkerRange(t *testing.T) {
+	tests := []struct {
+		input     string
+		wantStart string
+		wantEnd   string
+		wantErr   bool
+	}{
+		{"DOMEvent:click..Paint", "DOMEvent:click", "Paint", false},
+		{`"DOMEvent:click".."Paint"`, "DOMEvent:click", "Paint", false},
+		{"UserTiming:a .. UserTiming:b", "UserTiming:a", "UserTiming:b", false},
+		{"DOMEvent:click", "", "", true},
+		{"..Paint", "", "", true},
+	}
+
+	for _, tt := range tests {
+		start, end, err := ParseMarkerRange(tt.input)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("ParseMarkerRange(%q) expected error", tt.input)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseMarkerRange(%q) error: %v", tt.input, err)
+			continue
+		}
+		if start != tt.wantStart || end != tt.wantEnd {
+			t.Errorf("ParseMarkerRange(%q) = (%q, %q), want (%q, %q)", tt.input, start, end, tt.wantStart, tt.wantEnd)
+		}
+	}
+}
+
+func TestSlicedProfile_AnalyzersRespectRange(t *testing.T) {
+	profile := testutil.ProfileWithCallTree()
+
+	// computeHash samples run 0-490ms, render/updateDOM samples run 500-990ms
+	sliced := profile.Slice(parser.TimeRange{StartMs: 500, EndMs: 1000})
+	result := AnalyzeCallTree(sliced, "", 20)
+
+	if result.TotalSamples != 50 {
+		t.Errorf("TotalSamples = %d, want 50", result.TotalSamples)
+	}
+	for _, f := range result.TopFunctions {
+		if f.Name == "computeHash" {
+			t.Error("computeHash should not appear outside the selected range")
+		}
+	}
+}
@@ -18,6 +18,27 @@ type ExtensionReport struct {
 	IPCMessages   int             `json:"ipc_messages"`
 	ImpactScore   string          `json:"impact_score"`
 	TopMarkers    []MarkerSummary `json:"top_markers,omitempty"`
+
+	// Sample-based attribution: time of stacks containing the extension's code
+	CPUTimeMs    float64                `json:"cpu_time_ms"`
+	SampleCount  int                    `json:"sample_count"`
+	CPUByThread  []ExtensionThreadCPU   `json:"cpu_by_thread,omitempty"`
+	TopFunctions []ExtensionFunctionCPU `json:"top_functions,omitempty"`
+}
+
+// ExtensionThreadCPU is the CPU time of an extension on one thread
+type ExtensionThreadCPU struct {
+	Thread      string  `json:"thread"`
+	CPUTimeMs   float64 `json:"cpu_time_ms"`
+	SampleCount int     `json:"sample_count"`
+}
+
+// ExtensionFunctionCPU is the CPU time of one extension function
+type ExtensionFunctionCPU struct {
+	Name        string  `json:"name"`
+	File        string  `json:"file,omitempty"`
+	SelfTimeMs  float64 `json:"self_time_ms"`
+	TotalTimeMs float64 `json:"total_time_ms"`
 }
 
 // MarkerSummary is a simplified marker for reporting
@@ -31,6 +52,7 @@ type MarkerSummary struct {
 type ExtensionsAnalysis struct {
 	TotalExtensions int               `json:"total_extensions"`
 	TotalDuration   float64           `json:"total_duration_ms"`
+	TotalCPUTimeMs  float64           `json:"total_cpu_time_ms"`
 	TotalEvents     int               `json:"total_events"`
 	Extensions      []ExtensionReport `json:"extensions"`
 }
@@ -101,9 +123,12 @@ func AnalyzeExtensions(profile *parser.Profile) ExtensionsAnalysis {
 		}
 	}
 
+	// Attribute samples whose stacks run extension code
+	attributeExtensionSamples(profile, extReports, extensionURLs)
+
 	// Process extension reports
 	for _, report := range extReports {
-		if report.MarkersCount == 0 && report.TotalDuration == 0 {
+		if report.MarkersCount == 0 && report.TotalDuration == 0 && report.CPUTimeMs == 0 {
 			continue // Skip extensions with no activity
 		}
 
@@ -120,17 +145,251 @@ func AnalyzeExtensions(profile *parser.Profile) ExtensionsAnalysis {
 
 		analysis.Extensions = append(analysis.Extensions, *report)
 		analysis.TotalDuration += report.TotalDuration
+		analysis.TotalCPUTimeMs += report.CPUTimeMs
 		analysis.TotalEvents += report.MarkersCount
 	}
 
-	// Sort by total duration
-	sort.Slice(analysis.Extensions, func(i, j int) bool {
-		return analysis.Extensions[i].TotalDuration > analysis.Extensions[j].TotalDuration
-	})
+	// Sort by CPU time, then by marker duration
+	SortExtensionReports(analysis.Extensions)
 
 	return analysis
 }
 
+// SortExtensionReports orders extensions by CPU time, then by marker duration
+func SortExtensionReports(reports []ExtensionReport) {
+	sort.SliceStable(reports, func(i, j int) bool {
+		if reports[i].CPUTimeMs != reports[j].CPUTimeMs {
+			return reports[i].CPUTimeMs > reports[j].CPUTimeMs
+		}
+		return reports[i].TotalDuration > reports[j].TotalDuration
+	})
+}
+
+// attributeExtensionSamples adds the CPU time of samples whose stack contains a frame
+// from an extension. Frames match through their file, resource or function name against
+// the extension base URLs, and unknown chrome-extension:// IDs get their own report.
+func attributeExtensionSamples(profile *parser.Profile, extReports map[string]*ExtensionReport, extensionURLs map[string]string) {
+	// Match longer base URLs first so nested URLs resolve deterministically
+	baseURLs := make([]string, 0, len(extensionURLs))
+	urlToID := make(map[string]string)
+	for id, baseURL := range extensionURLs {
+		if baseURL == "" {
+			continue
+		}
+		baseURLs = append(baseURLs, baseURL)
+		urlToID[baseURL] = id
+	}
+	sort.Slice(baseURLs, func(i, j int) bool {
+		if len(baseURLs[i]) != len(baseURLs[j]) {
+			return len(baseURLs[i]) > len(baseURLs[j])
+		}
+		return baseURLs[i] < baseURLs[j]
+	})
+
+	matchURL := func(s string) string {
+		if s == "" {
+			return ""
+		}
+		for _, baseURL := range baseURLs {
+			if strings.Contains(s, baseURL) {
+				return urlToID[baseURL]
+			}
+		}
+		if idx := strings.Index(s, "chrome-extension://"); idx >= 0 {
+			if extID := parser.ExtractExtensionID(s[idx:]); extID != "" {
+				if extReports[extID] == nil {
+					extReports[extID] = &ExtensionReport{
+						ID:      extID,
+						Name:    extID,
+						BaseURL: "chrome-extension://" + extID + "/",
+					}
+				}
+				return extID
+			}
+		}
+		return ""
+	}
+
+	type extFrame struct {
+		extID string
+		name  string
+		file  string
+	}
+
+	type functionKey struct {
+		extID string
+		name  string
+		file  string
+	}
+
+	functions := make(map[functionKey]*ExtensionFunctionCPU)
+	sharedStrings := profile.Shared.StringArray
+	interval := profile.Meta.Interval
+
+	for _, thread := range profile.Threads {
+		stackTable := &thread.StackTable
+		frameTable := &thread.FrameTable
+		funcTable := &thread.FuncTable
+		resourceTable := &thread.ResourceTable
+		samples := &thread.Samples
+
+		// Use thread's string array, fall back to shared if empty
+		stringArray := thread.StringArray
+		if len(stringArray) == 0 {
+			stringArray = sharedStrings
+		}
+
+		getString := func(idx int) string {
+			if idx >= 0 && idx < len(stringArray) {
+				return stringArray[idx]
+			}
+			return ""
+		}
+
+		// Resolve each frame's extension once
+		frames := make(map[int]extFrame)
+		resolveFrame := func(frameIdx int) extFrame {
+			if ef, ok := frames[frameIdx]; ok {
+				return ef
+			}
+			ef := extFrame{}
+			if frameIdx >= 0 && frameIdx < len(frameTable.Func) {
+				funcIdx := frameTable.Func[frameIdx]
+				if funcIdx >= 0 && funcIdx < funcTable.Length {
+					if funcIdx < len(funcTable.Name) {
+						ef.name = getString(funcTable.Name[funcIdx])
+					}
+					if funcIdx < len(funcTable.FileName) {
+						ef.file = getString(funcTable.FileName[funcIdx])
+					}
+
+					candidates := []string{ef.file}
+					if funcIdx < len(funcTable.Resource) {
+						if resIdx := funcTable.Resource[funcIdx]; resIdx >= 0 && resIdx < resourceTable.Length {
+							if resIdx < len(resourceTable.Name) {
+								candidates = append(candidates, getString(resourceTable.Name[resIdx]))
+							}
+							if resIdx < len(resourceTable.Host) {
+								candidates = append(candidates, getString(resourceTable.Host[resIdx]))
+							}
+						}
+					}
+					candidates = append(candidates, ef.name)
+
+					for _, c := range candidates {
+						if ef.extID = matchURL(c); ef.extID != "" {
+							break
+						}
+					}
+				}
+			}
+			frames[frameIdx] = ef
+			return ef
+		}
+
+		// Aggregate time per unique stack first
+		stackTime := make(map[int]float64)
+		stackCount := make(map[int]int)
+		for i := 0; i < samples.Length; i++ {
+			stackIdx := -1
+			if i < len(samples.Stack) {
+				stackIdx = samples.Stack[i]
+			}
+			if stackIdx < 0 || stackIdx >= stackTable.Length {
+				continue
+			}
+
+			cpuDelta := interval
+			if i < len(samples.ThreadCPUDelta) && samples.ThreadCPUDelta[i] > 0 {
+				cpuDelta = float64(samples.ThreadCPUDelta[i]) / 1000.0
+			}
+
+			stackTime[stackIdx] += cpuDelta
+			stackCount[stackIdx]++
+		}
+
+		threadCPU := make(map[string]*ExtensionThreadCPU)
+
+		for stackIdx, timeMs := range stackTime {
+			seenExt := make(map[string]bool)
+			seenFunc := make(map[functionKey]bool)
+			leaf := true
+			for current := stackIdx; current >= 0 && current < stackTable.Length; {
+				if current < len(stackTable.Frame) {
+					ef := resolveFrame(stackTable.Frame[current])
+					if ef.extID != "" && extReports[ef.extID] != nil {
+						if !seenExt[ef.extID] {
+							seenExt[ef.extID] = true
+							report := extReports[ef.extID]
+							report.CPUTimeMs += timeMs
+							report.SampleCount += stackCount[stackIdx]
+
+							tc := threadCPU[ef.extID]
+							if tc == nil {
+								tc = &ExtensionThreadCPU{Thread: thread.Name}
+								threadCPU[ef.extID] = tc
+							}
+							tc.CPUTimeMs += timeMs
+							tc.SampleCount += stackCount[stackIdx]
+						}
+
+						key := functionKey{extID: ef.extID, name: ef.name, file: ef.file}
+						fn := functions[key]
+						if fn == nil {
+							fn = &ExtensionFunctionCPU{Name: ef.name, File: ef.file}
+							functions[key] = fn
+						}
+						if leaf {
+							fn.SelfTimeMs += timeMs
+						}
+						if !seenFunc[key] {
+							seenFunc[key] = true
+							fn.TotalTimeMs += timeMs
+						}
+					}
+				}
+				leaf = false
+				if current >= len(stackTable.Prefix) {
+					break
+				}
+				current = stackTable.Prefix[current]
+			}
+		}
+
+		for extID, tc := range threadCPU {
+			extReports[extID].CPUByThread = append(extReports[extID].CPUByThread, *tc)
+		}
+	}
+
+	for key, fn := range functions {
+		report := extReports[key.extID]
+		report.TopFunctions = append(report.TopFunctions, *fn)
+	}
+
+	for _, report := range extReports {
+		sort.Slice(report.CPUByThread, func(i, j int) bool {
+			if report.CPUByThread[i].CPUTimeMs != report.CPUByThread[j].CPUTimeMs {
+				return report.CPUByThread[i].CPUTimeMs > report.CPUByThread[j].CPUTimeMs
+			}
+			return report.CPUByThread[i].Thread < report.CPUByThread[j].Thread
+		})
+
+		sort.Slice(report.TopFunctions, func(i, j int) bool {
+			a, b := report.TopFunctions[i], report.TopFunctions[j]
+			if a.SelfTimeMs != b.SelfTimeMs {
+				return a.SelfTimeMs > b.SelfTimeMs
+			}
+			if a.TotalTimeMs != b.TotalTimeMs {
+				return a.TotalTimeMs > b.TotalTimeMs
+			}
+			return a.Name < b.Name
+		})
+		if len(report.TopFunctions) > 5 {
+			report.TopFunctions = report.TopFunctions[:5]
+		}
+	}
+}
+
 // matchExtension checks if a marker is related to an extension
 func matchExtension(m parser.ParsedMarker, extensionURLs map[string]string) string {
 	// Check URL in marker data
@@ -228,6 +487,15 @@ func calculateImpactScore(report *ExtensionReport) string {
 		score += 1
 	}
 
+	// Sampled CPU time scoring
+	if report.CPUTimeMs > 1000 {
+		score += 3
+	} else if report.CPUTimeMs > 500 {
+		score += 2
+	} else if report.CPUTimeMs > 100 {
+		score += 1
+	}
+
 	// Event count scoring
 	if report.MarkersCount > 1000 {
 		score += 3
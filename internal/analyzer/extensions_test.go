@@ -187,3 +187,125 @@ func TestCalculateImpactScore_High(t *testing.T) {
 		t.Errorf("expected high impact, got %s", score)
 	}
 }
+
+func TestAnalyzeExtensions_SampleAttribution(t *testing.T) {
+	result := AnalyzeExtensions(testutil.ProfileWithExtensionActivity())
+
+	cpu := make(map[string]float64)
+	for _, ext := range result.Extensions {
+		cpu[ext.ID] = ext.CPUTimeMs
+	}
+
+	// 20 samples of 2ms per stack: ext123 has 3 stacks, ext456 has 2
+	testutil.AssertFloatApproxEqual(t, cpu["ext123@example.com"], 120, 0.01)
+	testutil.AssertFloatApproxEqual(t, cpu["ext456@example.com"], 80, 0.01)
+	testutil.AssertFloatApproxEqual(t, result.TotalCPUTimeMs, 200, 0.01)
+
+	if result.Extensions[0].ID != "ext123@example.com" {
+		t.Errorf("expected ext123 first by CPU time, got %s", result.Extensions[0].ID)
+	}
+
+	ext := result.Extensions[0]
+	if ext.SampleCount != 60 {
+		t.Errorf("SampleCount = %d, want 60", ext.SampleCount)
+	}
+	if len(ext.CPUByThread) != 1 || ext.CPUByThread[0].Thread != "GeckoMain" {
+		t.Errorf("CPUByThread = %v, want GeckoMain only", ext.CPUByThread)
+	}
+	if len(ext.TopFunctions) != 3 {
+		t.Errorf("expected 3 top functions, got %d", len(ext.TopFunctions))
+	}
+}
+
+func TestAnalyzeExtensions_StackOnlyActivity(t *testing.T) {
+	chromeID := "abcdefghijklmnopabcdefghijklmnop"
+	strs := []string{
+		"onMessage",                         // 0
+		"moz-extension://abc123/content.js", // 1
+		"querySelectorAll",                  // 2
+		"main",                              // 3
+		"inject",                            // 4
+		"chrome-extension://" + chromeID + "/inject.js", // 5
+	}
+
+	fnb := testutil.NewFuncTableBuilder()
+	fnb.AddFuncWithFile(0, true, -1, 1, 1).
+		AddFunc(2, false, -1).
+		AddFunc(3, true, -1).
+		AddFuncWithFile(4, true, -1, 5, 1)
+
+	ftb := testutil.NewFrameTableBuilder()
+	ftb.AddFrame(0, 2).AddFrame(1, 0).AddFrame(2, 2).AddFrame(3, 2)
+
+	// Stack 0: main
+	// Stack 1: main -> onMessage
+	// Stack 2: main -> onMessage -> querySelectorAll
+	// Stack 3: inject
+	stb := testutil.NewStackTableBuilder()
+	stb.AddStack(2, 2, -1).
+		AddStack(0, 2, 0).
+		AddStack(1, 0, 1).
+		AddStack(3, 2, -1)
+
+	sb := testutil.NewSamplesBuilder()
+	for i := 0; i < 10; i++ {
+		sb.AddSampleWithCPUDelta(2, float64(i), 1000)
+	}
+	for i := 0; i < 4; i++ {
+		sb.AddSampleWithCPUDelta(1, float64(10+i), 1000)
+	}
+	for i := 0; i < 3; i++ {
+		sb.AddSampleWithCPUDelta(0, float64(20+i), 1000)
+	}
+	for i := 0; i < 2; i++ {
+		sb.AddSampleWithCPUDelta(3, float64(30+i), 1000)
+	}
+
+	profile := testutil.NewProfileBuilder().
+		WithDuration(100).
+		WithCategories(testutil.DefaultCategories()).
+		WithExtension("ext1@test.com", "Content Script Extension", "moz-extension://abc123/").
+		WithThread(testutil.NewThreadBuilder("GeckoMain").
+			AsMainThread().
+			WithStringArray(strs).
+			WithStackTable(stb.Build()).
+			WithFrameTable(ftb.Build()).
+			WithFuncTable(fnb.Build()).
+			WithSamples(sb.Build()).
+			Build()).
+		Build()
+
+	result := AnalyzeExtensions(profile)
+
+	var mozExt, chromeExt *ExtensionReport
+	for i := range result.Extensions {
+		switch result.Extensions[i].ID {
+		case "ext1@test.com":
+			mozExt = &result.Extensions[i]
+		case chromeID:
+			chromeExt = &result.Extensions[i]
+		}
+	}
+
+	if mozExt == nil {
+		t.Fatal("expected extension without markers to be reported from samples")
+	}
+	if mozExt.MarkersCount != 0 {
+		t.Errorf("MarkersCount = %d, want 0", mozExt.MarkersCount)
+	}
+	// Native work called by the content script counts for the extension
+	testutil.AssertFloatApproxEqual(t, mozExt.CPUTimeMs, 14, 0.01)
+	if len(mozExt.TopFunctions) != 1 || mozExt.TopFunctions[0].Name != "onMessage" {
+		t.Fatalf("TopFunctions = %v, want onMessage", mozExt.TopFunctions)
+	}
+	testutil.AssertFloatApproxEqual(t, mozExt.TopFunctions[0].SelfTimeMs, 4, 0.01)
+	testutil.AssertFloatApproxEqual(t, mozExt.TopFunctions[0].TotalTimeMs, 14, 0.01)
+
+	if chromeExt == nil {
+		t.Fatal("expected unknown chrome-extension:// ID to get its own report")
+	}
+	if chromeExt.BaseURL != "chrome-extension://"+chromeID+"/" {
+		t.Errorf("BaseURL = %s", chromeExt.BaseURL)
+	}
+	testutil.AssertFloatApproxEqual(t, chromeExt.CPUTimeMs, 2, 0.01)
+}
@@ -0,0 +1,196 @@
+package analyzer
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/CedricHerzog/perfowl/internal/parser"
+)
+
+// findingTopFunctions is how many of the heaviest functions are searched for one
+// with a source file to anchor findings that have no location of their own
+const findingTopFunctions = 20
+
+// findingLocationPattern matches a script path or URL with an optional :line suffix.
+// Longer extensions come first and the extension must end at a word boundary, so
+// app.jsx is not read as app.js and config.json is not a script.
+var findingLocationPattern = regexp.MustCompile(`((?:[a-z][a-z0-9+.-]*://)?[\w@~./%+-]+\.(?:cjs|mjs|jsx|js|tsx|ts|wasm|html|htm))\b(?::(\d+))?`)
+
+// Finding is one issue from an analysis in the shape CI test reports and code
+// scanning alerts need: a stable rule, a severity and, where known, a file and line
+type Finding struct {
+	Rule     string   `json:"rule"` // e.g. bottleneck/long-tasks
+	Title    string   `json:"title"`
+	Severity Severity `json:"severity"`
+	Message  string   `json:"message"`
+	File     string   `json:"file,omitempty"`
+	Line     int      `json:"line,omitempty"`
+}
+
+// findingLocator resolves where a finding points, falling back to the heaviest
+// function that has a source file
+type findingLocator struct {
+	profile  *parser.Profile
+	resolved bool
+	file     string
+	line     int
+}
+
+// locate returns the first script location found in the texts, or the top
+// function's file and line
+func (l *findingLocator) locate(texts ...string) (string, int) {
+	for _, text := range texts {
+		if m := findingLocationPattern.FindStringSubmatch(text); m != nil {
+			line, _ := strconv.Atoi(m[2])
+			return m[1], line
+		}
+	}
+	if !l.resolved {
+		l.resolved = true
+		if l.profile != nil {
+			for _, fn := range AnalyzeCallTree(l.profile, "", findingTopFunctions).TopFunctions {
+				if fn.File != "" {
+					l.file, l.line = fn.File, fn.Line
+					break
+				}
+			}
+		}
+	}
+	return l.file, l.line
+}
+
+// RepositoryPath maps a finding's file, usually a script URL, to a slash-separated
+// repository path, which is what code scanning tools need to place an alert on a
+// file. The file is looked up under root as ResolveSourceFile does, and the path
+// is relative to the working directory (the checkout CI runs in) when the file
+// lies inside it, otherwise to root. Without a root only relative paths are kept;
+// URLs, absolute paths and files that don't resolve under root return false.
+func RepositoryPath(root, file string) (string, bool) {
+	if file == "" {
+		return "", false
+	}
+	if root == "" {
+		if strings.Contains(file, "://") || filepath.IsAbs(file) || strings.HasPrefix(file, "/") {
+			return "", false
+		}
+		return filepath.ToSlash(file), true
+	}
+
+	path, ok := ResolveSourceFile(root, file)
+	if !ok {
+		return "", false
+	}
+	if wd, err := os.Getwd(); err == nil {
+		if rel, err := filepath.Rel(wd, path); err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return filepath.ToSlash(rel), true
+		}
+	}
+	absRoot, err := filepath.Abs(root)
+	if err != nil {
+		return "", false
+	}
+	rel, err := filepath.Rel(absRoot, path)
+	if err != nil {
+		return "", false
+	}
+	return filepath.ToSlash(rel), true
+}
+
+// findingRule builds a rule identifier from a category and a display name
+func findingRule(category, name string) string {
+	slug := strings.Trim(strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= '0' && r <= '9' {
+			return r
+		}
+		return '-'
+	}, strings.ToLower(name)), "-")
+	for strings.Contains(slug, "--") {
+		slug = strings.ReplaceAll(slug, "--", "-")
+	}
+	return category + "/" + slug
+}
+
+// BottleneckFindings converts each bottleneck to a finding. The location is the
+// first script path or URL in the bottleneck's locations, otherwise the file and
+// line of the heaviest function in the profile.
+func BottleneckFindings(report BottleneckReport, profile *parser.Profile) []Finding {
+	locator := &findingLocator{profile: profile}
+	findings := make([]Finding, 0, len(report.Bottlenecks))
+	for _, b := range report.Bottlenecks {
+		message := b.Description
+		if b.Recommendation != "" {
+			message += ". " + b.Recommendation
+		}
+		f := Finding{
+			Rule:     findingRule("bottleneck", b.Type),
+			Title:    b.Type,
+			Severity: b.Severity,
+			Message:  message,
+		}
+		f.File, f.Line = locator.locate(b.Locations...)
+		findings = append(findings, f)
+	}
+	return findings
+}
+
+// ContentionFindings reports each kind of contention that occurred, at the
+// analysis's overall severity; minimal contention is not reported
+func ContentionFindings(analysis ContentionAnalysis, profile *parser.Profile) []Finding {
+	findings := make([]Finding, 0)
+	var severity Severity
+	switch analysis.Severity {
+	case "high", "medium", "low":
+		severity = ParseSeverity(analysis.Severity)
+	default:
+		return findings
+	}
+
+	locator := &findingLocator{profile: profile}
+	kinds := []struct {
+		name  string
+		count int
+	}{
+		{"GC contention", analysis.GCContention},
+		{"IPC contention", analysis.IPCContention},
+		{"Lock contention", analysis.LockContention},
+	}
+	for _, k := range kinds {
+		if k.count == 0 {
+			continue
+		}
+		message := fmt.Sprintf("%d %s events; %.1fms total contention impact", k.count, k.name, analysis.TotalImpactMs)
+		if len(analysis.Recommendations) > 0 {
+			message += ". " + analysis.Recommendations[0]
+		}
+		f := Finding{
+			Rule:     findingRule("contention", k.name),
+			Title:    k.name,
+			Severity: severity,
+			Message:  message,
+		}
+		f.File, f.Line = locator.locate()
+		findings = append(findings, f)
+	}
+	return findings
+}
+
+// WarningFindings reports each warning of an analysis as a medium severity finding
+func WarningFindings(category string, warnings []string, profile *parser.Profile) []Finding {
+	locator := &findingLocator{profile: profile}
+	findings := make([]Finding, 0, len(warnings))
+	for _, w := range warnings {
+		f := Finding{
+			Rule:     category + "/warning",
+			Title:    w,
+			Severity: SeverityMedium,
+			Message:  w,
+		}
+		f.File, f.Line = locator.locate(w)
+		findings = append(findings, f)
+	}
+	return findings
+}
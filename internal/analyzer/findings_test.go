@@ -0,0 +1,147 @@
+package analyzer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/CedricHerzog/perfowl/internal/testutil"
+)
+
+func TestBottleneckFindings_Locations(t *testing.T) {
+	report := BottleneckReport{Bottlenecks: []Bottleneck{
+		{Type: "Network Blocking", Severity: SeverityHigh, Description: "slow script", Recommendation: "Defer it",
+			Locations: []string{"1500ms: https://cdn.example.com/lib.js:42"}},
+		{Type: "Long Tasks", Severity: SeverityLow, Description: "3 long tasks",
+			Locations: []string{"100.00ms in GeckoMain thread"}},
+	}}
+
+	findings := BottleneckFindings(report, testutil.ProfileWithSourceLines())
+	testutil.AssertSliceLen(t, findings, 2)
+
+	f := findings[0]
+	if f.Rule != "bottleneck/network-blocking" || f.Title != "Network Blocking" || f.Severity != SeverityHigh {
+		t.Errorf("unexpected finding %+v", f)
+	}
+	if f.File != "https://cdn.example.com/lib.js" || f.Line != 42 {
+		t.Errorf("location = %s:%d, want the script named in the bottleneck", f.File, f.Line)
+	}
+	if f.Message != "slow script. Defer it" {
+		t.Errorf("Message = %q", f.Message)
+	}
+
+	// No script in the locations: fall back to the heaviest function
+	if findings[1].File != "https://example.com/js/app.js" || findings[1].Line != 10 {
+		t.Errorf("fallback location = %s:%d, want app.js:10", findings[1].File, findings[1].Line)
+	}
+	if findings[1].Rule != "bottleneck/long-tasks" {
+		t.Errorf("Rule = %q, want bottleneck/long-tasks", findings[1].Rule)
+	}
+}
+
+func TestBottleneckFindings_Extensions(t *testing.T) {
+	tests := []struct {
+		location string
+		file     string
+		line     int
+	}{
+		{"src/app.jsx:42", "src/app.jsx", 42},
+		{"https://example.com/worker.mjs:7", "https://example.com/worker.mjs", 7},
+		{"lib/index.tsx", "lib/index.tsx", 0},
+		{"reading config.json took 40ms", "", 0},
+	}
+	for _, tt := range tests {
+		report := BottleneckReport{Bottlenecks: []Bottleneck{
+			{Type: "Long Tasks", Severity: SeverityLow, Locations: []string{tt.location}},
+		}}
+		f := BottleneckFindings(report, nil)[0]
+		if f.File != tt.file || f.Line != tt.line {
+			t.Errorf("%q: location = %s:%d, want %s:%d", tt.location, f.File, f.Line, tt.file, tt.line)
+		}
+	}
+}
+
+func TestBottleneckFindings_NoLocation(t *testing.T) {
+	report := BottleneckReport{Bottlenecks: DetectBottlenecks(testutil.ProfileWithBottlenecks())}
+
+	findings := BottleneckFindings(report, testutil.ProfileWithBottlenecks())
+	testutil.AssertSliceLen(t, findings, len(report.Bottlenecks))
+	for _, f := range findings {
+		if f.File != "" || f.Line != 0 {
+			t.Errorf("expected no location without scripts or functions, got %s:%d", f.File, f.Line)
+		}
+	}
+}
+
+func TestContentionFindings(t *testing.T) {
+	analysis := ContentionAnalysis{Severity: "medium", GCContention: 3, LockContention: 1, TotalImpactMs: 40,
+		Recommendations: []string{"Reduce allocations"}}
+
+	findings := ContentionFindings(analysis, nil)
+	testutil.AssertSliceLen(t, findings, 2)
+	if findings[0].Rule != "contention/gc-contention" || findings[0].Severity != SeverityMedium {
+		t.Errorf("unexpected finding %+v", findings[0])
+	}
+	testutil.AssertStringContains(t, findings[0].Message, "Reduce allocations")
+	if findings[1].Rule != "contention/lock-contention" {
+		t.Errorf("Rule = %q, want contention/lock-contention", findings[1].Rule)
+	}
+
+	analysis.Severity = "minimal"
+	testutil.AssertSliceEmpty(t, ContentionFindings(analysis, nil))
+}
+
+func TestWarningFindings(t *testing.T) {
+	findings := WarningFindings("crypto", []string{"Weak algorithm in legacy.js:7"}, nil)
+	testutil.AssertSliceLen(t, findings, 1)
+	if findings[0].Rule != "crypto/warning" || findings[0].Severity != SeverityMedium {
+		t.Errorf("unexpected finding %+v", findings[0])
+	}
+	if findings[0].File != "legacy.js" || findings[0].Line != 7 {
+		t.Errorf("location = %s:%d, want legacy.js:7", findings[0].File, findings[0].Line)
+	}
+	testutil.AssertSliceEmpty(t, WarningFindings("workers", nil, nil))
+}
+
+func TestFindingRule(t *testing.T) {
+	tests := map[string]string{
+		"Long Tasks":        "perf/long-tasks",
+		"GC / CC  Pressure": "perf/gc-cc-pressure",
+		"Sync IPC!":         "perf/sync-ipc",
+	}
+	for name, want := range tests {
+		if got := findingRule("perf", name); got != want {
+			t.Errorf("findingRule(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestRepositoryPath(t *testing.T) {
+	repo := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(repo, "src", "js"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(repo, "src", "js", "app.js"), []byte("run()\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Chdir(repo)
+
+	tests := []struct {
+		root string
+		file string
+		want string
+		ok   bool
+	}{
+		{"src", "https://cdn.example.com/js/app.js", "src/js/app.js", true}, // Relative to the checkout, not the root
+		{"src", "https://cdn.example.com/js/vendor.js", "", false},
+		{"", "https://cdn.example.com/js/app.js", "", false},
+		{"", "js/app.js", "js/app.js", true},
+		{"", "", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := RepositoryPath(tt.root, tt.file)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("RepositoryPath(%q, %q) = %q, %v; want %q, %v", tt.root, tt.file, got, ok, tt.want, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,157 @@
+package analyzer
+
+import (
+	"sort"
+
+	"github.com/CedricHerzog/perfowl/internal/parser"
+)
+
+// Flame graph settings
+const (
+	FlameDefaultMinPercent = 0.5 // Frames below this share of the total are pruned
+	flameMaxDepth          = 64  // Deeper frames are folded into their ancestor at this depth
+)
+
+// FlameNode is one frame of a flame graph: a function reached through one call
+// path, with the CPU time of every sample that passed through it
+type FlameNode struct {
+	Name     string       `json:"name"`
+	TotalMs  float64      `json:"total_ms"`
+	SelfMs   float64      `json:"self_ms"`
+	Children []*FlameNode `json:"children,omitempty"`
+}
+
+// child returns the named child, adding it when missing
+func (n *FlameNode) child(name string) *FlameNode {
+	for _, c := range n.Children {
+		if c.Name == name {
+			return c
+		}
+	}
+	c := &FlameNode{Name: name}
+	n.Children = append(n.Children, c)
+	return c
+}
+
+// Depth returns the number of frame levels below and including the node
+func (n *FlameNode) Depth() int {
+	depth := 0
+	for _, c := range n.Children {
+		if d := c.Depth(); d > depth {
+			depth = d
+		}
+	}
+	return depth + 1
+}
+
+// BuildFlameGraph merges every sampled stack into a tree rooted at "all", with
+// one child per thread and the thread's call stacks below it. Time is the
+// sample's CPU delta, as in AnalyzeCallTree. Frames taking less than minPercent
+// of the total are pruned, so their time shows as self time of the parent.
+func BuildFlameGraph(profile *parser.Profile, threadName string, minPercent float64) FlameNode {
+	root := &FlameNode{Name: "all"}
+	if minPercent < 0 {
+		minPercent = 0
+	}
+	interval := profile.Meta.Interval
+
+	for i := range profile.Threads {
+		thread := &profile.Threads[i]
+		if threadName != "" && thread.Name != threadName {
+			continue
+		}
+		stringArray := thread.StringArray
+		if len(stringArray) == 0 {
+			stringArray = profile.Shared.StringArray
+		}
+
+		// Resolve each distinct stack to its root-to-leaf function names once
+		paths := make(map[int][]string)
+		stackPath := func(stackIdx int) []string {
+			if path, ok := paths[stackIdx]; ok {
+				return path
+			}
+			var names []string
+			for s := stackIdx; s >= 0 && s < thread.StackTable.Length && s < len(thread.StackTable.Frame); {
+				names = append(names, flameFrameName(thread, stringArray, thread.StackTable.Frame[s]))
+				if s >= len(thread.StackTable.Prefix) {
+					break
+				}
+				s = thread.StackTable.Prefix[s]
+			}
+			for l, r := 0, len(names)-1; l < r; l, r = l+1, r-1 {
+				names[l], names[r] = names[r], names[l]
+			}
+			if len(names) > flameMaxDepth {
+				names = names[:flameMaxDepth]
+			}
+			paths[stackIdx] = names
+			return names
+		}
+
+		var threadNode *FlameNode
+		samples := &thread.Samples
+		for j := 0; j < samples.Length && j < len(samples.Stack); j++ {
+			stackIdx := samples.Stack[j]
+			if stackIdx < 0 {
+				continue
+			}
+			cpuDelta := interval
+			if j < len(samples.ThreadCPUDelta) && samples.ThreadCPUDelta[j] > 0 {
+				cpuDelta = float64(samples.ThreadCPUDelta[j]) / 1000.0
+			}
+
+			if threadNode == nil {
+				threadNode = root.child(thread.Name)
+			}
+			root.TotalMs += cpuDelta
+			node := threadNode
+			node.TotalMs += cpuDelta
+			for _, name := range stackPath(stackIdx) {
+				node = node.child(name)
+				node.TotalMs += cpuDelta
+			}
+			node.SelfMs += cpuDelta
+		}
+	}
+
+	pruneFlame(root, root.TotalMs*minPercent/100)
+	return *root
+}
+
+// flameFrameName returns the function name of a frame
+func flameFrameName(thread *parser.Thread, stringArray []string, frameIdx int) string {
+	if frameIdx < 0 || frameIdx >= len(thread.FrameTable.Func) {
+		return "(unknown)"
+	}
+	funcIdx := thread.FrameTable.Func[frameIdx]
+	if funcIdx < 0 || funcIdx >= len(thread.FuncTable.Name) {
+		return "(unknown)"
+	}
+	nameIdx := thread.FuncTable.Name[funcIdx]
+	if nameIdx < 0 || nameIdx >= len(stringArray) {
+		return "(unknown)"
+	}
+	return stringArray[nameIdx]
+}
+
+// pruneFlame drops children below minMs, moving their time into the parent's
+// self time, and sorts the rest by time with a name tie-break
+func pruneFlame(node *FlameNode, minMs float64) {
+	kept := node.Children[:0]
+	for _, c := range node.Children {
+		if c.TotalMs < minMs {
+			node.SelfMs += c.TotalMs
+			continue
+		}
+		pruneFlame(c, minMs)
+		kept = append(kept, c)
+	}
+	node.Children = kept
+	sort.Slice(node.Children, func(i, j int) bool {
+		if node.Children[i].TotalMs != node.Children[j].TotalMs {
+			return node.Children[i].TotalMs > node.Children[j].TotalMs
+		}
+		return node.Children[i].Name < node.Children[j].Name
+	})
+}
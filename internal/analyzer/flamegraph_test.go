@@ -0,0 +1,56 @@
+package analyzer
+
+import (
+	"testing"
+
+	"github.com/CedricHerzog/perfowl/internal/testutil"
+)
+
+func TestBuildFlameGraph(t *testing.T) {
+	root := BuildFlameGraph(testutil.ProfileWithCallTree(), "", 0)
+
+	testutil.AssertFloatApproxEqual(t, root.TotalMs, 100, 0.001)
+	testutil.AssertSliceLen(t, root.Children, 1)
+	thread := root.Children[0]
+	if thread.Name != "GeckoMain" {
+		t.Errorf("thread node = %q, want GeckoMain", thread.Name)
+	}
+
+	testutil.AssertSliceLen(t, thread.Children, 1)
+	main := thread.Children[0]
+	if main.Name != "main" || main.SelfMs != 0 {
+		t.Errorf("unexpected root frame %+v", main)
+	}
+
+	// processData and render both take 50ms; the tie breaks by name
+	testutil.AssertSliceLen(t, main.Children, 2)
+	if main.Children[0].Name != "processData" || main.Children[1].Name != "render" {
+		t.Errorf("children = %s, %s; want processData, render", main.Children[0].Name, main.Children[1].Name)
+	}
+	render := main.Children[1]
+	testutil.AssertFloatApproxEqual(t, render.TotalMs, 50, 0.001)
+	testutil.AssertFloatApproxEqual(t, render.SelfMs, 20, 0.001)
+	testutil.AssertFloatApproxEqual(t, render.Children[0].TotalMs, 30, 0.001)
+
+	if got := root.Depth(); got != 5 {
+		t.Errorf("Depth = %d, want 5 (all, thread, main, render, updateDOM)", got)
+	}
+}
+
+func TestBuildFlameGraph_Prune(t *testing.T) {
+	root := BuildFlameGraph(testutil.ProfileWithCallTree(), "", 40)
+
+	render := root.Children[0].Children[0].Children[1]
+	if len(render.Children) != 0 {
+		t.Errorf("expected updateDOM (30%%) to be pruned, got %d children", len(render.Children))
+	}
+	testutil.AssertFloatApproxEqual(t, render.SelfMs, 50, 0.001)
+}
+
+func TestBuildFlameGraph_ThreadFilter(t *testing.T) {
+	root := BuildFlameGraph(testutil.ProfileWithCallTree(), "DOM Worker", 0)
+
+	if root.TotalMs != 0 || len(root.Children) != 0 {
+		t.Errorf("expected an empty flame graph for a missing thread, got %+v", root)
+	}
+}
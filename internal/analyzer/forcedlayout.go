@@ -0,0 +1,285 @@
+package analyzer
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/CedricHerzog/perfowl/internal/parser"
+)
+
+// Thresholds for forced synchronous layout detection
+const (
+	ForcedLayoutSampleWindowMs = 5.0 // How far from a layout the sample naming its culprit may be
+	ForcedLayoutMaxLocations   = 10  // Culprits listed in the bottleneck locations
+)
+
+// jsExecutionMarkers are the markers that bracket JavaScript running on a thread
+var jsExecutionMarkers = map[string]bool{
+	"FunctionCall":       true, // Chrome
+	"EvaluateScript":     true,
+	"v8.evaluateModule":  true,
+	"v8.callFunction":    true,
+	"EventDispatch":      true,
+	"TimerFire":          true,
+	"FireAnimationFrame": true,
+	"FireIdleCallback":   true,
+	"RunMicrotasks":      true,
+	"DOMEvent":           true, // Firefox
+	"JS":                 true,
+}
+
+// ForcedLayoutCulprit is a JavaScript function that forced synchronous layouts
+type ForcedLayoutCulprit struct {
+	Function string   `json:"function"`
+	File     string   `json:"file,omitempty"`
+	Line     int      `json:"line,omitempty"`
+	Trigger  string   `json:"trigger,omitempty"` // DOM API that flushed layout, such as HTMLElement.offsetHeight
+	Count    int      `json:"count"`
+	TotalMs  float64  `json:"total_ms"`
+	MaxMs    float64  `json:"max_ms"`
+	Stack    []string `json:"stack,omitempty"` // Stack of the longest forced layout, innermost first
+}
+
+// Location formats the culprit for Bottleneck.Locations
+func (c ForcedLayoutCulprit) Location() string {
+	loc := CauseFrame{Function: c.Function, File: c.File, Line: c.Line}.String()
+	if c.Trigger != "" {
+		loc += " via " + c.Trigger
+	}
+	return fmt.Sprintf("%s: %d forced layouts, %.1fms", loc, c.Count, c.TotalMs)
+}
+
+// DetectForcedLayouts finds layouts that JavaScript forced synchronously and groups
+// them by the function that caused them. A layout is forced when its marker carries
+// a cause stack, when it runs inside a JavaScript execution marker, or when a sample
+// taken during it has JavaScript calling into layout on the same stack; a layout that
+// merely follows a script, as refresh-driver layouts do, is not. The culprit is the
+// innermost JavaScript frame of the cause stack or that sample, falling back to the
+// sample nearest the layout.
+func DetectForcedLayouts(profile *parser.Profile) []ForcedLayoutCulprit {
+	culprits := make(map[string]*ForcedLayoutCulprit)
+
+	for i := range profile.Threads {
+		thread := &profile.Threads[i]
+		markers := parser.ExtractMarkers(thread, profile.Meta.Categories)
+
+		var jsIntervals [][2]float64
+		for _, m := range markers {
+			if jsExecutionMarkers[m.Name] && m.EndTime > m.StartTime {
+				jsIntervals = append(jsIntervals, [2]float64{m.StartTime, m.EndTime})
+			}
+		}
+
+		for _, m := range markers {
+			if !isLayoutMarker(m.Name) || m.Duration <= 0 {
+				continue
+			}
+
+			frames := markerCauseFrames(profile, thread, m.Data)
+			if frames == nil {
+				frames = markerCauseFrames(profile, thread, milestoneData(m.Data))
+			}
+			forced := len(frames) > 0 || insideIntervals(jsIntervals, m.StartTime, m.EndTime)
+			if len(frames) == 0 {
+				if sampled := forcingSampleFrames(profile, thread, m.StartTime, m.EndTime); sampled != nil {
+					frames, forced = sampled, true
+				} else if forced {
+					frames = nearestSampleFrames(profile, thread, m.StartTime, m.EndTime)
+				}
+			}
+			if !forced {
+				continue
+			}
+
+			culprit, trigger := forcedLayoutCulprit(frames)
+			key := culprit.Function + "\x00" + culprit.File + "\x00" + fmt.Sprint(culprit.Line)
+			c := culprits[key]
+			if c == nil {
+				c = &ForcedLayoutCulprit{Function: culprit.Function, File: culprit.File, Line: culprit.Line}
+				culprits[key] = c
+			}
+			if c.Trigger == "" {
+				c.Trigger = trigger
+			}
+			c.Count++
+			c.TotalMs += m.Duration
+			if m.Duration > c.MaxMs {
+				c.MaxMs = m.Duration
+				c.Stack = make([]string, len(frames))
+				for j, f := range frames {
+					c.Stack[j] = f.String()
+				}
+			}
+		}
+	}
+
+	result := make([]ForcedLayoutCulprit, 0, len(culprits))
+	for _, c := range culprits {
+		result = append(result, *c)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].TotalMs != result[j].TotalMs {
+			return result[i].TotalMs > result[j].TotalMs
+		}
+		return result[i].Function < result[j].Function
+	})
+	return result
+}
+
+// isLayoutMarker reports whether a marker is a Firefox reflow or Chrome layout
+func isLayoutMarker(name string) bool {
+	return name == "Layout" || strings.HasPrefix(name, "Reflow")
+}
+
+// insideIntervals reports whether [start, end] lies within one of the intervals
+func insideIntervals(intervals [][2]float64, start, end float64) bool {
+	for _, iv := range intervals {
+		if start >= iv[0] && end <= iv[1] {
+			return true
+		}
+	}
+	return false
+}
+
+// nearestSampleFrames resolves the stack of the first sample taken during
+// [start, end], or of the closest sample within ForcedLayoutSampleWindowMs
+func nearestSampleFrames(profile *parser.Profile, thread *parser.Thread, start, end float64) []CauseFrame {
+	samples := &thread.Samples
+	n := len(samples.Time)
+	if n == 0 || len(samples.Stack) < n {
+		return nil
+	}
+
+	best, bestDist := -1, ForcedLayoutSampleWindowMs
+	i := sort.SearchFloat64s(samples.Time, start)
+	if i < n && samples.Time[i] <= end {
+		best = i
+	} else {
+		if i > 0 && start-samples.Time[i-1] <= bestDist {
+			best, bestDist = i-1, start-samples.Time[i-1]
+		}
+		if i < n && samples.Time[i]-end <= bestDist {
+			best = i
+		}
+	}
+	if best < 0 || samples.Stack[best] < 0 {
+		return nil
+	}
+	return threadStackFrames(profile, thread, samples.Stack[best])
+}
+
+// forcingSampleFrames resolves the stack of the first sample taken during
+// [start, end] in which JavaScript called into layout, or nil when there is none
+func forcingSampleFrames(profile *parser.Profile, thread *parser.Thread, start, end float64) []CauseFrame {
+	samples := &thread.Samples
+	n := len(samples.Time)
+	if len(samples.Stack) < n {
+		return nil
+	}
+	for i := sort.SearchFloat64s(samples.Time, start); i < n && samples.Time[i] <= end; i++ {
+		if samples.Stack[i] < 0 {
+			continue
+		}
+		frames := threadStackFrames(profile, thread, samples.Stack[i])
+		if jsCallsLayout(frames) {
+			return frames
+		}
+	}
+	return nil
+}
+
+// jsCallsLayout reports whether a stack, innermost first, has a JavaScript frame
+// below (calling) a layout frame
+func jsCallsLayout(frames []CauseFrame) bool {
+	for i, f := range frames {
+		if !isLayoutFrame(f.Function) {
+			continue
+		}
+		for _, caller := range frames[i+1:] {
+			if caller.IsJS {
+				return true
+			}
+		}
+		return false
+	}
+	return false
+}
+
+// isLayoutFrame reports whether a native frame runs layout, such as Firefox's
+// PresShell::DoReflow or Chrome's LocalFrameView::UpdateLayout
+func isLayoutFrame(name string) bool {
+	return strings.Contains(name, "Reflow") || strings.Contains(name, "Layout")
+}
+
+// forcedLayoutCulprit picks the innermost JavaScript frame as the culprit and
+// the innermost non-JavaScript frame above it, such as a DOM getter label, as
+// the trigger
+func forcedLayoutCulprit(frames []CauseFrame) (CauseFrame, string) {
+	trigger := ""
+	for _, f := range frames {
+		if f.IsJS {
+			return f, trigger
+		}
+		if isDOMTriggerFrame(f.Function) {
+			trigger = f.Function
+		}
+	}
+	if len(frames) > 0 {
+		return frames[0], ""
+	}
+	return CauseFrame{Function: "(unknown)"}, ""
+}
+
+// isDOMTriggerFrame reports whether a native frame names a web API, such as
+// Firefox's "get HTMLElement.offsetHeight" label frames
+func isDOMTriggerFrame(name string) bool {
+	name = strings.TrimPrefix(strings.TrimPrefix(name, "get "), "set ")
+	dot := strings.IndexByte(name, '.')
+	return dot > 0 && !strings.Contains(name, "::") && !strings.ContainsAny(name[:dot], " (")
+}
+
+// detectForcedLayout reports layouts forced synchronously by JavaScript
+func detectForcedLayout(profile *parser.Profile) *Bottleneck {
+	culprits := DetectForcedLayouts(profile)
+	if len(culprits) == 0 {
+		return nil
+	}
+
+	var count int
+	var totalDuration, maxDuration float64
+	for _, c := range culprits {
+		count += c.Count
+		totalDuration += c.TotalMs
+		if c.MaxMs > maxDuration {
+			maxDuration = c.MaxMs
+		}
+	}
+
+	severity := SeverityLow
+	if count > 20 || totalDuration > 200 {
+		severity = SeverityHigh
+	} else if count > 5 || totalDuration > 50 {
+		severity = SeverityMedium
+	}
+
+	locations := make([]string, 0, ForcedLayoutMaxLocations)
+	for _, c := range culprits {
+		if len(locations) == ForcedLayoutMaxLocations {
+			break
+		}
+		locations = append(locations, c.Location())
+	}
+
+	return &Bottleneck{
+		Type:           "Forced Synchronous Layout",
+		Severity:       severity,
+		Count:          count,
+		TotalDuration:  totalDuration,
+		AvgDuration:    totalDuration / float64(count),
+		MaxDuration:    maxDuration,
+		Description:    fmt.Sprintf("%d layouts were forced synchronously by %d JavaScript functions, taking %.0fms", count, len(culprits), totalDuration),
+		Recommendation: "Avoid reading layout properties such as offsetHeight or getBoundingClientRect after writing to the DOM; batch reads before writes or defer them to requestAnimationFrame",
+		Locations:      locations,
+	}
+}
@@ -0,0 +1,178 @@
+package analyzer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/CedricHerzog/perfowl/internal/testutil"
+)
+
+func TestDetectForcedLayouts_EmptyProfile(t *testing.T) {
+	culprits := DetectForcedLayouts(testutil.MinimalProfile())
+	if len(culprits) != 0 {
+		t.Errorf("expected no culprits, got %d", len(culprits))
+	}
+	if b := detectForcedLayout(testutil.MinimalProfile()); b != nil {
+		t.Errorf("expected nil bottleneck, got %+v", b)
+	}
+}
+
+func TestDetectForcedLayouts_GroupsByCulprit(t *testing.T) {
+	culprits := DetectForcedLayouts(testutil.ProfileWithForcedLayout())
+	if len(culprits) != 2 {
+		t.Fatalf("expected 2 culprits, got %d: %+v", len(culprits), culprits)
+	}
+
+	measure := culprits[0]
+	if measure.Function != "measureWidth" || measure.File != "https://example.com/app.js" || measure.Line != 42 {
+		t.Errorf("top culprit = %s (%s:%d), want measureWidth (https://example.com/app.js:42)", measure.Function, measure.File, measure.Line)
+	}
+	// Two reflows with cause stacks and one inside the click handler
+	if measure.Count != 3 {
+		t.Errorf("measureWidth count = %d, want 3", measure.Count)
+	}
+	testutil.AssertFloatApproxEqual(t, measure.TotalMs, 13, 0.001)
+	testutil.AssertFloatApproxEqual(t, measure.MaxMs, 6, 0.001)
+	if measure.Trigger != "get HTMLElement.offsetHeight" {
+		t.Errorf("trigger = %q, want get HTMLElement.offsetHeight", measure.Trigger)
+	}
+	if len(measure.Stack) != 3 || measure.Stack[2] != "onClick (https://example.com/app.js:10)" {
+		t.Errorf("unexpected stack %v", measure.Stack)
+	}
+
+	tick := culprits[1]
+	if tick.Function != "tick" || tick.Count != 1 || tick.Line != 80 {
+		t.Errorf("second culprit = %+v, want tick at line 80 with 1 layout", tick)
+	}
+}
+
+func TestDetectForcedLayouts_LayoutAfterScript(t *testing.T) {
+	// Refresh-driver reflows right after a JS task: the closest samples before them
+	// are in JavaScript, but none taken during them has JavaScript calling layout
+	markers, strs := testutil.NewMarkerBuilder().
+		AddCustom("Reflow", 3, 100, 4, map[string]interface{}{"type": "Reflow"}).
+		AddCustom("Reflow", 3, 200, 4, map[string]interface{}{"type": "Reflow"}).
+		Build()
+
+	fn := len(strs)
+	strs = append(strs, "tick", "PresShell::DoReflow", "nsRefreshDriver::Tick")
+	fnb := testutil.NewFuncTableBuilder()
+	fnb.AddFunc(fn, true, -1)
+	fnb.AddFunc(fn+1, false, -1)
+	fnb.AddFunc(fn+2, false, -1)
+	ftb := testutil.NewFrameTableBuilder()
+	ftb.AddFrame(0, 2)
+	ftb.AddFrame(1, 3)
+	ftb.AddFrame(2, 0)
+	stb := testutil.NewStackTableBuilder()
+	stb.AddStack(0, 2, -1) // tick
+	stb.AddStack(2, 0, -1) // nsRefreshDriver::Tick
+	stb.AddStack(1, 3, 1)  // nsRefreshDriver::Tick → PresShell::DoReflow
+	sb := testutil.NewSamplesBuilder().
+		AddSample(0, 98).
+		AddSample(0, 99.5).
+		AddSample(0, 199).
+		AddSample(2, 201)
+
+	profile := testutil.NewProfileBuilder().
+		WithCategories(testutil.DefaultCategories()).
+		WithThread(testutil.NewThreadBuilder("GeckoMain").
+			AsMainThread().
+			WithStringArray(strs).
+			WithMarkers(markers).
+			WithStackTable(stb.Build()).
+			WithFrameTable(ftb.Build()).
+			WithFuncTable(fnb.Build()).
+			WithSamples(sb.Build()).
+			Build()).
+		Build()
+
+	if culprits := DetectForcedLayouts(profile); len(culprits) != 0 {
+		t.Errorf("expected no forced layouts, got %+v", culprits)
+	}
+	if b := detectForcedLayout(profile); b != nil {
+		t.Errorf("expected no bottleneck, got %+v", b)
+	}
+}
+
+func TestDetectForcedLayouts_ChromeStackTrace(t *testing.T) {
+	stackTrace := []interface{}{
+		map[string]interface{}{"functionName": "readHeight", "url": "https://example.com/a.js", "lineNumber": float64(9), "columnNumber": float64(4)},
+		map[string]interface{}{"functionName": "update", "url": "https://example.com/a.js", "lineNumber": float64(1), "columnNumber": float64(0)},
+	}
+	mb := testutil.NewMarkerBuilder().
+		AddCustom("Layout", 3, 100, 8, map[string]interface{}{"beginData": map[string]interface{}{"stackTrace": stackTrace}}).
+		AddCustom("Layout", 3, 200, 4, map[string]interface{}{"beginData": map[string]interface{}{}})
+	markers, strs := mb.Build()
+	profile := testutil.NewProfileBuilder().
+		WithCategories(testutil.DefaultCategories()).
+		WithThread(testutil.NewThreadBuilder("CrRendererMain").
+			AsMainThread().
+			WithStringArray(strs).
+			WithMarkers(markers).
+			Build()).
+		Build()
+
+	culprits := DetectForcedLayouts(profile)
+	if len(culprits) != 1 {
+		t.Fatalf("expected 1 culprit, got %d: %+v", len(culprits), culprits)
+	}
+	c := culprits[0]
+	if c.Function != "readHeight" || c.Line != 10 || c.Count != 1 {
+		t.Errorf("culprit = %+v, want readHeight at line 10", c)
+	}
+	if c.Stack[0] != "readHeight (https://example.com/a.js:10:5)" {
+		t.Errorf("stack[0] = %q", c.Stack[0])
+	}
+}
+
+func TestDetectForcedLayout_Bottleneck(t *testing.T) {
+	b := detectForcedLayout(testutil.ProfileWithForcedLayout())
+	if b == nil {
+		t.Fatal("expected forced layout bottleneck")
+	}
+	if b.Type != "Forced Synchronous Layout" {
+		t.Errorf("Type = %v, want Forced Synchronous Layout", b.Type)
+	}
+	if b.Count != 4 {
+		t.Errorf("Count = %d, want 4", b.Count)
+	}
+	testutil.AssertFloatApproxEqual(t, b.TotalDuration, 15, 0.001)
+	if b.Severity != SeverityLow {
+		t.Errorf("Severity = %v, want low", b.Severity)
+	}
+	if len(b.Locations) != 2 {
+		t.Fatalf("expected 2 locations, got %v", b.Locations)
+	}
+	want := "measureWidth (https://example.com/app.js:42) via get HTMLElement.offsetHeight: 3 forced layouts, 13.0ms"
+	if b.Locations[0] != want {
+		t.Errorf("Locations[0] = %q, want %q", b.Locations[0], want)
+	}
+}
+
+func TestDetectBottlenecks_IncludesForcedLayout(t *testing.T) {
+	found := false
+	for _, b := range DetectBottlenecks(testutil.ProfileWithForcedLayout()) {
+		if strings.Contains(b.Type, "Forced Synchronous Layout") {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("expected DetectBottlenecks to report forced synchronous layouts")
+	}
+}
+
+func TestIsDOMTriggerFrame(t *testing.T) {
+	tests := map[string]bool{
+		"get HTMLElement.offsetHeight":   true,
+		"Element.getBoundingClientRect":  true,
+		"PresShell::DoReflow":            false,
+		"measureWidth":                   false,
+		"js::RunScript (self-hosted.js)": false,
+	}
+	for name, want := range tests {
+		if got := isDOMTriggerFrame(name); got != want {
+			t.Errorf("isDOMTriggerFrame(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
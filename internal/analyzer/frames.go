@@ -0,0 +1,499 @@
+package analyzer
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/CedricHerzog/perfowl/internal/parser"
+)
+
+// Frame analysis thresholds
+const (
+	DefaultVsyncIntervalMs = 1000.0 / 60.0
+	FrameJankMultiplier    = 1.5   // A frame is janky when it takes longer than 1.5 vsync intervals
+	FrameSevereJankMs      = 50.0  // Frames at least this long are visible stutters
+	FrameIdleGapMs         = 250.0 // Longer gaps with an idle main thread are not frames
+	FrameMaxVsyncMs        = 100.0 // Vsync deltas above this are pauses, not intervals
+)
+
+// frameSourceMarkers lists the markers that mark a presented frame, in order of preference
+var frameSourceMarkers = []string{
+	"DrawFrame",         // Chrome: frame drawn by the compositor
+	"Composite",         // Firefox: compositor frame
+	"RefreshDriverTick", // Firefox: main-thread frame
+	"BeginFrame",        // Chrome: frame started
+}
+
+// vsyncMarkers lists the markers that carry vsync timestamps
+var vsyncMarkers = []string{"Vsync", "VsyncTimestamp", "BeginFrame"}
+
+// FrameWork is a main-thread marker that overlapped a frame
+type FrameWork struct {
+	Name       string  `json:"name"`
+	Category   string  `json:"category"`
+	StartTime  float64 `json:"start_time"`
+	DurationMs float64 `json:"duration_ms"`
+	OverlapMs  float64 `json:"overlap_ms"`
+}
+
+// FrameFunction is a function that ran on the main thread during a frame
+type FrameFunction struct {
+	Name   string  `json:"name"`
+	TimeMs float64 `json:"time_ms"`
+}
+
+// FrameStats describes a single frame
+type FrameStats struct {
+	Index            int             `json:"index"`
+	StartTime        float64         `json:"start_time"`
+	EndTime          float64         `json:"end_time"`
+	DurationMs       float64         `json:"duration_ms"`
+	DroppedFrames    int             `json:"dropped_frames"`
+	MainThreadBusyMs float64         `json:"main_thread_busy_ms"`
+	Markers          []FrameWork     `json:"markers,omitempty"`
+	TopFunctions     []FrameFunction `json:"top_functions,omitempty"`
+}
+
+// FrameBucket is one bin of the frame duration distribution
+type FrameBucket struct {
+	Label   string  `json:"label"`
+	MinMs   float64 `json:"min_ms"`
+	MaxMs   float64 `json:"max_ms,omitempty"` // Zero for the open-ended last bucket
+	Count   int     `json:"count"`
+	Percent float64 `json:"percent"`
+}
+
+// FrameAnalysis contains frame rate and jank results
+type FrameAnalysis struct {
+	Source           string        `json:"source"`
+	SourceThread     string        `json:"source_thread,omitempty"`
+	MainThread       string        `json:"main_thread,omitempty"`
+	VsyncIntervalMs  float64       `json:"vsync_interval_ms"`
+	VsyncSource      string        `json:"vsync_source"`
+	TotalFrames      int           `json:"total_frames"`
+	AverageFPS       float64       `json:"average_fps"`
+	MeanFrameMs      float64       `json:"mean_frame_ms"`
+	P50FrameMs       float64       `json:"p50_frame_ms"`
+	P95FrameMs       float64       `json:"p95_frame_ms"`
+	P99FrameMs       float64       `json:"p99_frame_ms"`
+	MaxFrameMs       float64       `json:"max_frame_ms"`
+	DroppedFrames    int           `json:"dropped_frames"`
+	JankyFrames      int           `json:"janky_frames"`
+	JankyPercent     float64       `json:"janky_percent"`
+	SevereJankFrames int           `json:"severe_jank_frames"`
+	IdleGaps         int           `json:"idle_gaps"`
+	Distribution     []FrameBucket `json:"distribution"`
+	LongestFrames    []FrameStats  `json:"longest_frames"`
+	Recommendations  []string      `json:"recommendations,omitempty"`
+}
+
+// AnalyzeFrames measures frame timing from refresh driver, compositor, vsync and
+// Chrome frame markers. Frames come from the thread with the most markers of the
+// preferred kind, and the longest frames are linked to overlapping main-thread work.
+func AnalyzeFrames(profile *parser.Profile, limit int) FrameAnalysis {
+	analysis := FrameAnalysis{
+		Distribution:  make([]FrameBucket, 0),
+		LongestFrames: make([]FrameStats, 0),
+	}
+
+	if limit <= 0 {
+		limit = 10
+	}
+
+	// Collect markers per thread once
+	threadMarkers := make([][]parser.ParsedMarker, len(profile.Threads))
+	for i := range profile.Threads {
+		threadMarkers[i] = parser.ExtractMarkers(&profile.Threads[i], profile.Meta.Categories)
+	}
+
+	// Pick the frame source: first marker kind found, on the thread with the most of them
+	sourceThread := -1
+	var frameTimes []float64
+	for _, name := range frameSourceMarkers {
+		best := -1
+		var bestTimes []float64
+		for ti, markers := range threadMarkers {
+			times := frameTimestamps(markers, name)
+			if len(times) > len(bestTimes) {
+				best = ti
+				bestTimes = times
+			}
+		}
+		if len(bestTimes) >= 2 {
+			analysis.Source = name
+			sourceThread = best
+			frameTimes = bestTimes
+			break
+		}
+	}
+
+	if sourceThread < 0 {
+		analysis.VsyncIntervalMs = DefaultVsyncIntervalMs
+		analysis.VsyncSource = "default"
+		analysis.Recommendations = append(analysis.Recommendations,
+			"No frame markers found. Capture with the Graphics feature (Firefox) or the devtools.timeline.frame category (Chrome).")
+		return analysis
+	}
+
+	source := &profile.Threads[sourceThread]
+	analysis.SourceThread = source.Name
+
+	// Estimate the vsync interval from vsync markers on any thread
+	analysis.VsyncIntervalMs, analysis.VsyncSource = estimateVsyncInterval(threadMarkers, analysis.Source)
+
+	// Link frames to the main thread of the same process
+	mainIdx := frameMainThread(profile, sourceThread)
+	if mainIdx >= 0 {
+		analysis.MainThread = profile.Threads[mainIdx].Name
+	}
+
+	vsync := analysis.VsyncIntervalMs
+	durations := make([]float64, 0, len(frameTimes))
+	frames := make([]FrameStats, 0, len(frameTimes))
+
+	for i := 1; i < len(frameTimes); i++ {
+		start, end := frameTimes[i-1], frameTimes[i]
+		duration := end - start
+		if duration <= 0 {
+			continue
+		}
+
+		frame := FrameStats{
+			Index:      len(frames),
+			StartTime:  start,
+			EndTime:    end,
+			DurationMs: duration,
+		}
+		if mainIdx >= 0 {
+			frame.MainThreadBusyMs = mainThreadBusyTime(profile, &profile.Threads[mainIdx], start, end)
+		}
+
+		// Long gaps with an idle main thread mean nothing needed painting
+		if mainIdx >= 0 && duration > FrameIdleGapMs && frame.MainThreadBusyMs < duration/2 {
+			analysis.IdleGaps++
+			continue
+		}
+
+		if missed := int(math.Round(duration/vsync)) - 1; missed > 0 {
+			frame.DroppedFrames = missed
+			analysis.DroppedFrames += missed
+		}
+		if duration > vsync*FrameJankMultiplier {
+			analysis.JankyFrames++
+		}
+		if duration >= FrameSevereJankMs {
+			analysis.SevereJankFrames++
+		}
+
+		durations = append(durations, duration)
+		frames = append(frames, frame)
+	}
+
+	analysis.TotalFrames = len(frames)
+	if len(frames) == 0 {
+		return analysis
+	}
+
+	total := 0.0
+	for _, d := range durations {
+		total += d
+	}
+	analysis.MeanFrameMs = total / float64(len(durations))
+	if total > 0 {
+		analysis.AverageFPS = float64(len(durations)) / (total / 1000.0)
+	}
+	analysis.JankyPercent = float64(analysis.JankyFrames) / float64(len(frames)) * 100
+
+	sorted := append([]float64(nil), durations...)
+	sort.Float64s(sorted)
+	analysis.P50FrameMs = percentile(sorted, 50)
+	analysis.P95FrameMs = percentile(sorted, 95)
+	analysis.P99FrameMs = percentile(sorted, 99)
+	analysis.MaxFrameMs = sorted[len(sorted)-1]
+
+	analysis.Distribution = frameDistribution(durations, vsync)
+
+	// Longest frames with the main-thread work that overlapped them
+	sort.SliceStable(frames, func(i, j int) bool {
+		return frames[i].DurationMs > frames[j].DurationMs
+	})
+	if len(frames) > limit {
+		frames = frames[:limit]
+	}
+	for i := range frames {
+		if frames[i].DurationMs <= vsync*FrameJankMultiplier {
+			frames = frames[:i]
+			break
+		}
+		if mainIdx >= 0 {
+			frames[i].Markers = overlappingFrameWork(threadMarkers[mainIdx], frames[i].StartTime, frames[i].EndTime)
+			frames[i].TopFunctions = frameTopFunctions(profile, &profile.Threads[mainIdx], frames[i].StartTime, frames[i].EndTime)
+		}
+	}
+	analysis.LongestFrames = frames
+
+	analysis.Recommendations = frameRecommendations(analysis)
+
+	return analysis
+}
+
+// frameTimestamps returns the sorted presentation times of the named frame markers.
+// Interval markers count at their end, instant markers at their start.
+func frameTimestamps(markers []parser.ParsedMarker, name string) []float64 {
+	var times []float64
+	for _, m := range markers {
+		if m.Name != name {
+			continue
+		}
+		if m.Duration > 0 {
+			times = append(times, m.EndTime)
+		} else {
+			times = append(times, m.StartTime)
+		}
+	}
+	sort.Float64s(times)
+	return times
+}
+
+// estimateVsyncInterval returns the median interval between vsync markers, or 60Hz
+func estimateVsyncInterval(threadMarkers [][]parser.ParsedMarker, frameSource string) (float64, string) {
+	for _, name := range vsyncMarkers {
+		if name == frameSource {
+			continue
+		}
+		var deltas []float64
+		for _, markers := range threadMarkers {
+			times := frameTimestamps(markers, name)
+			for i := 1; i < len(times); i++ {
+				if d := times[i] - times[i-1]; d > 0 && d < FrameMaxVsyncMs {
+					deltas = append(deltas, d)
+				}
+			}
+		}
+		if len(deltas) > 0 {
+			sort.Float64s(deltas)
+			return percentile(deltas, 50), name
+		}
+	}
+	return DefaultVsyncIntervalMs, "default"
+}
+
+// frameMainThread returns the main thread in the frame source's process, or -1
+// when no thread was sampled
+func frameMainThread(profile *parser.Profile, sourceThread int) int {
+	source := &profile.Threads[sourceThread]
+	if source.IsMainThread {
+		return sourceThread
+	}
+	fallback := -1
+	for i := range profile.Threads {
+		if !profile.Threads[i].IsMainThread {
+			continue
+		}
+		if profile.Threads[i].PID == source.PID {
+			return i
+		}
+		if fallback < 0 {
+			fallback = i
+		}
+	}
+	if fallback >= 0 {
+		return fallback
+	}
+
+	// No thread is flagged as main (Chrome conversions, --threads selections): use
+	// the thread that owns the frames if it was sampled, otherwise the busiest one
+	if source.Samples.Length > 0 {
+		return sourceThread
+	}
+	busiest := -1
+	for i := range profile.Threads {
+		if n := profile.Threads[i].Samples.Length; n > 0 && (busiest < 0 || n > profile.Threads[busiest].Samples.Length) {
+			busiest = i
+		}
+	}
+	return busiest
+}
+
+// sampleTime returns the CPU time of a sample using the thread CPU delta convention
+func sampleTime(samples *parser.Samples, i int, interval float64) float64 {
+	if i < len(samples.ThreadCPUDelta) && samples.ThreadCPUDelta[i] > 0 {
+		return float64(samples.ThreadCPUDelta[i]) / 1000.0
+	}
+	return interval
+}
+
+// isIdleSample reports whether a sample's stack is in the Idle category
+func isIdleSample(profile *parser.Profile, thread *parser.Thread, stackIdx int) bool {
+	if stackIdx < 0 || stackIdx >= len(thread.StackTable.Category) {
+		return true
+	}
+	cat := profile.GetCategoryByIndex(thread.StackTable.Category[stackIdx])
+	return cat != nil && cat.Name == "Idle"
+}
+
+// mainThreadBusyTime sums the non-idle sample time of a thread in (start, end]
+func mainThreadBusyTime(profile *parser.Profile, thread *parser.Thread, start, end float64) float64 {
+	busy := 0.0
+	samples := &thread.Samples
+	for i := 0; i < samples.Length && i < len(samples.Time); i++ {
+		t := samples.Time[i]
+		if t <= start || t > end {
+			continue
+		}
+		stackIdx := -1
+		if i < len(samples.Stack) {
+			stackIdx = samples.Stack[i]
+		}
+		if isIdleSample(profile, thread, stackIdx) {
+			continue
+		}
+		busy += sampleTime(samples, i, profile.Meta.Interval)
+	}
+	return math.Min(busy, end-start)
+}
+
+// overlappingFrameWork returns the main-thread interval markers overlapping a frame
+func overlappingFrameWork(markers []parser.ParsedMarker, start, end float64) []FrameWork {
+	work := make([]FrameWork, 0)
+	for _, m := range markers {
+		if m.Duration <= 0 || m.EndTime <= start || m.StartTime >= end {
+			continue
+		}
+		overlap := math.Min(m.EndTime, end) - math.Max(m.StartTime, start)
+		work = append(work, FrameWork{
+			Name:       m.Name,
+			Category:   m.Category,
+			StartTime:  m.StartTime,
+			DurationMs: m.Duration,
+			OverlapMs:  overlap,
+		})
+	}
+	sort.SliceStable(work, func(i, j int) bool {
+		return work[i].OverlapMs > work[j].OverlapMs
+	})
+	if len(work) > 5 {
+		work = work[:5]
+	}
+	return work
+}
+
+// frameTopFunctions returns the leaf functions with the most sample time in (start, end]
+func frameTopFunctions(profile *parser.Profile, thread *parser.Thread, start, end float64) []FrameFunction {
+	stringArray := thread.StringArray
+	if len(stringArray) == 0 {
+		stringArray = profile.Shared.StringArray
+	}
+
+	times := make(map[string]float64)
+	samples := &thread.Samples
+	for i := 0; i < samples.Length && i < len(samples.Time); i++ {
+		t := samples.Time[i]
+		if t <= start || t > end {
+			continue
+		}
+		stackIdx := -1
+		if i < len(samples.Stack) {
+			stackIdx = samples.Stack[i]
+		}
+		if stackIdx < 0 || stackIdx >= len(thread.StackTable.Frame) || isIdleSample(profile, thread, stackIdx) {
+			continue
+		}
+		frameIdx := thread.StackTable.Frame[stackIdx]
+		if frameIdx < 0 || frameIdx >= len(thread.FrameTable.Func) {
+			continue
+		}
+		funcIdx := thread.FrameTable.Func[frameIdx]
+		if funcIdx < 0 || funcIdx >= len(thread.FuncTable.Name) {
+			continue
+		}
+		nameIdx := thread.FuncTable.Name[funcIdx]
+		if nameIdx < 0 || nameIdx >= len(stringArray) {
+			continue
+		}
+		times[stringArray[nameIdx]] += sampleTime(samples, i, profile.Meta.Interval)
+	}
+
+	funcs := make([]FrameFunction, 0, len(times))
+	for name, ms := range times {
+		funcs = append(funcs, FrameFunction{Name: name, TimeMs: ms})
+	}
+	sort.Slice(funcs, func(i, j int) bool {
+		if funcs[i].TimeMs != funcs[j].TimeMs {
+			return funcs[i].TimeMs > funcs[j].TimeMs
+		}
+		return funcs[i].Name < funcs[j].Name
+	})
+	if len(funcs) > 5 {
+		funcs = funcs[:5]
+	}
+	return funcs
+}
+
+// frameDistribution bins frame durations by multiples of the vsync interval
+func frameDistribution(durations []float64, vsync float64) []FrameBucket {
+	limits := []float64{vsync * FrameJankMultiplier, vsync * 2.5, FrameSevereJankMs, 100}
+	buckets := make([]FrameBucket, 0, len(limits)+1)
+	lower := 0.0
+	for _, upper := range limits {
+		if upper <= lower {
+			continue
+		}
+		buckets = append(buckets, FrameBucket{
+			Label: fmt.Sprintf("%.1f-%.1fms", lower, upper),
+			MinMs: lower,
+			MaxMs: upper,
+		})
+		lower = upper
+	}
+	buckets = append(buckets, FrameBucket{Label: fmt.Sprintf(">%.1fms", lower), MinMs: lower})
+
+	for _, d := range durations {
+		for i := range buckets {
+			if buckets[i].MaxMs == 0 || d <= buckets[i].MaxMs {
+				buckets[i].Count++
+				break
+			}
+		}
+	}
+	for i := range buckets {
+		buckets[i].Percent = float64(buckets[i].Count) / float64(len(durations)) * 100
+	}
+	return buckets
+}
+
+// frameRecommendations suggests follow-ups based on frame results
+func frameRecommendations(analysis FrameAnalysis) []string {
+	var recs []string
+	if analysis.SevereJankFrames > 0 {
+		recs = append(recs, fmt.Sprintf("%d frames took %.0fms or more; check the main-thread work listed for the longest frames", analysis.SevereJankFrames, FrameSevereJankMs))
+	}
+	if analysis.JankyPercent > 10 {
+		recs = append(recs, fmt.Sprintf("%.1f%% of frames missed the vsync deadline; split long tasks or move work off the main thread", analysis.JankyPercent))
+	}
+	for _, f := range analysis.LongestFrames {
+		if f.MainThreadBusyMs < f.DurationMs/2 {
+			recs = append(recs, "Some long frames had a mostly idle main thread; look at compositor, GPU or other process activity")
+			break
+		}
+	}
+	return recs
+}
+
+// percentile returns the p-th percentile of sorted values using linear interpolation
+func percentile(sorted []float64, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	if len(sorted) == 1 {
+		return sorted[0]
+	}
+	rank := p / 100 * float64(len(sorted)-1)
+	lower := int(math.Floor(rank))
+	upper := int(math.Ceil(rank))
+	if upper >= len(sorted) {
+		return sorted[len(sorted)-1]
+	}
+	return sorted[lower] + (sorted[upper]-sorted[lower])*(rank-float64(lower))
+}
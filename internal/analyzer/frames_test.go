@@ -0,0 +1,203 @@
+package analyzer
+
+import (
+	"testing"
+
+	"github.com/CedricHerzog/perfowl/internal/testutil"
+)
+
+func TestAnalyzeFrames_RefreshDriver(t *testing.T) {
+	analysis := AnalyzeFrames(testutil.ProfileWithFrames(), 10)
+
+	if analysis.Source != "RefreshDriverTick" {
+		t.Errorf("Source = %s, want RefreshDriverTick", analysis.Source)
+	}
+	if analysis.SourceThread != "GeckoMain" || analysis.MainThread != "GeckoMain" {
+		t.Errorf("threads = %s/%s, want GeckoMain", analysis.SourceThread, analysis.MainThread)
+	}
+	if analysis.VsyncSource != "default" {
+		t.Errorf("VsyncSource = %s, want default", analysis.VsyncSource)
+	}
+	if analysis.TotalFrames != 43 {
+		t.Errorf("TotalFrames = %d, want 43", analysis.TotalFrames)
+	}
+	if analysis.IdleGaps != 1 {
+		t.Errorf("IdleGaps = %d, want 1", analysis.IdleGaps)
+	}
+	if analysis.JankyFrames != 1 || analysis.SevereJankFrames != 1 {
+		t.Errorf("janky/severe = %d/%d, want 1/1", analysis.JankyFrames, analysis.SevereJankFrames)
+	}
+	if analysis.DroppedFrames != 5 {
+		t.Errorf("DroppedFrames = %d, want 5", analysis.DroppedFrames)
+	}
+
+	testutil.AssertFloatApproxEqual(t, analysis.P50FrameMs, 1000.0/60.0, 0.01)
+	testutil.AssertFloatApproxEqual(t, analysis.MaxFrameMs, 100, 0.01)
+	if analysis.P99FrameMs <= analysis.P95FrameMs {
+		t.Errorf("expected p99 (%.2f) above p95 (%.2f)", analysis.P99FrameMs, analysis.P95FrameMs)
+	}
+}
+
+func TestAnalyzeFrames_NoFlaggedMainThread(t *testing.T) {
+	mb := testutil.NewMarkerBuilder()
+	for _, ts := range []float64{0, 16, 33, 333, 350} {
+		mb.AddCustom("RefreshDriverTick", 4, ts, 0, nil)
+	}
+	markers, strs := mb.Build()
+	strs = append(strs, "runScript")
+
+	fnb := testutil.NewFuncTableBuilder()
+	fnb.AddFunc(len(strs)-1, true, -1)
+	ftb := testutil.NewFrameTableBuilder()
+	ftb.AddFrame(0, 2)
+	stb := testutil.NewStackTableBuilder()
+	stb.AddStack(0, 2, -1)
+	sb := testutil.NewSamplesBuilder()
+	for ts := 34.0; ts <= 333; ts++ {
+		sb.AddSample(0, ts) // JavaScript through the 300ms frame
+	}
+
+	// A Chrome conversion flags no thread as the main thread
+	profile := testutil.NewProfileBuilder().
+		WithCategories(testutil.DefaultCategories()).
+		WithThread(testutil.NewThreadBuilder("CrRendererMain").
+			WithStringArray(strs).
+			WithMarkers(markers).
+			WithStackTable(stb.Build()).
+			WithFrameTable(ftb.Build()).
+			WithFuncTable(fnb.Build()).
+			WithSamples(sb.Build()).
+			Build()).
+		Build()
+
+	analysis := AnalyzeFrames(profile, 10)
+
+	if analysis.MainThread != "CrRendererMain" {
+		t.Errorf("MainThread = %q, want the thread that owns the frames", analysis.MainThread)
+	}
+	if analysis.IdleGaps != 0 || analysis.SevereJankFrames != 1 {
+		t.Errorf("idle gaps/severe = %d/%d, want the busy 300ms frame counted as jank", analysis.IdleGaps, analysis.SevereJankFrames)
+	}
+}
+
+func TestAnalyzeFrames_LongestFrames(t *testing.T) {
+	analysis := AnalyzeFrames(testutil.ProfileWithFrames(), 10)
+
+	// Only janky frames are listed
+	if len(analysis.LongestFrames) != 1 {
+		t.Fatalf("expected 1 longest frame, got %d", len(analysis.LongestFrames))
+	}
+
+	frame := analysis.LongestFrames[0]
+	testutil.AssertFloatApproxEqual(t, frame.DurationMs, 100, 0.01)
+	testutil.AssertFloatApproxEqual(t, frame.MainThreadBusyMs, 90, 0.01)
+	if frame.DroppedFrames != 5 {
+		t.Errorf("DroppedFrames = %d, want 5", frame.DroppedFrames)
+	}
+
+	if len(frame.TopFunctions) != 1 || frame.TopFunctions[0].Name != "layoutHeavy" {
+		t.Errorf("TopFunctions = %v, want layoutHeavy", frame.TopFunctions)
+	}
+
+	foundLayout := false
+	for _, w := range frame.Markers {
+		if w.Name == "Reflow" {
+			foundLayout = true
+			testutil.AssertFloatApproxEqual(t, w.OverlapMs, 80, 0.01)
+		}
+	}
+	if !foundLayout {
+		t.Errorf("expected layout marker in frame work, got %v", frame.Markers)
+	}
+}
+
+func TestAnalyzeFrames_Distribution(t *testing.T) {
+	analysis := AnalyzeFrames(testutil.ProfileWithFrames(), 10)
+
+	total := 0
+	for _, b := range analysis.Distribution {
+		total += b.Count
+	}
+	if total != analysis.TotalFrames {
+		t.Errorf("distribution counts %d frames, want %d", total, analysis.TotalFrames)
+	}
+	if analysis.Distribution[0].Count != 42 {
+		t.Errorf("first bucket = %d, want 42", analysis.Distribution[0].Count)
+	}
+	if last := analysis.Distribution[len(analysis.Distribution)-1]; last.MaxMs != 0 {
+		t.Errorf("last bucket should be open-ended, got max %.1f", last.MaxMs)
+	}
+}
+
+func TestAnalyzeFrames_ChromeDrawFrame(t *testing.T) {
+	mb := testutil.NewMarkerBuilder()
+	// BeginFrame every 8.33ms (120Hz), DrawFrame every other vsync
+	for i := 0; i < 40; i++ {
+		mb.AddCustom("BeginFrame", 4, float64(i)*1000/120, 0, nil)
+	}
+	for i := 0; i < 20; i++ {
+		mb.AddCustom("DrawFrame", 4, float64(i)*2000/120, 0, nil)
+	}
+	markers, strs := mb.Build()
+
+	profile := testutil.NewProfileBuilder().
+		WithCategories(testutil.DefaultCategories()).
+		WithThread(testutil.NewThreadBuilder("CrRendererMain").
+			AsMainThread().
+			WithStringArray(strs).
+			WithMarkers(markers).
+			Build()).
+		Build()
+
+	analysis := AnalyzeFrames(profile, 5)
+
+	if analysis.Source != "DrawFrame" {
+		t.Errorf("Source = %s, want DrawFrame", analysis.Source)
+	}
+	if analysis.VsyncSource != "BeginFrame" {
+		t.Errorf("VsyncSource = %s, want BeginFrame", analysis.VsyncSource)
+	}
+	testutil.AssertFloatApproxEqual(t, analysis.VsyncIntervalMs, 1000.0/120, 0.01)
+	if analysis.TotalFrames != 19 {
+		t.Errorf("TotalFrames = %d, want 19", analysis.TotalFrames)
+	}
+	// Every frame misses one 120Hz vsync
+	if analysis.DroppedFrames != 19 {
+		t.Errorf("DroppedFrames = %d, want 19", analysis.DroppedFrames)
+	}
+	testutil.AssertFloatApproxEqual(t, analysis.AverageFPS, 60, 0.1)
+}
+
+func TestAnalyzeFrames_NoFrameMarkers(t *testing.T) {
+	analysis := AnalyzeFrames(testutil.MinimalProfile(), 10)
+
+	if analysis.TotalFrames != 0 || analysis.Source != "" {
+		t.Errorf("expected no frames, got %d from %q", analysis.TotalFrames, analysis.Source)
+	}
+	if len(analysis.Recommendations) == 0 {
+		t.Error("expected a recommendation about missing frame markers")
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	values := []float64{1, 2, 3, 4, 5}
+	tests := []struct {
+		p    float64
+		want float64
+	}{
+		{0, 1},
+		{50, 3},
+		{75, 4},
+		{90, 4.6},
+		{100, 5},
+	}
+	for _, tt := range tests {
+		testutil.AssertFloatApproxEqual(t, percentile(values, tt.p), tt.want, 0.0001)
+	}
+	if percentile(nil, 50) != 0 {
+		t.Error("expected 0 for empty values")
+	}
+	if percentile([]float64{7}, 99) != 7 {
+		t.Error("expected single value")
+	}
+}
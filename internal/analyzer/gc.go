@@ -0,0 +1,564 @@
+package analyzer
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/CedricHerzog/perfowl/internal/parser"
+)
+
+// GC analysis thresholds
+const (
+	GCMMUShortWindowMs   = 20.0 // Windows for minimum mutator utilisation
+	GCMMULongWindowMs    = 50.0
+	GCMMULow             = 0.5  // Less than half of a 50ms window left to the page
+	GCPromotionRateHigh  = 0.3  // Nursery collections tenuring more than 30% of the nursery
+	GCPrecedingWindowMs  = 50.0 // Samples this long before a GC are searched for allocators
+	gcUnknownReason      = "(unknown)"
+	gcNonincrementalNone = "None"
+)
+
+// GC collection kinds
+const (
+	GCKindMajor = "major"
+	GCKindMinor = "minor"
+)
+
+// GCCollection is a single major or minor garbage collection
+type GCCollection struct {
+	Kind                 string  `json:"kind"`
+	StartTime            float64 `json:"start_time"`
+	DurationMs           float64 `json:"duration_ms"`
+	Reason               string  `json:"reason"`
+	Thread               string  `json:"thread"`
+	ZonesCollected       int     `json:"zones_collected,omitempty"`
+	TotalZones           int     `json:"total_zones,omitempty"`
+	Slices               int     `json:"slices,omitempty"`
+	MaxPauseMs           float64 `json:"max_pause_ms,omitempty"`
+	NonincrementalReason string  `json:"nonincremental_reason,omitempty"`
+	HeapBeforeBytes      int64   `json:"heap_before_bytes,omitempty"`
+	HeapAfterBytes       int64   `json:"heap_after_bytes,omitempty"`
+	NurseryBytes         int64   `json:"nursery_bytes,omitempty"`
+	TenuredBytes         int64   `json:"tenured_bytes,omitempty"`
+	PromotionRate        float64 `json:"promotion_rate,omitempty"`
+}
+
+// GCReasonStats counts the collections triggered for one reason
+type GCReasonStats struct {
+	Reason  string  `json:"reason"`
+	Kind    string  `json:"kind"`
+	Count   int     `json:"count"`
+	TotalMs float64 `json:"total_ms"`
+	MaxMs   float64 `json:"max_ms"`
+}
+
+// GCSliceOverBudget is an incremental GC slice that paused longer than its budget
+type GCSliceOverBudget struct {
+	StartTime float64 `json:"start_time"`
+	PauseMs   float64 `json:"pause_ms"`
+	BudgetMs  float64 `json:"budget_ms"`
+	OverMs    float64 `json:"over_ms"`
+	Reason    string  `json:"reason,omitempty"`
+	Thread    string  `json:"thread"`
+}
+
+// GCAllocationHotspot is a function that was running in the samples before GCs
+type GCAllocationHotspot struct {
+	Name    string   `json:"name"`
+	TimeMs  float64  `json:"time_ms"`
+	GCCount int      `json:"gc_count"` // Number of GCs it preceded
+	Threads []string `json:"threads"`
+}
+
+// gcHotspotAccumulator collects a hotspot across threads and GCs
+type gcHotspotAccumulator struct {
+	GCAllocationHotspot
+	threads  map[string]bool
+	lastSeen int
+}
+
+// GCAnalysis contains garbage collection results
+type GCAnalysis struct {
+	MajorCount         int                   `json:"major_count"`
+	MinorCount         int                   `json:"minor_count"`
+	SliceCount         int                   `json:"slice_count"`
+	TotalPauseMs       float64               `json:"total_pause_ms"`
+	MaxPauseMs         float64               `json:"max_pause_ms"`
+	PauseTimePercent   float64               `json:"pause_time_percent"`
+	MMU20ms            float64               `json:"mmu_20ms"`
+	MMU50ms            float64               `json:"mmu_50ms"`
+	MMUThread          string                `json:"mmu_thread,omitempty"`
+	ReportedMMU20ms    float64               `json:"reported_mmu_20ms"` // Lowest GCMajor mmu_20ms, -1 when not reported
+	ReportedMMU50ms    float64               `json:"reported_mmu_50ms"`
+	NurseryBytes       int64                 `json:"nursery_bytes"`
+	TenuredBytes       int64                 `json:"tenured_bytes"`
+	PromotionRate      float64               `json:"promotion_rate"`
+	HeapReclaimedBytes int64                 `json:"heap_reclaimed_bytes"`
+	SlicesOverBudget   int                   `json:"slices_over_budget"`
+	Reasons            []GCReasonStats       `json:"reasons"`
+	LongestCollections []GCCollection        `json:"longest_collections"`
+	OverBudgetSlices   []GCSliceOverBudget   `json:"over_budget_slices"`
+	AllocationHotspots []GCAllocationHotspot `json:"allocation_hotspots"`
+	Recommendations    []string              `json:"recommendations,omitempty"`
+}
+
+// gcPause is a stop-the-world interval on a thread
+type gcPause struct {
+	start, end float64
+}
+
+// AnalyzeGC analyzes garbage collection from Firefox GCMajor, GCMinor and GCSlice
+// payloads and Chrome MajorGC/MinorGC events. It reports a histogram of GC reasons,
+// minimum mutator utilisation over 20ms and 50ms windows, nursery promotion rate,
+// incremental slices that overran their budget, and the functions running in the
+// samples just before each collection, which are the likeliest allocators.
+func AnalyzeGC(profile *parser.Profile, limit int) GCAnalysis {
+	analysis := GCAnalysis{
+		MMU20ms:            1,
+		MMU50ms:            1,
+		ReportedMMU20ms:    -1,
+		ReportedMMU50ms:    -1,
+		Reasons:            make([]GCReasonStats, 0),
+		LongestCollections: make([]GCCollection, 0),
+		OverBudgetSlices:   make([]GCSliceOverBudget, 0),
+		AllocationHotspots: make([]GCAllocationHotspot, 0),
+	}
+
+	if limit <= 0 {
+		limit = 10
+	}
+
+	var collections []GCCollection
+	reasons := make(map[string]*GCReasonStats)
+	hotspots := make(map[string]*gcHotspotAccumulator)
+	gcIndex := 0
+
+	for i := range profile.Threads {
+		thread := &profile.Threads[i]
+		markers := parser.ExtractMarkers(thread, profile.Meta.Categories)
+
+		// V8 pause events are only used when the trace has no MajorGC/MinorGC summaries
+		hasChromeSummary, hasFirefoxSlices := false, false
+		for _, m := range markers {
+			switch m.Name {
+			case "MajorGC", "MinorGC":
+				hasChromeSummary = true
+			case "GCSlice":
+				hasFirefoxSlices = true
+			}
+		}
+
+		var pauses []gcPause
+		var majorSlices []GCSliceOverBudget
+		for _, m := range markers {
+			data := gcPayload(m)
+			switch {
+			case m.Name == "GCMajor":
+				c := parseGCMajor(m, data, thread.Name)
+				collections = append(collections, c)
+				if v, ok := gcMMU(data["mmu_20ms"]); ok && (analysis.ReportedMMU20ms < 0 || v < analysis.ReportedMMU20ms) {
+					analysis.ReportedMMU20ms = v
+				}
+				if v, ok := gcMMU(data["mmu_50ms"]); ok && (analysis.ReportedMMU50ms < 0 || v < analysis.ReportedMMU50ms) {
+					analysis.ReportedMMU50ms = v
+				}
+				if !hasFirefoxSlices {
+					// Without GCSlice markers the slice list is the only record of the pauses
+					analysis.SliceCount += c.Slices
+					majorSlices = append(majorSlices, gcSlicesFromMajor(m, data, thread.Name)...)
+					pauses = append(pauses, gcPause{m.StartTime, m.StartTime + c.MaxPauseMs})
+				}
+
+			case m.Name == "GCMinor":
+				c := parseGCMinor(m, data, thread.Name)
+				collections = append(collections, c)
+				pauses = append(pauses, gcPause{m.StartTime, m.EndTime})
+
+			case m.Name == "GCSlice":
+				analysis.SliceCount++
+				pause := m.Duration
+				if p, ok := data["pause"].(float64); ok && p > 0 {
+					pause = p
+				}
+				pauses = append(pauses, gcPause{m.StartTime, m.StartTime + pause})
+				if budget, ok := parseGCBudget(data["budget"]); ok && pause > budget {
+					reason, _ := data["reason"].(string)
+					analysis.OverBudgetSlices = append(analysis.OverBudgetSlices, GCSliceOverBudget{
+						StartTime: m.StartTime,
+						PauseMs:   pause,
+						BudgetMs:  budget,
+						OverMs:    pause - budget,
+						Reason:    reason,
+						Thread:    thread.Name,
+					})
+				}
+
+			case m.Name == "MajorGC" || m.Name == "MinorGC",
+				!hasChromeSummary && (m.Name == "V8.GC_MARK_COMPACTOR" || m.Name == "V8.GC_SCAVENGER"):
+				c := parseV8GC(m, data, thread.Name)
+				collections = append(collections, c)
+				pauses = append(pauses, gcPause{m.StartTime, m.EndTime})
+			}
+		}
+		analysis.OverBudgetSlices = append(analysis.OverBudgetSlices, majorSlices...)
+
+		pauses = mergeGCPauses(pauses)
+		for _, p := range pauses {
+			d := p.end - p.start
+			analysis.TotalPauseMs += d
+			analysis.MaxPauseMs = math.Max(analysis.MaxPauseMs, d)
+		}
+		if len(pauses) > 0 {
+			mmu20 := minimumMutatorUtilisation(pauses, GCMMUShortWindowMs)
+			mmu50 := minimumMutatorUtilisation(pauses, GCMMULongWindowMs)
+			if mmu50 < analysis.MMU50ms || (mmu50 == analysis.MMU50ms && mmu20 < analysis.MMU20ms) {
+				analysis.MMUThread = thread.Name
+			}
+			analysis.MMU20ms = math.Min(analysis.MMU20ms, mmu20)
+			analysis.MMU50ms = math.Min(analysis.MMU50ms, mmu50)
+		}
+
+		// Functions sampled just before each pause are the likeliest allocators
+		prevEnd := math.Inf(-1)
+		for _, p := range pauses {
+			from := math.Max(prevEnd, p.start-GCPrecedingWindowMs)
+			prevEnd = p.end
+			gcIndex++
+			for _, f := range frameTopFunctions(profile, thread, from, p.start) {
+				h := hotspots[f.Name]
+				if h == nil {
+					h = &gcHotspotAccumulator{GCAllocationHotspot: GCAllocationHotspot{Name: f.Name}, threads: make(map[string]bool)}
+					hotspots[f.Name] = h
+				}
+				h.TimeMs += f.TimeMs
+				h.threads[thread.Name] = true
+				if h.lastSeen != gcIndex {
+					h.GCCount++
+					h.lastSeen = gcIndex
+				}
+			}
+		}
+	}
+
+	for _, c := range collections {
+		if c.Kind == GCKindMajor {
+			analysis.MajorCount++
+		} else {
+			analysis.MinorCount++
+		}
+		analysis.NurseryBytes += c.NurseryBytes
+		analysis.TenuredBytes += c.TenuredBytes
+		if c.HeapBeforeBytes > c.HeapAfterBytes {
+			analysis.HeapReclaimedBytes += c.HeapBeforeBytes - c.HeapAfterBytes
+		}
+
+		key := c.Kind + "\x00" + c.Reason
+		rs := reasons[key]
+		if rs == nil {
+			rs = &GCReasonStats{Reason: c.Reason, Kind: c.Kind}
+			reasons[key] = rs
+		}
+		rs.Count++
+		rs.TotalMs += c.DurationMs
+		rs.MaxMs = math.Max(rs.MaxMs, c.DurationMs)
+	}
+	if analysis.NurseryBytes > 0 {
+		analysis.PromotionRate = float64(analysis.TenuredBytes) / float64(analysis.NurseryBytes)
+	}
+	if duration := profile.DurationSeconds() * 1000; duration > 0 {
+		analysis.PauseTimePercent = analysis.TotalPauseMs / duration * 100
+	}
+
+	for _, rs := range reasons {
+		analysis.Reasons = append(analysis.Reasons, *rs)
+	}
+	sort.Slice(analysis.Reasons, func(i, j int) bool {
+		a, b := analysis.Reasons[i], analysis.Reasons[j]
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		if a.TotalMs != b.TotalMs {
+			return a.TotalMs > b.TotalMs
+		}
+		return a.Kind+a.Reason < b.Kind+b.Reason
+	})
+
+	sort.SliceStable(collections, func(i, j int) bool { return collections[i].DurationMs > collections[j].DurationMs })
+	if len(collections) > limit {
+		collections = collections[:limit]
+	}
+	analysis.LongestCollections = append(analysis.LongestCollections, collections...)
+
+	analysis.SlicesOverBudget = len(analysis.OverBudgetSlices)
+	sort.SliceStable(analysis.OverBudgetSlices, func(i, j int) bool {
+		return analysis.OverBudgetSlices[i].OverMs > analysis.OverBudgetSlices[j].OverMs
+	})
+	if len(analysis.OverBudgetSlices) > limit {
+		analysis.OverBudgetSlices = analysis.OverBudgetSlices[:limit]
+	}
+
+	for _, h := range hotspots {
+		h.Threads = make([]string, 0, len(h.threads))
+		for name := range h.threads {
+			h.Threads = append(h.Threads, name)
+		}
+		sort.Strings(h.Threads)
+		analysis.AllocationHotspots = append(analysis.AllocationHotspots, h.GCAllocationHotspot)
+	}
+	sort.Slice(analysis.AllocationHotspots, func(i, j int) bool {
+		a, b := analysis.AllocationHotspots[i], analysis.AllocationHotspots[j]
+		if a.TimeMs != b.TimeMs {
+			return a.TimeMs > b.TimeMs
+		}
+		return a.Name < b.Name
+	})
+	if len(analysis.AllocationHotspots) > limit {
+		analysis.AllocationHotspots = analysis.AllocationHotspots[:limit]
+	}
+
+	analysis.Recommendations = gcRecommendations(analysis)
+	return analysis
+}
+
+// gcPayload returns the GC details of a marker: Firefox nests them under
+// "timings" (GCMajor, GCSlice) or "nursery" (GCMinor), Chrome under "data" or the args
+func gcPayload(m parser.ParsedMarker) map[string]interface{} {
+	for _, key := range []string{"timings", "nursery"} {
+		if nested, ok := m.Data[key].(map[string]interface{}); ok {
+			return nested
+		}
+	}
+	return milestoneData(m.Data)
+}
+
+// parseGCMajor reads a Firefox GCMajor marker
+func parseGCMajor(m parser.ParsedMarker, data map[string]interface{}, threadName string) GCCollection {
+	c := GCCollection{
+		Kind:       GCKindMajor,
+		StartTime:  m.StartTime,
+		DurationMs: m.Duration,
+		Reason:     gcReason(data),
+		Thread:     threadName,
+	}
+	if v, ok := data["total_time"].(float64); ok && v > 0 {
+		c.DurationMs = v
+	}
+	if v, ok := data["zones_collected"].(float64); ok {
+		c.ZonesCollected = int(v)
+	}
+	if v, ok := data["total_zones"].(float64); ok {
+		c.TotalZones = int(v)
+	}
+	if v, ok := data["slices"].(float64); ok {
+		c.Slices = int(v)
+	}
+	if v, ok := data["max_pause"].(float64); ok {
+		c.MaxPauseMs = v
+	} else {
+		c.MaxPauseMs = c.DurationMs
+	}
+	if reason, ok := data["nonincremental_reason"].(string); ok && reason != gcNonincrementalNone {
+		c.NonincrementalReason = reason
+	}
+	if v, ok := data["pre_heap_size"].(float64); ok {
+		c.HeapBeforeBytes = int64(v)
+	}
+	if v, ok := data["post_heap_size"].(float64); ok {
+		c.HeapAfterBytes = int64(v)
+	}
+	return c
+}
+
+// parseGCMinor reads a Firefox GCMinor marker and its nursery promotion
+func parseGCMinor(m parser.ParsedMarker, data map[string]interface{}, threadName string) GCCollection {
+	c := GCCollection{
+		Kind:       GCKindMinor,
+		StartTime:  m.StartTime,
+		DurationMs: m.Duration,
+		Reason:     gcReason(data),
+		Thread:     threadName,
+	}
+	if v, ok := data["bytes_used"].(float64); ok {
+		c.NurseryBytes = int64(v)
+	}
+	if v, ok := data["bytes_tenured"].(float64); ok {
+		c.TenuredBytes = int64(v)
+	}
+	if c.NurseryBytes > 0 {
+		c.PromotionRate = float64(c.TenuredBytes) / float64(c.NurseryBytes)
+	}
+	return c
+}
+
+// parseV8GC reads a Chrome MajorGC/MinorGC event and its heap sizes
+func parseV8GC(m parser.ParsedMarker, data map[string]interface{}, threadName string) GCCollection {
+	kind := GCKindMinor
+	if m.Name == "MajorGC" || m.Name == "V8.GC_MARK_COMPACTOR" {
+		kind = GCKindMajor
+	}
+	c := GCCollection{
+		Kind:       kind,
+		StartTime:  m.StartTime,
+		DurationMs: m.Duration,
+		MaxPauseMs: m.Duration,
+		Reason:     gcUnknownReason,
+		Thread:     threadName,
+	}
+	if reason, ok := data["type"].(string); ok && reason != "" {
+		c.Reason = reason
+	}
+	if v, ok := data["usedHeapSizeBefore"].(float64); ok {
+		c.HeapBeforeBytes = int64(v)
+	}
+	if v, ok := data["usedHeapSizeAfter"].(float64); ok {
+		c.HeapAfterBytes = int64(v)
+	}
+	return c
+}
+
+// gcSlicesFromMajor returns the over-budget slices listed in a GCMajor payload.
+// Slice timestamps use a different clock, so they are reported at the GC's start.
+func gcSlicesFromMajor(m parser.ParsedMarker, data map[string]interface{}, threadName string) []GCSliceOverBudget {
+	list, _ := data["slices_list"].([]interface{})
+	var over []GCSliceOverBudget
+	for _, item := range list {
+		slice, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		pause, _ := slice["pause"].(float64)
+		budget, ok := parseGCBudget(slice["budget"])
+		if !ok || pause <= budget {
+			continue
+		}
+		reason, _ := slice["reason"].(string)
+		over = append(over, GCSliceOverBudget{
+			StartTime: m.StartTime,
+			PauseMs:   pause,
+			BudgetMs:  budget,
+			OverMs:    pause - budget,
+			Reason:    reason,
+			Thread:    threadName,
+		})
+	}
+	return over
+}
+
+// gcReason returns the trigger reason of a GC payload
+func gcReason(data map[string]interface{}) string {
+	if reason, ok := data["reason"].(string); ok && reason != "" {
+		return reason
+	}
+	return gcUnknownReason
+}
+
+// gcMMU reads a Firefox GCMajor MMU, which is always a percentage, as a fraction
+func gcMMU(v interface{}) (float64, bool) {
+	f, ok := v.(float64)
+	if !ok || f < 0 {
+		return 0, false
+	}
+	return f / 100, true
+}
+
+// parseGCBudget reads a slice budget such as "10ms" or 10; "unlimited" and work budgets have no time limit
+func parseGCBudget(v interface{}) (float64, bool) {
+	switch b := v.(type) {
+	case float64:
+		return b, b > 0
+	case string:
+		s := strings.TrimSpace(b)
+		if !strings.HasSuffix(s, "ms") {
+			return 0, false
+		}
+		ms, err := strconv.ParseFloat(strings.TrimSpace(strings.TrimSuffix(s, "ms")), 64)
+		if err != nil || ms <= 0 {
+			return 0, false
+		}
+		return ms, true
+	}
+	return 0, false
+}
+
+// mergeGCPauses sorts pauses and merges overlapping ones
+func mergeGCPauses(pauses []gcPause) []gcPause {
+	if len(pauses) == 0 {
+		return pauses
+	}
+	sort.Slice(pauses, func(i, j int) bool { return pauses[i].start < pauses[j].start })
+	merged := []gcPause{pauses[0]}
+	for _, p := range pauses[1:] {
+		last := &merged[len(merged)-1]
+		if p.start <= last.end {
+			last.end = math.Max(last.end, p.end)
+			continue
+		}
+		merged = append(merged, p)
+	}
+	return merged
+}
+
+// minimumMutatorUtilisation returns the smallest fraction of any window of the
+// given size left to the program by the merged, sorted GC pauses. The worst
+// window always starts at a pause start or ends at a pause end.
+func minimumMutatorUtilisation(pauses []gcPause, window float64) float64 {
+	paused := func(from, to float64) float64 {
+		total := 0.0
+		for _, p := range pauses {
+			if p.start >= to {
+				break
+			}
+			total += math.Max(0, math.Min(p.end, to)-math.Max(p.start, from))
+		}
+		return total
+	}
+	mmu := 1.0
+	for _, p := range pauses {
+		mmu = math.Min(mmu, 1-paused(p.start, p.start+window)/window)
+		mmu = math.Min(mmu, 1-paused(p.end-window, p.end)/window)
+	}
+	return math.Max(0, mmu)
+}
+
+// gcRecommendations suggests follow-ups based on GC results
+func gcRecommendations(analysis GCAnalysis) []string {
+	var recs []string
+	if analysis.MajorCount+analysis.MinorCount+analysis.SliceCount == 0 {
+		return append(recs, "No GC markers were found; Firefox profiles need GC markers and Chrome traces the disabled-by-default-v8.gc category")
+	}
+
+	if analysis.MMU50ms < GCMMULow {
+		recs = append(recs, fmt.Sprintf("Minimum mutator utilisation over 50ms windows is %.0f%% on %s; GC pauses leave too little time for frames and input",
+			analysis.MMU50ms*100, analysis.MMUThread))
+	}
+	if analysis.MaxPauseMs > GCPauseLongMs {
+		recs = append(recs, fmt.Sprintf("The longest GC pause is %.0fms; look for non-incremental collections and large heaps", analysis.MaxPauseMs))
+	}
+	if analysis.PromotionRate > GCPromotionRateHigh {
+		recs = append(recs, fmt.Sprintf("%.0f%% of nursery bytes are promoted to the tenured heap; short-lived objects are being kept alive too long (caches, closures, pending promises)",
+			analysis.PromotionRate*100))
+	}
+	if analysis.SlicesOverBudget > 0 {
+		recs = append(recs, fmt.Sprintf("%d incremental GC slices exceeded their budget, worst by %.1fms",
+			analysis.SlicesOverBudget, analysis.OverBudgetSlices[0].OverMs))
+	}
+	for _, c := range analysis.LongestCollections {
+		if c.NonincrementalReason != "" {
+			recs = append(recs, fmt.Sprintf("A major GC at %.0fms ran non-incrementally (%s)", c.StartTime, c.NonincrementalReason))
+			break
+		}
+	}
+	if len(analysis.Reasons) > 0 && analysis.Reasons[0].Reason != gcUnknownReason && analysis.Reasons[0].Count > 1 {
+		r := analysis.Reasons[0]
+		recs = append(recs, fmt.Sprintf("Most collections are %s GCs triggered by %s (%d)", r.Kind, r.Reason, r.Count))
+	}
+	if len(analysis.AllocationHotspots) > 0 {
+		h := analysis.AllocationHotspots[0]
+		recs = append(recs, fmt.Sprintf("%s was running before %d GCs; reduce its allocations or reuse objects", h.Name, h.GCCount))
+	}
+
+	return recs
+}
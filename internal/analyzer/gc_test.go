@@ -0,0 +1,188 @@
+package analyzer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/CedricHerzog/perfowl/internal/testutil"
+)
+
+func TestAnalyzeGC_FirefoxPayloads(t *testing.T) {
+	analysis := AnalyzeGC(testutil.ProfileWithGCPayloads(), 10)
+
+	if analysis.MajorCount != 1 || analysis.MinorCount != 2 || analysis.SliceCount != 2 {
+		t.Errorf("counts = %d major, %d minor, %d slices; want 1, 2, 2", analysis.MajorCount, analysis.MinorCount, analysis.SliceCount)
+	}
+	testutil.AssertFloatApproxEqual(t, analysis.TotalPauseMs, 65, 0.01)
+	testutil.AssertFloatApproxEqual(t, analysis.MaxPauseMs, 40, 0.01)
+	testutil.AssertFloatApproxEqual(t, analysis.PromotionRate, 0.4, 0.001)
+	testutil.AssertFloatApproxEqual(t, analysis.ReportedMMU20ms, 0.1, 0.001)
+	testutil.AssertFloatApproxEqual(t, analysis.ReportedMMU50ms, 0.2, 0.001)
+
+	if len(analysis.LongestCollections) != 3 || analysis.LongestCollections[0].Kind != GCKindMajor {
+		t.Fatalf("expected the major GC to be the longest of 3 collections, got %+v", analysis.LongestCollections)
+	}
+	major := analysis.LongestCollections[0]
+	if major.Reason != "ALLOC_TRIGGER" || major.ZonesCollected != 2 || major.TotalZones != 3 || major.NonincrementalReason != "" {
+		t.Errorf("major GC = %+v", major)
+	}
+	testutil.AssertFloatApproxEqual(t, major.DurationMs, 55, 0.01)
+}
+
+func TestAnalyzeGC_ReportedMMUPercentages(t *testing.T) {
+	// Firefox reports MMU as a percentage even below 1%, the worst pauses
+	markers, strs := testutil.NewMarkerBuilder().
+		AddCustom("GCMajor", 6, 100, 50, map[string]interface{}{
+			"type": "GCMajor",
+			"timings": map[string]interface{}{
+				"status":   "completed",
+				"reason":   "ALLOC_TRIGGER",
+				"mmu_20ms": 1.0,
+				"mmu_50ms": 0.5,
+			},
+		}).
+		Build()
+
+	profile := testutil.NewProfileBuilder().
+		WithCategories(testutil.DefaultCategories()).
+		WithThread(testutil.NewThreadBuilder("GeckoMain").
+			AsMainThread().
+			WithStringArray(strs).
+			WithMarkers(markers).
+			Build()).
+		Build()
+
+	analysis := AnalyzeGC(profile, 10)
+
+	testutil.AssertFloatApproxEqual(t, analysis.ReportedMMU20ms, 0.01, 0.0001)
+	testutil.AssertFloatApproxEqual(t, analysis.ReportedMMU50ms, 0.005, 0.0001)
+}
+
+func TestAnalyzeGC_Reasons(t *testing.T) {
+	analysis := AnalyzeGC(testutil.ProfileWithGCPayloads(), 10)
+
+	if len(analysis.Reasons) != 2 {
+		t.Fatalf("expected 2 reasons, got %+v", analysis.Reasons)
+	}
+	top := analysis.Reasons[0]
+	if top.Reason != "OUT_OF_NURSERY" || top.Kind != GCKindMinor || top.Count != 2 {
+		t.Errorf("top reason = %+v, want 2 minor OUT_OF_NURSERY", top)
+	}
+	testutil.AssertFloatApproxEqual(t, top.TotalMs, 10, 0.01)
+}
+
+func TestAnalyzeGC_MinimumMutatorUtilisation(t *testing.T) {
+	analysis := AnalyzeGC(testutil.ProfileWithGCPayloads(), 10)
+
+	// The 40ms slice fills a whole 20ms window and 80% of a 50ms window
+	testutil.AssertFloatApproxEqual(t, analysis.MMU20ms, 0, 0.001)
+	testutil.AssertFloatApproxEqual(t, analysis.MMU50ms, 0.2, 0.001)
+	if analysis.MMUThread != "GeckoMain" {
+		t.Errorf("MMUThread = %s, want GeckoMain", analysis.MMUThread)
+	}
+}
+
+func TestAnalyzeGC_SlicesOverBudget(t *testing.T) {
+	analysis := AnalyzeGC(testutil.ProfileWithGCPayloads(), 10)
+
+	if analysis.SlicesOverBudget != 2 {
+		t.Fatalf("expected 2 slices over budget, got %d", analysis.SlicesOverBudget)
+	}
+	worst := analysis.OverBudgetSlices[0]
+	if worst.Reason != "INTER_SLICE_GC" {
+		t.Errorf("worst slice reason = %s, want INTER_SLICE_GC", worst.Reason)
+	}
+	testutil.AssertFloatApproxEqual(t, worst.OverMs, 30, 0.01)
+	testutil.AssertFloatApproxEqual(t, worst.BudgetMs, 10, 0.01)
+}
+
+func TestAnalyzeGC_AllocationHotspots(t *testing.T) {
+	analysis := AnalyzeGC(testutil.ProfileWithGCPayloads(), 10)
+
+	if len(analysis.AllocationHotspots) != 2 {
+		t.Fatalf("expected 2 hotspots, got %+v", analysis.AllocationHotspots)
+	}
+	top := analysis.AllocationHotspots[0]
+	if top.Name != "allocateNodes" || top.GCCount != 3 {
+		t.Errorf("top hotspot = %+v, want allocateNodes before 3 GCs", top)
+	}
+	testutil.AssertFloatApproxEqual(t, top.TimeMs, 12, 0.01)
+	if len(top.Threads) != 1 || top.Threads[0] != "GeckoMain" {
+		t.Errorf("hotspot threads = %v, want [GeckoMain]", top.Threads)
+	}
+}
+
+func TestAnalyzeGC_Recommendations(t *testing.T) {
+	analysis := AnalyzeGC(testutil.ProfileWithGCPayloads(), 10)
+
+	joined := strings.Join(analysis.Recommendations, "\n")
+	for _, want := range []string{"Minimum mutator utilisation", "40% of nursery bytes", "2 incremental GC slices", "allocateNodes"} {
+		if !strings.Contains(joined, want) {
+			t.Errorf("expected recommendation containing %q, got %v", want, analysis.Recommendations)
+		}
+	}
+}
+
+func TestAnalyzeGC_ChromeHeapSizes(t *testing.T) {
+	heap := func(before, after float64) map[string]interface{} {
+		return map[string]interface{}{"usedHeapSizeBefore": before, "usedHeapSizeAfter": after}
+	}
+	markers, strs := testutil.NewMarkerBuilder().
+		AddCustom("MajorGC", 6, 100, 30, heap(10000000, 4000000)).
+		AddCustom("V8.GC_MARK_COMPACTOR", 6, 100, 25, nil).
+		AddCustom("MinorGC", 6, 300, 2, heap(5000000, 4500000)).
+		Build()
+
+	profile := testutil.NewProfileBuilder().
+		WithCategories(testutil.DefaultCategories()).
+		WithThread(testutil.NewThreadBuilder("CrRendererMain").
+			AsMainThread().
+			WithStringArray(strs).
+			WithMarkers(markers).
+			Build()).
+		Build()
+
+	analysis := AnalyzeGC(profile, 10)
+
+	if analysis.MajorCount != 1 || analysis.MinorCount != 1 {
+		t.Errorf("counts = %d major, %d minor; V8 pause events should not double count", analysis.MajorCount, analysis.MinorCount)
+	}
+	if analysis.HeapReclaimedBytes != 6500000 {
+		t.Errorf("HeapReclaimedBytes = %d, want 6500000", analysis.HeapReclaimedBytes)
+	}
+	if analysis.ReportedMMU50ms != -1 {
+		t.Errorf("ReportedMMU50ms = %f, want -1 without Firefox payloads", analysis.ReportedMMU50ms)
+	}
+}
+
+func TestAnalyzeGC_NoGC(t *testing.T) {
+	analysis := AnalyzeGC(testutil.MinimalProfile(), 10)
+
+	if analysis.MajorCount+analysis.MinorCount != 0 || analysis.MMU50ms != 1 {
+		t.Errorf("expected no GCs and full utilisation, got %+v", analysis)
+	}
+	if len(analysis.Recommendations) != 1 {
+		t.Errorf("expected a recommendation about missing GC markers, got %v", analysis.Recommendations)
+	}
+}
+
+func TestParseGCBudget(t *testing.T) {
+	tests := []struct {
+		in   interface{}
+		want float64
+		ok   bool
+	}{
+		{"10ms", 10, true},
+		{" 5ms", 5, true},
+		{"unlimited", 0, false},
+		{"work(100)", 0, false},
+		{4.0, 4, true},
+		{nil, 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := parseGCBudget(tt.in)
+		if ok != tt.ok || got != tt.want {
+			t.Errorf("parseGCBudget(%v) = %v, %v; want %v, %v", tt.in, got, ok, tt.want, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,471 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/CedricHerzog/perfowl/internal/parser"
+)
+
+// Interaction latency thresholds, matching the Interaction to Next Paint ratings
+const (
+	InteractionGoodMs           = 200.0
+	InteractionPoorMs           = 500.0
+	InteractionPresentWindowMs  = 1000.0 // Paints later than this after the handlers are not attributed
+	InteractionGroupWindowMs    = 100.0  // Events of one input family this close together form one interaction
+	EventDelayJankMs            = 50.0   // Event loop delays above this are noticeable to the user
+	interactionOutlierBudgetPer = 50     // INP ignores one outlier per 50 interactions
+)
+
+// interactionEventFamilies maps user input event types to the interaction they belong to
+var interactionEventFamilies = map[string]string{
+	"pointerdown": "pointer",
+	"pointerup":   "pointer",
+	"mousedown":   "pointer",
+	"mouseup":     "pointer",
+	"click":       "pointer",
+	"auxclick":    "pointer",
+	"contextmenu": "pointer",
+	"touchstart":  "pointer",
+	"touchend":    "pointer",
+	"keydown":     "keyboard",
+	"keypress":    "keyboard",
+	"keyup":       "keyboard",
+}
+
+// presentationMarkers lists the markers that put an interaction's result on screen
+var presentationMarkers = map[string]bool{
+	"Composite": true, // Firefox compositor frame
+	"DrawFrame": true, // Chrome compositor frame
+	"Paint":     true, // Paint on the main thread when no compositor frames are recorded
+}
+
+// InteractionEvent is one input event handled as part of an interaction
+type InteractionEvent struct {
+	Type       string  `json:"type"`
+	StartTime  float64 `json:"start_time"`
+	DurationMs float64 `json:"duration_ms"`
+}
+
+// Interaction is a user input and the time until its result was painted
+type Interaction struct {
+	Type                string             `json:"type"`
+	Thread              string             `json:"thread"`
+	StartTime           float64            `json:"start_time"`
+	LatencyMs           float64            `json:"latency_ms"`
+	InputDelayMs        float64            `json:"input_delay_ms"`
+	InputDelaySource    string             `json:"input_delay_source"` // "timestamp", "event_delay" or "none"
+	ProcessingMs        float64            `json:"processing_ms"`
+	PresentationDelayMs float64            `json:"presentation_delay_ms"`
+	PresentedBy         string             `json:"presented_by,omitempty"`
+	Events              []InteractionEvent `json:"events"`
+	TopFunctions        []FrameFunction    `json:"top_functions,omitempty"`
+
+	thread *parser.Thread // Thread the input events ran on; names are not unique across processes
+}
+
+// InteractionTypeStats summarizes interactions started by one event type
+type InteractionTypeStats struct {
+	Type         string  `json:"type"`
+	Count        int     `json:"count"`
+	P50LatencyMs float64 `json:"p50_latency_ms"`
+	MaxLatencyMs float64 `json:"max_latency_ms"`
+}
+
+// EventDelayStats summarizes the sampled event loop delay of a thread
+type EventDelayStats struct {
+	Thread      string  `json:"thread"`
+	MeanMs      float64 `json:"mean_ms"`
+	P95Ms       float64 `json:"p95_ms"`
+	MaxMs       float64 `json:"max_ms"`
+	SamplesOver int     `json:"samples_over_threshold"`
+	ThresholdMs float64 `json:"threshold_ms"`
+}
+
+// InteractionAnalysis contains input responsiveness results
+type InteractionAnalysis struct {
+	TotalInteractions    int                    `json:"total_interactions"`
+	INPMs                float64                `json:"inp_ms"`
+	INPRating            string                 `json:"inp_rating"`
+	P50LatencyMs         float64                `json:"p50_latency_ms"`
+	P75LatencyMs         float64                `json:"p75_latency_ms"`
+	MaxLatencyMs         float64                `json:"max_latency_ms"`
+	AvgInputDelayMs      float64                `json:"avg_input_delay_ms"`
+	AvgProcessingMs      float64                `json:"avg_processing_ms"`
+	AvgPresentationMs    float64                `json:"avg_presentation_delay_ms"`
+	SlowInteractions     int                    `json:"slow_interactions"`
+	UnpresentedCount     int                    `json:"unpresented_count"`
+	InputDelayTimestamps int                    `json:"input_delay_timestamps"`
+	ByType               []InteractionTypeStats `json:"by_type"`
+	EventDelay           []EventDelayStats      `json:"event_delay,omitempty"`
+	WorstInteractions    []Interaction          `json:"worst_interactions"`
+	Recommendations      []string               `json:"recommendations,omitempty"`
+}
+
+// AnalyzeInteractions measures the latency of clicks, taps and key presses from
+// DOM event markers: input delay until the handlers start, processing time in the
+// handlers and presentation delay until the next paint or composite. Related
+// events such as pointerdown, pointerup and click are grouped into one interaction.
+func AnalyzeInteractions(profile *parser.Profile, limit int) InteractionAnalysis {
+	analysis := InteractionAnalysis{
+		ByType:            make([]InteractionTypeStats, 0),
+		WorstInteractions: make([]Interaction, 0),
+	}
+
+	if limit <= 0 {
+		limit = 10
+	}
+
+	threadMarkers := make([][]parser.ParsedMarker, len(profile.Threads))
+	for i := range profile.Threads {
+		threadMarkers[i] = parser.ExtractMarkers(&profile.Threads[i], profile.Meta.Categories)
+	}
+
+	// Presentation times per process, with a profile-wide fallback for
+	// compositors that live in a separate GPU process
+	presentByPID := make(map[json.Number][]presentation)
+	var presentAll []presentation
+	for i, markers := range threadMarkers {
+		for _, m := range markers {
+			if !presentationMarkers[m.Name] {
+				continue
+			}
+			p := presentation{name: m.Name, end: math.Max(m.EndTime, m.StartTime)}
+			pid := profile.Threads[i].PID
+			presentByPID[pid] = append(presentByPID[pid], p)
+			presentAll = append(presentAll, p)
+		}
+	}
+	for pid := range presentByPID {
+		sortPresentations(presentByPID[pid])
+	}
+	sortPresentations(presentAll)
+
+	var interactions []Interaction
+	for ti := range profile.Threads {
+		thread := &profile.Threads[ti]
+		events := interactionMarkers(threadMarkers[ti])
+		if len(events) == 0 {
+			continue
+		}
+		presents := presentByPID[thread.PID]
+		if len(presents) == 0 {
+			presents = presentAll
+		}
+		for _, group := range groupInteractionEvents(events) {
+			interactions = append(interactions, buildInteraction(profile, thread, group, presents))
+		}
+	}
+
+	analysis.EventDelay = eventDelayStats(profile)
+
+	if len(interactions) == 0 {
+		analysis.INPRating = "n/a"
+		analysis.Recommendations = interactionRecommendations(analysis)
+		return analysis
+	}
+
+	latencies := make([]float64, len(interactions))
+	byType := make(map[string][]float64)
+	for i, in := range interactions {
+		latencies[i] = in.LatencyMs
+		byType[in.Type] = append(byType[in.Type], in.LatencyMs)
+		analysis.AvgInputDelayMs += in.InputDelayMs
+		analysis.AvgProcessingMs += in.ProcessingMs
+		analysis.AvgPresentationMs += in.PresentationDelayMs
+		if in.LatencyMs > InteractionGoodMs {
+			analysis.SlowInteractions++
+		}
+		if in.PresentedBy == "" {
+			analysis.UnpresentedCount++
+		}
+		if in.InputDelaySource == "timestamp" {
+			analysis.InputDelayTimestamps++
+		}
+	}
+	n := float64(len(interactions))
+	analysis.TotalInteractions = len(interactions)
+	analysis.AvgInputDelayMs /= n
+	analysis.AvgProcessingMs /= n
+	analysis.AvgPresentationMs /= n
+
+	sort.Float64s(latencies)
+	analysis.INPMs = interactionToNextPaint(latencies)
+	analysis.INPRating = rateInteractionLatency(analysis.INPMs)
+	analysis.P50LatencyMs = percentile(latencies, 50)
+	analysis.P75LatencyMs = percentile(latencies, 75)
+	analysis.MaxLatencyMs = latencies[len(latencies)-1]
+
+	for typ, values := range byType {
+		sort.Float64s(values)
+		analysis.ByType = append(analysis.ByType, InteractionTypeStats{
+			Type:         typ,
+			Count:        len(values),
+			P50LatencyMs: percentile(values, 50),
+			MaxLatencyMs: values[len(values)-1],
+		})
+	}
+	sort.Slice(analysis.ByType, func(i, j int) bool {
+		if analysis.ByType[i].Count != analysis.ByType[j].Count {
+			return analysis.ByType[i].Count > analysis.ByType[j].Count
+		}
+		return analysis.ByType[i].Type < analysis.ByType[j].Type
+	})
+
+	sort.SliceStable(interactions, func(i, j int) bool {
+		if interactions[i].LatencyMs != interactions[j].LatencyMs {
+			return interactions[i].LatencyMs > interactions[j].LatencyMs
+		}
+		return interactions[i].StartTime < interactions[j].StartTime
+	})
+	if len(interactions) > limit {
+		interactions = interactions[:limit]
+	}
+
+	// Only the reported interactions get their functions attributed
+	for i := range interactions {
+		in := &interactions[i]
+		in.TopFunctions = frameTopFunctions(profile, in.thread, in.StartTime, in.StartTime+in.LatencyMs)
+	}
+	analysis.WorstInteractions = interactions
+	analysis.Recommendations = interactionRecommendations(analysis)
+
+	return analysis
+}
+
+// presentation is a paint or composite that can present an interaction
+type presentation struct {
+	name string
+	end  float64
+}
+
+// sortPresentations orders presentations by the time they reach the screen
+func sortPresentations(p []presentation) {
+	sort.Slice(p, func(i, j int) bool { return p[i].end < p[j].end })
+}
+
+// interactionEvent is a user input event marker on one thread
+type interactionEvent struct {
+	typ       string
+	family    string
+	start     float64
+	end       float64
+	timeStamp float64 // Event creation time when recorded, otherwise zero
+}
+
+// interactionMarkers returns the user input event markers in start order
+func interactionMarkers(markers []parser.ParsedMarker) []interactionEvent {
+	var events []interactionEvent
+	for _, m := range markers {
+		typ := interactionEventType(m)
+		family, ok := interactionEventFamilies[typ]
+		if !ok {
+			continue
+		}
+		ev := interactionEvent{
+			typ:    typ,
+			family: family,
+			start:  m.StartTime,
+			end:    math.Max(m.EndTime, m.StartTime),
+		}
+		if ts, ok := m.Data["timeStamp"].(float64); ok && ts > 0 && ts <= m.StartTime {
+			ev.timeStamp = ts
+		}
+		events = append(events, ev)
+	}
+	sort.SliceStable(events, func(i, j int) bool { return events[i].start < events[j].start })
+	return events
+}
+
+// interactionEventType returns the DOM event type of a Firefox DOMEvent or Chrome EventDispatch marker
+func interactionEventType(m parser.ParsedMarker) string {
+	if m.Data == nil {
+		return ""
+	}
+	if t, ok := m.Data["eventType"].(string); ok {
+		return t
+	}
+	if m.Name != "EventDispatch" {
+		return ""
+	}
+	if t, ok := m.Data["type"].(string); ok {
+		return t
+	}
+	if data, ok := m.Data["data"].(map[string]interface{}); ok {
+		if t, ok := data["type"].(string); ok {
+			return t
+		}
+	}
+	return ""
+}
+
+// groupInteractionEvents merges events of the same input family that start close together
+func groupInteractionEvents(events []interactionEvent) [][]interactionEvent {
+	var groups [][]interactionEvent
+	for _, ev := range events {
+		if n := len(groups); n > 0 {
+			last := groups[n-1]
+			first := last[0]
+			if first.family == ev.family && ev.start-first.start <= InteractionGroupWindowMs && !startsNewInteraction(last, ev) {
+				groups[n-1] = append(last, ev)
+				continue
+			}
+		}
+		groups = append(groups, []interactionEvent{ev})
+	}
+	return groups
+}
+
+// startsNewInteraction reports whether ev repeats an event type already in the group
+func startsNewInteraction(group []interactionEvent, ev interactionEvent) bool {
+	for _, g := range group {
+		if g.typ == ev.typ {
+			return true
+		}
+	}
+	return false
+}
+
+// buildInteraction splits one group of events into input delay, processing and presentation delay
+func buildInteraction(profile *parser.Profile, thread *parser.Thread, group []interactionEvent, presents []presentation) Interaction {
+	in := Interaction{
+		Type:             group[0].typ,
+		Thread:           thread.Name,
+		InputDelaySource: "none",
+		thread:           thread,
+		Events:           make([]InteractionEvent, 0, len(group)),
+	}
+
+	handlerStart := group[0].start
+	handlerEnd := group[0].end
+	for _, ev := range group {
+		in.Events = append(in.Events, InteractionEvent{Type: ev.typ, StartTime: ev.start, DurationMs: ev.end - ev.start})
+		handlerEnd = math.Max(handlerEnd, ev.end)
+	}
+	in.ProcessingMs = handlerEnd - handlerStart
+
+	// Input delay: the recorded event timestamp, or the sampled event loop delay
+	// just before the first handler ran
+	in.StartTime = handlerStart
+	if group[0].timeStamp > 0 {
+		in.InputDelayMs = handlerStart - group[0].timeStamp
+		in.StartTime = group[0].timeStamp
+		in.InputDelaySource = "timestamp"
+	} else if delay, ok := sampledEventDelay(&thread.Samples, handlerStart); ok {
+		in.InputDelayMs = delay
+		in.StartTime = handlerStart - delay
+		in.InputDelaySource = "event_delay"
+	}
+
+	// Presentation: the first paint or composite that finishes after the handlers
+	end := handlerEnd
+	idx := sort.Search(len(presents), func(i int) bool { return presents[i].end >= handlerEnd })
+	if idx < len(presents) && presents[idx].end-handlerEnd <= InteractionPresentWindowMs {
+		in.PresentedBy = presents[idx].name
+		end = presents[idx].end
+	}
+	in.PresentationDelayMs = end - handlerEnd
+	in.LatencyMs = end - in.StartTime
+	return in
+}
+
+// sampledEventDelay returns the event delay of the last sample at or before t
+func sampledEventDelay(samples *parser.Samples, t float64) (float64, bool) {
+	if len(samples.EventDelay) == 0 {
+		return 0, false
+	}
+	best := -1
+	for i := 0; i < samples.Length && i < len(samples.Time) && i < len(samples.EventDelay); i++ {
+		if samples.Time[i] > t {
+			break
+		}
+		best = i
+	}
+	if best < 0 {
+		return 0, false
+	}
+	return samples.EventDelay[best], true
+}
+
+// eventDelayStats summarizes the event delay column of every thread that records one
+func eventDelayStats(profile *parser.Profile) []EventDelayStats {
+	var stats []EventDelayStats
+	for ti := range profile.Threads {
+		thread := &profile.Threads[ti]
+		delays := thread.Samples.EventDelay
+		if len(delays) == 0 {
+			continue
+		}
+		sorted := make([]float64, len(delays))
+		copy(sorted, delays)
+		sort.Float64s(sorted)
+
+		s := EventDelayStats{Thread: thread.Name, ThresholdMs: EventDelayJankMs, MaxMs: sorted[len(sorted)-1]}
+		for _, d := range sorted {
+			s.MeanMs += d
+			if d > EventDelayJankMs {
+				s.SamplesOver++
+			}
+		}
+		s.MeanMs /= float64(len(sorted))
+		s.P95Ms = percentile(sorted, 95)
+		stats = append(stats, s)
+	}
+	sort.SliceStable(stats, func(i, j int) bool { return stats[i].MaxMs > stats[j].MaxMs })
+	return stats
+}
+
+// interactionToNextPaint returns the INP of sorted latencies: the worst
+// interaction after ignoring one outlier for every 50 interactions
+func interactionToNextPaint(sorted []float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	skip := len(sorted) / interactionOutlierBudgetPer
+	return sorted[len(sorted)-1-skip]
+}
+
+// rateInteractionLatency rates an INP value as good, needs-improvement or poor
+func rateInteractionLatency(ms float64) string {
+	switch {
+	case ms <= InteractionGoodMs:
+		return "good"
+	case ms <= InteractionPoorMs:
+		return "needs-improvement"
+	default:
+		return "poor"
+	}
+}
+
+// interactionRecommendations suggests follow-ups based on interaction results
+func interactionRecommendations(analysis InteractionAnalysis) []string {
+	var recs []string
+	if analysis.TotalInteractions == 0 {
+		recs = append(recs, "No click, tap or key events were recorded; interact with the page while profiling")
+	}
+	if analysis.INPRating == "poor" || analysis.INPRating == "needs-improvement" {
+		recs = append(recs, fmt.Sprintf("INP is %.0fms (%s); look at the functions listed for the worst interactions", analysis.INPMs, analysis.INPRating))
+	}
+	if analysis.TotalInteractions > 0 {
+		switch {
+		case analysis.AvgInputDelayMs >= analysis.AvgProcessingMs && analysis.AvgInputDelayMs >= analysis.AvgPresentationMs && analysis.AvgInputDelayMs > 50:
+			recs = append(recs, "Input delay dominates; long tasks were blocking the main thread when users interacted, so break them up or yield more often")
+		case analysis.AvgProcessingMs >= analysis.AvgPresentationMs && analysis.AvgProcessingMs > 50:
+			recs = append(recs, "Event handlers dominate; defer non-urgent work out of input handlers")
+		case analysis.AvgPresentationMs > 50:
+			recs = append(recs, "Presentation delay dominates; reduce the style, layout and paint work triggered by interactions")
+		}
+	}
+	if analysis.UnpresentedCount > 0 && analysis.UnpresentedCount == analysis.TotalInteractions {
+		recs = append(recs, "No paint or composite markers followed the interactions; latency only covers input delay and processing")
+	}
+	for _, ed := range analysis.EventDelay {
+		if ed.MaxMs > EventDelayJankMs {
+			recs = append(recs, fmt.Sprintf("Event delay on %s reached %.0fms; input arriving then would have waited that long", ed.Thread, ed.MaxMs))
+			break
+		}
+	}
+	return recs
+}
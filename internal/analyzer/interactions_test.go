@@ -0,0 +1,175 @@
+package analyzer
+
+import (
+	"testing"
+
+	"github.com/CedricHerzog/perfowl/internal/parser"
+	"github.com/CedricHerzog/perfowl/internal/testutil"
+)
+
+func TestAnalyzeInteractions_Phases(t *testing.T) {
+	analysis := AnalyzeInteractions(testutil.ProfileWithInteractions(), 10)
+
+	if analysis.TotalInteractions != 3 {
+		t.Fatalf("TotalInteractions = %d, want 3", analysis.TotalInteractions)
+	}
+	if analysis.UnpresentedCount != 1 {
+		t.Errorf("UnpresentedCount = %d, want 1", analysis.UnpresentedCount)
+	}
+	if analysis.InputDelayTimestamps != 1 {
+		t.Errorf("InputDelayTimestamps = %d, want 1", analysis.InputDelayTimestamps)
+	}
+
+	var pointer *Interaction
+	for i := range analysis.WorstInteractions {
+		if analysis.WorstInteractions[i].Type == "pointerdown" {
+			pointer = &analysis.WorstInteractions[i]
+		}
+	}
+	if pointer == nil {
+		t.Fatal("expected the pointerdown/pointerup/click events to form one interaction")
+	}
+	if len(pointer.Events) != 3 {
+		t.Errorf("Events = %d, want 3", len(pointer.Events))
+	}
+	if pointer.InputDelaySource != "timestamp" || pointer.PresentedBy != "Composite" {
+		t.Errorf("source/presented = %s/%s, want timestamp/Composite", pointer.InputDelaySource, pointer.PresentedBy)
+	}
+	testutil.AssertFloatApproxEqual(t, pointer.InputDelayMs, 10, 0.01)
+	testutil.AssertFloatApproxEqual(t, pointer.ProcessingMs, 100, 0.01)
+	testutil.AssertFloatApproxEqual(t, pointer.PresentationDelayMs, 16, 0.01)
+	testutil.AssertFloatApproxEqual(t, pointer.LatencyMs, 126, 0.01)
+}
+
+func TestAnalyzeInteractions_EventDelayAndINP(t *testing.T) {
+	analysis := AnalyzeInteractions(testutil.ProfileWithInteractions(), 10)
+
+	worst := analysis.WorstInteractions[0]
+	if worst.Type != "keydown" || worst.InputDelaySource != "event_delay" {
+		t.Fatalf("worst = %s (%s), want keydown from event_delay", worst.Type, worst.InputDelaySource)
+	}
+	testutil.AssertFloatApproxEqual(t, worst.InputDelayMs, 80, 0.01)
+	testutil.AssertFloatApproxEqual(t, worst.LatencyMs, 390, 0.01)
+	if len(worst.TopFunctions) == 0 || worst.TopFunctions[0].Name != "slowKeyHandler" {
+		t.Errorf("TopFunctions = %v, want slowKeyHandler", worst.TopFunctions)
+	}
+
+	// Fewer than 50 interactions: INP is the worst one
+	testutil.AssertFloatApproxEqual(t, analysis.INPMs, 390, 0.01)
+	if analysis.INPRating != "needs-improvement" {
+		t.Errorf("INPRating = %s, want needs-improvement", analysis.INPRating)
+	}
+
+	if len(analysis.EventDelay) != 1 {
+		t.Fatalf("expected event delay stats for one thread, got %d", len(analysis.EventDelay))
+	}
+	ed := analysis.EventDelay[0]
+	if ed.Thread != "GeckoMain" || ed.SamplesOver != 1 {
+		t.Errorf("event delay = %s/%d, want GeckoMain/1", ed.Thread, ed.SamplesOver)
+	}
+	testutil.AssertFloatApproxEqual(t, ed.MaxMs, 80, 0.01)
+}
+
+func TestAnalyzeInteractions_ChromeEventDispatch(t *testing.T) {
+	mb := testutil.NewMarkerBuilder().
+		AddEventDispatch("click", 100, 30).
+		AddEventDispatch("mousemove", 200, 5).
+		AddCustom("DrawFrame", 4, 150, 0, nil)
+	markers, strs := mb.Build()
+
+	profile := testutil.NewProfileBuilder().
+		WithCategories(testutil.DefaultCategories()).
+		WithThread(testutil.NewThreadBuilder("CrRendererMain").
+			AsMainThread().
+			WithStringArray(strs).
+			WithMarkers(markers).
+			Build()).
+		Build()
+
+	analysis := AnalyzeInteractions(profile, 10)
+
+	if analysis.TotalInteractions != 1 {
+		t.Fatalf("TotalInteractions = %d, want 1 (mousemove is not an interaction)", analysis.TotalInteractions)
+	}
+	in := analysis.WorstInteractions[0]
+	if in.PresentedBy != "DrawFrame" || in.InputDelaySource != "none" {
+		t.Errorf("presented/source = %s/%s, want DrawFrame/none", in.PresentedBy, in.InputDelaySource)
+	}
+	testutil.AssertFloatApproxEqual(t, in.LatencyMs, 50, 0.01)
+	if analysis.INPRating != "good" {
+		t.Errorf("INPRating = %s, want good", analysis.INPRating)
+	}
+}
+
+func TestAnalyzeInteractions_NoInteractions(t *testing.T) {
+	analysis := AnalyzeInteractions(testutil.MinimalProfile(), 10)
+
+	if analysis.TotalInteractions != 0 || analysis.INPRating != "n/a" {
+		t.Errorf("expected no interactions, got %d (%s)", analysis.TotalInteractions, analysis.INPRating)
+	}
+	if len(analysis.Recommendations) == 0 {
+		t.Error("expected a recommendation about missing interactions")
+	}
+}
+
+func TestInteractionToNextPaint(t *testing.T) {
+	latencies := make([]float64, 120)
+	for i := range latencies {
+		latencies[i] = float64(i + 1)
+	}
+	// Two outliers are ignored for 120 interactions
+	if got := interactionToNextPaint(latencies); got != 118 {
+		t.Errorf("INP = %.0f, want 118", got)
+	}
+	if got := interactionToNextPaint([]float64{5, 40}); got != 40 {
+		t.Errorf("INP = %.0f, want 40", got)
+	}
+	if interactionToNextPaint(nil) != 0 {
+		t.Error("expected 0 for no interactions")
+	}
+}
+
+func TestAnalyzeInteractions_SameNamedThreads(t *testing.T) {
+	// Two content processes each have a GeckoMain; only the second handles the keydown
+	sampledThread := func(funcName string, markers parser.Markers, strs []string) parser.Thread {
+		nameIdx := len(strs)
+		strs = append(strs, funcName)
+		fnb := testutil.NewFuncTableBuilder()
+		fnb.AddFunc(nameIdx, true, -1)
+		ftb := testutil.NewFrameTableBuilder()
+		ftb.AddFrame(0, 2)
+		stb := testutil.NewStackTableBuilder()
+		stb.AddStack(0, 2, -1)
+		sb := testutil.NewSamplesBuilder()
+		for ts := 1001.0; ts < 1300; ts++ {
+			sb.AddSample(0, ts)
+		}
+		return testutil.NewThreadBuilder("GeckoMain").
+			AsMainThread().
+			WithStringArray(strs).
+			WithMarkers(markers).
+			WithStackTable(stb.Build()).
+			WithFrameTable(ftb.Build()).
+			WithFuncTable(fnb.Build()).
+			WithSamples(sb.Build()).
+			Build()
+	}
+
+	idleMarkers, idleStrs := testutil.NewMarkerBuilder().Build()
+	inputMarkers, inputStrs := testutil.NewMarkerBuilder().AddDOMEventWithDuration("keydown", 1000, 300).Build()
+
+	profile := testutil.NewProfileBuilder().
+		WithDuration(2000).
+		WithCategories(testutil.DefaultCategories()).
+		WithThread(sampledThread("otherTabWork", idleMarkers, idleStrs)).
+		WithThread(sampledThread("slowKeyHandler", inputMarkers, inputStrs)).
+		Build()
+
+	analysis := AnalyzeInteractions(profile, 10)
+
+	testutil.AssertSliceLen(t, analysis.WorstInteractions, 1)
+	top := analysis.WorstInteractions[0].TopFunctions
+	if len(top) == 0 || top[0].Name != "slowKeyHandler" {
+		t.Errorf("TopFunctions = %v, want slowKeyHandler from the thread that handled the input", top)
+	}
+}
@@ -0,0 +1,535 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/CedricHerzog/perfowl/internal/parser"
+)
+
+// JIT execution tiers, from slowest to fastest
+const (
+	TierInterpreter         = "interpreter"
+	TierBaselineInterpreter = "baseline_interpreter"
+	TierBaseline            = "baseline"
+	TierOptimized           = "optimized"
+	TierUnknown             = "unknown"
+)
+
+var jitTierOrder = []string{
+	TierInterpreter,
+	TierBaselineInterpreter,
+	TierBaseline,
+	TierOptimized,
+	TierUnknown,
+}
+
+// Thresholds for JIT tier analysis
+const (
+	JITHotFunctionMinPercent   = 1.0  // Function must account for >= 1% of JS time to be considered hot
+	JITUnoptimizedMaxPercent   = 50.0 // Hot functions with less optimized time than this are reported
+	JITTierChangeMinPercentPts = 10.0 // Minimum optimized share change to report a function in a comparison
+)
+
+// TierStats contains time spent in a single JIT tier
+type TierStats struct {
+	Tier        string  `json:"tier"`
+	TimeMs      float64 `json:"time_ms"`
+	Percent     float64 `json:"percent"`
+	SampleCount int     `json:"sample_count"`
+}
+
+// FunctionTierStats contains the tier mix of a single JS function
+type FunctionTierStats struct {
+	Name                  string  `json:"name"`
+	File                  string  `json:"file,omitempty"`
+	TotalTimeMs           float64 `json:"total_time_ms"`
+	InterpreterMs         float64 `json:"interpreter_ms"`
+	BaselineInterpreterMs float64 `json:"baseline_interpreter_ms"`
+	BaselineMs            float64 `json:"baseline_ms"`
+	OptimizedMs           float64 `json:"optimized_ms"`
+	UnknownMs             float64 `json:"unknown_ms,omitempty"`
+	OptimizedPercent      float64 `json:"optimized_percent"`
+	IsWasm                bool    `json:"is_wasm,omitempty"`
+}
+
+// JITTierAnalysis contains JS time broken down by execution tier
+type JITTierAnalysis struct {
+	TotalJSTimeMs   float64                `json:"total_js_time_ms"`
+	TotalSamples    int                    `json:"total_samples"`
+	Tiers           []TierStats            `json:"tiers"`
+	ByThread        map[string][]TierStats `json:"by_thread,omitempty"`
+	TopFunctions    []FunctionTierStats    `json:"top_functions"`
+	Unoptimized     []FunctionTierStats    `json:"unoptimized_functions"`
+	Recommendations []string               `json:"recommendations,omitempty"`
+}
+
+// TierChange contains the change in share of a single tier between two profiles
+type TierChange struct {
+	Tier              string  `json:"tier"`
+	BaselineMs        float64 `json:"baseline_ms"`
+	ComparisonMs      float64 `json:"comparison_ms"`
+	BaselinePercent   float64 `json:"baseline_percent"`
+	ComparisonPercent float64 `json:"comparison_percent"`
+	ChangePoints      float64 `json:"change_points"`
+}
+
+// FunctionTierChange contains the change in optimized share of a function between two profiles
+type FunctionTierChange struct {
+	Name                       string  `json:"name"`
+	BaselineTimeMs             float64 `json:"baseline_time_ms"`
+	ComparisonTimeMs           float64 `json:"comparison_time_ms"`
+	BaselineOptimizedPercent   float64 `json:"baseline_optimized_percent"`
+	ComparisonOptimizedPercent float64 `json:"comparison_optimized_percent"`
+	ChangePoints               float64 `json:"change_points"`
+}
+
+// JITTierComparison compares the tier mix of two profiles
+type JITTierComparison struct {
+	Baseline        JITTierAnalysis      `json:"baseline"`
+	Comparison      JITTierAnalysis      `json:"comparison"`
+	TierChanges     []TierChange         `json:"tier_changes"`
+	FunctionChanges []FunctionTierChange `json:"function_changes"`
+	Analysis        string               `json:"analysis"`
+}
+
+// AnalyzeJITTiers computes JS time per execution tier, overall, per thread and per function.
+// Each sample is attributed to its innermost JS frame, so native code called from JS
+// counts towards the tier of its JS caller.
+func AnalyzeJITTiers(profile *parser.Profile, limit int) JITTierAnalysis {
+	analysis, _ := analyzeJITTiers(profile, limit)
+	return analysis
+}
+
+// analyzeJITTiers returns the analysis along with the stats of every JS function
+func analyzeJITTiers(profile *parser.Profile, limit int) (JITTierAnalysis, []FunctionTierStats) {
+	sharedStrings := profile.Shared.StringArray
+	analysis := JITTierAnalysis{
+		Tiers:        make([]TierStats, 0),
+		ByThread:     make(map[string][]TierStats),
+		TopFunctions: make([]FunctionTierStats, 0),
+		Unoptimized:  make([]FunctionTierStats, 0),
+	}
+
+	if limit <= 0 {
+		limit = 20
+	}
+
+	interval := profile.Meta.Interval
+
+	globalTiers := make(map[string]*TierStats)
+	threadTiers := make(map[string]map[string]*TierStats)
+	funcStats := make(map[string]*FunctionTierStats)
+
+	for _, thread := range profile.Threads {
+		stackTable := &thread.StackTable
+		frameTable := &thread.FrameTable
+		funcTable := &thread.FuncTable
+		samples := &thread.Samples
+
+		// Use thread's string array, fall back to shared if empty
+		stringArray := thread.StringArray
+		if len(stringArray) == 0 {
+			stringArray = sharedStrings
+		}
+
+		getString := func(idx int) string {
+			if idx >= 0 && idx < len(stringArray) {
+				return stringArray[idx]
+			}
+			return ""
+		}
+
+		isJSFrame := func(frameIdx int) bool {
+			if frameIdx < 0 || frameIdx >= len(frameTable.Func) {
+				return false
+			}
+			funcIdx := frameTable.Func[frameIdx]
+			if funcIdx < 0 || funcIdx >= len(funcTable.IsJS) || !funcTable.IsJS[funcIdx] {
+				return false
+			}
+			// V8 marks native and VM states such as (program) or (garbage collector) as "other"
+			return !strings.EqualFold(frameImplementation(frameTable, frameIdx, stringArray), "other")
+		}
+
+		// jsFrameForStack caches the innermost JS frame of each stack (-1 if none)
+		jsFrameForStack := make([]int, stackTable.Length)
+		for i := range jsFrameForStack {
+			jsFrameForStack[i] = -2
+		}
+		resolveJSFrame := func(stackIdx int) int {
+			// Walk up to the first cached or JS stack, then fill the cache on the way back
+			var path []int
+			result := -1
+			for current := stackIdx; current >= 0 && current < stackTable.Length; {
+				if jsFrameForStack[current] != -2 {
+					result = jsFrameForStack[current]
+					break
+				}
+				path = append(path, current)
+				if current < len(stackTable.Frame) && isJSFrame(stackTable.Frame[current]) {
+					result = stackTable.Frame[current]
+					break
+				}
+				if current >= len(stackTable.Prefix) {
+					break
+				}
+				current = stackTable.Prefix[current]
+			}
+			for _, s := range path {
+				jsFrameForStack[s] = result
+			}
+			return result
+		}
+
+		if threadTiers[thread.Name] == nil {
+			threadTiers[thread.Name] = make(map[string]*TierStats)
+		}
+
+		for i := 0; i < samples.Length; i++ {
+			stackIdx := -1
+			if i < len(samples.Stack) {
+				stackIdx = samples.Stack[i]
+			}
+			if stackIdx < 0 || stackIdx >= stackTable.Length {
+				continue
+			}
+
+			frameIdx := resolveJSFrame(stackIdx)
+			if frameIdx < 0 {
+				continue
+			}
+
+			cpuDelta := interval
+			if i < len(samples.ThreadCPUDelta) && samples.ThreadCPUDelta[i] > 0 {
+				cpuDelta = float64(samples.ThreadCPUDelta[i]) / 1000.0
+			}
+
+			tier := ClassifyJITTier(frameImplementation(frameTable, frameIdx, stringArray))
+
+			analysis.TotalJSTimeMs += cpuDelta
+			analysis.TotalSamples++
+			addTierTime(globalTiers, tier, cpuDelta)
+			addTierTime(threadTiers[thread.Name], tier, cpuDelta)
+
+			funcIdx := frameTable.Func[frameIdx]
+			name := "(unknown)"
+			file := ""
+			if funcIdx < len(funcTable.Name) {
+				if s := getString(funcTable.Name[funcIdx]); s != "" {
+					name = s
+				}
+			}
+			if funcIdx < len(funcTable.FileName) {
+				file = getString(funcTable.FileName[funcIdx])
+			}
+
+			fs := funcStats[name]
+			if fs == nil {
+				fs = &FunctionTierStats{Name: name, File: file, IsWasm: isWasmFunction(name, file)}
+				funcStats[name] = fs
+			}
+			fs.TotalTimeMs += cpuDelta
+			switch tier {
+			case TierInterpreter:
+				fs.InterpreterMs += cpuDelta
+			case TierBaselineInterpreter:
+				fs.BaselineInterpreterMs += cpuDelta
+			case TierBaseline:
+				fs.BaselineMs += cpuDelta
+			case TierOptimized:
+				fs.OptimizedMs += cpuDelta
+			default:
+				fs.UnknownMs += cpuDelta
+			}
+		}
+	}
+
+	analysis.Tiers = tierStatsList(globalTiers, analysis.TotalJSTimeMs)
+	for name, tiers := range threadTiers {
+		total := 0.0
+		for _, ts := range tiers {
+			total += ts.TimeMs
+		}
+		if total > 0 {
+			analysis.ByThread[name] = tierStatsList(tiers, total)
+		}
+	}
+
+	allFuncs := make([]FunctionTierStats, 0, len(funcStats))
+	for _, fs := range funcStats {
+		if known := fs.TotalTimeMs - fs.UnknownMs; known > 0 {
+			fs.OptimizedPercent = (fs.OptimizedMs / known) * 100
+		}
+		allFuncs = append(allFuncs, *fs)
+	}
+	sort.Slice(allFuncs, func(i, j int) bool {
+		if allFuncs[i].TotalTimeMs != allFuncs[j].TotalTimeMs {
+			return allFuncs[i].TotalTimeMs > allFuncs[j].TotalTimeMs
+		}
+		return allFuncs[i].Name < allFuncs[j].Name
+	})
+
+	for _, fs := range allFuncs {
+		if len(analysis.TopFunctions) < limit {
+			analysis.TopFunctions = append(analysis.TopFunctions, fs)
+		}
+		if isUnoptimizedHotFunction(fs, analysis.TotalJSTimeMs) && len(analysis.Unoptimized) < limit {
+			analysis.Unoptimized = append(analysis.Unoptimized, fs)
+		}
+	}
+
+	analysis.Recommendations = jitRecommendations(analysis)
+
+	return analysis, allFuncs
+}
+
+// ClassifyJITTier maps a frame implementation string to a JIT tier. Firefox uses
+// "interpreter", "blinterp", "baseline" and "ion" (a missing implementation on a JS
+// frame means interpreted code), V8 reports the codeType of the call frame.
+func ClassifyJITTier(implementation string) string {
+	switch strings.ToLower(implementation) {
+	case "", "interpreter", "interpreted", "ignition":
+		return TierInterpreter
+	case "blinterp":
+		return TierBaselineInterpreter
+	case "baseline", "sparkplug", "liftoff":
+		return TierBaseline
+	case "ion", "jit", "maglev", "turbofan", "optimized":
+		return TierOptimized
+	default:
+		return TierUnknown
+	}
+}
+
+// frameImplementation returns the implementation string of a frame, or "" if none
+func frameImplementation(frameTable *parser.FrameTable, frameIdx int, stringArray []string) string {
+	if frameIdx < 0 || frameIdx >= len(frameTable.Implementation) {
+		return ""
+	}
+
+	idx := -1
+	switch v := frameTable.Implementation[frameIdx].(type) {
+	case int:
+		idx = v
+	case float64:
+		idx = int(v)
+	case json.Number:
+		if n, err := v.Int64(); err == nil {
+			idx = int(n)
+		}
+	case string:
+		return v
+	}
+
+	if idx >= 0 && idx < len(stringArray) {
+		return stringArray[idx]
+	}
+	return ""
+}
+
+// isWasmFunction returns true if the function looks like WebAssembly code
+func isWasmFunction(name, file string) bool {
+	return strings.Contains(name, "wasm-function[") ||
+		strings.HasPrefix(file, "wasm://") ||
+		strings.Contains(file, ".wasm")
+}
+
+// isUnoptimizedHotFunction returns true if a hot function spends most of its time below the optimizing tier
+func isUnoptimizedHotFunction(fs FunctionTierStats, totalJSTimeMs float64) bool {
+	if !isHotFunction(fs, totalJSTimeMs) || fs.TotalTimeMs-fs.UnknownMs <= 0 {
+		return false
+	}
+	return fs.OptimizedPercent < JITUnoptimizedMaxPercent
+}
+
+func addTierTime(tiers map[string]*TierStats, tier string, timeMs float64) {
+	ts := tiers[tier]
+	if ts == nil {
+		ts = &TierStats{Tier: tier}
+		tiers[tier] = ts
+	}
+	ts.TimeMs += timeMs
+	ts.SampleCount++
+}
+
+// tierStatsList returns tier stats in tier order with percentages of total
+func tierStatsList(tiers map[string]*TierStats, total float64) []TierStats {
+	result := make([]TierStats, 0, len(tiers))
+	for _, tier := range jitTierOrder {
+		ts, ok := tiers[tier]
+		if !ok {
+			continue
+		}
+		stats := *ts
+		if total > 0 {
+			stats.Percent = (stats.TimeMs / total) * 100
+		}
+		result = append(result, stats)
+	}
+	return result
+}
+
+// tierPercent returns the share of a tier, or 0 if it is absent
+func tierPercent(tiers []TierStats, tier string) (float64, float64) {
+	for _, ts := range tiers {
+		if ts.Tier == tier {
+			return ts.TimeMs, ts.Percent
+		}
+	}
+	return 0, 0
+}
+
+func jitRecommendations(analysis JITTierAnalysis) []string {
+	var recs []string
+
+	if analysis.TotalJSTimeMs == 0 {
+		return recs
+	}
+
+	_, unknownPercent := tierPercent(analysis.Tiers, TierUnknown)
+	if unknownPercent > 50 {
+		recs = append(recs, "Most JS frames carry no tier information (V8 traces only report the code type), so the tier breakdown is limited")
+	}
+
+	_, optimizedPercent := tierPercent(analysis.Tiers, TierOptimized)
+	if unknownPercent < 50 && optimizedPercent < 50 {
+		recs = append(recs, fmt.Sprintf("Only %.1f%% of JS time runs in the optimizing tier - the profile may be dominated by warm-up or short-lived code", optimizedPercent))
+	}
+
+	if len(analysis.Unoptimized) > 0 {
+		names := make([]string, 0, 3)
+		for i, fs := range analysis.Unoptimized {
+			if i >= 3 {
+				break
+			}
+			names = append(names, fs.Name)
+		}
+		recs = append(recs, fmt.Sprintf("%d hot functions stay in the interpreter or baseline tiers (%s) - check for deoptimization loops, polymorphic call sites or code that runs too briefly to warm up",
+			len(analysis.Unoptimized), strings.Join(names, ", ")))
+	}
+
+	for _, fs := range analysis.Unoptimized {
+		if fs.IsWasm {
+			recs = append(recs, fmt.Sprintf("WebAssembly function %s runs mostly in the baseline compiler - tier-up may be disabled or not yet complete", fs.Name))
+			break
+		}
+	}
+
+	return recs
+}
+
+// CompareJITTiers compares the JIT tier mix of two profiles
+func CompareJITTiers(baseline, comparison *parser.Profile, limit int) JITTierComparison {
+	if limit <= 0 {
+		limit = 20
+	}
+
+	baseAnalysis, baseFuncs := analyzeJITTiers(baseline, limit)
+	compAnalysis, compFuncs := analyzeJITTiers(comparison, limit)
+
+	result := JITTierComparison{
+		Baseline:        baseAnalysis,
+		Comparison:      compAnalysis,
+		TierChanges:     make([]TierChange, 0, len(jitTierOrder)),
+		FunctionChanges: make([]FunctionTierChange, 0),
+	}
+
+	for _, tier := range jitTierOrder {
+		baseMs, basePct := tierPercent(baseAnalysis.Tiers, tier)
+		compMs, compPct := tierPercent(compAnalysis.Tiers, tier)
+		if baseMs == 0 && compMs == 0 {
+			continue
+		}
+		result.TierChanges = append(result.TierChanges, TierChange{
+			Tier:              tier,
+			BaselineMs:        baseMs,
+			ComparisonMs:      compMs,
+			BaselinePercent:   basePct,
+			ComparisonPercent: compPct,
+			ChangePoints:      compPct - basePct,
+		})
+	}
+
+	// Match functions by name and report those whose optimized share moved
+	baseByName := make(map[string]FunctionTierStats, len(baseFuncs))
+	for _, fs := range baseFuncs {
+		baseByName[fs.Name] = fs
+	}
+	for _, comp := range compFuncs {
+		base, ok := baseByName[comp.Name]
+		if !ok {
+			continue
+		}
+		hot := isHotFunction(base, baseAnalysis.TotalJSTimeMs) || isHotFunction(comp, compAnalysis.TotalJSTimeMs)
+		change := comp.OptimizedPercent - base.OptimizedPercent
+		if !hot || math.Abs(change) < JITTierChangeMinPercentPts {
+			continue
+		}
+		result.FunctionChanges = append(result.FunctionChanges, FunctionTierChange{
+			Name:                       comp.Name,
+			BaselineTimeMs:             base.TotalTimeMs,
+			ComparisonTimeMs:           comp.TotalTimeMs,
+			BaselineOptimizedPercent:   base.OptimizedPercent,
+			ComparisonOptimizedPercent: comp.OptimizedPercent,
+			ChangePoints:               change,
+		})
+	}
+	sort.Slice(result.FunctionChanges, func(i, j int) bool {
+		return math.Abs(result.FunctionChanges[i].ChangePoints) > math.Abs(result.FunctionChanges[j].ChangePoints)
+	})
+	if len(result.FunctionChanges) > limit {
+		result.FunctionChanges = result.FunctionChanges[:limit]
+	}
+
+	// Generate analysis text
+	var analysis strings.Builder
+
+	_, baseOpt := tierPercent(baseAnalysis.Tiers, TierOptimized)
+	_, compOpt := tierPercent(compAnalysis.Tiers, TierOptimized)
+	if compOpt-baseOpt < -JITTierChangeMinPercentPts {
+		analysis.WriteString(fmt.Sprintf("Optimized tier share dropped from %.1f%% to %.1f%%. ", baseOpt, compOpt))
+	} else if compOpt-baseOpt > JITTierChangeMinPercentPts {
+		analysis.WriteString(fmt.Sprintf("Optimized tier share increased from %.1f%% to %.1f%%. ", baseOpt, compOpt))
+	}
+
+	regressed := 0
+	for _, fc := range result.FunctionChanges {
+		if fc.ChangePoints < 0 {
+			regressed++
+		}
+	}
+	if regressed > 0 {
+		worst := firstRegressedFunction(result.FunctionChanges)
+		analysis.WriteString(fmt.Sprintf("%d hot functions spend less time optimized (largest: %s, %.1f%% -> %.1f%%). ",
+			regressed, worst.Name, worst.BaselineOptimizedPercent, worst.ComparisonOptimizedPercent))
+	}
+	if improved := len(result.FunctionChanges) - regressed; improved > 0 {
+		analysis.WriteString(fmt.Sprintf("%d hot functions spend more time optimized. ", improved))
+	}
+
+	result.Analysis = analysis.String()
+	if result.Analysis == "" {
+		result.Analysis = "No significant JIT tier changes detected between profiles."
+	}
+
+	return result
+}
+
+// isHotFunction returns true if the function accounts for a meaningful share of JS time
+func isHotFunction(fs FunctionTierStats, totalJSTimeMs float64) bool {
+	return totalJSTimeMs > 0 && (fs.TotalTimeMs/totalJSTimeMs)*100 >= JITHotFunctionMinPercent
+}
+
+// firstRegressedFunction returns the function change with the largest optimized share loss
+func firstRegressedFunction(changes []FunctionTierChange) FunctionTierChange {
+	for _, fc := range changes {
+		if fc.ChangePoints < 0 {
+			return fc
+		}
+	}
+	return FunctionTierChange{}
+}
@@ -0,0 +1,267 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/CedricHerzog/perfowl/internal/parser"
+	"github.com/CedricHerzog/perfowl/internal/testutil"
+)
+
+func TestClassifyJITTier(t *testing.T) {
+	tests := []struct {
+		impl string
+		want string
+	}{
+		{"", TierInterpreter},
+		{"interpreter", TierInterpreter},
+		{"blinterp", TierBaselineInterpreter},
+		{"baseline", TierBaseline},
+		{"ion", TierOptimized},
+		{"ION", TierOptimized},
+		{"jit", TierOptimized},
+		{"turbofan", TierOptimized},
+		{"JS", TierUnknown},
+		{"something", TierUnknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.impl, func(t *testing.T) {
+			if got := ClassifyJITTier(tt.impl); got != tt.want {
+				t.Errorf("ClassifyJITTier(%q) = %q, want %q", tt.impl, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFrameImplementation(t *testing.T) {
+	strings := []string{"ion", "baseline"}
+	ft := &parser.FrameTable{
+		Implementation: []interface{}{nil, 0, 1.0, json.Number("0"), "blinterp", 99},
+	}
+
+	want := []string{"", "ion", "baseline", "ion", "blinterp", ""}
+	for i, w := range want {
+		if got := frameImplementation(ft, i, strings); got != w {
+			t.Errorf("frameImplementation(%d) = %q, want %q", i, got, w)
+		}
+	}
+	if got := frameImplementation(ft, 10, strings); got != "" {
+		t.Errorf("frameImplementation(out of range) = %q, want empty", got)
+	}
+}
+
+func TestAnalyzeJITTiers_Empty(t *testing.T) {
+	profile := testutil.MinimalProfile()
+
+	result := AnalyzeJITTiers(profile, 10)
+
+	if result.TotalJSTimeMs != 0 {
+		t.Errorf("TotalJSTimeMs = %v, want 0", result.TotalJSTimeMs)
+	}
+	if len(result.Tiers) != 0 {
+		t.Errorf("len(Tiers) = %d, want 0", len(result.Tiers))
+	}
+}
+
+func TestAnalyzeJITTiers_TierBreakdown(t *testing.T) {
+	profile := testutil.ProfileWithJITTiers()
+
+	result := AnalyzeJITTiers(profile, 10)
+
+	// nativeHelper samples are attributed to their JS caller, so every sample counts
+	if result.TotalSamples != 90 {
+		t.Errorf("TotalSamples = %d, want 90", result.TotalSamples)
+	}
+	testutil.AssertFloatApproxEqual(t, result.TotalJSTimeMs, 90, 0.001)
+
+	want := map[string]float64{
+		TierInterpreter:         5,
+		TierBaselineInterpreter: 10,
+		TierBaseline:            35,
+		TierOptimized:           40,
+	}
+	if len(result.Tiers) != len(want) {
+		t.Fatalf("len(Tiers) = %d, want %d", len(result.Tiers), len(want))
+	}
+	for _, ts := range result.Tiers {
+		testutil.AssertFloatApproxEqual(t, ts.TimeMs, want[ts.Tier], 0.001)
+	}
+
+	// Tiers are ordered from slowest to fastest
+	if result.Tiers[0].Tier != TierInterpreter || result.Tiers[3].Tier != TierOptimized {
+		t.Errorf("unexpected tier order: %v", result.Tiers)
+	}
+
+	if _, ok := result.ByThread["GeckoMain"]; !ok {
+		t.Error("expected GeckoMain in ByThread")
+	}
+}
+
+func TestAnalyzeJITTiers_Functions(t *testing.T) {
+	profile := testutil.ProfileWithJITTiers()
+
+	result := AnalyzeJITTiers(profile, 10)
+
+	if len(result.TopFunctions) != 3 {
+		t.Fatalf("len(TopFunctions) = %d, want 3", len(result.TopFunctions))
+	}
+
+	hotLoop := result.TopFunctions[0]
+	if hotLoop.Name != "hotLoop" {
+		t.Fatalf("TopFunctions[0] = %q, want hotLoop", hotLoop.Name)
+	}
+	testutil.AssertFloatApproxEqual(t, hotLoop.OptimizedMs, 40, 0.001)
+	testutil.AssertFloatApproxEqual(t, hotLoop.BaselineMs, 5, 0.001)
+	testutil.AssertFloatApproxEqual(t, hotLoop.OptimizedPercent, 40.0/45.0*100, 0.01)
+
+	for _, fs := range result.TopFunctions {
+		if fs.Name == "nativeHelper" {
+			t.Error("native function should not appear in JS tier stats")
+		}
+	}
+}
+
+func TestAnalyzeJITTiers_Unoptimized(t *testing.T) {
+	profile := testutil.ProfileWithJITTiers()
+
+	result := AnalyzeJITTiers(profile, 10)
+
+	found := false
+	for _, fs := range result.Unoptimized {
+		if fs.Name == "hotLoop" {
+			t.Error("hotLoop is mostly optimized and should not be reported")
+		}
+		if fs.Name == "decrypt" {
+			found = true
+			testutil.AssertFloatApproxEqual(t, fs.BaselineMs, 30, 0.001)
+			testutil.AssertFloatApproxEqual(t, fs.BaselineInterpreterMs, 10, 0.001)
+		}
+	}
+	if !found {
+		t.Error("expected decrypt in unoptimized functions")
+	}
+	if len(result.Recommendations) == 0 {
+		t.Error("expected recommendations")
+	}
+}
+
+func TestAnalyzeJITTiers_Limit(t *testing.T) {
+	profile := testutil.ProfileWithJITTiers()
+
+	result := AnalyzeJITTiers(profile, 1)
+
+	if len(result.TopFunctions) != 1 {
+		t.Errorf("len(TopFunctions) = %d, want 1", len(result.TopFunctions))
+	}
+}
+
+func TestAnalyzeJITTiers_V8CodeType(t *testing.T) {
+	// V8 only reports "JS" or "other" code types
+	strings := []string{"(program)", "app", "other", "JS"}
+
+	fnb := testutil.NewFuncTableBuilder()
+	fnb.AddFunc(0, true, -1).AddFunc(1, true, -1)
+
+	ftb := testutil.NewFrameTableBuilder()
+	ftb.AddFrameWithImplementation(0, 0, 2).
+		AddFrameWithImplementation(1, 2, 3)
+
+	stb := testutil.NewStackTableBuilder()
+	stb.AddStack(0, 0, -1).AddStack(1, 2, -1)
+
+	sb := testutil.NewSamplesBuilder()
+	sb.AddSampleWithCPUDelta(0, 0, 1000).
+		AddSampleWithCPUDelta(1, 1, 1000).
+		AddSampleWithCPUDelta(1, 2, 1000)
+
+	profile := testutil.NewProfileBuilder().
+		WithCategories(testutil.DefaultCategories()).
+		WithThread(testutil.NewThreadBuilder("CrRendererMain").
+			WithStringArray(strings).
+			WithStackTable(stb.Build()).
+			WithFrameTable(ftb.Build()).
+			WithFuncTable(fnb.Build()).
+			WithSamples(sb.Build()).
+			Build()).
+		Build()
+
+	result := AnalyzeJITTiers(profile, 10)
+
+	// (program) has code type "other" and is not JS
+	if result.TotalSamples != 2 {
+		t.Errorf("TotalSamples = %d, want 2", result.TotalSamples)
+	}
+	if len(result.Tiers) != 1 || result.Tiers[0].Tier != TierUnknown {
+		t.Errorf("Tiers = %v, want only unknown", result.Tiers)
+	}
+	if len(result.Unoptimized) != 0 {
+		t.Error("functions without tier information should not be reported as unoptimized")
+	}
+}
+
+func TestCompareJITTiers(t *testing.T) {
+	baseline := testutil.ProfileWithJITTiers()
+	comparison := testutil.ProfileWithJITTiers()
+	// decrypt tiers up to Ion in the comparison profile
+	comparison.Threads[0].FrameTable.Implementation[2] = 4
+
+	result := CompareJITTiers(baseline, comparison, 10)
+
+	var optimized *TierChange
+	for i := range result.TierChanges {
+		if result.TierChanges[i].Tier == TierOptimized {
+			optimized = &result.TierChanges[i]
+		}
+	}
+	if optimized == nil {
+		t.Fatal("expected optimized tier change")
+	}
+	testutil.AssertFloatApproxEqual(t, optimized.BaselineMs, 40, 0.001)
+	testutil.AssertFloatApproxEqual(t, optimized.ComparisonMs, 70, 0.001)
+
+	if len(result.FunctionChanges) != 1 || result.FunctionChanges[0].Name != "decrypt" {
+		t.Fatalf("FunctionChanges = %v, want decrypt only", result.FunctionChanges)
+	}
+	testutil.AssertFloatApproxEqual(t, result.FunctionChanges[0].ChangePoints, 75, 0.001)
+	testutil.AssertStringContains(t, result.Analysis, "increased")
+}
+
+func TestCompareJITTiers_Regression(t *testing.T) {
+	baseline := testutil.ProfileWithJITTiers()
+	comparison := testutil.ProfileWithJITTiers()
+	// hotLoop no longer reaches Ion
+	comparison.Threads[0].FrameTable.Implementation[1] = 5
+
+	result := CompareJITTiers(baseline, comparison, 10)
+
+	testutil.AssertStringContains(t, result.Analysis, "dropped")
+	testutil.AssertStringContains(t, result.Analysis, "hotLoop")
+}
+
+func TestCompareJITTiers_NoChange(t *testing.T) {
+	profile := testutil.ProfileWithJITTiers()
+
+	result := CompareJITTiers(profile, profile, 10)
+
+	if len(result.FunctionChanges) != 0 {
+		t.Errorf("len(FunctionChanges) = %d, want 0", len(result.FunctionChanges))
+	}
+	if result.Analysis != "No significant JIT tier changes detected between profiles." {
+		t.Errorf("Analysis = %q", result.Analysis)
+	}
+}
+
+func TestAnalyzeJITTiers_JSONRoundTrip(t *testing.T) {
+	// Implementation indices become float64 after loading from JSON
+	path := testutil.TempProfileFile(t, testutil.ProfileWithJITTiers())
+	profile, _, err := parser.LoadProfileAuto(path)
+	if err != nil {
+		t.Fatalf("LoadProfileAuto error: %v", err)
+	}
+
+	result := AnalyzeJITTiers(profile, 10)
+
+	_, optimizedPercent := tierPercent(result.Tiers, TierOptimized)
+	testutil.AssertFloatApproxEqual(t, optimizedPercent, 40.0/90.0*100, 0.01)
+}
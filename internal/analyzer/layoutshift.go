@@ -0,0 +1,261 @@
+package analyzer
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/CedricHerzog/perfowl/internal/parser"
+)
+
+// Layout stability thresholds, matching the Cumulative Layout Shift definition
+const (
+	LayoutShiftGapMs    = 1000.0 // A shift more than 1s after the previous one starts a new session window
+	LayoutShiftWindowMs = 5000.0 // Session windows last at most 5s
+	CLSGood             = 0.1
+	CLSPoor             = 0.25
+)
+
+// LayoutShiftNode is a DOM node moved by a layout shift
+type LayoutShiftNode struct {
+	NodeID  int       `json:"node_id"`
+	OldRect []float64 `json:"old_rect,omitempty"` // x, y, width, height
+	NewRect []float64 `json:"new_rect,omitempty"`
+}
+
+// LayoutShift is a single layout shift event
+type LayoutShift struct {
+	TimeMs         float64           `json:"time_ms"`
+	Score          float64           `json:"score"`
+	HadRecentInput bool              `json:"had_recent_input"`
+	Window         int               `json:"window"` // Session window index, -1 when excluded
+	Thread         string            `json:"thread"`
+	Nodes          []LayoutShiftNode `json:"nodes,omitempty"`
+}
+
+// LayoutShiftWindow is a session window of layout shifts
+type LayoutShiftWindow struct {
+	Index      int     `json:"index"`
+	StartTime  float64 `json:"start_time"`
+	EndTime    float64 `json:"end_time"`
+	Score      float64 `json:"score"`
+	ShiftCount int     `json:"shift_count"`
+}
+
+// LayoutShiftAnalysis contains layout stability results
+type LayoutShiftAnalysis struct {
+	CLS             float64             `json:"cls"`
+	Rating          string              `json:"rating"`
+	TotalShifts     int                 `json:"total_shifts"`
+	ExcludedShifts  int                 `json:"excluded_shifts"` // Shifts right after user input do not count
+	TotalScore      float64             `json:"total_score"`
+	WorstWindow     int                 `json:"worst_window"`
+	Windows         []LayoutShiftWindow `json:"windows"`
+	LargestShifts   []LayoutShift       `json:"largest_shifts"`
+	Recommendations []string            `json:"recommendations,omitempty"`
+}
+
+// AnalyzeLayoutShifts computes Cumulative Layout Shift from Chrome LayoutShift
+// events. Shifts that follow recent input are excluded, the rest are grouped
+// into session windows, and CLS is the score of the worst window.
+func AnalyzeLayoutShifts(profile *parser.Profile, limit int) LayoutShiftAnalysis {
+	analysis := LayoutShiftAnalysis{
+		WorstWindow:   -1,
+		Windows:       make([]LayoutShiftWindow, 0),
+		LargestShifts: make([]LayoutShift, 0),
+	}
+
+	if limit <= 0 {
+		limit = 10
+	}
+
+	var shifts []LayoutShift
+	for i := range profile.Threads {
+		thread := &profile.Threads[i]
+		for _, m := range parser.ExtractMarkers(thread, profile.Meta.Categories) {
+			if m.Name != "LayoutShift" {
+				continue
+			}
+			shifts = append(shifts, parseLayoutShift(m, thread.Name))
+		}
+	}
+	sort.SliceStable(shifts, func(i, j int) bool { return shifts[i].TimeMs < shifts[j].TimeMs })
+	analysis.TotalShifts = len(shifts)
+
+	// Group counted shifts into session windows
+	for i := range shifts {
+		s := &shifts[i]
+		if s.HadRecentInput {
+			s.Window = -1
+			analysis.ExcludedShifts++
+			continue
+		}
+		analysis.TotalScore += s.Score
+
+		n := len(analysis.Windows)
+		if n == 0 || s.TimeMs-analysis.Windows[n-1].EndTime > LayoutShiftGapMs || s.TimeMs-analysis.Windows[n-1].StartTime > LayoutShiftWindowMs {
+			analysis.Windows = append(analysis.Windows, LayoutShiftWindow{Index: n, StartTime: s.TimeMs})
+			n++
+		}
+		w := &analysis.Windows[n-1]
+		w.EndTime = s.TimeMs
+		w.Score += s.Score
+		w.ShiftCount++
+		s.Window = w.Index
+	}
+
+	for _, w := range analysis.Windows {
+		if w.Score > analysis.CLS {
+			analysis.CLS = w.Score
+			analysis.WorstWindow = w.Index
+		}
+	}
+	analysis.Rating = rateCLS(analysis.CLS)
+
+	sort.SliceStable(shifts, func(i, j int) bool { return shifts[i].Score > shifts[j].Score })
+	if len(shifts) > limit {
+		shifts = shifts[:limit]
+	}
+	analysis.LargestShifts = append(analysis.LargestShifts, shifts...)
+	analysis.Recommendations = layoutShiftRecommendations(analysis)
+
+	return analysis
+}
+
+// parseLayoutShift reads the score, input flag and impacted nodes of a LayoutShift event
+func parseLayoutShift(m parser.ParsedMarker, threadName string) LayoutShift {
+	shift := LayoutShift{TimeMs: m.StartTime, Thread: threadName}
+	data, _ := m.Data["data"].(map[string]interface{})
+	if data == nil {
+		return shift
+	}
+
+	// The weighted delta accounts for shifts in subframes
+	if score, ok := data["weighted_score_delta"].(float64); ok {
+		shift.Score = score
+	} else if score, ok := data["score"].(float64); ok {
+		shift.Score = score
+	}
+	shift.HadRecentInput, _ = data["had_recent_input"].(bool)
+
+	nodes, _ := data["impacted_nodes"].([]interface{})
+	for _, n := range nodes {
+		node, ok := n.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		id, _ := node["node_id"].(float64)
+		shift.Nodes = append(shift.Nodes, LayoutShiftNode{
+			NodeID:  int(id),
+			OldRect: rectValues(node["old_rect"]),
+			NewRect: rectValues(node["new_rect"]),
+		})
+	}
+	return shift
+}
+
+// rectValues converts a JSON rect array into numbers
+func rectValues(v interface{}) []float64 {
+	values, ok := v.([]interface{})
+	if !ok {
+		return nil
+	}
+	rect := make([]float64, 0, len(values))
+	for _, x := range values {
+		if f, ok := x.(float64); ok {
+			rect = append(rect, f)
+		}
+	}
+	return rect
+}
+
+// rateCLS rates a CLS score as good, needs-improvement or poor
+func rateCLS(cls float64) string {
+	switch {
+	case cls <= CLSGood:
+		return "good"
+	case cls <= CLSPoor:
+		return "needs-improvement"
+	default:
+		return "poor"
+	}
+}
+
+// layoutShiftRecommendations suggests follow-ups based on layout shift results
+func layoutShiftRecommendations(analysis LayoutShiftAnalysis) []string {
+	var recs []string
+	if analysis.TotalShifts == 0 {
+		return append(recs, "No LayoutShift events were recorded; Chrome traces need the loading category")
+	}
+	if analysis.CLS > CLSGood {
+		w := analysis.Windows[analysis.WorstWindow]
+		recs = append(recs, fmt.Sprintf("CLS is %.3f (%s), from %d shifts between %.0fms and %.0fms; reserve space for late content such as images, ads and embeds",
+			analysis.CLS, analysis.Rating, w.ShiftCount, w.StartTime, w.EndTime))
+	}
+	for _, s := range analysis.LargestShifts {
+		if s.Score > CLSGood && !s.HadRecentInput {
+			recs = append(recs, fmt.Sprintf("A single shift at %.0fms scored %.3f; check the nodes it moved for missing dimensions or injected content", s.TimeMs, s.Score))
+			break
+		}
+	}
+	return recs
+}
+
+// FormatLayoutShiftAnalysis returns a human-readable summary
+func FormatLayoutShiftAnalysis(analysis LayoutShiftAnalysis) string {
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf("Layout Shift Analysis (CLS %.3f, rating: %s)\n", analysis.CLS, analysis.Rating))
+	sb.WriteString(strings.Repeat("=", 60) + "\n\n")
+
+	sb.WriteString(fmt.Sprintf("Layout Shifts: %d (%d excluded after input)\n", analysis.TotalShifts, analysis.ExcludedShifts))
+	sb.WriteString(fmt.Sprintf("Total Score: %.4f\n", analysis.TotalScore))
+	sb.WriteString(fmt.Sprintf("Session Windows: %d\n\n", len(analysis.Windows)))
+
+	if len(analysis.Windows) > 0 {
+		sb.WriteString("Session Windows:\n")
+		sb.WriteString(strings.Repeat("-", 60) + "\n")
+		for _, w := range analysis.Windows {
+			marker := ""
+			if w.Index == analysis.WorstWindow {
+				marker = "  <- CLS"
+			}
+			sb.WriteString(fmt.Sprintf("  %.2fms - %.2fms: %.4f (%d shifts)%s\n",
+				w.StartTime, w.EndTime, w.Score, w.ShiftCount, marker))
+		}
+		sb.WriteString("\n")
+	}
+
+	if len(analysis.LargestShifts) > 0 {
+		sb.WriteString("Largest Shifts:\n")
+		sb.WriteString(strings.Repeat("-", 60) + "\n")
+		for _, s := range analysis.LargestShifts {
+			note := ""
+			if s.HadRecentInput {
+				note = " (after input, excluded)"
+			}
+			sb.WriteString(fmt.Sprintf("  %.2fms: %.4f%s\n", s.TimeMs, s.Score, note))
+			for _, n := range s.Nodes {
+				sb.WriteString(fmt.Sprintf("    - node %d: %s -> %s\n", n.NodeID, formatRect(n.OldRect), formatRect(n.NewRect)))
+			}
+		}
+		sb.WriteString("\n")
+	}
+
+	if len(analysis.Recommendations) > 0 {
+		sb.WriteString("Recommendations:\n")
+		for _, r := range analysis.Recommendations {
+			sb.WriteString(fmt.Sprintf("  - %s\n", r))
+		}
+	}
+
+	return sb.String()
+}
+
+// formatRect renders an x, y, width, height rect
+func formatRect(rect []float64) string {
+	if len(rect) != 4 {
+		return "(none)"
+	}
+	return fmt.Sprintf("(%.0f,%.0f %.0fx%.0f)", rect[0], rect[1], rect[2], rect[3])
+}
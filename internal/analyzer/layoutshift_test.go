@@ -0,0 +1,64 @@
+package analyzer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/CedricHerzog/perfowl/internal/testutil"
+)
+
+func TestAnalyzeLayoutShifts_SessionWindows(t *testing.T) {
+	analysis := AnalyzeLayoutShifts(testutil.ProfileWithLayoutShifts(), 10)
+
+	if analysis.TotalShifts != 5 || analysis.ExcludedShifts != 1 {
+		t.Errorf("shifts = %d (%d excluded), want 5 (1 excluded)", analysis.TotalShifts, analysis.ExcludedShifts)
+	}
+	if len(analysis.Windows) != 2 {
+		t.Fatalf("expected 2 session windows, got %d", len(analysis.Windows))
+	}
+	testutil.AssertFloatApproxEqual(t, analysis.CLS, 0.13, 0.0001)
+	testutil.AssertFloatApproxEqual(t, analysis.TotalScore, 0.16, 0.0001)
+	if analysis.WorstWindow != 0 || analysis.Windows[0].ShiftCount != 2 {
+		t.Errorf("worst window = %d with %d shifts, want 0 with 2", analysis.WorstWindow, analysis.Windows[0].ShiftCount)
+	}
+	if analysis.Rating != "needs-improvement" {
+		t.Errorf("Rating = %s, want needs-improvement", analysis.Rating)
+	}
+}
+
+func TestAnalyzeLayoutShifts_LargestShifts(t *testing.T) {
+	analysis := AnalyzeLayoutShifts(testutil.ProfileWithLayoutShifts(), 2)
+
+	if len(analysis.LargestShifts) != 2 {
+		t.Fatalf("expected 2 largest shifts, got %d", len(analysis.LargestShifts))
+	}
+	first := analysis.LargestShifts[0]
+	if !first.HadRecentInput || first.Window != -1 {
+		t.Errorf("largest shift should be the excluded 0.3 shift, got %+v", first)
+	}
+	second := analysis.LargestShifts[1]
+	if len(second.Nodes) != 1 || second.Nodes[0].NodeID != 12 || len(second.Nodes[0].NewRect) != 4 {
+		t.Errorf("expected node 12 with rects, got %+v", second.Nodes)
+	}
+}
+
+func TestAnalyzeLayoutShifts_NoShifts(t *testing.T) {
+	analysis := AnalyzeLayoutShifts(testutil.MinimalProfile(), 10)
+
+	if analysis.CLS != 0 || analysis.Rating != "good" || analysis.WorstWindow != -1 {
+		t.Errorf("expected good CLS 0 with no window, got %.3f %s %d", analysis.CLS, analysis.Rating, analysis.WorstWindow)
+	}
+	if len(analysis.Recommendations) == 0 {
+		t.Error("expected a recommendation about missing LayoutShift events")
+	}
+}
+
+func TestFormatLayoutShiftAnalysis_Output(t *testing.T) {
+	output := FormatLayoutShiftAnalysis(AnalyzeLayoutShifts(testutil.ProfileWithLayoutShifts(), 10))
+
+	for _, want := range []string{"CLS 0.130", "<- CLS", "node 12", "excluded"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("expected output to contain %q", want)
+		}
+	}
+}
@@ -0,0 +1,408 @@
+package analyzer
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/CedricHerzog/perfowl/internal/parser"
+)
+
+// Message flow settings
+const (
+	MessageFlowDefaultLimit   = 20          // Channels listed when no limit is given
+	MessageSlowLatencyMs      = 16.0        // p95 queueing latency above one frame is worth reporting
+	MessageChattyCount        = 100         // Messages on one channel before batching is suggested
+	MessageLargePayloadBytes  = 1024 * 1024 // Average payload above which transferring beats cloning
+	MessageUnpairedMinPercent = 25.0        // Share of unmatched sends before the pairing is called out
+)
+
+// messageSendMarkers are Firefox markers and Chrome events that post a message to another thread
+var messageSendMarkers = map[string]bool{
+	"PostMessage":               true, // Firefox
+	"postMessage":               true,
+	"WorkerThread::PostMessage": true, // Chrome
+	"DedicatedWorkerMessagingProxy::PostMessageToWorkerGlobalScope": true,
+	"DedicatedWorkerObjectProxy::PostMessageToWorkerObject":         true,
+	"MessagePort::postMessage":                                      true,
+}
+
+// messageReceiveMarkers are Chrome events that dispatch a posted message
+var messageReceiveMarkers = map[string]bool{
+	"HandlePostMessage":               true,
+	"WorkerThread::HandlePostMessage": true,
+	"MessagePort::Accept":             true,
+}
+
+// messagePayloadKeys are the marker data fields that may carry a message's size in bytes
+var messagePayloadKeys = []string{"size", "bytes", "byteLength", "messageSize", "dataSize"}
+
+// messageEndpoint is one side of a posted message
+type messageEndpoint struct {
+	thread int
+	time   float64
+	data   map[string]interface{}
+}
+
+// threadMessage is a send matched with the receive that handled it
+type threadMessage struct {
+	send messageEndpoint
+	recv messageEndpoint
+}
+
+// threadMessages are all posted messages found in a profile
+type threadMessages struct {
+	sends    []messageEndpoint
+	receives []messageEndpoint
+	paired   []threadMessage
+}
+
+// MessageChannel summarizes the messages posted from one thread to another
+type MessageChannel struct {
+	From         string  `json:"from"`
+	To           string  `json:"to"`
+	Messages     int     `json:"messages"`
+	TotalBytes   int64   `json:"total_bytes,omitempty"`
+	AvgBytes     float64 `json:"avg_bytes,omitempty"`
+	MaxBytes     int64   `json:"max_bytes,omitempty"`
+	LatencyMinMs float64 `json:"latency_min_ms"`
+	LatencyAvgMs float64 `json:"latency_avg_ms"`
+	LatencyP50Ms float64 `json:"latency_p50_ms"`
+	LatencyP95Ms float64 `json:"latency_p95_ms"`
+	LatencyMaxMs float64 `json:"latency_max_ms"`
+}
+
+// MessageThreadStats counts the messages one thread posted and handled
+type MessageThreadStats struct {
+	Thread   string `json:"thread"`
+	IsMain   bool   `json:"is_main,omitempty"`
+	IsWorker bool   `json:"is_worker,omitempty"`
+	Sent     int    `json:"sent"`
+	Received int    `json:"received"`
+}
+
+// MessageFlowAnalysis describes who posts messages to whom and how long they queue
+type MessageFlowAnalysis struct {
+	TotalSent        int                  `json:"total_sent"`
+	TotalReceived    int                  `json:"total_received"`
+	Paired           int                  `json:"paired"`
+	UnpairedSends    int                  `json:"unpaired_sends"`
+	UnpairedReceives int                  `json:"unpaired_receives"`
+	TotalBytes       int64                `json:"total_bytes,omitempty"`
+	Channels         []MessageChannel     `json:"channels"`
+	Threads          []MessageThreadStats `json:"threads"`
+	Recommendations  []string             `json:"recommendations,omitempty"`
+}
+
+// isMessageSend reports whether a marker posts a message to another thread
+func isMessageSend(m parser.ParsedMarker) bool {
+	return messageSendMarkers[m.Name]
+}
+
+// isMessageReceive reports whether a marker handles a posted message: a Chrome
+// dispatch event or a Firefox "message" DOM event
+func isMessageReceive(m parser.ParsedMarker) bool {
+	return messageReceiveMarkers[m.Name] || isMessageEvent(m)
+}
+
+// isMessageEvent reports whether a marker is a Firefox "message" DOM event
+func isMessageEvent(m parser.ParsedMarker) bool {
+	if m.Name != "DOMEvent" && m.Type != "DOMEvent" {
+		return false
+	}
+	eventType, _ := m.Data["eventType"].(string)
+	return eventType == "message"
+}
+
+// messageID returns the message identifier of a send or receive payload, if any
+func messageID(data map[string]interface{}) string {
+	for _, key := range []string{"messageId", "message_id", "id"} {
+		switch v := data[key].(type) {
+		case string:
+			return v
+		case float64:
+			return fmt.Sprint(v)
+		}
+	}
+	return ""
+}
+
+// messagePayloadBytes returns the payload size recorded on a message marker, or -1
+func messagePayloadBytes(data map[string]interface{}) int64 {
+	for _, d := range []map[string]interface{}{data, milestoneData(data)} {
+		for _, key := range messagePayloadKeys {
+			if v, ok := d[key].(float64); ok && v >= 0 {
+				return int64(v)
+			}
+		}
+	}
+	return -1
+}
+
+// pairThreadMessages collects posted messages from every thread and matches each
+// receive with the send that caused it. Posted messages carry no id in Firefox, so
+// a receive is paired with the unpaired send from another thread closest to the
+// reported latency, or the oldest one.
+func pairThreadMessages(profile *parser.Profile) threadMessages {
+	var msgs threadMessages
+	for i := range profile.Threads {
+		for _, m := range parser.ExtractMarkers(&profile.Threads[i], profile.Meta.Categories) {
+			ep := messageEndpoint{thread: i, time: m.StartTime, data: m.Data}
+			switch {
+			case isMessageSend(m):
+				msgs.sends = append(msgs.sends, ep)
+			case isMessageReceive(m):
+				msgs.receives = append(msgs.receives, ep)
+			}
+		}
+	}
+
+	sort.SliceStable(msgs.sends, func(i, j int) bool { return msgs.sends[i].time < msgs.sends[j].time })
+	sort.SliceStable(msgs.receives, func(i, j int) bool { return msgs.receives[i].time < msgs.receives[j].time })
+	paired := make([]bool, len(msgs.sends))
+	for _, r := range msgs.receives {
+		expected := math.Inf(-1)
+		if latency, ok := r.data["latency"].(float64); ok && latency > 0 {
+			expected = r.time - latency
+		}
+		best := -1
+		for i, s := range msgs.sends {
+			if s.time > r.time {
+				break
+			}
+			if paired[i] || s.thread == r.thread {
+				continue
+			}
+			if id := messageID(r.data); id != "" && messageID(s.data) != id {
+				continue
+			}
+			if best < 0 || math.Abs(s.time-expected) < math.Abs(msgs.sends[best].time-expected) {
+				best = i
+			}
+		}
+		if best >= 0 {
+			paired[best] = true
+			msgs.paired = append(msgs.paired, threadMessage{send: msgs.sends[best], recv: r})
+		}
+	}
+	return msgs
+}
+
+// AnalyzeMessageFlow pairs posted messages between threads and reports, per
+// sender/receiver channel, the message count, queueing latency from send to receive
+// and payload sizes where the markers record them. limit caps the channels listed.
+func AnalyzeMessageFlow(profile *parser.Profile, limit int) MessageFlowAnalysis {
+	if limit <= 0 {
+		limit = MessageFlowDefaultLimit
+	}
+	analysis := MessageFlowAnalysis{
+		Channels: make([]MessageChannel, 0),
+		Threads:  make([]MessageThreadStats, 0),
+	}
+
+	msgs := pairThreadMessages(profile)
+	analysis.TotalSent = len(msgs.sends)
+	analysis.TotalReceived = len(msgs.receives)
+	analysis.Paired = len(msgs.paired)
+	analysis.UnpairedSends = analysis.TotalSent - analysis.Paired
+	analysis.UnpairedReceives = analysis.TotalReceived - analysis.Paired
+
+	threads := make(map[int]*MessageThreadStats)
+	threadStats := func(idx int) *MessageThreadStats {
+		if s, ok := threads[idx]; ok {
+			return s
+		}
+		thread := &profile.Threads[idx]
+		s := &MessageThreadStats{Thread: threadLabel(thread), IsMain: thread.IsMainThread, IsWorker: isWorkerThread(thread)}
+		threads[idx] = s
+		return s
+	}
+	for _, s := range msgs.sends {
+		threadStats(s.thread).Sent++
+	}
+	for _, r := range msgs.receives {
+		threadStats(r.thread).Received++
+	}
+
+	type channelKey struct{ from, to int }
+	type channelAcc struct {
+		latencies []float64
+		sized     int
+		bytes     int64
+		maxBytes  int64
+	}
+	channels := make(map[channelKey]*channelAcc)
+	for _, m := range msgs.paired {
+		key := channelKey{m.send.thread, m.recv.thread}
+		acc, ok := channels[key]
+		if !ok {
+			acc = &channelAcc{}
+			channels[key] = acc
+		}
+		acc.latencies = append(acc.latencies, m.recv.time-m.send.time)
+
+		size := messagePayloadBytes(m.send.data)
+		if size < 0 {
+			size = messagePayloadBytes(m.recv.data)
+		}
+		if size >= 0 {
+			acc.sized++
+			acc.bytes += size
+			if size > acc.maxBytes {
+				acc.maxBytes = size
+			}
+		}
+	}
+
+	for key, acc := range channels {
+		sort.Float64s(acc.latencies)
+		c := MessageChannel{
+			From:         threadStats(key.from).Thread,
+			To:           threadStats(key.to).Thread,
+			Messages:     len(acc.latencies),
+			TotalBytes:   acc.bytes,
+			MaxBytes:     acc.maxBytes,
+			LatencyMinMs: acc.latencies[0],
+			LatencyP50Ms: percentile(acc.latencies, 50),
+			LatencyP95Ms: percentile(acc.latencies, 95),
+			LatencyMaxMs: acc.latencies[len(acc.latencies)-1],
+		}
+		var total float64
+		for _, l := range acc.latencies {
+			total += l
+		}
+		c.LatencyAvgMs = total / float64(len(acc.latencies))
+		if acc.sized > 0 {
+			c.AvgBytes = float64(acc.bytes) / float64(acc.sized)
+		}
+		analysis.TotalBytes += acc.bytes
+		analysis.Channels = append(analysis.Channels, c)
+	}
+	sort.Slice(analysis.Channels, func(i, j int) bool {
+		a, b := analysis.Channels[i], analysis.Channels[j]
+		if a.Messages != b.Messages {
+			return a.Messages > b.Messages
+		}
+		if a.From != b.From {
+			return a.From < b.From
+		}
+		return a.To < b.To
+	})
+	if len(analysis.Channels) > limit {
+		analysis.Channels = analysis.Channels[:limit]
+	}
+
+	for _, s := range threads {
+		analysis.Threads = append(analysis.Threads, *s)
+	}
+	sort.Slice(analysis.Threads, func(i, j int) bool {
+		a, b := analysis.Threads[i], analysis.Threads[j]
+		if a.Sent+a.Received != b.Sent+b.Received {
+			return a.Sent+a.Received > b.Sent+b.Received
+		}
+		return a.Thread < b.Thread
+	})
+
+	analysis.Recommendations = messageFlowRecommendations(analysis)
+	return analysis
+}
+
+// messageFlowRecommendations suggests follow-ups based on message traffic
+func messageFlowRecommendations(analysis MessageFlowAnalysis) []string {
+	var recs []string
+	if analysis.TotalSent == 0 && analysis.TotalReceived == 0 {
+		return append(recs, "No posted messages were found; the profile needs PostMessage markers or message events")
+	}
+
+	for _, c := range analysis.Channels {
+		if c.LatencyP95Ms > MessageSlowLatencyMs {
+			recs = append(recs, fmt.Sprintf("Messages from %s to %s waited %.1fms at p95 before being handled; the receiving thread is busy, so split its work or add more workers", c.From, c.To, c.LatencyP95Ms))
+		}
+		if c.Messages >= MessageChattyCount && c.AvgBytes < MessageLargePayloadBytes {
+			recs = append(recs, fmt.Sprintf("%s posted %d messages to %s; batch small messages to cut per-message overhead", c.From, c.Messages, c.To))
+		}
+		if c.AvgBytes >= MessageLargePayloadBytes {
+			recs = append(recs, fmt.Sprintf("Messages from %s to %s average %.1fMB; transfer ArrayBuffers or use a SharedArrayBuffer instead of structured cloning", c.From, c.To, c.AvgBytes/(1024*1024)))
+		}
+	}
+	if analysis.TotalSent > 0 && float64(analysis.UnpairedSends)/float64(analysis.TotalSent)*100 >= MessageUnpairedMinPercent {
+		recs = append(recs, fmt.Sprintf("%d of %d sent messages have no matching receive; profile the receiving threads too so latency can be measured", analysis.UnpairedSends, analysis.TotalSent))
+	}
+	return recs
+}
+
+// messageFlowNodes assigns a graph node id to every thread that appears in a channel
+func messageFlowNodes(analysis MessageFlowAnalysis) ([]string, map[string]string) {
+	var names []string
+	ids := make(map[string]string)
+	for _, c := range analysis.Channels {
+		for _, name := range []string{c.From, c.To} {
+			if _, ok := ids[name]; !ok {
+				ids[name] = fmt.Sprintf("t%d", len(names))
+				names = append(names, name)
+			}
+		}
+	}
+	return names, ids
+}
+
+// messageChannelLabel is the edge label used in the exported graphs
+func messageChannelLabel(c MessageChannel) string {
+	unit := "msgs"
+	if c.Messages == 1 {
+		unit = "msg"
+	}
+	label := fmt.Sprintf("%d %s, p50 %.1fms", c.Messages, unit, c.LatencyP50Ms)
+	if c.TotalBytes > 0 {
+		label += fmt.Sprintf(", %s", formatMessageBytes(c.TotalBytes))
+	}
+	return label
+}
+
+// formatMessageBytes formats a payload size for graph labels
+func formatMessageBytes(bytes int64) string {
+	switch {
+	case bytes >= 1024*1024:
+		return fmt.Sprintf("%.1f MB", float64(bytes)/(1024*1024))
+	case bytes >= 1024:
+		return fmt.Sprintf("%.1f KB", float64(bytes)/1024)
+	default:
+		return fmt.Sprintf("%d B", bytes)
+	}
+}
+
+// FormatMessageFlowDOT renders the message channels as a Graphviz digraph
+func FormatMessageFlowDOT(analysis MessageFlowAnalysis) string {
+	var sb strings.Builder
+	quote := strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+
+	sb.WriteString("digraph messages {\n")
+	sb.WriteString("  rankdir=LR;\n")
+	sb.WriteString("  node [shape=box];\n")
+	names, ids := messageFlowNodes(analysis)
+	for _, name := range names {
+		sb.WriteString(fmt.Sprintf("  %s [label=\"%s\"];\n", ids[name], quote.Replace(name)))
+	}
+	for _, c := range analysis.Channels {
+		sb.WriteString(fmt.Sprintf("  %s -> %s [label=\"%s\"];\n", ids[c.From], ids[c.To], quote.Replace(messageChannelLabel(c))))
+	}
+	sb.WriteString("}\n")
+	return sb.String()
+}
+
+// FormatMessageFlowMermaid renders the message channels as a Mermaid flowchart
+func FormatMessageFlowMermaid(analysis MessageFlowAnalysis) string {
+	var sb strings.Builder
+	quote := strings.NewReplacer(`"`, "#quot;")
+
+	sb.WriteString("flowchart LR\n")
+	names, ids := messageFlowNodes(analysis)
+	for _, name := range names {
+		sb.WriteString(fmt.Sprintf("  %s[\"%s\"]\n", ids[name], quote.Replace(name)))
+	}
+	for _, c := range analysis.Channels {
+		sb.WriteString(fmt.Sprintf("  %s -->|\"%s\"| %s\n", ids[c.From], quote.Replace(messageChannelLabel(c)), ids[c.To]))
+	}
+	return sb.String()
+}
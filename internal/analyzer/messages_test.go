@@ -0,0 +1,133 @@
+package analyzer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/CedricHerzog/perfowl/internal/testutil"
+)
+
+func TestAnalyzeMessageFlow_EmptyProfile(t *testing.T) {
+	analysis := AnalyzeMessageFlow(testutil.MinimalProfile(), 0)
+	if analysis.TotalSent != 0 || len(analysis.Channels) != 0 || len(analysis.Threads) != 0 {
+		t.Errorf("expected no messages, got %+v", analysis)
+	}
+	if len(analysis.Recommendations) != 1 || !strings.Contains(analysis.Recommendations[0], "No posted messages") {
+		t.Errorf("unexpected recommendations %v", analysis.Recommendations)
+	}
+}
+
+func TestAnalyzeMessageFlow_Channels(t *testing.T) {
+	analysis := AnalyzeMessageFlow(testutil.ProfileWithMessageFlow(), 0)
+
+	if analysis.TotalSent != 5 || analysis.TotalReceived != 4 || analysis.Paired != 4 {
+		t.Errorf("sent/received/paired = %d/%d/%d, want 5/4/4", analysis.TotalSent, analysis.TotalReceived, analysis.Paired)
+	}
+	if analysis.UnpairedSends != 1 || analysis.UnpairedReceives != 0 {
+		t.Errorf("unpaired sends/receives = %d/%d, want 1/0", analysis.UnpairedSends, analysis.UnpairedReceives)
+	}
+	if analysis.TotalBytes != 6244 {
+		t.Errorf("TotalBytes = %d, want 6244", analysis.TotalBytes)
+	}
+	if len(analysis.Channels) != 2 {
+		t.Fatalf("expected 2 channels, got %+v", analysis.Channels)
+	}
+
+	c := analysis.Channels[0]
+	if c.From != "GeckoMain (1)" || c.To != "DOM Worker (2)" || c.Messages != 3 {
+		t.Errorf("first channel = %+v, want 3 messages from GeckoMain (1) to DOM Worker (2)", c)
+	}
+	testutil.AssertFloatApproxEqual(t, c.LatencyMinMs, 1, 0.001)
+	testutil.AssertFloatApproxEqual(t, c.LatencyAvgMs, 8.0/3, 0.001)
+	testutil.AssertFloatApproxEqual(t, c.LatencyP50Ms, 2, 0.001)
+	testutil.AssertFloatApproxEqual(t, c.LatencyP95Ms, 4.7, 0.001)
+	testutil.AssertFloatApproxEqual(t, c.LatencyMaxMs, 5, 0.001)
+	testutil.AssertFloatApproxEqual(t, c.AvgBytes, 2048, 0.001)
+	if c.TotalBytes != 6144 || c.MaxBytes != 3072 {
+		t.Errorf("bytes total/max = %d/%d, want 6144/3072", c.TotalBytes, c.MaxBytes)
+	}
+
+	reply := analysis.Channels[1]
+	if reply.From != "DOM Worker (2)" || reply.Messages != 1 || reply.TotalBytes != 100 {
+		t.Errorf("second channel = %+v, want one 100 byte reply from the worker", reply)
+	}
+	testutil.AssertFloatApproxEqual(t, reply.LatencyP50Ms, 8, 0.001)
+
+	if len(analysis.Threads) != 2 {
+		t.Fatalf("expected 2 threads, got %+v", analysis.Threads)
+	}
+	main := analysis.Threads[0]
+	if main.Thread != "GeckoMain (1)" || !main.IsMain || main.Sent != 4 || main.Received != 1 {
+		t.Errorf("main thread stats = %+v, want 4 sent and 1 received", main)
+	}
+}
+
+func TestAnalyzeMessageFlow_Limit(t *testing.T) {
+	analysis := AnalyzeMessageFlow(testutil.ProfileWithMessageFlow(), 1)
+	if len(analysis.Channels) != 1 {
+		t.Errorf("expected 1 channel, got %d", len(analysis.Channels))
+	}
+	if analysis.TotalBytes != 6244 {
+		t.Errorf("TotalBytes = %d, want totals over all channels", analysis.TotalBytes)
+	}
+}
+
+func TestAnalyzeMessageFlow_ChromeEvents(t *testing.T) {
+	main, mainStrs := testutil.NewMarkerBuilder().
+		AddCustom("WorkerThread::PostMessage", 1, 10, 0, map[string]interface{}{"data": map[string]interface{}{"bytes": float64(4096)}}).
+		Build()
+	worker, workerStrs := testutil.NewMarkerBuilder().
+		AddCustom("HandlePostMessage", 1, 40, 2, nil).
+		Build()
+	profile := testutil.NewProfileBuilder().
+		WithThread(testutil.NewThreadBuilder("CrRendererMain").AsMainThread().WithStringArray(mainStrs).WithMarkers(main).Build()).
+		WithThread(testutil.NewThreadBuilder("DedicatedWorker thread").WithStringArray(workerStrs).WithMarkers(worker).Build()).
+		Build()
+
+	analysis := AnalyzeMessageFlow(profile, 0)
+	if len(analysis.Channels) != 1 {
+		t.Fatalf("expected 1 channel, got %+v", analysis.Channels)
+	}
+	c := analysis.Channels[0]
+	testutil.AssertFloatApproxEqual(t, c.LatencyP95Ms, 30, 0.001)
+	if c.TotalBytes != 4096 {
+		t.Errorf("TotalBytes = %d, want 4096", c.TotalBytes)
+	}
+	found := false
+	for _, r := range analysis.Recommendations {
+		if strings.Contains(r, "waited 30.0ms at p95") {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("expected a queueing latency recommendation, got %v", analysis.Recommendations)
+	}
+}
+
+func TestFormatMessageFlowDOT(t *testing.T) {
+	out := FormatMessageFlowDOT(AnalyzeMessageFlow(testutil.ProfileWithMessageFlow(), 0))
+	for _, want := range []string{
+		"digraph messages {",
+		`t0 [label="GeckoMain (1)"];`,
+		`t1 [label="DOM Worker (2)"];`,
+		`t0 -> t1 [label="3 msgs, p50 2.0ms, 6.0 KB"];`,
+		`t1 -> t0 [label="1 msg, p50 8.0ms, 100 B"];`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("DOT output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestFormatMessageFlowMermaid(t *testing.T) {
+	out := FormatMessageFlowMermaid(AnalyzeMessageFlow(testutil.ProfileWithMessageFlow(), 0))
+	for _, want := range []string{
+		"flowchart LR",
+		`t0["GeckoMain (1)"]`,
+		`t0 -->|"3 msgs, p50 2.0ms, 6.0 KB"| t1`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Mermaid output missing %q:\n%s", want, out)
+		}
+	}
+}
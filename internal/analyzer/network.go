@@ -0,0 +1,582 @@
+package analyzer
+
+import (
+	"fmt"
+	"math"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/CedricHerzog/perfowl/internal/parser"
+)
+
+// Network analysis thresholds
+const (
+	NetworkChainGapMs   = 20.0 // A critical request starting this soon after another ended is treated as its dependent
+	NetworkSlowRequest  = 1000.0
+	networkMaxChains    = 5
+	networkDefaultLimit = 50
+)
+
+// Resource types of network requests
+const (
+	ResourceDocument   = "document"
+	ResourceStylesheet = "stylesheet"
+	ResourceScript     = "script"
+	ResourceFont       = "font"
+	ResourceImage      = "image"
+	ResourceFetch      = "fetch"
+	ResourceOther      = "other"
+)
+
+// NetworkRequest is a reconstructed network request with its phase timings
+type NetworkRequest struct {
+	ID             string  `json:"id"`
+	URL            string  `json:"url"`
+	Domain         string  `json:"domain"`
+	Type           string  `json:"type"`
+	Method         string  `json:"method,omitempty"`
+	Status         int     `json:"status,omitempty"`
+	MimeType       string  `json:"mime_type,omitempty"`
+	Priority       string  `json:"priority,omitempty"`
+	StartTime      float64 `json:"start_time"`
+	EndTime        float64 `json:"end_time"`
+	DurationMs     float64 `json:"duration_ms"`
+	DNSMs          float64 `json:"dns_ms"`
+	ConnectMs      float64 `json:"connect_ms"`
+	TLSMs          float64 `json:"tls_ms"`
+	WaitingMs      float64 `json:"waiting_ms"` // Request sent until the first response byte
+	DownloadMs     float64 `json:"download_ms"`
+	TransferBytes  int64   `json:"transfer_bytes"`
+	FromCache      bool    `json:"from_cache,omitempty"`
+	Failed         bool    `json:"failed,omitempty"`
+	RenderBlocking bool    `json:"render_blocking"`
+	BlockingReason string  `json:"blocking_reason,omitempty"`
+	Initiator      string  `json:"initiator,omitempty"`
+	Thread         string  `json:"thread"`
+}
+
+// NetworkDomainStats aggregates the requests made to one domain
+type NetworkDomainStats struct {
+	Domain         string  `json:"domain"`
+	Requests       int     `json:"requests"`
+	TransferBytes  int64   `json:"transfer_bytes"`
+	TotalTimeMs    float64 `json:"total_time_ms"`
+	AvgDurationMs  float64 `json:"avg_duration_ms"`
+	MaxDurationMs  float64 `json:"max_duration_ms"`
+	RenderBlocking int     `json:"render_blocking"`
+}
+
+// CriticalRequestChain is a sequence of critical requests that each waited on the previous one
+type CriticalRequestChain struct {
+	URLs       []string `json:"urls"`
+	StartTime  float64  `json:"start_time"`
+	EndTime    float64  `json:"end_time"`
+	DurationMs float64  `json:"duration_ms"`
+}
+
+// NetworkAnalysis contains network request waterfall results
+type NetworkAnalysis struct {
+	TotalRequests        int                    `json:"total_requests"`
+	FailedRequests       int                    `json:"failed_requests"`
+	CachedRequests       int                    `json:"cached_requests"`
+	RenderBlockingCount  int                    `json:"render_blocking_count"`
+	TransferBytes        int64                  `json:"transfer_bytes"`
+	StartTime            float64                `json:"start_time"`
+	EndTime              float64                `json:"end_time"`
+	RenderBlockingEndMs  float64                `json:"render_blocking_end_ms,omitempty"` // When the last render-blocking request finished
+	FirstContentfulPaint float64                `json:"first_contentful_paint_ms,omitempty"`
+	LongestChainMs       float64                `json:"longest_chain_ms"`
+	Requests             []NetworkRequest       `json:"requests"`
+	ByDomain             []NetworkDomainStats   `json:"by_domain"`
+	CriticalChains       []CriticalRequestChain `json:"critical_chains"`
+	Recommendations      []string               `json:"recommendations,omitempty"`
+}
+
+// AnalyzeNetwork reconstructs network requests from Firefox Network markers and
+// Chrome ResourceSendRequest, ResourceReceiveResponse and ResourceFinish events.
+// Requests are classified as render-blocking from Chrome's renderBlocking flag, or
+// for stylesheets and scripts requested before first paint, and chained into
+// critical request chains by initiator or by start-after-end timing.
+func AnalyzeNetwork(profile *parser.Profile, limit int) NetworkAnalysis {
+	analysis := NetworkAnalysis{
+		Requests:       make([]NetworkRequest, 0),
+		ByDomain:       make([]NetworkDomainStats, 0),
+		CriticalChains: make([]CriticalRequestChain, 0),
+	}
+
+	if limit <= 0 {
+		limit = networkDefaultLimit
+	}
+
+	requests := ExtractNetworkRequests(profile)
+	if len(requests) == 0 {
+		analysis.Recommendations = networkRecommendations(analysis)
+		return analysis
+	}
+
+	// First paint bounds which stylesheets and scripts could have blocked rendering
+	paintCutoff := math.Inf(1)
+	if navs := ExtractPageLoadMilestones(profile); len(navs) > 0 {
+		for _, name := range []string{MilestoneFirstContentfulPaint, MilestoneFirstPaint, MilestoneDOMContentLoaded} {
+			if m, ok := navs[0].Milestone(name); ok {
+				paintCutoff = m.TimeMs
+				if name == MilestoneFirstContentfulPaint {
+					analysis.FirstContentfulPaint = m.TimeMs
+				}
+				break
+			}
+		}
+	}
+	classifyRenderBlocking(requests, paintCutoff)
+
+	analysis.StartTime = requests[0].StartTime
+	domains := make(map[string]*NetworkDomainStats)
+	for _, r := range requests {
+		analysis.TotalRequests++
+		analysis.TransferBytes += r.TransferBytes
+		analysis.EndTime = math.Max(analysis.EndTime, r.EndTime)
+		if r.Failed {
+			analysis.FailedRequests++
+		}
+		if r.FromCache {
+			analysis.CachedRequests++
+		}
+		if r.RenderBlocking {
+			analysis.RenderBlockingCount++
+			analysis.RenderBlockingEndMs = math.Max(analysis.RenderBlockingEndMs, r.EndTime)
+		}
+
+		ds := domains[r.Domain]
+		if ds == nil {
+			ds = &NetworkDomainStats{Domain: r.Domain}
+			domains[r.Domain] = ds
+		}
+		ds.Requests++
+		ds.TransferBytes += r.TransferBytes
+		ds.TotalTimeMs += r.DurationMs
+		ds.MaxDurationMs = math.Max(ds.MaxDurationMs, r.DurationMs)
+		if r.RenderBlocking {
+			ds.RenderBlocking++
+		}
+	}
+	for _, ds := range domains {
+		ds.AvgDurationMs = ds.TotalTimeMs / float64(ds.Requests)
+		analysis.ByDomain = append(analysis.ByDomain, *ds)
+	}
+	sort.Slice(analysis.ByDomain, func(i, j int) bool {
+		if analysis.ByDomain[i].TotalTimeMs != analysis.ByDomain[j].TotalTimeMs {
+			return analysis.ByDomain[i].TotalTimeMs > analysis.ByDomain[j].TotalTimeMs
+		}
+		return analysis.ByDomain[i].Domain < analysis.ByDomain[j].Domain
+	})
+
+	analysis.CriticalChains = criticalRequestChains(requests)
+	if len(analysis.CriticalChains) > 0 {
+		analysis.LongestChainMs = analysis.CriticalChains[0].DurationMs
+	}
+
+	if len(requests) > limit {
+		requests = requests[:limit]
+	}
+	analysis.Requests = requests
+	analysis.Recommendations = networkRecommendations(analysis)
+
+	return analysis
+}
+
+// ExtractNetworkRequests reconstructs network requests in start order
+func ExtractNetworkRequests(profile *parser.Profile) []NetworkRequest {
+	byID := make(map[string]*NetworkRequest)
+	var order []string
+	get := func(id, thread string) *NetworkRequest {
+		r := byID[id]
+		if r == nil {
+			r = &NetworkRequest{ID: id, Thread: thread, StartTime: math.Inf(1)}
+			byID[id] = r
+			order = append(order, id)
+		}
+		return r
+	}
+
+	// Chrome marks the times of receive and finish events, which bound the download phase
+	responseAt := make(map[string]float64)
+
+	for i := range profile.Threads {
+		thread := &profile.Threads[i]
+		for _, m := range parser.ExtractMarkers(thread, profile.Meta.Categories) {
+			switch {
+			case m.Name == "ResourceSendRequest" || m.Name == "ResourceReceiveResponse" || m.Name == "ResourceFinish":
+				data := milestoneData(m.Data)
+				id, _ := data["requestId"].(string)
+				if id == "" {
+					continue
+				}
+				r := get("chrome:"+id, thread.Name)
+				applyChromeResourceEvent(r, m, data, responseAt)
+			case m.Type == "Network" || strings.HasPrefix(m.Name, "Load "):
+				uri, _ := m.Data["URI"].(string)
+				if uri == "" {
+					continue
+				}
+				id := fmt.Sprintf("firefox:%v", m.Data["id"])
+				if m.Data["id"] == nil {
+					id = fmt.Sprintf("firefox:%s@%.3f", uri, m.StartTime)
+				}
+				applyFirefoxNetworkMarker(get(id, thread.Name), m)
+			}
+		}
+	}
+
+	requests := make([]NetworkRequest, 0, len(order))
+	for _, id := range order {
+		r := byID[id]
+		if r.URL == "" || math.IsInf(r.StartTime, 1) {
+			continue
+		}
+		if r.EndTime < r.StartTime {
+			r.EndTime = r.StartTime
+		}
+		r.DurationMs = r.EndTime - r.StartTime
+		r.Domain = requestDomain(r.URL)
+		r.Type = requestResourceType(r.URL, r.MimeType)
+		requests = append(requests, *r)
+	}
+	sort.SliceStable(requests, func(i, j int) bool { return requests[i].StartTime < requests[j].StartTime })
+	return requests
+}
+
+// applyFirefoxNetworkMarker merges a Firefox Network marker into its request.
+// Firefox records one marker when the request starts and one when it stops;
+// only the stop marker carries the phase timings.
+func applyFirefoxNetworkMarker(r *NetworkRequest, m parser.ParsedMarker) {
+	d := m.Data
+	num := func(key string) float64 {
+		v, _ := d[key].(float64)
+		return v
+	}
+	phase := func(start, end string) float64 {
+		s, e := num(start), num(end)
+		if s <= 0 || e < s {
+			return 0
+		}
+		return e - s
+	}
+
+	r.URL, _ = d["URI"].(string)
+	start, end := m.StartTime, m.EndTime
+	if v := num("startTime"); v > 0 {
+		start = v
+	}
+	if v := num("endTime"); v > 0 {
+		end = v
+	}
+	r.StartTime = math.Min(r.StartTime, start)
+	r.EndTime = math.Max(r.EndTime, end)
+
+	if method, ok := d["requestMethod"].(string); ok {
+		r.Method = method
+	}
+	if ct, ok := d["contentType"].(string); ok && ct != "" {
+		r.MimeType = ct
+	}
+	if status := num("responseStatus"); status > 0 {
+		r.Status = int(status)
+	}
+	if pri := num("pri"); pri != 0 {
+		r.Priority = fmt.Sprintf("%.0f", pri)
+	}
+	if count := num("count"); count > 0 {
+		r.TransferBytes = int64(count)
+	}
+	if cache, ok := d["cache"].(string); ok && strings.Contains(cache, "Hit") {
+		r.FromCache = true
+	}
+	if status, ok := d["status"].(string); ok && status == "STATUS_CANCEL" {
+		r.Failed = true
+	}
+
+	if dns := phase("domainLookupStart", "domainLookupEnd"); dns > 0 {
+		r.DNSMs = dns
+	}
+	if tls := phase("secureConnectionStart", "connectEnd"); tls > 0 {
+		r.TLSMs = tls
+	}
+	if connect := phase("connectStart", "tcpConnectEnd"); connect > 0 {
+		r.ConnectMs = connect
+	} else if connect := phase("connectStart", "connectEnd"); connect > 0 {
+		r.ConnectMs = connect - r.TLSMs
+	}
+	if waiting := phase("requestStart", "responseStart"); waiting > 0 {
+		r.WaitingMs = waiting
+	}
+	if download := phase("responseStart", "responseEnd"); download > 0 {
+		r.DownloadMs = download
+	}
+}
+
+// applyChromeResourceEvent merges a Chrome resource event into its request
+func applyChromeResourceEvent(r *NetworkRequest, m parser.ParsedMarker, data map[string]interface{}, responseAt map[string]float64) {
+	num := func(obj map[string]interface{}, key string) float64 {
+		v, _ := obj[key].(float64)
+		return v
+	}
+
+	switch m.Name {
+	case "ResourceSendRequest":
+		// Redirects send again under the same request ID; keep the first send
+		if m.StartTime < r.StartTime {
+			r.StartTime = m.StartTime
+		}
+		if r.URL == "" {
+			r.URL, _ = data["url"].(string)
+		}
+		r.Method, _ = data["requestMethod"].(string)
+		r.Priority, _ = data["priority"].(string)
+		switch blocking, _ := data["renderBlocking"].(string); blocking {
+		case "blocking", "in_body_parser_blocking":
+			r.RenderBlocking = true
+			r.BlockingReason = "marked " + blocking + " by Chrome"
+		}
+		if initiator, ok := data["initiator"].(map[string]interface{}); ok {
+			r.Initiator, _ = initiator["url"].(string)
+		}
+	case "ResourceReceiveResponse":
+		responseAt[r.ID] = m.StartTime
+		r.EndTime = math.Max(r.EndTime, m.StartTime)
+		r.Status = int(num(data, "statusCode"))
+		r.MimeType, _ = data["mimeType"].(string)
+		if cached, _ := data["fromCache"].(bool); cached {
+			r.FromCache = true
+		}
+		if timing, ok := data["timing"].(map[string]interface{}); ok {
+			span := func(start, end string) float64 {
+				s, e := num(timing, start), num(timing, end)
+				if s < 0 || e < s {
+					return 0
+				}
+				return e - s
+			}
+			r.DNSMs = span("dnsStart", "dnsEnd")
+			r.TLSMs = span("sslStart", "sslEnd")
+			r.ConnectMs = math.Max(0, span("connectStart", "connectEnd")-r.TLSMs)
+			r.WaitingMs = span("sendEnd", "receiveHeadersEnd")
+		}
+	case "ResourceFinish":
+		r.EndTime = math.Max(r.EndTime, m.StartTime)
+		if n := num(data, "encodedDataLength"); n > 0 {
+			r.TransferBytes = int64(n)
+		}
+		if failed, _ := data["didFail"].(bool); failed {
+			r.Failed = true
+		}
+		if at, ok := responseAt[r.ID]; ok && m.StartTime > at {
+			r.DownloadMs = m.StartTime - at
+		}
+	}
+}
+
+// classifyRenderBlocking marks stylesheets and scripts requested before first paint
+// as render-blocking when the browser did not say so itself
+func classifyRenderBlocking(requests []NetworkRequest, paintCutoff float64) {
+	for i := range requests {
+		r := &requests[i]
+		if r.RenderBlocking || r.StartTime >= paintCutoff || r.Failed {
+			continue
+		}
+		switch r.Type {
+		case ResourceStylesheet:
+			r.RenderBlocking = true
+			r.BlockingReason = "stylesheet requested before first paint"
+		case ResourceScript:
+			if !math.IsInf(paintCutoff, 1) {
+				r.RenderBlocking = true
+				r.BlockingReason = "script requested before first paint"
+			}
+		}
+	}
+}
+
+// isCriticalRequest reports whether a request can be on the critical rendering path
+func isCriticalRequest(r NetworkRequest) bool {
+	if r.RenderBlocking {
+		return true
+	}
+	switch r.Type {
+	case ResourceDocument, ResourceStylesheet, ResourceFont:
+		return true
+	case ResourceScript:
+		return !isLowPriority(r.Priority)
+	}
+	return false
+}
+
+// isLowPriority reports whether a Chrome priority name or Firefox priority value
+// marks a request the browser did not need for the first render
+func isLowPriority(priority string) bool {
+	switch priority {
+	case "Low", "VeryLow", "Lowest", "Idle":
+		return true
+	}
+	var pri float64
+	if _, err := fmt.Sscanf(priority, "%g", &pri); err == nil {
+		return pri > 0 // Firefox: negative values are more urgent than normal (0)
+	}
+	return false
+}
+
+// criticalRequestChains links critical requests to the request that initiated them,
+// by initiator URL when known and otherwise to the critical request that finished
+// just before they started, and returns the longest root-to-leaf chains
+func criticalRequestChains(requests []NetworkRequest) []CriticalRequestChain {
+	var critical []int
+	for i, r := range requests {
+		if isCriticalRequest(r) && !r.Failed {
+			critical = append(critical, i)
+		}
+	}
+
+	parent := make(map[int]int)
+	hasChild := make(map[int]bool)
+	for _, b := range critical {
+		rb := requests[b]
+		best := -1
+		for _, a := range critical {
+			if a == b {
+				continue
+			}
+			ra := requests[a]
+			if rb.Initiator != "" {
+				if ra.URL == rb.Initiator && ra.StartTime <= rb.StartTime {
+					best = a
+					break
+				}
+				continue
+			}
+			if ra.EndTime <= rb.StartTime && rb.StartTime-ra.EndTime <= NetworkChainGapMs {
+				if best < 0 || ra.EndTime > requests[best].EndTime {
+					best = a
+				}
+			}
+		}
+		if best >= 0 {
+			parent[b] = best
+			hasChild[best] = true
+		}
+	}
+
+	chains := make([]CriticalRequestChain, 0)
+	for _, leaf := range critical {
+		if hasChild[leaf] {
+			continue
+		}
+		if _, ok := parent[leaf]; !ok {
+			continue
+		}
+		var path []int
+		seen := make(map[int]bool)
+		for cur, ok := leaf, true; ok && !seen[cur]; cur, ok = parent[cur] {
+			seen[cur] = true
+			path = append([]int{cur}, path...)
+		}
+		chain := CriticalRequestChain{
+			URLs:      make([]string, 0, len(path)),
+			StartTime: requests[path[0]].StartTime,
+			EndTime:   requests[leaf].EndTime,
+		}
+		for _, idx := range path {
+			chain.URLs = append(chain.URLs, requests[idx].URL)
+		}
+		chain.DurationMs = chain.EndTime - chain.StartTime
+		chains = append(chains, chain)
+	}
+
+	sort.SliceStable(chains, func(i, j int) bool {
+		if chains[i].DurationMs != chains[j].DurationMs {
+			return chains[i].DurationMs > chains[j].DurationMs
+		}
+		return len(chains[i].URLs) > len(chains[j].URLs)
+	})
+	if len(chains) > networkMaxChains {
+		chains = chains[:networkMaxChains]
+	}
+	return chains
+}
+
+// requestDomain returns the host of a request URL
+func requestDomain(rawURL string) string {
+	if u, err := url.Parse(rawURL); err == nil && u.Host != "" {
+		return u.Hostname()
+	}
+	return "(unknown)"
+}
+
+// requestResourceType guesses the resource type from the MIME type or URL extension
+func requestResourceType(rawURL, mimeType string) string {
+	mime := strings.ToLower(mimeType)
+	switch {
+	case strings.Contains(mime, "html"):
+		return ResourceDocument
+	case strings.Contains(mime, "css"):
+		return ResourceStylesheet
+	case strings.Contains(mime, "javascript") || strings.Contains(mime, "ecmascript"):
+		return ResourceScript
+	case strings.HasPrefix(mime, "font/") || strings.Contains(mime, "font"):
+		return ResourceFont
+	case strings.HasPrefix(mime, "image/"):
+		return ResourceImage
+	case strings.Contains(mime, "json"):
+		return ResourceFetch
+	}
+
+	p := rawURL
+	if u, err := url.Parse(rawURL); err == nil {
+		p = u.Path
+	}
+	switch strings.ToLower(path.Ext(p)) {
+	case ".html", ".htm":
+		return ResourceDocument
+	case ".css":
+		return ResourceStylesheet
+	case ".js", ".mjs":
+		return ResourceScript
+	case ".woff", ".woff2", ".ttf", ".otf":
+		return ResourceFont
+	case ".png", ".jpg", ".jpeg", ".gif", ".webp", ".avif", ".svg", ".ico":
+		return ResourceImage
+	case ".json":
+		return ResourceFetch
+	case "":
+		if p == "" || strings.HasSuffix(p, "/") {
+			return ResourceDocument
+		}
+	}
+	return ResourceOther
+}
+
+// networkRecommendations suggests follow-ups based on network results
+func networkRecommendations(analysis NetworkAnalysis) []string {
+	var recs []string
+	if analysis.TotalRequests == 0 {
+		return append(recs, "No network requests were recorded; enable network markers (Firefox) or the devtools.timeline category (Chrome)")
+	}
+	if analysis.RenderBlockingCount > 0 {
+		recs = append(recs, fmt.Sprintf("%d render-blocking requests finished by %.0fms; inline critical CSS and defer or async non-critical scripts",
+			analysis.RenderBlockingCount, analysis.RenderBlockingEndMs))
+	}
+	if len(analysis.CriticalChains) > 0 && len(analysis.CriticalChains[0].URLs) > 2 {
+		recs = append(recs, fmt.Sprintf("The longest critical request chain has %d requests over %.0fms; preload late-discovered resources",
+			len(analysis.CriticalChains[0].URLs), analysis.LongestChainMs))
+	}
+	if analysis.FailedRequests > 0 {
+		recs = append(recs, fmt.Sprintf("%d requests failed or were cancelled", analysis.FailedRequests))
+	}
+	for _, ds := range analysis.ByDomain {
+		if ds.MaxDurationMs > NetworkSlowRequest {
+			recs = append(recs, fmt.Sprintf("Requests to %s took up to %.0fms; check server latency or use a preconnect hint", ds.Domain, ds.MaxDurationMs))
+			break
+		}
+	}
+	return recs
+}
@@ -0,0 +1,155 @@
+package analyzer
+
+import (
+	"testing"
+
+	"github.com/CedricHerzog/perfowl/internal/testutil"
+)
+
+func TestExtractNetworkRequests_FirefoxPhases(t *testing.T) {
+	requests := ExtractNetworkRequests(testutil.ProfileWithNetwork())
+
+	if len(requests) != 6 {
+		t.Fatalf("expected 6 requests (start and stop markers merged), got %d", len(requests))
+	}
+
+	doc := requests[0]
+	if doc.URL != "https://example.com/" || doc.Type != ResourceDocument || doc.Status != 200 {
+		t.Errorf("document = %s (%s, %d), want https://example.com/ (document, 200)", doc.URL, doc.Type, doc.Status)
+	}
+	testutil.AssertFloatApproxEqual(t, doc.DurationMs, 190, 0.01)
+	testutil.AssertFloatApproxEqual(t, doc.DNSMs, 20, 0.01)
+	testutil.AssertFloatApproxEqual(t, doc.ConnectMs, 20, 0.01)
+	testutil.AssertFloatApproxEqual(t, doc.TLSMs, 30, 0.01)
+	testutil.AssertFloatApproxEqual(t, doc.WaitingMs, 70, 0.01)
+	testutil.AssertFloatApproxEqual(t, doc.DownloadMs, 50, 0.01)
+	if doc.TransferBytes != 5000 {
+		t.Errorf("TransferBytes = %d, want 5000", doc.TransferBytes)
+	}
+}
+
+func TestAnalyzeNetwork_RenderBlockingAndChains(t *testing.T) {
+	analysis := AnalyzeNetwork(testutil.ProfileWithNetwork(), 50)
+
+	if analysis.TotalRequests != 6 || analysis.FailedRequests != 1 {
+		t.Errorf("requests = %d (%d failed), want 6 (1 failed)", analysis.TotalRequests, analysis.FailedRequests)
+	}
+	testutil.AssertFloatApproxEqual(t, analysis.FirstContentfulPaint, 600, 0.01)
+
+	blocking := make(map[string]bool)
+	for _, r := range analysis.Requests {
+		if r.RenderBlocking {
+			blocking[r.URL] = true
+		}
+	}
+	if len(blocking) != 2 || !blocking["https://example.com/app.css"] || !blocking["https://cdn.example.org/lib.js"] {
+		t.Errorf("render-blocking = %v, want app.css and lib.js", blocking)
+	}
+	testutil.AssertFloatApproxEqual(t, analysis.RenderBlockingEndMs, 300, 0.01)
+
+	if len(analysis.CriticalChains) != 1 {
+		t.Fatalf("expected 1 critical chain, got %+v", analysis.CriticalChains)
+	}
+	chain := analysis.CriticalChains[0]
+	if len(chain.URLs) != 3 || chain.URLs[0] != "https://example.com/" || chain.URLs[2] != "https://fonts.example.net/font.woff2" {
+		t.Errorf("chain = %v, want document -> app.css -> font", chain.URLs)
+	}
+	testutil.AssertFloatApproxEqual(t, analysis.LongestChainMs, 440, 0.01)
+}
+
+func TestAnalyzeNetwork_ByDomain(t *testing.T) {
+	analysis := AnalyzeNetwork(testutil.ProfileWithNetwork(), 2)
+
+	if len(analysis.Requests) != 2 {
+		t.Errorf("expected requests limited to 2, got %d", len(analysis.Requests))
+	}
+	if len(analysis.ByDomain) != 4 {
+		t.Fatalf("expected 4 domains, got %d", len(analysis.ByDomain))
+	}
+	top := analysis.ByDomain[0]
+	if top.Domain != "example.com" || top.Requests != 3 || top.RenderBlocking != 1 {
+		t.Errorf("top domain = %+v, want example.com with 3 requests, 1 blocking", top)
+	}
+	testutil.AssertFloatApproxEqual(t, top.TotalTimeMs, 1480, 0.01)
+}
+
+func TestAnalyzeNetwork_ChromeResourceEvents(t *testing.T) {
+	send := func(id, url, blocking string) map[string]interface{} {
+		return map[string]interface{}{"data": map[string]interface{}{
+			"requestId": id, "url": url, "requestMethod": "GET", "priority": "VeryHigh", "renderBlocking": blocking,
+		}}
+	}
+	mb := testutil.NewMarkerBuilder().
+		AddCustom("ResourceSendRequest", 7, 100, 0, send("1.1", "https://example.com/style.css", "blocking")).
+		AddCustom("ResourceReceiveResponse", 7, 180, 0, map[string]interface{}{"data": map[string]interface{}{
+			"requestId": "1.1", "statusCode": 200.0, "mimeType": "text/css",
+			"timing": map[string]interface{}{
+				"dnsStart": 0.0, "dnsEnd": 10.0, "connectStart": 10.0, "connectEnd": 40.0, "sslStart": 20.0, "sslEnd": 40.0,
+				"sendStart": 41.0, "sendEnd": 42.0, "receiveHeadersEnd": 75.0,
+			},
+		}}).
+		AddCustom("ResourceFinish", 7, 220, 0, map[string]interface{}{"data": map[string]interface{}{
+			"requestId": "1.1", "encodedDataLength": 1234.0, "didFail": false,
+		}}).
+		AddCustom("ResourceSendRequest", 7, 150, 0, send("1.2", "https://example.com/app.js", "non_blocking")).
+		AddCustom("ResourceFinish", 7, 160, 0, map[string]interface{}{"data": map[string]interface{}{
+			"requestId": "1.2", "didFail": true,
+		}})
+	markers, strs := mb.Build()
+
+	profile := testutil.NewProfileBuilder().
+		WithCategories(testutil.DefaultCategories()).
+		WithThread(testutil.NewThreadBuilder("CrRendererMain").
+			AsMainThread().
+			WithStringArray(strs).
+			WithMarkers(markers).
+			Build()).
+		Build()
+
+	analysis := AnalyzeNetwork(profile, 10)
+
+	if analysis.TotalRequests != 2 || analysis.FailedRequests != 1 {
+		t.Fatalf("requests = %d (%d failed), want 2 (1 failed)", analysis.TotalRequests, analysis.FailedRequests)
+	}
+	css := analysis.Requests[0]
+	if !css.RenderBlocking || css.Type != ResourceStylesheet || css.TransferBytes != 1234 {
+		t.Errorf("stylesheet = %+v, want render-blocking stylesheet of 1234 bytes", css)
+	}
+	testutil.AssertFloatApproxEqual(t, css.DurationMs, 120, 0.01)
+	testutil.AssertFloatApproxEqual(t, css.TLSMs, 20, 0.01)
+	testutil.AssertFloatApproxEqual(t, css.ConnectMs, 10, 0.01)
+	testutil.AssertFloatApproxEqual(t, css.WaitingMs, 33, 0.01)
+	testutil.AssertFloatApproxEqual(t, css.DownloadMs, 40, 0.01)
+	if analysis.Requests[1].RenderBlocking {
+		t.Error("non_blocking script should not be render-blocking")
+	}
+}
+
+func TestAnalyzeNetwork_NoRequests(t *testing.T) {
+	analysis := AnalyzeNetwork(testutil.MinimalProfile(), 10)
+
+	if analysis.TotalRequests != 0 || len(analysis.Requests) != 0 {
+		t.Errorf("expected no requests, got %d", analysis.TotalRequests)
+	}
+	if len(analysis.Recommendations) == 0 {
+		t.Error("expected a recommendation about missing network data")
+	}
+}
+
+func TestRequestResourceType(t *testing.T) {
+	tests := []struct {
+		url, mime, want string
+	}{
+		{"https://example.com/", "", ResourceDocument},
+		{"https://example.com/a.css?v=1", "", ResourceStylesheet},
+		{"https://example.com/data", "application/json", ResourceFetch},
+		{"https://example.com/x", "text/javascript; charset=utf-8", ResourceScript},
+		{"https://example.com/logo.svg", "", ResourceImage},
+		{"https://example.com/blob.bin", "", ResourceOther},
+	}
+	for _, tt := range tests {
+		if got := requestResourceType(tt.url, tt.mime); got != tt.want {
+			t.Errorf("requestResourceType(%q, %q) = %s, want %s", tt.url, tt.mime, got, tt.want)
+		}
+	}
+}
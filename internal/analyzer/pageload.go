@@ -0,0 +1,282 @@
+package analyzer
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/CedricHerzog/perfowl/internal/parser"
+)
+
+// Page-load milestone names
+const (
+	MilestoneNavigationStart        = "navigationStart"
+	MilestoneFirstPaint             = "firstPaint"
+	MilestoneFirstContentfulPaint   = "firstContentfulPaint"
+	MilestoneDOMContentLoaded       = "domContentLoaded"
+	MilestoneLargestContentfulPaint = "largestContentfulPaint"
+	MilestoneLoad                   = "load"
+)
+
+// PageLoadMarkerType is the delimiter type of page-load milestone anchors
+const PageLoadMarkerType = "PageLoad"
+
+// milestoneMarkers maps Chrome trace events and Firefox markers to milestones
+var milestoneMarkers = map[string]string{
+	"navigationStart":                   MilestoneNavigationStart, // Chrome blink.user_timing
+	"Navigation::Start":                 MilestoneNavigationStart, // Firefox
+	"firstPaint":                        MilestoneFirstPaint,      // Chrome loading
+	"FirstNonBlankPaint":                MilestoneFirstPaint,      // Firefox
+	"firstContentfulPaint":              MilestoneFirstContentfulPaint,
+	"FirstContentfulPaint":              MilestoneFirstContentfulPaint,
+	"domContentLoadedEventStart":        MilestoneDOMContentLoaded, // Chrome blink.user_timing
+	"MarkDOMContent":                    MilestoneDOMContentLoaded, // Chrome devtools.timeline
+	"Navigation::DOMContentLoaded":      MilestoneDOMContentLoaded, // Firefox
+	"largestContentfulPaint::Candidate": MilestoneLargestContentfulPaint,
+	"LargestContentfulPaint":            MilestoneLargestContentfulPaint,
+	"loadEventStart":                    MilestoneLoad, // Chrome blink.user_timing
+	"MarkLoad":                          MilestoneLoad, // Chrome devtools.timeline
+	"Navigation::Load":                  MilestoneLoad, // Firefox
+	"DocumentLoad":                      MilestoneLoad, // Firefox
+}
+
+// milestoneAliases maps the short names accepted as anchors to milestones
+var milestoneAliases = map[string]string{
+	"nav":                    MilestoneNavigationStart,
+	"navigationstart":        MilestoneNavigationStart,
+	"fp":                     MilestoneFirstPaint,
+	"firstpaint":             MilestoneFirstPaint,
+	"fcp":                    MilestoneFirstContentfulPaint,
+	"firstcontentfulpaint":   MilestoneFirstContentfulPaint,
+	"dcl":                    MilestoneDOMContentLoaded,
+	"domcontentloaded":       MilestoneDOMContentLoaded,
+	"lcp":                    MilestoneLargestContentfulPaint,
+	"largestcontentfulpaint": MilestoneLargestContentfulPaint,
+	"load":                   MilestoneLoad,
+}
+
+// PageLoadMilestone is one milestone of a navigation
+type PageLoadMilestone struct {
+	Name              string  `json:"name"`
+	TimeMs            float64 `json:"time_ms"`
+	SinceNavigationMs float64 `json:"since_navigation_ms"`
+	Source            string  `json:"source"`
+	Thread            string  `json:"thread,omitempty"`
+}
+
+// Navigation is a page load and the milestones it reached
+type Navigation struct {
+	Index        int                 `json:"index"`
+	URL          string              `json:"url,omitempty"`
+	NavigationID string              `json:"navigation_id,omitempty"`
+	StartTime    float64             `json:"start_time"`
+	Implicit     bool                `json:"implicit,omitempty"` // No navigation start was recorded; times are from profile start
+	Milestones   []PageLoadMilestone `json:"milestones"`
+}
+
+// Milestone returns the named milestone of a navigation
+func (n Navigation) Milestone(name string) (PageLoadMilestone, bool) {
+	for _, m := range n.Milestones {
+		if m.Name == name {
+			return m, true
+		}
+	}
+	return PageLoadMilestone{}, false
+}
+
+// milestoneEvent is a milestone marker before it is assigned to a navigation
+type milestoneEvent struct {
+	name   string
+	source string
+	time   float64
+	thread string
+	navID  string
+	url    string
+}
+
+// ExtractPageLoadMilestones finds navigation start, first paint, first contentful
+// paint, DOMContentLoaded, largest contentful paint and load for each navigation.
+// Milestones are matched to navigations by Chrome navigation ID when present and
+// otherwise to the latest navigation that started before them. LCP is the last
+// candidate reported for a navigation; the other milestones are the first one seen.
+func ExtractPageLoadMilestones(profile *parser.Profile) []Navigation {
+	var events []milestoneEvent
+	for i := range profile.Threads {
+		thread := &profile.Threads[i]
+		for _, m := range parser.ExtractMarkers(thread, profile.Meta.Categories) {
+			name := milestoneMarkers[m.Name]
+			if name == "" && m.Name == "DOMEvent" {
+				if et, _ := m.Data["eventType"].(string); et == "DOMContentLoaded" {
+					name = MilestoneDOMContentLoaded
+				}
+			}
+			if name == "" {
+				continue
+			}
+			data := milestoneData(m.Data)
+			if name == MilestoneNavigationStart && isSubframeNavigation(data) {
+				continue
+			}
+			ev := milestoneEvent{
+				name:   name,
+				source: m.Name,
+				time:   m.StartTime,
+				thread: thread.Name,
+			}
+			// Firefox records DocumentLoad, first paints and the Navigation:: milestones
+			// as intervals from navigation start; the milestone is where they end
+			if name != MilestoneNavigationStart && m.EndTime > m.StartTime {
+				ev.time = m.EndTime
+			}
+			ev.navID, _ = data["navigationId"].(string)
+			ev.url, _ = data["documentLoaderURL"].(string)
+			if ev.url == "" {
+				ev.url, _ = data["url"].(string)
+			}
+			events = append(events, ev)
+		}
+	}
+	if len(events) == 0 {
+		return make([]Navigation, 0)
+	}
+	sort.SliceStable(events, func(i, j int) bool { return events[i].time < events[j].time })
+
+	var navs []Navigation
+	for _, ev := range events {
+		if ev.name != MilestoneNavigationStart {
+			continue
+		}
+		navs = append(navs, Navigation{
+			URL:          ev.url,
+			NavigationID: ev.navID,
+			StartTime:    ev.time,
+			Milestones: []PageLoadMilestone{{
+				Name:   MilestoneNavigationStart,
+				TimeMs: ev.time,
+				Source: ev.source,
+				Thread: ev.thread,
+			}},
+		})
+	}
+
+	for _, ev := range events {
+		if ev.name == MilestoneNavigationStart {
+			continue
+		}
+		idx := milestoneNavigation(navs, ev)
+		if idx < 0 {
+			navs = append([]Navigation{{Implicit: true, Milestones: make([]PageLoadMilestone, 0)}}, navs...)
+			idx = 0
+		}
+		nav := &navs[idx]
+		milestone := PageLoadMilestone{
+			Name:              ev.name,
+			TimeMs:            ev.time,
+			SinceNavigationMs: ev.time - nav.StartTime,
+			Source:            ev.source,
+			Thread:            ev.thread,
+		}
+		replaced := false
+		for i := range nav.Milestones {
+			if nav.Milestones[i].Name != ev.name {
+				continue
+			}
+			// Later LCP candidates supersede earlier ones
+			if ev.name == MilestoneLargestContentfulPaint {
+				nav.Milestones[i] = milestone
+			}
+			replaced = true
+			break
+		}
+		if !replaced {
+			nav.Milestones = append(nav.Milestones, milestone)
+		}
+	}
+
+	// Drop the empty about:blank navigations browsers start with
+	result := make([]Navigation, 0, len(navs))
+	for _, nav := range navs {
+		if len(nav.Milestones) == 1 && (nav.URL == "" || nav.URL == "about:blank") && len(navs) > 1 {
+			continue
+		}
+		sort.SliceStable(nav.Milestones, func(i, j int) bool {
+			return nav.Milestones[i].TimeMs < nav.Milestones[j].TimeMs
+		})
+		nav.Index = len(result)
+		result = append(result, nav)
+	}
+	return result
+}
+
+// milestoneNavigation returns the navigation a milestone belongs to, or -1
+func milestoneNavigation(navs []Navigation, ev milestoneEvent) int {
+	if ev.navID != "" {
+		for i := range navs {
+			if navs[i].NavigationID == ev.navID {
+				return i
+			}
+		}
+	}
+	idx := -1
+	for i := range navs {
+		if navs[i].StartTime <= ev.time {
+			idx = i
+		}
+	}
+	return idx
+}
+
+// milestoneData returns the event payload, unwrapping Chrome's nested "data" object
+func milestoneData(data map[string]interface{}) map[string]interface{} {
+	if nested, ok := data["data"].(map[string]interface{}); ok {
+		return nested
+	}
+	if data == nil {
+		return map[string]interface{}{}
+	}
+	return data
+}
+
+// isSubframeNavigation reports whether a Chrome navigationStart belongs to an iframe
+func isSubframeNavigation(data map[string]interface{}) bool {
+	if main, ok := data["isOutermostMainFrame"].(bool); ok {
+		return !main
+	}
+	if main, ok := data["isLoadingMainFrame"].(bool); ok {
+		return !main
+	}
+	return false
+}
+
+// ResolveMilestoneAnchor returns the milestone named by an anchor pattern such as
+// "FCP", "LCP", "DCL", "navigationStart" or "PageLoad:load". Callers matching
+// markers should prefer a marker that matches a bare alias literally.
+func ResolveMilestoneAnchor(pattern string) (string, bool) {
+	p := strings.TrimSpace(pattern)
+	if prefix := PageLoadMarkerType + ":"; len(p) > len(prefix) && strings.EqualFold(p[:len(prefix)], prefix) {
+		name, ok := milestoneAliases[strings.ToLower(p[len(prefix):])]
+		return name, ok
+	}
+	name, ok := milestoneAliases[strings.ToLower(p)]
+	return name, ok
+}
+
+// milestoneDelimiters converts page-load milestones into delimiter markers
+func milestoneDelimiters(navs []Navigation) []DelimiterMarker {
+	var markers []DelimiterMarker
+	for _, nav := range navs {
+		for _, m := range nav.Milestones {
+			markers = append(markers, DelimiterMarker{
+				TimeMs:   m.TimeMs,
+				Name:     m.Name,
+				Type:     PageLoadMarkerType,
+				Category: "PageLoad",
+				Thread:   m.Thread,
+				Data: map[string]interface{}{
+					"navigation": nav.Index,
+					"source":     m.Source,
+				},
+			})
+		}
+	}
+	return markers
+}
@@ -0,0 +1,106 @@
+package analyzer
+
+import (
+	"testing"
+
+	"github.com/CedricHerzog/perfowl/internal/testutil"
+)
+
+func TestExtractPageLoadMilestones_Chrome(t *testing.T) {
+	navs := ExtractPageLoadMilestones(testutil.ProfileWithPageLoad())
+
+	// The empty about:blank navigation and the iframe navigation are dropped
+	if len(navs) != 1 {
+		t.Fatalf("expected 1 navigation, got %d: %+v", len(navs), navs)
+	}
+	nav := navs[0]
+	if nav.URL != "https://example.com/" || nav.NavigationID != "nav-1" {
+		t.Errorf("navigation = %s (%s), want https://example.com/ (nav-1)", nav.URL, nav.NavigationID)
+	}
+
+	want := []struct {
+		name  string
+		since float64
+	}{
+		{MilestoneNavigationStart, 0},
+		{MilestoneFirstPaint, 250},
+		{MilestoneFirstContentfulPaint, 260},
+		{MilestoneDOMContentLoaded, 400},
+		{MilestoneLargestContentfulPaint, 700},
+		{MilestoneLoad, 800},
+	}
+	if len(nav.Milestones) != len(want) {
+		t.Fatalf("expected %d milestones, got %d: %+v", len(want), len(nav.Milestones), nav.Milestones)
+	}
+	for i, w := range want {
+		m := nav.Milestones[i]
+		if m.Name != w.name {
+			t.Errorf("milestone %d = %s, want %s", i, m.Name, w.name)
+		}
+		testutil.AssertFloatApproxEqual(t, m.SinceNavigationMs, w.since, 0.01)
+	}
+
+	lcp, ok := nav.Milestone(MilestoneLargestContentfulPaint)
+	if !ok || lcp.Source != "largestContentfulPaint::Candidate" {
+		t.Errorf("LCP = %+v, want the last candidate", lcp)
+	}
+}
+
+func TestExtractPageLoadMilestones_FirefoxImplicitNavigation(t *testing.T) {
+	mb := testutil.NewMarkerBuilder().
+		AddCustom("FirstContentfulPaint", 4, 120, 0, nil).
+		AddDOMEvent("DOMContentLoaded", 200).
+		AddDOMEvent("click", 250).
+		AddCustom("DocumentLoad", 5, 30, 370, nil) // Interval from navigation start to load
+	markers, strs := mb.Build()
+
+	profile := testutil.NewProfileBuilder().
+		WithCategories(testutil.DefaultCategories()).
+		WithThread(testutil.NewThreadBuilder("GeckoMain").
+			AsMainThread().
+			WithStringArray(strs).
+			WithMarkers(markers).
+			Build()).
+		Build()
+
+	navs := ExtractPageLoadMilestones(profile)
+
+	if len(navs) != 1 || !navs[0].Implicit {
+		t.Fatalf("expected one implicit navigation, got %+v", navs)
+	}
+	if len(navs[0].Milestones) != 3 {
+		t.Errorf("expected FCP, DCL and load, got %+v", navs[0].Milestones)
+	}
+	if m, ok := navs[0].Milestone(MilestoneLoad); !ok || m.SinceNavigationMs != 400 {
+		t.Errorf("load = %+v, want 400ms from profile start", m)
+	}
+}
+
+func TestExtractPageLoadMilestones_None(t *testing.T) {
+	if navs := ExtractPageLoadMilestones(testutil.MinimalProfile()); len(navs) != 0 {
+		t.Errorf("expected no navigations, got %d", len(navs))
+	}
+}
+
+func TestResolveMilestoneAnchor(t *testing.T) {
+	tests := []struct {
+		pattern string
+		want    string
+		ok      bool
+	}{
+		{"FCP", MilestoneFirstContentfulPaint, true},
+		{"lcp", MilestoneLargestContentfulPaint, true},
+		{"navigationStart", MilestoneNavigationStart, true},
+		{"DCL", MilestoneDOMContentLoaded, true},
+		{"PageLoad:load", MilestoneLoad, true},
+		{"load", MilestoneLoad, true},
+		{"DOMEvent:click", "", false},
+		{"", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := ResolveMilestoneAnchor(tt.pattern)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("ResolveMilestoneAnchor(%q) = %q, %v; want %q, %v", tt.pattern, got, ok, tt.want, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,509 @@
+package analyzer
+
+import (
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/CedricHerzog/perfowl/internal/parser"
+)
+
+// Party classifications for resources
+const (
+	PartyFirst        = "first_party"
+	PartyThird        = "third_party"
+	PartyUnclassified = "unclassified" // Web code when no first-party domains are configured
+	PartyExtension    = "extension"
+	PartyBrowser      = "browser" // Native code, libraries and browser-internal scripts
+)
+
+// resourceTypeNames maps Firefox resource table types to names
+var resourceTypeNames = map[int]string{
+	0: "unknown",
+	1: "library",
+	2: "addon",
+	3: "webhost",
+	4: "otherhost",
+	5: "url",
+}
+
+// ResourceOptions configures resource attribution
+type ResourceOptions struct {
+	FirstPartyDomains []string // Domains owned by the page; subdomains match too
+	Limit             int      // Maximum number of resources, origins and libraries to return
+}
+
+// ResourceStats contains the time attributed to a single resource (script, host or library)
+type ResourceStats struct {
+	Name        string  `json:"name"`
+	Type        string  `json:"type"`
+	Origin      string  `json:"origin,omitempty"`
+	Party       string  `json:"party"`
+	SelfTimeMs  float64 `json:"self_time_ms"`
+	TotalTimeMs float64 `json:"total_time_ms"`
+	SelfPercent float64 `json:"self_percent"`
+	SampleCount int     `json:"sample_count"`
+}
+
+// OriginStats contains the time attributed to all resources of an origin
+type OriginStats struct {
+	Origin        string  `json:"origin"`
+	Party         string  `json:"party"`
+	SelfTimeMs    float64 `json:"self_time_ms"`
+	TotalTimeMs   float64 `json:"total_time_ms"`
+	SelfPercent   float64 `json:"self_percent"`
+	ResourceCount int     `json:"resource_count"`
+}
+
+// PartyStats contains the time attributed to a party classification
+type PartyStats struct {
+	Party        string  `json:"party"`
+	SelfTimeMs   float64 `json:"self_time_ms"`
+	TotalTimeMs  float64 `json:"total_time_ms"`
+	SelfPercent  float64 `json:"self_percent"`
+	TotalPercent float64 `json:"total_percent"`
+}
+
+// LibraryStats contains the time attributed to a native library
+type LibraryStats struct {
+	Name        string  `json:"name"`
+	Path        string  `json:"path,omitempty"`
+	SelfTimeMs  float64 `json:"self_time_ms"`
+	TotalTimeMs float64 `json:"total_time_ms"`
+	SelfPercent float64 `json:"self_percent"`
+	SampleCount int     `json:"sample_count"`
+}
+
+// ResourceAnalysis contains CPU time rolled up by resource, origin, party and library
+type ResourceAnalysis struct {
+	TotalTimeMs       float64                 `json:"total_time_ms"`
+	FirstPartyDomains []string                `json:"first_party_domains,omitempty"`
+	Parties           []PartyStats            `json:"parties"`
+	Origins           []OriginStats           `json:"origins"`
+	Resources         []ResourceStats         `json:"resources"`
+	Libraries         []LibraryStats          `json:"libraries"`
+	ByThread          map[string][]PartyStats `json:"by_thread,omitempty"`
+	Notes             []string                `json:"notes,omitempty"`
+}
+
+// frameResource is the resolved resource of a frame
+type frameResource struct {
+	name    string
+	typ     string
+	origin  string
+	party   string
+	lib     string
+	libPath string
+}
+
+// AnalyzeResources attributes sample self and total time to the resources, origins
+// and native libraries of the frames. Self time goes to the leaf frame, total time
+// to every distinct resource on the stack.
+func AnalyzeResources(profile *parser.Profile, opts ResourceOptions) ResourceAnalysis {
+	analysis := ResourceAnalysis{
+		FirstPartyDomains: opts.FirstPartyDomains,
+		Parties:           make([]PartyStats, 0),
+		Origins:           make([]OriginStats, 0),
+		Resources:         make([]ResourceStats, 0),
+		Libraries:         make([]LibraryStats, 0),
+		ByThread:          make(map[string][]PartyStats),
+	}
+
+	if opts.Limit <= 0 {
+		opts.Limit = 20
+	}
+
+	sharedStrings := profile.Shared.StringArray
+	interval := profile.Meta.Interval
+
+	resources := make(map[string]*ResourceStats)
+	origins := make(map[string]*OriginStats)
+	originResources := make(map[string]map[string]bool)
+	parties := make(map[string]*PartyStats)
+	libraries := make(map[string]*LibraryStats)
+
+	for _, thread := range profile.Threads {
+		stackTable := &thread.StackTable
+		frameTable := &thread.FrameTable
+		funcTable := &thread.FuncTable
+		resourceTable := &thread.ResourceTable
+		samples := &thread.Samples
+
+		// Use thread's string array, fall back to shared if empty
+		stringArray := thread.StringArray
+		if len(stringArray) == 0 {
+			stringArray = sharedStrings
+		}
+
+		getString := func(idx int) string {
+			if idx >= 0 && idx < len(stringArray) {
+				return stringArray[idx]
+			}
+			return ""
+		}
+
+		// Resolve each frame's resource once
+		frames := make(map[int]*frameResource)
+		resolveFrame := func(frameIdx int) *frameResource {
+			if fr, ok := frames[frameIdx]; ok {
+				return fr
+			}
+			fr := resolveFrameResource(profile, frameTable, funcTable, resourceTable, frameIdx, getString, opts.FirstPartyDomains)
+			frames[frameIdx] = fr
+			return fr
+		}
+
+		// Aggregate time per unique stack first
+		stackTime := make(map[int]float64)
+		stackCount := make(map[int]int)
+		threadTime := 0.0
+		for i := 0; i < samples.Length; i++ {
+			stackIdx := -1
+			if i < len(samples.Stack) {
+				stackIdx = samples.Stack[i]
+			}
+			if stackIdx < 0 || stackIdx >= stackTable.Length {
+				continue
+			}
+
+			cpuDelta := interval
+			if i < len(samples.ThreadCPUDelta) && samples.ThreadCPUDelta[i] > 0 {
+				cpuDelta = float64(samples.ThreadCPUDelta[i]) / 1000.0
+			}
+
+			threadTime += cpuDelta
+			stackTime[stackIdx] += cpuDelta
+			stackCount[stackIdx]++
+		}
+		analysis.TotalTimeMs += threadTime
+
+		threadParties := make(map[string]*PartyStats)
+
+		for stackIdx, timeMs := range stackTime {
+			// Self time goes to the leaf frame's resource
+			if stackIdx < len(stackTable.Frame) {
+				fr := resolveFrame(stackTable.Frame[stackIdx])
+				if fr.name != "" {
+					rs := getResourceStats(resources, fr)
+					rs.SelfTimeMs += timeMs
+					rs.SampleCount += stackCount[stackIdx]
+				}
+				if fr.origin != "" {
+					getOriginStats(origins, fr).SelfTimeMs += timeMs
+				}
+				if fr.lib != "" {
+					ls := getLibraryStats(libraries, fr)
+					ls.SelfTimeMs += timeMs
+					ls.SampleCount += stackCount[stackIdx]
+				}
+				getPartyStats(parties, fr.party).SelfTimeMs += timeMs
+				getPartyStats(threadParties, fr.party).SelfTimeMs += timeMs
+			}
+
+			// Total time goes to every distinct resource, origin, library and party on the stack
+			seenResources := make(map[string]bool)
+			seenOrigins := make(map[string]bool)
+			seenLibs := make(map[string]bool)
+			seenParties := make(map[string]bool)
+			for current := stackIdx; current >= 0 && current < stackTable.Length; {
+				if current < len(stackTable.Frame) {
+					fr := resolveFrame(stackTable.Frame[current])
+					if fr.name != "" && !seenResources[fr.name] {
+						seenResources[fr.name] = true
+						getResourceStats(resources, fr).TotalTimeMs += timeMs
+						if fr.origin != "" {
+							if originResources[fr.origin] == nil {
+								originResources[fr.origin] = make(map[string]bool)
+							}
+							originResources[fr.origin][fr.name] = true
+						}
+					}
+					if fr.origin != "" && !seenOrigins[fr.origin] {
+						seenOrigins[fr.origin] = true
+						getOriginStats(origins, fr).TotalTimeMs += timeMs
+					}
+					if fr.lib != "" && !seenLibs[fr.lib] {
+						seenLibs[fr.lib] = true
+						getLibraryStats(libraries, fr).TotalTimeMs += timeMs
+					}
+					if !seenParties[fr.party] {
+						seenParties[fr.party] = true
+						getPartyStats(parties, fr.party).TotalTimeMs += timeMs
+						getPartyStats(threadParties, fr.party).TotalTimeMs += timeMs
+					}
+				}
+				if current >= len(stackTable.Prefix) {
+					break
+				}
+				current = stackTable.Prefix[current]
+			}
+		}
+
+		if threadTime > 0 {
+			analysis.ByThread[thread.Name] = mergePartyStats(analysis.ByThread[thread.Name], threadParties)
+		}
+	}
+
+	percent := func(ms float64) float64 {
+		if analysis.TotalTimeMs > 0 {
+			return (ms / analysis.TotalTimeMs) * 100
+		}
+		return 0
+	}
+
+	for _, ps := range parties {
+		ps.SelfPercent = percent(ps.SelfTimeMs)
+		ps.TotalPercent = percent(ps.TotalTimeMs)
+		analysis.Parties = append(analysis.Parties, *ps)
+	}
+	sortPartyStats(analysis.Parties)
+
+	for name, stats := range analysis.ByThread {
+		threadTotal := 0.0
+		for _, ps := range stats {
+			threadTotal += ps.SelfTimeMs
+		}
+		for i := range stats {
+			if threadTotal > 0 {
+				stats[i].SelfPercent = (stats[i].SelfTimeMs / threadTotal) * 100
+				stats[i].TotalPercent = (stats[i].TotalTimeMs / threadTotal) * 100
+			}
+		}
+		sortPartyStats(stats)
+		analysis.ByThread[name] = stats
+	}
+
+	for origin, os := range origins {
+		os.SelfPercent = percent(os.SelfTimeMs)
+		os.ResourceCount = len(originResources[origin])
+		analysis.Origins = append(analysis.Origins, *os)
+	}
+	sort.Slice(analysis.Origins, func(i, j int) bool {
+		if analysis.Origins[i].TotalTimeMs != analysis.Origins[j].TotalTimeMs {
+			return analysis.Origins[i].TotalTimeMs > analysis.Origins[j].TotalTimeMs
+		}
+		return analysis.Origins[i].Origin < analysis.Origins[j].Origin
+	})
+	if len(analysis.Origins) > opts.Limit {
+		analysis.Origins = analysis.Origins[:opts.Limit]
+	}
+
+	for _, rs := range resources {
+		rs.SelfPercent = percent(rs.SelfTimeMs)
+		analysis.Resources = append(analysis.Resources, *rs)
+	}
+	sort.Slice(analysis.Resources, func(i, j int) bool {
+		if analysis.Resources[i].SelfTimeMs != analysis.Resources[j].SelfTimeMs {
+			return analysis.Resources[i].SelfTimeMs > analysis.Resources[j].SelfTimeMs
+		}
+		if analysis.Resources[i].TotalTimeMs != analysis.Resources[j].TotalTimeMs {
+			return analysis.Resources[i].TotalTimeMs > analysis.Resources[j].TotalTimeMs
+		}
+		return analysis.Resources[i].Name < analysis.Resources[j].Name
+	})
+	if len(analysis.Resources) > opts.Limit {
+		analysis.Resources = analysis.Resources[:opts.Limit]
+	}
+
+	for _, ls := range libraries {
+		ls.SelfPercent = percent(ls.SelfTimeMs)
+		analysis.Libraries = append(analysis.Libraries, *ls)
+	}
+	sort.Slice(analysis.Libraries, func(i, j int) bool {
+		if analysis.Libraries[i].SelfTimeMs != analysis.Libraries[j].SelfTimeMs {
+			return analysis.Libraries[i].SelfTimeMs > analysis.Libraries[j].SelfTimeMs
+		}
+		return analysis.Libraries[i].Name < analysis.Libraries[j].Name
+	})
+	if len(analysis.Libraries) > opts.Limit {
+		analysis.Libraries = analysis.Libraries[:opts.Limit]
+	}
+
+	if parties[PartyUnclassified] != nil {
+		analysis.Notes = append(analysis.Notes,
+			"No first-party domains configured, web origins are unclassified. Pass the page's domains to split first- and third-party time.")
+	}
+
+	return analysis
+}
+
+// resolveFrameResource determines the resource, origin, party and library of a frame
+func resolveFrameResource(profile *parser.Profile, frameTable *parser.FrameTable, funcTable *parser.FuncTable,
+	resourceTable *parser.ResourceTable, frameIdx int, getString func(int) string, firstParty []string) *frameResource {
+	fr := &frameResource{party: PartyBrowser}
+
+	if frameIdx < 0 || frameIdx >= len(frameTable.Func) {
+		return fr
+	}
+	funcIdx := frameTable.Func[frameIdx]
+	if funcIdx < 0 || funcIdx >= funcTable.Length {
+		return fr
+	}
+
+	isJS := funcIdx < len(funcTable.IsJS) && funcTable.IsJS[funcIdx]
+
+	resIdx := -1
+	if funcIdx < len(funcTable.Resource) {
+		resIdx = funcTable.Resource[funcIdx]
+	}
+
+	if resIdx >= 0 && resIdx < resourceTable.Length {
+		if resIdx < len(resourceTable.Name) {
+			fr.name = getString(resourceTable.Name[resIdx])
+		}
+		resType := 0
+		if resIdx < len(resourceTable.Type) {
+			resType = resourceTable.Type[resIdx]
+		}
+		fr.typ = resourceTypeNames[resType]
+		if fr.typ == "" {
+			fr.typ = resourceTypeNames[0]
+		}
+
+		if resType == 1 && resIdx < len(resourceTable.Lib) {
+			if libIdx := resourceTable.Lib[resIdx]; libIdx >= 0 && libIdx < len(profile.Libs) {
+				fr.lib = profile.Libs[libIdx].Name
+				fr.libPath = profile.Libs[libIdx].Path
+			}
+			if fr.lib == "" {
+				fr.lib = fr.name
+			}
+			return fr
+		}
+		if resType == 2 && fr.name != "" {
+			// Firefox names addon resources after the extension, not its moz-extension:// URL
+			fr.origin, fr.party = fr.name, PartyExtension
+			return fr
+		}
+	} else if isJS && funcIdx < len(funcTable.FileName) {
+		// Profiles without a resource table (e.g. Chrome) still carry the script URL
+		fr.name = getString(funcTable.FileName[funcIdx])
+		fr.typ = resourceTypeNames[5]
+	}
+
+	if fr.name == "" {
+		return fr
+	}
+
+	fr.origin, fr.party = classifyResourceOrigin(fr.name, fr.typ == "webhost" || fr.typ == "otherhost", firstParty)
+	return fr
+}
+
+// classifyResourceOrigin returns the origin of a resource name or URL and its party.
+// bareHost is set for Firefox host resources, which are named after the host alone.
+func classifyResourceOrigin(name string, bareHost bool, firstParty []string) (string, string) {
+	if strings.HasPrefix(name, "moz-extension://") || strings.HasPrefix(name, "chrome-extension://") {
+		if u, err := url.Parse(name); err == nil && u.Host != "" {
+			return u.Scheme + "://" + u.Host, PartyExtension
+		}
+		return name, PartyExtension
+	}
+
+	host := ""
+	origin := ""
+	if strings.Contains(name, "://") {
+		u, err := url.Parse(name)
+		if err != nil {
+			return "", PartyBrowser
+		}
+		if u.Scheme != "http" && u.Scheme != "https" {
+			// resource://, chrome://, file:// and friends are not web origins
+			return "", PartyBrowser
+		}
+		host = u.Hostname()
+		origin = u.Scheme + "://" + u.Host
+	} else if bareHost {
+		host = name
+		origin = name
+	} else {
+		return "", PartyBrowser
+	}
+
+	if len(firstParty) == 0 {
+		return origin, PartyUnclassified
+	}
+	if IsFirstPartyHost(host, firstParty) {
+		return origin, PartyFirst
+	}
+	return origin, PartyThird
+}
+
+// IsFirstPartyHost reports whether host equals or is a subdomain of one of the domains
+func IsFirstPartyHost(host string, domains []string) bool {
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	for _, d := range domains {
+		d = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(d), "."))
+		if d == "" {
+			continue
+		}
+		if host == d || strings.HasSuffix(host, "."+d) {
+			return true
+		}
+	}
+	return false
+}
+
+func getResourceStats(m map[string]*ResourceStats, fr *frameResource) *ResourceStats {
+	rs := m[fr.name]
+	if rs == nil {
+		rs = &ResourceStats{Name: fr.name, Type: fr.typ, Origin: fr.origin, Party: fr.party}
+		m[fr.name] = rs
+	}
+	return rs
+}
+
+func getOriginStats(m map[string]*OriginStats, fr *frameResource) *OriginStats {
+	os := m[fr.origin]
+	if os == nil {
+		os = &OriginStats{Origin: fr.origin, Party: fr.party}
+		m[fr.origin] = os
+	}
+	return os
+}
+
+func getLibraryStats(m map[string]*LibraryStats, fr *frameResource) *LibraryStats {
+	ls := m[fr.lib]
+	if ls == nil {
+		ls = &LibraryStats{Name: fr.lib, Path: fr.libPath}
+		m[fr.lib] = ls
+	}
+	return ls
+}
+
+func getPartyStats(m map[string]*PartyStats, party string) *PartyStats {
+	ps := m[party]
+	if ps == nil {
+		ps = &PartyStats{Party: party}
+		m[party] = ps
+	}
+	return ps
+}
+
+// mergePartyStats adds party times to an existing list, used when threads share a name
+func mergePartyStats(existing []PartyStats, add map[string]*PartyStats) []PartyStats {
+	merged := make(map[string]*PartyStats)
+	for i := range existing {
+		ps := existing[i]
+		merged[ps.Party] = &ps
+	}
+	for party, ps := range add {
+		m := getPartyStats(merged, party)
+		m.SelfTimeMs += ps.SelfTimeMs
+		m.TotalTimeMs += ps.TotalTimeMs
+	}
+
+	result := make([]PartyStats, 0, len(merged))
+	for _, ps := range merged {
+		result = append(result, *ps)
+	}
+	return result
+}
+
+func sortPartyStats(stats []PartyStats) {
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].SelfTimeMs != stats[j].SelfTimeMs {
+			return stats[i].SelfTimeMs > stats[j].SelfTimeMs
+		}
+		return stats[i].Party < stats[j].Party
+	})
+}
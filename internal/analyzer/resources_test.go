@@ -0,0 +1,229 @@
+package analyzer
+
+import (
+	"testing"
+
+	"github.com/CedricHerzog/perfowl/internal/testutil"
+)
+
+func findParty(stats []PartyStats, party string) *PartyStats {
+	for i := range stats {
+		if stats[i].Party == party {
+			return &stats[i]
+		}
+	}
+	return nil
+}
+
+func TestAnalyzeResources_Parties(t *testing.T) {
+	analysis := AnalyzeResources(testutil.ProfileWithResources(), ResourceOptions{
+		FirstPartyDomains: []string{"example.com"},
+	})
+
+	testutil.AssertFloatApproxEqual(t, analysis.TotalTimeMs, 50, 0.01)
+
+	tests := []struct {
+		party string
+		self  float64
+		total float64
+	}{
+		{PartyFirst, 10, 45},
+		{PartyThird, 20, 30},
+		{PartyBrowser, 15, 15},
+		{PartyExtension, 5, 5},
+	}
+	for _, tt := range tests {
+		ps := findParty(analysis.Parties, tt.party)
+		if ps == nil {
+			t.Errorf("missing party %s", tt.party)
+			continue
+		}
+		testutil.AssertFloatApproxEqual(t, ps.SelfTimeMs, tt.self, 0.01)
+		testutil.AssertFloatApproxEqual(t, ps.TotalTimeMs, tt.total, 0.01)
+	}
+
+	if analysis.Parties[0].Party != PartyThird {
+		t.Errorf("expected third party first by self time, got %s", analysis.Parties[0].Party)
+	}
+	if len(analysis.Notes) != 0 {
+		t.Errorf("expected no notes with first-party domains, got %v", analysis.Notes)
+	}
+	if stats := analysis.ByThread["GeckoMain"]; findParty(stats, PartyThird) == nil {
+		t.Errorf("expected third party in GeckoMain breakdown, got %v", stats)
+	}
+}
+
+func TestAnalyzeResources_OriginsAndResources(t *testing.T) {
+	analysis := AnalyzeResources(testutil.ProfileWithResources(), ResourceOptions{
+		FirstPartyDomains: []string{"example.com"},
+	})
+
+	if len(analysis.Origins) != 3 {
+		t.Fatalf("expected 3 origins, got %d: %v", len(analysis.Origins), analysis.Origins)
+	}
+	if analysis.Origins[0].Origin != "https://www.example.com" {
+		t.Errorf("top origin = %s, want https://www.example.com", analysis.Origins[0].Origin)
+	}
+	testutil.AssertFloatApproxEqual(t, analysis.Origins[0].TotalTimeMs, 45, 0.01)
+
+	var track *ResourceStats
+	for i := range analysis.Resources {
+		if analysis.Resources[i].Name == "https://cdn.analytics.com/track.js" {
+			track = &analysis.Resources[i]
+		}
+	}
+	if track == nil {
+		t.Fatal("expected track.js resource")
+	}
+	if track.Party != PartyThird || track.Origin != "https://cdn.analytics.com" {
+		t.Errorf("track.js party/origin = %s/%s", track.Party, track.Origin)
+	}
+	testutil.AssertFloatApproxEqual(t, track.SelfTimeMs, 20, 0.01)
+	testutil.AssertFloatApproxEqual(t, track.TotalTimeMs, 30, 0.01)
+	testutil.AssertFloatApproxEqual(t, track.SelfPercent, 40, 0.01)
+}
+
+func TestAnalyzeResources_Libraries(t *testing.T) {
+	analysis := AnalyzeResources(testutil.ProfileWithResources(), ResourceOptions{})
+
+	if len(analysis.Libraries) != 1 {
+		t.Fatalf("expected 1 library, got %d", len(analysis.Libraries))
+	}
+	lib := analysis.Libraries[0]
+	if lib.Name != "libxul.so" || lib.Path != "/usr/lib/firefox/libxul.so" {
+		t.Errorf("library = %s (%s)", lib.Name, lib.Path)
+	}
+	testutil.AssertFloatApproxEqual(t, lib.SelfTimeMs, 15, 0.01)
+	if lib.SampleCount != 15 {
+		t.Errorf("library samples = %d, want 15", lib.SampleCount)
+	}
+}
+
+func TestAnalyzeResources_Unclassified(t *testing.T) {
+	analysis := AnalyzeResources(testutil.ProfileWithResources(), ResourceOptions{})
+
+	ps := findParty(analysis.Parties, PartyUnclassified)
+	if ps == nil {
+		t.Fatal("expected unclassified party without first-party domains")
+	}
+	testutil.AssertFloatApproxEqual(t, ps.SelfTimeMs, 30, 0.01)
+	if findParty(analysis.Parties, PartyFirst) != nil || findParty(analysis.Parties, PartyThird) != nil {
+		t.Error("expected no first or third party without domains")
+	}
+	if len(analysis.Notes) == 0 {
+		t.Error("expected a note about missing first-party domains")
+	}
+}
+
+func TestAnalyzeResources_Limit(t *testing.T) {
+	analysis := AnalyzeResources(testutil.ProfileWithResources(), ResourceOptions{Limit: 1})
+	if len(analysis.Resources) != 1 || len(analysis.Origins) != 1 {
+		t.Errorf("expected 1 resource and origin, got %d and %d", len(analysis.Resources), len(analysis.Origins))
+	}
+}
+
+func TestAnalyzeResources_FileNameFallback(t *testing.T) {
+	// Profiles without a resource table (e.g. Chrome) use the function's file URL
+	analysis := AnalyzeResources(testutil.ProfileWithSourceLines(), ResourceOptions{
+		FirstPartyDomains: []string{"example.com"},
+	})
+
+	if len(analysis.Resources) != 1 || analysis.Resources[0].Name != "https://example.com/js/app.js" {
+		t.Fatalf("resources = %v", analysis.Resources)
+	}
+	if analysis.Resources[0].Party != PartyFirst {
+		t.Errorf("party = %s, want %s", analysis.Resources[0].Party, PartyFirst)
+	}
+}
+
+func TestAnalyzeResources_AddonResource(t *testing.T) {
+	rtb := testutil.NewResourceTableBuilder()
+	rtb.AddResource(-1, 1, -1, 2) // Type 2 is an addon, named after the extension
+	fnb := testutil.NewFuncTableBuilder()
+	fnb.AddFunc(0, true, 0)
+	ftb := testutil.NewFrameTableBuilder()
+	ftb.AddFrame(0, 2)
+	stb := testutil.NewStackTableBuilder()
+	stb.AddStack(0, 2, -1)
+	sb := testutil.NewSamplesBuilder()
+	for i := 0; i < 4; i++ {
+		sb.AddSampleWithCPUDelta(0, float64(i), 1000)
+	}
+	profile := testutil.NewProfileBuilder().
+		WithDuration(4).
+		WithCategories(testutil.DefaultCategories()).
+		WithThread(testutil.NewThreadBuilder("GeckoMain").
+			AsMainThread().
+			WithStringArray([]string{"filterRequest", "uBlock Origin"}).
+			WithStackTable(stb.Build()).
+			WithFrameTable(ftb.Build()).
+			WithFuncTable(fnb.Build()).
+			WithResourceTable(rtb.Build()).
+			WithSamples(sb.Build()).
+			Build()).
+		Build()
+
+	analysis := AnalyzeResources(profile, ResourceOptions{FirstPartyDomains: []string{"example.com"}})
+
+	ps := findParty(analysis.Parties, PartyExtension)
+	if ps == nil {
+		t.Fatalf("expected extension time, got parties %v", analysis.Parties)
+	}
+	testutil.AssertFloatApproxEqual(t, ps.SelfTimeMs, 4, 0.01)
+	if findParty(analysis.Parties, PartyBrowser) != nil {
+		t.Errorf("expected no browser time, got parties %v", analysis.Parties)
+	}
+	if len(analysis.Origins) != 1 || analysis.Origins[0].Origin != "uBlock Origin" {
+		t.Errorf("origins = %v, want the extension name", analysis.Origins)
+	}
+}
+
+func TestClassifyResourceOrigin(t *testing.T) {
+	firstParty := []string{"example.com"}
+	tests := []struct {
+		name       string
+		bareHost   bool
+		wantOrigin string
+		wantParty  string
+	}{
+		{"https://www.example.com/a.js", false, "https://www.example.com", PartyFirst},
+		{"https://example.com:8443/a.js", false, "https://example.com:8443", PartyFirst},
+		{"https://notexample.com/a.js", false, "https://notexample.com", PartyThird},
+		{"cdn.tracker.net", true, "cdn.tracker.net", PartyThird},
+		{"moz-extension://abc/content.js", false, "moz-extension://abc", PartyExtension},
+		{"chrome-extension://xyz/bg.js", false, "chrome-extension://xyz", PartyExtension},
+		{"resource://gre/modules/Foo.sys.mjs", false, "", PartyBrowser},
+		{"chrome://browser/content/browser.js", false, "", PartyBrowser},
+		{"libxul.so", false, "", PartyBrowser},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			origin, party := classifyResourceOrigin(tt.name, tt.bareHost, firstParty)
+			if origin != tt.wantOrigin || party != tt.wantParty {
+				t.Errorf("classifyResourceOrigin(%q) = %q, %q, want %q, %q", tt.name, origin, party, tt.wantOrigin, tt.wantParty)
+			}
+		})
+	}
+}
+
+func TestIsFirstPartyHost(t *testing.T) {
+	domains := []string{" Example.com", ".cdn.example.org", ""}
+	tests := []struct {
+		host string
+		want bool
+	}{
+		{"example.com", true},
+		{"www.example.com", true},
+		{"EXAMPLE.COM.", true},
+		{"static.cdn.example.org", true},
+		{"example.org", false},
+		{"badexample.com", false},
+	}
+
+	for _, tt := range tests {
+		if got := IsFirstPartyHost(tt.host, domains); got != tt.want {
+			t.Errorf("IsFirstPartyHost(%q) = %v, want %v", tt.host, got, tt.want)
+		}
+	}
+}
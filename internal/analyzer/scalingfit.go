@@ -0,0 +1,259 @@
+package analyzer
+
+import "math"
+
+// Scaling model fit settings
+const (
+	ScalingModelAmdahl = "amdahl"
+	ScalingModelUSL    = "usl"
+
+	ScalingFitMaxWorkers = 64   // Upper bound for predictions and recommendations
+	ScalingFitMinGain    = 0.05 // Under Amdahl, stop adding workers once one more saves less than this fraction
+	scalingFitMinKappa   = 1e-9 // Smaller coherency terms are rounding noise from an Amdahl-shaped fit
+)
+
+// ScalingPrediction is the modelled time and speedup at one worker count
+type ScalingPrediction struct {
+	Workers int     `json:"workers"`
+	TimeMs  float64 `json:"time_ms"`
+	Speedup float64 `json:"speedup"` // Relative throughput against one worker
+}
+
+// ScalingFit describes how one label's time scales with the worker count under
+// Amdahl's law and the Universal Scalability Law. Worker count 0 (sequential) is
+// treated as a single worker.
+type ScalingFit struct {
+	Metric             string              `json:"metric"` // wall_clock or operation_time
+	Points             int                 `json:"points"`
+	Model              string              `json:"model"`       // Model used for the prediction and recommendation
+	BaselineMs         float64             `json:"baseline_ms"` // Fitted time with one worker
+	SerialFraction     float64             `json:"serial_fraction"`
+	AmdahlMaxSpeedup   float64             `json:"amdahl_max_speedup,omitempty"` // 1 / serial fraction
+	AmdahlR2           float64             `json:"amdahl_r2"`
+	Contention         float64             `json:"usl_contention"` // USL σ
+	Coherency          float64             `json:"usl_coherency"`  // USL κ
+	USLR2              float64             `json:"usl_r2,omitempty"`
+	PeakWorkers        float64             `json:"peak_workers,omitempty"` // USL throughput peak sqrt((1-σ)/κ)
+	RecommendedWorkers int                 `json:"recommended_workers"`
+	Predicted          []ScalingPrediction `json:"predicted"`
+}
+
+// scalingSample is one measured time at an effective worker count
+type scalingSample struct {
+	n    float64
+	time float64
+}
+
+// FitScaling fits Amdahl's law and the Universal Scalability Law to a series of
+// measurements. Both models give T(N) = T1 * g(N) / N with g(N) = 1 + σ(N-1) + κN(N-1)
+// (κ = 0 for Amdahl), so T(N)*N is fitted by linear least squares. Operation time is
+// used when every point measured it, wall clock time otherwise. Amdahl needs two
+// distinct worker counts and USL three; ok is false when there are too few.
+func FitScaling(points []ProfileDataPoint) (fit ScalingFit, ok bool) {
+	fit.Metric = BatchMetricOperationTime
+	for _, p := range points {
+		if p.OperationTimeMs <= 0 {
+			fit.Metric = BatchMetricWallClock
+			break
+		}
+	}
+
+	var samples []scalingSample
+	distinct := make(map[float64]bool)
+	for _, p := range points {
+		t := p.WallClockMs
+		if fit.Metric == BatchMetricOperationTime {
+			t = p.OperationTimeMs
+		}
+		if t <= 0 {
+			continue
+		}
+		n := math.Max(1, float64(p.WorkerCount))
+		samples = append(samples, scalingSample{n: n, time: t})
+		distinct[n] = true
+	}
+	fit.Points = len(samples)
+	if len(distinct) < 2 {
+		return fit, false
+	}
+
+	// Amdahl: T*N = A + B(N-1), serial fraction s = B/A
+	amdahl, solved := leastSquares(samples, func(n float64) []float64 { return []float64{1, n - 1} })
+	if !solved || amdahl[0] <= 0 {
+		return fit, false
+	}
+	fit.SerialFraction = math.Min(1, math.Max(0, amdahl[1]/amdahl[0]))
+	amdahlBaseline := scalingBaseline(samples, fit.SerialFraction, 0)
+	fit.AmdahlR2 = scalingR2(samples, amdahlBaseline, fit.SerialFraction, 0)
+	if fit.SerialFraction > 0 {
+		fit.AmdahlMaxSpeedup = 1 / fit.SerialFraction
+	}
+	fit.Model, fit.BaselineMs = ScalingModelAmdahl, amdahlBaseline
+
+	// USL: T*N = A + B(N-1) + C*N(N-1), σ = B/A and κ = C/A
+	if len(distinct) >= 3 {
+		sigma, kappa := fit.SerialFraction, 0.0
+		if usl, solved := leastSquares(samples, func(n float64) []float64 { return []float64{1, n - 1, n * (n - 1)} }); solved && usl[0] > 0 && usl[2]/usl[0] > scalingFitMinKappa {
+			sigma, kappa = usl[1]/usl[0], usl[2]/usl[0]
+			if sigma < 0 {
+				// Superlinear start: refit coherency alone
+				if c, solved := leastSquares(samples, func(n float64) []float64 { return []float64{1, n * (n - 1)} }); solved && c[0] > 0 && c[1]/c[0] > scalingFitMinKappa {
+					sigma, kappa = 0, c[1]/c[0]
+				} else {
+					sigma, kappa = fit.SerialFraction, 0
+				}
+			}
+		}
+		fit.Contention, fit.Coherency = math.Min(1, sigma), kappa
+		uslBaseline := scalingBaseline(samples, fit.Contention, fit.Coherency)
+		fit.USLR2 = scalingR2(samples, uslBaseline, fit.Contention, fit.Coherency)
+		if fit.Coherency > 0 {
+			fit.Model, fit.BaselineMs = ScalingModelUSL, uslBaseline
+			fit.PeakWorkers = math.Sqrt((1 - fit.Contention) / fit.Coherency)
+		}
+	}
+
+	sigma, kappa := fit.SerialFraction, 0.0
+	if fit.Model == ScalingModelUSL {
+		sigma, kappa = fit.Contention, fit.Coherency
+	}
+	predict := func(n int) float64 {
+		return scalingTime(fit.BaselineMs, sigma, kappa, float64(n))
+	}
+
+	// Recommended workers: the USL throughput peak, or under Amdahl the point where
+	// one more worker stops paying off
+	fit.RecommendedWorkers = ScalingFitMaxWorkers
+	if fit.Model == ScalingModelUSL {
+		fit.RecommendedWorkers = 1
+		if fit.PeakWorkers >= 1 {
+			lo := int(math.Min(math.Floor(fit.PeakWorkers), ScalingFitMaxWorkers))
+			fit.RecommendedWorkers = lo
+			if hi := lo + 1; hi <= ScalingFitMaxWorkers && predict(hi) < predict(lo) {
+				fit.RecommendedWorkers = hi
+			}
+		}
+	} else {
+		for n := 1; n < ScalingFitMaxWorkers; n++ {
+			if predict(n+1) > predict(n)*(1-ScalingFitMinGain) {
+				fit.RecommendedWorkers = n
+				break
+			}
+		}
+	}
+
+	// Predict past the measured counts: half as far again, and past the recommendation
+	var maxN float64
+	for n := range distinct {
+		maxN = math.Max(maxN, n)
+	}
+	last := int(math.Max(maxN*1.5, maxN+2))
+	if fit.RecommendedWorkers+1 > last {
+		last = fit.RecommendedWorkers + 1
+	}
+	if last > ScalingFitMaxWorkers {
+		last = ScalingFitMaxWorkers
+	}
+	fit.Predicted = make([]ScalingPrediction, 0, last)
+	for n := 1; n <= last; n++ {
+		t := predict(n)
+		p := ScalingPrediction{Workers: n, TimeMs: t}
+		if t > 0 {
+			p.Speedup = fit.BaselineMs / t
+		}
+		fit.Predicted = append(fit.Predicted, p)
+	}
+
+	return fit, true
+}
+
+// scalingTime is the modelled time at n workers: T1 * (1 + σ(n-1) + κn(n-1)) / n
+func scalingTime(baseline, sigma, kappa, n float64) float64 {
+	return baseline * (1 + sigma*(n-1) + kappa*n*(n-1)) / n
+}
+
+// scalingBaseline is the least-squares one-worker time for fixed σ and κ
+func scalingBaseline(samples []scalingSample, sigma, kappa float64) float64 {
+	var num, den float64
+	for _, s := range samples {
+		g := (1 + sigma*(s.n-1) + kappa*s.n*(s.n-1)) / s.n
+		num += s.time * g
+		den += g * g
+	}
+	if den == 0 {
+		return 0
+	}
+	return num / den
+}
+
+// scalingR2 is the coefficient of determination of the modelled times
+func scalingR2(samples []scalingSample, baseline, sigma, kappa float64) float64 {
+	var mean float64
+	for _, s := range samples {
+		mean += s.time
+	}
+	mean /= float64(len(samples))
+
+	var ssRes, ssTot float64
+	for _, s := range samples {
+		r := s.time - scalingTime(baseline, sigma, kappa, s.n)
+		ssRes += r * r
+		ssTot += (s.time - mean) * (s.time - mean)
+	}
+	if ssTot == 0 {
+		if ssRes == 0 {
+			return 1
+		}
+		return 0
+	}
+	return 1 - ssRes/ssTot
+}
+
+// leastSquares fits T*N against the regressors returned by row for each sample by
+// solving the normal equations. ok is false when the system is singular.
+func leastSquares(samples []scalingSample, row func(n float64) []float64) ([]float64, bool) {
+	k := len(row(1))
+	m := make([][]float64, k)
+	for i := range m {
+		m[i] = make([]float64, k+1)
+	}
+	for _, s := range samples {
+		x := row(s.n)
+		y := s.time * s.n
+		for i := 0; i < k; i++ {
+			for j := 0; j < k; j++ {
+				m[i][j] += x[i] * x[j]
+			}
+			m[i][k] += x[i] * y
+		}
+	}
+
+	// Gaussian elimination with partial pivoting
+	for col := 0; col < k; col++ {
+		pivot := col
+		for r := col + 1; r < k; r++ {
+			if math.Abs(m[r][col]) > math.Abs(m[pivot][col]) {
+				pivot = r
+			}
+		}
+		if math.Abs(m[pivot][col]) < 1e-12 {
+			return nil, false
+		}
+		m[col], m[pivot] = m[pivot], m[col]
+		for r := col + 1; r < k; r++ {
+			f := m[r][col] / m[col][col]
+			for c := col; c <= k; c++ {
+				m[r][c] -= f * m[col][c]
+			}
+		}
+	}
+	coef := make([]float64, k)
+	for i := k - 1; i >= 0; i-- {
+		v := m[i][k]
+		for j := i + 1; j < k; j++ {
+			v -= m[i][j] * coef[j]
+		}
+		coef[i] = v / m[i][i]
+	}
+	return coef, true
+}
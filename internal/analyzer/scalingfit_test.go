@@ -0,0 +1,100 @@
+package analyzer
+
+import (
+	"testing"
+
+	"github.com/CedricHerzog/perfowl/internal/testutil"
+)
+
+// scalingPoints builds wall clock measurements from a time function of the worker count
+func scalingPoints(workers []int, time func(n float64) float64) []ProfileDataPoint {
+	points := make([]ProfileDataPoint, 0, len(workers))
+	for _, w := range workers {
+		n := float64(w)
+		if n < 1 {
+			n = 1
+		}
+		points = append(points, ProfileDataPoint{WorkerCount: w, WallClockMs: time(n)})
+	}
+	return points
+}
+
+func TestFitScaling_TooFewPoints(t *testing.T) {
+	if _, ok := FitScaling(nil); ok {
+		t.Error("expected no fit without points")
+	}
+	// Sequential and one worker are the same effective count
+	if _, ok := FitScaling(scalingPoints([]int{0, 1}, func(n float64) float64 { return 1000 / n })); ok {
+		t.Error("expected no fit with a single distinct worker count")
+	}
+}
+
+func TestFitScaling_Amdahl(t *testing.T) {
+	fit, ok := FitScaling(scalingPoints([]int{1, 2, 4, 8}, func(n float64) float64 { return 200 + 800/n }))
+	if !ok {
+		t.Fatal("expected a fit")
+	}
+
+	if fit.Model != ScalingModelAmdahl || fit.Metric != BatchMetricWallClock || fit.Points != 4 {
+		t.Errorf("model/metric/points = %s/%s/%d, want amdahl/wall_clock/4", fit.Model, fit.Metric, fit.Points)
+	}
+	testutil.AssertFloatApproxEqual(t, fit.SerialFraction, 0.2, 1e-6)
+	testutil.AssertFloatApproxEqual(t, fit.BaselineMs, 1000, 1e-6)
+	testutil.AssertFloatApproxEqual(t, fit.AmdahlMaxSpeedup, 5, 1e-6)
+	testutil.AssertFloatApproxEqual(t, fit.AmdahlR2, 1, 1e-9)
+	if fit.Coherency != 0 {
+		t.Errorf("Coherency = %v, want 0 for Amdahl-shaped data", fit.Coherency)
+	}
+	// One more worker saves under 5% from 7 workers on
+	if fit.RecommendedWorkers != 7 {
+		t.Errorf("RecommendedWorkers = %d, want 7", fit.RecommendedWorkers)
+	}
+	if len(fit.Predicted) != 12 {
+		t.Fatalf("expected predictions up to 12 workers, got %d", len(fit.Predicted))
+	}
+	testutil.AssertFloatApproxEqual(t, fit.Predicted[9].TimeMs, 280, 1e-6)
+	testutil.AssertFloatApproxEqual(t, fit.Predicted[9].Speedup, 1000.0/280, 1e-6)
+}
+
+func TestFitScaling_USL(t *testing.T) {
+	usl := func(n float64) float64 { return 1000 * (1 + 0.05*(n-1) + 0.01*n*(n-1)) / n }
+	points := scalingPoints([]int{0, 2, 4, 8, 16}, usl)
+	for i := range points {
+		points[i].OperationTimeMs = points[i].WallClockMs
+		points[i].WallClockMs += 50
+	}
+
+	fit, ok := FitScaling(points)
+	if !ok {
+		t.Fatal("expected a fit")
+	}
+
+	if fit.Model != ScalingModelUSL || fit.Metric != BatchMetricOperationTime {
+		t.Errorf("model/metric = %s/%s, want usl/operation_time", fit.Model, fit.Metric)
+	}
+	testutil.AssertFloatApproxEqual(t, fit.Contention, 0.05, 1e-6)
+	testutil.AssertFloatApproxEqual(t, fit.Coherency, 0.01, 1e-6)
+	testutil.AssertFloatApproxEqual(t, fit.USLR2, 1, 1e-9)
+	testutil.AssertFloatApproxEqual(t, fit.PeakWorkers, 9.747, 0.001)
+	if fit.USLR2 <= fit.AmdahlR2 {
+		t.Errorf("USL R² %v should beat Amdahl R² %v on retrograde data", fit.USLR2, fit.AmdahlR2)
+	}
+	// The peak lies between 9 and 10 workers; 10 is marginally faster
+	if fit.RecommendedWorkers != 10 {
+		t.Errorf("RecommendedWorkers = %d, want 10", fit.RecommendedWorkers)
+	}
+	if len(fit.Predicted) != 24 {
+		t.Errorf("expected predictions up to 24 workers, got %d", len(fit.Predicted))
+	}
+}
+
+func TestFitScaling_TwoCountsIsAmdahlOnly(t *testing.T) {
+	fit, ok := FitScaling(scalingPoints([]int{1, 4}, func(n float64) float64 { return 500 + 500/n }))
+	if !ok {
+		t.Fatal("expected a fit")
+	}
+	if fit.Model != ScalingModelAmdahl || fit.USLR2 != 0 {
+		t.Errorf("expected an Amdahl-only fit, got %+v", fit)
+	}
+	testutil.AssertFloatApproxEqual(t, fit.SerialFraction, 0.5, 1e-6)
+}
@@ -0,0 +1,333 @@
+package analyzer
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/CedricHerzog/perfowl/internal/parser"
+)
+
+// Script pipeline phases
+const (
+	ScriptPhaseParse   = "parse"
+	ScriptPhaseCompile = "compile"
+	ScriptPhaseExecute = "execute"
+)
+
+// ScriptUnknownURL groups parse and compile work whose event carries no script URL
+const ScriptUnknownURL = "(unknown)"
+
+// scriptPhaseMarkers maps Chrome trace events and Firefox markers to script phases
+var scriptPhaseMarkers = map[string]string{
+	"v8.parseOnBackground":         ScriptPhaseParse, // Chrome streaming parse
+	"v8.parseOnBackgroundParsing":  ScriptPhaseParse,
+	"v8.parseOnBackgroundWaiting":  ScriptPhaseParse, // Main thread waiting on a background parse
+	"v8.compile":                   ScriptPhaseCompile,
+	"v8.compileModule":             ScriptPhaseCompile,
+	"v8.produceCache":              ScriptPhaseCompile,
+	"v8.produceModuleCache":        ScriptPhaseCompile,
+	"EvaluateScript":               ScriptPhaseExecute,
+	"v8.evaluateModule":            ScriptPhaseExecute,
+	"JSParse":                      ScriptPhaseParse, // Firefox
+	"ScriptParse":                  ScriptPhaseParse,
+	"JS::Parse":                    ScriptPhaseParse,
+	"BytecodeCompile":              ScriptPhaseCompile,
+	"BytecodeEmit":                 ScriptPhaseCompile,
+	"ScriptCompile":                ScriptPhaseCompile,
+	"JS::Compile":                  ScriptPhaseCompile,
+	"ScriptEvaluate":               ScriptPhaseExecute,
+	"ScriptEvaluation":             ScriptPhaseExecute,
+	"JS::Evaluate":                 ScriptPhaseExecute,
+	"ScriptLoader::EvaluateScript": ScriptPhaseExecute,
+}
+
+// scriptBackgroundMarkers are V8's streaming parse events, which run off the main
+// thread; the main thread waiting on them is main-thread time
+var scriptBackgroundMarkers = map[string]bool{
+	"v8.parseOnBackground":        true,
+	"v8.parseOnBackgroundParsing": true,
+}
+
+// scriptHelperThreads name the threads browsers parse and compile scripts on
+// off the main thread. Worker threads are not among them: a worker evaluating
+// its own script is that thread's main-thread work.
+var scriptHelperThreads = []string{"ThreadPoolForegroundWorker", "ThreadPoolBackgroundWorker", "V8 DefaultWorker", "JS Helper"}
+
+// isScriptHelperThread reports whether a thread is a script parse or compile helper
+func isScriptHelperThread(name string) bool {
+	for _, helper := range scriptHelperThreads {
+		if strings.HasPrefix(name, helper) {
+			return true
+		}
+	}
+	return false
+}
+
+// ScriptCost is the load, parse, compile and execution cost of one script
+type ScriptCost struct {
+	URL            string  `json:"url"`
+	TransferBytes  int64   `json:"transfer_bytes,omitempty"`
+	RequestStart   float64 `json:"request_start,omitempty"`
+	DownloadMs     float64 `json:"download_ms,omitempty"`
+	EvalStart      float64 `json:"eval_start,omitempty"`
+	LoadToEvalMs   float64 `json:"load_to_eval_ms,omitempty"` // Request start until evaluation began
+	ParseMs        float64 `json:"parse_ms"`
+	CompileMs      float64 `json:"compile_ms"`
+	ExecuteMs      float64 `json:"execute_ms"` // Evaluation time excluding nested parse and compile
+	MainThreadMs   float64 `json:"main_thread_ms"`
+	BackgroundMs   float64 `json:"background_ms"`
+	TotalMs        float64 `json:"total_ms"`
+	Streamed       bool    `json:"streamed,omitempty"`         // Parsed while downloading
+	CodeCacheBytes int64   `json:"code_cache_bytes,omitempty"` // Bytes consumed from V8's code cache
+	Events         int     `json:"events"`
+}
+
+// ScriptCostAnalysis contains per-script JavaScript load and evaluation costs
+type ScriptCostAnalysis struct {
+	TotalScripts    int          `json:"total_scripts"`
+	TotalParseMs    float64      `json:"total_parse_ms"`
+	TotalCompileMs  float64      `json:"total_compile_ms"`
+	TotalExecuteMs  float64      `json:"total_execute_ms"`
+	MainThreadMs    float64      `json:"main_thread_ms"`
+	MainCompileMs   float64      `json:"main_compile_ms"` // Parse and compile that blocked the main thread
+	BackgroundMs    float64      `json:"background_ms"`
+	Scripts         []ScriptCost `json:"scripts"`
+	Recommendations []string     `json:"recommendations,omitempty"`
+}
+
+// scriptEvent is a parse, compile or execute marker attributed to a script
+type scriptEvent struct {
+	url        string
+	phase      string
+	start      float64
+	end        float64
+	thread     int
+	background bool
+	data       map[string]interface{}
+}
+
+// AnalyzeScriptCosts breaks JavaScript cost down per script URL into parse,
+// compile and execution time, split between the main thread and background
+// (streaming or off-thread) compilation. Execution excludes parse and compile
+// nested inside the evaluation on the same thread. Script requests found by
+// ExtractNetworkRequests supply the transfer size and download-to-eval time.
+func AnalyzeScriptCosts(profile *parser.Profile, limit int) ScriptCostAnalysis {
+	analysis := ScriptCostAnalysis{
+		Scripts: make([]ScriptCost, 0),
+	}
+
+	if limit <= 0 {
+		limit = 20
+	}
+
+	events := extractScriptEvents(profile)
+	if len(events) == 0 {
+		analysis.Recommendations = scriptCostRecommendations(analysis)
+		return analysis
+	}
+
+	scripts := make(map[string]*ScriptCost)
+	get := func(url string) *ScriptCost {
+		s := scripts[url]
+		if s == nil {
+			s = &ScriptCost{URL: url, EvalStart: -1}
+			scripts[url] = s
+		}
+		return s
+	}
+
+	for i, ev := range events {
+		dur := ev.end - ev.start
+		if ev.phase == ScriptPhaseExecute {
+			dur -= nestedScriptWork(events, i)
+		}
+		dur = math.Max(0, dur)
+
+		s := get(ev.url)
+		s.Events++
+		switch ev.phase {
+		case ScriptPhaseParse:
+			s.ParseMs += dur
+		case ScriptPhaseCompile:
+			s.CompileMs += dur
+			if streamed, _ := ev.data["streamed"].(bool); streamed {
+				s.Streamed = true
+			}
+			if size, ok := ev.data["consumedCacheSize"].(float64); ok && size > 0 {
+				s.CodeCacheBytes += int64(size)
+			}
+		case ScriptPhaseExecute:
+			s.ExecuteMs += dur
+			if s.EvalStart < 0 || ev.start < s.EvalStart {
+				s.EvalStart = ev.start
+			}
+		}
+		if ev.background {
+			s.BackgroundMs += dur
+			if ev.phase == ScriptPhaseParse {
+				s.Streamed = true
+			}
+		} else {
+			s.MainThreadMs += dur
+			if ev.phase != ScriptPhaseExecute {
+				analysis.MainCompileMs += dur
+			}
+		}
+	}
+
+	// Attach download timings from the script's network request
+	requests := make(map[string]NetworkRequest)
+	for _, r := range ExtractNetworkRequests(profile) {
+		if _, seen := requests[scriptURLKey(r.URL)]; !seen {
+			requests[scriptURLKey(r.URL)] = r
+		}
+	}
+
+	for _, s := range scripts {
+		s.TotalMs = s.ParseMs + s.CompileMs + s.ExecuteMs
+		if r, ok := requests[scriptURLKey(s.URL)]; ok {
+			s.TransferBytes = r.TransferBytes
+			s.RequestStart = r.StartTime
+			s.DownloadMs = r.DurationMs
+			if s.EvalStart >= r.StartTime {
+				s.LoadToEvalMs = s.EvalStart - r.StartTime
+			}
+		}
+		if s.EvalStart < 0 {
+			s.EvalStart = 0
+		}
+
+		analysis.TotalParseMs += s.ParseMs
+		analysis.TotalCompileMs += s.CompileMs
+		analysis.TotalExecuteMs += s.ExecuteMs
+		analysis.MainThreadMs += s.MainThreadMs
+		analysis.BackgroundMs += s.BackgroundMs
+		analysis.Scripts = append(analysis.Scripts, *s)
+	}
+	analysis.TotalScripts = len(analysis.Scripts)
+
+	sort.Slice(analysis.Scripts, func(i, j int) bool {
+		if analysis.Scripts[i].TotalMs != analysis.Scripts[j].TotalMs {
+			return analysis.Scripts[i].TotalMs > analysis.Scripts[j].TotalMs
+		}
+		return analysis.Scripts[i].URL < analysis.Scripts[j].URL
+	})
+	if len(analysis.Scripts) > limit {
+		analysis.Scripts = analysis.Scripts[:limit]
+	}
+	analysis.Recommendations = scriptCostRecommendations(analysis)
+
+	return analysis
+}
+
+// extractScriptEvents collects script phase markers from all threads in start order
+func extractScriptEvents(profile *parser.Profile) []scriptEvent {
+	var events []scriptEvent
+	for i := range profile.Threads {
+		thread := &profile.Threads[i]
+		for _, m := range parser.ExtractMarkers(thread, profile.Meta.Categories) {
+			phase, ok := scriptPhaseMarkers[m.Name]
+			if !ok || m.EndTime <= m.StartTime {
+				continue
+			}
+			data := milestoneData(m.Data)
+			url := scriptEventURL(data)
+			if url == "" {
+				url = ScriptUnknownURL
+			}
+			events = append(events, scriptEvent{
+				url:        url,
+				phase:      phase,
+				start:      m.StartTime,
+				end:        m.EndTime,
+				thread:     i,
+				background: scriptBackgroundMarkers[m.Name] || isScriptHelperThread(thread.Name),
+				data:       data,
+			})
+		}
+	}
+	// Enclosing events come before the ones nested inside them that start at the same time
+	sort.SliceStable(events, func(i, j int) bool {
+		if events[i].start != events[j].start {
+			return events[i].start < events[j].start
+		}
+		return events[i].end > events[j].end
+	})
+	return events
+}
+
+// scriptEventURL returns the script URL carried by a Chrome or Firefox event payload
+func scriptEventURL(data map[string]interface{}) string {
+	for _, key := range []string{"url", "fileName", "filename", "scriptUrl", "name"} {
+		if url, ok := data[key].(string); ok && url != "" {
+			return url
+		}
+	}
+	return ""
+}
+
+// nestedScriptWork sums the parse and compile time nested inside an evaluation on the
+// same thread. Only direct children count: a parse inside a nested compile is
+// already part of the compile's time.
+func nestedScriptWork(events []scriptEvent, idx int) float64 {
+	outer := events[idx]
+	nested := 0.0
+	counted := outer.start // End of the last event subtracted; events inside it are already covered
+	for j := idx + 1; j < len(events) && events[j].start < outer.end; j++ {
+		ev := events[j]
+		if ev.thread != outer.thread || ev.phase == ScriptPhaseExecute || ev.end > outer.end || ev.start < counted {
+			continue
+		}
+		nested += ev.end - ev.start
+		counted = ev.end
+	}
+	return nested
+}
+
+// scriptURLKey normalizes a script URL for matching against network requests
+func scriptURLKey(url string) string {
+	if i := strings.IndexByte(url, '#'); i >= 0 {
+		url = url[:i]
+	}
+	return url
+}
+
+// scriptCostRecommendations suggests follow-ups based on script costs
+func scriptCostRecommendations(analysis ScriptCostAnalysis) []string {
+	var recs []string
+	if analysis.TotalScripts == 0 {
+		return append(recs, "No script parse, compile or evaluation events were found; Chrome traces need the v8 and devtools.timeline categories")
+	}
+
+	if analysis.MainCompileMs > 50 {
+		recs = append(recs, fmt.Sprintf("%.0fms of parse and compile ran on the main thread; load large scripts with async or defer so V8 can stream-compile them off the main thread", analysis.MainCompileMs))
+	}
+
+	for _, s := range analysis.Scripts {
+		if s.URL == ScriptUnknownURL {
+			continue
+		}
+		if compile := s.ParseMs + s.CompileMs; compile > 50 && compile > s.ExecuteMs {
+			recs = append(recs, fmt.Sprintf("%s spends %.0fms parsing and compiling but only %.0fms executing; code-split it or remove unused code", s.URL, compile, s.ExecuteMs))
+			break
+		}
+	}
+
+	for _, s := range analysis.Scripts {
+		if s.ExecuteMs > 100 {
+			recs = append(recs, fmt.Sprintf("Top-level evaluation of %s takes %.0fms; defer work that is not needed for first render", s.URL, s.ExecuteMs))
+			break
+		}
+	}
+
+	for _, s := range analysis.Scripts {
+		if s.LoadToEvalMs > 1000 {
+			recs = append(recs, fmt.Sprintf("%s starts evaluating %.0fms after it was requested; preload it or move it earlier in the document", s.URL, s.LoadToEvalMs))
+			break
+		}
+	}
+
+	return recs
+}
@@ -0,0 +1,130 @@
+package analyzer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/CedricHerzog/perfowl/internal/testutil"
+)
+
+func TestAnalyzeScriptCosts_Breakdown(t *testing.T) {
+	analysis := AnalyzeScriptCosts(testutil.ProfileWithScriptCosts(), 10)
+
+	if analysis.TotalScripts != 3 {
+		t.Fatalf("expected 3 scripts (app.js, vendor.js, unknown), got %d", analysis.TotalScripts)
+	}
+
+	app := analysis.Scripts[0]
+	if app.URL != "https://example.com/app.js" {
+		t.Fatalf("expected app.js to be the most expensive script, got %s", app.URL)
+	}
+	testutil.AssertFloatApproxEqual(t, app.CompileMs, 60, 0.01)
+	testutil.AssertFloatApproxEqual(t, app.ExecuteMs, 140, 0.01) // Nested compile is not execution
+	testutil.AssertFloatApproxEqual(t, app.MainThreadMs, 200, 0.01)
+	testutil.AssertFloatApproxEqual(t, app.BackgroundMs, 0, 0.01)
+	testutil.AssertFloatApproxEqual(t, app.DownloadMs, 100, 0.01)
+	testutil.AssertFloatApproxEqual(t, app.LoadToEvalMs, 190, 0.01)
+	if app.TransferBytes != 50000 {
+		t.Errorf("TransferBytes = %d, want 50000", app.TransferBytes)
+	}
+
+	vendor := analysis.Scripts[1]
+	if vendor.URL != "https://example.com/vendor.js" {
+		t.Fatalf("expected vendor.js second, got %s", vendor.URL)
+	}
+	testutil.AssertFloatApproxEqual(t, vendor.ParseMs, 80, 0.01)
+	testutil.AssertFloatApproxEqual(t, vendor.BackgroundMs, 80, 0.01)
+	testutil.AssertFloatApproxEqual(t, vendor.MainThreadMs, 40, 0.01)
+	if !vendor.Streamed || vendor.CodeCacheBytes != 1024 {
+		t.Errorf("vendor.js streamed=%v cache=%d, want streamed with 1024 cached bytes", vendor.Streamed, vendor.CodeCacheBytes)
+	}
+
+	if analysis.Scripts[2].URL != ScriptUnknownURL {
+		t.Errorf("expected URL-less compile under %s, got %s", ScriptUnknownURL, analysis.Scripts[2].URL)
+	}
+
+	testutil.AssertFloatApproxEqual(t, analysis.MainCompileMs, 90, 0.01)
+	testutil.AssertFloatApproxEqual(t, analysis.BackgroundMs, 80, 0.01)
+	testutil.AssertFloatApproxEqual(t, analysis.TotalExecuteMs, 160, 0.01)
+}
+
+func TestAnalyzeScriptCosts_WorkerEvaluation(t *testing.T) {
+	data := map[string]interface{}{"data": map[string]interface{}{"url": "https://example.com/worker.js"}}
+	markers, strs := testutil.NewMarkerBuilder().
+		AddCustom("v8.compile", 2, 100, 10, data).
+		AddCustom("EvaluateScript", 2, 110, 30, data).
+		Build()
+	profile := testutil.NewProfileBuilder().
+		WithDuration(1000).
+		WithCategories(testutil.DefaultCategories()).
+		WithThread(testutil.NewThreadBuilder("CrRendererMain").AsMainThread().Build()).
+		WithThread(testutil.NewThreadBuilder("DedicatedWorker thread").
+			WithStringArray(strs).
+			WithMarkers(markers).
+			Build()).
+		Build()
+
+	analysis := AnalyzeScriptCosts(profile, 10)
+
+	testutil.AssertSliceLen(t, analysis.Scripts, 1)
+	worker := analysis.Scripts[0]
+	testutil.AssertFloatApproxEqual(t, worker.MainThreadMs, 40, 0.01)
+	testutil.AssertFloatApproxEqual(t, worker.BackgroundMs, 0, 0.01)
+	testutil.AssertFalse(t, worker.Streamed, "a worker compiling its own script is not streamed")
+}
+
+func TestAnalyzeScriptCosts_NestedCompileAndParse(t *testing.T) {
+	data := map[string]interface{}{"data": map[string]interface{}{"url": "https://example.com/app.js"}}
+	markers, strs := testutil.NewMarkerBuilder().
+		AddCustom("v8.compile", 2, 100, 60, data). // Same start as the evaluation it is nested in
+		AddCustom("EvaluateScript", 2, 100, 100, data).
+		AddCustom("v8.parseOnBackgroundWaiting", 2, 120, 30, data). // Inside the compile
+		AddCustom("v8.compile", 2, 170, 10, data).
+		Build()
+	profile := testutil.NewProfileBuilder().
+		WithDuration(1000).
+		WithCategories(testutil.DefaultCategories()).
+		WithThread(testutil.NewThreadBuilder("CrRendererMain").
+			AsMainThread().
+			WithStringArray(strs).
+			WithMarkers(markers).
+			Build()).
+		Build()
+
+	analysis := AnalyzeScriptCosts(profile, 10)
+
+	testutil.AssertSliceLen(t, analysis.Scripts, 1)
+	// 100ms evaluation minus its two direct compiles; the parse is part of the first
+	testutil.AssertFloatApproxEqual(t, analysis.Scripts[0].ExecuteMs, 30, 0.01)
+}
+
+func TestAnalyzeScriptCosts_Recommendations(t *testing.T) {
+	analysis := AnalyzeScriptCosts(testutil.ProfileWithScriptCosts(), 10)
+
+	joined := strings.Join(analysis.Recommendations, "\n")
+	if !strings.Contains(joined, "90ms of parse and compile ran on the main thread") {
+		t.Errorf("expected main-thread compile recommendation, got %v", analysis.Recommendations)
+	}
+	if !strings.Contains(joined, "Top-level evaluation of https://example.com/app.js takes 140ms") {
+		t.Errorf("expected top-level evaluation recommendation, got %v", analysis.Recommendations)
+	}
+}
+
+func TestAnalyzeScriptCosts_Limit(t *testing.T) {
+	analysis := AnalyzeScriptCosts(testutil.ProfileWithScriptCosts(), 1)
+
+	if len(analysis.Scripts) != 1 || analysis.TotalScripts != 3 {
+		t.Errorf("expected 1 of 3 scripts, got %d of %d", len(analysis.Scripts), analysis.TotalScripts)
+	}
+}
+
+func TestAnalyzeScriptCosts_NoEvents(t *testing.T) {
+	analysis := AnalyzeScriptCosts(testutil.MinimalProfile(), 10)
+
+	if analysis.TotalScripts != 0 || analysis.Scripts == nil {
+		t.Errorf("expected an empty, non-nil script list, got %+v", analysis.Scripts)
+	}
+	if len(analysis.Recommendations) != 1 {
+		t.Errorf("expected a recommendation about missing events, got %v", analysis.Recommendations)
+	}
+}
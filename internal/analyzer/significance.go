@@ -0,0 +1,204 @@
+package analyzer
+
+import (
+	"math"
+	"sort"
+)
+
+// Significance test settings
+const (
+	SignificanceDefaultAlpha = 0.05
+	significanceExactMaxRuns = 20 // Larger samples, or samples with ties, use the normal approximation
+
+	// Cliff's delta magnitude thresholds (Romano et al.)
+	effectNegligible = 0.147
+	effectSmall      = 0.33
+	effectMedium     = 0.474
+)
+
+// Verdicts for a metric compared across two sets of runs
+const (
+	VerdictImproved     = "improved"
+	VerdictRegressed    = "regressed"
+	VerdictUnchanged    = "unchanged"
+	VerdictInconclusive = "inconclusive" // Too few runs for any difference to reach significance
+)
+
+// MannWhitneyResult is the outcome of a two-sided Mann-Whitney U test
+type MannWhitneyResult struct {
+	U      float64 `json:"u"` // Pairs where the candidate is larger, counting ties as half
+	PValue float64 `json:"p_value"`
+	Exact  bool    `json:"exact"` // P-value from the exact distribution rather than the normal approximation
+	MinP   float64 `json:"min_p"` // Smallest p-value these sample sizes can produce
+}
+
+// MannWhitneyU tests whether candidate values tend to differ from baseline values.
+// Small tie-free samples use the exact distribution of U; otherwise the normal
+// approximation with tie and continuity corrections is used.
+func MannWhitneyU(baseline, candidate []float64) MannWhitneyResult {
+	n, m := len(baseline), len(candidate)
+	if n == 0 || m == 0 {
+		return MannWhitneyResult{PValue: 1, MinP: 1}
+	}
+
+	var u float64
+	for _, c := range candidate {
+		for _, b := range baseline {
+			switch {
+			case c > b:
+				u++
+			case c == b:
+				u += 0.5
+			}
+		}
+	}
+
+	result := MannWhitneyResult{U: u}
+	ties := tieCounts(baseline, candidate)
+	if n+m <= significanceExactMaxRuns {
+		dist := mannWhitneyDistribution(n, m)
+		total := 0.0
+		for _, c := range dist {
+			total += c
+		}
+		result.MinP = math.Min(1, 2*dist[0]/total)
+		if len(ties) == 0 {
+			k := int(u)
+			var lower, upper float64
+			for i, c := range dist {
+				if i <= k {
+					lower += c
+				}
+				if i >= k {
+					upper += c
+				}
+			}
+			result.PValue = math.Min(1, 2*math.Min(lower, upper)/total)
+			result.Exact = true
+			return result
+		}
+	} else {
+		result.MinP = 0
+	}
+
+	nf, mf := float64(n), float64(m)
+	total := nf + mf
+	var tieSum float64
+	for _, t := range ties {
+		tieSum += float64(t*t*t - t)
+	}
+	variance := nf * mf / 12 * ((total + 1) - tieSum/(total*(total-1)))
+	if variance <= 0 {
+		result.PValue = 1
+		return result
+	}
+	z := math.Max(0, math.Abs(u-nf*mf/2)-0.5) / math.Sqrt(variance)
+	result.PValue = math.Min(1, math.Erfc(z/math.Sqrt2))
+	return result
+}
+
+// mannWhitneyDistribution counts the orderings of n baseline and m candidate values
+// giving each U from 0 to n*m
+func mannWhitneyDistribution(n, m int) []float64 {
+	// counts[i][j][u] built up one value at a time; only two rows of i are kept
+	prev := make([][]float64, m+1)
+	for j := range prev {
+		prev[j] = make([]float64, n*m+1)
+		prev[j][0] = 1 // No baseline values: U is always 0
+	}
+	for i := 1; i <= n; i++ {
+		cur := make([][]float64, m+1)
+		for j := range cur {
+			cur[j] = make([]float64, n*m+1)
+		}
+		cur[0][0] = 1
+		for j := 1; j <= m; j++ {
+			for u := 0; u <= i*j; u++ {
+				// Largest value is a candidate (beats all i baseline values) or a baseline value
+				if u >= i {
+					cur[j][u] += cur[j-1][u-i]
+				}
+				cur[j][u] += prev[j][u]
+			}
+		}
+		prev = cur
+	}
+	return prev[m]
+}
+
+// tieCounts returns the size of each group of equal values across both samples
+func tieCounts(a, b []float64) []int {
+	all := make([]float64, 0, len(a)+len(b))
+	all = append(all, a...)
+	all = append(all, b...)
+	sort.Float64s(all)
+
+	var ties []int
+	for i := 0; i < len(all); {
+		j := i + 1
+		for j < len(all) && all[j] == all[i] {
+			j++
+		}
+		if j-i > 1 {
+			ties = append(ties, j-i)
+		}
+		i = j
+	}
+	return ties
+}
+
+// CliffsDelta is the probability a candidate value is larger than a baseline value
+// minus the probability it is smaller, from -1 to 1
+func CliffsDelta(baseline, candidate []float64, u float64) float64 {
+	pairs := float64(len(baseline) * len(candidate))
+	if pairs == 0 {
+		return 0
+	}
+	return 2*u/pairs - 1
+}
+
+// effectMagnitude labels the size of a Cliff's delta
+func effectMagnitude(delta float64) string {
+	switch d := math.Abs(delta); {
+	case d < effectNegligible:
+		return "negligible"
+	case d < effectSmall:
+		return "small"
+	case d < effectMedium:
+		return "medium"
+	default:
+		return "large"
+	}
+}
+
+// holmAdjust applies the Holm-Bonferroni step-down correction to a family of
+// p-values, returning the adjusted p-values in the same order
+func holmAdjust(pValues []float64) []float64 {
+	order := make([]int, len(pValues))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool { return pValues[order[a]] < pValues[order[b]] })
+
+	adjusted := make([]float64, len(pValues))
+	running := 0.0
+	for rank, i := range order {
+		running = math.Max(running, math.Min(1, float64(len(pValues)-rank)*pValues[i]))
+		adjusted[i] = running
+	}
+	return adjusted
+}
+
+// significanceVerdict decides a metric's verdict; lower values are better
+func significanceVerdict(test MannWhitneyResult, delta, alpha float64) string {
+	switch {
+	case test.PValue < alpha && delta < 0:
+		return VerdictImproved
+	case test.PValue < alpha && delta > 0:
+		return VerdictRegressed
+	case test.MinP >= alpha:
+		return VerdictInconclusive
+	default:
+		return VerdictUnchanged
+	}
+}
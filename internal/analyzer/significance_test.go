@@ -0,0 +1,107 @@
+package analyzer
+
+import (
+	"testing"
+
+	"github.com/CedricHerzog/perfowl/internal/testutil"
+)
+
+func TestMannWhitneyU_Exact(t *testing.T) {
+	result := MannWhitneyU([]float64{1, 2, 3, 4, 5}, []float64{6, 7, 8, 9, 10})
+
+	if !result.Exact {
+		t.Error("expected the exact distribution for small tie-free samples")
+	}
+	if result.U != 25 {
+		t.Errorf("U = %v, want 25", result.U)
+	}
+	// Only 1 of C(10,5) = 252 orderings is this extreme in each direction
+	testutil.AssertFloatApproxEqual(t, result.PValue, 2.0/252, 1e-12)
+	testutil.AssertFloatApproxEqual(t, result.MinP, 2.0/252, 1e-12)
+}
+
+func TestMannWhitneyU_ExactOverlap(t *testing.T) {
+	result := MannWhitneyU([]float64{1, 3, 5}, []float64{2, 6, 7})
+	if result.U != 7 {
+		t.Errorf("U = %v, want 7", result.U)
+	}
+	// U = 7 of 9: P(U >= 7) = P(U <= 2) = 4 of C(6,3) = 20 orderings
+	testutil.AssertFloatApproxEqual(t, result.PValue, 0.4, 1e-12)
+}
+
+func TestMannWhitneyU_NormalApproximation(t *testing.T) {
+	baseline := make([]float64, 0, 15)
+	candidate := make([]float64, 0, 15)
+	for i := 0; i < 15; i++ {
+		baseline = append(baseline, float64(100+i%5))
+		candidate = append(candidate, float64(110+i%5))
+	}
+	result := MannWhitneyU(baseline, candidate)
+
+	if result.Exact {
+		t.Error("expected the normal approximation for large samples with ties")
+	}
+	if result.U != 225 {
+		t.Errorf("U = %v, want 225", result.U)
+	}
+	testutil.AssertLess(t, result.PValue, 0.001)
+}
+
+func TestMannWhitneyU_Identical(t *testing.T) {
+	result := MannWhitneyU([]float64{5, 5, 5}, []float64{5, 5, 5})
+	if result.PValue != 1 {
+		t.Errorf("PValue = %v, want 1 for identical samples", result.PValue)
+	}
+}
+
+func TestMannWhitneyU_Empty(t *testing.T) {
+	result := MannWhitneyU(nil, []float64{1})
+	if result.PValue != 1 || result.MinP != 1 {
+		t.Errorf("expected p = 1 for an empty sample, got %+v", result)
+	}
+}
+
+func TestCliffsDelta(t *testing.T) {
+	base, cand := []float64{1, 2, 3, 4, 5}, []float64{6, 7, 8, 9, 10}
+	testutil.AssertFloatApproxEqual(t, CliffsDelta(base, cand, MannWhitneyU(base, cand).U), 1, 1e-12)
+	testutil.AssertFloatApproxEqual(t, CliffsDelta(cand, base, MannWhitneyU(cand, base).U), -1, 1e-12)
+	testutil.AssertFloatApproxEqual(t, CliffsDelta(nil, cand, 0), 0, 1e-12)
+}
+
+func TestEffectMagnitude(t *testing.T) {
+	tests := map[float64]string{0.1: "negligible", -0.2: "small", 0.4: "medium", -0.9: "large"}
+	for delta, want := range tests {
+		if got := effectMagnitude(delta); got != want {
+			t.Errorf("effectMagnitude(%v) = %q, want %q", delta, got, want)
+		}
+	}
+}
+
+func TestHolmAdjust(t *testing.T) {
+	adjusted := holmAdjust([]float64{0.04, 0.01, 0.03, 0.5})
+
+	// Sorted: 0.01*4, 0.03*3, 0.04*2, 0.5*1, each at least the one before it
+	want := []float64{0.09, 0.04, 0.09, 0.5}
+	for i := range want {
+		testutil.AssertFloatApproxEqual(t, adjusted[i], want[i], 1e-12)
+	}
+	testutil.AssertSliceLen(t, holmAdjust(nil), 0)
+}
+
+func TestSignificanceVerdict(t *testing.T) {
+	tests := []struct {
+		test  MannWhitneyResult
+		delta float64
+		want  string
+	}{
+		{MannWhitneyResult{PValue: 0.01, MinP: 0.008}, -0.8, VerdictImproved},
+		{MannWhitneyResult{PValue: 0.01, MinP: 0.008}, 0.8, VerdictRegressed},
+		{MannWhitneyResult{PValue: 0.4, MinP: 0.008}, 0.2, VerdictUnchanged},
+		{MannWhitneyResult{PValue: 0.1, MinP: 0.1}, 1, VerdictInconclusive},
+	}
+	for _, tt := range tests {
+		if got := significanceVerdict(tt.test, tt.delta, 0.05); got != tt.want {
+			t.Errorf("significanceVerdict(%+v, %v) = %q, want %q", tt.test, tt.delta, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,418 @@
+package analyzer
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/CedricHerzog/perfowl/internal/parser"
+)
+
+// SourceLineOptions selects the code to attribute per line
+type SourceLineOptions struct {
+	Function     string // Function name filter (case-insensitive substring match)
+	File         string // File name or URL filter (case-insensitive substring match)
+	SourceRoot   string // Local directory to resolve source files for annotated listings
+	ContextLines int    // Lines of context around sampled lines in listings
+	Limit        int    // Maximum number of hot lines to return
+}
+
+// LineStats contains the time attributed to a single source line
+type LineStats struct {
+	File          string  `json:"file,omitempty"`
+	Function      string  `json:"function,omitempty"`
+	Line          int     `json:"line"`
+	SelfTimeMs    float64 `json:"self_time_ms"`
+	TotalTimeMs   float64 `json:"total_time_ms"`
+	SelfPercent   float64 `json:"self_percent"`
+	SampleCount   int     `json:"sample_count"`
+	FunctionStart bool    `json:"function_start,omitempty"` // Frames had no line, time is on the function's first line
+}
+
+// AnnotatedLine is a source line with its attributed time
+type AnnotatedLine struct {
+	Line        int     `json:"line"`
+	SelfTimeMs  float64 `json:"self_time_ms"`
+	TotalTimeMs float64 `json:"total_time_ms"`
+	Source      string  `json:"source"`
+	Gap         bool    `json:"gap,omitempty"` // Marks skipped lines between two listing blocks
+}
+
+// FileLineStats contains per-line attribution for a single file
+type FileLineStats struct {
+	File        string          `json:"file"`
+	SourcePath  string          `json:"source_path,omitempty"`
+	SourceError string          `json:"source_error,omitempty"`
+	SelfTimeMs  float64         `json:"self_time_ms"`
+	Functions   []string        `json:"functions"`
+	Lines       []LineStats     `json:"lines"`
+	Listing     []AnnotatedLine `json:"listing,omitempty"`
+}
+
+// SourceLineAnalysis contains source line attribution results
+type SourceLineAnalysis struct {
+	Function         string          `json:"function,omitempty"`
+	File             string          `json:"file,omitempty"`
+	ProfileTimeMs    float64         `json:"profile_time_ms"`
+	SelfTimeMs       float64         `json:"self_time_ms"`
+	UnattributedMs   float64         `json:"unattributed_ms,omitempty"` // Matching frames without any line information
+	Files            []FileLineStats `json:"files"`
+	HotLines         []LineStats     `json:"hot_lines"`
+	MatchedFunctions []string        `json:"matched_functions"`
+}
+
+// AnalyzeSourceLines attributes self and total time to source lines of the functions
+// or files matching the options. Frame line numbers are used when the profile has
+// them, otherwise time falls back to the function's first line.
+func AnalyzeSourceLines(profile *parser.Profile, opts SourceLineOptions) (SourceLineAnalysis, error) {
+	analysis := SourceLineAnalysis{
+		Function:         opts.Function,
+		File:             opts.File,
+		Files:            make([]FileLineStats, 0),
+		HotLines:         make([]LineStats, 0),
+		MatchedFunctions: make([]string, 0),
+	}
+
+	if opts.Function == "" && opts.File == "" {
+		return analysis, fmt.Errorf("a function or file filter is required")
+	}
+	if opts.Limit <= 0 {
+		opts.Limit = 20
+	}
+
+	functionFilter := strings.ToLower(opts.Function)
+	fileFilter := strings.ToLower(opts.File)
+	sharedStrings := profile.Shared.StringArray
+	interval := profile.Meta.Interval
+
+	type lineKey struct {
+		file string
+		line int
+	}
+	type frameLine struct {
+		matched       bool
+		key           lineKey
+		function      string
+		functionStart bool
+	}
+
+	lineStats := make(map[lineKey]*LineStats)
+	fileFuncs := make(map[string]map[string]bool)
+	matchedFuncs := make(map[string]bool)
+
+	for _, thread := range profile.Threads {
+		stackTable := &thread.StackTable
+		frameTable := &thread.FrameTable
+		funcTable := &thread.FuncTable
+		samples := &thread.Samples
+
+		// Use thread's string array, fall back to shared if empty
+		stringArray := thread.StringArray
+		if len(stringArray) == 0 {
+			stringArray = sharedStrings
+		}
+
+		getString := func(idx int) string {
+			if idx >= 0 && idx < len(stringArray) {
+				return stringArray[idx]
+			}
+			return ""
+		}
+
+		// Resolve each frame once
+		frames := make(map[int]*frameLine)
+		resolveFrame := func(frameIdx int) *frameLine {
+			if fl, ok := frames[frameIdx]; ok {
+				return fl
+			}
+			fl := &frameLine{}
+			frames[frameIdx] = fl
+
+			if frameIdx < 0 || frameIdx >= len(frameTable.Func) {
+				return fl
+			}
+			funcIdx := frameTable.Func[frameIdx]
+			if funcIdx < 0 || funcIdx >= funcTable.Length {
+				return fl
+			}
+
+			name := ""
+			if funcIdx < len(funcTable.Name) {
+				name = getString(funcTable.Name[funcIdx])
+			}
+			file := ""
+			if funcIdx < len(funcTable.FileName) {
+				file = getString(funcTable.FileName[funcIdx])
+			}
+
+			if functionFilter != "" && !strings.Contains(strings.ToLower(name), functionFilter) {
+				return fl
+			}
+			if fileFilter != "" && !strings.Contains(strings.ToLower(file), fileFilter) {
+				return fl
+			}
+
+			fl.matched = true
+			fl.function = name
+			fl.key = lineKey{file: file, line: frameLineNumber(frameTable, frameIdx)}
+			if fl.key.line <= 0 && funcIdx < len(funcTable.LineNumber) && funcTable.LineNumber[funcIdx] > 0 {
+				fl.key.line = funcTable.LineNumber[funcIdx]
+				fl.functionStart = true
+			}
+			return fl
+		}
+
+		// Aggregate time per unique stack first
+		stackTime := make(map[int]float64)
+		stackCount := make(map[int]int)
+		for i := 0; i < samples.Length; i++ {
+			stackIdx := -1
+			if i < len(samples.Stack) {
+				stackIdx = samples.Stack[i]
+			}
+			if stackIdx < 0 || stackIdx >= stackTable.Length {
+				continue
+			}
+
+			cpuDelta := interval
+			if i < len(samples.ThreadCPUDelta) && samples.ThreadCPUDelta[i] > 0 {
+				cpuDelta = float64(samples.ThreadCPUDelta[i]) / 1000.0
+			}
+
+			analysis.ProfileTimeMs += cpuDelta
+			stackTime[stackIdx] += cpuDelta
+			stackCount[stackIdx]++
+		}
+
+		getLine := func(fl *frameLine) *LineStats {
+			ls := lineStats[fl.key]
+			if ls == nil {
+				ls = &LineStats{File: fl.key.file, Function: fl.function, Line: fl.key.line, FunctionStart: fl.functionStart}
+				lineStats[fl.key] = ls
+				if fileFuncs[fl.key.file] == nil {
+					fileFuncs[fl.key.file] = make(map[string]bool)
+				}
+			}
+			fileFuncs[fl.key.file][fl.function] = true
+			matchedFuncs[fl.function] = true
+			return ls
+		}
+
+		for stackIdx, timeMs := range stackTime {
+			// Self time goes to the leaf frame's line
+			if stackIdx < len(stackTable.Frame) {
+				if fl := resolveFrame(stackTable.Frame[stackIdx]); fl.matched {
+					analysis.SelfTimeMs += timeMs
+					if fl.key.line <= 0 {
+						analysis.UnattributedMs += timeMs
+					} else {
+						ls := getLine(fl)
+						ls.SelfTimeMs += timeMs
+						ls.SampleCount += stackCount[stackIdx]
+					}
+				}
+			}
+
+			// Total time goes to every matching line on the stack, once per stack
+			seen := make(map[lineKey]bool)
+			for current := stackIdx; current >= 0 && current < stackTable.Length; {
+				if current < len(stackTable.Frame) {
+					if fl := resolveFrame(stackTable.Frame[current]); fl.matched && fl.key.line > 0 && !seen[fl.key] {
+						seen[fl.key] = true
+						getLine(fl).TotalTimeMs += timeMs
+					}
+				}
+				if current >= len(stackTable.Prefix) {
+					break
+				}
+				current = stackTable.Prefix[current]
+			}
+		}
+	}
+
+	// Group lines by file
+	byFile := make(map[string]*FileLineStats)
+	for _, ls := range lineStats {
+		if analysis.ProfileTimeMs > 0 {
+			ls.SelfPercent = (ls.SelfTimeMs / analysis.ProfileTimeMs) * 100
+		}
+		fs := byFile[ls.File]
+		if fs == nil {
+			fs = &FileLineStats{File: ls.File, Functions: make([]string, 0), Lines: make([]LineStats, 0)}
+			byFile[ls.File] = fs
+		}
+		fs.SelfTimeMs += ls.SelfTimeMs
+		fs.Lines = append(fs.Lines, *ls)
+		analysis.HotLines = append(analysis.HotLines, *ls)
+	}
+
+	for file, fs := range byFile {
+		sort.Slice(fs.Lines, func(i, j int) bool {
+			return fs.Lines[i].Line < fs.Lines[j].Line
+		})
+		for name := range fileFuncs[file] {
+			fs.Functions = append(fs.Functions, name)
+		}
+		sort.Strings(fs.Functions)
+
+		if opts.SourceRoot != "" {
+			annotateFileLines(fs, opts.SourceRoot, opts.ContextLines)
+		}
+
+		analysis.Files = append(analysis.Files, *fs)
+	}
+	sort.Slice(analysis.Files, func(i, j int) bool {
+		if analysis.Files[i].SelfTimeMs != analysis.Files[j].SelfTimeMs {
+			return analysis.Files[i].SelfTimeMs > analysis.Files[j].SelfTimeMs
+		}
+		return analysis.Files[i].File < analysis.Files[j].File
+	})
+
+	sort.Slice(analysis.HotLines, func(i, j int) bool {
+		a, b := analysis.HotLines[i], analysis.HotLines[j]
+		if a.SelfTimeMs != b.SelfTimeMs {
+			return a.SelfTimeMs > b.SelfTimeMs
+		}
+		if a.TotalTimeMs != b.TotalTimeMs {
+			return a.TotalTimeMs > b.TotalTimeMs
+		}
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		return a.Line < b.Line
+	})
+	if len(analysis.HotLines) > opts.Limit {
+		analysis.HotLines = analysis.HotLines[:opts.Limit]
+	}
+
+	for name := range matchedFuncs {
+		analysis.MatchedFunctions = append(analysis.MatchedFunctions, name)
+	}
+	sort.Strings(analysis.MatchedFunctions)
+
+	return analysis, nil
+}
+
+// frameLineNumber returns the line of a frame, or 0 if the profile has none
+func frameLineNumber(frameTable *parser.FrameTable, frameIdx int) int {
+	if frameIdx < 0 || frameIdx >= len(frameTable.Line) {
+		return 0
+	}
+	switch v := frameTable.Line[frameIdx].(type) {
+	case int:
+		return v
+	case float64:
+		return int(v)
+	case json.Number:
+		if n, err := v.Int64(); err == nil {
+			return int(n)
+		}
+	}
+	return 0
+}
+
+// annotateFileLines reads the source file from root and builds a listing of the
+// sampled lines with contextLines of surrounding code
+func annotateFileLines(fs *FileLineStats, root string, contextLines int) {
+	path, ok := ResolveSourceFile(root, fs.File)
+	if !ok {
+		fs.SourceError = fmt.Sprintf("source file not found under %s", root)
+		return
+	}
+	fs.SourcePath = path
+
+	source, err := readLines(path)
+	if err != nil {
+		fs.SourceError = err.Error()
+		return
+	}
+
+	if contextLines < 0 {
+		contextLines = 0
+	}
+
+	timeByLine := make(map[int]LineStats, len(fs.Lines))
+	include := make(map[int]bool)
+	for _, ls := range fs.Lines {
+		timeByLine[ls.Line] = ls
+		for l := ls.Line - contextLines; l <= ls.Line+contextLines; l++ {
+			if l >= 1 && l <= len(source) {
+				include[l] = true
+			}
+		}
+	}
+
+	last := 0
+	for l := 1; l <= len(source); l++ {
+		if !include[l] {
+			continue
+		}
+		if last > 0 && l > last+1 {
+			fs.Listing = append(fs.Listing, AnnotatedLine{Gap: true})
+		}
+		ls := timeByLine[l]
+		fs.Listing = append(fs.Listing, AnnotatedLine{
+			Line:        l,
+			SelfTimeMs:  ls.SelfTimeMs,
+			TotalTimeMs: ls.TotalTimeMs,
+			Source:      source[l-1],
+		})
+		last = l
+	}
+}
+
+// ResolveSourceFile maps a profile file name or URL to a file under root. The URL
+// path is tried first, then with leading directories removed one at a time.
+func ResolveSourceFile(root, file string) (string, bool) {
+	p := file
+	if u, err := url.Parse(file); err == nil && len(u.Scheme) > 1 {
+		p = u.Path
+	} else if i := strings.IndexAny(p, "?#"); i >= 0 {
+		p = p[:i]
+	}
+
+	parts := strings.FieldsFunc(filepath.ToSlash(p), func(r rune) bool { return r == '/' })
+	absRoot, err := filepath.Abs(root)
+	if err != nil {
+		return "", false
+	}
+
+	for i := range parts {
+		candidate := filepath.Join(absRoot, filepath.Join(parts[i:]...))
+		// Never resolve outside the source root
+		rel, err := filepath.Rel(absRoot, candidate)
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			continue
+		}
+		if info, err := os.Stat(candidate); err == nil && !info.IsDir() {
+			return candidate, true
+		}
+	}
+
+	return "", false
+}
+
+// readLines reads a text file into lines
+func readLines(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read source file: %w", err)
+	}
+	defer f.Close()
+
+	var lines []string
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read source file: %w", err)
+	}
+	return lines, nil
+}
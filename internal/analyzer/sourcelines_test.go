@@ -0,0 +1,226 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/CedricHerzog/perfowl/internal/parser"
+	"github.com/CedricHerzog/perfowl/internal/testutil"
+)
+
+func TestAnalyzeSourceLines_RequiresFilter(t *testing.T) {
+	_, err := AnalyzeSourceLines(testutil.ProfileWithSourceLines(), SourceLineOptions{})
+	if err == nil {
+		t.Error("expected error without function or file filter")
+	}
+}
+
+func TestAnalyzeSourceLines_Function(t *testing.T) {
+	analysis, err := AnalyzeSourceLines(testutil.ProfileWithSourceLines(), SourceLineOptions{Function: "decrypt"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	testutil.AssertFloatApproxEqual(t, analysis.ProfileTimeMs, 50, 0.01)
+	testutil.AssertFloatApproxEqual(t, analysis.SelfTimeMs, 45, 0.01)
+
+	if len(analysis.MatchedFunctions) != 1 || analysis.MatchedFunctions[0] != "decrypt" {
+		t.Errorf("MatchedFunctions = %v, want [decrypt]", analysis.MatchedFunctions)
+	}
+	if len(analysis.Files) != 1 {
+		t.Fatalf("expected 1 file, got %d", len(analysis.Files))
+	}
+
+	lines := analysis.Files[0].Lines
+	if len(lines) != 3 {
+		t.Fatalf("expected 3 lines, got %d", len(lines))
+	}
+
+	// Lines are sorted by line number, 10 is the function start fallback
+	wantLines := []int{10, 12, 13}
+	wantSelf := []float64{5, 30, 10}
+	for i, ls := range lines {
+		if ls.Line != wantLines[i] {
+			t.Errorf("lines[%d].Line = %d, want %d", i, ls.Line, wantLines[i])
+		}
+		testutil.AssertFloatApproxEqual(t, ls.SelfTimeMs, wantSelf[i], 0.01)
+	}
+	if !lines[0].FunctionStart {
+		t.Error("expected line 10 to be marked as function start")
+	}
+	if lines[1].FunctionStart {
+		t.Error("expected line 12 to come from frame line info")
+	}
+
+	if analysis.HotLines[0].Line != 12 {
+		t.Errorf("hottest line = %d, want 12", analysis.HotLines[0].Line)
+	}
+	testutil.AssertFloatApproxEqual(t, analysis.HotLines[0].SelfPercent, 60, 0.01)
+}
+
+func TestAnalyzeSourceLines_FileTotalTime(t *testing.T) {
+	analysis, err := AnalyzeSourceLines(testutil.ProfileWithSourceLines(), SourceLineOptions{File: "APP.JS"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(analysis.MatchedFunctions) != 2 {
+		t.Errorf("MatchedFunctions = %v, want main and decrypt", analysis.MatchedFunctions)
+	}
+
+	var callSite *LineStats
+	for i := range analysis.Files[0].Lines {
+		if analysis.Files[0].Lines[i].Line == 5 {
+			callSite = &analysis.Files[0].Lines[i]
+		}
+	}
+	if callSite == nil {
+		t.Fatal("expected line 5 (call site in main)")
+	}
+
+	// Call site line includes the time of its callees
+	testutil.AssertFloatApproxEqual(t, callSite.SelfTimeMs, 5, 0.01)
+	testutil.AssertFloatApproxEqual(t, callSite.TotalTimeMs, 50, 0.01)
+}
+
+func TestAnalyzeSourceLines_Limit(t *testing.T) {
+	analysis, err := AnalyzeSourceLines(testutil.ProfileWithSourceLines(), SourceLineOptions{File: "app.js", Limit: 2})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(analysis.HotLines) != 2 {
+		t.Errorf("expected 2 hot lines, got %d", len(analysis.HotLines))
+	}
+}
+
+func TestAnalyzeSourceLines_NoMatch(t *testing.T) {
+	analysis, err := AnalyzeSourceLines(testutil.ProfileWithSourceLines(), SourceLineOptions{Function: "missing"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(analysis.Files) != 0 || len(analysis.HotLines) != 0 {
+		t.Errorf("expected no results, got %d files and %d hot lines", len(analysis.Files), len(analysis.HotLines))
+	}
+}
+
+func TestAnalyzeSourceLines_Listing(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "js"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	var source []string
+	for i := 1; i <= 30; i++ {
+		source = append(source, "line"+strings.Repeat("x", i%3))
+	}
+	if err := os.WriteFile(filepath.Join(root, "js", "app.js"), []byte(strings.Join(source, "\n")+"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	analysis, err := AnalyzeSourceLines(testutil.ProfileWithSourceLines(), SourceLineOptions{
+		Function:     "decrypt",
+		SourceRoot:   root,
+		ContextLines: 1,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	fs := analysis.Files[0]
+	if fs.SourceError != "" {
+		t.Fatalf("unexpected source error: %s", fs.SourceError)
+	}
+	if fs.SourcePath != filepath.Join(root, "js", "app.js") {
+		t.Errorf("SourcePath = %q", fs.SourcePath)
+	}
+
+	// Lines 9-14 are contiguous around 10, 12 and 13
+	var got []int
+	for _, al := range fs.Listing {
+		got = append(got, al.Line)
+		if al.Line == 12 {
+			testutil.AssertFloatApproxEqual(t, al.SelfTimeMs, 30, 0.01)
+			if al.Source != source[11] {
+				t.Errorf("line 12 source = %q, want %q", al.Source, source[11])
+			}
+		}
+	}
+	want := []int{9, 10, 11, 12, 13, 14}
+	if len(got) != len(want) {
+		t.Fatalf("listing lines = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("listing lines = %v, want %v", got, want)
+			break
+		}
+	}
+}
+
+func TestAnalyzeSourceLines_ListingMissingSource(t *testing.T) {
+	analysis, err := AnalyzeSourceLines(testutil.ProfileWithSourceLines(), SourceLineOptions{
+		Function:   "decrypt",
+		SourceRoot: t.TempDir(),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if analysis.Files[0].SourceError == "" {
+		t.Error("expected source error for missing file")
+	}
+	if len(analysis.Files[0].Listing) != 0 {
+		t.Error("expected no listing for missing file")
+	}
+}
+
+func TestResolveSourceFile(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "src"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	appPath := filepath.Join(root, "src", "app.js")
+	if err := os.WriteFile(appPath, []byte("x\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		file string
+		want string
+		ok   bool
+	}{
+		{"https://example.com/src/app.js?v=3", appPath, true},
+		{"https://example.com/static/build/src/app.js", appPath, true},
+		{"src/app.js", appPath, true},
+		{"/abs/path/app.js", "", false},
+		{"../../src/app.js", appPath, true},
+		{"https://example.com/other.js", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			got, ok := ResolveSourceFile(root, tt.file)
+			if ok != tt.ok || got != tt.want {
+				t.Errorf("ResolveSourceFile(%q) = %q, %v, want %q, %v", tt.file, got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+func TestFrameLineNumber(t *testing.T) {
+	ft := &parser.FrameTable{
+		Line: []interface{}{nil, 7, 8.0, json.Number("9"), "x"},
+	}
+
+	want := []int{0, 7, 8, 9, 0}
+	for i, w := range want {
+		if got := frameLineNumber(ft, i); got != w {
+			t.Errorf("frameLineNumber(%d) = %d, want %d", i, got, w)
+		}
+	}
+	if got := frameLineNumber(ft, 10); got != 0 {
+		t.Errorf("frameLineNumber(out of range) = %d, want 0", got)
+	}
+}
@@ -0,0 +1,93 @@
+package analyzer
+
+import (
+	"math"
+	"sort"
+)
+
+// Repeated-run statistics settings
+const (
+	StatsOutlierIQR        = 1.5 // Tukey fence: values beyond this many IQRs outside the quartiles are outliers
+	StatsOutlierMinSamples = 4   // Fewer runs are too few to call any of them an outlier
+)
+
+// tCritical95 holds two-sided 95% Student's t critical values by degrees of freedom
+var tCritical95 = []float64{
+	0, 12.706, 4.303, 3.182, 2.776, 2.571, 2.447, 2.365, 2.306, 2.262, 2.228,
+	2.201, 2.179, 2.160, 2.145, 2.131, 2.120, 2.110, 2.101, 2.093, 2.086,
+	2.080, 2.074, 2.069, 2.064, 2.060, 2.056, 2.052, 2.048, 2.045, 2.042,
+}
+
+// MetricStats summarizes repeated measurements of one metric after outlier rejection
+type MetricStats struct {
+	N        int     `json:"n"`        // Runs kept
+	Outliers int     `json:"outliers"` // Runs rejected by the Tukey fences
+	Mean     float64 `json:"mean"`
+	Median   float64 `json:"median"`
+	StdDev   float64 `json:"stddev"` // Sample standard deviation
+	Min      float64 `json:"min"`
+	Max      float64 `json:"max"`
+	CILow    float64 `json:"ci95_low"`
+	CIHigh   float64 `json:"ci95_high"`
+}
+
+// Margin returns the half-width of the 95% confidence interval
+func (s MetricStats) Margin() float64 {
+	return (s.CIHigh - s.CILow) / 2
+}
+
+// SummarizeRuns computes descriptive statistics and a 95% confidence interval of
+// the mean for repeated measurements. With StatsOutlierMinSamples or more values,
+// values outside the Tukey fences are dropped first.
+func SummarizeRuns(values []float64) MetricStats {
+	if len(values) == 0 {
+		return MetricStats{}
+	}
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+
+	kept := sorted
+	if len(sorted) >= StatsOutlierMinSamples {
+		q1, q3 := percentile(sorted, 25), percentile(sorted, 75)
+		lo, hi := q1-StatsOutlierIQR*(q3-q1), q3+StatsOutlierIQR*(q3-q1)
+		kept = make([]float64, 0, len(sorted))
+		for _, v := range sorted {
+			if v >= lo && v <= hi {
+				kept = append(kept, v)
+			}
+		}
+	}
+
+	stats := MetricStats{
+		N:        len(kept),
+		Outliers: len(sorted) - len(kept),
+		Median:   percentile(kept, 50),
+		Min:      kept[0],
+		Max:      kept[len(kept)-1],
+	}
+	for _, v := range kept {
+		stats.Mean += v
+	}
+	stats.Mean /= float64(len(kept))
+
+	stats.CILow, stats.CIHigh = stats.Mean, stats.Mean
+	if len(kept) > 1 {
+		var ss float64
+		for _, v := range kept {
+			ss += (v - stats.Mean) * (v - stats.Mean)
+		}
+		stats.StdDev = math.Sqrt(ss / float64(len(kept)-1))
+		margin := tCritical(len(kept)-1) * stats.StdDev / math.Sqrt(float64(len(kept)))
+		stats.CILow, stats.CIHigh = stats.Mean-margin, stats.Mean+margin
+	}
+	return stats
+}
+
+// tCritical returns the two-sided 95% t critical value, using the normal
+// approximation beyond the table
+func tCritical(df int) float64 {
+	if df < len(tCritical95) {
+		return tCritical95[df]
+	}
+	return 1.96
+}
@@ -0,0 +1,65 @@
+package analyzer
+
+import (
+	"testing"
+
+	"github.com/CedricHerzog/perfowl/internal/testutil"
+)
+
+func TestSummarizeRuns_Empty(t *testing.T) {
+	if stats := SummarizeRuns(nil); stats.N != 0 || stats.Mean != 0 {
+		t.Errorf("expected zero stats, got %+v", stats)
+	}
+}
+
+func TestSummarizeRuns_Single(t *testing.T) {
+	stats := SummarizeRuns([]float64{42})
+	if stats.N != 1 || stats.Mean != 42 || stats.Median != 42 || stats.StdDev != 0 {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+	if stats.CILow != 42 || stats.CIHigh != 42 {
+		t.Errorf("CI = [%v, %v], want a zero-width interval", stats.CILow, stats.CIHigh)
+	}
+}
+
+func TestSummarizeRuns_ConfidenceInterval(t *testing.T) {
+	stats := SummarizeRuns([]float64{104, 98, 100, 102, 96})
+
+	if stats.N != 5 || stats.Outliers != 0 {
+		t.Errorf("N/Outliers = %d/%d, want 5/0", stats.N, stats.Outliers)
+	}
+	testutil.AssertFloatApproxEqual(t, stats.Mean, 100, 1e-9)
+	testutil.AssertFloatApproxEqual(t, stats.Median, 100, 1e-9)
+	testutil.AssertFloatApproxEqual(t, stats.StdDev, 3.1623, 0.0001)
+	if stats.Min != 96 || stats.Max != 104 {
+		t.Errorf("Min/Max = %v/%v, want 96/104", stats.Min, stats.Max)
+	}
+	// t(0.975, 4) = 2.776; margin = 2.776 * sqrt(10) / sqrt(5)
+	testutil.AssertFloatApproxEqual(t, stats.Margin(), 3.9259, 0.0001)
+	testutil.AssertFloatApproxEqual(t, stats.CILow, 100-3.9259, 0.0001)
+}
+
+func TestSummarizeRuns_RejectsOutliers(t *testing.T) {
+	stats := SummarizeRuns([]float64{100, 101, 99, 100, 102, 98, 250})
+
+	if stats.N != 6 || stats.Outliers != 1 {
+		t.Errorf("N/Outliers = %d/%d, want 6/1", stats.N, stats.Outliers)
+	}
+	testutil.AssertFloatApproxEqual(t, stats.Mean, 100, 1e-9)
+	if stats.Max != 102 {
+		t.Errorf("Max = %v, want the outlier excluded", stats.Max)
+	}
+}
+
+func TestSummarizeRuns_KeepsSmallSamples(t *testing.T) {
+	stats := SummarizeRuns([]float64{100, 101, 250})
+	if stats.N != 3 || stats.Outliers != 0 {
+		t.Errorf("N/Outliers = %d/%d, want 3/0 below the outlier sample minimum", stats.N, stats.Outliers)
+	}
+}
+
+func TestTCritical(t *testing.T) {
+	testutil.AssertFloatApproxEqual(t, tCritical(1), 12.706, 1e-9)
+	testutil.AssertFloatApproxEqual(t, tCritical(30), 2.042, 1e-9)
+	testutil.AssertFloatApproxEqual(t, tCritical(100), 1.96, 1e-9)
+}
@@ -0,0 +1,431 @@
+package analyzer
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/CedricHerzog/perfowl/internal/parser"
+)
+
+// Style recalculation thresholds
+const (
+	StyleLargeRecalcElements = 1000 // Recalcs styling more elements than this touch a large part of the DOM
+	StyleLowReuseRatio       = 0.2  // Less than 20% of styled elements reusing a cached style
+	causeStackMaxFrames      = 10
+)
+
+// styleRecalcMarkers are the Firefox and Chrome style recalculation markers
+var styleRecalcMarkers = map[string]bool{
+	"Styles":            true, // Firefox
+	"UpdateLayoutTree":  true, // Chrome
+	"RecalculateStyles": true, // Chrome, older traces
+}
+
+// StyleRecalc is a single style recalculation
+type StyleRecalc struct {
+	StartTime         float64  `json:"start_time"`
+	DurationMs        float64  `json:"duration_ms"`
+	Thread            string   `json:"thread"`
+	ElementsStyled    int      `json:"elements_styled"`
+	ElementsTraversed int      `json:"elements_traversed,omitempty"`
+	ElementsMatched   int      `json:"elements_matched,omitempty"`
+	StylesShared      int      `json:"styles_shared,omitempty"`
+	StylesReused      int      `json:"styles_reused,omitempty"`
+	Forced            bool     `json:"forced"` // Flushed synchronously by JavaScript
+	Stack             []string `json:"stack,omitempty"`
+}
+
+// StyleCulprit is a JavaScript function that forced style recalculations
+type StyleCulprit struct {
+	Function       string   `json:"function"`
+	Count          int      `json:"count"`
+	TotalMs        float64  `json:"total_ms"`
+	ElementsStyled int      `json:"elements_styled"`
+	Stack          []string `json:"stack"` // Stack of the longest forced recalc, innermost frame first
+}
+
+// StyleSelectorStats is the matching cost of one CSS selector from Chrome selector stats
+type StyleSelectorStats struct {
+	Selector        string  `json:"selector"`
+	ElapsedMs       float64 `json:"elapsed_ms"`
+	MatchAttempts   int     `json:"match_attempts"`
+	MatchCount      int     `json:"match_count"`
+	FastRejectCount int     `json:"fast_reject_count"`
+	StyleSheetID    string  `json:"style_sheet_id,omitempty"`
+}
+
+// StyleAnalysis contains style system results
+type StyleAnalysis struct {
+	TotalRecalcs         int                  `json:"total_recalcs"`
+	ForcedRecalcs        int                  `json:"forced_recalcs"`
+	TotalMs              float64              `json:"total_ms"`
+	ForcedMs             float64              `json:"forced_ms"`
+	MaxMs                float64              `json:"max_ms"`
+	ElementsStyled       int64                `json:"elements_styled"`
+	AvgElementsPerRecalc float64              `json:"avg_elements_per_recalc"`
+	MaxElementsStyled    int                  `json:"max_elements_styled"`
+	CacheReuseRatio      float64              `json:"cache_reuse_ratio"` // Styled elements that shared or reused a style, -1 when not reported
+	SlowestRecalcs       []StyleRecalc        `json:"slowest_recalcs"`
+	Culprits             []StyleCulprit       `json:"culprits"`
+	Selectors            []StyleSelectorStats `json:"selectors"`
+	Recommendations      []string             `json:"recommendations,omitempty"`
+}
+
+// AnalyzeStyles analyzes style recalculation from Firefox Styles markers and Chrome
+// UpdateLayoutTree events. It reports elements styled per recalc, how many styled
+// elements reused a cached style, and recalcs forced synchronously by JavaScript
+// grouped by the function that forced them. Chrome selector stats, when enabled,
+// add the slowest CSS selectors.
+func AnalyzeStyles(profile *parser.Profile, limit int) StyleAnalysis {
+	analysis := StyleAnalysis{
+		CacheReuseRatio: -1,
+		SlowestRecalcs:  make([]StyleRecalc, 0),
+		Culprits:        make([]StyleCulprit, 0),
+		Selectors:       make([]StyleSelectorStats, 0),
+	}
+
+	if limit <= 0 {
+		limit = 10
+	}
+
+	var recalcs []StyleRecalc
+	culprits := make(map[string]*StyleCulprit)
+	longestForced := make(map[string]float64)
+	selectors := make(map[string]*StyleSelectorStats)
+	var reused, reuseBase int64
+
+	for i := range profile.Threads {
+		thread := &profile.Threads[i]
+		for _, m := range parser.ExtractMarkers(thread, profile.Meta.Categories) {
+			if m.Name == "SelectorStats" || styleRecalcMarkers[m.Name] {
+				addSelectorStats(selectors, m.Data)
+			}
+			if !styleRecalcMarkers[m.Name] || m.Duration <= 0 {
+				continue
+			}
+
+			r := parseStyleRecalc(profile, thread, m)
+			recalcs = append(recalcs, r)
+
+			analysis.TotalRecalcs++
+			analysis.TotalMs += r.DurationMs
+			analysis.MaxMs = math.Max(analysis.MaxMs, r.DurationMs)
+			analysis.ElementsStyled += int64(r.ElementsStyled)
+			if r.ElementsStyled > analysis.MaxElementsStyled {
+				analysis.MaxElementsStyled = r.ElementsStyled
+			}
+			if r.StylesShared > 0 || r.StylesReused > 0 || r.ElementsMatched > 0 {
+				reused += int64(r.StylesShared + r.StylesReused)
+				reuseBase += int64(r.ElementsStyled)
+			}
+
+			if !r.Forced {
+				continue
+			}
+			analysis.ForcedRecalcs++
+			analysis.ForcedMs += r.DurationMs
+			fn := "(unknown)"
+			if len(r.Stack) > 0 {
+				fn = r.Stack[0]
+			}
+			c := culprits[fn]
+			if c == nil {
+				c = &StyleCulprit{Function: fn}
+				culprits[fn] = c
+			}
+			if r.DurationMs > longestForced[fn] {
+				longestForced[fn] = r.DurationMs
+				c.Stack = r.Stack
+			}
+			c.Count++
+			c.TotalMs += r.DurationMs
+			c.ElementsStyled += r.ElementsStyled
+		}
+	}
+
+	if analysis.TotalRecalcs > 0 {
+		analysis.AvgElementsPerRecalc = float64(analysis.ElementsStyled) / float64(analysis.TotalRecalcs)
+	}
+	if reuseBase > 0 {
+		analysis.CacheReuseRatio = math.Min(1, float64(reused)/float64(reuseBase))
+	}
+
+	sort.SliceStable(recalcs, func(i, j int) bool { return recalcs[i].DurationMs > recalcs[j].DurationMs })
+	if len(recalcs) > limit {
+		recalcs = recalcs[:limit]
+	}
+	analysis.SlowestRecalcs = append(analysis.SlowestRecalcs, recalcs...)
+
+	for _, c := range culprits {
+		if c.Stack == nil {
+			c.Stack = make([]string, 0)
+		}
+		analysis.Culprits = append(analysis.Culprits, *c)
+	}
+	sort.Slice(analysis.Culprits, func(i, j int) bool {
+		if analysis.Culprits[i].TotalMs != analysis.Culprits[j].TotalMs {
+			return analysis.Culprits[i].TotalMs > analysis.Culprits[j].TotalMs
+		}
+		return analysis.Culprits[i].Function < analysis.Culprits[j].Function
+	})
+	if len(analysis.Culprits) > limit {
+		analysis.Culprits = analysis.Culprits[:limit]
+	}
+
+	for _, s := range selectors {
+		analysis.Selectors = append(analysis.Selectors, *s)
+	}
+	sort.Slice(analysis.Selectors, func(i, j int) bool {
+		if analysis.Selectors[i].ElapsedMs != analysis.Selectors[j].ElapsedMs {
+			return analysis.Selectors[i].ElapsedMs > analysis.Selectors[j].ElapsedMs
+		}
+		return analysis.Selectors[i].Selector < analysis.Selectors[j].Selector
+	})
+	if len(analysis.Selectors) > limit {
+		analysis.Selectors = analysis.Selectors[:limit]
+	}
+
+	analysis.Recommendations = styleRecommendations(analysis)
+	return analysis
+}
+
+// parseStyleRecalc reads element counts and the forcing stack of a style marker
+func parseStyleRecalc(profile *parser.Profile, thread *parser.Thread, m parser.ParsedMarker) StyleRecalc {
+	r := StyleRecalc{
+		StartTime:  m.StartTime,
+		DurationMs: m.Duration,
+		Thread:     thread.Name,
+	}
+	data := m.Data
+	if data == nil {
+		return r
+	}
+
+	// Firefox traversal statistics
+	r.ElementsTraversed = markerInt(data, "elementsTraversed")
+	r.ElementsStyled = markerInt(data, "elementsStyled")
+	r.ElementsMatched = markerInt(data, "elementsMatched")
+	r.StylesShared = markerInt(data, "stylesShared")
+	r.StylesReused = markerInt(data, "stylesReused")
+
+	// Chrome reports the element count at the end of the event
+	if r.ElementsStyled == 0 {
+		r.ElementsStyled = markerInt(data, "elementCount")
+		if r.ElementsStyled == 0 {
+			r.ElementsStyled = markerInt(milestoneData(data), "elementCount")
+		}
+	}
+
+	r.Stack = markerCauseStack(profile, thread, data)
+	r.Forced = len(r.Stack) > 0
+	return r
+}
+
+// markerInt reads an integer field of a marker payload
+func markerInt(data map[string]interface{}, key string) int {
+	if v, ok := data[key].(float64); ok {
+		return int(v)
+	}
+	return 0
+}
+
+// CauseFrame is one frame of the JavaScript stack that caused a marker
+type CauseFrame struct {
+	Function string `json:"function"`
+	File     string `json:"file,omitempty"`
+	Line     int    `json:"line,omitempty"`
+	Column   int    `json:"column,omitempty"`
+	IsJS     bool   `json:"is_js"`
+}
+
+// String formats the frame as "function (file:line:column)"
+func (f CauseFrame) String() string {
+	switch {
+	case f.File == "":
+		return f.Function
+	case f.Line <= 0:
+		return fmt.Sprintf("%s (%s)", f.Function, f.File)
+	case f.Column <= 0:
+		return fmt.Sprintf("%s (%s:%d)", f.Function, f.File, f.Line)
+	}
+	return fmt.Sprintf("%s (%s:%d:%d)", f.Function, f.File, f.Line, f.Column)
+}
+
+// markerCauseStack returns the JavaScript stack that caused a marker, innermost
+// frame first. Firefox stores it as a stack index under "cause" or "stack"; Chrome
+// stores a stackTrace array in the event's beginData.
+func markerCauseStack(profile *parser.Profile, thread *parser.Thread, data map[string]interface{}) []string {
+	frames := markerCauseFrames(profile, thread, data)
+	if frames == nil {
+		return nil
+	}
+	stack := make([]string, len(frames))
+	for i, f := range frames {
+		stack[i] = f.String()
+	}
+	return stack
+}
+
+// markerCauseFrames resolves the cause stack of a marker, innermost frame first
+func markerCauseFrames(profile *parser.Profile, thread *parser.Thread, data map[string]interface{}) []CauseFrame {
+	if begin, ok := data["beginData"].(map[string]interface{}); ok {
+		if trace, ok := begin["stackTrace"].([]interface{}); ok && len(trace) > 0 {
+			return chromeStackTrace(trace)
+		}
+	}
+	if nested, ok := data["data"].(map[string]interface{}); ok {
+		if trace, ok := nested["stackTrace"].([]interface{}); ok && len(trace) > 0 {
+			return chromeStackTrace(trace)
+		}
+	}
+
+	stackIdx := -1
+	if cause, ok := data["cause"].(map[string]interface{}); ok {
+		if s, ok := cause["stack"].(float64); ok {
+			stackIdx = int(s)
+		}
+	} else if s, ok := data["stack"].(float64); ok {
+		stackIdx = int(s)
+	}
+	if stackIdx < 0 {
+		return nil
+	}
+	return threadStackFrames(profile, thread, stackIdx)
+}
+
+// threadStackFrames resolves a stack table entry into frames, innermost first
+func threadStackFrames(profile *parser.Profile, thread *parser.Thread, stackIdx int) []CauseFrame {
+	stringArray := thread.StringArray
+	if len(stringArray) == 0 {
+		stringArray = profile.Shared.StringArray
+	}
+	str := func(idx int) string {
+		if idx >= 0 && idx < len(stringArray) {
+			return stringArray[idx]
+		}
+		return ""
+	}
+
+	funcs := &thread.FuncTable
+	var frames []CauseFrame
+	for stackIdx >= 0 && stackIdx < len(thread.StackTable.Frame) && len(frames) < causeStackMaxFrames {
+		frameIdx := thread.StackTable.Frame[stackIdx]
+		if frameIdx >= 0 && frameIdx < len(thread.FrameTable.Func) {
+			funcIdx := thread.FrameTable.Func[frameIdx]
+			if funcIdx >= 0 && funcIdx < len(funcs.Name) {
+				f := CauseFrame{Function: str(funcs.Name[funcIdx])}
+				if funcIdx < len(funcs.IsJS) {
+					f.IsJS = funcs.IsJS[funcIdx]
+				}
+				if funcIdx < len(funcs.FileName) {
+					f.File = str(funcs.FileName[funcIdx])
+				}
+				f.Line = frameLineNumber(&thread.FrameTable, frameIdx)
+				if f.Line <= 0 && funcIdx < len(funcs.LineNumber) {
+					f.Line = funcs.LineNumber[funcIdx]
+				}
+				if f.File == "" {
+					f.Line = 0
+				}
+				frames = append(frames, f)
+			}
+		}
+		if stackIdx >= len(thread.StackTable.Prefix) {
+			break
+		}
+		stackIdx = thread.StackTable.Prefix[stackIdx]
+	}
+	return frames
+}
+
+// chromeStackTrace resolves a Chrome stackTrace array, innermost frame first
+func chromeStackTrace(trace []interface{}) []CauseFrame {
+	frames := make([]CauseFrame, 0, len(trace))
+	for _, item := range trace {
+		if len(frames) == causeStackMaxFrames {
+			break
+		}
+		frame, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		f := CauseFrame{IsJS: true}
+		f.Function, _ = frame["functionName"].(string)
+		if f.Function == "" {
+			f.Function = "(anonymous)"
+		}
+		if url, _ := frame["url"].(string); url != "" {
+			line, _ := frame["lineNumber"].(float64)
+			col, _ := frame["columnNumber"].(float64)
+			f.File, f.Line, f.Column = url, int(line)+1, int(col)+1
+		}
+		frames = append(frames, f)
+	}
+	return frames
+}
+
+// addSelectorStats accumulates Chrome selector_stats timings
+func addSelectorStats(selectors map[string]*StyleSelectorStats, data map[string]interface{}) {
+	stats, ok := data["selector_stats"].(map[string]interface{})
+	if !ok {
+		stats, ok = milestoneData(data)["selector_stats"].(map[string]interface{})
+		if !ok {
+			return
+		}
+	}
+	timings, _ := stats["selector_timings"].([]interface{})
+	for _, item := range timings {
+		t, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		selector, _ := t["selector"].(string)
+		if selector == "" {
+			continue
+		}
+		sheet, _ := t["style_sheet_id"].(string)
+		key := sheet + "\x00" + selector
+		s := selectors[key]
+		if s == nil {
+			s = &StyleSelectorStats{Selector: selector, StyleSheetID: sheet}
+			selectors[key] = s
+		}
+		elapsed, _ := t["elapsed (us)"].(float64)
+		s.ElapsedMs += elapsed / 1000
+		s.MatchAttempts += markerInt(t, "match_attempts")
+		s.MatchCount += markerInt(t, "match_count")
+		s.FastRejectCount += markerInt(t, "fast_reject_count")
+	}
+}
+
+// styleRecommendations suggests follow-ups based on style results
+func styleRecommendations(analysis StyleAnalysis) []string {
+	var recs []string
+	if analysis.TotalRecalcs == 0 {
+		return append(recs, "No style recalculation markers were found")
+	}
+
+	if analysis.ForcedRecalcs > 0 {
+		rec := fmt.Sprintf("%d style recalculations (%.0fms) were forced synchronously by JavaScript", analysis.ForcedRecalcs, analysis.ForcedMs)
+		if len(analysis.Culprits) > 0 && analysis.Culprits[0].Function != "(unknown)" {
+			rec += fmt.Sprintf(", most by %s", analysis.Culprits[0].Function)
+		}
+		recs = append(recs, rec+"; batch style reads (getComputedStyle, offsetWidth) before writes")
+	}
+	if analysis.MaxElementsStyled > StyleLargeRecalcElements {
+		recs = append(recs, fmt.Sprintf("A single recalc styled %d elements; scope class changes to the smallest subtree and avoid toggling classes on <html> or <body>",
+			analysis.MaxElementsStyled))
+	}
+	if analysis.CacheReuseRatio >= 0 && analysis.CacheReuseRatio < StyleLowReuseRatio && analysis.ElementsStyled > StyleLargeRecalcElements {
+		recs = append(recs, fmt.Sprintf("Only %.0f%% of styled elements reused a cached style; sibling elements with differing classes or attributes defeat style sharing",
+			analysis.CacheReuseRatio*100))
+	}
+	if len(analysis.Selectors) > 0 && analysis.Selectors[0].ElapsedMs > 1 {
+		s := analysis.Selectors[0]
+		recs = append(recs, fmt.Sprintf("Selector %q took %.1fms over %d match attempts; simplify it or make its rightmost part more specific",
+			s.Selector, s.ElapsedMs, s.MatchAttempts))
+	}
+
+	return recs
+}
@@ -0,0 +1,123 @@
+package analyzer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/CedricHerzog/perfowl/internal/testutil"
+)
+
+func TestAnalyzeStyles_ElementCounts(t *testing.T) {
+	analysis := AnalyzeStyles(testutil.ProfileWithStyles(), 10)
+
+	if analysis.TotalRecalcs != 4 {
+		t.Fatalf("expected 4 recalcs, got %d", analysis.TotalRecalcs)
+	}
+	testutil.AssertFloatApproxEqual(t, analysis.TotalMs, 28, 0.01)
+	testutil.AssertFloatApproxEqual(t, analysis.MaxMs, 12, 0.01)
+	if analysis.ElementsStyled != 2000 || analysis.MaxElementsStyled != 1500 {
+		t.Errorf("elements styled = %d (max %d), want 2000 (max 1500)", analysis.ElementsStyled, analysis.MaxElementsStyled)
+	}
+	testutil.AssertFloatApproxEqual(t, analysis.AvgElementsPerRecalc, 500, 0.01)
+	testutil.AssertFloatApproxEqual(t, analysis.CacheReuseRatio, 0.12, 0.001)
+
+	slowest := analysis.SlowestRecalcs[0]
+	if slowest.ElementsStyled != 1500 || slowest.ElementsTraversed != 3000 || slowest.StylesShared != 50 {
+		t.Errorf("slowest recalc = %+v", slowest)
+	}
+}
+
+func TestAnalyzeStyles_ForcedCulprits(t *testing.T) {
+	analysis := AnalyzeStyles(testutil.ProfileWithStyles(), 10)
+
+	if analysis.ForcedRecalcs != 2 {
+		t.Fatalf("expected 2 forced recalcs, got %d", analysis.ForcedRecalcs)
+	}
+	testutil.AssertFloatApproxEqual(t, analysis.ForcedMs, 20, 0.01)
+
+	if len(analysis.Culprits) != 1 {
+		t.Fatalf("expected 1 culprit, got %+v", analysis.Culprits)
+	}
+	c := analysis.Culprits[0]
+	if c.Function != "measureWidth" || c.Count != 2 || c.ElementsStyled != 1900 {
+		t.Errorf("culprit = %+v, want measureWidth forcing 2 recalcs of 1900 elements", c)
+	}
+	if len(c.Stack) != 2 || c.Stack[1] != "onClick" {
+		t.Errorf("culprit stack = %v, want [measureWidth onClick]", c.Stack)
+	}
+}
+
+func TestAnalyzeStyles_Recommendations(t *testing.T) {
+	analysis := AnalyzeStyles(testutil.ProfileWithStyles(), 10)
+
+	joined := strings.Join(analysis.Recommendations, "\n")
+	for _, want := range []string{"forced synchronously by JavaScript, most by measureWidth", "styled 1500 elements", "Only 12% of styled elements"} {
+		if !strings.Contains(joined, want) {
+			t.Errorf("expected recommendation containing %q, got %v", want, analysis.Recommendations)
+		}
+	}
+}
+
+func TestAnalyzeStyles_ChromeStackAndSelectors(t *testing.T) {
+	markers, strs := testutil.NewMarkerBuilder().
+		AddCustom("UpdateLayoutTree", 3, 100, 4, map[string]interface{}{
+			"elementCount": 50.0,
+			"beginData": map[string]interface{}{
+				"stackTrace": []interface{}{
+					map[string]interface{}{"functionName": "resize", "url": "https://example.com/a.js", "lineNumber": 9.0, "columnNumber": 4.0},
+					map[string]interface{}{"functionName": "", "url": "https://example.com/a.js", "lineNumber": 1.0, "columnNumber": 0.0},
+				},
+			},
+			"selector_stats": map[string]interface{}{
+				"selector_timings": []interface{}{
+					map[string]interface{}{"selector": ".list .item", "elapsed (us)": 2500.0, "match_attempts": 100.0, "match_count": 10.0, "fast_reject_count": 5.0, "style_sheet_id": "s1"},
+					map[string]interface{}{"selector": "div", "elapsed (us)": 100.0, "match_attempts": 50.0, "match_count": 50.0},
+				},
+			},
+		}).
+		AddCustom("UpdateLayoutTree", 3, 200, 2, map[string]interface{}{"elementCount": 10.0}).
+		Build()
+
+	profile := testutil.NewProfileBuilder().
+		WithCategories(testutil.DefaultCategories()).
+		WithThread(testutil.NewThreadBuilder("CrRendererMain").
+			AsMainThread().
+			WithStringArray(strs).
+			WithMarkers(markers).
+			Build()).
+		Build()
+
+	analysis := AnalyzeStyles(profile, 10)
+
+	if analysis.TotalRecalcs != 2 || analysis.ForcedRecalcs != 1 || analysis.ElementsStyled != 60 {
+		t.Errorf("recalcs = %d (%d forced, %d elements), want 2 (1 forced, 60 elements)", analysis.TotalRecalcs, analysis.ForcedRecalcs, analysis.ElementsStyled)
+	}
+	if analysis.CacheReuseRatio != -1 {
+		t.Errorf("CacheReuseRatio = %f, want -1 without traversal statistics", analysis.CacheReuseRatio)
+	}
+	if len(analysis.Culprits) != 1 || analysis.Culprits[0].Function != "resize (https://example.com/a.js:10:5)" {
+		t.Errorf("culprits = %+v", analysis.Culprits)
+	}
+	if got := analysis.Culprits[0].Stack[1]; got != "(anonymous) (https://example.com/a.js:2:1)" {
+		t.Errorf("outer frame = %s", got)
+	}
+
+	if len(analysis.Selectors) != 2 || analysis.Selectors[0].Selector != ".list .item" {
+		t.Fatalf("selectors = %+v", analysis.Selectors)
+	}
+	testutil.AssertFloatApproxEqual(t, analysis.Selectors[0].ElapsedMs, 2.5, 0.001)
+	if analysis.Selectors[0].MatchAttempts != 100 || analysis.Selectors[0].StyleSheetID != "s1" {
+		t.Errorf("selector stats = %+v", analysis.Selectors[0])
+	}
+}
+
+func TestAnalyzeStyles_NoRecalcs(t *testing.T) {
+	analysis := AnalyzeStyles(testutil.MinimalProfile(), 10)
+
+	if analysis.TotalRecalcs != 0 || analysis.Culprits == nil || analysis.Selectors == nil {
+		t.Errorf("expected empty, non-nil results, got %+v", analysis)
+	}
+	if len(analysis.Recommendations) != 1 {
+		t.Errorf("expected a recommendation about missing markers, got %v", analysis.Recommendations)
+	}
+}
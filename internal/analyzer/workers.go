@@ -168,8 +168,12 @@ func AnalyzeWorkers(profile *parser.Profile) WorkerAnalysis {
 					isSync:     isSync,
 					duration:   m.Duration,
 				})
-			case "postMessage":
-				stats.MessagesSent++
+			default:
+				if isMessageSend(m) {
+					stats.MessagesSent++
+				} else if isMessageReceive(m) {
+					stats.MessagesReceived++
+				}
 			}
 
 			// Track IPC waits
@@ -172,6 +172,21 @@ func TestAnalyzeWorkers_EmptyProfile(t *testing.T) {
 	}
 }
 
+func TestAnalyzeWorkers_CountsMessages(t *testing.T) {
+	result := AnalyzeWorkers(testutil.ProfileWithMessageFlow())
+
+	if len(result.Workers) != 1 {
+		t.Fatalf("expected 1 worker, got %d", len(result.Workers))
+	}
+	w := result.Workers[0]
+	if w.MessagesSent != 1 {
+		t.Errorf("MessagesSent = %d, want 1", w.MessagesSent)
+	}
+	if w.MessagesReceived != 3 {
+		t.Errorf("MessagesReceived = %d, want 3", w.MessagesReceived)
+	}
+}
+
 func TestFormatWorkerAnalysis_Output(t *testing.T) {
 	analysis := WorkerAnalysis{
 		TotalWorkers:      2,
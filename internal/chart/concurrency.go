@@ -0,0 +1,214 @@
+package chart
+
+import (
+	"fmt"
+	"html"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/CedricHerzog/perfowl/internal/analyzer"
+)
+
+// concurrencyMaxLayers is the number of threads drawn as their own layer; the
+// remaining threads are stacked together as "Other threads"
+const concurrencyMaxLayers = 10
+
+// concurrencyColors are assigned to thread layers in order
+var concurrencyColors = []string{
+	"#42A5F5", "#66BB6A", "#FFA726", "#AB47BC", "#26A69A",
+	"#EF5350", "#8D6E63", "#5C6BC0", "#D4E157", "#EC407A",
+}
+
+// concurrencyLayer is one band of the stacked area
+type concurrencyLayer struct {
+	name   string
+	color  string
+	values []float64
+}
+
+// GenerateConcurrencyChart creates a stacked-area SVG of running threads over time
+// from a concurrency analysis. Each thread is a band whose height is its busy
+// fraction in the bucket, so the top of the stack is the number of effectively
+// running threads. Serial phases, where only the main thread ran, are shaded.
+func GenerateConcurrencyChart(analysis analyzer.ConcurrencyAnalysis) string {
+	var sb strings.Builder
+
+	const (
+		width  = 1000
+		height = 450
+	)
+	margin := struct{ top, right, bottom, left int }{60, 200, 60, 60}
+	chartWidth := width - margin.left - margin.right
+	chartHeight := height - margin.top - margin.bottom
+
+	layers := concurrencyLayers(analysis)
+
+	tMin, tMax := analysis.StartTime, analysis.EndTime
+	if tMax <= tMin {
+		tMax = tMin + 1
+	}
+	yMax := 1.0
+	for _, p := range analysis.Timeline {
+		yMax = math.Max(yMax, math.Ceil(p.Active))
+	}
+	scaleX := func(t float64) float64 {
+		return float64(margin.left) + (t-tMin)/(tMax-tMin)*float64(chartWidth)
+	}
+	scaleY := func(v float64) float64 {
+		return float64(margin.top+chartHeight) - v/yMax*float64(chartHeight)
+	}
+
+	// SVG header
+	sb.WriteString(fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
+<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 %d %d" width="%d" height="%d">
+<style>
+  .chart-bg { fill: #fafafa; }
+  .axis { stroke: #333; stroke-width: 1.5; fill: none; }
+  .grid { stroke: #e0e0e0; stroke-width: 0.5; stroke-dasharray: 4,4; }
+  .title { font: bold 18px system-ui, -apple-system, sans-serif; fill: #222; }
+  .axis-label { font: 11px system-ui, -apple-system, sans-serif; fill: #555; }
+  .axis-title { font: 13px system-ui, -apple-system, sans-serif; fill: #333; }
+  .legend-text { font: 12px system-ui, -apple-system, sans-serif; fill: #333; }
+  .serial { fill: #E53935; opacity: 0.08; }
+  .layer { stroke: #fff; stroke-width: 0.3; opacity: 0.9; }
+</style>
+`, width, height, width, height))
+
+	sb.WriteString(fmt.Sprintf(`<rect class="chart-bg" x="0" y="0" width="%d" height="%d"/>
+`, width, height))
+	sb.WriteString(fmt.Sprintf(`<text class="title" x="%d" y="30" text-anchor="middle">Thread Concurrency (avg parallelism %.2f, peak %d)</text>
+`, margin.left+chartWidth/2, analysis.AverageParallelism, analysis.PeakConcurrency))
+
+	bottom := margin.top + chartHeight
+
+	// Serial phases behind the layers
+	for _, phase := range analysis.SerialPhases {
+		x := scaleX(phase.StartTime)
+		sb.WriteString(fmt.Sprintf(`<rect class="serial" x="%.1f" y="%d" width="%.1f" height="%d"><title>Serial %.1fms</title></rect>
+`, x, margin.top, math.Max(1, scaleX(phase.EndTime)-x), chartHeight, phase.DurationMs))
+	}
+
+	// Grid and axes
+	for _, tick := range calculateTicks(0, yMax, int(math.Min(yMax, 8))) {
+		if tick < 0 || tick > yMax || tick != math.Trunc(tick) {
+			continue
+		}
+		y := scaleY(tick)
+		sb.WriteString(fmt.Sprintf(`<line class="grid" x1="%d" y1="%.1f" x2="%d" y2="%.1f"/>
+`, margin.left, y, margin.left+chartWidth, y))
+		sb.WriteString(fmt.Sprintf(`<text class="axis-label" x="%d" y="%.1f" text-anchor="end" dominant-baseline="middle">%s</text>
+`, margin.left-8, y, formatNumber(tick)))
+	}
+	for _, tick := range calculateTicks(tMin, tMax, 6) {
+		if tick < tMin || tick > tMax {
+			continue
+		}
+		sb.WriteString(fmt.Sprintf(`<text class="axis-label" x="%.1f" y="%d" text-anchor="middle">%sms</text>
+`, scaleX(tick), bottom+16, formatNumber(tick)))
+	}
+
+	// Stacked layers, bottom to top
+	base := make([]float64, len(analysis.Timeline))
+	for _, layer := range layers {
+		if len(analysis.Timeline) == 0 {
+			break
+		}
+		points := make([]string, 0, 2*len(analysis.Timeline))
+		for i, p := range analysis.Timeline {
+			x0, x1 := scaleX(p.Time), scaleX(math.Min(p.Time+analysis.BucketMs, tMax))
+			y := scaleY(base[i] + layer.values[i])
+			points = append(points, fmt.Sprintf("%.1f,%.1f %.1f,%.1f", x0, y, x1, y))
+		}
+		// Walk back along the top of the layer below
+		for i := len(analysis.Timeline) - 1; i >= 0; i-- {
+			p := analysis.Timeline[i]
+			x0, x1 := scaleX(p.Time), scaleX(math.Min(p.Time+analysis.BucketMs, tMax))
+			y := scaleY(base[i])
+			points = append(points, fmt.Sprintf("%.1f,%.1f %.1f,%.1f", x1, y, x0, y))
+			base[i] += layer.values[i]
+		}
+		sb.WriteString(fmt.Sprintf(`<polygon class="layer" points="%s" fill="%s"><title>%s</title></polygon>
+`, strings.Join(points, " "), layer.color, html.EscapeString(layer.name)))
+	}
+
+	sb.WriteString(fmt.Sprintf(`<line class="axis" x1="%d" y1="%d" x2="%d" y2="%d"/>
+`, margin.left, bottom, margin.left+chartWidth, bottom))
+	sb.WriteString(fmt.Sprintf(`<line class="axis" x1="%d" y1="%d" x2="%d" y2="%d"/>
+`, margin.left, margin.top, margin.left, bottom))
+	sb.WriteString(fmt.Sprintf(`<text class="axis-title" x="%d" y="%d" text-anchor="middle">Time</text>
+`, margin.left+chartWidth/2, height-15))
+	sb.WriteString(fmt.Sprintf(`<text class="axis-title" x="20" y="%d" text-anchor="middle" transform="rotate(-90, 20, %d)">Running threads</text>
+`, margin.top+chartHeight/2, margin.top+chartHeight/2))
+
+	// Legend, top layer first so it reads like the stack
+	legendX := margin.left + chartWidth + 20
+	legendY := margin.top + 10
+	for i := len(layers) - 1; i >= 0; i-- {
+		sb.WriteString(fmt.Sprintf(`<rect x="%d" y="%d" width="12" height="12" fill="%s"/>
+`, legendX, legendY-6, layers[i].color))
+		sb.WriteString(fmt.Sprintf(`<text class="legend-text" x="%d" y="%d" dominant-baseline="middle">%s</text>
+`, legendX+18, legendY, html.EscapeString(layers[i].name)))
+		legendY += 20
+	}
+	if len(analysis.SerialPhases) > 0 {
+		sb.WriteString(fmt.Sprintf(`<rect class="serial" x="%d" y="%d" width="12" height="12" style="opacity:0.3"/>
+`, legendX, legendY-6))
+		sb.WriteString(fmt.Sprintf(`<text class="legend-text" x="%d" y="%d" dominant-baseline="middle">Serial (main only)</text>
+`, legendX+18, legendY))
+	}
+
+	sb.WriteString(`</svg>`)
+	return sb.String()
+}
+
+// concurrencyLayers turns per-thread usage into chart layers, main thread first
+// and the least busy threads folded into one layer beyond concurrencyMaxLayers
+func concurrencyLayers(analysis analyzer.ConcurrencyAnalysis) []concurrencyLayer {
+	order := make([]int, 0, len(analysis.Threads))
+	for i, t := range analysis.Threads {
+		if t.IsMain {
+			order = append(order, i)
+		}
+	}
+	rest := make([]int, 0, len(analysis.Threads))
+	for i, t := range analysis.Threads {
+		if !t.IsMain {
+			rest = append(rest, i)
+		}
+	}
+	// Busiest threads get their own layer
+	sort.SliceStable(rest, func(i, j int) bool {
+		return analysis.Threads[rest[i]].BusyMs > analysis.Threads[rest[j]].BusyMs
+	})
+	order = append(order, rest...)
+
+	var layers []concurrencyLayer
+	for n, idx := range order {
+		if n == concurrencyMaxLayers {
+			other := concurrencyLayer{name: fmt.Sprintf("Other threads (%d)", len(order)-n), color: "#BDBDBD", values: make([]float64, len(analysis.Timeline))}
+			for _, idx := range order[n:] {
+				for i, p := range analysis.Timeline {
+					if idx < len(p.Threads) {
+						other.values[i] += p.Threads[idx]
+					}
+				}
+			}
+			return append(layers, other)
+		}
+
+		t := analysis.Threads[idx]
+		name := t.Name
+		if t.TID != "" {
+			name = fmt.Sprintf("%s (%s)", t.Name, t.TID)
+		}
+		layer := concurrencyLayer{name: name, color: concurrencyColors[n%len(concurrencyColors)], values: make([]float64, len(analysis.Timeline))}
+		for i, p := range analysis.Timeline {
+			if idx < len(p.Threads) {
+				layer.values[i] = p.Threads[idx]
+			}
+		}
+		layers = append(layers, layer)
+	}
+	return layers
+}
@@ -0,0 +1,63 @@
+package chart
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/CedricHerzog/perfowl/internal/analyzer"
+	"github.com/CedricHerzog/perfowl/internal/parser"
+	"github.com/CedricHerzog/perfowl/internal/testutil"
+)
+
+func TestGenerateConcurrencyChart(t *testing.T) {
+	analysis := analyzer.AnalyzeConcurrency(testutil.ProfileWithConcurrency(), 0)
+
+	svg := GenerateConcurrencyChart(analysis)
+
+	if !strings.HasPrefix(svg, "<?xml") || !strings.HasSuffix(svg, "</svg>") {
+		t.Fatal("expected a complete SVG document")
+	}
+	if !strings.Contains(svg, "Thread Concurrency (avg parallelism 1.20, peak 2)") {
+		t.Error("expected title with parallelism and peak")
+	}
+	if got := strings.Count(svg, `<polygon class="layer"`); got != 3 {
+		t.Errorf("expected one layer per thread, got %d", got)
+	}
+	if got := strings.Count(svg, `<rect class="serial" x=`); got != 3 {
+		t.Errorf("expected 2 serial phases plus the legend swatch, got %d", got)
+	}
+	if !strings.Contains(svg, "GeckoMain (1)") || !strings.Contains(svg, "DOM Worker (3)") {
+		t.Error("expected thread names in the legend")
+	}
+}
+
+func TestGenerateConcurrencyChart_OtherThreads(t *testing.T) {
+	pb := testutil.NewProfileBuilder().WithInterval(1)
+	for i := 0; i < 12; i++ {
+		sb := testutil.NewSamplesBuilder()
+		for t := 0; t <= 10; t++ {
+			sb.AddSampleWithCPUDelta(0, float64(t), 1000)
+		}
+		pb.WithThread(testutil.NewThreadBuilder("DOM Worker").WithSamples(sb.Build()).Build())
+	}
+
+	svg := GenerateConcurrencyChart(analyzer.AnalyzeConcurrency(pb.Build(), 0))
+
+	if got := strings.Count(svg, `<polygon class="layer"`); got != 11 {
+		t.Errorf("expected 10 thread layers plus one for the rest, got %d", got)
+	}
+	if !strings.Contains(svg, "Other threads (2)") {
+		t.Error("expected remaining threads folded into one layer")
+	}
+}
+
+func TestGenerateConcurrencyChart_Empty(t *testing.T) {
+	svg := GenerateConcurrencyChart(analyzer.AnalyzeConcurrency(&parser.Profile{}, 0))
+
+	if !strings.Contains(svg, "<svg") || strings.Contains(svg, "<polygon") {
+		t.Error("expected an empty concurrency chart")
+	}
+	if strings.Contains(svg, "NaN") {
+		t.Error("empty chart should not contain NaN coordinates")
+	}
+}
@@ -0,0 +1,95 @@
+package chart
+
+import (
+	"fmt"
+	"hash/fnv"
+	"html"
+	"strings"
+
+	"github.com/CedricHerzog/perfowl/internal/analyzer"
+)
+
+// flameColors are the warm hues frames are picked from by name, so a function
+// keeps its colour wherever it appears
+var flameColors = []string{
+	"#E53935", "#F4511E", "#FB8C00", "#FFB300", "#FDD835",
+	"#EF6C00", "#D84315", "#FF7043", "#FFA726", "#FFCA28",
+}
+
+// flameCharWidth approximates the width of one label character in pixels
+const flameCharWidth = 7.0
+
+// flameColor picks a stable colour for a function name
+func flameColor(name string) string {
+	h := fnv.New32a()
+	h.Write([]byte(name))
+	return flameColors[h.Sum32()%uint32(len(flameColors))]
+}
+
+// GenerateFlameGraph creates an SVG flame graph from a flame graph tree. Each
+// frame is a box as wide as its share of the total time, stacked on its caller,
+// with the root at the bottom; hovering a box shows its total and self time.
+func GenerateFlameGraph(root analyzer.FlameNode) string {
+	var sb strings.Builder
+
+	const (
+		width     = 1000
+		rowHeight = 18
+	)
+	margin := struct{ top, right, bottom, left int }{50, 10, 20, 10}
+	depth := root.Depth()
+	height := margin.top + margin.bottom + depth*rowHeight
+	chartWidth := float64(width - margin.left - margin.right)
+
+	total := root.TotalMs
+	if total <= 0 {
+		total = 1
+	}
+
+	sb.WriteString(fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
+<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 %d %d" width="%d" height="%d">
+<style>
+  .chart-bg { fill: #fafafa; }
+  .title { font: bold 18px system-ui, -apple-system, sans-serif; fill: #222; }
+  .frame { stroke: #fff; stroke-width: 0.5; }
+  .frame-label { font: 11px ui-monospace, monospace; fill: #222; pointer-events: none; }
+</style>
+`, width, height, width, height))
+
+	sb.WriteString(fmt.Sprintf(`<rect class="chart-bg" x="0" y="0" width="%d" height="%d"/>
+`, width, height))
+	sb.WriteString(fmt.Sprintf(`<text class="title" x="%d" y="30" text-anchor="middle">Flame Graph (%.1fms)</text>
+`, width/2, root.TotalMs))
+
+	bottom := margin.top + depth*rowHeight
+	var draw func(node *analyzer.FlameNode, x float64, level int)
+	draw = func(node *analyzer.FlameNode, x float64, level int) {
+		w := node.TotalMs / total * chartWidth
+		if w < 0.5 {
+			return
+		}
+		y := bottom - (level+1)*rowHeight
+		sb.WriteString(fmt.Sprintf(`<g><title>%s: %.2fms total (%.1f%%), %.2fms self</title><rect class="frame" x="%.1f" y="%d" width="%.1f" height="%d" fill="%s"/>`,
+			html.EscapeString(node.Name), node.TotalMs, node.TotalMs/total*100, node.SelfMs, x, y, w, rowHeight-1, flameColor(node.Name)))
+		if chars := int((w - 6) / flameCharWidth); chars >= 3 {
+			label := node.Name
+			if runes := []rune(label); len(runes) > chars {
+				label = string(runes[:chars-2]) + ".."
+			}
+			sb.WriteString(fmt.Sprintf(`<text class="frame-label" x="%.1f" y="%d">%s</text>`, x+3, y+rowHeight-5, html.EscapeString(label)))
+		}
+		sb.WriteString("</g>\n")
+
+		childX := x
+		for _, c := range node.Children {
+			draw(c, childX, level+1)
+			childX += c.TotalMs / total * chartWidth
+		}
+	}
+	if root.TotalMs > 0 {
+		draw(&root, float64(margin.left), 0)
+	}
+
+	sb.WriteString(`</svg>`)
+	return sb.String()
+}
@@ -0,0 +1,59 @@
+package chart
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/CedricHerzog/perfowl/internal/analyzer"
+	"github.com/CedricHerzog/perfowl/internal/testutil"
+)
+
+func TestGenerateFlameGraph(t *testing.T) {
+	svg := GenerateFlameGraph(analyzer.BuildFlameGraph(testutil.ProfileWithCallTree(), "", 0))
+
+	if !strings.HasPrefix(svg, "<?xml") || !strings.HasSuffix(svg, "</svg>") {
+		t.Fatal("expected a complete SVG document")
+	}
+	if !strings.Contains(svg, "Flame Graph (100.0ms)") {
+		t.Error("expected title with the total time")
+	}
+	// all, GeckoMain, main, processData, computeHash, render, updateDOM
+	if got := strings.Count(svg, `<rect class="frame"`); got != 7 {
+		t.Errorf("expected one box per frame, got %d", got)
+	}
+	if !strings.Contains(svg, "<title>computeHash: 50.00ms total (50.0%), 50.00ms self</title>") {
+		t.Error("expected a tooltip with total and self time")
+	}
+	if flameColor("computeHash") != flameColor("computeHash") {
+		t.Error("expected a stable colour per function")
+	}
+}
+
+func TestGenerateFlameGraph_Empty(t *testing.T) {
+	svg := GenerateFlameGraph(analyzer.FlameNode{Name: "all"})
+
+	if !strings.Contains(svg, "<svg") || strings.Contains(svg, `<rect class="frame"`) {
+		t.Error("expected an empty flame graph")
+	}
+	if strings.Contains(svg, "NaN") {
+		t.Error("empty chart should not contain NaN coordinates")
+	}
+}
+
+func TestGenerateFlameGraph_TruncatesRunes(t *testing.T) {
+	name := strings.Repeat("描画", 40)
+	root := analyzer.FlameNode{Name: "all", TotalMs: 10, Children: []*analyzer.FlameNode{
+		{Name: name, TotalMs: 1, SelfMs: 1},
+		{Name: "other", TotalMs: 9, SelfMs: 9},
+	}}
+
+	svg := GenerateFlameGraph(root)
+
+	if !utf8.ValidString(svg) {
+		t.Error("expected truncated labels to stay valid UTF-8")
+	}
+	if !strings.Contains(svg, "描画..") && !strings.Contains(svg, "描..") {
+		t.Error("expected the long label to be truncated")
+	}
+}
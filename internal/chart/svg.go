@@ -2,6 +2,7 @@ package chart
 
 import (
 	"fmt"
+	"html"
 	"math"
 	"strings"
 
@@ -21,8 +22,10 @@ const (
 
 // DataPoint is a single x,y coordinate
 type DataPoint struct {
-	X float64
-	Y float64
+	X    float64
+	Y    float64
+	Low  float64 // Error bar extent, drawn when High > Low
+	High float64
 }
 
 // DataSeries represents a single line in the chart
@@ -30,6 +33,7 @@ type DataSeries struct {
 	Name   string
 	Color  string
 	Points []DataPoint
+	Dashed bool // Model curve: drawn dashed without point markers
 }
 
 // ChartConfig defines chart appearance
@@ -66,36 +70,45 @@ func GenerateScalingChart(result *analyzer.BatchAnalysisResult, chartType ChartT
 		config.XAxisLabel = "Worker Count"
 		config.YAxisLabel = "Time (ms)"
 		series = buildSeries(result, func(p analyzer.ProfileDataPoint) float64 { return p.WallClockMs })
+		addErrorBars(result, series, analyzer.BatchMetricWallClock)
+		series = append(series, buildFitSeries(result, series, analyzer.BatchMetricWallClock)...)
 
 	case ChartOperationTime:
 		config.Title = "Operation Time vs Worker Count"
 		config.XAxisLabel = "Worker Count"
 		config.YAxisLabel = "Time (ms)"
 		series = buildSeries(result, func(p analyzer.ProfileDataPoint) float64 { return p.OperationTimeMs })
+		addErrorBars(result, series, analyzer.BatchMetricOperationTime)
+		series = append(series, buildFitSeries(result, series, analyzer.BatchMetricOperationTime)...)
 
 	case ChartEfficiency:
 		config.Title = "Parallel Efficiency vs Worker Count"
 		config.XAxisLabel = "Worker Count"
 		config.YAxisLabel = "Efficiency (%)"
 		series = buildSeries(result, func(p analyzer.ProfileDataPoint) float64 { return p.Efficiency })
+		addErrorBars(result, series, analyzer.BatchMetricEfficiency)
 
 	case ChartSpeedup:
 		config.Title = "Speedup vs Worker Count"
 		config.XAxisLabel = "Worker Count"
 		config.YAxisLabel = "Speedup (x)"
 		series = buildSeries(result, func(p analyzer.ProfileDataPoint) float64 { return p.Speedup })
+		addErrorBars(result, series, analyzer.BatchMetricSpeedup)
 
 	case ChartCryptoTime:
 		config.Title = "Crypto Time vs Worker Count"
 		config.XAxisLabel = "Worker Count"
 		config.YAxisLabel = "Time (ms)"
 		series = buildSeries(result, func(p analyzer.ProfileDataPoint) float64 { return p.CryptoTimeMs })
+		addErrorBars(result, series, analyzer.BatchMetricCryptoTime)
 
 	default:
 		config.Title = "Wall Clock Time vs Worker Count"
 		config.XAxisLabel = "Worker Count"
 		config.YAxisLabel = "Time (ms)"
 		series = buildSeries(result, func(p analyzer.ProfileDataPoint) float64 { return p.WallClockMs })
+		addErrorBars(result, series, analyzer.BatchMetricWallClock)
+		series = append(series, buildFitSeries(result, series, analyzer.BatchMetricWallClock)...)
 	}
 
 	return GenerateSVG(config, series)
@@ -137,6 +150,54 @@ func buildSeries(result *analyzer.BatchAnalysisResult, getMetric func(analyzer.P
 	return series
 }
 
+// addErrorBars sets the 95% confidence interval of repeated runs as the error bar of
+// each point. Series points are in the same order as the batch series.
+func addErrorBars(result *analyzer.BatchAnalysisResult, series []DataSeries, metric string) {
+	for i := range series {
+		points := result.Series[series[i].Name]
+		for j := range series[i].Points {
+			if j >= len(points) {
+				break
+			}
+			if stats, ok := points[j].Stats[metric]; ok && stats.CIHigh > stats.CILow {
+				series[i].Points[j].Low = stats.CILow
+				series[i].Points[j].High = stats.CIHigh
+			}
+		}
+	}
+}
+
+// buildFitSeries creates dashed model curves for the labels whose scaling fit was made
+// on the charted metric, in the color of the measured series
+func buildFitSeries(result *analyzer.BatchAnalysisResult, measured []DataSeries, metric string) []DataSeries {
+	var series []DataSeries
+
+	for _, m := range measured {
+		fit, ok := result.Summary.ScalingFits[m.Name]
+		if !ok || fit.Metric != metric || len(fit.Predicted) == 0 {
+			continue
+		}
+
+		model := "Amdahl"
+		if fit.Model == analyzer.ScalingModelUSL {
+			model = "USL"
+		}
+		ds := DataSeries{
+			Name:   fmt.Sprintf("%s (%s fit)", m.Name, model),
+			Color:  m.Color,
+			Points: make([]DataPoint, 0, len(fit.Predicted)),
+			Dashed: true,
+		}
+		for _, p := range fit.Predicted {
+			ds.Points = append(ds.Points, DataPoint{X: float64(p.Workers), Y: p.TimeMs})
+		}
+
+		series = append(series, ds)
+	}
+
+	return series
+}
+
 // GenerateSVG creates an SVG chart from data series
 func GenerateSVG(config ChartConfig, series []DataSeries) string {
 	var sb strings.Builder
@@ -150,6 +211,14 @@ func GenerateSVG(config ChartConfig, series []DataSeries) string {
 	}
 
 	margin := struct{ top, right, bottom, left int }{60, 100, 70, 80}
+	if config.ShowLegend {
+		// Widen the legend column for long series names
+		for _, s := range series {
+			if w := 45 + 7*len(s.Name); w > margin.right {
+				margin.right = w
+			}
+		}
+	}
 	chartWidth := config.Width - margin.left - margin.right
 	chartHeight := config.Height - margin.top - margin.bottom
 
@@ -191,6 +260,8 @@ func GenerateSVG(config ChartConfig, series []DataSeries) string {
   .legend-text { font: 12px system-ui, -apple-system, sans-serif; fill: #333; }
   .data-line { fill: none; stroke-width: 2.5; stroke-linecap: round; stroke-linejoin: round; }
   .data-point { stroke: white; stroke-width: 2; }
+  .error-bar { stroke-width: 1.5; opacity: 0.7; }
+  .fit-line { fill: none; stroke-width: 1.5; stroke-dasharray: 6,4; opacity: 0.8; }
 </style>
 `, config.Width, config.Height, config.Width, config.Height))
 
@@ -201,7 +272,7 @@ func GenerateSVG(config ChartConfig, series []DataSeries) string {
 	// Title
 	if config.Title != "" {
 		sb.WriteString(fmt.Sprintf(`<text class="title" x="%d" y="35" text-anchor="middle">%s</text>
-`, config.Width/2, config.Title))
+`, config.Width/2, html.EscapeString(config.Title)))
 	}
 
 	// Grid lines (horizontal)
@@ -233,7 +304,7 @@ func GenerateSVG(config ChartConfig, series []DataSeries) string {
 
 	// X axis title
 	sb.WriteString(fmt.Sprintf(`<text class="axis-title" x="%d" y="%d" text-anchor="middle">%s</text>
-`, config.Width/2, config.Height-15, config.XAxisLabel))
+`, config.Width/2, config.Height-15, html.EscapeString(config.XAxisLabel)))
 
 	// Y axis labels
 	yTicks := calculateTicks(yMin, yMax, 5)
@@ -246,7 +317,7 @@ func GenerateSVG(config ChartConfig, series []DataSeries) string {
 
 	// Y axis title (rotated)
 	sb.WriteString(fmt.Sprintf(`<text class="axis-title" x="20" y="%d" text-anchor="middle" transform="rotate(-90, 20, %d)">%s</text>
-`, (config.Height-margin.top-margin.bottom)/2+margin.top, (config.Height-margin.top-margin.bottom)/2+margin.top, config.YAxisLabel))
+`, (config.Height-margin.top-margin.bottom)/2+margin.top, (config.Height-margin.top-margin.bottom)/2+margin.top, html.EscapeString(config.YAxisLabel)))
 
 	// Data lines and points
 	for _, s := range series {
@@ -265,9 +336,24 @@ func GenerateSVG(config ChartConfig, series []DataSeries) string {
 				pathData.WriteString(fmt.Sprintf(" L%.1f,%.1f", x, y))
 			}
 		}
+		if s.Dashed {
+			sb.WriteString(fmt.Sprintf(`<path class="fit-line" stroke="%s" d="%s"/>
+`, s.Color, pathData.String()))
+			continue
+		}
 		sb.WriteString(fmt.Sprintf(`<path class="data-line" stroke="%s" d="%s"/>
 `, s.Color, pathData.String()))
 
+		// Draw error bars
+		for _, p := range s.Points {
+			if p.High <= p.Low {
+				continue
+			}
+			x, yLow, yHigh := scaleX(p.X), scaleY(p.Low), scaleY(p.High)
+			sb.WriteString(fmt.Sprintf(`<path class="error-bar" stroke="%s" d="M%.1f,%.1f L%.1f,%.1f M%.1f,%.1f L%.1f,%.1f M%.1f,%.1f L%.1f,%.1f"/>
+`, s.Color, x, yLow, x, yHigh, x-4, yLow, x+4, yLow, x-4, yHigh, x+4, yHigh))
+		}
+
 		// Draw points
 		for _, p := range s.Points {
 			x := scaleX(p.X)
@@ -284,12 +370,17 @@ func GenerateSVG(config ChartConfig, series []DataSeries) string {
 
 		for i, s := range series {
 			y := legendY + i*25
-			sb.WriteString(fmt.Sprintf(`<line x1="%d" y1="%d" x2="%d" y2="%d" stroke="%s" stroke-width="3"/>
+			if s.Dashed {
+				sb.WriteString(fmt.Sprintf(`<line class="fit-line" x1="%d" y1="%d" x2="%d" y2="%d" stroke="%s"/>
 `, legendX, y, legendX+20, y, s.Color))
-			sb.WriteString(fmt.Sprintf(`<circle cx="%d" cy="%d" r="4" fill="%s" stroke="white" stroke-width="1"/>
+			} else {
+				sb.WriteString(fmt.Sprintf(`<line x1="%d" y1="%d" x2="%d" y2="%d" stroke="%s" stroke-width="3"/>
+`, legendX, y, legendX+20, y, s.Color))
+				sb.WriteString(fmt.Sprintf(`<circle cx="%d" cy="%d" r="4" fill="%s" stroke="white" stroke-width="1"/>
 `, legendX+10, y, s.Color))
+			}
 			sb.WriteString(fmt.Sprintf(`<text class="legend-text" x="%d" y="%d" dominant-baseline="middle">%s</text>
-`, legendX+28, y, s.Name))
+`, legendX+28, y, html.EscapeString(s.Name)))
 		}
 	}
 
@@ -318,6 +409,10 @@ func calculateRanges(series []DataSeries) (xMin, xMax, yMin, yMax float64) {
 			if p.Y > yMax {
 				yMax = p.Y
 			}
+			if p.High > p.Low {
+				yMin = math.Min(yMin, p.Low)
+				yMax = math.Max(yMax, p.High)
+			}
 		}
 	}
 
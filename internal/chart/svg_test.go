@@ -69,6 +69,44 @@ func TestGenerateScalingChart_CryptoTime(t *testing.T) {
 	}
 }
 
+func TestGenerateScalingChart_FitOverlay(t *testing.T) {
+	result := createTestBatchResult()
+	fit, ok := analyzer.FitScaling(result.Series["Firefox"])
+	if !ok {
+		t.Fatal("expected a scaling fit for the test series")
+	}
+	result.Summary.ScalingFits = map[string]analyzer.ScalingFit{"Firefox": fit}
+
+	svg := GenerateScalingChart(result, ChartOperationTime)
+	if !strings.Contains(svg, `<path class="fit-line"`) {
+		t.Error("expected a dashed fit curve on the operation time chart")
+	}
+	if !strings.Contains(svg, "Firefox (Amdahl fit)") {
+		t.Error("expected the fit in the legend")
+	}
+
+	// Every point measured operation time, so the fit is not drawn on wall clock time
+	if svg := GenerateScalingChart(result, ChartWallClock); strings.Contains(svg, `<path class="fit-line"`) {
+		t.Error("expected no fit curve on the wall clock chart")
+	}
+}
+
+func TestGenerateScalingChart_ErrorBars(t *testing.T) {
+	result := createTestBatchResult()
+	result.Series["Firefox"][1].Runs = 5
+	result.Series["Firefox"][1].Stats = map[string]analyzer.MetricStats{
+		analyzer.BatchMetricWallClock: {N: 5, Mean: 600, CILow: 560, CIHigh: 640},
+	}
+
+	svg := GenerateScalingChart(result, ChartWallClock)
+	if strings.Count(svg, `<path class="error-bar"`) != 1 {
+		t.Error("expected one error bar on the wall clock chart")
+	}
+	if svg := GenerateScalingChart(result, ChartSpeedup); strings.Contains(svg, `<path class="error-bar"`) {
+		t.Error("expected no error bars without speedup statistics")
+	}
+}
+
 func TestGenerateScalingChart_DefaultType(t *testing.T) {
 	result := createTestBatchResult()
 
@@ -140,6 +178,23 @@ func TestGenerateSVG_SingleSeries(t *testing.T) {
 	}
 }
 
+func TestGenerateSVG_EscapesLabels(t *testing.T) {
+	config := ChartConfig{Width: 800, Height: 450, Title: "A & B", ShowLegend: true}
+	series := []DataSeries{
+		{Name: "A & B", Color: "#FF0000", Points: []DataPoint{{X: 1, Y: 100}, {X: 2, Y: 50}}},
+		{Name: "<script>alert(1)</script> (USL fit)", Color: "#FF0000", Points: []DataPoint{{X: 1, Y: 90}}, Dashed: true},
+	}
+
+	svg := GenerateSVG(config, series)
+
+	if strings.Contains(svg, "<script>") || strings.Contains(svg, "A & B") {
+		t.Error("expected batch labels to be escaped")
+	}
+	if !strings.Contains(svg, "A &amp; B") || !strings.Contains(svg, "&lt;script&gt;") {
+		t.Error("expected escaped labels in the title and legend")
+	}
+}
+
 func TestGenerateSVG_MultipleSeries(t *testing.T) {
 	config := ChartConfig{
 		Width:      800,
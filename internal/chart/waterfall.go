@@ -0,0 +1,172 @@
+package chart
+
+import (
+	"fmt"
+	"html"
+	"math"
+	"strings"
+
+	"github.com/CedricHerzog/perfowl/internal/analyzer"
+)
+
+// waterfallPhase is one coloured segment of a request bar
+type waterfallPhase struct {
+	name  string
+	color string
+	ms    func(analyzer.NetworkRequest) float64
+}
+
+// waterfallPhases are drawn left to right in the order a request goes through them
+var waterfallPhases = []waterfallPhase{
+	{"DNS", "#26A69A", func(r analyzer.NetworkRequest) float64 { return r.DNSMs }},
+	{"Connect", "#FFA726", func(r analyzer.NetworkRequest) float64 { return r.ConnectMs }},
+	{"TLS", "#AB47BC", func(r analyzer.NetworkRequest) float64 { return r.TLSMs }},
+	{"Waiting", "#66BB6A", func(r analyzer.NetworkRequest) float64 { return r.WaitingMs }},
+	{"Download", "#42A5F5", func(r analyzer.NetworkRequest) float64 { return r.DownloadMs }},
+}
+
+// GenerateWaterfallChart creates an SVG request waterfall from a network analysis.
+// Each request is a row whose bar is split into its DNS, connect, TLS, waiting and
+// download phases; time not covered by a phase is drawn as queueing. Render-blocking
+// requests are outlined and first contentful paint is drawn as a vertical line.
+func GenerateWaterfallChart(analysis analyzer.NetworkAnalysis) string {
+	var sb strings.Builder
+
+	const (
+		width     = 1000
+		rowHeight = 20
+		labelW    = 280
+	)
+	margin := struct{ top, right, bottom, left int }{60, 30, 60, labelW + 10}
+	rows := len(analysis.Requests)
+	height := margin.top + margin.bottom + int(math.Max(1, float64(rows)))*rowHeight
+	chartWidth := width - margin.left - margin.right
+
+	tMin, tMax := analysis.StartTime, analysis.EndTime
+	for _, r := range analysis.Requests {
+		tMin = math.Min(tMin, r.StartTime)
+		tMax = math.Max(tMax, r.EndTime)
+	}
+	if analysis.FirstContentfulPaint > 0 {
+		tMax = math.Max(tMax, analysis.FirstContentfulPaint)
+	}
+	if tMax <= tMin {
+		tMax = tMin + 1
+	}
+	scaleX := func(t float64) float64 {
+		return float64(margin.left) + (t-tMin)/(tMax-tMin)*float64(chartWidth)
+	}
+	scaleW := func(ms float64) float64 {
+		return ms / (tMax - tMin) * float64(chartWidth)
+	}
+
+	// SVG header
+	sb.WriteString(fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
+<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 %d %d" width="%d" height="%d">
+<style>
+  .chart-bg { fill: #fafafa; }
+  .axis { stroke: #333; stroke-width: 1.5; fill: none; }
+  .grid { stroke: #e0e0e0; stroke-width: 0.5; stroke-dasharray: 4,4; }
+  .title { font: bold 18px system-ui, -apple-system, sans-serif; fill: #222; }
+  .axis-label { font: 11px system-ui, -apple-system, sans-serif; fill: #555; }
+  .row-label { font: 11px ui-monospace, monospace; fill: #333; }
+  .legend-text { font: 12px system-ui, -apple-system, sans-serif; fill: #333; }
+  .queued { fill: #d0d0d0; }
+  .blocking { fill: none; stroke: #E53935; stroke-width: 1.5; }
+  .failed { fill: #E53935; opacity: 0.6; }
+  .fcp { stroke: #E53935; stroke-width: 1.5; stroke-dasharray: 6,3; }
+</style>
+`, width, height, width, height))
+
+	sb.WriteString(fmt.Sprintf(`<rect class="chart-bg" x="0" y="0" width="%d" height="%d"/>
+`, width, height))
+	sb.WriteString(fmt.Sprintf(`<text class="title" x="%d" y="30" text-anchor="middle">Network Waterfall (%d requests)</text>
+`, width/2, analysis.TotalRequests))
+
+	// Time grid
+	bottom := height - margin.bottom
+	for _, tick := range calculateTicks(tMin, tMax, 6) {
+		if tick < tMin || tick > tMax {
+			continue
+		}
+		x := scaleX(tick)
+		sb.WriteString(fmt.Sprintf(`<line class="grid" x1="%.1f" y1="%d" x2="%.1f" y2="%d"/>
+`, x, margin.top, x, bottom))
+		sb.WriteString(fmt.Sprintf(`<text class="axis-label" x="%.1f" y="%d" text-anchor="middle">%sms</text>
+`, x, bottom+16, formatNumber(tick)))
+	}
+	sb.WriteString(fmt.Sprintf(`<line class="axis" x1="%d" y1="%d" x2="%d" y2="%d"/>
+`, margin.left, bottom, width-margin.right, bottom))
+
+	// Request rows
+	for i, r := range analysis.Requests {
+		y := float64(margin.top + i*rowHeight)
+		barY := y + 4
+		barH := float64(rowHeight - 8)
+
+		label := r.URL
+		if len(label) > 42 {
+			label = "…" + label[len(label)-41:]
+		}
+		sb.WriteString(fmt.Sprintf(`<text class="row-label" x="%d" y="%.1f" dominant-baseline="middle"><title>%s</title>%s</text>
+`, 10, y+float64(rowHeight)/2, html.EscapeString(r.URL), html.EscapeString(label)))
+
+		x := scaleX(r.StartTime)
+		if r.Failed {
+			sb.WriteString(fmt.Sprintf(`<rect class="failed" x="%.1f" y="%.1f" width="%.1f" height="%.1f"/>
+`, x, barY, math.Max(1, scaleW(r.DurationMs)), barH))
+		} else {
+			// Anything the phases do not account for was spent queued before the request went out
+			phased := 0.0
+			for _, p := range waterfallPhases {
+				phased += p.ms(r)
+			}
+			if queued := r.DurationMs - phased; queued > 0 {
+				sb.WriteString(fmt.Sprintf(`<rect class="queued" x="%.1f" y="%.1f" width="%.1f" height="%.1f"/>
+`, x, barY, scaleW(queued), barH))
+				x += scaleW(queued)
+			}
+			for _, p := range waterfallPhases {
+				w := scaleW(p.ms(r))
+				if w <= 0 {
+					continue
+				}
+				sb.WriteString(fmt.Sprintf(`<rect x="%.1f" y="%.1f" width="%.1f" height="%.1f" fill="%s"/>
+`, x, barY, w, barH, p.color))
+				x += w
+			}
+		}
+		if r.RenderBlocking {
+			sb.WriteString(fmt.Sprintf(`<rect class="blocking" x="%.1f" y="%.1f" width="%.1f" height="%.1f"/>
+`, scaleX(r.StartTime), barY-1, math.Max(1, scaleW(r.DurationMs)), barH+2))
+		}
+	}
+
+	// First contentful paint
+	if analysis.FirstContentfulPaint > 0 {
+		x := scaleX(analysis.FirstContentfulPaint)
+		sb.WriteString(fmt.Sprintf(`<line class="fcp" x1="%.1f" y1="%d" x2="%.1f" y2="%d"/>
+`, x, margin.top-8, x, bottom))
+		sb.WriteString(fmt.Sprintf(`<text class="axis-label" x="%.1f" y="%d" text-anchor="middle">FCP</text>
+`, x, margin.top-12))
+	}
+
+	// Legend
+	legendX := margin.left
+	legendY := height - 18
+	legend := append([]waterfallPhase{{name: "Queued", color: "#d0d0d0"}}, waterfallPhases...)
+	for _, p := range legend {
+		sb.WriteString(fmt.Sprintf(`<rect x="%d" y="%d" width="12" height="12" fill="%s"/>
+`, legendX, legendY-6, p.color))
+		sb.WriteString(fmt.Sprintf(`<text class="legend-text" x="%d" y="%d" dominant-baseline="middle">%s</text>
+`, legendX+16, legendY, p.name))
+		legendX += 90
+	}
+	sb.WriteString(fmt.Sprintf(`<rect class="blocking" x="%d" y="%d" width="12" height="12"/>
+`, legendX, legendY-6))
+	sb.WriteString(fmt.Sprintf(`<text class="legend-text" x="%d" y="%d" dominant-baseline="middle">Render-blocking</text>
+`, legendX+16, legendY))
+
+	sb.WriteString(`</svg>`)
+	return sb.String()
+}
@@ -0,0 +1,45 @@
+package chart
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/CedricHerzog/perfowl/internal/analyzer"
+	"github.com/CedricHerzog/perfowl/internal/testutil"
+)
+
+func TestGenerateWaterfallChart(t *testing.T) {
+	analysis := analyzer.AnalyzeNetwork(testutil.ProfileWithNetwork(), 50)
+
+	svg := GenerateWaterfallChart(analysis)
+
+	if !strings.HasPrefix(svg, "<?xml") || !strings.HasSuffix(svg, "</svg>") {
+		t.Fatal("expected a complete SVG document")
+	}
+	if !strings.Contains(svg, "Network Waterfall (6 requests)") {
+		t.Error("expected title with request count")
+	}
+	if got := strings.Count(svg, `class="blocking" x=`); got != 3 {
+		t.Errorf("expected 2 render-blocking outlines plus the legend swatch, got %d", got)
+	}
+	if !strings.Contains(svg, `class="fcp"`) {
+		t.Error("expected first contentful paint line")
+	}
+	if !strings.Contains(svg, `class="failed"`) {
+		t.Error("expected failed request bar")
+	}
+	if !strings.Contains(svg, "https://example.com/app.css") {
+		t.Error("expected request URLs as row labels")
+	}
+}
+
+func TestGenerateWaterfallChart_Empty(t *testing.T) {
+	svg := GenerateWaterfallChart(analyzer.AnalyzeNetwork(testutil.MinimalProfile(), 10))
+
+	if !strings.Contains(svg, "<svg") || !strings.Contains(svg, "Network Waterfall (0 requests)") {
+		t.Error("expected an empty waterfall chart")
+	}
+	if strings.Contains(svg, "NaN") {
+		t.Error("empty waterfall should not contain NaN coordinates")
+	}
+}
@@ -0,0 +1,73 @@
+// Package junit writes JUnit XML test reports, the format CI systems use to show
+// test results.
+package junit
+
+import (
+	"encoding/xml"
+	"io"
+)
+
+// TestSuites is the document root
+type TestSuites struct {
+	XMLName  xml.Name    `xml:"testsuites"`
+	Name     string      `xml:"name,attr,omitempty"`
+	Tests    int         `xml:"tests,attr"`
+	Failures int         `xml:"failures,attr"`
+	Suites   []TestSuite `xml:"testsuite"`
+}
+
+// TestSuite groups related test cases
+type TestSuite struct {
+	Name     string     `xml:"name,attr"`
+	Tests    int        `xml:"tests,attr"`
+	Failures int        `xml:"failures,attr"`
+	Cases    []TestCase `xml:"testcase"`
+}
+
+// TestCase is one check; it failed when Failure is set
+type TestCase struct {
+	Name      string   `xml:"name,attr"`
+	ClassName string   `xml:"classname,attr"`
+	File      string   `xml:"file,attr,omitempty"`
+	Line      int      `xml:"line,attr,omitempty"`
+	Failure   *Failure `xml:"failure,omitempty"`
+	SystemOut string   `xml:"system-out,omitempty"`
+}
+
+// Failure describes why a test case failed
+type Failure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr,omitempty"`
+	Text    string `xml:",chardata"`
+}
+
+// NewSuite builds a suite from its cases, counting tests and failures
+func NewSuite(name string, cases []TestCase) TestSuite {
+	suite := TestSuite{Name: name, Tests: len(cases), Cases: cases}
+	for _, c := range cases {
+		if c.Failure != nil {
+			suite.Failures++
+		}
+	}
+	return suite
+}
+
+// Write encodes the suites as an indented JUnit XML document, filling in the totals
+func Write(w io.Writer, name string, suites ...TestSuite) error {
+	doc := TestSuites{Name: name, Suites: suites}
+	for _, s := range suites {
+		doc.Tests += s.Tests
+		doc.Failures += s.Failures
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
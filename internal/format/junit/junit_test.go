@@ -0,0 +1,55 @@
+package junit
+
+import (
+	"encoding/xml"
+	"strings"
+	"testing"
+)
+
+func TestWrite(t *testing.T) {
+	suite := NewSuite("budget", []TestCase{
+		{Name: "score", ClassName: "perfowl.budget.score", File: "budget.yaml", Line: 3},
+		{Name: "long_tasks", ClassName: "perfowl.budget.long_tasks", Failure: &Failure{Message: "5 exceeds 3", Type: "budget/long_tasks", Text: "value 5"}},
+	})
+	if suite.Tests != 2 || suite.Failures != 1 {
+		t.Errorf("Tests/Failures = %d/%d, want 2/1", suite.Tests, suite.Failures)
+	}
+
+	var sb strings.Builder
+	if err := Write(&sb, "perfowl", suite); err != nil {
+		t.Fatalf("Write error: %v", err)
+	}
+	out := sb.String()
+
+	if !strings.HasPrefix(out, xml.Header) {
+		t.Error("expected an XML declaration")
+	}
+	for _, want := range []string{
+		`<testsuites name="perfowl" tests="2" failures="1">`,
+		`<testcase name="score" classname="perfowl.budget.score" file="budget.yaml" line="3"></testcase>`,
+		`<failure message="5 exceeds 3" type="budget/long_tasks">value 5</failure>`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+
+	var doc TestSuites
+	if err := xml.Unmarshal([]byte(out), &doc); err != nil {
+		t.Fatalf("output is not valid XML: %v", err)
+	}
+	if len(doc.Suites) != 1 || len(doc.Suites[0].Cases) != 2 {
+		t.Errorf("unexpected round trip %+v", doc)
+	}
+}
+
+func TestWrite_EscapesText(t *testing.T) {
+	var sb strings.Builder
+	suite := NewSuite("s", []TestCase{{Name: `a < b & "c"`, Failure: &Failure{Message: "<tag>"}}})
+	if err := Write(&sb, "perfowl", suite); err != nil {
+		t.Fatalf("Write error: %v", err)
+	}
+	if strings.Contains(sb.String(), "<tag>") {
+		t.Errorf("expected markup in messages to be escaped:\n%s", sb.String())
+	}
+}
@@ -0,0 +1,107 @@
+// Package sarif writes SARIF 2.1.0 logs, the format code scanning tools use to
+// show findings inline in pull requests.
+// See: https://docs.oasis-open.org/sarif/sarif/v2.1.0/sarif-v2.1.0.html
+package sarif
+
+import (
+	"encoding/json"
+	"io"
+)
+
+const (
+	schemaURI = "https://json.schemastore.org/sarif-2.1.0.json"
+	version   = "2.1.0"
+)
+
+// Result levels
+const (
+	LevelError   = "error"
+	LevelWarning = "warning"
+	LevelNote    = "note"
+)
+
+// Log is the document root
+type Log struct {
+	Schema  string `json:"$schema"`
+	Version string `json:"version"`
+	Runs    []Run  `json:"runs"`
+}
+
+// Run holds the results of one tool invocation
+type Run struct {
+	Tool    Tool     `json:"tool"`
+	Results []Result `json:"results"`
+}
+
+// Tool describes the analyzer that produced the results
+type Tool struct {
+	Driver Driver `json:"driver"`
+}
+
+// Driver names the tool and the rules its results refer to
+type Driver struct {
+	Name           string `json:"name"`
+	Version        string `json:"version,omitempty"`
+	InformationURI string `json:"informationUri,omitempty"`
+	Rules          []Rule `json:"rules,omitempty"`
+}
+
+// Rule describes one kind of finding
+type Rule struct {
+	ID               string   `json:"id"`
+	ShortDescription *Message `json:"shortDescription,omitempty"`
+	Help             *Message `json:"help,omitempty"`
+}
+
+// Result is one finding
+type Result struct {
+	RuleID    string     `json:"ruleId"`
+	Level     string     `json:"level"`
+	Message   Message    `json:"message"`
+	Locations []Location `json:"locations,omitempty"`
+}
+
+// Message is a plain text message
+type Message struct {
+	Text string `json:"text"`
+}
+
+// Location points a result at a file and, optionally, a line
+type Location struct {
+	PhysicalLocation PhysicalLocation `json:"physicalLocation"`
+}
+
+// PhysicalLocation is a location within an artifact
+type PhysicalLocation struct {
+	ArtifactLocation ArtifactLocation `json:"artifactLocation"`
+	Region           *Region          `json:"region,omitempty"`
+}
+
+// ArtifactLocation identifies a file by URI
+type ArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+// Region is a 1-based line range
+type Region struct {
+	StartLine int `json:"startLine"`
+}
+
+// NewLocation builds a location for a file, with a region when line is positive
+func NewLocation(uri string, line int) Location {
+	loc := Location{PhysicalLocation: PhysicalLocation{ArtifactLocation: ArtifactLocation{URI: uri}}}
+	if line > 0 {
+		loc.PhysicalLocation.Region = &Region{StartLine: line}
+	}
+	return loc
+}
+
+// Write encodes a single run as an indented SARIF log
+func Write(w io.Writer, run Run) error {
+	if run.Results == nil {
+		run.Results = make([]Result, 0)
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(Log{Schema: schemaURI, Version: version, Runs: []Run{run}})
+}
@@ -0,0 +1,54 @@
+package sarif
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestWrite(t *testing.T) {
+	run := Run{
+		Tool: Tool{Driver: Driver{Name: "perfowl", Version: "1.0.0", Rules: []Rule{{ID: "budget/score"}}}},
+		Results: []Result{{
+			RuleID:    "budget/score",
+			Level:     LevelError,
+			Message:   Message{Text: "score 60 is below the minimum 80"},
+			Locations: []Location{NewLocation("budget.yaml", 4)},
+		}},
+	}
+
+	var sb strings.Builder
+	if err := Write(&sb, run); err != nil {
+		t.Fatalf("Write error: %v", err)
+	}
+
+	var log map[string]any
+	if err := json.Unmarshal([]byte(sb.String()), &log); err != nil {
+		t.Fatalf("output is not valid JSON: %v", err)
+	}
+	if log["version"] != "2.1.0" || log["$schema"] == nil {
+		t.Errorf("unexpected header %v / %v", log["version"], log["$schema"])
+	}
+	for _, want := range []string{`"ruleId": "budget/score"`, `"level": "error"`, `"uri": "budget.yaml"`, `"startLine": 4`} {
+		if !strings.Contains(sb.String(), want) {
+			t.Errorf("output missing %s:\n%s", want, sb.String())
+		}
+	}
+}
+
+func TestWrite_EmptyResults(t *testing.T) {
+	var sb strings.Builder
+	if err := Write(&sb, Run{Tool: Tool{Driver: Driver{Name: "perfowl"}}}); err != nil {
+		t.Fatalf("Write error: %v", err)
+	}
+	// Code scanning treats a missing results array differently from an empty one
+	if !strings.Contains(sb.String(), `"results": []`) {
+		t.Errorf("expected an empty results array:\n%s", sb.String())
+	}
+}
+
+func TestNewLocation_NoLine(t *testing.T) {
+	if loc := NewLocation("a.js", 0); loc.PhysicalLocation.Region != nil {
+		t.Errorf("expected no region without a line, got %+v", loc.PhysicalLocation.Region)
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/CedricHerzog/perfowl/internal/chart"
 	"github.com/CedricHerzog/perfowl/internal/format/toon"
 	"github.com/CedricHerzog/perfowl/internal/parser"
+	"github.com/CedricHerzog/perfowl/internal/report"
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
 )
@@ -41,7 +42,7 @@ func (pos *PerfOwlServer) registerTools() {
 		mcp.WithDescription("Get a summary of the browser profile including duration, platform, threads, and extensions"),
 		mcp.WithString("path", mcp.Required(), mcp.Description("Path to the profile JSON file (gzip supported)")),
 	)
-	pos.server.AddTool(summaryTool, pos.handleGetSummary)
+	pos.server.AddTool(withScopeParams(summaryTool), pos.handleGetSummary)
 
 	// get_bottlenecks tool
 	bottlenecksTool := mcp.NewTool("get_bottlenecks",
@@ -49,7 +50,7 @@ func (pos *PerfOwlServer) registerTools() {
 		mcp.WithString("path", mcp.Required(), mcp.Description("Path to the profile JSON file")),
 		mcp.WithString("min_severity", mcp.Description("Minimum severity to report: low, medium, high")),
 	)
-	pos.server.AddTool(bottlenecksTool, pos.handleGetBottlenecks)
+	pos.server.AddTool(withScopeParams(bottlenecksTool), pos.handleGetBottlenecks)
 
 	// get_markers tool
 	markersTool := mcp.NewTool("get_markers",
@@ -61,22 +62,22 @@ func (pos *PerfOwlServer) registerTools() {
 		mcp.WithNumber("min_duration", mcp.Description("Minimum duration in milliseconds")),
 		mcp.WithNumber("limit", mcp.Description("Maximum number of markers to return")),
 	)
-	pos.server.AddTool(markersTool, pos.handleGetMarkers)
+	pos.server.AddTool(withScopeParams(markersTool), pos.handleGetMarkers)
 
 	// analyze_extension tool
 	extensionTool := mcp.NewTool("analyze_extension",
-		mcp.WithDescription("Analyze extension performance impact including duration, events, DOM interactions, and IPC messages"),
+		mcp.WithDescription("Analyze extension performance impact including sampled CPU time per thread, top extension functions, marker duration, events, DOM interactions, and IPC messages"),
 		mcp.WithString("path", mcp.Required(), mcp.Description("Path to the profile JSON file")),
 		mcp.WithString("extension_id", mcp.Description("Filter by specific extension ID (optional)")),
 	)
-	pos.server.AddTool(extensionTool, pos.handleAnalyzeExtension)
+	pos.server.AddTool(withScopeParams(extensionTool), pos.handleAnalyzeExtension)
 
 	// analyze_profile tool (comprehensive analysis)
 	analyzeTool := mcp.NewTool("analyze_profile",
 		mcp.WithDescription("Perform a comprehensive analysis of the profile including summary, bottlenecks, and extension impact"),
 		mcp.WithString("path", mcp.Required(), mcp.Description("Path to the profile JSON file")),
 	)
-	pos.server.AddTool(analyzeTool, pos.handleAnalyzeProfile)
+	pos.server.AddTool(withScopeParams(analyzeTool), pos.handleAnalyzeProfile)
 
 	// get_call_tree tool
 	callTreeTool := mcp.NewTool("get_call_tree",
@@ -85,7 +86,7 @@ func (pos *PerfOwlServer) registerTools() {
 		mcp.WithString("thread", mcp.Description("Filter by thread name (optional)")),
 		mcp.WithNumber("limit", mcp.Description("Maximum number of functions/paths to return (default 20)")),
 	)
-	pos.server.AddTool(callTreeTool, pos.handleGetCallTree)
+	pos.server.AddTool(withScopeParams(callTreeTool), pos.handleGetCallTree)
 
 	// get_category_breakdown tool
 	categoryTool := mcp.NewTool("get_category_breakdown",
@@ -93,14 +94,14 @@ func (pos *PerfOwlServer) registerTools() {
 		mcp.WithString("path", mcp.Required(), mcp.Description("Path to the profile JSON file")),
 		mcp.WithString("thread", mcp.Description("Filter by thread name (optional)")),
 	)
-	pos.server.AddTool(categoryTool, pos.handleGetCategoryBreakdown)
+	pos.server.AddTool(withScopeParams(categoryTool), pos.handleGetCategoryBreakdown)
 
 	// get_thread_analysis tool
 	threadTool := mcp.NewTool("get_thread_analysis",
 		mcp.WithDescription("Analyze all threads including CPU time, sample counts, wake patterns, and category distribution"),
 		mcp.WithString("path", mcp.Required(), mcp.Description("Path to the profile JSON file")),
 	)
-	pos.server.AddTool(threadTool, pos.handleGetThreadAnalysis)
+	pos.server.AddTool(withScopeParams(threadTool), pos.handleGetThreadAnalysis)
 
 	// compare_profiles tool
 	compareTool := mcp.NewTool("compare_profiles",
@@ -108,42 +109,42 @@ func (pos *PerfOwlServer) registerTools() {
 		mcp.WithString("baseline", mcp.Required(), mcp.Description("Path to the baseline profile JSON file")),
 		mcp.WithString("comparison", mcp.Required(), mcp.Description("Path to the comparison profile JSON file")),
 	)
-	pos.server.AddTool(compareTool, pos.handleCompareProfiles)
+	pos.server.AddTool(withScopeParams(compareTool), pos.handleCompareProfiles)
 
 	// analyze_workers tool
 	workersTool := mcp.NewTool("analyze_workers",
 		mcp.WithDescription("Analyze worker thread performance including CPU time, idle time, messaging, and synchronization points"),
 		mcp.WithString("path", mcp.Required(), mcp.Description("Path to the profile JSON file")),
 	)
-	pos.server.AddTool(workersTool, pos.handleAnalyzeWorkers)
+	pos.server.AddTool(withScopeParams(workersTool), pos.handleAnalyzeWorkers)
 
 	// analyze_crypto tool
 	cryptoTool := mcp.NewTool("analyze_crypto",
 		mcp.WithDescription("Analyze cryptographic operations including SubtleCrypto API usage, algorithm detection, and serialization issues"),
 		mcp.WithString("path", mcp.Required(), mcp.Description("Path to the profile JSON file")),
 	)
-	pos.server.AddTool(cryptoTool, pos.handleAnalyzeCrypto)
+	pos.server.AddTool(withScopeParams(cryptoTool), pos.handleAnalyzeCrypto)
 
 	// analyze_jscrypto tool
 	jsCryptoTool := mcp.NewTool("analyze_jscrypto",
 		mcp.WithDescription("Analyze JavaScript-level crypto operations including crypto worker files (seipdDecryptionWorker, openpgp.js), per-worker time distribution, and top functions"),
 		mcp.WithString("path", mcp.Required(), mcp.Description("Path to the profile JSON file")),
 	)
-	pos.server.AddTool(jsCryptoTool, pos.handleAnalyzeJSCrypto)
+	pos.server.AddTool(withScopeParams(jsCryptoTool), pos.handleAnalyzeJSCrypto)
 
 	// analyze_contention tool
 	contentionTool := mcp.NewTool("analyze_contention",
 		mcp.WithDescription("Detect thread contention issues including GC pauses affecting workers, sync IPC blocking, and lock contention"),
 		mcp.WithString("path", mcp.Required(), mcp.Description("Path to the profile JSON file")),
 	)
-	pos.server.AddTool(contentionTool, pos.handleAnalyzeContention)
+	pos.server.AddTool(withScopeParams(contentionTool), pos.handleAnalyzeContention)
 
 	// analyze_scaling tool
 	scalingTool := mcp.NewTool("analyze_scaling",
 		mcp.WithDescription("Analyze parallel scaling efficiency including worker utilization, speedup, and bottleneck identification"),
 		mcp.WithString("path", mcp.Required(), mcp.Description("Path to the profile JSON file")),
 	)
-	pos.server.AddTool(scalingTool, pos.handleAnalyzeScaling)
+	pos.server.AddTool(withScopeParams(scalingTool), pos.handleAnalyzeScaling)
 
 	// compare_scaling tool
 	compareScalingTool := mcp.NewTool("compare_scaling",
@@ -151,14 +152,14 @@ func (pos *PerfOwlServer) registerTools() {
 		mcp.WithString("baseline", mcp.Required(), mcp.Description("Path to the baseline profile JSON file")),
 		mcp.WithString("comparison", mcp.Required(), mcp.Description("Path to the comparison profile JSON file")),
 	)
-	pos.server.AddTool(compareScalingTool, pos.handleCompareScaling)
+	pos.server.AddTool(withScopeParams(compareScalingTool), pos.handleCompareScaling)
 
 	// batch_analyze tool
 	batchTool := mcp.NewTool("batch_analyze",
 		mcp.WithDescription("Analyze multiple profiles across worker counts and return aggregated results for charting. Provide a JSON array of profile entries."),
 		mcp.WithString("profiles", mcp.Required(), mcp.Description(`JSON array of profile entries. Each entry: {"path": "file.json.gz", "workers": 4, "label": "Chrome", "start_pattern": "EventDispatch", "end_pattern": "UpdateLayoutTree", "start_min_duration": 0, "end_min_duration": 1000}`)),
 	)
-	pos.server.AddTool(batchTool, pos.handleBatchAnalyze)
+	pos.server.AddTool(withThreadsParam(batchTool), pos.handleBatchAnalyze)
 
 	// generate_chart tool
 	chartTool := mcp.NewTool("generate_chart",
@@ -168,7 +169,7 @@ func (pos *PerfOwlServer) registerTools() {
 		mcp.WithString("output", mcp.Description("Output mode: inline (returns SVG), file (saves to path)")),
 		mcp.WithString("output_path", mcp.Description("File path for 'file' output mode (default: chart.svg)")),
 	)
-	pos.server.AddTool(chartTool, pos.handleGenerateChart)
+	pos.server.AddTool(withThreadsParam(chartTool), pos.handleGenerateChart)
 
 	// get_delimiter_markers tool
 	delimitersTool := mcp.NewTool("get_delimiter_markers",
@@ -177,7 +178,7 @@ func (pos *PerfOwlServer) registerTools() {
 		mcp.WithString("categories", mcp.Description("Filter by categories (comma-separated, e.g., 'DOM,Layout,Graphics')")),
 		mcp.WithNumber("limit", mcp.Description("Maximum number of markers to return (default: all)")),
 	)
-	pos.server.AddTool(delimitersTool, pos.handleGetDelimiterMarkers)
+	pos.server.AddTool(withScopeParams(delimitersTool), pos.handleGetDelimiterMarkers)
 
 	// measure_operation tool
 	measureTool := mcp.NewTool("measure_operation",
@@ -193,7 +194,141 @@ func (pos *PerfOwlServer) registerTools() {
 		mcp.WithNumber("start_min_duration", mcp.Description("Only match start markers with duration >= this value in ms (optional)")),
 		mcp.WithNumber("end_min_duration", mcp.Description("Only match end markers with duration >= this value in ms (optional)")),
 	)
-	pos.server.AddTool(measureTool, pos.handleMeasureOperation)
+	pos.server.AddTool(withScopeParams(measureTool), pos.handleMeasureOperation)
+
+	// analyze_jit_tiers tool
+	jitTool := mcp.NewTool("analyze_jit_tiers",
+		mcp.WithDescription("Analyze JavaScript time by JIT execution tier (interpreter, baseline interpreter, baseline, optimized) overall, per thread and per function, and list hot functions that never reach the optimizing tier"),
+		mcp.WithString("path", mcp.Required(), mcp.Description("Path to the profile JSON file")),
+		mcp.WithNumber("limit", mcp.Description("Maximum number of functions to return (default 20)")),
+	)
+	pos.server.AddTool(withScopeParams(jitTool), pos.handleAnalyzeJITTiers)
+
+	// compare_jit_tiers tool
+	compareJITTool := mcp.NewTool("compare_jit_tiers",
+		mcp.WithDescription("Compare the JIT tier mix between two profiles and list functions whose optimized share changed"),
+		mcp.WithString("baseline", mcp.Required(), mcp.Description("Path to the baseline profile JSON file")),
+		mcp.WithString("comparison", mcp.Required(), mcp.Description("Path to the comparison profile JSON file")),
+		mcp.WithNumber("limit", mcp.Description("Maximum number of functions to return (default 20)")),
+	)
+	pos.server.AddTool(withScopeParams(compareJITTool), pos.handleCompareJITTiers)
+
+	// get_source_lines tool
+	sourceLinesTool := mcp.NewTool("get_source_lines",
+		mcp.WithDescription("Attribute self and total time to individual source lines of a function or file, with an annotated source listing when source_root points to a local checkout"),
+		mcp.WithString("path", mcp.Required(), mcp.Description("Path to the profile JSON file")),
+		mcp.WithString("function", mcp.Description("Function name filter (case-insensitive substring match)")),
+		mcp.WithString("file", mcp.Description("File name or URL filter (case-insensitive substring match)")),
+		mcp.WithString("source_root", mcp.Description("Local directory containing the source files, enables the annotated listing")),
+		mcp.WithNumber("context", mcp.Description("Lines of context around sampled lines in the listing (default 3)")),
+		mcp.WithNumber("limit", mcp.Description("Maximum number of hot lines to return (default 20)")),
+	)
+	pos.server.AddTool(withScopeParams(sourceLinesTool), pos.handleGetSourceLines)
+
+	// analyze_resources tool
+	resourcesTool := mcp.NewTool("analyze_resources",
+		mcp.WithDescription("Attribute CPU self and total time to scripts, origins, first- vs third-party code, extensions and native libraries"),
+		mcp.WithString("path", mcp.Required(), mcp.Description("Path to the profile JSON file")),
+		mcp.WithString("first_party", mcp.Description("Comma-separated first-party domains, subdomains match (e.g., 'example.com,examplecdn.net')")),
+		mcp.WithNumber("limit", mcp.Description("Maximum number of resources, origins and libraries to return (default 20)")),
+	)
+	pos.server.AddTool(withScopeParams(resourcesTool), pos.handleAnalyzeResources)
+
+	// analyze_frames tool
+	framesTool := mcp.NewTool("analyze_frames",
+		mcp.WithDescription("Analyze frame rate and jank from refresh driver, compositor, vsync and Chrome frame markers: frame time distribution, p50/p95/p99, dropped and janky frames, and the longest frames with overlapping main-thread work"),
+		mcp.WithString("path", mcp.Required(), mcp.Description("Path to the profile JSON file")),
+		mcp.WithNumber("limit", mcp.Description("Maximum number of long frames to return (default 10)")),
+	)
+	pos.server.AddTool(withScopeParams(framesTool), pos.handleAnalyzeFrames)
+
+	// analyze_interactions tool
+	interactionsTool := mcp.NewTool("analyze_interactions",
+		mcp.WithDescription("Analyze input responsiveness: split each click, tap and key press into input delay, processing and presentation delay, compute an INP-style score, and list the slowest interactions with the functions running during them"),
+		mcp.WithString("path", mcp.Required(), mcp.Description("Path to the profile JSON file")),
+		mcp.WithNumber("limit", mcp.Description("Maximum number of slow interactions to return (default 10)")),
+	)
+	pos.server.AddTool(withScopeParams(interactionsTool), pos.handleAnalyzeInteractions)
+
+	// analyze_layout_shifts tool
+	layoutShiftsTool := mcp.NewTool("analyze_layout_shifts",
+		mcp.WithDescription("Analyze layout stability from Chrome LayoutShift events: session-windowed CLS, shifts excluded after input, and the largest shifts with their timestamps and moved DOM nodes"),
+		mcp.WithString("path", mcp.Required(), mcp.Description("Path to the profile JSON file")),
+		mcp.WithNumber("limit", mcp.Description("Maximum number of shifts to return (default 10)")),
+	)
+	pos.server.AddTool(withScopeParams(layoutShiftsTool), pos.handleAnalyzeLayoutShifts)
+
+	// analyze_network tool
+	networkTool := mcp.NewTool("analyze_network",
+		mcp.WithDescription("Reconstruct the network request waterfall from Firefox Network markers and Chrome Resource events: per-request DNS/connect/TLS/waiting/download timings, render-blocking requests before first paint, critical request chains, and per-domain totals"),
+		mcp.WithString("path", mcp.Required(), mcp.Description("Path to the profile JSON file")),
+		mcp.WithNumber("limit", mcp.Description("Maximum number of requests to return (default 50)")),
+	)
+	pos.server.AddTool(withScopeParams(networkTool), pos.handleAnalyzeNetwork)
+
+	// analyze_script_costs tool
+	scriptCostsTool := mcp.NewTool("analyze_script_costs",
+		mcp.WithDescription("Break JavaScript cost down per script URL: parse, compile and top-level execution time, main thread versus background compilation, and transfer size and download-to-eval delay"),
+		mcp.WithString("path", mcp.Required(), mcp.Description("Path to the profile JSON file")),
+		mcp.WithNumber("limit", mcp.Description("Maximum number of scripts to return (default 20)")),
+	)
+	pos.server.AddTool(withScopeParams(scriptCostsTool), pos.handleAnalyzeScriptCosts)
+
+	// analyze_gc tool
+	gcTool := mcp.NewTool("analyze_gc",
+		mcp.WithDescription("Analyze garbage collection from Firefox GCMajor/GCMinor/GCSlice payloads and Chrome MajorGC/MinorGC events: reason histogram, minimum mutator utilisation, nursery promotion rate, slices over budget, and functions sampled before each GC"),
+		mcp.WithString("path", mcp.Required(), mcp.Description("Path to the profile JSON file")),
+		mcp.WithNumber("limit", mcp.Description("Maximum number of collections, slices and functions to return (default 10)")),
+	)
+	pos.server.AddTool(withScopeParams(gcTool), pos.handleAnalyzeGC)
+
+	// analyze_styles tool
+	stylesTool := mcp.NewTool("analyze_styles",
+		mcp.WithDescription("Analyze style recalculation from Firefox Styles markers and Chrome UpdateLayoutTree events: elements styled per recalc, style cache reuse, recalcs forced by JavaScript with the forcing stack, and Chrome selector stats"),
+		mcp.WithString("path", mcp.Required(), mcp.Description("Path to the profile JSON file")),
+		mcp.WithNumber("limit", mcp.Description("Maximum number of recalcs, culprits and selectors to return (default 10)")),
+	)
+	pos.server.AddTool(withScopeParams(stylesTool), pos.handleAnalyzeStyles)
+
+	// analyze_concurrency tool
+	concurrencyTool := mcp.NewTool("analyze_concurrency",
+		mcp.WithDescription("Analyze how many threads run in parallel over time from sample timestamps and CPU deltas: average parallelism, time per concurrency level, serial phases where only the main thread runs, and worker load imbalance"),
+		mcp.WithString("path", mcp.Required(), mcp.Description("Path to the profile JSON file")),
+		mcp.WithNumber("bucket_ms", mcp.Description("Timeline resolution in ms (default: the profile sampling interval)")),
+		mcp.WithBoolean("include_timeline", mcp.Description("If true, include the per-bucket timeline of running threads")),
+	)
+	pos.server.AddTool(withScopeParams(concurrencyTool), pos.handleAnalyzeConcurrency)
+
+	// analyze_critical_path tool
+	criticalPathTool := mcp.NewTool("analyze_critical_path",
+		mcp.WithDescription("Find what determined the time between two markers across threads. Walks back from the end marker following postMessage deliveries, Chrome flow events and IPC messages the thread waited on, and returns the thread segments on the critical path with their contribution and the slack of off-path workers. Pattern format as in measure_operation."),
+		mcp.WithString("path", mcp.Required(), mcp.Description("Path to the profile JSON file")),
+		mcp.WithString("start_pattern", mcp.Required(), mcp.Description("Pattern to match start marker (e.g., 'UserTiming:decrypt-start')")),
+		mcp.WithString("end_pattern", mcp.Required(), mcp.Description("Pattern to match end marker (e.g., 'UserTiming:decrypt-end')")),
+		mcp.WithBoolean("find_last", mcp.Description("If true, use the LAST matching end marker instead of the first")),
+	)
+	pos.server.AddTool(withScopeParams(criticalPathTool), pos.handleAnalyzeCriticalPath)
+
+	// analyze_messages tool
+	messagesTool := mcp.NewTool("analyze_messages",
+		mcp.WithDescription("Analyze postMessage traffic between threads. Pairs sends with the events that handled them and returns per-thread sent/received counts and, per sender/receiver channel, the message count, queueing latency (min/avg/p50/p95/max) and payload sizes. Set graph to get a DOT or Mermaid graph of who talks to whom instead."),
+		mcp.WithString("path", mcp.Required(), mcp.Description("Path to the profile JSON file")),
+		mcp.WithNumber("limit", mcp.Description("Maximum number of channels to return (default 20)")),
+		mcp.WithString("graph", mcp.Description("Return a graph instead of the analysis: dot or mermaid (optional)")),
+	)
+	pos.server.AddTool(withScopeParams(messagesTool), pos.handleAnalyzeMessages)
+
+	// compare_profile_sets tool
+	compareSetsTool := mcp.NewTool("compare_profile_sets",
+		mcp.WithDescription("Compare repeated baseline runs against repeated candidate runs. Tests duration, GC time, long tasks, optional operation time between two marker patterns and the self time of the heaviest functions with a Mann-Whitney U test, and returns per metric the medians, change, p-value, Holm-Bonferroni adjusted p-value across all metrics and functions, Cliff's delta effect size and a verdict decided on the adjusted p-value (improved, regressed, unchanged, or inconclusive when there are too few runs)."),
+		mcp.WithArray("baselines", mcp.Required(), mcp.WithStringItems(), mcp.Description("Paths to the baseline profile JSON files")),
+		mcp.WithArray("candidates", mcp.Required(), mcp.WithStringItems(), mcp.Description("Paths to the candidate profile JSON files")),
+		mcp.WithString("start_pattern", mcp.Description("Pattern for the operation's start marker (optional)")),
+		mcp.WithString("end_pattern", mcp.Description("Pattern for the operation's end marker (optional)")),
+		mcp.WithNumber("alpha", mcp.Description("Significance level (default 0.05)")),
+		mcp.WithNumber("limit", mcp.Description("Maximum number of functions to compare (default 10)")),
+	)
+	pos.server.AddTool(withScopeParams(compareSetsTool), pos.handleCompareProfileSets)
 }
 
 // Serve starts the MCP server on stdio
@@ -209,12 +344,12 @@ func (pos *PerfOwlServer) handleGetSummary(ctx context.Context, req mcp.CallTool
 		return nil, fmt.Errorf("path is required: %w", err)
 	}
 
-	profile, _, err := parser.LoadProfileAuto(path)
+	profile, bt, err := loadProfileWithType(path, req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to load profile: %w", err)
+		return nil, err
 	}
 
-	summary := buildSummary(profile)
+	summary := report.BuildSummary(profile, bt)
 	output, err := toon.Encode(summary)
 	if err != nil {
 		return nil, fmt.Errorf("failed to encode summary: %w", err)
@@ -229,32 +364,14 @@ func (pos *PerfOwlServer) handleGetBottlenecks(ctx context.Context, req mcp.Call
 		return nil, fmt.Errorf("path is required: %w", err)
 	}
 
-	profile, _, err := parser.LoadProfileAuto(path)
+	profile, err := loadProfile(path, req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to load profile: %w", err)
+		return nil, err
 	}
 
-	bottlenecks := analyzer.DetectBottlenecks(profile)
+	bottlenecks := report.Bottlenecks(profile, req.GetString("min_severity", ""))
 
-	// Filter by min severity if provided
-	if minSev, err := req.RequireString("min_severity"); err == nil && minSev != "" {
-		sev := analyzer.ParseSeverity(minSev)
-		var filtered []analyzer.Bottleneck
-		for _, b := range bottlenecks {
-			if b.Severity >= sev {
-				filtered = append(filtered, b)
-			}
-		}
-		bottlenecks = filtered
-	}
-
-	report := analyzer.BottleneckReport{
-		Score:       analyzer.CalculateScore(bottlenecks),
-		Summary:     analyzer.GenerateSummary(bottlenecks, profile),
-		Bottlenecks: bottlenecks,
-	}
-
-	output, err := toon.Encode(report)
+	output, err := toon.Encode(bottlenecks)
 	if err != nil {
 		return nil, fmt.Errorf("failed to encode bottlenecks: %w", err)
 	}
@@ -268,9 +385,9 @@ func (pos *PerfOwlServer) handleGetMarkers(ctx context.Context, req mcp.CallTool
 		return nil, fmt.Errorf("path is required: %w", err)
 	}
 
-	profile, _, err := parser.LoadProfileAuto(path)
+	profile, err := loadProfile(path, req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to load profile: %w", err)
+		return nil, err
 	}
 
 	// Extract all markers
@@ -330,9 +447,9 @@ func (pos *PerfOwlServer) handleAnalyzeExtension(ctx context.Context, req mcp.Ca
 		return nil, fmt.Errorf("path is required: %w", err)
 	}
 
-	profile, _, err := parser.LoadProfileAuto(path)
+	profile, err := loadProfile(path, req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to load profile: %w", err)
+		return nil, err
 	}
 
 	report := analyzer.AnalyzeExtensions(profile)
@@ -362,28 +479,14 @@ func (pos *PerfOwlServer) handleAnalyzeProfile(ctx context.Context, req mcp.Call
 		return nil, fmt.Errorf("path is required: %w", err)
 	}
 
-	profile, _, err := parser.LoadProfileAuto(path)
+	profile, bt, err := loadProfileWithType(path, req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to load profile: %w", err)
+		return nil, err
 	}
 
-	// Build comprehensive analysis
-	summary := buildSummary(profile)
-	bottlenecks := analyzer.DetectBottlenecks(profile)
-	bottleneckReport := analyzer.BottleneckReport{
-		Score:       analyzer.CalculateScore(bottlenecks),
-		Summary:     analyzer.GenerateSummary(bottlenecks, profile),
-		Bottlenecks: bottlenecks,
-	}
-	extensions := analyzer.AnalyzeExtensions(profile)
-
-	fullReport := map[string]interface{}{
-		"summary":     summary,
-		"bottlenecks": bottleneckReport,
-		"extensions":  extensions,
-	}
+	analysis := report.Analyze(profile, bt)
 
-	output, err := toon.Encode(fullReport)
+	output, err := toon.Encode(analysis)
 	if err != nil {
 		return nil, fmt.Errorf("failed to encode full report: %w", err)
 	}
@@ -391,35 +494,15 @@ func (pos *PerfOwlServer) handleAnalyzeProfile(ctx context.Context, req mcp.Call
 	return mcp.NewToolResultText(output), nil
 }
 
-// Helper types and functions
-
-type ProfileSummary struct {
-	DurationSeconds float64           `json:"duration_seconds"`
-	Platform        string            `json:"platform"`
-	OSCPU           string            `json:"os_cpu"`
-	Product         string            `json:"product"`
-	BuildID         string            `json:"build_id"`
-	CPUName         string            `json:"cpu_name"`
-	PhysicalCPUs    int               `json:"physical_cpus"`
-	LogicalCPUs     int               `json:"logical_cpus"`
-	ThreadCount     int               `json:"thread_count"`
-	MainThreadCount int               `json:"main_thread_count"`
-	ExtensionCount  int               `json:"extension_count"`
-	Extensions      map[string]string `json:"extensions"`
-	Features        []string          `json:"features"`
-	TotalMarkers    int               `json:"total_markers"`
-	TotalSamples    int               `json:"total_samples"`
-}
-
 func (pos *PerfOwlServer) handleGetCallTree(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	path, err := req.RequireString("path")
 	if err != nil {
 		return nil, fmt.Errorf("path is required: %w", err)
 	}
 
-	profile, _, err := parser.LoadProfileAuto(path)
+	profile, err := loadProfile(path, req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to load profile: %w", err)
+		return nil, err
 	}
 
 	threadName := ""
@@ -427,12 +510,12 @@ func (pos *PerfOwlServer) handleGetCallTree(ctx context.Context, req mcp.CallToo
 		threadName = t
 	}
 
-	limit := 20
+	limit := report.DefaultLimit
 	if l, err := req.RequireFloat("limit"); err == nil && l > 0 {
 		limit = int(l)
 	}
 
-	analysis := analyzer.AnalyzeCallTree(profile, threadName, limit)
+	analysis := report.CallTree(profile, threadName, limit)
 
 	output, err := toon.Encode(analysis)
 	if err != nil {
@@ -448,9 +531,9 @@ func (pos *PerfOwlServer) handleGetCategoryBreakdown(ctx context.Context, req mc
 		return nil, fmt.Errorf("path is required: %w", err)
 	}
 
-	profile, _, err := parser.LoadProfileAuto(path)
+	profile, err := loadProfile(path, req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to load profile: %w", err)
+		return nil, err
 	}
 
 	threadName := ""
@@ -458,7 +541,7 @@ func (pos *PerfOwlServer) handleGetCategoryBreakdown(ctx context.Context, req mc
 		threadName = t
 	}
 
-	breakdown := analyzer.AnalyzeCategories(profile, threadName)
+	breakdown := report.Categories(profile, threadName)
 
 	output, err := toon.Encode(breakdown)
 	if err != nil {
@@ -474,12 +557,12 @@ func (pos *PerfOwlServer) handleGetThreadAnalysis(ctx context.Context, req mcp.C
 		return nil, fmt.Errorf("path is required: %w", err)
 	}
 
-	profile, _, err := parser.LoadProfileAuto(path)
+	profile, err := loadProfile(path, req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to load profile: %w", err)
+		return nil, err
 	}
 
-	analysis := analyzer.AnalyzeThreads(profile)
+	analysis := report.Threads(profile)
 
 	output, err := toon.Encode(analysis)
 	if err != nil {
@@ -504,13 +587,21 @@ func (pos *PerfOwlServer) handleCompareProfiles(ctx context.Context, req mcp.Cal
 	if err != nil {
 		return nil, fmt.Errorf("failed to load baseline profile: %w", err)
 	}
+	baseline, err = applyScope(baseline, req)
+	if err != nil {
+		return nil, err
+	}
 
 	comparison, _, err := parser.LoadProfileAuto(comparisonPath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to load comparison profile: %w", err)
 	}
+	comparison, err = applyScope(comparison, req)
+	if err != nil {
+		return nil, err
+	}
 
-	diff := analyzer.CompareProfiles(baseline, comparison)
+	diff := report.Compare(baseline, comparison)
 
 	output, err := toon.Encode(diff)
 	if err != nil {
@@ -520,44 +611,15 @@ func (pos *PerfOwlServer) handleCompareProfiles(ctx context.Context, req mcp.Cal
 	return mcp.NewToolResultText(output), nil
 }
 
-// Helper types and functions
-
-func buildSummary(profile *parser.Profile) ProfileSummary {
-	summary := ProfileSummary{
-		DurationSeconds: profile.DurationSeconds(),
-		Platform:        profile.Meta.Platform,
-		OSCPU:           profile.Meta.OSCPU,
-		Product:         profile.Meta.Product,
-		BuildID:         profile.Meta.AppBuildID,
-		CPUName:         profile.Meta.CPUName,
-		PhysicalCPUs:    profile.Meta.PhysicalCPUs,
-		LogicalCPUs:     profile.Meta.LogicalCPUs,
-		ThreadCount:     profile.ThreadCount(),
-		ExtensionCount:  profile.ExtensionCount(),
-		Extensions:      profile.GetExtensions(),
-		Features:        profile.Meta.Configuration.Features,
-	}
-
-	for _, t := range profile.Threads {
-		if t.IsMainThread {
-			summary.MainThreadCount++
-		}
-		summary.TotalMarkers += t.Markers.Length
-		summary.TotalSamples += t.Samples.Length
-	}
-
-	return summary
-}
-
 func (pos *PerfOwlServer) handleAnalyzeWorkers(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	path, err := req.RequireString("path")
 	if err != nil {
 		return nil, fmt.Errorf("path is required: %w", err)
 	}
 
-	profile, _, err := parser.LoadProfileAuto(path)
+	profile, err := loadProfile(path, req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to load profile: %w", err)
+		return nil, err
 	}
 
 	analysis := analyzer.AnalyzeWorkers(profile)
@@ -576,9 +638,9 @@ func (pos *PerfOwlServer) handleAnalyzeCrypto(ctx context.Context, req mcp.CallT
 		return nil, fmt.Errorf("path is required: %w", err)
 	}
 
-	profile, _, err := parser.LoadProfileAuto(path)
+	profile, err := loadProfile(path, req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to load profile: %w", err)
+		return nil, err
 	}
 
 	analysis := analyzer.AnalyzeCrypto(profile)
@@ -597,9 +659,9 @@ func (pos *PerfOwlServer) handleAnalyzeJSCrypto(ctx context.Context, req mcp.Cal
 		return nil, fmt.Errorf("path is required: %w", err)
 	}
 
-	profile, _, err := parser.LoadProfileAuto(path)
+	profile, err := loadProfile(path, req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to load profile: %w", err)
+		return nil, err
 	}
 
 	analysis := analyzer.AnalyzeJSCrypto(profile)
@@ -618,9 +680,9 @@ func (pos *PerfOwlServer) handleAnalyzeContention(ctx context.Context, req mcp.C
 		return nil, fmt.Errorf("path is required: %w", err)
 	}
 
-	profile, _, err := parser.LoadProfileAuto(path)
+	profile, err := loadProfile(path, req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to load profile: %w", err)
+		return nil, err
 	}
 
 	analysis := analyzer.AnalyzeContention(profile)
@@ -639,9 +701,9 @@ func (pos *PerfOwlServer) handleAnalyzeScaling(ctx context.Context, req mcp.Call
 		return nil, fmt.Errorf("path is required: %w", err)
 	}
 
-	profile, _, err := parser.LoadProfileAuto(path)
+	profile, err := loadProfile(path, req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to load profile: %w", err)
+		return nil, err
 	}
 
 	analysis := analyzer.AnalyzeScaling(profile)
@@ -669,11 +731,19 @@ func (pos *PerfOwlServer) handleCompareScaling(ctx context.Context, req mcp.Call
 	if err != nil {
 		return nil, fmt.Errorf("failed to load baseline profile: %w", err)
 	}
+	baseline, err = applyScope(baseline, req)
+	if err != nil {
+		return nil, err
+	}
 
 	comparison, _, err := parser.LoadProfileAuto(comparisonPath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to load comparison profile: %w", err)
 	}
+	comparison, err = applyScope(comparison, req)
+	if err != nil {
+		return nil, err
+	}
 
 	result := analyzer.CompareScaling(baseline, comparison)
 
@@ -699,6 +769,7 @@ func (pos *PerfOwlServer) handleBatchAnalyze(ctx context.Context, req mcp.CallTo
 	if len(profiles) == 0 {
 		return nil, fmt.Errorf("no profiles provided")
 	}
+	applyDefaultThreads(profiles, req)
 
 	result, err := analyzer.AnalyzeBatch(profiles)
 	if err != nil {
@@ -727,6 +798,7 @@ func (pos *PerfOwlServer) handleGenerateChart(ctx context.Context, req mcp.CallT
 	if len(profiles) == 0 {
 		return nil, fmt.Errorf("no profiles provided")
 	}
+	applyDefaultThreads(profiles, req)
 
 	chartType := chart.ChartWallClock
 	if ct, err := req.RequireString("chart_type"); err == nil && ct != "" {
@@ -765,9 +837,9 @@ func (pos *PerfOwlServer) handleGetDelimiterMarkers(ctx context.Context, req mcp
 		return nil, fmt.Errorf("path is required: %w", err)
 	}
 
-	profile, _, err := parser.LoadProfileAuto(path)
+	profile, err := loadProfile(path, req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to load profile: %w", err)
+		return nil, err
 	}
 
 	var categories []string
@@ -796,9 +868,9 @@ func (pos *PerfOwlServer) handleMeasureOperation(ctx context.Context, req mcp.Ca
 		return nil, fmt.Errorf("path is required: %w", err)
 	}
 
-	profile, _, err := parser.LoadProfileAuto(path)
+	profile, err := loadProfile(path, req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to load profile: %w", err)
+		return nil, err
 	}
 
 	// Check if using index-based measurement
@@ -868,6 +940,603 @@ func (pos *PerfOwlServer) handleMeasureOperation(ctx context.Context, req mcp.Ca
 	return mcp.NewToolResultText(output), nil
 }
 
+func (pos *PerfOwlServer) handleAnalyzeJITTiers(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	path, err := req.RequireString("path")
+	if err != nil {
+		return nil, fmt.Errorf("path is required: %w", err)
+	}
+
+	limit := 20
+	if l, err := req.RequireFloat("limit"); err == nil && l > 0 {
+		limit = int(l)
+	}
+
+	profile, err := loadProfile(path, req)
+	if err != nil {
+		return nil, err
+	}
+
+	analysis := analyzer.AnalyzeJITTiers(profile, limit)
+
+	output, err := toon.Encode(analysis)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode JIT tier analysis: %w", err)
+	}
+
+	return mcp.NewToolResultText(output), nil
+}
+
+func (pos *PerfOwlServer) handleCompareJITTiers(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	baselinePath, err := req.RequireString("baseline")
+	if err != nil {
+		return nil, fmt.Errorf("baseline path is required: %w", err)
+	}
+
+	comparisonPath, err := req.RequireString("comparison")
+	if err != nil {
+		return nil, fmt.Errorf("comparison path is required: %w", err)
+	}
+
+	limit := 20
+	if l, err := req.RequireFloat("limit"); err == nil && l > 0 {
+		limit = int(l)
+	}
+
+	baseline, _, err := parser.LoadProfileAuto(baselinePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load baseline profile: %w", err)
+	}
+	baseline, err = applyScope(baseline, req)
+	if err != nil {
+		return nil, err
+	}
+
+	comparison, _, err := parser.LoadProfileAuto(comparisonPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load comparison profile: %w", err)
+	}
+	comparison, err = applyScope(comparison, req)
+	if err != nil {
+		return nil, err
+	}
+
+	result := analyzer.CompareJITTiers(baseline, comparison, limit)
+
+	output, err := toon.Encode(result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode JIT tier comparison: %w", err)
+	}
+
+	return mcp.NewToolResultText(output), nil
+}
+
+func (pos *PerfOwlServer) handleGetSourceLines(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	path, err := req.RequireString("path")
+	if err != nil {
+		return nil, fmt.Errorf("path is required: %w", err)
+	}
+
+	opts := analyzer.SourceLineOptions{
+		Function:     req.GetString("function", ""),
+		File:         req.GetString("file", ""),
+		SourceRoot:   req.GetString("source_root", ""),
+		ContextLines: 3,
+		Limit:        20,
+	}
+	if c, err := req.RequireFloat("context"); err == nil && c >= 0 {
+		opts.ContextLines = int(c)
+	}
+	if l, err := req.RequireFloat("limit"); err == nil && l > 0 {
+		opts.Limit = int(l)
+	}
+
+	profile, err := loadProfile(path, req)
+	if err != nil {
+		return nil, err
+	}
+
+	analysis, err := analyzer.AnalyzeSourceLines(profile, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	output, err := toon.Encode(analysis)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode source line analysis: %w", err)
+	}
+
+	return mcp.NewToolResultText(output), nil
+}
+
+func (pos *PerfOwlServer) handleAnalyzeResources(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	path, err := req.RequireString("path")
+	if err != nil {
+		return nil, fmt.Errorf("path is required: %w", err)
+	}
+
+	opts := analyzer.ResourceOptions{Limit: 20}
+	if l, err := req.RequireFloat("limit"); err == nil && l > 0 {
+		opts.Limit = int(l)
+	}
+	for _, d := range strings.Split(req.GetString("first_party", ""), ",") {
+		if d = strings.TrimSpace(d); d != "" {
+			opts.FirstPartyDomains = append(opts.FirstPartyDomains, d)
+		}
+	}
+
+	profile, err := loadProfile(path, req)
+	if err != nil {
+		return nil, err
+	}
+
+	analysis := analyzer.AnalyzeResources(profile, opts)
+
+	output, err := toon.Encode(analysis)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode resource analysis: %w", err)
+	}
+
+	return mcp.NewToolResultText(output), nil
+}
+
+func (pos *PerfOwlServer) handleAnalyzeFrames(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	path, err := req.RequireString("path")
+	if err != nil {
+		return nil, fmt.Errorf("path is required: %w", err)
+	}
+
+	limit := 10
+	if l, err := req.RequireFloat("limit"); err == nil && l > 0 {
+		limit = int(l)
+	}
+
+	profile, err := loadProfile(path, req)
+	if err != nil {
+		return nil, err
+	}
+
+	analysis := analyzer.AnalyzeFrames(profile, limit)
+
+	output, err := toon.Encode(analysis)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode frame analysis: %w", err)
+	}
+
+	return mcp.NewToolResultText(output), nil
+}
+
+func (pos *PerfOwlServer) handleAnalyzeInteractions(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	path, err := req.RequireString("path")
+	if err != nil {
+		return nil, fmt.Errorf("path is required: %w", err)
+	}
+
+	limit := 10
+	if l, err := req.RequireFloat("limit"); err == nil && l > 0 {
+		limit = int(l)
+	}
+
+	profile, err := loadProfile(path, req)
+	if err != nil {
+		return nil, err
+	}
+
+	analysis := analyzer.AnalyzeInteractions(profile, limit)
+
+	output, err := toon.Encode(analysis)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode interaction analysis: %w", err)
+	}
+
+	return mcp.NewToolResultText(output), nil
+}
+
+func (pos *PerfOwlServer) handleAnalyzeLayoutShifts(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	path, err := req.RequireString("path")
+	if err != nil {
+		return nil, fmt.Errorf("path is required: %w", err)
+	}
+
+	limit := 10
+	if l, err := req.RequireFloat("limit"); err == nil && l > 0 {
+		limit = int(l)
+	}
+
+	profile, err := loadProfile(path, req)
+	if err != nil {
+		return nil, err
+	}
+
+	analysis := analyzer.AnalyzeLayoutShifts(profile, limit)
+
+	output, err := toon.Encode(analysis)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode layout shift analysis: %w", err)
+	}
+
+	return mcp.NewToolResultText(output), nil
+}
+
+func (pos *PerfOwlServer) handleAnalyzeNetwork(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	path, err := req.RequireString("path")
+	if err != nil {
+		return nil, fmt.Errorf("path is required: %w", err)
+	}
+
+	limit := 50
+	if l, err := req.RequireFloat("limit"); err == nil && l > 0 {
+		limit = int(l)
+	}
+
+	profile, err := loadProfile(path, req)
+	if err != nil {
+		return nil, err
+	}
+
+	analysis := analyzer.AnalyzeNetwork(profile, limit)
+
+	output, err := toon.Encode(analysis)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode network analysis: %w", err)
+	}
+
+	return mcp.NewToolResultText(output), nil
+}
+
+func (pos *PerfOwlServer) handleAnalyzeScriptCosts(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	path, err := req.RequireString("path")
+	if err != nil {
+		return nil, fmt.Errorf("path is required: %w", err)
+	}
+
+	limit := 20
+	if l, err := req.RequireFloat("limit"); err == nil && l > 0 {
+		limit = int(l)
+	}
+
+	profile, err := loadProfile(path, req)
+	if err != nil {
+		return nil, err
+	}
+
+	analysis := analyzer.AnalyzeScriptCosts(profile, limit)
+
+	output, err := toon.Encode(analysis)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode script cost analysis: %w", err)
+	}
+
+	return mcp.NewToolResultText(output), nil
+}
+
+func (pos *PerfOwlServer) handleAnalyzeGC(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	path, err := req.RequireString("path")
+	if err != nil {
+		return nil, fmt.Errorf("path is required: %w", err)
+	}
+
+	limit := 10
+	if l, err := req.RequireFloat("limit"); err == nil && l > 0 {
+		limit = int(l)
+	}
+
+	profile, err := loadProfile(path, req)
+	if err != nil {
+		return nil, err
+	}
+
+	analysis := analyzer.AnalyzeGC(profile, limit)
+
+	output, err := toon.Encode(analysis)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode GC analysis: %w", err)
+	}
+
+	return mcp.NewToolResultText(output), nil
+}
+
+func (pos *PerfOwlServer) handleAnalyzeStyles(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	path, err := req.RequireString("path")
+	if err != nil {
+		return nil, fmt.Errorf("path is required: %w", err)
+	}
+
+	limit := 10
+	if l, err := req.RequireFloat("limit"); err == nil && l > 0 {
+		limit = int(l)
+	}
+
+	profile, err := loadProfile(path, req)
+	if err != nil {
+		return nil, err
+	}
+
+	analysis := analyzer.AnalyzeStyles(profile, limit)
+
+	output, err := toon.Encode(analysis)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode style analysis: %w", err)
+	}
+
+	return mcp.NewToolResultText(output), nil
+}
+
+func (pos *PerfOwlServer) handleAnalyzeConcurrency(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	path, err := req.RequireString("path")
+	if err != nil {
+		return nil, fmt.Errorf("path is required: %w", err)
+	}
+
+	bucketMs := 0.0
+	if b, err := req.RequireFloat("bucket_ms"); err == nil && b > 0 {
+		bucketMs = b
+	}
+
+	profile, err := loadProfile(path, req)
+	if err != nil {
+		return nil, err
+	}
+
+	analysis := analyzer.AnalyzeConcurrency(profile, bucketMs)
+	if include, err := req.RequireBool("include_timeline"); err != nil || !include {
+		analysis.Timeline = nil
+	}
+
+	output, err := toon.Encode(analysis)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode concurrency analysis: %w", err)
+	}
+
+	return mcp.NewToolResultText(output), nil
+}
+
+func (pos *PerfOwlServer) handleAnalyzeCriticalPath(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	path, err := req.RequireString("path")
+	if err != nil {
+		return nil, fmt.Errorf("path is required: %w", err)
+	}
+
+	startPattern, err := req.RequireString("start_pattern")
+	if err != nil {
+		return nil, fmt.Errorf("start_pattern is required: %w", err)
+	}
+
+	endPattern, err := req.RequireString("end_pattern")
+	if err != nil {
+		return nil, fmt.Errorf("end_pattern is required: %w", err)
+	}
+
+	opts := analyzer.MeasureOptions{
+		StartPattern: startPattern,
+		EndPattern:   endPattern,
+	}
+	if fl, err := req.RequireBool("find_last"); err == nil {
+		opts.FindLast = fl
+	}
+
+	profile, err := loadProfile(path, req)
+	if err != nil {
+		return nil, err
+	}
+
+	analysis, err := analyzer.AnalyzeCriticalPath(profile, opts)
+	if err != nil {
+		return nil, fmt.Errorf("critical path failed: %w", err)
+	}
+
+	output, err := toon.Encode(analysis)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode critical path analysis: %w", err)
+	}
+
+	return mcp.NewToolResultText(output), nil
+}
+
+func (pos *PerfOwlServer) handleAnalyzeMessages(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	path, err := req.RequireString("path")
+	if err != nil {
+		return nil, fmt.Errorf("path is required: %w", err)
+	}
+
+	limit := 20
+	if l, err := req.RequireFloat("limit"); err == nil && l > 0 {
+		limit = int(l)
+	}
+	graph, _ := req.RequireString("graph")
+
+	profile, err := loadProfile(path, req)
+	if err != nil {
+		return nil, err
+	}
+
+	analysis := analyzer.AnalyzeMessageFlow(profile, limit)
+
+	switch graph {
+	case "":
+	case "dot":
+		return mcp.NewToolResultText(analyzer.FormatMessageFlowDOT(analysis)), nil
+	case "mermaid":
+		return mcp.NewToolResultText(analyzer.FormatMessageFlowMermaid(analysis)), nil
+	default:
+		return nil, fmt.Errorf("unknown graph format %q (use dot or mermaid)", graph)
+	}
+
+	output, err := toon.Encode(analysis)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode message flow analysis: %w", err)
+	}
+
+	return mcp.NewToolResultText(output), nil
+}
+
+func (pos *PerfOwlServer) handleCompareProfileSets(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	baselinePaths, err := req.RequireStringSlice("baselines")
+	if err != nil {
+		return nil, fmt.Errorf("baselines are required: %w", err)
+	}
+	if len(baselinePaths) == 0 {
+		return nil, fmt.Errorf("baselines must list at least one profile")
+	}
+	candidatePaths, err := req.RequireStringSlice("candidates")
+	if err != nil {
+		return nil, fmt.Errorf("candidates are required: %w", err)
+	}
+	if len(candidatePaths) == 0 {
+		return nil, fmt.Errorf("candidates must list at least one profile")
+	}
+
+	opts := analyzer.CompareSetOptions{}
+	opts.Operation.StartPattern, _ = req.RequireString("start_pattern")
+	opts.Operation.EndPattern, _ = req.RequireString("end_pattern")
+	if a, err := req.RequireFloat("alpha"); err == nil {
+		if a <= 0 || a >= 1 {
+			return nil, fmt.Errorf("alpha must be between 0 and 1")
+		}
+		opts.Alpha = a
+	}
+	if l, err := req.RequireFloat("limit"); err == nil && l > 0 {
+		opts.FunctionLimit = int(l)
+	}
+
+	load := func(paths []string) ([]*parser.Profile, error) {
+		profiles := make([]*parser.Profile, 0, len(paths))
+		for _, path := range paths {
+			profile, err := loadProfile(path, req)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", path, err)
+			}
+			profiles = append(profiles, profile)
+		}
+		return profiles, nil
+	}
+	baselines, err := load(baselinePaths)
+	if err != nil {
+		return nil, err
+	}
+	candidates, err := load(candidatePaths)
+	if err != nil {
+		return nil, err
+	}
+
+	comparison := analyzer.CompareProfileSets(baselines, candidates, opts)
+
+	output, err := toon.Encode(comparison)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode profile set comparison: %w", err)
+	}
+
+	return mcp.NewToolResultText(output), nil
+}
+
+// withScopeParams adds the optional time range and thread selection parameters to a tool
+func withScopeParams(tool mcp.Tool) mcp.Tool {
+	mcp.WithString("range", mcp.Description("Only analyze samples and markers in this time range (e.g., '1200ms-3400ms')"))(&tool)
+	mcp.WithString("range_from_markers", mcp.Description("Only analyze the range between two marker patterns (e.g., 'DOMEvent:click..Paint')"))(&tool)
+	return withThreadsParam(tool)
+}
+
+// withThreadsParam adds the optional thread selection parameter to a tool
+func withThreadsParam(tool mcp.Tool) mcp.Tool {
+	mcp.WithString("threads", mcp.Description("Only analyze matching threads: comma-separated terms of name globs or name:, re:, pid:, tid:, process:, pname:, main: conditions joined with '&', prefix '!' to exclude (e.g., 'GeckoMain&process:tab,!pid:1234')"))(&tool)
+	return tool
+}
+
+// applyDefaultThreads sets the threads parameter on batch entries that don't specify their own selector
+func applyDefaultThreads(profiles []analyzer.ProfileEntry, req mcp.CallToolRequest) {
+	threads := req.GetString("threads", "")
+	if threads == "" {
+		return
+	}
+	for i := range profiles {
+		if profiles[i].Threads == "" {
+			profiles[i].Threads = threads
+		}
+	}
+}
+
+// loadProfile loads a profile and applies the time range and thread selection requested by the tool call
+func loadProfile(path string, req mcp.CallToolRequest) (*parser.Profile, error) {
+	profile, _, err := loadProfileWithType(path, req)
+	return profile, err
+}
+
+// loadProfileWithType is loadProfile that also returns the detected browser type
+func loadProfileWithType(path string, req mcp.CallToolRequest) (*parser.Profile, parser.BrowserType, error) {
+	profile, bt, err := parser.LoadProfileAuto(path)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to load profile: %w", err)
+	}
+	profile, err = applyScope(profile, req)
+	if err != nil {
+		return nil, "", err
+	}
+	return profile, bt, nil
+}
+
+// applyScope applies both the range and thread selection parameters
+func applyScope(profile *parser.Profile, req mcp.CallToolRequest) (*parser.Profile, error) {
+	profile, err := applyRange(profile, req)
+	if err != nil {
+		return nil, err
+	}
+	return applyThreadSelector(profile, req)
+}
+
+// applyThreadSelector restricts the profile to the threads given by the threads parameter
+func applyThreadSelector(profile *parser.Profile, req mcp.CallToolRequest) (*parser.Profile, error) {
+	expr := req.GetString("threads", "")
+	if expr == "" {
+		return profile, nil
+	}
+
+	sel, err := parser.ParseThreadSelector(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	selected := profile.SelectThreads(sel)
+	if len(selected.Threads) == 0 {
+		return nil, fmt.Errorf("no threads match selector %q", expr)
+	}
+	return selected, nil
+}
+
+// applyRange restricts the profile to the range given by the range or range_from_markers parameters
+func applyRange(profile *parser.Profile, req mcp.CallToolRequest) (*parser.Profile, error) {
+	rangeExpr := req.GetString("range", "")
+	markerExpr := req.GetString("range_from_markers", "")
+
+	if rangeExpr != "" && markerExpr != "" {
+		return nil, fmt.Errorf("range and range_from_markers cannot be used together")
+	}
+
+	var r parser.TimeRange
+	switch {
+	case rangeExpr != "":
+		var err error
+		if r, err = parser.ParseTimeRange(rangeExpr); err != nil {
+			return nil, err
+		}
+	case markerExpr != "":
+		startPattern, endPattern, err := analyzer.ParseMarkerRange(markerExpr)
+		if err != nil {
+			return nil, err
+		}
+		if r, err = analyzer.ResolveMarkerRange(profile, startPattern, endPattern); err != nil {
+			return nil, fmt.Errorf("failed to resolve marker range: %w", err)
+		}
+	default:
+		return profile, nil
+	}
+
+	sliced := profile.Slice(r)
+	if sliced.Duration() <= 0 {
+		return nil, fmt.Errorf("range %s does not overlap the profile (%s)", r,
+			parser.TimeRange{StartMs: profile.Meta.ProfilingStartTime, EndMs: profile.Meta.ProfilingEndTime})
+	}
+	return sliced, nil
+}
+
 // splitAndTrim splits a string by comma and trims whitespace
 func splitAndTrim(s string) []string {
 	var result []string
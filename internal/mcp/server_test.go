@@ -5,8 +5,10 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/CedricHerzog/perfowl/internal/analyzer"
 	"github.com/CedricHerzog/perfowl/internal/format/toon"
 	"github.com/CedricHerzog/perfowl/internal/parser"
+	"github.com/CedricHerzog/perfowl/internal/report"
 	"github.com/CedricHerzog/perfowl/internal/testutil"
 	"github.com/mark3labs/mcp-go/mcp"
 )
@@ -19,7 +21,7 @@ func TestTOONOutputFormat(t *testing.T) {
 	}
 
 	// Build summary using the same function as the MCP handler
-	summary := buildSummary(profile)
+	summary := report.BuildSummary(profile, parser.BrowserFirefox)
 
 	// Encode to TOON
 	output, err := toon.Encode(summary)
@@ -105,7 +107,7 @@ func TestBuildSummary_BasicFields(t *testing.T) {
 		WithThread(testutil.NewThreadBuilder("DOM Worker").Build()).
 		Build()
 
-	summary := buildSummary(profile)
+	summary := report.BuildSummary(profile, parser.BrowserFirefox)
 
 	if summary.DurationSeconds != 5 {
 		t.Errorf("DurationSeconds = %v, want 5", summary.DurationSeconds)
@@ -133,7 +135,7 @@ func TestBuildSummary_BasicFields(t *testing.T) {
 func TestBuildSummary_WithExtensions(t *testing.T) {
 	profile := testutil.ProfileWithExtensions()
 
-	summary := buildSummary(profile)
+	summary := report.BuildSummary(profile, parser.BrowserFirefox)
 
 	if summary.ExtensionCount != 2 {
 		t.Errorf("ExtensionCount = %v, want 2", summary.ExtensionCount)
@@ -158,7 +160,7 @@ func TestBuildSummary_Markers(t *testing.T) {
 			Build()).
 		Build()
 
-	summary := buildSummary(profile)
+	summary := report.BuildSummary(profile, parser.BrowserFirefox)
 
 	if summary.TotalMarkers != 2 {
 		t.Errorf("TotalMarkers = %v, want 2", summary.TotalMarkers)
@@ -179,7 +181,7 @@ func TestBuildSummary_Samples(t *testing.T) {
 			Build()).
 		Build()
 
-	summary := buildSummary(profile)
+	summary := report.BuildSummary(profile, parser.BrowserFirefox)
 
 	if summary.TotalSamples != 100 {
 		t.Errorf("TotalSamples = %v, want 100", summary.TotalSamples)
@@ -242,7 +244,7 @@ func TestSplitAndTrim_EmptyParts(t *testing.T) {
 }
 
 func TestProfileSummary_Fields(t *testing.T) {
-	summary := ProfileSummary{
+	summary := report.Summary{
 		DurationSeconds: 10.5,
 		Platform:        "Linux",
 		OSCPU:           "x86_64",
@@ -263,7 +265,7 @@ func TestProfileSummary_Fields(t *testing.T) {
 	// Verify struct can be encoded
 	output, err := toon.Encode(summary)
 	if err != nil {
-		t.Fatalf("failed to encode ProfileSummary: %v", err)
+		t.Fatalf("failed to encode Summary: %v", err)
 	}
 
 	if !strings.Contains(output, "duration_seconds:") {
@@ -277,7 +279,7 @@ func TestProfileSummary_Fields(t *testing.T) {
 func TestBuildSummary_EmptyProfile(t *testing.T) {
 	profile := testutil.MinimalProfile()
 
-	summary := buildSummary(profile)
+	summary := report.BuildSummary(profile, parser.BrowserFirefox)
 
 	if summary.ThreadCount != 0 {
 		t.Errorf("ThreadCount = %v, want 0", summary.ThreadCount)
@@ -310,6 +312,12 @@ func TestHandleGetSummary_Success(t *testing.T) {
 	if result == nil {
 		t.Fatal("expected non-nil result")
 	}
+
+	// The summary comes from the report package shared with the CLI
+	text := result.Content[0].(mcp.TextContent).Text
+	if !strings.Contains(text, "browser_type: firefox") {
+		t.Errorf("expected the detected browser in the summary, got:\n%s", text)
+	}
 }
 
 func TestHandleGetSummary_MissingPath(t *testing.T) {
@@ -992,3 +1000,601 @@ func mockRequest(args map[string]any) mcp.CallToolRequest {
 		},
 	}
 }
+
+func TestHandleGetCallTree_WithRange(t *testing.T) {
+	profile := testutil.ProfileWithCallTree()
+	path := testutil.TempProfileFile(t, profile)
+
+	server := NewServer()
+	req := mockRequest(map[string]any{
+		"path":  path,
+		"range": "500ms-1000ms",
+	})
+
+	result, err := server.handleGetCallTree(context.TODO(), req)
+
+	if err != nil {
+		t.Fatalf("handleGetCallTree error: %v", err)
+	}
+	if result == nil {
+		t.Fatal("expected non-nil result")
+	}
+}
+
+func TestHandleMeasureOperation_WithMarkerRange(t *testing.T) {
+	profile := testutil.ProfileWithDelimiters()
+	path := testutil.TempProfileFile(t, profile)
+
+	server := NewServer()
+	req := mockRequest(map[string]any{
+		"path":               path,
+		"range_from_markers": "DOMEvent:click..Paint",
+		"start_pattern":      "DOMEvent:click",
+		"end_pattern":        "Styles",
+	})
+
+	result, err := server.handleMeasureOperation(context.TODO(), req)
+
+	if err != nil {
+		t.Fatalf("handleMeasureOperation error: %v", err)
+	}
+	if result == nil {
+		t.Fatal("expected non-nil result")
+	}
+}
+
+func TestApplyRange_InvalidRange(t *testing.T) {
+	profile := testutil.ProfileWithDelimiters()
+
+	_, err := applyRange(profile, mockRequest(map[string]any{"range": "bogus"}))
+	if err == nil {
+		t.Error("expected error for invalid range")
+	}
+
+	_, err = applyRange(profile, mockRequest(map[string]any{"range_from_markers": "Nothing..Paint"}))
+	if err == nil {
+		t.Error("expected error for unmatched marker range")
+	}
+
+	_, err = applyRange(profile, mockRequest(map[string]any{"range": "90000ms-95000ms"}))
+	testutil.AssertErrorContains(t, err, "does not overlap the profile")
+}
+
+func TestHandleAnalyzeWorkers_WithThreads(t *testing.T) {
+	profile := testutil.ProfileWithWorkers(2)
+	path := testutil.TempProfileFile(t, profile)
+
+	server := NewServer()
+	req := mockRequest(map[string]any{
+		"path":    path,
+		"threads": "DOM Worker",
+	})
+
+	result, err := server.handleAnalyzeWorkers(context.TODO(), req)
+
+	if err != nil {
+		t.Fatalf("handleAnalyzeWorkers error: %v", err)
+	}
+	if result == nil {
+		t.Fatal("expected non-nil result")
+	}
+}
+
+func TestApplyThreadSelector(t *testing.T) {
+	profile := testutil.ProfileWithWorkers(2)
+
+	result, err := applyThreadSelector(profile, mockRequest(map[string]any{"threads": "main"}))
+	if err != nil {
+		t.Fatalf("applyThreadSelector error: %v", err)
+	}
+	if len(result.Threads) != 1 {
+		t.Errorf("len(Threads) = %d, want 1", len(result.Threads))
+	}
+
+	if _, err := applyThreadSelector(profile, mockRequest(map[string]any{"threads": "Compositor"})); err == nil {
+		t.Error("expected error when no threads match")
+	}
+}
+
+func TestApplyDefaultThreads(t *testing.T) {
+	profiles := []analyzer.ProfileEntry{
+		{Path: "a.json"},
+		{Path: "b.json", Threads: "GeckoMain"},
+	}
+
+	applyDefaultThreads(profiles, mockRequest(map[string]any{"threads": "DOM Worker*"}))
+
+	if profiles[0].Threads != "DOM Worker*" {
+		t.Errorf("profiles[0].Threads = %q, want %q", profiles[0].Threads, "DOM Worker*")
+	}
+	if profiles[1].Threads != "GeckoMain" {
+		t.Errorf("profiles[1].Threads = %q, want %q", profiles[1].Threads, "GeckoMain")
+	}
+}
+
+func TestHandleAnalyzeJITTiers_Success(t *testing.T) {
+	profile := testutil.ProfileWithJITTiers()
+	path := testutil.TempProfileFile(t, profile)
+
+	server := NewServer()
+	req := mockRequest(map[string]any{
+		"path":  path,
+		"limit": float64(5),
+	})
+
+	result, err := server.handleAnalyzeJITTiers(context.TODO(), req)
+
+	if err != nil {
+		t.Fatalf("handleAnalyzeJITTiers error: %v", err)
+	}
+	if result == nil {
+		t.Fatal("expected non-nil result")
+	}
+}
+
+func TestHandleAnalyzeJITTiers_MissingPath(t *testing.T) {
+	server := NewServer()
+	req := mockRequest(map[string]any{})
+
+	_, err := server.handleAnalyzeJITTiers(context.TODO(), req)
+
+	if err == nil {
+		t.Error("expected error for missing path")
+	}
+}
+
+func TestHandleGetSourceLines_Success(t *testing.T) {
+	path := testutil.TempProfileFile(t, testutil.ProfileWithSourceLines())
+
+	server := NewServer()
+	req := mockRequest(map[string]any{
+		"path":     path,
+		"function": "decrypt",
+		"context":  float64(1),
+		"limit":    float64(5),
+	})
+
+	result, err := server.handleGetSourceLines(context.TODO(), req)
+
+	if err != nil {
+		t.Fatalf("handleGetSourceLines error: %v", err)
+	}
+	if result == nil {
+		t.Fatal("expected non-nil result")
+	}
+}
+
+func TestHandleGetSourceLines_MissingFilter(t *testing.T) {
+	path := testutil.TempProfileFile(t, testutil.ProfileWithSourceLines())
+
+	server := NewServer()
+	req := mockRequest(map[string]any{"path": path})
+
+	_, err := server.handleGetSourceLines(context.TODO(), req)
+
+	if err == nil {
+		t.Error("expected error without function or file filter")
+	}
+}
+
+func TestHandleAnalyzeResources_Success(t *testing.T) {
+	path := testutil.TempProfileFile(t, testutil.ProfileWithResources())
+
+	server := NewServer()
+	req := mockRequest(map[string]any{
+		"path":        path,
+		"first_party": "example.com, examplecdn.net",
+		"limit":       float64(5),
+	})
+
+	result, err := server.handleAnalyzeResources(context.TODO(), req)
+
+	if err != nil {
+		t.Fatalf("handleAnalyzeResources error: %v", err)
+	}
+	if result == nil {
+		t.Fatal("expected non-nil result")
+	}
+}
+
+func TestHandleAnalyzeResources_MissingPath(t *testing.T) {
+	server := NewServer()
+	req := mockRequest(map[string]any{})
+
+	_, err := server.handleAnalyzeResources(context.TODO(), req)
+
+	if err == nil {
+		t.Error("expected error for missing path")
+	}
+}
+
+func TestHandleAnalyzeFrames_Success(t *testing.T) {
+	path := testutil.TempProfileFile(t, testutil.ProfileWithFrames())
+
+	server := NewServer()
+	req := mockRequest(map[string]any{
+		"path":  path,
+		"limit": float64(3),
+	})
+
+	result, err := server.handleAnalyzeFrames(context.TODO(), req)
+
+	if err != nil {
+		t.Fatalf("handleAnalyzeFrames error: %v", err)
+	}
+	if result == nil {
+		t.Fatal("expected non-nil result")
+	}
+}
+
+func TestHandleAnalyzeFrames_MissingPath(t *testing.T) {
+	server := NewServer()
+	req := mockRequest(map[string]any{})
+
+	_, err := server.handleAnalyzeFrames(context.TODO(), req)
+
+	if err == nil {
+		t.Error("expected error for missing path")
+	}
+}
+
+func TestHandleAnalyzeInteractions_Success(t *testing.T) {
+	path := testutil.TempProfileFile(t, testutil.ProfileWithInteractions())
+
+	server := NewServer()
+	req := mockRequest(map[string]any{
+		"path":  path,
+		"limit": float64(2),
+	})
+
+	result, err := server.handleAnalyzeInteractions(context.TODO(), req)
+
+	if err != nil {
+		t.Fatalf("handleAnalyzeInteractions error: %v", err)
+	}
+	if result == nil {
+		t.Fatal("expected non-nil result")
+	}
+}
+
+func TestHandleAnalyzeInteractions_MissingPath(t *testing.T) {
+	server := NewServer()
+	req := mockRequest(map[string]any{})
+
+	_, err := server.handleAnalyzeInteractions(context.TODO(), req)
+
+	if err == nil {
+		t.Error("expected error for missing path")
+	}
+}
+
+func TestHandleAnalyzeLayoutShifts_Success(t *testing.T) {
+	path := testutil.TempProfileFile(t, testutil.ProfileWithLayoutShifts())
+
+	server := NewServer()
+	req := mockRequest(map[string]any{
+		"path":  path,
+		"limit": float64(3),
+	})
+
+	result, err := server.handleAnalyzeLayoutShifts(context.TODO(), req)
+
+	if err != nil {
+		t.Fatalf("handleAnalyzeLayoutShifts error: %v", err)
+	}
+	if result == nil {
+		t.Fatal("expected non-nil result")
+	}
+}
+
+func TestHandleAnalyzeLayoutShifts_MissingPath(t *testing.T) {
+	server := NewServer()
+	req := mockRequest(map[string]any{})
+
+	_, err := server.handleAnalyzeLayoutShifts(context.TODO(), req)
+
+	if err == nil {
+		t.Error("expected error for missing path")
+	}
+}
+
+func TestHandleAnalyzeNetwork_Success(t *testing.T) {
+	path := testutil.TempProfileFile(t, testutil.ProfileWithNetwork())
+
+	server := NewServer()
+	req := mockRequest(map[string]any{
+		"path":  path,
+		"limit": float64(3),
+	})
+
+	result, err := server.handleAnalyzeNetwork(context.TODO(), req)
+
+	if err != nil {
+		t.Fatalf("handleAnalyzeNetwork error: %v", err)
+	}
+	if result == nil {
+		t.Fatal("expected non-nil result")
+	}
+}
+
+func TestHandleAnalyzeNetwork_MissingPath(t *testing.T) {
+	server := NewServer()
+	req := mockRequest(map[string]any{})
+
+	_, err := server.handleAnalyzeNetwork(context.TODO(), req)
+
+	if err == nil {
+		t.Error("expected error for missing path")
+	}
+}
+
+func TestHandleAnalyzeScriptCosts_Success(t *testing.T) {
+	path := testutil.TempProfileFile(t, testutil.ProfileWithScriptCosts())
+
+	server := NewServer()
+	req := mockRequest(map[string]any{
+		"path":  path,
+		"limit": float64(5),
+	})
+
+	result, err := server.handleAnalyzeScriptCosts(context.TODO(), req)
+
+	if err != nil {
+		t.Fatalf("handleAnalyzeScriptCosts error: %v", err)
+	}
+	if result == nil {
+		t.Fatal("expected non-nil result")
+	}
+}
+
+func TestHandleAnalyzeScriptCosts_MissingPath(t *testing.T) {
+	server := NewServer()
+	req := mockRequest(map[string]any{})
+
+	_, err := server.handleAnalyzeScriptCosts(context.TODO(), req)
+
+	if err == nil {
+		t.Error("expected error for missing path")
+	}
+}
+
+func TestHandleAnalyzeGC_Success(t *testing.T) {
+	path := testutil.TempProfileFile(t, testutil.ProfileWithGCPayloads())
+
+	server := NewServer()
+	req := mockRequest(map[string]any{
+		"path":  path,
+		"limit": float64(5),
+	})
+
+	result, err := server.handleAnalyzeGC(context.TODO(), req)
+
+	if err != nil {
+		t.Fatalf("handleAnalyzeGC error: %v", err)
+	}
+	if result == nil {
+		t.Fatal("expected non-nil result")
+	}
+}
+
+func TestHandleAnalyzeGC_MissingPath(t *testing.T) {
+	server := NewServer()
+	req := mockRequest(map[string]any{})
+
+	_, err := server.handleAnalyzeGC(context.TODO(), req)
+
+	if err == nil {
+		t.Error("expected error for missing path")
+	}
+}
+
+func TestHandleAnalyzeStyles_Success(t *testing.T) {
+	path := testutil.TempProfileFile(t, testutil.ProfileWithStyles())
+
+	server := NewServer()
+	req := mockRequest(map[string]any{
+		"path":  path,
+		"limit": float64(5),
+	})
+
+	result, err := server.handleAnalyzeStyles(context.TODO(), req)
+
+	if err != nil {
+		t.Fatalf("handleAnalyzeStyles error: %v", err)
+	}
+	if result == nil {
+		t.Fatal("expected non-nil result")
+	}
+}
+
+func TestHandleAnalyzeStyles_MissingPath(t *testing.T) {
+	server := NewServer()
+	req := mockRequest(map[string]any{})
+
+	_, err := server.handleAnalyzeStyles(context.TODO(), req)
+
+	if err == nil {
+		t.Error("expected error for missing path")
+	}
+}
+
+func TestHandleAnalyzeConcurrency_Success(t *testing.T) {
+	path := testutil.TempProfileFile(t, testutil.ProfileWithConcurrency())
+
+	server := NewServer()
+	req := mockRequest(map[string]any{
+		"path":             path,
+		"bucket_ms":        float64(5),
+		"include_timeline": true,
+	})
+
+	result, err := server.handleAnalyzeConcurrency(context.TODO(), req)
+
+	if err != nil {
+		t.Fatalf("handleAnalyzeConcurrency error: %v", err)
+	}
+	if result == nil {
+		t.Fatal("expected non-nil result")
+	}
+}
+
+func TestHandleAnalyzeConcurrency_MissingPath(t *testing.T) {
+	server := NewServer()
+	req := mockRequest(map[string]any{})
+
+	_, err := server.handleAnalyzeConcurrency(context.TODO(), req)
+
+	if err == nil {
+		t.Error("expected error for missing path")
+	}
+}
+
+func TestHandleAnalyzeCriticalPath_Success(t *testing.T) {
+	path := testutil.TempProfileFile(t, testutil.ProfileWithCriticalPath())
+
+	server := NewServer()
+	req := mockRequest(map[string]any{
+		"path":          path,
+		"start_pattern": "UserTiming:decrypt-start",
+		"end_pattern":   "UserTiming:decrypt-end",
+	})
+
+	result, err := server.handleAnalyzeCriticalPath(context.TODO(), req)
+
+	if err != nil {
+		t.Fatalf("handleAnalyzeCriticalPath error: %v", err)
+	}
+	if result == nil {
+		t.Fatal("expected non-nil result")
+	}
+}
+
+func TestHandleAnalyzeCriticalPath_MissingParams(t *testing.T) {
+	path := testutil.TempProfileFile(t, testutil.ProfileWithCriticalPath())
+	server := NewServer()
+
+	for _, args := range []map[string]any{
+		{},
+		{"path": path},
+		{"path": path, "start_pattern": "UserTiming:decrypt-start"},
+		{"path": path, "start_pattern": "UserTiming:nope", "end_pattern": "UserTiming:decrypt-end"},
+	} {
+		if _, err := server.handleAnalyzeCriticalPath(context.TODO(), mockRequest(args)); err == nil {
+			t.Errorf("expected error for arguments %v", args)
+		}
+	}
+}
+
+func TestHandleAnalyzeMessages_Success(t *testing.T) {
+	path := testutil.TempProfileFile(t, testutil.ProfileWithMessageFlow())
+	server := NewServer()
+
+	for _, graph := range []string{"", "dot", "mermaid"} {
+		req := mockRequest(map[string]any{
+			"path":  path,
+			"limit": float64(5),
+			"graph": graph,
+		})
+
+		result, err := server.handleAnalyzeMessages(context.TODO(), req)
+
+		if err != nil {
+			t.Fatalf("handleAnalyzeMessages graph %q error: %v", graph, err)
+		}
+		if result == nil {
+			t.Fatal("expected non-nil result")
+		}
+	}
+}
+
+func TestHandleAnalyzeMessages_Errors(t *testing.T) {
+	path := testutil.TempProfileFile(t, testutil.ProfileWithMessageFlow())
+	server := NewServer()
+
+	for _, args := range []map[string]any{
+		{},
+		{"path": path, "graph": "png"},
+	} {
+		if _, err := server.handleAnalyzeMessages(context.TODO(), mockRequest(args)); err == nil {
+			t.Errorf("expected error for arguments %v", args)
+		}
+	}
+}
+
+func TestHandleCompareProfileSets_Success(t *testing.T) {
+	baselines := make([]any, 0, 4)
+	candidates := make([]any, 0, 4)
+	for i := 0; i < 4; i++ {
+		baselines = append(baselines, testutil.TempProfileFile(t, testutil.ProfileRun(1000+float64(i), 30)))
+		candidates = append(candidates, testutil.TempProfileFile(t, testutil.ProfileRun(1200+float64(i), 60)))
+	}
+
+	server := NewServer()
+	req := mockRequest(map[string]any{
+		"baselines":  baselines,
+		"candidates": candidates,
+		"alpha":      0.05,
+		"limit":      5.0,
+	})
+
+	result, err := server.handleCompareProfileSets(context.TODO(), req)
+	if err != nil {
+		t.Fatalf("handleCompareProfileSets error: %v", err)
+	}
+	if result == nil {
+		t.Fatal("expected non-nil result")
+	}
+	text := result.Content[0].(mcp.TextContent).Text
+	testutil.AssertStringContains(t, text, "regressed")
+	testutil.AssertStringContains(t, text, "computeHash")
+}
+
+func TestHandleCompareProfileSets_Errors(t *testing.T) {
+	path := testutil.TempProfileFile(t, testutil.MinimalProfile())
+	server := NewServer()
+
+	for _, args := range []map[string]any{
+		{},
+		{"baselines": []any{path}},
+		{"baselines": []any{}, "candidates": []any{path}},
+		{"baselines": []any{path}, "candidates": []any{path}, "alpha": 2.0},
+		{"baselines": []any{path}, "candidates": []any{"/nonexistent/profile.json"}},
+	} {
+		if _, err := server.handleCompareProfileSets(context.TODO(), mockRequest(args)); err == nil {
+			t.Errorf("expected error for arguments %v", args)
+		}
+	}
+}
+
+func TestHandleCompareJITTiers_Success(t *testing.T) {
+	path1 := testutil.TempProfileFile(t, testutil.ProfileWithJITTiers())
+	path2 := testutil.TempProfileFile(t, testutil.ProfileWithJITTiers())
+
+	server := NewServer()
+	req := mockRequest(map[string]any{
+		"baseline":   path1,
+		"comparison": path2,
+	})
+
+	result, err := server.handleCompareJITTiers(context.TODO(), req)
+
+	if err != nil {
+		t.Fatalf("handleCompareJITTiers error: %v", err)
+	}
+	if result == nil {
+		t.Fatal("expected non-nil result")
+	}
+}
+
+func TestHandleCompareJITTiers_MissingComparison(t *testing.T) {
+	server := NewServer()
+	req := mockRequest(map[string]any{
+		"baseline": "/some/path.json",
+	})
+
+	_, err := server.handleCompareJITTiers(context.TODO(), req)
+
+	if err == nil {
+		t.Error("expected error for missing comparison")
+	}
+}
@@ -59,6 +59,7 @@ const (
 	PhaseAsyncEnd2  = "e" // Async nestable end
 	PhaseAsyncStep  = "n" // Async nestable step
 	PhaseFlowStart  = "s" // Flow event start
+	PhaseFlowStep   = "t" // Flow event step
 	PhaseFlowEnd    = "f" // Flow event end
 	PhaseSample     = "P" // Sample event (V8 profiler)
 	PhaseObject     = "O" // Object snapshot
@@ -72,6 +73,7 @@ type V8CPUProfile struct {
 	Nodes      []V8Node `json:"nodes"`
 	Samples    []int    `json:"samples"`
 	TimeDeltas []int    `json:"timeDeltas,omitempty"`
+	Lines      []int    `json:"lines,omitempty"` // 1-based source line of each sample, when recorded
 	StartTime  int64    `json:"startTime,omitempty"`
 	EndTime    int64    `json:"endTime,omitempty"`
 }
@@ -104,6 +106,7 @@ type ProfileChunkArgs struct {
 type ProfileChunkData struct {
 	CPUProfile V8CPUProfile `json:"cpuProfile"`
 	TimeDeltas []int        `json:"timeDeltas,omitempty"`
+	Lines      []int        `json:"lines,omitempty"`
 }
 
 // ThreadNameArgs represents args for thread_name metadata events
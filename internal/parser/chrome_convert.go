@@ -226,6 +226,8 @@ func (c *chromeConverter) processEvents() {
 			c.handleInstantEvent(&evt)
 		case PhaseMark: // R - mark event
 			c.handleMarkEvent(&evt)
+		case PhaseFlowStart, PhaseFlowStep, PhaseFlowEnd: // s/t/f - cross-thread flow
+			c.handleFlowEvent(&evt)
 		}
 	}
 }
@@ -275,6 +277,35 @@ func (c *chromeConverter) handleInstantEvent(evt *ChromeEvent) {
 	}
 }
 
+// FlowMarkerName names the instant markers created from Chrome flow events
+const FlowMarkerName = "Flow"
+
+// handleFlowEvent keeps a flow event as an instant "Flow" marker whose data carries
+// the flow id and phase, so the threads a flow connects can be paired up later
+func (c *chromeConverter) handleFlowEvent(evt *ChromeEvent) {
+	if evt.ID == nil {
+		return
+	}
+	tb := c.getOrCreateThread(evt.Pid, evt.Tid)
+
+	data, err := json.Marshal(map[string]interface{}{
+		"type":  FlowMarkerName,
+		"id":    fmt.Sprint(evt.ID),
+		"phase": evt.Ph,
+		"name":  evt.Name,
+	})
+	if err != nil {
+		return
+	}
+
+	tb.markerStartTimes = append(tb.markerStartTimes, (evt.Ts-c.minTime)/1000.0)
+	tb.markerEndTimes = append(tb.markerEndTimes, nil)
+	tb.markerNames = append(tb.markerNames, c.internString(FlowMarkerName))
+	tb.markerCategories = append(tb.markerCategories, c.mapCategory(evt.Cat))
+	tb.markerPhases = append(tb.markerPhases, 0) // Instant
+	tb.markerData = append(tb.markerData, data)
+}
+
 func (c *chromeConverter) handleMarkEvent(evt *ChromeEvent) {
 	// Mark events are similar to instant events
 	c.handleInstantEvent(evt)
@@ -359,12 +390,13 @@ func (c *chromeConverter) processCPUProfile(tb *threadBuilder, cp *V8CPUProfile,
 
 	// Build node ID to stack index mapping
 	nodeToStack := make(map[int]int)
+	nodeFrames := make(map[int]*V8CallFrame)
 
 	// Process nodes in order (parent before children due to tree structure)
-	for _, node := range cp.Nodes {
+	for i, node := range cp.Nodes {
 		funcIdx := c.getOrCreateFunc(tb, &node.CallFrame)
 		catIdx := c.getCategoryForCallFrame(&node.CallFrame)
-		frameIdx := c.getOrCreateFrame(tb, funcIdx, catIdx)
+		frameIdx := c.getOrCreateFrame(tb, funcIdx, catIdx, node.CallFrame.CodeType, 0)
 
 		// Find parent stack index
 		prefixIdx := -1
@@ -376,6 +408,13 @@ func (c *chromeConverter) processCPUProfile(tb *threadBuilder, cp *V8CPUProfile,
 
 		stackIdx := c.getOrCreateStack(tb, frameIdx, prefixIdx, catIdx)
 		nodeToStack[node.ID] = stackIdx
+		nodeFrames[node.ID] = &cp.Nodes[i].CallFrame
+	}
+
+	// Per-sample source lines, when the trace recorded them
+	lines := data.Lines
+	if len(lines) == 0 {
+		lines = cp.Lines
 	}
 
 	// Process samples
@@ -391,6 +430,15 @@ func (c *chromeConverter) processCPUProfile(tb *threadBuilder, cp *V8CPUProfile,
 			stackIdx = -1
 		}
 
+		// Give the leaf frame the sampled line so time can be attributed per line
+		if ok && i < len(lines) && lines[i] > 0 {
+			cf := nodeFrames[nodeID]
+			funcIdx := c.getOrCreateFunc(tb, cf)
+			catIdx := c.getCategoryForCallFrame(cf)
+			frameIdx := c.getOrCreateFrame(tb, funcIdx, catIdx, cf.CodeType, lines[i])
+			stackIdx = c.getOrCreateStack(tb, frameIdx, tb.stackPrefixes[stackIdx], catIdx)
+		}
+
 		var delta float64
 		if i < len(timeDeltas) {
 			delta = float64(timeDeltas[i]) / 1000.0 // Convert to ms
@@ -427,14 +475,15 @@ func (c *chromeConverter) getOrCreateFunc(tb *threadBuilder, cf *V8CallFrame) in
 	tb.funcRelevant = append(tb.funcRelevant, true)
 	tb.funcResources = append(tb.funcResources, -1)
 	tb.funcFileNames = append(tb.funcFileNames, fileIdx)
-	tb.funcLineNumbers = append(tb.funcLineNumbers, cf.LineNumber)
-	tb.funcColNumbers = append(tb.funcColNumbers, cf.ColumnNumber)
+	// V8 call frame positions are 0-based, Firefox's are 1-based
+	tb.funcLineNumbers = append(tb.funcLineNumbers, cf.LineNumber+1)
+	tb.funcColNumbers = append(tb.funcColNumbers, cf.ColumnNumber+1)
 
 	return idx
 }
 
-func (c *chromeConverter) getOrCreateFrame(tb *threadBuilder, funcIdx, catIdx int) int {
-	key := fmt.Sprintf("%d|%d", funcIdx, catIdx)
+func (c *chromeConverter) getOrCreateFrame(tb *threadBuilder, funcIdx, catIdx int, codeType string, line int) int {
+	key := fmt.Sprintf("%d|%d|%s|%d", funcIdx, catIdx, codeType, line)
 
 	if idx, ok := tb.frameMap[key]; ok {
 		return idx
@@ -450,8 +499,17 @@ func (c *chromeConverter) getOrCreateFrame(tb *threadBuilder, funcIdx, catIdx in
 	tb.frameFuncs = append(tb.frameFuncs, funcIdx)
 	tb.frameSymbols = append(tb.frameSymbols, nil)
 	tb.frameWindowIDs = append(tb.frameWindowIDs, nil)
-	tb.frameImpls = append(tb.frameImpls, nil)
-	tb.frameLines = append(tb.frameLines, nil)
+	// Keep V8's codeType as the frame implementation, like Firefox's JIT tier
+	if codeType != "" {
+		tb.frameImpls = append(tb.frameImpls, c.internString(codeType))
+	} else {
+		tb.frameImpls = append(tb.frameImpls, nil)
+	}
+	if line > 0 {
+		tb.frameLines = append(tb.frameLines, line)
+	} else {
+		tb.frameLines = append(tb.frameLines, nil)
+	}
 	tb.frameCols = append(tb.frameCols, nil)
 
 	return idx
@@ -491,12 +549,20 @@ func (c *chromeConverter) getCategoryForCallFrame(cf *V8CallFrame) int {
 	return c.categoryMap["JavaScript"]
 }
 
-// extractExtensionID extracts and tracks extension IDs from chrome-extension:// URLs
+// extractExtensionID tracks extension IDs from chrome-extension:// URLs
 func (c *chromeConverter) extractExtensionID(url string) {
+	if extID := ExtractExtensionID(url); extID != "" {
+		c.extensions[extID] = true
+	}
+}
+
+// ExtractExtensionID returns the extension ID of a chrome-extension:// URL, or an
+// empty string if the URL does not start with a valid Chrome extension ID
+func ExtractExtensionID(url string) string {
 	// URL format: chrome-extension://EXTENSION_ID/path/to/file.js
 	const prefix = "chrome-extension://"
 	if !strings.HasPrefix(url, prefix) {
-		return
+		return ""
 	}
 	rest := url[len(prefix):]
 	// Find the end of the extension ID (next slash or end of string)
@@ -507,9 +573,10 @@ func (c *chromeConverter) extractExtensionID(url string) {
 	} else {
 		extID = rest
 	}
-	if extID != "" && len(extID) == 32 { // Chrome extension IDs are 32 chars
-		c.extensions[extID] = true
+	if len(extID) != 32 { // Chrome extension IDs are 32 chars
+		return ""
 	}
+	return extID
 }
 
 // buildExtensions creates the Extensions struct from discovered extension IDs
@@ -271,6 +271,41 @@ func TestConvertChromeToProfile_InstantEvents(t *testing.T) {
 	}
 }
 
+func TestConvertChromeToProfile_FlowEvents(t *testing.T) {
+	chrome := &ChromeProfile{
+		TraceEvents: []ChromeEvent{
+			{Name: "PostMessage", Cat: "devtools.timeline", Ph: "s", Ts: 1000000, Pid: 1, Tid: 1, ID: float64(7)},
+			{Name: "PostMessage", Cat: "devtools.timeline", Ph: "f", Ts: 1002000, Pid: 1, Tid: 2, ID: float64(7), Bp: "e"},
+			{Name: "Unbound", Cat: "devtools.timeline", Ph: "s", Ts: 1003000, Pid: 1, Tid: 1},
+		},
+	}
+
+	profile, err := ConvertChromeToProfile(chrome)
+	if err != nil {
+		t.Fatalf("ConvertChromeToProfile() error = %v", err)
+	}
+
+	var flows []ParsedMarker
+	for i := range profile.Threads {
+		for _, m := range ExtractMarkers(&profile.Threads[i], profile.Meta.Categories) {
+			if m.Name == FlowMarkerName {
+				flows = append(flows, m)
+			}
+		}
+	}
+	if len(flows) != 2 {
+		t.Fatalf("expected 2 flow markers (events without an id are dropped), got %d", len(flows))
+	}
+	for _, m := range flows {
+		if m.Data["id"] != "7" {
+			t.Errorf("flow id = %v, want 7", m.Data["id"])
+		}
+	}
+	if flows[0].ThreadName == flows[1].ThreadName && flows[0].Data["phase"] == flows[1].Data["phase"] {
+		t.Error("expected the flow start and end on different threads")
+	}
+}
+
 func TestConvertChromeToProfile_V8CPUProfile(t *testing.T) {
 	chrome := &ChromeProfile{
 		TraceEvents: []ChromeEvent{
@@ -329,6 +364,121 @@ func TestConvertChromeToProfile_V8CPUProfile(t *testing.T) {
 	}
 }
 
+func TestConvertChromeToProfile_CodeType(t *testing.T) {
+	chrome := &ChromeProfile{
+		TraceEvents: []ChromeEvent{
+			{
+				Name: "ProfileChunk",
+				Cat:  "disabled-by-default-v8.cpu_profiler",
+				Ph:   "P",
+				Ts:   1000000,
+				Pid:  1,
+				Tid:  1,
+				Args: json.RawMessage(`{
+					"data": {
+						"cpuProfile": {
+							"nodes": [
+								{"id": 1, "callFrame": {"functionName": "(root)", "scriptId": 0, "url": "", "codeType": "other"}},
+								{"id": 2, "callFrame": {"functionName": "main", "scriptId": 1, "url": "file://test.js", "codeType": "JS"}, "parent": 1},
+								{"id": 3, "callFrame": {"functionName": "helper", "scriptId": 1, "url": "file://test.js"}, "parent": 2}
+							],
+							"samples": [1, 2, 3]
+						},
+						"timeDeltas": [1000, 1000, 1000]
+					}
+				}`),
+			},
+		},
+	}
+
+	profile, err := ConvertChromeToProfile(chrome)
+	if err != nil {
+		t.Fatalf("ConvertChromeToProfile() error = %v", err)
+	}
+
+	thread := &profile.Threads[0]
+	impls := make(map[string]bool)
+	for _, impl := range thread.FrameTable.Implementation {
+		if impl == nil {
+			impls[""] = true
+			continue
+		}
+		idx, ok := impl.(int)
+		if !ok || idx < 0 || idx >= len(thread.StringArray) {
+			t.Fatalf("unexpected implementation value %v", impl)
+		}
+		impls[thread.StringArray[idx]] = true
+	}
+
+	for _, want := range []string{"other", "JS", ""} {
+		if !impls[want] {
+			t.Errorf("expected implementation %q in frame table, got %v", want, impls)
+		}
+	}
+}
+
+func TestConvertChromeToProfile_Lines(t *testing.T) {
+	chrome := &ChromeProfile{
+		TraceEvents: []ChromeEvent{
+			{
+				Name: "ProfileChunk",
+				Cat:  "disabled-by-default-v8.cpu_profiler",
+				Ph:   "P",
+				Ts:   1000000,
+				Pid:  1,
+				Tid:  1,
+				Args: json.RawMessage(`{
+					"data": {
+						"cpuProfile": {
+							"nodes": [
+								{"id": 1, "callFrame": {"functionName": "(root)", "scriptId": 0, "url": ""}},
+								{"id": 2, "callFrame": {"functionName": "main", "scriptId": 1, "url": "file://test.js", "lineNumber": 9, "columnNumber": 4}, "parent": 1}
+							],
+							"samples": [2, 2, 2],
+							"lines": [12, 13, 0]
+						},
+						"timeDeltas": [1000, 1000, 1000]
+					}
+				}`),
+			},
+		},
+	}
+
+	profile, err := ConvertChromeToProfile(chrome)
+	if err != nil {
+		t.Fatalf("ConvertChromeToProfile() error = %v", err)
+	}
+
+	thread := &profile.Threads[0]
+
+	// V8 positions are 0-based, the profile uses 1-based lines
+	mainFunc := -1
+	for i := 0; i < thread.FuncTable.Length; i++ {
+		if thread.StringArray[thread.FuncTable.Name[i]] == "main" {
+			mainFunc = i
+		}
+	}
+	if mainFunc < 0 {
+		t.Fatal("expected main in function table")
+	}
+	if thread.FuncTable.LineNumber[mainFunc] != 10 || thread.FuncTable.ColumnNumber[mainFunc] != 5 {
+		t.Errorf("main position = %d:%d, want 10:5", thread.FuncTable.LineNumber[mainFunc], thread.FuncTable.ColumnNumber[mainFunc])
+	}
+
+	// Each sample's leaf frame carries the sampled line
+	var got []interface{}
+	for i := 0; i < thread.Samples.Length; i++ {
+		frameIdx := thread.StackTable.Frame[thread.Samples.Stack[i]]
+		got = append(got, thread.FrameTable.Line[frameIdx])
+	}
+	want := []interface{}{12, 13, nil}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("sample %d line = %v, want %v", i, got[i], want[i])
+		}
+	}
+}
+
 func TestConvertChromeToProfile_CategoryMapping(t *testing.T) {
 	chrome := &ChromeProfile{
 		TraceEvents: []ChromeEvent{
@@ -708,3 +858,23 @@ func TestProfileTargetMapping(t *testing.T) {
 		t.Error("Expected to find 'workerFunc' in worker thread's FuncTable")
 	}
 }
+
+func TestExtractExtensionID(t *testing.T) {
+	id := "abcdefghijklmnopabcdefghijklmnop"
+	tests := []struct {
+		url  string
+		want string
+	}{
+		{"chrome-extension://" + id + "/content.js", id},
+		{"chrome-extension://" + id, id},
+		{"chrome-extension://short/content.js", ""},
+		{"moz-extension://" + id + "/content.js", ""},
+		{"https://example.com/app.js", ""},
+	}
+
+	for _, tt := range tests {
+		if got := ExtractExtensionID(tt.url); got != tt.want {
+			t.Errorf("ExtractExtensionID(%q) = %q, want %q", tt.url, got, tt.want)
+		}
+	}
+}
@@ -129,6 +129,7 @@ type Samples struct {
 	Weight         []int     `json:"weight,omitempty"`
 	WeightType     string    `json:"weightType,omitempty"`
 	ThreadCPUDelta []int     `json:"threadCPUDelta,omitempty"`
+	EventDelay     []float64 `json:"eventDelay,omitempty"` // Event loop delay at each sample, in SampleUnits.EventDelay
 }
 
 // Markers contains marker data
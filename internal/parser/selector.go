@@ -0,0 +1,191 @@
+package parser
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ThreadSelector selects threads by name, process and thread identifiers.
+//
+// A selector is a comma-separated list of terms. A thread is selected when it
+// matches at least one include term (or there are none) and no exclude term.
+// Terms prefixed with "!" exclude threads. Conditions inside a term can be
+// combined with "&" and must all match.
+//
+// Supported conditions:
+//   - "GeckoMain", "name:DOM Worker*" - thread name glob (case-insensitive)
+//   - "re:^DOM Worker \d+$"           - thread name regular expression
+//   - "pid:1234", "tid:5678"          - process or thread ID
+//   - "process:tab"                   - process type (default, tab, gpu, ...)
+//   - "pname:Web Content*"            - process name glob (case-insensitive)
+//   - "main", "main:false"            - main thread flag
+//
+// Example: "GeckoMain&process:tab,!pid:1234"
+//
+// Because "," and "&" separate terms and conditions, regular expressions
+// cannot contain either character.
+type ThreadSelector struct {
+	expr    string
+	include []threadTerm
+	exclude []threadTerm
+}
+
+// threadTerm is a conjunction of conditions
+type threadTerm []func(*Thread) bool
+
+func (t threadTerm) matches(thread *Thread) bool {
+	for _, cond := range t {
+		if !cond(thread) {
+			return false
+		}
+	}
+	return true
+}
+
+// ParseThreadSelector parses a thread selector expression
+func ParseThreadSelector(expr string) (*ThreadSelector, error) {
+	sel := &ThreadSelector{expr: strings.TrimSpace(expr)}
+
+	for _, rawTerm := range strings.Split(expr, ",") {
+		rawTerm = strings.TrimSpace(rawTerm)
+		if rawTerm == "" {
+			continue
+		}
+
+		exclude := strings.HasPrefix(rawTerm, "!")
+		rawTerm = strings.TrimSpace(strings.TrimPrefix(rawTerm, "!"))
+
+		var term threadTerm
+		for _, rawCond := range strings.Split(rawTerm, "&") {
+			cond, err := parseThreadCondition(strings.TrimSpace(rawCond))
+			if err != nil {
+				return nil, fmt.Errorf("invalid thread selector %q: %w", expr, err)
+			}
+			term = append(term, cond)
+		}
+
+		if exclude {
+			sel.exclude = append(sel.exclude, term)
+		} else {
+			sel.include = append(sel.include, term)
+		}
+	}
+
+	return sel, nil
+}
+
+// parseThreadCondition parses a single "key:value" condition
+func parseThreadCondition(s string) (func(*Thread) bool, error) {
+	if s == "" {
+		return nil, fmt.Errorf("empty condition")
+	}
+
+	key, value, hasKey := strings.Cut(s, ":")
+	if !hasKey {
+		if strings.EqualFold(s, "main") {
+			return func(t *Thread) bool { return t.IsMainThread }, nil
+		}
+		key, value = "name", s
+	}
+	key = strings.ToLower(strings.TrimSpace(key))
+	value = strings.TrimSpace(value)
+
+	switch key {
+	case "name":
+		re, err := globToRegexp(value)
+		if err != nil {
+			return nil, err
+		}
+		return func(t *Thread) bool { return re.MatchString(t.Name) }, nil
+	case "re", "regex":
+		re, err := regexp.Compile(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid regular expression %q: %w", value, err)
+		}
+		return func(t *Thread) bool { return re.MatchString(t.Name) }, nil
+	case "pid":
+		return func(t *Thread) bool { return t.PID.String() == value }, nil
+	case "tid":
+		return func(t *Thread) bool { return t.TID.String() == value }, nil
+	case "process", "ptype":
+		return func(t *Thread) bool { return strings.EqualFold(t.ProcessType, value) }, nil
+	case "pname":
+		re, err := globToRegexp(value)
+		if err != nil {
+			return nil, err
+		}
+		return func(t *Thread) bool { return re.MatchString(t.ProcessName) }, nil
+	case "main":
+		isMain, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid main value %q: expected true or false", value)
+		}
+		return func(t *Thread) bool { return t.IsMainThread == isMain }, nil
+	default:
+		return nil, fmt.Errorf("unknown condition %q (expected name, re, pid, tid, process, pname or main)", key)
+	}
+}
+
+// globToRegexp converts a "*" and "?" glob into an anchored case-insensitive regexp
+func globToRegexp(glob string) (*regexp.Regexp, error) {
+	pattern := regexp.QuoteMeta(glob)
+	pattern = strings.ReplaceAll(pattern, `\*`, ".*")
+	pattern = strings.ReplaceAll(pattern, `\?`, ".")
+	return regexp.Compile("(?i)^" + pattern + "$")
+}
+
+// Matches returns true if the thread is selected. A nil selector selects every thread.
+func (s *ThreadSelector) Matches(thread *Thread) bool {
+	if s == nil {
+		return true
+	}
+
+	for _, term := range s.exclude {
+		if term.matches(thread) {
+			return false
+		}
+	}
+
+	if len(s.include) == 0 {
+		return true
+	}
+	for _, term := range s.include {
+		if term.matches(thread) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsEmpty returns true if the selector has no conditions
+func (s *ThreadSelector) IsEmpty() bool {
+	return s == nil || (len(s.include) == 0 && len(s.exclude) == 0)
+}
+
+// String returns the original selector expression
+func (s *ThreadSelector) String() string {
+	if s == nil {
+		return ""
+	}
+	return s.expr
+}
+
+// SelectThreads returns a view of the profile containing only the threads
+// matched by the selector. Thread data is shared with the original profile.
+func (p *Profile) SelectThreads(sel *ThreadSelector) *Profile {
+	if sel.IsEmpty() {
+		return p
+	}
+
+	selected := *p
+	selected.Threads = make([]Thread, 0, len(p.Threads))
+	for i := range p.Threads {
+		if sel.Matches(&p.Threads[i]) {
+			selected.Threads = append(selected.Threads, p.Threads[i])
+		}
+	}
+
+	return &selected
+}
@@ -0,0 +1,122 @@
+package parser
+
+import "testing"
+
+func selectorTestThreads() []Thread {
+	return []Thread{
+		{Name: "GeckoMain", IsMainThread: true, ProcessType: "default", ProcessName: "Parent Process", PID: "100", TID: "100"},
+		{Name: "GeckoMain", IsMainThread: true, ProcessType: "tab", ProcessName: "Web Content", PID: "200", TID: "200"},
+		{Name: "DOM Worker", ProcessType: "tab", ProcessName: "Web Content", PID: "200", TID: "201"},
+		{Name: "Renderer", ProcessType: "gpu", ProcessName: "GPU Process", PID: "300", TID: "301"},
+	}
+}
+
+func TestThreadSelector_Matches(t *testing.T) {
+	tests := []struct {
+		name string
+		expr string
+		want []string // expected TIDs
+	}{
+		{"empty selects all", "", []string{"100", "200", "201", "301"}},
+		{"exact name", "GeckoMain", []string{"100", "200"}},
+		{"name is case-insensitive", "geckomain", []string{"100", "200"}},
+		{"name glob", "name:DOM*", []string{"201"}},
+		{"regex", `re:^(Gecko|Render)`, []string{"100", "200", "301"}},
+		{"pid", "pid:200", []string{"200", "201"}},
+		{"tid", "tid:301", []string{"301"}},
+		{"process type", "process:TAB", []string{"200", "201"}},
+		{"process name glob", "pname:GPU*", []string{"301"}},
+		{"main thread", "main", []string{"100", "200"}},
+		{"not main thread", "main:false", []string{"201", "301"}},
+		{"and", "GeckoMain&process:tab", []string{"200"}},
+		{"or", "tid:100,tid:301", []string{"100", "301"}},
+		{"exclude only", "!pid:200", []string{"100", "301"}},
+		{"include and exclude", "pid:200,!main", []string{"201"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sel, err := ParseThreadSelector(tt.expr)
+			if err != nil {
+				t.Fatalf("ParseThreadSelector(%q) error: %v", tt.expr, err)
+			}
+
+			var got []string
+			threads := selectorTestThreads()
+			for i := range threads {
+				if sel.Matches(&threads[i]) {
+					got = append(got, threads[i].TID.String())
+				}
+			}
+
+			if len(got) != len(tt.want) {
+				t.Fatalf("selector %q matched %v, want %v", tt.expr, got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("selector %q matched %v, want %v", tt.expr, got, tt.want)
+					break
+				}
+			}
+		})
+	}
+}
+
+func TestParseThreadSelector_Errors(t *testing.T) {
+	tests := []string{
+		"unknown:value",
+		"re:(",
+		"main:maybe",
+		"GeckoMain&",
+	}
+
+	for _, expr := range tests {
+		if _, err := ParseThreadSelector(expr); err == nil {
+			t.Errorf("ParseThreadSelector(%q) expected error", expr)
+		}
+	}
+}
+
+func TestThreadSelector_NilAndEmpty(t *testing.T) {
+	var sel *ThreadSelector
+	thread := &Thread{Name: "GeckoMain"}
+
+	if !sel.Matches(thread) {
+		t.Error("nil selector should match every thread")
+	}
+	if !sel.IsEmpty() {
+		t.Error("nil selector should be empty")
+	}
+	if sel.String() != "" {
+		t.Errorf("nil selector String() = %q, want empty", sel.String())
+	}
+
+	sel, _ = ParseThreadSelector(" GeckoMain ")
+	if sel.IsEmpty() {
+		t.Error("expected non-empty selector")
+	}
+	if sel.String() != "GeckoMain" {
+		t.Errorf("String() = %q, want %q", sel.String(), "GeckoMain")
+	}
+}
+
+func TestProfile_SelectThreads(t *testing.T) {
+	p := &Profile{Threads: selectorTestThreads()}
+
+	sel, err := ParseThreadSelector("process:tab")
+	if err != nil {
+		t.Fatalf("ParseThreadSelector error: %v", err)
+	}
+
+	selected := p.SelectThreads(sel)
+	if len(selected.Threads) != 2 {
+		t.Fatalf("len(Threads) = %d, want 2", len(selected.Threads))
+	}
+	if len(p.Threads) != 4 {
+		t.Errorf("original profile modified: len(Threads) = %d, want 4", len(p.Threads))
+	}
+
+	if p.SelectThreads(nil) != p {
+		t.Error("empty selector should return the original profile")
+	}
+}
@@ -0,0 +1,184 @@
+package parser
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// TimeRange is a closed interval [StartMs, EndMs] in profile time (the same
+// time base used by marker and sample timestamps)
+type TimeRange struct {
+	StartMs float64 `json:"start_ms"`
+	EndMs   float64 `json:"end_ms"`
+}
+
+// DurationMs returns the length of the range in milliseconds
+func (r TimeRange) DurationMs() float64 {
+	return r.EndMs - r.StartMs
+}
+
+// Contains returns true if t falls within the range
+func (r TimeRange) Contains(t float64) bool {
+	return t >= r.StartMs && t <= r.EndMs
+}
+
+// Overlaps returns true if the interval [start, end] intersects the range.
+// Instant events (end <= start) overlap when their start is contained.
+func (r TimeRange) Overlaps(start, end float64) bool {
+	if end <= start {
+		return r.Contains(start)
+	}
+	return start <= r.EndMs && end >= r.StartMs
+}
+
+// String formats the range in the same syntax accepted by ParseTimeRange
+func (r TimeRange) String() string {
+	return fmt.Sprintf("%sms-%sms",
+		strconv.FormatFloat(r.StartMs, 'f', -1, 64),
+		strconv.FormatFloat(r.EndMs, 'f', -1, 64))
+}
+
+// ParseTimeRange parses a range like "1200ms-3400ms", "1.2s-3.4s" or "1200-3400".
+// Values without a unit are interpreted as milliseconds.
+func ParseTimeRange(s string) (TimeRange, error) {
+	s = strings.TrimSpace(s)
+	parts := strings.SplitN(s, "-", 2)
+	if len(parts) != 2 {
+		return TimeRange{}, fmt.Errorf("invalid range %q: expected format START-END (e.g. 1200ms-3400ms)", s)
+	}
+
+	start, err := parseTimeValue(parts[0])
+	if err != nil {
+		return TimeRange{}, fmt.Errorf("invalid range start %q: %w", parts[0], err)
+	}
+	end, err := parseTimeValue(parts[1])
+	if err != nil {
+		return TimeRange{}, fmt.Errorf("invalid range end %q: %w", parts[1], err)
+	}
+
+	if end <= start {
+		return TimeRange{}, fmt.Errorf("invalid range %q: end must be after start", s)
+	}
+
+	return TimeRange{StartMs: start, EndMs: end}, nil
+}
+
+// parseTimeValue parses a single time value with an optional ms/s suffix
+func parseTimeValue(s string) (float64, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	scale := 1.0
+	switch {
+	case strings.HasSuffix(s, "ms"):
+		s = strings.TrimSuffix(s, "ms")
+	case strings.HasSuffix(s, "s"):
+		s = strings.TrimSuffix(s, "s")
+		scale = 1000.0
+	}
+	v, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+	if err != nil {
+		return 0, err
+	}
+	return v * scale, nil
+}
+
+// Slice returns a view of the profile restricted to the given time range.
+// Samples are kept when their timestamp falls inside the range and markers are
+// kept when they overlap it. Stack, frame and function tables are shared with
+// the original profile, which is left untouched. The profiling bounds are clamped
+// to the capture, so a range outside it gives an empty slice with zero Duration().
+func (p *Profile) Slice(r TimeRange) *Profile {
+	sliced := *p
+	sliced.Threads = make([]Thread, len(p.Threads))
+	for i := range p.Threads {
+		sliced.Threads[i] = sliceThread(&p.Threads[i], r)
+	}
+
+	// Clamp profiling bounds so Duration() reflects the selection; profiles
+	// without an end time take the range end as it is
+	start, end := r.StartMs, r.EndMs
+	if start < p.Meta.ProfilingStartTime {
+		start = p.Meta.ProfilingStartTime
+	}
+	if p.Meta.ProfilingEndTime > p.Meta.ProfilingStartTime && end > p.Meta.ProfilingEndTime {
+		end = p.Meta.ProfilingEndTime
+	}
+	if end < start {
+		end = start
+	}
+	sliced.Meta.ProfilingStartTime, sliced.Meta.ProfilingEndTime = start, end
+
+	return &sliced
+}
+
+// markerPhaseIntervalEnd is the phase of a marker that records only its end time
+const markerPhaseIntervalEnd = 3
+
+// sliceThread returns a copy of the thread with samples and markers outside r removed
+func sliceThread(thread *Thread, r TimeRange) Thread {
+	t := *thread
+
+	src := &thread.Samples
+	samples := Samples{WeightType: src.WeightType}
+	for i := 0; i < src.Length; i++ {
+		if !r.Contains(getFloat(src.Time, i)) {
+			continue
+		}
+		samples.Length++
+		if i < len(src.Stack) {
+			samples.Stack = append(samples.Stack, src.Stack[i])
+		}
+		if i < len(src.Time) {
+			samples.Time = append(samples.Time, src.Time[i])
+		}
+		if i < len(src.Weight) {
+			samples.Weight = append(samples.Weight, src.Weight[i])
+		}
+		if i < len(src.ThreadCPUDelta) {
+			samples.ThreadCPUDelta = append(samples.ThreadCPUDelta, src.ThreadCPUDelta[i])
+		}
+		if i < len(src.EventDelay) {
+			samples.EventDelay = append(samples.EventDelay, src.EventDelay[i])
+		}
+	}
+	t.Samples = samples
+
+	m := &thread.Markers
+	markers := Markers{}
+	for i := 0; i < m.Length; i++ {
+		start := getFloat(m.StartTime, i)
+		end := start
+		if endTime := getEndTime(m.EndTime, i); endTime != nil {
+			end = *endTime
+		}
+		if getPhase(m.Phase, i) == markerPhaseIntervalEnd {
+			// The null start time reads as 0, which would overlap every range
+			start = end
+		}
+		if !r.Overlaps(start, end) {
+			continue
+		}
+		markers.Length++
+		if i < len(m.Category) {
+			markers.Category = append(markers.Category, m.Category[i])
+		}
+		if i < len(m.Data) {
+			markers.Data = append(markers.Data, m.Data[i])
+		}
+		if i < len(m.EndTime) {
+			markers.EndTime = append(markers.EndTime, m.EndTime[i])
+		}
+		if i < len(m.Name) {
+			markers.Name = append(markers.Name, m.Name[i])
+		}
+		if i < len(m.Phase) {
+			markers.Phase = append(markers.Phase, m.Phase[i])
+		}
+		if i < len(m.StartTime) {
+			markers.StartTime = append(markers.StartTime, m.StartTime[i])
+		}
+	}
+	t.Markers = markers
+
+	return t
+}
@@ -0,0 +1,206 @@
+package parser
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestParseTimeRange(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		wantStart float64
+		wantEnd   float64
+		wantErr   bool
+	}{
+		{"milliseconds", "1200ms-3400ms", 1200, 3400, false},
+		{"no unit", "1200-3400", 1200, 3400, false},
+		{"seconds", "1.2s-3.4s", 1200, 3400, false},
+		{"mixed units", "500ms-2s", 500, 2000, false},
+		{"whitespace", " 10ms - 20ms ", 10, 20, false},
+		{"missing end", "1200ms", 0, 0, true},
+		{"invalid number", "abc-3400ms", 0, 0, true},
+		{"end before start", "3400ms-1200ms", 0, 0, true},
+		{"empty", "", 0, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := ParseTimeRange(tt.input)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("ParseTimeRange(%q) expected error", tt.input)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseTimeRange(%q) error: %v", tt.input, err)
+			}
+			if r.StartMs != tt.wantStart || r.EndMs != tt.wantEnd {
+				t.Errorf("ParseTimeRange(%q) = %v-%v, want %v-%v", tt.input, r.StartMs, r.EndMs, tt.wantStart, tt.wantEnd)
+			}
+		})
+	}
+}
+
+func TestTimeRange_Overlaps(t *testing.T) {
+	r := TimeRange{StartMs: 100, EndMs: 200}
+
+	tests := []struct {
+		name  string
+		start float64
+		end   float64
+		want  bool
+	}{
+		{"inside", 120, 150, true},
+		{"spans start", 50, 120, true},
+		{"spans end", 180, 250, true},
+		{"covers range", 50, 250, true},
+		{"before", 10, 90, false},
+		{"after", 210, 300, false},
+		{"instant inside", 150, 150, true},
+		{"instant outside", 250, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := r.Overlaps(tt.start, tt.end); got != tt.want {
+				t.Errorf("Overlaps(%v, %v) = %v, want %v", tt.start, tt.end, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTimeRange_String(t *testing.T) {
+	r := TimeRange{StartMs: 1200, EndMs: 3400.5}
+	if got := r.String(); got != "1200ms-3400.5ms" {
+		t.Errorf("String() = %q, want %q", got, "1200ms-3400.5ms")
+	}
+}
+
+func sliceTestProfile() *Profile {
+	return &Profile{
+		Meta: Meta{
+			Interval:           1,
+			ProfilingStartTime: 0,
+			ProfilingEndTime:   1000,
+		},
+		Threads: []Thread{
+			{
+				Name:        "GeckoMain",
+				StringArray: []string{"A", "B", "C"},
+				Samples: Samples{
+					Length:         5,
+					Stack:          []int{0, 1, 2, 3, 4},
+					Time:           []float64{0, 100, 200, 300, 400},
+					ThreadCPUDelta: []int{1000, 2000, 3000, 4000, 5000},
+					EventDelay:     []float64{0, 4, 16, 0, 2},
+				},
+				Markers: Markers{
+					Length:    3,
+					Name:      []int{0, 1, 2},
+					Category:  []int{0, 0, 0},
+					Phase:     []int{1, 1, 0},
+					StartTime: []float64{50, 180, 500},
+					EndTime:   []interface{}{150.0, 260.0, nil},
+					Data:      []json.RawMessage{nil, nil, nil},
+				},
+			},
+		},
+	}
+}
+
+func TestProfile_Slice_Samples(t *testing.T) {
+	p := sliceTestProfile()
+
+	sliced := p.Slice(TimeRange{StartMs: 100, EndMs: 300})
+	samples := sliced.Threads[0].Samples
+
+	if samples.Length != 3 {
+		t.Fatalf("Samples.Length = %d, want 3", samples.Length)
+	}
+	if samples.Time[0] != 100 || samples.Time[2] != 300 {
+		t.Errorf("Samples.Time = %v, want [100 200 300]", samples.Time)
+	}
+	if samples.Stack[0] != 1 || samples.ThreadCPUDelta[0] != 2000 {
+		t.Errorf("sample columns not kept aligned: stack=%v cpu=%v", samples.Stack, samples.ThreadCPUDelta)
+	}
+	if len(samples.EventDelay) != 3 || samples.EventDelay[1] != 16 {
+		t.Errorf("Samples.EventDelay = %v, want [4 16 0]", samples.EventDelay)
+	}
+}
+
+func TestProfile_Slice_Markers(t *testing.T) {
+	p := sliceTestProfile()
+
+	sliced := p.Slice(TimeRange{StartMs: 100, EndMs: 300})
+	markers := sliced.Threads[0].Markers
+
+	// First two markers overlap the range, the instant marker at 500 does not
+	if markers.Length != 2 {
+		t.Fatalf("Markers.Length = %d, want 2", markers.Length)
+	}
+	if len(markers.StartTime) != 2 || len(markers.EndTime) != 2 || len(markers.Name) != 2 {
+		t.Errorf("marker columns not kept aligned")
+	}
+}
+
+func TestProfile_Slice_IntervalEndMarkers(t *testing.T) {
+	p := sliceTestProfile()
+	p.Threads[0].Markers = Markers{
+		Length:    2,
+		Name:      []int{0, 1},
+		Category:  []int{0, 0},
+		Phase:     []int{3, 3},
+		StartTime: []float64{0, 0}, // Null start times
+		EndTime:   []interface{}{400.0, 250.0},
+		Data:      []json.RawMessage{nil, nil},
+	}
+
+	markers := p.Slice(TimeRange{StartMs: 100, EndMs: 300}).Threads[0].Markers
+
+	// Only the marker ending inside the range is kept
+	if markers.Length != 1 || markers.Name[0] != 1 {
+		t.Errorf("Markers = %+v, want only the marker ending at 250", markers)
+	}
+}
+
+func TestProfile_Slice_Duration(t *testing.T) {
+	p := sliceTestProfile()
+
+	sliced := p.Slice(TimeRange{StartMs: 100, EndMs: 300})
+
+	if sliced.Duration() != 200 {
+		t.Errorf("Duration() = %v, want 200", sliced.Duration())
+	}
+}
+
+func TestProfile_Slice_ClampsToCapture(t *testing.T) {
+	p := sliceTestProfile()
+
+	partial := p.Slice(TimeRange{StartMs: 800, EndMs: 5000})
+	if partial.Meta.ProfilingStartTime != 800 || partial.Meta.ProfilingEndTime != 1000 {
+		t.Errorf("bounds = %v-%v, want 800-1000", partial.Meta.ProfilingStartTime, partial.Meta.ProfilingEndTime)
+	}
+
+	outside := p.Slice(TimeRange{StartMs: 90000, EndMs: 95000})
+	if outside.Duration() != 0 || outside.Threads[0].Samples.Length != 0 {
+		t.Errorf("Duration() = %v with %d samples, want an empty slice", outside.Duration(), outside.Threads[0].Samples.Length)
+	}
+}
+
+func TestProfile_Slice_DoesNotModifyOriginal(t *testing.T) {
+	p := sliceTestProfile()
+
+	_ = p.Slice(TimeRange{StartMs: 100, EndMs: 300})
+
+	if p.Threads[0].Samples.Length != 5 {
+		t.Errorf("original Samples.Length = %d, want 5", p.Threads[0].Samples.Length)
+	}
+	if p.Threads[0].Markers.Length != 3 {
+		t.Errorf("original Markers.Length = %d, want 3", p.Threads[0].Markers.Length)
+	}
+	if p.Duration() != 1000 {
+		t.Errorf("original Duration() = %v, want 1000", p.Duration())
+	}
+}
@@ -0,0 +1,136 @@
+// Package report assembles the reports shared by the CLI commands and the MCP
+// tools, so both return the same data for the same profile and parameters.
+package report
+
+import (
+	"sort"
+
+	"github.com/CedricHerzog/perfowl/internal/analyzer"
+	"github.com/CedricHerzog/perfowl/internal/parser"
+)
+
+// DefaultLimit is the number of functions and hot paths a call tree reports
+const DefaultLimit = 20
+
+// Summary describes the profile: browser, platform, threads, extensions and
+// page-load milestones
+type Summary struct {
+	BrowserType     string                `json:"browser_type"`
+	DurationSeconds float64               `json:"duration_seconds"`
+	Platform        string                `json:"platform"`
+	OSCPU           string                `json:"os_cpu"`
+	Product         string                `json:"product"`
+	BuildID         string                `json:"build_id"`
+	CPUName         string                `json:"cpu_name"`
+	PhysicalCPUs    int                   `json:"physical_cpus"`
+	LogicalCPUs     int                   `json:"logical_cpus"`
+	ThreadCount     int                   `json:"thread_count"`
+	MainThreadCount int                   `json:"main_thread_count"`
+	ExtensionCount  int                   `json:"extension_count"`
+	Extensions      map[string]string     `json:"extensions"`
+	Features        []string              `json:"features"`
+	Categories      []string              `json:"categories"`
+	TotalMarkers    int                   `json:"total_markers"`
+	TotalSamples    int                   `json:"total_samples"`
+	PageLoad        []analyzer.Navigation `json:"page_load,omitempty"`
+}
+
+// Analysis is the comprehensive report: summary, bottlenecks and extension impact
+type Analysis struct {
+	Summary     Summary                     `json:"summary"`
+	Bottlenecks analyzer.BottleneckReport   `json:"bottlenecks"`
+	Extensions  analyzer.ExtensionsAnalysis `json:"extensions"`
+}
+
+// BuildSummary summarizes the profile; bt is the browser it was detected as
+func BuildSummary(profile *parser.Profile, bt parser.BrowserType) Summary {
+	summary := Summary{
+		BrowserType:     string(bt),
+		DurationSeconds: profile.DurationSeconds(),
+		Platform:        profile.Meta.Platform,
+		OSCPU:           profile.Meta.OSCPU,
+		Product:         profile.Meta.Product,
+		BuildID:         profile.Meta.AppBuildID,
+		CPUName:         profile.Meta.CPUName,
+		PhysicalCPUs:    profile.Meta.PhysicalCPUs,
+		LogicalCPUs:     profile.Meta.LogicalCPUs,
+		ThreadCount:     profile.ThreadCount(),
+		ExtensionCount:  profile.ExtensionCount(),
+		Extensions:      profile.GetExtensions(),
+		Features:        profile.Meta.Configuration.Features,
+	}
+	// Count main threads
+	for _, t := range profile.Threads {
+		if t.IsMainThread {
+			summary.MainThreadCount++
+		}
+		summary.TotalMarkers += t.Markers.Length
+		summary.TotalSamples += t.Samples.Length
+	}
+
+	// Get category names
+	for _, cat := range profile.Meta.Categories {
+		summary.Categories = append(summary.Categories, cat.Name)
+	}
+
+	summary.PageLoad = analyzer.ExtractPageLoadMilestones(profile)
+
+	return summary
+}
+
+// Bottlenecks detects bottlenecks at or above minSeverity (all when empty),
+// highest severity first, and scores them
+func Bottlenecks(profile *parser.Profile, minSeverity string) analyzer.BottleneckReport {
+	detected := analyzer.DetectBottlenecks(profile)
+
+	bottlenecks := make([]analyzer.Bottleneck, 0, len(detected))
+	minSev := analyzer.ParseSeverity(minSeverity)
+	for _, b := range detected {
+		if minSeverity == "" || b.Severity >= minSev {
+			bottlenecks = append(bottlenecks, b)
+		}
+	}
+	sort.SliceStable(bottlenecks, func(i, j int) bool {
+		return bottlenecks[i].Severity > bottlenecks[j].Severity
+	})
+
+	return analyzer.BottleneckReport{
+		Score:       analyzer.CalculateScore(bottlenecks),
+		Summary:     analyzer.GenerateSummary(bottlenecks, profile),
+		Bottlenecks: bottlenecks,
+	}
+}
+
+// Analyze builds the comprehensive report
+func Analyze(profile *parser.Profile, bt parser.BrowserType) Analysis {
+	return Analysis{
+		Summary:     BuildSummary(profile, bt),
+		Bottlenecks: Bottlenecks(profile, ""),
+		Extensions:  analyzer.AnalyzeExtensions(profile),
+	}
+}
+
+// CallTree finds the hot functions and paths of one thread, or of all threads
+// when thread is empty; a limit of zero or less means DefaultLimit
+func CallTree(profile *parser.Profile, thread string, limit int) analyzer.CallTreeAnalysis {
+	if limit <= 0 {
+		limit = DefaultLimit
+	}
+	return analyzer.AnalyzeCallTree(profile, thread, limit)
+}
+
+// Categories breaks CPU time down by profiler category for one thread, or for
+// all threads when thread is empty
+func Categories(profile *parser.Profile, thread string) analyzer.CategoryBreakdown {
+	return analyzer.AnalyzeCategories(profile, thread)
+}
+
+// Threads analyzes every thread in the profile
+func Threads(profile *parser.Profile) analyzer.ThreadAnalysis {
+	return analyzer.AnalyzeThreads(profile)
+}
+
+// Compare diffs a single comparison profile against a single baseline
+func Compare(baseline, comparison *parser.Profile) analyzer.ProfileDiff {
+	return analyzer.CompareProfiles(baseline, comparison)
+}
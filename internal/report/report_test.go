@@ -0,0 +1,106 @@
+package report
+
+import (
+	"testing"
+
+	"github.com/CedricHerzog/perfowl/internal/analyzer"
+	"github.com/CedricHerzog/perfowl/internal/parser"
+	"github.com/CedricHerzog/perfowl/internal/testutil"
+)
+
+func TestBuildSummary(t *testing.T) {
+	profile := testutil.NewProfileBuilder().
+		WithMeta(parser.Meta{
+			Platform:     "Linux",
+			Product:      "Firefox",
+			PhysicalCPUs: 4,
+			LogicalCPUs:  8,
+			Categories:   []parser.Category{{Name: "JavaScript"}, {Name: "Layout"}},
+		}).
+		WithDuration(2000).
+		WithThread(testutil.NewThreadBuilder("GeckoMain").AsMainThread().Build()).
+		WithThread(testutil.NewThreadBuilder("DOM Worker").Build()).
+		Build()
+
+	summary := BuildSummary(profile, parser.BrowserFirefox)
+
+	if summary.BrowserType != "firefox" {
+		t.Errorf("BrowserType = %q, want firefox", summary.BrowserType)
+	}
+	testutil.AssertFloatApproxEqual(t, summary.DurationSeconds, 2, 0.001)
+	if summary.ThreadCount != 2 || summary.MainThreadCount != 1 {
+		t.Errorf("threads = %d (%d main), want 2 (1 main)", summary.ThreadCount, summary.MainThreadCount)
+	}
+	testutil.AssertSliceLen(t, summary.Categories, 2)
+}
+
+func TestBottlenecks_SortedBySeverity(t *testing.T) {
+	report := Bottlenecks(testutil.ProfileWithBottlenecks(), "")
+
+	if len(report.Bottlenecks) == 0 {
+		t.Fatal("expected bottlenecks")
+	}
+	for i := 1; i < len(report.Bottlenecks); i++ {
+		if report.Bottlenecks[i].Severity > report.Bottlenecks[i-1].Severity {
+			t.Errorf("bottleneck %d (%s) is more severe than the one before it", i, report.Bottlenecks[i].Type)
+		}
+	}
+	if report.Score != analyzer.CalculateScore(report.Bottlenecks) {
+		t.Errorf("Score = %d, want the score of the reported bottlenecks", report.Score)
+	}
+}
+
+func TestBottlenecks_MinSeverity(t *testing.T) {
+	profile := testutil.ProfileWithBottlenecks()
+	all := Bottlenecks(profile, "")
+	medium := Bottlenecks(profile, "medium")
+
+	if len(medium.Bottlenecks) >= len(all.Bottlenecks) {
+		t.Errorf("expected the filter to drop low-severity bottlenecks, got %d of %d", len(medium.Bottlenecks), len(all.Bottlenecks))
+	}
+	for _, b := range medium.Bottlenecks {
+		if b.Severity < analyzer.SeverityMedium {
+			t.Errorf("bottleneck %s below the minimum severity", b.Type)
+		}
+	}
+
+	none := Bottlenecks(testutil.MinimalProfile(), "high")
+	if none.Bottlenecks == nil {
+		t.Error("expected an empty, non-nil bottleneck list")
+	}
+}
+
+func TestAnalyze(t *testing.T) {
+	profile := testutil.ProfileWithBottlenecks()
+	analysis := Analyze(profile, parser.BrowserFirefox)
+
+	if analysis.Summary.BrowserType != "firefox" {
+		t.Errorf("Summary.BrowserType = %q, want firefox", analysis.Summary.BrowserType)
+	}
+	if got, want := len(analysis.Bottlenecks.Bottlenecks), len(Bottlenecks(profile, "").Bottlenecks); got != want {
+		t.Errorf("%d bottlenecks, want %d", got, want)
+	}
+	if analysis.Extensions.TotalExtensions != 1 {
+		t.Errorf("TotalExtensions = %d, want 1", analysis.Extensions.TotalExtensions)
+	}
+}
+
+func TestCallTree_DefaultLimit(t *testing.T) {
+	profile := testutil.ProfileWithCallTree()
+
+	limited := CallTree(profile, "", 2)
+	testutil.AssertSliceLen(t, limited.TopFunctions, 2)
+
+	unlimited := CallTree(profile, "", 0)
+	if len(unlimited.TopFunctions) <= 2 {
+		t.Errorf("expected a limit of 0 to fall back to DefaultLimit, got %d functions", len(unlimited.TopFunctions))
+	}
+}
+
+func TestCompare(t *testing.T) {
+	diff := Compare(testutil.ProfileWithCallTree(), testutil.ProfileWithBottlenecks())
+
+	if diff.Changes.DurationChangeMs <= 0 {
+		t.Errorf("DurationChangeMs = %.2f, want an increase", diff.Changes.DurationChangeMs)
+	}
+}
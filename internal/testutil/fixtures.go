@@ -1,6 +1,8 @@
 package testutil
 
 import (
+	"fmt"
+
 	"github.com/CedricHerzog/perfowl/internal/parser"
 )
 
@@ -747,3 +749,905 @@ func ProfileWithWorkersData() *parser.Profile {
 			Build()).
 		Build()
 }
+
+// ProfileWithJITTiers returns a profile with JS frames in different JIT tiers.
+// hotLoop runs mostly in Ion, decrypt never leaves the baseline tiers and
+// nativeHelper is native code called from decrypt.
+func ProfileWithJITTiers() *parser.Profile {
+	strings := []string{
+		"main",         // 0
+		"hotLoop",      // 1
+		"decrypt",      // 2
+		"nativeHelper", // 3
+		"ion",          // 4
+		"baseline",     // 5
+		"blinterp",     // 6
+	}
+
+	// Function table: main, hotLoop, decrypt are JS, nativeHelper is native
+	fnb := NewFuncTableBuilder()
+	fnb.AddFunc(0, true, -1).
+		AddFunc(1, true, -1).
+		AddFunc(2, true, -1).
+		AddFunc(3, false, -1)
+
+	// Frame table
+	// Frame 0: main (interpreter)
+	// Frame 1: hotLoop (ion)
+	// Frame 2: decrypt (baseline)
+	// Frame 3: decrypt (blinterp)
+	// Frame 4: nativeHelper
+	// Frame 5: hotLoop (baseline, before tier-up)
+	ftb := NewFrameTableBuilder()
+	ftb.AddFrame(0, 2).
+		AddFrameWithImplementation(1, 2, 4).
+		AddFrameWithImplementation(2, 2, 5).
+		AddFrameWithImplementation(2, 2, 6).
+		AddFrame(3, 0).
+		AddFrameWithImplementation(1, 2, 5)
+
+	// Stack table
+	// Stack 0: main
+	// Stack 1: main -> hotLoop (ion)
+	// Stack 2: main -> decrypt (baseline)
+	// Stack 3: main -> decrypt (blinterp)
+	// Stack 4: main -> decrypt (baseline) -> nativeHelper
+	// Stack 5: main -> hotLoop (baseline)
+	stb := NewStackTableBuilder()
+	stb.AddStack(0, 2, -1).
+		AddStack(1, 2, 0).
+		AddStack(2, 2, 0).
+		AddStack(3, 2, 0).
+		AddStack(4, 0, 2).
+		AddStack(5, 2, 0)
+
+	sb := NewSamplesBuilder()
+	t := 0.0
+	add := func(stackIdx, count int) {
+		for i := 0; i < count; i++ {
+			sb.AddSampleWithCPUDelta(stackIdx, t, 1000)
+			t++
+		}
+	}
+	add(5, 5)  // hotLoop baseline warm-up
+	add(1, 40) // hotLoop ion
+	add(2, 20) // decrypt baseline
+	add(3, 10) // decrypt blinterp
+	add(4, 10) // nativeHelper under decrypt baseline
+	add(0, 5)  // main interpreter
+
+	return NewProfileBuilder().
+		WithDuration(100).
+		WithCategories(DefaultCategories()).
+		WithThread(NewThreadBuilder("GeckoMain").
+			AsMainThread().
+			WithStringArray(strings).
+			WithStackTable(stb.Build()).
+			WithFrameTable(ftb.Build()).
+			WithFuncTable(fnb.Build()).
+			WithSamples(sb.Build()).
+			Build()).
+		Build()
+}
+
+// ProfileWithSourceLines returns a profile with frame line numbers in app.js.
+// main calls decrypt from line 5, decrypt spends time on lines 12 and 13 and
+// one of its frames has no line, so it falls back to decrypt's first line (10).
+func ProfileWithSourceLines() *parser.Profile {
+	strings := []string{
+		"main",                          // 0
+		"decrypt",                       // 1
+		"https://example.com/js/app.js", // 2
+	}
+
+	fnb := NewFuncTableBuilder()
+	fnb.AddFuncWithFile(0, true, -1, 2, 1).
+		AddFuncWithFile(1, true, -1, 2, 10)
+
+	// Frame 0: main line 5
+	// Frame 1: decrypt line 12
+	// Frame 2: decrypt line 13
+	// Frame 3: decrypt without line
+	ftb := NewFrameTableBuilder()
+	ftb.AddFrameWithLine(0, 2, 5).
+		AddFrameWithLine(1, 2, 12).
+		AddFrameWithLine(1, 2, 13).
+		AddFrame(1, 2)
+
+	// Stack 0: main
+	// Stack 1: main -> decrypt:12
+	// Stack 2: main -> decrypt:13
+	// Stack 3: main -> decrypt
+	stb := NewStackTableBuilder()
+	stb.AddStack(0, 2, -1).
+		AddStack(1, 2, 0).
+		AddStack(2, 2, 0).
+		AddStack(3, 2, 0)
+
+	sb := NewSamplesBuilder()
+	t := 0.0
+	add := func(stackIdx, count int) {
+		for i := 0; i < count; i++ {
+			sb.AddSampleWithCPUDelta(stackIdx, t, 1000)
+			t++
+		}
+	}
+	add(1, 30) // decrypt:12
+	add(2, 10) // decrypt:13
+	add(3, 5)  // decrypt without line
+	add(0, 5)  // main:5
+
+	return NewProfileBuilder().
+		WithDuration(50).
+		WithCategories(DefaultCategories()).
+		WithThread(NewThreadBuilder("GeckoMain").
+			AsMainThread().
+			WithStringArray(strings).
+			WithStackTable(stb.Build()).
+			WithFrameTable(ftb.Build()).
+			WithFuncTable(fnb.Build()).
+			WithSamples(sb.Build()).
+			Build()).
+		Build()
+}
+
+// ProfileWithResources returns a profile with first-party, third-party, extension
+// and native library resources. With example.com as first party:
+// app.js (first party) calls track.js (third party) which calls memcpy in libxul.so,
+// and an extension content script runs on its own.
+func ProfileWithResources() *parser.Profile {
+	strings := []string{
+		"main",                               // 0
+		"https://www.example.com/app.js",     // 1
+		"trackEvent",                         // 2
+		"https://cdn.analytics.com/track.js", // 3
+		"memcpy",                             // 4
+		"libxul.so",                          // 5
+		"www.example.com",                    // 6
+		"cdn.analytics.com",                  // 7
+		"moz-extension://abc-123/content.js", // 8
+		"scan",                               // 9
+	}
+
+	// Resource table: type 1 is a library, type 5 a URL
+	rtb := NewResourceTableBuilder()
+	rtb.AddResource(-1, 1, 6, 5).
+		AddResource(-1, 3, 7, 5).
+		AddResource(0, 5, -1, 1).
+		AddResource(-1, 8, -1, 5)
+
+	fnb := NewFuncTableBuilder()
+	fnb.AddFunc(0, true, 0).
+		AddFunc(2, true, 1).
+		AddFunc(4, false, 2).
+		AddFunc(9, true, 3)
+
+	ftb := NewFrameTableBuilder()
+	ftb.AddFrame(0, 2).
+		AddFrame(1, 2).
+		AddFrame(2, 0).
+		AddFrame(3, 2)
+
+	// Stack 0: main
+	// Stack 1: main -> trackEvent
+	// Stack 2: main -> trackEvent -> memcpy
+	// Stack 3: scan
+	// Stack 4: main -> memcpy
+	stb := NewStackTableBuilder()
+	stb.AddStack(0, 2, -1).
+		AddStack(1, 2, 0).
+		AddStack(2, 0, 1).
+		AddStack(3, 2, -1).
+		AddStack(2, 0, 0)
+
+	sb := NewSamplesBuilder()
+	t := 0.0
+	add := func(stackIdx, count int) {
+		for i := 0; i < count; i++ {
+			sb.AddSampleWithCPUDelta(stackIdx, t, 1000)
+			t++
+		}
+	}
+	add(0, 10) // main
+	add(1, 20) // trackEvent
+	add(2, 10) // memcpy under trackEvent
+	add(3, 5)  // extension content script
+	add(4, 5)  // memcpy under main
+
+	profile := NewProfileBuilder().
+		WithDuration(50).
+		WithCategories(DefaultCategories()).
+		WithThread(NewThreadBuilder("GeckoMain").
+			AsMainThread().
+			WithStringArray(strings).
+			WithStackTable(stb.Build()).
+			WithFrameTable(ftb.Build()).
+			WithFuncTable(fnb.Build()).
+			WithResourceTable(rtb.Build()).
+			WithSamples(sb.Build()).
+			Build()).
+		Build()
+	profile.Libs = []parser.Lib{{Name: "libxul.so", Path: "/usr/lib/firefox/libxul.so"}}
+
+	return profile
+}
+
+// ProfileWithFrames returns a profile with RefreshDriverTick markers on the main thread.
+// Frames are 16.67ms apart except for one 100ms frame spent in layoutHeavy and a
+// 1000ms idle gap. That gives 43 frames: 1 janky (severe) with 5 dropped frames.
+func ProfileWithFrames() *parser.Profile {
+	const vsync = 1000.0 / 60.0
+
+	var ticks []float64
+	t := 0.0
+	for i := 0; i < 30; i++ {
+		ticks = append(ticks, t)
+		t += vsync
+	}
+	t = ticks[len(ticks)-1] + 100 // long frame
+	for i := 0; i < 10; i++ {
+		ticks = append(ticks, t)
+		t += vsync
+	}
+	t = ticks[len(ticks)-1] + 1000 // idle gap
+	for i := 0; i < 5; i++ {
+		ticks = append(ticks, t)
+		t += vsync
+	}
+
+	mb := NewMarkerBuilder()
+	for _, tick := range ticks {
+		mb.AddCustom("RefreshDriverTick", 4, tick, 2, nil)
+	}
+	longFrameStart := ticks[29] + 2
+	mb.AddLayout(longFrameStart+5, 80)
+	markers, strs := mb.Build()
+
+	// Function names follow the marker strings
+	funcName := len(strs)
+	strs = append(strs, "layoutHeavy")
+
+	fnb := NewFuncTableBuilder()
+	fnb.AddFunc(funcName, false, -1)
+	ftb := NewFrameTableBuilder()
+	ftb.AddFrame(0, 3)
+	stb := NewStackTableBuilder()
+	stb.AddStack(0, 3, -1)
+
+	// Main thread busy in layout for 90ms of the long frame
+	sb := NewSamplesBuilder()
+	for i := 0; i < 90; i++ {
+		sb.AddSampleWithCPUDelta(0, longFrameStart+5+float64(i), 1000)
+	}
+
+	return NewProfileBuilder().
+		WithDuration(t).
+		WithCategories(DefaultCategories()).
+		WithThread(NewThreadBuilder("GeckoMain").
+			AsMainThread().
+			WithStringArray(strs).
+			WithMarkers(markers).
+			WithStackTable(stb.Build()).
+			WithFrameTable(ftb.Build()).
+			WithFuncTable(fnb.Build()).
+			WithSamples(sb.Build()).
+			Build()).
+		Build()
+}
+
+// ProfileWithInteractions creates a profile with three user interactions:
+//   - a pointerdown/pointerup/click group at 90-216ms with a recorded event
+//     timestamp (10ms input delay, 100ms processing, 16ms presentation delay)
+//   - a keydown at 920-1310ms whose 80ms input delay comes from the sampled
+//     event delay and whose 300ms handler runs slowKeyHandler
+//   - a click at 2000ms that is never followed by a composite
+func ProfileWithInteractions() *parser.Profile {
+	mb := NewMarkerBuilder()
+	mb.AddCustom("DOMEvent", 5, 100, 5, map[string]interface{}{"type": "DOMEvent", "eventType": "pointerdown", "timeStamp": 90.0})
+	mb.AddDOMEventWithDuration("pointerup", 150, 5)
+	mb.AddDOMEventWithDuration("click", 160, 40)
+	mb.AddDOMEventWithDuration("keydown", 1000, 300)
+	mb.AddDOMEventWithDuration("click", 2000, 10)
+	mainMarkers, strs := mb.Build()
+
+	// Function names follow the marker strings
+	funcName := len(strs)
+	strs = append(strs, "slowKeyHandler")
+
+	fnb := NewFuncTableBuilder()
+	fnb.AddFunc(funcName, true, -1)
+	ftb := NewFrameTableBuilder()
+	ftb.AddFrame(0, 2)
+	stb := NewStackTableBuilder()
+	stb.AddStack(0, 2, -1)
+
+	sb := NewSamplesBuilder()
+	sb.AddSampleWithEventDelay(0, 990, 80)
+	for t := 1001.0; t < 1300; t++ {
+		sb.AddSampleWithEventDelay(0, t, 0)
+	}
+	sb.AddSampleWithEventDelay(0, 1990, 0)
+
+	compositor := NewMarkerBuilder().
+		AddCustom("Composite", 4, 200, 16, nil).
+		AddCustom("Composite", 4, 1300, 10, nil)
+	compositorMarkers, compositorStrs := compositor.Build()
+
+	return NewProfileBuilder().
+		WithDuration(2100).
+		WithCategories(DefaultCategories()).
+		WithThread(NewThreadBuilder("GeckoMain").
+			AsMainThread().
+			WithStringArray(strs).
+			WithMarkers(mainMarkers).
+			WithStackTable(stb.Build()).
+			WithFrameTable(ftb.Build()).
+			WithFuncTable(fnb.Build()).
+			WithSamples(sb.Build()).
+			Build()).
+		WithThread(NewThreadBuilder("Compositor").
+			WithStringArray(compositorStrs).
+			WithMarkers(compositorMarkers).
+			Build()).
+		Build()
+}
+
+// ProfileWithPageLoad creates a Chrome-style page load: an empty about:blank
+// navigation at 5ms, then a navigation to https://example.com/ at 100ms with
+// first paint at 350ms, FCP at 360ms, DOMContentLoaded at 500ms, LCP
+// candidates at 400ms and 800ms, and load at 900ms. An iframe navigation
+// at 300ms must be ignored.
+func ProfileWithPageLoad() *parser.Profile {
+	navData := func(url, id string, mainFrame bool) map[string]interface{} {
+		return map[string]interface{}{"data": map[string]interface{}{
+			"documentLoaderURL":  url,
+			"isLoadingMainFrame": mainFrame,
+			"navigationId":       id,
+		}}
+	}
+	milestoneData := map[string]interface{}{"data": map[string]interface{}{"navigationId": "nav-1"}}
+
+	mb := NewMarkerBuilder()
+	mb.AddCustom("navigationStart", 1, 5, 0, navData("about:blank", "nav-0", true))
+	mb.AddCustom("navigationStart", 1, 100, 0, navData("https://example.com/", "nav-1", true))
+	mb.AddCustom("navigationStart", 1, 300, 0, navData("https://ads.example.net/frame", "nav-2", false))
+	mb.AddCustom("firstPaint", 7, 350, 0, milestoneData)
+	mb.AddCustom("firstContentfulPaint", 7, 360, 0, milestoneData)
+	mb.AddCustom("largestContentfulPaint::Candidate", 7, 400, 0, milestoneData)
+	mb.AddCustom("domContentLoadedEventStart", 1, 500, 0, nil)
+	mb.AddCustom("largestContentfulPaint::Candidate", 7, 800, 0, milestoneData)
+	mb.AddCustom("loadEventStart", 1, 900, 0, nil)
+	markers, strs := mb.Build()
+
+	return NewProfileBuilder().
+		WithDuration(1000).
+		WithCategories(DefaultCategories()).
+		WithThread(NewThreadBuilder("CrRendererMain").
+			AsMainThread().
+			WithStringArray(strs).
+			WithMarkers(markers).
+			Build()).
+		Build()
+}
+
+// ProfileWithLayoutShifts creates a profile with Chrome LayoutShift events:
+// shifts of 0.05 and 0.08 at 500ms and 900ms (one session window, CLS 0.13),
+// a 0.3 shift at 1200ms right after input, and shifts of 0.02 and 0.01 at
+// 3000ms and 3500ms in a second window.
+func ProfileWithLayoutShifts() *parser.Profile {
+	shift := func(score float64, recentInput bool, nodeID int) map[string]interface{} {
+		return map[string]interface{}{"data": map[string]interface{}{
+			"score":            score,
+			"had_recent_input": recentInput,
+			"impacted_nodes": []interface{}{map[string]interface{}{
+				"node_id":  nodeID,
+				"old_rect": []interface{}{0.0, 100.0, 800.0, 200.0},
+				"new_rect": []interface{}{0.0, 300.0, 800.0, 200.0},
+			}},
+		}}
+	}
+
+	mb := NewMarkerBuilder()
+	mb.AddCustom("LayoutShift", 7, 500, 0, shift(0.05, false, 11))
+	mb.AddCustom("LayoutShift", 7, 900, 0, shift(0.08, false, 12))
+	mb.AddCustom("LayoutShift", 7, 1200, 0, shift(0.3, true, 13))
+	mb.AddCustom("LayoutShift", 7, 3000, 0, shift(0.02, false, 14))
+	mb.AddCustom("LayoutShift", 7, 3500, 0, shift(0.01, false, 15))
+	markers, strs := mb.Build()
+
+	return NewProfileBuilder().
+		WithDuration(4000).
+		WithCategories(DefaultCategories()).
+		WithThread(NewThreadBuilder("CrRendererMain").
+			AsMainThread().
+			WithStringArray(strs).
+			WithMarkers(markers).
+			Build()).
+		Build()
+}
+
+// ProfileWithNetwork creates a Firefox page load with Network markers and FCP at 600ms:
+//   - the document https://example.com/ (10-200ms) with DNS, TCP, TLS, waiting and download phases
+//   - app.css (210-300ms) and a font (310-450ms), forming a chain behind the document
+//   - a CDN script (100-250ms) requested during the document load
+//   - a hero image (700-1900ms) requested after first paint
+//   - a cancelled ad script (400-420ms)
+func ProfileWithNetwork() *parser.Profile {
+	mb := NewMarkerBuilder()
+	network := func(id int, uri, contentType, status string, start, end float64, extra map[string]interface{}) {
+		data := map[string]interface{}{
+			"type":        "Network",
+			"id":          float64(id),
+			"URI":         uri,
+			"status":      status,
+			"startTime":   start,
+			"endTime":     end,
+			"contentType": contentType,
+		}
+		for k, v := range extra {
+			data[k] = v
+		}
+		mb.AddCustom(fmt.Sprintf("Load %d: %s", id, uri), 7, start, end-start, data)
+	}
+
+	network(1, "https://example.com/", "", "STATUS_START", 10, 80, nil)
+	network(1, "https://example.com/", "text/html", "STATUS_STOP", 10, 200, map[string]interface{}{
+		"domainLookupStart":     10.0,
+		"domainLookupEnd":       30.0,
+		"connectStart":          30.0,
+		"tcpConnectEnd":         50.0,
+		"secureConnectionStart": 50.0,
+		"connectEnd":            80.0,
+		"requestStart":          80.0,
+		"responseStart":         150.0,
+		"responseEnd":           200.0,
+		"count":                 5000.0,
+		"responseStatus":        200.0,
+	})
+	network(4, "https://cdn.example.org/lib.js", "text/javascript", "STATUS_STOP", 100, 250, map[string]interface{}{"count": 20000.0})
+	network(2, "https://example.com/app.css", "text/css", "STATUS_STOP", 210, 300, map[string]interface{}{"count": 3000.0})
+	network(3, "https://fonts.example.net/font.woff2", "font/woff2", "STATUS_STOP", 310, 450, nil)
+	network(6, "https://ads.example.net/ad.js", "", "STATUS_CANCEL", 400, 420, nil)
+	network(5, "https://example.com/hero.png", "image/png", "STATUS_STOP", 700, 1900, map[string]interface{}{"count": 150000.0})
+	mb.AddCustom("FirstContentfulPaint", 4, 600, 0, nil)
+	markers, strs := mb.Build()
+
+	return NewProfileBuilder().
+		WithDuration(2000).
+		WithCategories(DefaultCategories()).
+		WithThread(NewThreadBuilder("GeckoMain").
+			AsMainThread().
+			WithStringArray(strs).
+			WithMarkers(markers).
+			Build()).
+		Build()
+}
+
+// ProfileWithScriptCosts returns a Chrome-style profile with script parse, compile
+// and evaluation events. app.js compiles (60ms) inside its evaluation (200ms) on
+// the main thread; vendor.js is parsed on a background thread (80ms) and then
+// compiled (20ms, from code cache) and evaluated (20ms) on the main thread.
+func ProfileWithScriptCosts() *parser.Profile {
+	data := func(fields map[string]interface{}) map[string]interface{} {
+		return map[string]interface{}{"data": fields}
+	}
+	app := "https://example.com/app.js"
+	vendor := "https://example.com/vendor.js"
+
+	main := NewMarkerBuilder().
+		AddCustom("ResourceSendRequest", 7, 10, 0, data(map[string]interface{}{"requestId": "1.1", "url": app})).
+		AddCustom("ResourceFinish", 7, 110, 0, data(map[string]interface{}{"requestId": "1.1", "encodedDataLength": 50000.0})).
+		AddCustom("v8.compile", 2, 150, 20, data(map[string]interface{}{"url": vendor, "streamed": true, "consumedCacheSize": 1024.0})).
+		AddCustom("EvaluateScript", 2, 170, 20, data(map[string]interface{}{"url": vendor})).
+		AddCustom("EvaluateScript", 2, 200, 200, data(map[string]interface{}{"url": app})).
+		AddCustom("v8.compile", 2, 200, 60, data(map[string]interface{}{"url": app})).
+		AddCustom("v8.compile", 2, 500, 10, nil)
+	mainMarkers, mainStrs := main.Build()
+
+	background := NewMarkerBuilder().
+		AddCustom("v8.parseOnBackground", 2, 50, 80, data(map[string]interface{}{"url": vendor}))
+	bgMarkers, bgStrs := background.Build()
+
+	return NewProfileBuilder().
+		WithDuration(1000).
+		WithCategories(DefaultCategories()).
+		WithThread(NewThreadBuilder("CrRendererMain").
+			AsMainThread().
+			WithStringArray(mainStrs).
+			WithMarkers(mainMarkers).
+			Build()).
+		WithThread(NewThreadBuilder("ThreadPoolForegroundWorker").
+			WithStringArray(bgStrs).
+			WithMarkers(bgMarkers).
+			Build()).
+		Build()
+}
+
+// ProfileWithGCPayloads returns a Firefox-style profile with GC payloads. Two minor GCs
+// (100ms and 300ms, 5ms each) promote 800KB of a 2MB nursery; a major GC from
+// 400ms to 700ms runs two slices with a 10ms budget, pausing 15ms at 400ms and
+// 40ms at 600ms. allocateNodes is sampled before three of the four pauses.
+func ProfileWithGCPayloads() *parser.Profile {
+	minor := func(tenured float64) map[string]interface{} {
+		return map[string]interface{}{
+			"type": "GCMinor",
+			"nursery": map[string]interface{}{
+				"status":        "complete",
+				"reason":        "OUT_OF_NURSERY",
+				"bytes_used":    1000000.0,
+				"bytes_tenured": tenured,
+			},
+		}
+	}
+	slice := func(pause float64, reason string) map[string]interface{} {
+		return map[string]interface{}{
+			"type": "GCSlice",
+			"timings": map[string]interface{}{
+				"pause":  pause,
+				"budget": "10ms",
+				"reason": reason,
+			},
+		}
+	}
+
+	mb := NewMarkerBuilder().
+		AddCustom("GCMinor", 6, 100, 5, minor(500000)).
+		AddCustom("GCMinor", 6, 300, 5, minor(300000)).
+		AddCustom("GCMajor", 6, 400, 300, map[string]interface{}{
+			"type": "GCMajor",
+			"timings": map[string]interface{}{
+				"status":                "completed",
+				"reason":                "ALLOC_TRIGGER",
+				"zones_collected":       2.0,
+				"total_zones":           3.0,
+				"slices":                2.0,
+				"max_pause":             40.0,
+				"total_time":            55.0,
+				"mmu_20ms":              10.0,
+				"mmu_50ms":              20.0,
+				"nonincremental_reason": "None",
+			},
+		}).
+		AddCustom("GCSlice", 6, 400, 15, slice(15, "ALLOC_TRIGGER")).
+		AddCustom("GCSlice", 6, 600, 40, slice(40, "INTER_SLICE_GC"))
+	markers, strs := mb.Build()
+
+	// Function names follow the marker strings
+	allocateNodes := len(strs)
+	strs = append(strs, "allocateNodes", "layout")
+
+	fnb := NewFuncTableBuilder()
+	fnb.AddFunc(allocateNodes, true, -1)
+	fnb.AddFunc(allocateNodes+1, true, -1)
+	ftb := NewFrameTableBuilder()
+	ftb.AddFrame(0, 2)
+	ftb.AddFrame(1, 3)
+	stb := NewStackTableBuilder()
+	stb.AddStack(0, 2, -1)
+	stb.AddStack(1, 3, -1)
+
+	sb := NewSamplesBuilder()
+	for t := 60.0; t < 100; t += 5 {
+		sb.AddSampleWithCPUDelta(0, t, 1000)
+	}
+	sb.AddSampleWithCPUDelta(0, 260, 1000)
+	sb.AddSampleWithCPUDelta(0, 270, 1000)
+	sb.AddSampleWithCPUDelta(0, 280, 1000)
+	sb.AddSampleWithCPUDelta(1, 290, 1000)
+	sb.AddSampleWithCPUDelta(1, 380, 1000)
+	sb.AddSampleWithCPUDelta(0, 590, 1000)
+
+	return NewProfileBuilder().
+		WithDuration(1000).
+		WithCategories(DefaultCategories()).
+		WithThread(NewThreadBuilder("GeckoMain").
+			AsMainThread().
+			WithStringArray(strs).
+			WithMarkers(markers).
+			WithStackTable(stb.Build()).
+			WithFrameTable(ftb.Build()).
+			WithFuncTable(fnb.Build()).
+			WithSamples(sb.Build()).
+			Build()).
+		Build()
+}
+
+// ProfileWithStyles returns a Firefox-style profile with four Styles markers.
+// Two of them (200ms and 300ms) were forced by measureWidth called from onClick;
+// 240 of the 2000 styled elements shared or reused a cached style.
+func ProfileWithStyles() *parser.Profile {
+	styles := func(styled, matched, shared, reused float64, causeStack int) map[string]interface{} {
+		data := map[string]interface{}{
+			"type":              "Styles",
+			"elementsTraversed": styled * 2,
+			"elementsStyled":    styled,
+			"elementsMatched":   matched,
+			"stylesShared":      shared,
+			"stylesReused":      reused,
+		}
+		if causeStack >= 0 {
+			data["cause"] = map[string]interface{}{"stack": float64(causeStack)}
+		}
+		return data
+	}
+
+	mb := NewMarkerBuilder().
+		AddCustom("Styles", 3, 100, 5, styles(100, 60, 30, 10, -1)).
+		AddCustom("Styles", 3, 200, 12, styles(1500, 1400, 50, 50, 1)).
+		AddCustom("Styles", 3, 300, 8, styles(400, 300, 60, 40, 1)).
+		AddCustom("Styles", 3, 400, 3, nil)
+	markers, strs := mb.Build()
+
+	// Function names follow the marker strings
+	onClick := len(strs)
+	strs = append(strs, "onClick", "measureWidth")
+
+	fnb := NewFuncTableBuilder()
+	fnb.AddFunc(onClick, true, -1)
+	fnb.AddFunc(onClick+1, true, -1)
+	ftb := NewFrameTableBuilder()
+	ftb.AddFrame(0, 2)
+	ftb.AddFrame(1, 2)
+	stb := NewStackTableBuilder()
+	stb.AddStack(0, 2, -1)
+	stb.AddStack(1, 2, 0)
+
+	return NewProfileBuilder().
+		WithDuration(1000).
+		WithCategories(DefaultCategories()).
+		WithThread(NewThreadBuilder("GeckoMain").
+			AsMainThread().
+			WithStringArray(strs).
+			WithMarkers(markers).
+			WithStackTable(stb.Build()).
+			WithFrameTable(ftb.Build()).
+			WithFuncTable(fnb.Build()).
+			Build()).
+		Build()
+}
+
+// ProfileWithForcedLayout creates a profile where JavaScript forces synchronous reflows.
+// measureWidth (app.js:42) reads offsetHeight, forcing two reflows with a cause stack and
+// a third inside a click DOMEvent; tick (app.js:80) forces one seen only by a sample; the
+// final reflow is a regular refresh driver layout.
+func ProfileWithForcedLayout() *parser.Profile {
+	reflow := func(causeStack int) map[string]interface{} {
+		data := map[string]interface{}{"type": "Reflow"}
+		if causeStack >= 0 {
+			data["cause"] = map[string]interface{}{"stack": float64(causeStack)}
+		}
+		return data
+	}
+
+	mb := NewMarkerBuilder().
+		AddCustom("Reflow", 3, 100, 4, reflow(2)).
+		AddCustom("Reflow", 3, 200, 6, reflow(2)).
+		AddCustom("DOMEvent", 2, 295, 15, map[string]interface{}{"type": "DOMEvent", "eventType": "click"}).
+		AddCustom("Reflow", 3, 300, 3, reflow(-1)).
+		AddCustom("Reflow", 3, 500, 2, reflow(-1)).
+		AddCustom("Reflow", 3, 800, 5, reflow(-1))
+	markers, strs := mb.Build()
+
+	// Function names follow the marker strings
+	fn := len(strs)
+	strs = append(strs, "onClick", "measureWidth", "get HTMLElement.offsetHeight", "PresShell::DoReflow", "tick", "https://example.com/app.js")
+	file := fn + 5
+
+	fnb := NewFuncTableBuilder()
+	fnb.AddFuncWithFile(fn, true, -1, file, 10)
+	fnb.AddFuncWithFile(fn+1, true, -1, file, 42)
+	fnb.AddFunc(fn+2, false, -1)
+	fnb.AddFunc(fn+3, false, -1)
+	fnb.AddFuncWithFile(fn+4, true, -1, file, 80)
+	ftb := NewFrameTableBuilder()
+	ftb.AddFrame(0, 2)
+	ftb.AddFrame(1, 2)
+	ftb.AddFrame(2, 5)
+	ftb.AddFrame(3, 3)
+	ftb.AddFrame(4, 2)
+	ftb.AddFrame(3, 3)
+	stb := NewStackTableBuilder()
+	stb.AddStack(0, 2, -1) // onClick
+	stb.AddStack(1, 2, 0)  // onClick → measureWidth
+	stb.AddStack(2, 5, 1)  // … → get HTMLElement.offsetHeight
+	stb.AddStack(3, 3, 2)  // … → PresShell::DoReflow
+	stb.AddStack(4, 2, -1) // tick
+	stb.AddStack(5, 3, 4)  // tick → PresShell::DoReflow
+	sb := NewSamplesBuilder().
+		AddSample(0, 296).
+		AddSample(3, 301).
+		AddSample(5, 500.5).
+		AddSample(0, 700)
+
+	return NewProfileBuilder().
+		WithDuration(1000).
+		WithCategories(DefaultCategories()).
+		WithThread(NewThreadBuilder("GeckoMain").
+			AsMainThread().
+			WithStringArray(strs).
+			WithMarkers(markers).
+			WithStackTable(stb.Build()).
+			WithFrameTable(ftb.Build()).
+			WithFuncTable(fnb.Build()).
+			WithSamples(sb.Build()).
+			Build()).
+		Build()
+}
+
+// ProfileWithConcurrency creates a main thread and two workers sampled every 1ms over
+// 100ms. The main thread runs alone during 0-40ms and 80-100ms, worker 2 runs during
+// 40-80ms and worker 3 during 40-60ms.
+func ProfileWithConcurrency() *parser.Profile {
+	samples := func(busy func(t int) bool) parser.Samples {
+		sb := NewSamplesBuilder()
+		for t := 0; t <= 100; t++ {
+			delta := 0
+			if busy(t) {
+				delta = 1000
+			}
+			sb.AddSampleWithCPUDelta(0, float64(t), delta)
+		}
+		return sb.Build()
+	}
+
+	return NewProfileBuilder().
+		WithDuration(100).
+		WithInterval(1).
+		WithCategories(DefaultCategories()).
+		WithThread(NewThreadBuilder("GeckoMain").
+			AsMainThread().
+			WithTID("1").
+			WithSamples(samples(func(t int) bool { return (t >= 1 && t <= 40) || t > 80 })).
+			Build()).
+		WithThread(NewThreadBuilder("DOM Worker").
+			WithTID("2").
+			WithSamples(samples(func(t int) bool { return t > 40 && t <= 80 })).
+			Build()).
+		WithThread(NewThreadBuilder("DOM Worker").
+			WithTID("3").
+			WithSamples(samples(func(t int) bool { return t > 40 && t <= 60 })).
+			Build()).
+		Build()
+}
+
+// ProfileWithCriticalPath creates a main thread that hands work to two workers between
+// the decrypt-start and decrypt-end user timings. Worker 2 receives its task at 3ms and
+// posts the result at 68ms; worker 3 works from 4ms to 38ms. The main thread waits for
+// both results and finishes at 80ms.
+func ProfileWithCriticalPath() *parser.Profile {
+	postMessage := map[string]interface{}{"type": "PostMessage"}
+	samples := func(busy func(t int) bool) parser.Samples {
+		sb := NewSamplesBuilder()
+		for t := 0; t <= 80; t++ {
+			delta := 0
+			if busy(t) {
+				delta = 1000
+			}
+			sb.AddSampleWithCPUDelta(0, float64(t), delta)
+		}
+		return sb.Build()
+	}
+
+	mainMarkers, mainStrings := NewMarkerBuilder().
+		AddUserTiming("decrypt-start", 0).
+		AddCustom("PostMessage", 2, 1, 0, postMessage).
+		AddCustom("PostMessage", 2, 2, 0, postMessage).
+		AddDOMEvent("message", 40).
+		AddDOMEvent("message", 70).
+		AddUserTiming("decrypt-end", 80).
+		Build()
+	slowMarkers, slowStrings := NewMarkerBuilder().
+		AddDOMEvent("message", 3).
+		AddCustom("PostMessage", 2, 68, 0, postMessage).
+		Build()
+	fastMarkers, fastStrings := NewMarkerBuilder().
+		AddDOMEvent("message", 4).
+		AddCustom("PostMessage", 2, 38, 0, postMessage).
+		Build()
+
+	return NewProfileBuilder().
+		WithDuration(80).
+		WithInterval(1).
+		WithCategories(DefaultCategories()).
+		WithThread(NewThreadBuilder("GeckoMain").
+			AsMainThread().
+			WithTID("1").
+			WithStringArray(mainStrings).
+			WithMarkers(mainMarkers).
+			WithSamples(samples(func(t int) bool { return (t >= 1 && t <= 2) || (t >= 41 && t <= 42) || t > 70 })).
+			Build()).
+		WithThread(NewThreadBuilder("DOM Worker").
+			WithTID("2").
+			WithStringArray(slowStrings).
+			WithMarkers(slowMarkers).
+			WithSamples(samples(func(t int) bool { return t >= 4 && t <= 68 })).
+			Build()).
+		WithThread(NewThreadBuilder("DOM Worker").
+			WithTID("3").
+			WithStringArray(fastStrings).
+			WithMarkers(fastMarkers).
+			WithSamples(samples(func(t int) bool { return t >= 5 && t <= 38 })).
+			Build()).
+		Build()
+}
+
+// ProfileWithMessageFlow creates a main thread that posts three sized messages to a
+// worker at 0, 10 and 20ms, handled after 2, 5 and 1ms. The worker posts a reply at
+// 30ms that the main thread handles at 38ms, and the main thread's last message at
+// 50ms is never received.
+func ProfileWithMessageFlow() *parser.Profile {
+	post := func(size float64) map[string]interface{} {
+		return map[string]interface{}{"type": "PostMessage", "size": size}
+	}
+
+	mainMarkers, mainStrings := NewMarkerBuilder().
+		AddCustom("PostMessage", 2, 0, 0, post(1024)).
+		AddCustom("PostMessage", 2, 10, 0, post(2048)).
+		AddCustom("PostMessage", 2, 20, 0, post(3072)).
+		AddDOMEvent("message", 38).
+		AddCustom("PostMessage", 2, 50, 0, post(512)).
+		Build()
+	workerMarkers, workerStrings := NewMarkerBuilder().
+		AddDOMEvent("message", 2).
+		AddDOMEvent("message", 15).
+		AddDOMEvent("message", 21).
+		AddCustom("PostMessage", 2, 30, 0, post(100)).
+		Build()
+
+	return NewProfileBuilder().
+		WithDuration(60).
+		WithInterval(1).
+		WithCategories(DefaultCategories()).
+		WithThread(NewThreadBuilder("GeckoMain").
+			AsMainThread().
+			WithTID("1").
+			WithStringArray(mainStrings).
+			WithMarkers(mainMarkers).
+			Build()).
+		WithThread(NewThreadBuilder("DOM Worker").
+			WithTID("2").
+			WithStringArray(workerStrings).
+			WithMarkers(workerMarkers).
+			Build()).
+		Build()
+}
+
+// ProfileRun creates one run of a repeated benchmark: a main thread that spends
+// hotSamples 1ms samples in computeHash and 20 in render, with the given duration.
+// Used to compare sets of runs.
+func ProfileRun(durationMs float64, hotSamples int) *parser.Profile {
+	strings := []string{"main", "computeHash", "render"}
+
+	stb := NewStackTableBuilder()
+	stb.AddStack(0, 2, -1). // main
+				AddStack(1, 2, 0). // computeHash -> main
+				AddStack(2, 2, 0)  // render -> main
+
+	ftb := NewFrameTableBuilder()
+	fnb := NewFuncTableBuilder()
+	for i := 0; i < 3; i++ {
+		ftb.AddFrame(i, 2)
+		fnb.AddFunc(i, true, -1)
+	}
+
+	sb := NewSamplesBuilder()
+	for i := 0; i < hotSamples; i++ {
+		sb.AddSampleWithCPUDelta(1, float64(i), 1000)
+	}
+	for i := 0; i < 20; i++ {
+		sb.AddSampleWithCPUDelta(2, float64(hotSamples+i), 1000)
+	}
+
+	return NewProfileBuilder().
+		WithDuration(durationMs).
+		WithCategories(DefaultCategories()).
+		WithThread(NewThreadBuilder("GeckoMain").
+			AsMainThread().
+			WithStringArray(strings).
+			WithStackTable(stb.Build()).
+			WithFrameTable(ftb.Build()).
+			WithFuncTable(fnb.Build()).
+			WithSamples(sb.Build()).
+			Build()).
+		Build()
+}
@@ -219,3 +219,56 @@ func TestProfileWithWorkersData(t *testing.T) {
 		t.Errorf("expected 5 threads, got %d", len(profile.Threads))
 	}
 }
+
+func TestProfileWithJITTiers(t *testing.T) {
+	profile := ProfileWithJITTiers()
+	if profile == nil {
+		t.Fatal("expected non-nil profile")
+	}
+	if profile.Threads[0].Samples.Length != 90 {
+		t.Errorf("expected 90 samples, got %d", profile.Threads[0].Samples.Length)
+	}
+	if profile.Threads[0].FrameTable.Implementation[1] != 4 {
+		t.Errorf("expected frame 1 implementation 4, got %v", profile.Threads[0].FrameTable.Implementation[1])
+	}
+}
+
+func TestProfileWithSourceLines(t *testing.T) {
+	profile := ProfileWithSourceLines()
+	if profile == nil {
+		t.Fatal("expected non-nil profile")
+	}
+	if profile.Threads[0].Samples.Length != 50 {
+		t.Errorf("expected 50 samples, got %d", profile.Threads[0].Samples.Length)
+	}
+	if profile.Threads[0].FrameTable.Line[1] != 12 {
+		t.Errorf("expected frame 1 line 12, got %v", profile.Threads[0].FrameTable.Line[1])
+	}
+}
+
+func TestProfileWithResources(t *testing.T) {
+	profile := ProfileWithResources()
+	if profile == nil {
+		t.Fatal("expected non-nil profile")
+	}
+	if profile.Threads[0].ResourceTable.Length != 4 {
+		t.Errorf("expected 4 resources, got %d", profile.Threads[0].ResourceTable.Length)
+	}
+	if len(profile.Libs) != 1 {
+		t.Errorf("expected 1 lib, got %d", len(profile.Libs))
+	}
+}
+
+func TestProfileWithFrames(t *testing.T) {
+	profile := ProfileWithFrames()
+	if profile == nil {
+		t.Fatal("expected non-nil profile")
+	}
+	// 45 refresh driver ticks plus one layout marker
+	if profile.Threads[0].Markers.Length != 46 {
+		t.Errorf("expected 46 markers, got %d", profile.Threads[0].Markers.Length)
+	}
+	if profile.Threads[0].Samples.Length != 90 {
+		t.Errorf("expected 90 samples, got %d", profile.Threads[0].Samples.Length)
+	}
+}
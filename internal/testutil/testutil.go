@@ -480,6 +480,15 @@ func (b *SamplesBuilder) AddSampleWithCPUDelta(stackIdx int, time float64, cpuDe
 	return b
 }
 
+// AddSampleWithEventDelay adds a sample with an event loop delay in milliseconds.
+func (b *SamplesBuilder) AddSampleWithEventDelay(stackIdx int, time, eventDelay float64) *SamplesBuilder {
+	b.samples.Length++
+	b.samples.Stack = append(b.samples.Stack, stackIdx)
+	b.samples.Time = append(b.samples.Time, time)
+	b.samples.EventDelay = append(b.samples.EventDelay, eventDelay)
+	return b
+}
+
 // AddSampleWithWeight adds a sample with weight.
 func (b *SamplesBuilder) AddSampleWithWeight(stackIdx int, time float64, weight int) *SamplesBuilder {
 	b.samples.Length++
@@ -571,6 +580,20 @@ func (b *FrameTableBuilder) AddFrame(funcIdx, categoryIdx int) *FrameTableBuilde
 	return b
 }
 
+// AddFrameWithImplementation adds a frame entry with an implementation string index (JIT tier).
+func (b *FrameTableBuilder) AddFrameWithImplementation(funcIdx, categoryIdx, implementationIdx int) *FrameTableBuilder {
+	b.AddFrame(funcIdx, categoryIdx)
+	b.table.Implementation[b.table.Length-1] = implementationIdx
+	return b
+}
+
+// AddFrameWithLine adds a frame entry with the source line being executed.
+func (b *FrameTableBuilder) AddFrameWithLine(funcIdx, categoryIdx, line int) *FrameTableBuilder {
+	b.AddFrame(funcIdx, categoryIdx)
+	b.table.Line[b.table.Length-1] = line
+	return b
+}
+
 // Build returns the constructed frame table.
 func (b *FrameTableBuilder) Build() parser.FrameTable {
 	return b.table
@@ -463,3 +463,34 @@ func TestDefaultCategories(t *testing.T) {
 		t.Error("expected JavaScript category")
 	}
 }
+
+func TestFrameTableBuilder_AddFrameWithImplementation(t *testing.T) {
+	ft := NewFrameTableBuilder().
+		AddFrame(0, 0).
+		AddFrameWithImplementation(1, 0, 3).
+		Build()
+
+	if ft.Length != 2 {
+		t.Errorf("FrameTable length = %v, want 2", ft.Length)
+	}
+	if ft.Implementation[0] != nil {
+		t.Errorf("Implementation[0] = %v, want nil", ft.Implementation[0])
+	}
+	if ft.Implementation[1] != 3 {
+		t.Errorf("Implementation[1] = %v, want 3", ft.Implementation[1])
+	}
+}
+
+func TestFrameTableBuilder_AddFrameWithLine(t *testing.T) {
+	ft := NewFrameTableBuilder().
+		AddFrame(0, 0).
+		AddFrameWithLine(1, 0, 42).
+		Build()
+
+	if ft.Line[0] != nil {
+		t.Errorf("Line[0] = %v, want nil", ft.Line[0])
+	}
+	if ft.Line[1] != 42 {
+		t.Errorf("Line[1] = %v, want 42", ft.Line[1])
+	}
+}